@@ -21,6 +21,7 @@ import (
 type Account struct {
 	Email              string
 	Registration       *acme.RegistrationResource
+	Registrations      map[string]*acme.RegistrationResource `json:",omitempty"`
 	PrivateKey         []byte
 	DomainsCertificate DomainsCertificates
 	ChallengeCerts     map[string]*ChallengeCert
@@ -195,6 +196,14 @@ func (dc *DomainsCertificates) addCertificateForDomains(acmeCert *Certificate, d
 	return &cert, nil
 }
 
+// certificates returns a snapshot of the certificates.
+func (dc *DomainsCertificates) certificates() []*DomainsCertificate {
+	dc.lock.RLock()
+	defer dc.lock.RUnlock()
+
+	return append([]*DomainsCertificate{}, dc.Certs...)
+}
+
 func (dc *DomainsCertificates) getCertificateForDomain(domainToFind string) (*DomainsCertificate, bool) {
 	dc.lock.RLock()
 	defer dc.lock.RUnlock()
@@ -247,15 +256,15 @@ type DomainsCertificate struct {
 	tlsCert     *tls.Certificate
 }
 
-func (dc *DomainsCertificate) needRenew() bool {
+func (dc *DomainsCertificate) needRenew(renewBefore time.Duration) bool {
 	for _, c := range dc.tlsCert.Certificate {
 		crt, err := x509.ParseCertificate(c)
 		if err != nil {
 			// If there's an error, we assume the cert is broken, and needs update
 			return true
 		}
-		// <= 30 days left, renew certificate
-		if crt.NotAfter.Before(time.Now().Add(24 * 30 * time.Hour)) {
+		// within the renewal duration, renew certificate
+		if crt.NotAfter.Before(time.Now().Add(renewBefore)) {
 			return true
 		}
 	}
@@ -12,6 +12,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/BurntSushi/ty/fun"
@@ -19,15 +20,15 @@ import (
 	"github.com/containous/flaeg"
 	"github.com/containous/mux"
 	"github.com/containous/staert"
+	"github.com/containous/traefik/audit"
 	"github.com/containous/traefik/cluster"
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
 	"github.com/containous/traefik/safe"
 	traefikTls "github.com/containous/traefik/tls"
-	"github.com/containous/traefik/tls/generate"
 	"github.com/containous/traefik/types"
 	"github.com/eapache/channels"
 	"github.com/xenolf/lego/acme"
-	"github.com/xenolf/lego/providers/dns"
 )
 
 var (
@@ -42,15 +43,24 @@ type ACME struct {
 	Storage               string         `description:"File or key used for certificates storage."`
 	StorageFile           string         // deprecated
 	OnDemand              bool           `description:"Enable on demand certificate generation. This will request a certificate from Let's Encrypt during the first TLS handshake for a hostname that does not yet have a certificate."` //deprecated
+	OnDemandRules         *OnDemandRules `description:"Domains allowed to get a certificate on demand, and minimum interval between their requests."`
 	OnHostRule            bool           `description:"Enable certificate generation on frontends Host rules."`
 	CAServer              string         `description:"CA server to use."`
+	CAServerRules         CAServerRules  `description:"CA servers of the domains matching patterns, e.g. 'CAServer:https://acme-staging.api.letsencrypt.org/directory Domains:*.staging.example.com'"`
+	KeyType               string         `description:"Key type of the certificates: RSA2048, RSA4096 (default), EC256 or EC384."`
+	KeyTypeRules          KeyTypeRules   `description:"Key types of the domains matching patterns, e.g. 'KeyType:RSA2048 Domains:*.legacy.example.com'"`
+	PreferredChain        string         `description:"Common name of the root issuing the chain to serve, e.g. 'ISRG Root X1', the chain issued by the CA server being served otherwise."`
 	EntryPoint            string         `description:"Entrypoint to proxy acme challenge to."`
 	DNSChallenge          *DNSChallenge  `description:"Activate DNS-01 Challenge"`
 	HTTPChallenge         *HTTPChallenge `description:"Activate HTTP-01 Challenge"`
 	DNSProvider           string         `description:"Use a DNS-01 acme challenge rather than TLS-SNI-01 challenge."`                                // deprecated
 	DelayDontCheckDNS     flaeg.Duration `description:"Assume DNS propagates after a delay in seconds rather than finding and querying nameservers."` // deprecated
 	ACMELogging           bool           `description:"Enable debug logging of ACME actions."`
+	RenewBefore           flaeg.Duration `description:"Renew the certificates expiring within this duration, 30 days by default."`
+	RenewRetry            *RenewRetry    `description:"Retry schedule of the failed renewals, from one hour up to one day by default."`
 	client                *acme.Client
+	caClients             map[string]*acme.Client
+	caClientsLock         sync.Mutex
 	defaultCertificate    *tls.Certificate
 	store                 cluster.Store
 	challengeTLSProvider  *challengeTLSProvider
@@ -59,6 +69,14 @@ type ACME struct {
 	jobs                  *channels.InfiniteChannel
 	TLSConfig             *tls.Config `description:"TLS config in case wildcard certs are used"`
 	dynamicCerts          *safe.Safe
+	// nextGetCertificate is the previous callback of the TLS config, e.g. of another ACME configuration on the same entry point.
+	nextGetCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	renewalStates      map[string]*renewalState
+	renewalStatesLock  sync.RWMutex
+	// onDemandAttempts are the times of the last certificate requests on demand, by domain.
+	onDemandAttempts      map[string]time.Time
+	onDemandAttemptsLock  sync.Mutex
+	onDemandDomainRegexps []*regexp.Regexp
 }
 
 // DNSChallenge contains DNS challenge Configuration
@@ -68,8 +86,10 @@ type DNSChallenge struct {
 }
 
 // HTTPChallenge contains HTTP challenge Configuration
+// The challenge is served on the entry point, and on the dedicated address if any.
 type HTTPChallenge struct {
 	EntryPoint string `description:"HTTP challenge EntryPoint"`
+	Address    string `description:"Dedicated address serving the HTTP challenge, e.g. ':8089' when the port 80 is forwarded to it"`
 }
 
 //Domains parse []Domain
@@ -134,46 +154,31 @@ func (a *ACME) init() error {
 		acme.Logger = fmtlog.New(ioutil.Discard, "", 0)
 	}
 	// no certificates in TLS config, so we add a default one
-	cert, err := generate.DefaultCertificate()
+	cert, err := traefikTls.GenerateDefaultCertificate()
 	if err != nil {
 		return err
 	}
 	a.defaultCertificate = cert
 
+	a.onDemandDomainRegexps, err = a.onDemandRegexps()
+	if err != nil {
+		return err
+	}
+
 	a.jobs = channels.NewInfiniteChannel()
+	a.renewalStates = make(map[string]*renewalState)
+	a.onDemandAttempts = make(map[string]time.Time)
 	return nil
 }
 
 // AddRoutes add routes on internal router
 func (a *ACME) AddRoutes(router *mux.Router) {
-	router.Methods(http.MethodGet).
-		Path(acme.HTTP01ChallengePath("{token}")).
-		Handler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-			if a.challengeHTTPProvider == nil {
-				rw.WriteHeader(http.StatusNotFound)
-				return
-			}
-
-			vars := mux.Vars(req)
-			if token, ok := vars["token"]; ok {
-				domain, _, err := net.SplitHostPort(req.Host)
-				if err != nil {
-					log.Debugf("Unable to split host and port: %v. Fallback to request host.", err)
-					domain = req.Host
-				}
-				tokenValue := a.challengeHTTPProvider.getTokenValue(token, domain)
-				if len(tokenValue) > 0 {
-					rw.WriteHeader(http.StatusOK)
-					rw.Write(tokenValue)
-					return
-				}
-			}
-			rw.WriteHeader(http.StatusNotFound)
-		}))
+	AddChallengeRoutes(router, a)
 }
 
-// CreateClusterConfig creates a tls.config using ACME configuration in cluster mode
-func (a *ACME) CreateClusterConfig(leadership *cluster.Leadership, tlsConfig *tls.Config, certs *safe.Safe, checkOnDemandDomain func(domain string) bool) error {
+// CreateClusterConfig creates a tls.config using ACME configuration in cluster mode,
+// its routines renewing the certificates until the context is done.
+func (a *ACME) CreateClusterConfig(ctx context.Context, leadership *cluster.Leadership, tlsConfig *tls.Config, certs *safe.Safe, checkOnDemandDomain func(domain string) bool) error {
 	err := a.init()
 	if err != nil {
 		return err
@@ -184,6 +189,7 @@ func (a *ACME) CreateClusterConfig(leadership *cluster.Leadership, tlsConfig *tl
 	a.checkOnDemandDomain = checkOnDemandDomain
 	a.dynamicCerts = certs
 	tlsConfig.Certificates = append(tlsConfig.Certificates, *a.defaultCertificate)
+	a.nextGetCertificate = tlsConfig.GetCertificate
 	tlsConfig.GetCertificate = a.getCertificate
 	a.TLSConfig = tlsConfig
 	listener := func(object cluster.Object) error {
@@ -199,7 +205,7 @@ func (a *ACME) CreateClusterConfig(leadership *cluster.Leadership, tlsConfig *tl
 	}
 
 	datastore, err := cluster.NewDataStore(
-		leadership.Pool.Ctx(),
+		ctx,
 		staert.KvSource{
 			Store:  leadership.Store,
 			Prefix: a.Storage,
@@ -212,26 +218,37 @@ func (a *ACME) CreateClusterConfig(leadership *cluster.Leadership, tlsConfig *tl
 
 	a.store = datastore
 	a.challengeTLSProvider = &challengeTLSProvider{store: a.store}
+	a.challengeHTTPProvider = &challengeHTTPProvider{store: a.store}
+	a.registerRenewals()
 
-	ticker := time.NewTicker(24 * time.Hour)
-	leadership.Pool.AddGoCtx(func(ctx context.Context) {
+	ticker := time.NewTicker(a.renewCheckInterval())
+	safe.Go(func() {
 		log.Info("Starting ACME renew job...")
 		defer log.Info("Stopped ACME renew job...")
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				a.renewCertificates()
+				// only the leader renews the certificates
+				if leadership.IsLeader() {
+					a.renewCertificates()
+				}
 			}
 		}
 	})
 
-	leadership.AddListener(a.leadershipListener)
+	leadership.AddListener(func(elected bool) error {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return a.leadershipListener(ctx, elected)
+	})
 	return nil
 }
 
-func (a *ACME) leadershipListener(elected bool) error {
+func (a *ACME) leadershipListener(ctx context.Context, elected bool) error {
 	if elected {
 		_, err := a.store.Load()
 		if err != nil {
@@ -288,34 +305,45 @@ func (a *ACME) leadershipListener(elected bool) error {
 
 		a.retrieveCertificates()
 		a.renewCertificates()
-		a.runJobs()
+		a.runJobs(ctx)
 	}
 	return nil
 }
 
-// CreateLocalConfig creates a tls.config using local ACME configuration
-func (a *ACME) CreateLocalConfig(tlsConfig *tls.Config, certs *safe.Safe, checkOnDemandDomain func(domain string) bool) error {
-	defer a.runJobs()
+// CreateLocalConfig creates a tls.config using local ACME configuration,
+// its routines renewing the certificates until the context is done.
+func (a *ACME) CreateLocalConfig(ctx context.Context, tlsConfig *tls.Config, certs *safe.Safe, checkOnDemandDomain func(domain string) bool) error {
 	err := a.init()
 	if err != nil {
 		return err
 	}
+	defer a.runJobs(ctx)
 	if len(a.Storage) == 0 {
 		return errors.New("Empty Store, please provide a filename for certs storage")
 	}
 	a.checkOnDemandDomain = checkOnDemandDomain
 	a.dynamicCerts = certs
 	tlsConfig.Certificates = append(tlsConfig.Certificates, *a.defaultCertificate)
+	a.nextGetCertificate = tlsConfig.GetCertificate
 	tlsConfig.GetCertificate = a.getCertificate
 	a.TLSConfig = tlsConfig
-	localStore := NewLocalStore(a.Storage)
+	storage, err := NewStorage(a.Storage)
+	if err != nil {
+		return err
+	}
+	localStore := newStore(storage)
 	a.store = localStore
 	a.challengeTLSProvider = &challengeTLSProvider{store: a.store}
+	a.challengeHTTPProvider = &challengeHTTPProvider{store: a.store}
 
 	var needRegister bool
 	var account *Account
 
-	if fileInfo, fileErr := os.Stat(a.Storage); fileErr == nil && fileInfo.Size() != 0 {
+	stored, err := storage.Read()
+	if err != nil {
+		return err
+	}
+	if len(stored) != 0 {
 		log.Info("Loading ACME Account...")
 		// load account
 		object, err := localStore.Load()
@@ -375,18 +403,44 @@ Let's Encrypt functionality will be limited until traefik is restarted.`, err)
 		return err
 	}
 
+	a.registerRenewals()
 	a.retrieveCertificates()
 	a.renewCertificates()
 
-	ticker := time.NewTicker(24 * time.Hour)
+	ticker := time.NewTicker(a.renewCheckInterval())
 	safe.Go(func() {
-		for range ticker.C {
-			a.renewCertificates()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.renewCertificates()
+			}
 		}
 	})
 	return nil
 }
 
+// Certificates returns the certificates obtained from the CA servers.
+func (a *ACME) Certificates() []*tls.Certificate {
+	if a.store == nil {
+		return nil
+	}
+	account, ok := a.store.Get().(*Account)
+	if !ok || account == nil {
+		return nil
+	}
+
+	var certs []*tls.Certificate
+	for _, certificateResource := range account.DomainsCertificate.certificates() {
+		if certificateResource.tlsCert != nil {
+			certs = append(certs, certificateResource.tlsCert)
+		}
+	}
+	return certs
+}
+
 func (a *ACME) getCertificate(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
 	domain := types.CanonicalDomain(clientHello.ServerName)
 	account := a.store.Get().(*Account)
@@ -403,10 +457,18 @@ func (a *ACME) getCertificate(clientHello *tls.ClientHelloInfo) (*tls.Certificat
 		log.Debugf("ACME got domain cert %s", domain)
 		return domainCert.tlsCert, nil
 	}
+	if a.nextGetCertificate != nil {
+		if cert, err := a.nextGetCertificate(clientHello); cert != nil || err != nil {
+			return cert, err
+		}
+	}
 	if a.OnDemand {
 		if a.checkOnDemandDomain != nil && !a.checkOnDemandDomain(domain) {
 			return nil, nil
 		}
+		if !a.onDemandAllowed(domain, time.Now()) {
+			return nil, nil
+		}
 		return a.loadCertificateOnDemand(clientHello)
 	}
 	log.Debugf("No certificate found or generated for %s", domain)
@@ -452,14 +514,22 @@ func (a *ACME) retrieveCertificates() {
 
 func (a *ACME) renewCertificates() {
 	a.jobs.In() <- func() {
-		log.Info("Testing certificate renew...")
+		log.Debug("Testing certificate renew...")
 		account := a.store.Get().(*Account)
 		for _, certificateResource := range account.DomainsCertificate.Certs {
-			if certificateResource.needRenew() {
+			now := time.Now()
+			if certificateResource.needRenew(a.renewBefore()) && a.renewalDue(certificateResource.Domains.Main, now) {
 				log.Infof("Renewing certificate from LE : %+v", certificateResource.Domains)
 				renewedACMECert, err := a.renewACMECertificate(certificateResource)
 				if err != nil {
-					log.Errorf("Error renewing certificate from LE: %v", err)
+					nextAttempt := a.renewalFailed(certificateResource.Domains.Main, now, err)
+					log.Errorf("Error renewing certificate from LE: %v, retrying at %s", err, nextAttempt)
+					notification.Notify(notification.Event{
+						Type:    notification.CertificateExpiring,
+						Message: fmt.Sprintf("Certificate expiring in less than %s could not be renewed", a.renewBefore()),
+						Domains: append([]string{certificateResource.Domains.Main}, certificateResource.Domains.SANs...),
+						Error:   err.Error(),
+					})
 					continue
 				}
 				operation := func() error {
@@ -472,32 +542,53 @@ func (a *ACME) renewCertificates() {
 				ebo.MaxElapsedTime = 60 * time.Second
 				err = backoff.RetryNotify(safe.OperationWithRecover(operation), ebo, notify)
 				if err != nil {
+					a.renewalFailed(certificateResource.Domains.Main, now, err)
 					log.Errorf("Datastore cannot sync: %v", err)
 					continue
 				}
+				a.renewalSucceeded(certificateResource.Domains.Main, now)
 			}
 		}
 	}
 }
 
 func (a *ACME) renewACMECertificate(certificateResource *DomainsCertificate) (*Certificate, error) {
-	renewedCert, err := a.client.RenewCertificate(acme.CertificateResource{
+	client, err := a.getClient(certificateResource.Domains.Main)
+	if err != nil {
+		return nil, err
+	}
+	// the private key is kept on renewal, unless the key type of the domain changed
+	privateKey := certificateResource.Certificate.PrivateKey
+	if keyType := a.keyTypeForDomain(certificateResource.Domains.Main); privateKeyType(privateKey) != keyType {
+		log.Infof("Renewing certificate from LE with a new %s key: %+v", keyType, certificateResource.Domains)
+		if _, privateKey, err = generatePrivateKey(keyType); err != nil {
+			return nil, err
+		}
+	}
+	renewedCert, err := client.RenewCertificate(acme.CertificateResource{
 		Domain:        certificateResource.Certificate.Domain,
 		CertURL:       certificateResource.Certificate.CertURL,
 		CertStableURL: certificateResource.Certificate.CertStableURL,
-		PrivateKey:    certificateResource.Certificate.PrivateKey,
+		PrivateKey:    privateKey,
 		Certificate:   certificateResource.Certificate.Certificate,
 	}, true, OSCPMustStaple)
+	event := audit.Event{
+		Type:    audit.CertificateRenewed,
+		Domains: append([]string{certificateResource.Domains.Main}, certificateResource.Domains.SANs...),
+	}
 	if err != nil {
+		event.Error = err.Error()
+		audit.Record(event)
 		return nil, err
 	}
+	audit.Record(event)
 	log.Infof("Renewed certificate from  LE: %+v", certificateResource.Domains)
 	return &Certificate{
 		Domain:        renewedCert.Domain,
 		CertURL:       renewedCert.CertURL,
 		CertStableURL: renewedCert.CertStableURL,
 		PrivateKey:    renewedCert.PrivateKey,
-		Certificate:   renewedCert.Certificate,
+		Certificate:   a.selectChain(renewedCert.Certificate, certificateResource.Domains.Main),
 	}, nil
 }
 
@@ -545,12 +636,12 @@ func dnsOverrideDelay(delay flaeg.Duration) error {
 }
 
 func (a *ACME) buildACMEClient(account *Account) (*acme.Client, error) {
-	log.Debug("Building ACME client...")
-	caServer := "https://acme-v01.api.letsencrypt.org/directory"
-	if len(a.CAServer) > 0 {
-		caServer = a.CAServer
-	}
-	client, err := acme.NewClient(caServer, account, acme.RSA4096)
+	return a.newACMEClient(account, a.caServer())
+}
+
+func (a *ACME) newACMEClient(user acme.User, caServer string) (*acme.Client, error) {
+	log.Debugf("Building ACME client for CA server %s...", caServer)
+	client, err := acme.NewClient(caServer, user, a.keyType())
 	if err != nil {
 		return nil, err
 	}
@@ -564,16 +655,15 @@ func (a *ACME) buildACMEClient(account *Account) (*acme.Client, error) {
 		}
 
 		var provider acme.ChallengeProvider
-		provider, err = dns.NewDNSChallengeProviderByName(a.DNSChallenge.Provider)
+		provider, err = dnsChallengeProviderByName(a.DNSChallenge.Provider)
 		if err != nil {
 			return nil, err
 		}
 
 		client.ExcludeChallenges([]acme.Challenge{acme.HTTP01, acme.TLSSNI01})
 		err = client.SetChallengeProvider(acme.DNS01, provider)
-	} else if a.HTTPChallenge != nil && len(a.HTTPChallenge.EntryPoint) > 0 {
+	} else if a.HTTPChallenge != nil && (len(a.HTTPChallenge.EntryPoint) > 0 || len(a.HTTPChallenge.Address) > 0) {
 		client.ExcludeChallenges([]acme.Challenge{acme.DNS01, acme.TLSSNI01})
-		a.challengeHTTPProvider = &challengeHTTPProvider{store: a.store}
 		err = client.SetChallengeProvider(acme.HTTP01, a.challengeHTTPProvider)
 	} else {
 		client.ExcludeChallenges([]acme.Challenge{acme.HTTP01, acme.DNS01})
@@ -773,27 +863,54 @@ func searchUncheckedDomains(domains []string, certs map[string]*tls.Certificate)
 func (a *ACME) getDomainsCertificates(domains []string) (*Certificate, error) {
 	domains = fun.Map(types.CanonicalDomain, domains).([]string)
 	log.Debugf("Loading ACME certificates %s...", domains)
+	client, err := a.getClient(domains[0])
+	if err != nil {
+		return nil, err
+	}
+	privateKey, _, err := generatePrivateKey(a.keyTypeForDomain(domains[0]))
+	if err != nil {
+		return nil, err
+	}
 	bundle := true
-	certificate, failures := a.client.ObtainCertificate(domains, bundle, nil, OSCPMustStaple)
+	certificate, failures := client.ObtainCertificate(domains, bundle, privateKey, OSCPMustStaple)
 	if len(failures) > 0 {
 		log.Error(failures)
-		return nil, fmt.Errorf("cannot obtain certificates %+v", failures)
+		err := fmt.Errorf("cannot obtain certificates %+v", failures)
+		audit.Record(audit.Event{Type: audit.CertificateIssued, Domains: domains, Error: err.Error()})
+		notification.Notify(notification.Event{
+			Type:    notification.ACMEFailure,
+			Message: "Cannot obtain ACME certificate",
+			Domains: domains,
+			Error:   err.Error(),
+		})
+		return nil, err
 	}
+	audit.Record(audit.Event{Type: audit.CertificateIssued, Domains: domains})
 	log.Debugf("Loaded ACME certificates %s", domains)
 	return &Certificate{
 		Domain:        certificate.Domain,
 		CertURL:       certificate.CertURL,
 		CertStableURL: certificate.CertStableURL,
 		PrivateKey:    certificate.PrivateKey,
-		Certificate:   certificate.Certificate,
+		Certificate:   a.selectChain(certificate.Certificate, domains[0]),
 	}, nil
 }
 
-func (a *ACME) runJobs() {
+// runJobs runs the jobs of the ACME configuration until the context is done.
+func (a *ACME) runJobs(ctx context.Context) {
+	jobs := a.jobs
 	safe.Go(func() {
-		for job := range a.jobs.Out() {
-			function := job.(func())
-			function()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case job, ok := <-jobs.Out():
+				if !ok {
+					return
+				}
+				function := job.(func())
+				function()
+			}
 		}
 	})
 }
@@ -3,15 +3,21 @@ package acme
 import (
 	"crypto/tls"
 	"encoding/base64"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/containous/flaeg"
+	"github.com/containous/mux"
 	"github.com/containous/traefik/tls/generate"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/xenolf/lego/acme"
 )
 
@@ -322,3 +328,318 @@ func TestAcme_getProvidedCertificate(t *testing.T) {
 	certificate = a.getProvidedCertificate(domain)
 	assert.Nil(t, certificate)
 }
+
+func TestCAServerRulesSet(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		value         string
+		expected      CAServerRules
+		expectedError bool
+	}{
+		{
+			desc:  "rule",
+			value: "CAServer:https://acme-staging.api.letsencrypt.org/directory Domains:*.staging.example.com,test.example.com",
+			expected: CAServerRules{{
+				CAServer: "https://acme-staging.api.letsencrypt.org/directory",
+				Domains:  []string{"*.staging.example.com", "test.example.com"},
+			}},
+		},
+		{
+			desc:          "missing domains",
+			value:         "CAServer:https://acme-staging.api.letsencrypt.org/directory",
+			expectedError: true,
+		},
+		{
+			desc:          "unknown field",
+			value:         "CAServer:https://acme-staging.api.letsencrypt.org/directory Domains:example.com Foo:bar",
+			expectedError: true,
+		},
+		{
+			desc:          "bad format",
+			value:         "example.com",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rules := CAServerRules{}
+			err := rules.Set(test.value)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, rules)
+		})
+	}
+}
+
+func TestAcme_caServerForDomain(t *testing.T) {
+	a := &ACME{
+		CAServerRules: CAServerRules{
+			{CAServer: "https://staging", Domains: []string{"*.staging.example.com", "test.example.com"}},
+			{CAServer: "https://other", Domains: []string{"*.example.com"}},
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		domain   string
+		expected string
+	}{
+		{
+			desc:     "pattern",
+			domain:   "app.staging.example.com",
+			expected: "https://staging",
+		},
+		{
+			desc:     "exact domain with another case",
+			domain:   "Test.Example.com",
+			expected: "https://staging",
+		},
+		{
+			desc:     "first matching rule",
+			domain:   "app.example.com",
+			expected: "https://other",
+		},
+		{
+			desc:     "default CA server",
+			domain:   "example.org",
+			expected: defaultCAServer,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, a.caServerForDomain(test.domain))
+		})
+	}
+}
+
+func TestAcme_getClientErrorReleasesStore(t *testing.T) {
+	account, err := NewAccount("test@example.com")
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "acme")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewLocalStore(filepath.Join(dir, "acme.json"))
+	store.account = account
+	a := &ACME{
+		CAServerRules: CAServerRules{{CAServer: "http://127.0.0.1:1/directory", Domains: []string{"*.staging.example.com"}}},
+		store:         store,
+	}
+
+	_, err = a.getClient("app.staging.example.com")
+	require.Error(t, err)
+
+	// the store isn't left locked by the failed registration
+	begun := make(chan struct{})
+	go func() {
+		transaction, object, _ := store.Begin()
+		transaction.Commit(object)
+		close(begun)
+	}()
+	select {
+	case <-begun:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the store is still locked")
+	}
+}
+
+func TestAcme_AddRoutes(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "acme")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	fileContent, err := ioutil.ReadFile("./acme_example.json")
+	require.NoError(t, err)
+	_, err = tmpFile.Write(fileContent)
+	require.NoError(t, err)
+
+	store := NewLocalStore(tmpFile.Name())
+	_, err = store.Load()
+	require.NoError(t, err)
+
+	// the challenge is served from the stored account, without any ACME client
+	a := &ACME{challengeHTTPProvider: &challengeHTTPProvider{store: store}}
+	err = a.challengeHTTPProvider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	a.AddRoutes(router)
+
+	req := httptest.NewRequest(http.MethodGet, acme.HTTP01ChallengePath("token"), nil)
+	req.Host = "example.com:8089"
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "keyAuth", recorder.Body.String())
+}
+
+func TestResolversSet(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		value         string
+		expected      Resolvers
+		expectedError bool
+	}{
+		{
+			desc:  "resolver",
+			value: "Name:internal Email:ops@example.com Storage:internal.json EntryPoint:https CAServer:https://ca.example.com/acme/acme/directory OnHostRule:true",
+			expected: Resolvers{{
+				Name: "internal",
+				ACME: ACME{
+					Email:      "ops@example.com",
+					Storage:    "internal.json",
+					EntryPoint: "https",
+					CAServer:   "https://ca.example.com/acme/acme/directory",
+					OnHostRule: true,
+				},
+			}},
+		},
+		{
+			desc:  "challenges and domains",
+			value: "Name:internal DNSChallenge.Provider:route53 DNSChallenge.DelayBeforeCheck:10s HTTPChallenge.EntryPoint:http Domains:example.com,www.example.com",
+			expected: Resolvers{{
+				Name: "internal",
+				ACME: ACME{
+					DNSChallenge:  &DNSChallenge{Provider: "route53", DelayBeforeCheck: flaeg.Duration(10 * time.Second)},
+					HTTPChallenge: &HTTPChallenge{EntryPoint: "http"},
+					Domains:       Domains{{Main: "example.com", SANs: []string{"www.example.com"}}},
+				},
+			}},
+		},
+		{
+			desc:  "key type and renewal",
+			value: "Name:internal KeyType:EC256 RenewBefore:360h RenewRetry.InitialInterval:10m RenewRetry.MaxInterval:6h",
+			expected: Resolvers{{
+				Name: "internal",
+				ACME: ACME{
+					KeyType:     "EC256",
+					RenewBefore: flaeg.Duration(360 * time.Hour),
+					RenewRetry:  &RenewRetry{InitialInterval: flaeg.Duration(10 * time.Minute), MaxInterval: flaeg.Duration(6 * time.Hour)},
+				},
+			}},
+		},
+		{
+			desc:          "missing name",
+			value:         "Storage:internal.json",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid boolean",
+			value:         "Name:internal OnHostRule:maybe",
+			expectedError: true,
+		},
+		{
+			desc:          "unknown field",
+			value:         "Name:internal Foo:bar",
+			expectedError: true,
+		},
+		{
+			desc:          "bad format",
+			value:         "internal",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			resolvers := Resolvers{}
+			err := resolvers.Set(test.value)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, resolvers)
+		})
+	}
+}
+
+func TestAddChallengeRoutes(t *testing.T) {
+	public := &ACME{challengeHTTPProvider: &challengeHTTPProvider{store: &LocalStore{account: &Account{
+		HTTPChallenge: map[string]map[string][]byte{"other": {"public.example.com": []byte("publicKeyAuth")}},
+	}}}}
+	internal := &ACME{challengeHTTPProvider: &challengeHTTPProvider{store: &LocalStore{account: &Account{
+		HTTPChallenge: map[string]map[string][]byte{"token": {"internal.example.com": []byte("internalKeyAuth")}},
+	}}}}
+
+	router := mux.NewRouter()
+	AddChallengeRoutes(router, &ACME{}, public, internal)
+
+	req := httptest.NewRequest(http.MethodGet, acme.HTTP01ChallengePath("token"), nil)
+	req.Host = "internal.example.com"
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "internalKeyAuth", recorder.Body.String())
+}
+
+func TestAcme_getCertificateChaining(t *testing.T) {
+	newACME := func(domain string) *ACME {
+		store := &LocalStore{account: &Account{DomainsCertificate: DomainsCertificates{Certs: []*DomainsCertificate{{
+			Domains: Domain{Main: domain},
+			tlsCert: &tls.Certificate{OCSPStaple: []byte(domain)},
+		}}}}}
+		return &ACME{
+			TLSConfig:            &tls.Config{},
+			store:                store,
+			challengeTLSProvider: &challengeTLSProvider{store: store},
+		}
+	}
+	public := newACME("public.example.com")
+	internal := newACME("internal.example.com")
+	internal.nextGetCertificate = public.getCertificate
+
+	testCases := []struct {
+		desc       string
+		serverName string
+		expected   string
+	}{
+		{
+			desc:       "certificate of the configuration",
+			serverName: "internal.example.com",
+			expected:   "internal.example.com",
+		},
+		{
+			desc:       "certificate of the next configuration",
+			serverName: "public.example.com",
+			expected:   "public.example.com",
+		},
+		{
+			desc:       "unknown domain",
+			serverName: "unknown.example.com",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cert, err := internal.getCertificate(&tls.ClientHelloInfo{ServerName: test.serverName})
+			require.NoError(t, err)
+			if len(test.expected) == 0 {
+				assert.Nil(t, cert)
+				return
+			}
+			require.NotNil(t, cert)
+			assert.Equal(t, test.expected, string(cert.OCSPStaple))
+		})
+	}
+}
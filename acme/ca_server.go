@@ -0,0 +1,160 @@
+package acme
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+	"github.com/ryanuber/go-glob"
+	"github.com/xenolf/lego/acme"
+)
+
+const defaultCAServer = "https://acme-v01.api.letsencrypt.org/directory"
+
+// CAServerRule selects the CA server of the certificates whose main domain matches one of its domains,
+// e.g. the staging CA server for the testing domains.
+type CAServerRule struct {
+	CAServer string   `description:"CA server to use"`
+	Domains  []string `description:"Domains or patterns such as *.staging.example.com"`
+}
+
+func (r CAServerRule) matches(domain string) bool {
+	return matchesDomain(r.Domains, domain)
+}
+
+// matchesDomain checks whether the canonical domain matches one of the domains or patterns.
+func matchesDomain(patterns []string, domain string) bool {
+	for _, pattern := range patterns {
+		if glob.Glob(types.CanonicalDomain(pattern), domain) {
+			return true
+		}
+	}
+	return false
+}
+
+//CAServerRules parse []CAServerRule
+type CAServerRules []CAServerRule
+
+//Set adds a rule written as 'CAServer:https://... Domains:*.staging.example.com,test.example.com'
+func (rs *CAServerRules) Set(str string) error {
+	rule := CAServerRule{}
+	for _, field := range strings.Fields(str) {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("bad CA server rule format: %s", str)
+		}
+		switch strings.ToLower(kv[0]) {
+		case "caserver":
+			rule.CAServer = kv[1]
+		case "domains":
+			rule.Domains = strings.Split(kv[1], ",")
+		default:
+			return fmt.Errorf("unknown CA server rule field %s: %s", kv[0], str)
+		}
+	}
+	if len(rule.CAServer) == 0 || len(rule.Domains) == 0 {
+		return fmt.Errorf("CA server rule without CA server or domains: %s", str)
+	}
+	*rs = append(*rs, rule)
+	return nil
+}
+
+//Get []CAServerRule
+func (rs *CAServerRules) Get() interface{} { return []CAServerRule(*rs) }
+
+//String returns []CAServerRule in string
+func (rs *CAServerRules) String() string { return fmt.Sprintf("%+v", *rs) }
+
+//SetValue sets []CAServerRule into the parser
+func (rs *CAServerRules) SetValue(val interface{}) {
+	*rs = val.(CAServerRules)
+}
+
+// caServer returns the default CA server.
+func (a *ACME) caServer() string {
+	if len(a.CAServer) > 0 {
+		return a.CAServer
+	}
+	return defaultCAServer
+}
+
+// caServerForDomain returns the CA server of the first rule matching the domain, or the default one.
+func (a *ACME) caServerForDomain(domain string) string {
+	domain = types.CanonicalDomain(domain)
+	for _, rule := range a.CAServerRules {
+		if rule.matches(domain) {
+			return rule.CAServer
+		}
+	}
+	return a.caServer()
+}
+
+// caUser is the account registered on a CA server selected by a rule,
+// the registrations being specific to each CA server.
+type caUser struct {
+	*Account
+	registration *acme.RegistrationResource
+}
+
+// GetRegistration returns the registration on the CA server
+func (u *caUser) GetRegistration() *acme.RegistrationResource {
+	return u.registration
+}
+
+// getClient returns the client of the CA server of the certificates whose main domain is given,
+// registering the account on the CA server when it is selected by a rule for the first time.
+func (a *ACME) getClient(domain string) (*acme.Client, error) {
+	caServer := a.caServerForDomain(domain)
+	if caServer == a.caServer() {
+		return a.client, nil
+	}
+
+	a.caClientsLock.Lock()
+	defer a.caClientsLock.Unlock()
+
+	if client, ok := a.caClients[caServer]; ok {
+		return client, nil
+	}
+
+	// the registrations are only written below, while holding the lock of the CA clients
+	account := a.store.Get().(*Account)
+
+	user := &caUser{Account: account, registration: account.Registrations[caServer]}
+	client, err := a.newACMEClient(user, caServer)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.registration == nil {
+		// registered before beginning the transaction, for the store not to stay locked on a failure
+		log.Infof("Register on CA server %s...", caServer)
+		user.registration, err = client.Register()
+		if err != nil {
+			return nil, err
+		}
+
+		transaction, object, err := a.store.Begin()
+		if err != nil {
+			return nil, err
+		}
+		account = object.(*Account)
+		if account.Registrations == nil {
+			account.Registrations = make(map[string]*acme.RegistrationResource)
+		}
+		account.Registrations[caServer] = user.registration
+		if err = transaction.Commit(account); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = client.AgreeToTOS(); err != nil {
+		log.Errorf("Error sending ACME agreement to TOS of CA server %s: %v", caServer, err)
+	}
+
+	if a.caClients == nil {
+		a.caClients = make(map[string]*acme.Client)
+	}
+	a.caClients[caServer] = client
+	return client, nil
+}
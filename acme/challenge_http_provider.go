@@ -19,21 +19,29 @@ type challengeHTTPProvider struct {
 	lock  sync.RWMutex
 }
 
-func (c *challengeHTTPProvider) getTokenValue(token, domain string) []byte {
+// getTokenValue returns the key authorization of the challenge of the token for the domain, stored by one of the providers,
+// e.g. of the ACME configurations sharing an entry point, waiting for it to be stored by another node in cluster mode.
+func getTokenValue(providers []*challengeHTTPProvider, token, domain string) []byte {
 	log.Debugf("Looking for an existing ACME challenge for token %v...", token)
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-	account := c.store.Get().(*Account)
-	if account.HTTPChallenge == nil {
+	hasChallenges := false
+	for _, c := range providers {
+		if c.hasChallenges() {
+			hasChallenges = true
+			break
+		}
+	}
+	if !hasChallenges {
 		return []byte{}
 	}
 	var result []byte
 	operation := func() error {
-		var ok bool
-		if result, ok = account.HTTPChallenge[token][domain]; !ok {
-			return fmt.Errorf("cannot find challenge for token %v", token)
+		for _, c := range providers {
+			var ok bool
+			if result, ok = c.lookupTokenValue(token, domain); ok {
+				return nil
+			}
 		}
-		return nil
+		return fmt.Errorf("cannot find challenge for token %v", token)
 	}
 	notify := func(err error, time time.Duration) {
 		log.Errorf("Error getting challenge for token retrying in %s", time)
@@ -48,6 +56,19 @@ func (c *challengeHTTPProvider) getTokenValue(token, domain string) []byte {
 	return result
 }
 
+func (c *challengeHTTPProvider) hasChallenges() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.store.Get().(*Account).HTTPChallenge != nil
+}
+
+func (c *challengeHTTPProvider) lookupTokenValue(token, domain string) ([]byte, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	result, ok := c.store.Get().(*Account).HTTPChallenge[token][domain]
+	return result, ok
+}
+
 func (c *challengeHTTPProvider) Present(domain, token, keyAuth string) error {
 	log.Debugf("Challenge Present %s", domain)
 	c.lock.Lock()
@@ -0,0 +1,215 @@
+package acme
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/xenolf/lego/acme"
+	"github.com/xenolf/lego/providers/dns"
+)
+
+// rawMode passes the domain, token and key authorization of the challenge to the exec and httpreq providers,
+// instead of the FQDN and value of the TXT record.
+const rawMode = "RAW"
+
+// defaultExecTimeout is the time given to the program of the exec provider, after which it is killed.
+const defaultExecTimeout = time.Minute
+
+// execOutputDelay is the time the output of the program of the exec provider is waited for once it exited,
+// the children of a killed program keeping its output open.
+const execOutputDelay = time.Second
+
+// dnsChallengeProviderByName returns the DNS-01 challenge provider of the name, the exec and httpreq providers
+// delegating the TXT records to the in-house DNS systems not supported by lego.
+func dnsChallengeProviderByName(name string) (acme.ChallengeProvider, error) {
+	switch name {
+	case "exec":
+		return newExecDNSProvider()
+	case "httpreq":
+		return newHTTPReqDNSProvider()
+	}
+	return dns.NewDNSChallengeProviderByName(name)
+}
+
+// execDNSProvider creates and cleans up the TXT records of the challenges with an external program, called as
+// 'program present|cleanup <fqdn> <value>', or 'program present|cleanup <domain> <token> <keyAuth>' in raw mode.
+type execDNSProvider struct {
+	path    string
+	raw     bool
+	timeout time.Duration
+}
+
+// newExecDNSProvider creates the provider from the EXEC_PATH, EXEC_MODE and EXEC_TIMEOUT environment variables.
+func newExecDNSProvider() (*execDNSProvider, error) {
+	path := os.Getenv("EXEC_PATH")
+	if len(path) == 0 {
+		return nil, errors.New("exec DNS provider: missing EXEC_PATH")
+	}
+
+	timeout := defaultExecTimeout
+	if value := os.Getenv("EXEC_TIMEOUT"); len(value) > 0 {
+		var err error
+		timeout, err = time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("exec DNS provider: invalid EXEC_TIMEOUT %q", value)
+		}
+	}
+
+	return &execDNSProvider{
+		path:    path,
+		raw:     strings.EqualFold(os.Getenv("EXEC_MODE"), rawMode),
+		timeout: timeout,
+	}, nil
+}
+
+// Present creates the TXT record of the challenge.
+func (p *execDNSProvider) Present(domain, token, keyAuth string) error {
+	return p.run("present", domain, token, keyAuth)
+}
+
+// CleanUp removes the TXT record of the challenge.
+func (p *execDNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return p.run("cleanup", domain, token, keyAuth)
+}
+
+func (p *execDNSProvider) run(command, domain, token, keyAuth string) error {
+	args := []string{command, domain, token, keyAuth}
+	if !p.raw {
+		fqdn, value, _ := acme.DNS01Record(domain, keyAuth)
+		args = []string{command, fqdn, value}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	output, err := combinedOutput(exec.CommandContext(ctx, p.path, args...))
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("exec DNS provider: %s %s timed out after %s", p.path, command, p.timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("exec DNS provider: %s %s failed: %v: %s", p.path, command, err, strings.TrimSpace(string(output)))
+	}
+	log.Debugf("exec DNS provider: %s %s for %s: %s", p.path, command, domain, strings.TrimSpace(string(output)))
+	return nil
+}
+
+// combinedOutput runs the command and returns its standard output and error,
+// the output of the children of a killed program being waited for execOutputDelay only.
+func combinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	err = cmd.Start()
+	writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	outputChan := make(chan []byte, 1)
+	go func() {
+		output, _ := ioutil.ReadAll(reader)
+		outputChan <- output
+	}()
+
+	err = cmd.Wait()
+	select {
+	case output := <-outputChan:
+		return output, err
+	case <-time.After(execOutputDelay):
+		return nil, err
+	}
+}
+
+// httpReqDNSProvider creates and cleans up the TXT records of the challenges by posting them to a webhook,
+// on its /present and /cleanup paths.
+type httpReqDNSProvider struct {
+	endpoint string
+	raw      bool
+	username string
+	password string
+	client   *http.Client
+}
+
+// httpReqMessage is the JSON body of the webhook requests, with the FQDN and value of the TXT record,
+// or the domain, token and key authorization in raw mode.
+type httpReqMessage struct {
+	FQDN    string `json:"fqdn,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Domain  string `json:"domain,omitempty"`
+	Token   string `json:"token,omitempty"`
+	KeyAuth string `json:"keyAuth,omitempty"`
+}
+
+// newHTTPReqDNSProvider creates the provider from the HTTPREQ_ENDPOINT, HTTPREQ_MODE, HTTPREQ_USERNAME
+// and HTTPREQ_PASSWORD environment variables.
+func newHTTPReqDNSProvider() (*httpReqDNSProvider, error) {
+	endpoint := os.Getenv("HTTPREQ_ENDPOINT")
+	if len(endpoint) == 0 {
+		return nil, errors.New("httpreq DNS provider: missing HTTPREQ_ENDPOINT")
+	}
+	return &httpReqDNSProvider{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		raw:      strings.EqualFold(os.Getenv("HTTPREQ_MODE"), rawMode),
+		username: os.Getenv("HTTPREQ_USERNAME"),
+		password: os.Getenv("HTTPREQ_PASSWORD"),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Present creates the TXT record of the challenge.
+func (p *httpReqDNSProvider) Present(domain, token, keyAuth string) error {
+	return p.send("present", domain, token, keyAuth)
+}
+
+// CleanUp removes the TXT record of the challenge.
+func (p *httpReqDNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return p.send("cleanup", domain, token, keyAuth)
+}
+
+func (p *httpReqDNSProvider) send(action, domain, token, keyAuth string) error {
+	message := httpReqMessage{Domain: domain, Token: token, KeyAuth: keyAuth}
+	if !p.raw {
+		fqdn, value, _ := acme.DNS01Record(domain, keyAuth)
+		message = httpReqMessage{FQDN: fqdn, Value: value}
+	}
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.endpoint+"/"+action, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(p.username) > 0 || len(p.password) > 0 {
+		req.SetBasicAuth(p.username, p.password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("httpreq DNS provider: %s failed: %v", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		data, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("httpreq DNS provider: %s failed with status code %d: %s", action, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package acme
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xenolf/lego/acme"
+)
+
+func setEnv(t *testing.T, env map[string]string) func() {
+	t.Helper()
+
+	for key, value := range env {
+		require.NoError(t, os.Setenv(key, value))
+	}
+	return func() {
+		for key := range env {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestExecDNSProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exec")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the script records its arguments, and fails on the failing domain
+	output := filepath.Join(dir, "output")
+	script := filepath.Join(dir, "dns.sh")
+	require.NoError(t, ioutil.WriteFile(script, []byte("#!/bin/sh\necho \"$@\" >> "+output+"\ncase \"$*\" in *failing*) echo 'zone not found'; exit 1;; esac\n"), 0700))
+
+	fqdn, value, _ := acme.DNS01Record("example.com", "keyAuth")
+
+	testCases := []struct {
+		desc          string
+		mode          string
+		domain        string
+		expected      []string
+		expectedError string
+	}{
+		{
+			desc:     "TXT record",
+			domain:   "example.com",
+			expected: []string{"present " + fqdn + " " + value, "cleanup " + fqdn + " " + value},
+		},
+		{
+			desc:     "raw mode",
+			mode:     "RAW",
+			domain:   "example.com",
+			expected: []string{"present example.com token keyAuth", "cleanup example.com token keyAuth"},
+		},
+		{
+			desc:          "failing program",
+			domain:        "failing.example.com",
+			expectedError: "exec DNS provider: " + script + " present failed: exit status 1: zone not found",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer setEnv(t, map[string]string{"EXEC_PATH": script, "EXEC_MODE": test.mode})()
+			os.Remove(output)
+
+			provider, err := dnsChallengeProviderByName("exec")
+			require.NoError(t, err)
+
+			err = provider.Present(test.domain, "token", "keyAuth")
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, provider.CleanUp(test.domain, "token", "keyAuth"))
+
+			data, err := ioutil.ReadFile(output)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, strings.Split(strings.TrimSpace(string(data)), "\n"))
+		})
+	}
+}
+
+func TestExecDNSProviderTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exec")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	script := filepath.Join(dir, "dns.sh")
+	require.NoError(t, ioutil.WriteFile(script, []byte("#!/bin/sh\nsleep 10\n"), 0700))
+	defer setEnv(t, map[string]string{"EXEC_PATH": script, "EXEC_TIMEOUT": "100ms"})()
+
+	provider, err := dnsChallengeProviderByName("exec")
+	require.NoError(t, err)
+
+	start := time.Now()
+	err = provider.Present("example.com", "token", "keyAuth")
+	assert.EqualError(t, err, "exec DNS provider: "+script+" present timed out after 100ms")
+	assert.True(t, time.Since(start) < 5*time.Second, "the program is not killed")
+}
+
+func TestExecDNSProviderInvalidTimeout(t *testing.T) {
+	defer setEnv(t, map[string]string{"EXEC_PATH": "/bin/true", "EXEC_TIMEOUT": "soon"})()
+
+	_, err := dnsChallengeProviderByName("exec")
+	assert.EqualError(t, err, `exec DNS provider: invalid EXEC_TIMEOUT "soon"`)
+}
+
+func TestExecDNSProviderWithoutPath(t *testing.T) {
+	_, err := dnsChallengeProviderByName("exec")
+	assert.EqualError(t, err, "exec DNS provider: missing EXEC_PATH")
+}
+
+func TestHTTPReqDNSProvider(t *testing.T) {
+	fqdn, value, _ := acme.DNS01Record("example.com", "keyAuth")
+
+	testCases := []struct {
+		desc          string
+		env           map[string]string
+		status        int
+		expected      []string
+		expectedError string
+	}{
+		{
+			desc:   "TXT record",
+			status: http.StatusOK,
+			expected: []string{
+				`/present {"fqdn":"` + fqdn + `","value":"` + value + `"}`,
+				`/cleanup {"fqdn":"` + fqdn + `","value":"` + value + `"}`,
+			},
+		},
+		{
+			desc:   "raw mode with basic auth",
+			env:    map[string]string{"HTTPREQ_MODE": "RAW", "HTTPREQ_USERNAME": "traefik", "HTTPREQ_PASSWORD": "s3cr3t"},
+			status: http.StatusNoContent,
+			expected: []string{
+				`traefik:s3cr3t /present {"domain":"example.com","token":"token","keyAuth":"keyAuth"}`,
+				`traefik:s3cr3t /cleanup {"domain":"example.com","token":"token","keyAuth":"keyAuth"}`,
+			},
+		},
+		{
+			desc:          "webhook error",
+			status:        http.StatusInternalServerError,
+			expectedError: "httpreq DNS provider: present failed with status code 500: zone not found",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				message := httpReqMessage{}
+				require.NoError(t, json.NewDecoder(req.Body).Decode(&message))
+				body, err := json.Marshal(message)
+				require.NoError(t, err)
+
+				request := req.URL.Path + " " + string(body)
+				if username, password, ok := req.BasicAuth(); ok {
+					request = username + ":" + password + " " + request
+				}
+				requests = append(requests, request)
+
+				rw.WriteHeader(test.status)
+				if test.status != http.StatusOK && test.status != http.StatusNoContent {
+					rw.Write([]byte("zone not found\n"))
+				}
+			}))
+			defer server.Close()
+
+			env := map[string]string{"HTTPREQ_ENDPOINT": server.URL + "/"}
+			for key, value := range test.env {
+				env[key] = value
+			}
+			defer setEnv(t, env)()
+
+			provider, err := dnsChallengeProviderByName("httpreq")
+			require.NoError(t, err)
+
+			err = provider.Present("example.com", "token", "keyAuth")
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, provider.CleanUp("example.com", "token", "keyAuth"))
+
+			assert.Equal(t, test.expected, requests)
+		})
+	}
+}
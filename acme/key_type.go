@@ -0,0 +1,170 @@
+package acme
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/containous/traefik/types"
+	"github.com/xenolf/lego/acme"
+)
+
+const defaultKeyType = acme.RSA4096
+
+// keyTypes are the key types of the certificates by name.
+var keyTypes = map[string]acme.KeyType{
+	"RSA2048": acme.RSA2048,
+	"RSA4096": acme.RSA4096,
+	"EC256":   acme.EC256,
+	"EC384":   acme.EC384,
+}
+
+// KeyTypeRule selects the key type of the certificates whose main domain matches one of its domains,
+// e.g. RSA keys for the domains of clients not supporting ECDSA.
+type KeyTypeRule struct {
+	KeyType string   `description:"Key type to use: RSA2048, RSA4096, EC256 or EC384"`
+	Domains []string `description:"Domains or patterns such as *.legacy.example.com"`
+}
+
+//KeyTypeRules parse []KeyTypeRule
+type KeyTypeRules []KeyTypeRule
+
+//Set adds a rule written as 'KeyType:RSA2048 Domains:*.legacy.example.com,old.example.com'
+func (rs *KeyTypeRules) Set(str string) error {
+	rule := KeyTypeRule{}
+	for _, field := range strings.Fields(str) {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("bad key type rule format: %s", str)
+		}
+		switch strings.ToLower(kv[0]) {
+		case "keytype":
+			rule.KeyType = kv[1]
+		case "domains":
+			rule.Domains = strings.Split(kv[1], ",")
+		default:
+			return fmt.Errorf("unknown key type rule field %s: %s", kv[0], str)
+		}
+	}
+	if len(rule.KeyType) == 0 || len(rule.Domains) == 0 {
+		return fmt.Errorf("key type rule without key type or domains: %s", str)
+	}
+	*rs = append(*rs, rule)
+	return nil
+}
+
+//Get []KeyTypeRule
+func (rs *KeyTypeRules) Get() interface{} { return []KeyTypeRule(*rs) }
+
+//String returns []KeyTypeRule in string
+func (rs *KeyTypeRules) String() string { return fmt.Sprintf("%+v", *rs) }
+
+//SetValue sets []KeyTypeRule into the parser
+func (rs *KeyTypeRules) SetValue(val interface{}) {
+	*rs = val.(KeyTypeRules)
+}
+
+// ValidateKeyTypes checks the key types of the configuration and of its rules.
+func (a *ACME) ValidateKeyTypes() error {
+	if _, ok := keyTypes[a.KeyType]; len(a.KeyType) > 0 && !ok {
+		return fmt.Errorf("invalid key type %q", a.KeyType)
+	}
+	for _, rule := range a.KeyTypeRules {
+		if _, ok := keyTypes[rule.KeyType]; !ok {
+			return fmt.Errorf("invalid key type %q for domains %s", rule.KeyType, strings.Join(rule.Domains, ","))
+		}
+	}
+	return nil
+}
+
+// keyType returns the default key type of the certificates.
+func (a *ACME) keyType() acme.KeyType {
+	if keyType, ok := keyTypes[a.KeyType]; ok {
+		return keyType
+	}
+	return defaultKeyType
+}
+
+// keyTypeForDomain returns the key type of the first rule matching the domain, or the default one.
+func (a *ACME) keyTypeForDomain(domain string) acme.KeyType {
+	domain = types.CanonicalDomain(domain)
+	for _, rule := range a.KeyTypeRules {
+		if matchesDomain(rule.Domains, domain) {
+			if keyType, ok := keyTypes[rule.KeyType]; ok {
+				return keyType
+			}
+		}
+	}
+	return a.keyType()
+}
+
+// generatePrivateKey generates a private key of the key type, PEM encoded as the ACME client stores it.
+func generatePrivateKey(keyType acme.KeyType) (crypto.PrivateKey, []byte, error) {
+	switch keyType {
+	case acme.EC256, acme.EC384:
+		curve := elliptic.P256()
+		if keyType == acme.EC384 {
+			curve = elliptic.P384()
+		}
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		return key, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+	case acme.RSA2048, acme.RSA4096:
+		bits := 2048
+		if keyType == acme.RSA4096 {
+			bits = 4096
+		}
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, nil, err
+		}
+		return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), nil
+	}
+	return nil, nil, fmt.Errorf("invalid key type %q", keyType)
+}
+
+// privateKeyType returns the key type of a PEM encoded private key, or an empty key type if it can't be parsed.
+func privateKeyType(pemKey []byte) acme.KeyType {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return ""
+	}
+
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return ""
+		}
+		switch key.Curve {
+		case elliptic.P256():
+			return acme.EC256
+		case elliptic.P384():
+			return acme.EC384
+		}
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return ""
+		}
+		switch key.N.BitLen() {
+		case 2048:
+			return acme.RSA2048
+		case 4096:
+			return acme.RSA4096
+		}
+	}
+	return ""
+}
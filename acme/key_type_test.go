@@ -0,0 +1,126 @@
+package acme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xenolf/lego/acme"
+)
+
+func TestKeyTypeRulesSet(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		value         string
+		expected      KeyTypeRules
+		expectedError bool
+	}{
+		{
+			desc:  "rule",
+			value: "KeyType:RSA2048 Domains:*.legacy.example.com,old.example.com",
+			expected: KeyTypeRules{{
+				KeyType: "RSA2048",
+				Domains: []string{"*.legacy.example.com", "old.example.com"},
+			}},
+		},
+		{
+			desc:          "missing domains",
+			value:         "KeyType:RSA2048",
+			expectedError: true,
+		},
+		{
+			desc:          "unknown field",
+			value:         "KeyType:RSA2048 Domains:example.com Foo:bar",
+			expectedError: true,
+		},
+		{
+			desc:          "bad format",
+			value:         "example.com",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rules := KeyTypeRules{}
+			err := rules.Set(test.value)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, rules)
+		})
+	}
+}
+
+func TestAcme_keyTypeForDomain(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		keyType  string
+		domain   string
+		expected acme.KeyType
+	}{
+		{
+			desc:     "pattern",
+			keyType:  "EC256",
+			domain:   "app.legacy.example.com",
+			expected: acme.RSA2048,
+		},
+		{
+			desc:     "exact domain with another case",
+			keyType:  "EC256",
+			domain:   "Old.Example.com",
+			expected: acme.RSA2048,
+		},
+		{
+			desc:     "configured key type",
+			keyType:  "EC384",
+			domain:   "example.org",
+			expected: acme.EC384,
+		},
+		{
+			desc:     "default key type",
+			domain:   "example.org",
+			expected: acme.RSA4096,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			a := &ACME{
+				KeyType: test.keyType,
+				KeyTypeRules: KeyTypeRules{
+					{KeyType: "RSA2048", Domains: []string{"*.legacy.example.com", "old.example.com"}},
+				},
+			}
+			assert.Equal(t, test.expected, a.keyTypeForDomain(test.domain))
+		})
+	}
+}
+
+func TestAcme_ValidateKeyTypes(t *testing.T) {
+	assert.NoError(t, (&ACME{}).ValidateKeyTypes())
+	assert.NoError(t, (&ACME{KeyType: "EC256", KeyTypeRules: KeyTypeRules{{KeyType: "RSA2048", Domains: []string{"example.com"}}}}).ValidateKeyTypes())
+	assert.EqualError(t, (&ACME{KeyType: "RSA1024"}).ValidateKeyTypes(), `invalid key type "RSA1024"`)
+	assert.EqualError(t, (&ACME{KeyTypeRules: KeyTypeRules{{KeyType: "EC521", Domains: []string{"example.com"}}}}).ValidateKeyTypes(), `invalid key type "EC521" for domains example.com`)
+}
+
+func TestGeneratePrivateKey(t *testing.T) {
+	for _, keyType := range []acme.KeyType{acme.EC256, acme.EC384, acme.RSA2048} {
+		key, pemKey, err := generatePrivateKey(keyType)
+		require.NoError(t, err, keyType)
+		assert.NotNil(t, key)
+		assert.Equal(t, keyType, privateKeyType(pemKey))
+	}
+
+	_, _, err := generatePrivateKey(acme.RSA8192)
+	assert.Error(t, err)
+	assert.Empty(t, privateKeyType([]byte("not a key")))
+}
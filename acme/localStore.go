@@ -3,8 +3,6 @@ package acme
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"os"
 	"sync"
 
 	"github.com/containous/traefik/cluster"
@@ -13,17 +11,22 @@ import (
 
 var _ cluster.Store = (*LocalStore)(nil)
 
-// LocalStore is a store using a file as storage
+// LocalStore is a store using a file, or another storage outside of the cluster mode
 type LocalStore struct {
-	file        string
+	storage     Storage
 	storageLock sync.RWMutex
 	account     *Account
 }
 
 // NewLocalStore create a LocalStore
 func NewLocalStore(file string) *LocalStore {
+	return NewStorageStore(&fileStorage{file: file})
+}
+
+// NewStorageStore creates a LocalStore using the given storage.
+func NewStorageStore(storage Storage) *LocalStore {
 	return &LocalStore{
-		file: file,
+		storage: storage,
 	}
 }
 
@@ -40,25 +43,19 @@ func (s *LocalStore) Load() (cluster.Object, error) {
 	defer s.storageLock.Unlock()
 	account := &Account{}
 
-	err := checkPermissions(s.file)
+	data, err := s.storage.Read()
 	if err != nil {
 		return nil, err
 	}
-	f, err := os.Open(s.file)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	file, err := ioutil.ReadAll(f)
-	if err != nil {
-		return nil, err
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no ACME account stored in %s", s.storage)
 	}
-	if err := json.Unmarshal(file, &account); err != nil {
+	if err := json.Unmarshal(data, &account); err != nil {
 		return nil, err
 	}
 	account.Init()
 	s.account = account
-	log.Infof("Loaded ACME config from store %s", s.file)
+	log.Infof("Loaded ACME config from store %s", s.storage)
 	return account, nil
 }
 
@@ -83,12 +80,12 @@ func (t *localTransaction) Commit(object cluster.Object) error {
 		return fmt.Errorf("transaction already used, please begin a new one")
 	}
 
-	// write account to file
+	// write account to storage
 	data, err := json.MarshalIndent(object, "", "  ")
 	if err != nil {
 		return err
 	}
-	err = ioutil.WriteFile(t.file, data, 0600)
+	err = t.storage.Write(data)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,93 @@
+package acme
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/log"
+)
+
+const defaultOnDemandInterval = time.Hour
+
+// OnDemandRules restricts the certificates requested on demand to the allowed domains, at most one per domain per interval,
+// so that handshakes with bogus server names can't exhaust the rate limits of the CA server.
+type OnDemandRules struct {
+	Domains       []string       `description:"Domains or patterns such as *.example.com allowed to get a certificate on demand"`
+	DomainRegexps []string       `description:"Regular expressions of the domains allowed to get a certificate on demand"`
+	Interval      flaeg.Duration `description:"Minimum interval between two certificate requests for the same domain, one hour by default"`
+}
+
+// ValidateOnDemandRules checks the regular expressions of the on demand rules.
+func (a *ACME) ValidateOnDemandRules() error {
+	_, err := a.onDemandRegexps()
+	return err
+}
+
+func (a *ACME) onDemandRegexps() ([]*regexp.Regexp, error) {
+	if a.OnDemandRules == nil {
+		return nil, nil
+	}
+
+	var regexps []*regexp.Regexp
+	for _, expr := range a.OnDemandRules.DomainRegexps {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid on demand domain regexp %q: %v", expr, err)
+		}
+		regexps = append(regexps, re)
+	}
+	return regexps, nil
+}
+
+// onDemandDomainAllowed checks whether the domain matches the domains or the regular expressions of the on demand rules,
+// all the domains being allowed without rules.
+func (a *ACME) onDemandDomainAllowed(domain string) bool {
+	if a.OnDemandRules == nil || (len(a.OnDemandRules.Domains) == 0 && len(a.onDemandDomainRegexps) == 0) {
+		return true
+	}
+	if matchesDomain(a.OnDemandRules.Domains, domain) {
+		return true
+	}
+	for _, re := range a.onDemandDomainRegexps {
+		if re.MatchString(domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// onDemandInterval returns the minimum interval between two certificate requests for the same domain.
+func (a *ACME) onDemandInterval() time.Duration {
+	if a.OnDemandRules != nil && a.OnDemandRules.Interval > 0 {
+		return time.Duration(a.OnDemandRules.Interval)
+	}
+	return defaultOnDemandInterval
+}
+
+// onDemandAllowed checks whether a certificate can be requested on demand for the domain,
+// and records the request attempt if it can.
+func (a *ACME) onDemandAllowed(domain string, now time.Time) bool {
+	if !a.onDemandDomainAllowed(domain) {
+		log.Debugf("Domain %s is not allowed to get a certificate on demand", domain)
+		return false
+	}
+
+	a.onDemandAttemptsLock.Lock()
+	defer a.onDemandAttemptsLock.Unlock()
+
+	interval := a.onDemandInterval()
+	if lastAttempt, ok := a.onDemandAttempts[domain]; ok && now.Sub(lastAttempt) < interval {
+		log.Debugf("A certificate was requested on demand for %s at %s, not requesting another one before %s", domain, lastAttempt, lastAttempt.Add(interval))
+		return false
+	}
+
+	for d, lastAttempt := range a.onDemandAttempts {
+		if now.Sub(lastAttempt) >= interval {
+			delete(a.onDemandAttempts, d)
+		}
+	}
+	a.onDemandAttempts[domain] = now
+	return true
+}
@@ -0,0 +1,89 @@
+package acme
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnDemandAllowed(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		desc        string
+		rules       *OnDemandRules
+		domain      string
+		lastAttempt time.Time
+		expected    bool
+	}{
+		{
+			desc:     "no rules",
+			domain:   "www.example.com",
+			expected: true,
+		},
+		{
+			desc:     "allowed domain pattern",
+			rules:    &OnDemandRules{Domains: []string{"*.example.com"}},
+			domain:   "www.example.com",
+			expected: true,
+		},
+		{
+			desc:     "allowed domain regexp",
+			rules:    &OnDemandRules{Domains: []string{"*.example.com"}, DomainRegexps: []string{`^[a-z]+\.customers\.example\.org$`}},
+			domain:   "acme.customers.example.org",
+			expected: true,
+		},
+		{
+			desc:   "domain not allowed",
+			rules:  &OnDemandRules{Domains: []string{"*.example.com"}, DomainRegexps: []string{`^[a-z]+\.customers\.example\.org$`}},
+			domain: "bogus.example.net",
+		},
+		{
+			desc:        "requested within the default interval",
+			domain:      "www.example.com",
+			lastAttempt: now.Add(-30 * time.Minute),
+		},
+		{
+			desc:        "requested before the default interval",
+			domain:      "www.example.com",
+			lastAttempt: now.Add(-2 * time.Hour),
+			expected:    true,
+		},
+		{
+			desc:        "requested before the interval",
+			rules:       &OnDemandRules{Interval: flaeg.Duration(10 * time.Minute)},
+			domain:      "www.example.com",
+			lastAttempt: now.Add(-30 * time.Minute),
+			expected:    true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			a := &ACME{OnDemandRules: test.rules, onDemandAttempts: make(map[string]time.Time)}
+			a.onDemandDomainRegexps, _ = a.onDemandRegexps()
+			if !test.lastAttempt.IsZero() {
+				a.onDemandAttempts[test.domain] = test.lastAttempt
+			}
+
+			assert.Equal(t, test.expected, a.onDemandAllowed(test.domain, now))
+			if test.expected {
+				assert.False(t, a.onDemandAllowed(test.domain, now.Add(time.Second)), "the attempt is recorded")
+			}
+		})
+	}
+}
+
+func TestValidateOnDemandRules(t *testing.T) {
+	a := &ACME{OnDemandRules: &OnDemandRules{DomainRegexps: []string{`^[a-z]+\.example\.com$`, `(`}}}
+
+	err := a.ValidateOnDemandRules()
+	_, expected := regexp.Compile(`(`)
+	assert.EqualError(t, err, `invalid on demand domain regexp "(": `+expected.Error())
+}
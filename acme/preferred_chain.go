@@ -0,0 +1,48 @@
+package acme
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/containous/traefik/log"
+)
+
+// selectChain returns the chain of a PEM encoded certificate bundle issued by the CA server, as it's served:
+// with a preferred chain, the bundle is cut after its first certificate issued by the root of this common name,
+// e.g. 'ISRG Root X1' to drop the certificate cross-signed by 'DST Root CA X3' from the Let's Encrypt chain.
+// The bundle is kept as is when none of its certificates is issued by this root.
+func (a *ACME) selectChain(bundle []byte, domain string) []byte {
+	if len(a.PreferredChain) == 0 {
+		return bundle
+	}
+
+	var chain []byte
+	rest := bundle
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		chain = append(chain, pem.EncodeToMemory(block)...)
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			log.Warnf("Unable to parse the certificate chain of %s, serving it as issued: %v", domain, err)
+			return bundle
+		}
+		if cert.Issuer.CommonName == a.PreferredChain {
+			if len(bytes.TrimSpace(rest)) > 0 {
+				log.Debugf("Serving the chain of %s issued by %s", domain, a.PreferredChain)
+			}
+			return chain
+		}
+	}
+
+	log.Debugf("No chain of %s is issued by %s, serving the chain as issued", domain, a.PreferredChain)
+	return bundle
+}
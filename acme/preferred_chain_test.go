@@ -0,0 +1,104 @@
+package acme
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateChain generates a certificate issued by each of the issuers in turn, the first one being self-signed,
+// and returns them PEM encoded from the last one, as a bundle served by a CA server.
+func generateChain(t *testing.T, commonNames ...string) []byte {
+	t.Helper()
+
+	var bundle []byte
+	var issuer *x509.Certificate
+	var issuerKey *ecdsa.PrivateKey
+	for i, commonName := range commonNames {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(int64(i + 1)),
+			Subject:               pkix.Name{CommonName: commonName},
+			NotBefore:             time.Now(),
+			NotAfter:              time.Now().Add(time.Hour),
+			BasicConstraintsValid: true,
+			IsCA:                  i < len(commonNames)-1,
+		}
+		if issuer == nil {
+			issuer, issuerKey = template, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, issuerKey)
+		require.NoError(t, err)
+		issuer, err = x509.ParseCertificate(der)
+		require.NoError(t, err)
+		issuerKey = key
+
+		if i > 0 {
+			bundle = append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), bundle...)
+		}
+	}
+	return bundle
+}
+
+// chainCommonNames returns the common names of the certificates of a PEM encoded bundle.
+func chainCommonNames(t *testing.T, bundle []byte) []string {
+	t.Helper()
+
+	var commonNames []string
+	for block, rest := pem.Decode(bundle); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		commonNames = append(commonNames, cert.Subject.CommonName)
+	}
+	return commonNames
+}
+
+func TestSelectChain(t *testing.T) {
+	bundle := generateChain(t, "DST Root CA X3", "ISRG Root X1", "R3", "example.com")
+
+	testCases := []struct {
+		desc           string
+		preferredChain string
+		expected       []string
+	}{
+		{
+			desc:     "no preferred chain",
+			expected: []string{"example.com", "R3", "ISRG Root X1"},
+		},
+		{
+			desc:           "chain of the intermediate root",
+			preferredChain: "ISRG Root X1",
+			expected:       []string{"example.com", "R3"},
+		},
+		{
+			desc:           "chain of the cross-signing root",
+			preferredChain: "DST Root CA X3",
+			expected:       []string{"example.com", "R3", "ISRG Root X1"},
+		},
+		{
+			desc:           "unknown root",
+			preferredChain: "Other Root",
+			expected:       []string{"example.com", "R3", "ISRG Root X1"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			a := &ACME{PreferredChain: test.preferredChain}
+			assert.Equal(t, test.expected, chainCommonNames(t, a.selectChain(bundle, "example.com")))
+		})
+	}
+}
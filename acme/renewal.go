@@ -0,0 +1,222 @@
+package acme
+
+import (
+	"crypto/x509"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containous/flaeg"
+)
+
+const (
+	defaultRenewBefore               = 30 * 24 * time.Hour
+	defaultRenewRetryInitialInterval = time.Hour
+	defaultRenewRetryMaxInterval     = 24 * time.Hour
+	defaultRenewCheckInterval        = time.Hour
+	renewRetryIntervalMultiplier     = 2
+)
+
+var (
+	renewalsLock sync.RWMutex
+	// renewals are the ACME configurations whose renewals are exposed by the API, by storage.
+	renewals = make(map[string]*ACME)
+)
+
+// RenewRetry configures the retries of the failed renewals, the interval between two attempts doubling
+// from the initial interval up to the maximum interval.
+type RenewRetry struct {
+	InitialInterval flaeg.Duration `description:"Interval before retrying a failed renewal the first time"`
+	MaxInterval     flaeg.Duration `description:"Maximum interval between the retries of a failed renewal"`
+}
+
+// RenewalStatus is the state of the renewal of a certificate, as exposed by the API.
+type RenewalStatus struct {
+	Storage     string     `json:"storage"`
+	Domains     Domain     `json:"domains"`
+	NotAfter    time.Time  `json:"notAfter"`
+	LastAttempt *time.Time `json:"lastAttempt,omitempty"`
+	NextAttempt time.Time  `json:"nextAttempt"`
+	Attempts    int        `json:"attempts,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// renewalState is the state of the renewal attempts of a certificate, by main domain.
+type renewalState struct {
+	lastAttempt time.Time
+	nextAttempt time.Time
+	attempts    int
+	err         string
+}
+
+// RenewalStatuses returns the states of the renewals of the certificates of all the ACME configurations,
+// sorted by storage and main domain.
+func RenewalStatuses() []RenewalStatus {
+	renewalsLock.RLock()
+	defer renewalsLock.RUnlock()
+
+	statuses := make([]RenewalStatus, 0)
+	for _, a := range renewals {
+		statuses = append(statuses, a.renewalStatuses()...)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Storage != statuses[j].Storage {
+			return statuses[i].Storage < statuses[j].Storage
+		}
+		return statuses[i].Domains.Main < statuses[j].Domains.Main
+	})
+	return statuses
+}
+
+// IssuedSerialNumbers returns the serial numbers, in lowercase hexadecimal, of the certificates
+// of all the ACME configurations.
+func IssuedSerialNumbers() map[string]bool {
+	renewalsLock.RLock()
+	defer renewalsLock.RUnlock()
+
+	serials := make(map[string]bool)
+	for _, a := range renewals {
+		if a.store == nil {
+			continue
+		}
+		account, ok := a.store.Get().(*Account)
+		if !ok || account == nil {
+			continue
+		}
+		for _, certificateResource := range account.DomainsCertificate.certificates() {
+			if leaf := certificateResource.leaf(); leaf != nil {
+				serials[leaf.SerialNumber.Text(16)] = true
+			}
+		}
+	}
+	return serials
+}
+
+// registerRenewals exposes the renewals of the ACME configuration, replacing the previous configuration of its storage.
+func (a *ACME) registerRenewals() {
+	renewalsLock.Lock()
+	defer renewalsLock.Unlock()
+
+	renewals[a.Storage] = a
+}
+
+func (a *ACME) renewalStatuses() []RenewalStatus {
+	if a.store == nil {
+		return nil
+	}
+	account, ok := a.store.Get().(*Account)
+	if !ok || account == nil {
+		return nil
+	}
+
+	a.renewalStatesLock.RLock()
+	defer a.renewalStatesLock.RUnlock()
+
+	var statuses []RenewalStatus
+	for _, certificateResource := range account.DomainsCertificate.certificates() {
+		status := RenewalStatus{
+			Storage: a.Storage,
+			Domains: certificateResource.Domains,
+		}
+		if leaf := certificateResource.leaf(); leaf != nil {
+			status.NotAfter = leaf.NotAfter
+			status.NextAttempt = leaf.NotAfter.Add(-a.renewBefore())
+		}
+		if state, ok := a.renewalStates[certificateResource.Domains.Main]; ok {
+			lastAttempt := state.lastAttempt
+			status.LastAttempt = &lastAttempt
+			status.Attempts = state.attempts
+			status.Error = state.err
+			if !state.nextAttempt.IsZero() {
+				status.NextAttempt = state.nextAttempt
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// renewBefore returns the duration before the expiration of the certificates from which they are renewed.
+func (a *ACME) renewBefore() time.Duration {
+	if a.RenewBefore > 0 {
+		return time.Duration(a.RenewBefore)
+	}
+	return defaultRenewBefore
+}
+
+// renewCheckInterval returns the interval between the checks of the certificates to renew,
+// short enough for the failed renewals to be retried on time.
+func (a *ACME) renewCheckInterval() time.Duration {
+	if initialInterval := a.renewRetryInterval(1); initialInterval < defaultRenewCheckInterval {
+		return initialInterval
+	}
+	return defaultRenewCheckInterval
+}
+
+// renewRetryInterval returns the interval before the next attempt after the given number of failed attempts.
+func (a *ACME) renewRetryInterval(attempts int) time.Duration {
+	initialInterval, maxInterval := defaultRenewRetryInitialInterval, defaultRenewRetryMaxInterval
+	if a.RenewRetry != nil {
+		if a.RenewRetry.InitialInterval > 0 {
+			initialInterval = time.Duration(a.RenewRetry.InitialInterval)
+		}
+		if a.RenewRetry.MaxInterval > 0 {
+			maxInterval = time.Duration(a.RenewRetry.MaxInterval)
+		}
+	}
+
+	interval := initialInterval
+	for i := 1; i < attempts && interval < maxInterval; i++ {
+		interval *= renewRetryIntervalMultiplier
+	}
+	if interval > maxInterval {
+		return maxInterval
+	}
+	return interval
+}
+
+// renewalDue checks whether the renewal of the certificate of the domain is not waiting for its next retry.
+func (a *ACME) renewalDue(domain string, now time.Time) bool {
+	a.renewalStatesLock.RLock()
+	defer a.renewalStatesLock.RUnlock()
+
+	state, ok := a.renewalStates[domain]
+	return !ok || !now.Before(state.nextAttempt)
+}
+
+// renewalFailed records a failed renewal attempt, and returns the time of the next attempt.
+func (a *ACME) renewalFailed(domain string, now time.Time, err error) time.Time {
+	a.renewalStatesLock.Lock()
+	defer a.renewalStatesLock.Unlock()
+
+	state, ok := a.renewalStates[domain]
+	if !ok {
+		state = &renewalState{}
+		a.renewalStates[domain] = state
+	}
+	state.lastAttempt = now
+	state.attempts++
+	state.nextAttempt = now.Add(a.renewRetryInterval(state.attempts))
+	state.err = err.Error()
+	return state.nextAttempt
+}
+
+// renewalSucceeded records a successful renewal attempt, resetting the retries.
+func (a *ACME) renewalSucceeded(domain string, now time.Time) {
+	a.renewalStatesLock.Lock()
+	defer a.renewalStatesLock.Unlock()
+
+	a.renewalStates[domain] = &renewalState{lastAttempt: now}
+}
+
+// leaf returns the parsed certificate of the domains, without its chain.
+func (dc *DomainsCertificate) leaf() *x509.Certificate {
+	if dc.tlsCert == nil || len(dc.tlsCert.Certificate) == 0 {
+		return nil
+	}
+	leaf, err := x509.ParseCertificate(dc.tlsCert.Certificate[0])
+	if err != nil {
+		return nil
+	}
+	return leaf
+}
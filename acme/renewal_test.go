@@ -0,0 +1,98 @@
+package acme
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewRetryInterval(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		renewRetry *RenewRetry
+		attempts   int
+		expected   time.Duration
+	}{
+		{
+			desc:     "default first retry",
+			attempts: 1,
+			expected: time.Hour,
+		},
+		{
+			desc:     "default third retry",
+			attempts: 3,
+			expected: 4 * time.Hour,
+		},
+		{
+			desc:     "default maximum interval",
+			attempts: 10,
+			expected: 24 * time.Hour,
+		},
+		{
+			desc:       "configured first retry",
+			renewRetry: &RenewRetry{InitialInterval: flaeg.Duration(10 * time.Minute), MaxInterval: flaeg.Duration(time.Hour)},
+			attempts:   1,
+			expected:   10 * time.Minute,
+		},
+		{
+			desc:       "configured maximum interval",
+			renewRetry: &RenewRetry{InitialInterval: flaeg.Duration(10 * time.Minute), MaxInterval: flaeg.Duration(time.Hour)},
+			attempts:   4,
+			expected:   time.Hour,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			a := &ACME{RenewRetry: test.renewRetry}
+			assert.Equal(t, test.expected, a.renewRetryInterval(test.attempts))
+		})
+	}
+}
+
+func TestRenewCheckInterval(t *testing.T) {
+	assert.Equal(t, time.Hour, (&ACME{}).renewCheckInterval())
+	assert.Equal(t, 10*time.Minute, (&ACME{RenewRetry: &RenewRetry{InitialInterval: flaeg.Duration(10 * time.Minute)}}).renewCheckInterval())
+}
+
+func TestRenewalStates(t *testing.T) {
+	now := time.Now()
+	a := &ACME{
+		Storage:       "acme.json",
+		renewalStates: make(map[string]*renewalState),
+		store:         &LocalStore{account: &Account{DomainsCertificate: DomainsCertificates{Certs: []*DomainsCertificate{{Domains: Domain{Main: "example.com"}}}}}},
+	}
+	assert.True(t, a.renewalDue("example.com", now))
+
+	nextAttempt := a.renewalFailed("example.com", now, errors.New("rate limited"))
+	assert.Equal(t, now.Add(time.Hour), nextAttempt)
+	assert.False(t, a.renewalDue("example.com", now.Add(time.Minute)))
+	assert.True(t, a.renewalDue("example.com", now.Add(time.Hour)))
+
+	nextAttempt = a.renewalFailed("example.com", now.Add(time.Hour), errors.New("rate limited"))
+	assert.Equal(t, now.Add(3*time.Hour), nextAttempt)
+
+	statuses := a.renewalStatuses()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "acme.json", statuses[0].Storage)
+	assert.Equal(t, Domain{Main: "example.com"}, statuses[0].Domains)
+	assert.Equal(t, 2, statuses[0].Attempts)
+	assert.Equal(t, now.Add(3*time.Hour), statuses[0].NextAttempt)
+	require.NotNil(t, statuses[0].LastAttempt)
+	assert.Equal(t, now.Add(time.Hour), *statuses[0].LastAttempt)
+	assert.Equal(t, "rate limited", statuses[0].Error)
+
+	a.renewalSucceeded("example.com", now.Add(3*time.Hour))
+	assert.True(t, a.renewalDue("example.com", now.Add(3*time.Hour)))
+	statuses = a.renewalStatuses()
+	require.Len(t, statuses, 1)
+	assert.Zero(t, statuses[0].Attempts)
+	assert.Empty(t, statuses[0].Error)
+}
@@ -0,0 +1,140 @@
+package acme
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/containous/mux"
+	"github.com/containous/traefik/log"
+	"github.com/xenolf/lego/acme"
+)
+
+// Resolver is an ACME configuration selected by name by the frontends, in addition to the default one:
+// it has its own CA server, account, storage and challenge, e.g. to get the certificates of the internal domains
+// from an internal CA while the public ones come from Let's Encrypt.
+type Resolver struct {
+	Name string `description:"Name of the resolver, selected by the frontends"`
+	ACME
+}
+
+// Resolvers holds a Resolver parser
+type Resolvers []*Resolver
+
+//Set adds a resolver written as 'Name:... Email:... Storage:... EntryPoint:... CAServer:... OnHostRule:true DNSChallenge.Provider:...' into the parser
+func (rs *Resolvers) Set(str string) error {
+	resolver := &Resolver{}
+	for _, field := range strings.Fields(str) {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("bad ACME resolver format: %s", str)
+		}
+		var err error
+		switch strings.ToLower(kv[0]) {
+		case "name":
+			resolver.Name = kv[1]
+		case "email":
+			resolver.Email = kv[1]
+		case "storage":
+			resolver.Storage = kv[1]
+		case "entrypoint":
+			resolver.EntryPoint = kv[1]
+		case "caserver":
+			resolver.CAServer = kv[1]
+		case "onhostrule":
+			resolver.OnHostRule, err = strconv.ParseBool(kv[1])
+		case "acmelogging":
+			resolver.ACMELogging, err = strconv.ParseBool(kv[1])
+		case "domains":
+			domains := strings.Split(kv[1], ",")
+			resolver.Domains = append(resolver.Domains, Domain{Main: domains[0], SANs: domains[1:]})
+		case "keytype":
+			resolver.KeyType = kv[1]
+		case "renewbefore":
+			err = resolver.RenewBefore.Set(kv[1])
+		case "renewretry.initialinterval":
+			if resolver.RenewRetry == nil {
+				resolver.RenewRetry = &RenewRetry{}
+			}
+			err = resolver.RenewRetry.InitialInterval.Set(kv[1])
+		case "renewretry.maxinterval":
+			if resolver.RenewRetry == nil {
+				resolver.RenewRetry = &RenewRetry{}
+			}
+			err = resolver.RenewRetry.MaxInterval.Set(kv[1])
+		case "dnschallenge.provider":
+			if resolver.DNSChallenge == nil {
+				resolver.DNSChallenge = &DNSChallenge{}
+			}
+			resolver.DNSChallenge.Provider = kv[1]
+		case "dnschallenge.delaybeforecheck":
+			if resolver.DNSChallenge == nil {
+				resolver.DNSChallenge = &DNSChallenge{}
+			}
+			err = resolver.DNSChallenge.DelayBeforeCheck.Set(kv[1])
+		case "httpchallenge.entrypoint":
+			if resolver.HTTPChallenge == nil {
+				resolver.HTTPChallenge = &HTTPChallenge{}
+			}
+			resolver.HTTPChallenge.EntryPoint = kv[1]
+		default:
+			return fmt.Errorf("unknown ACME resolver field %s: %s", kv[0], str)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid ACME resolver %s: %v", kv[0], err)
+		}
+	}
+	if len(resolver.Name) == 0 {
+		return fmt.Errorf("missing ACME resolver name: %s", str)
+	}
+	*rs = append(*rs, resolver)
+	return nil
+}
+
+//Get []*Resolver
+func (rs *Resolvers) Get() interface{} { return []*Resolver(*rs) }
+
+//String returns []*Resolver in string
+func (rs *Resolvers) String() string { return fmt.Sprintf("%+v", *rs) }
+
+//SetValue sets []*Resolver into the parser
+func (rs *Resolvers) SetValue(val interface{}) {
+	*rs = val.(Resolvers)
+}
+
+// AddChallengeRoutes adds the route serving the HTTP challenges of the ACME configurations sharing an entry point
+// or a dedicated address, each of them answering the challenges of its own certificates.
+func AddChallengeRoutes(router *mux.Router, configurations ...*ACME) {
+	router.Methods(http.MethodGet).
+		Path(acme.HTTP01ChallengePath("{token}")).
+		Handler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			var providers []*challengeHTTPProvider
+			for _, a := range configurations {
+				if a.challengeHTTPProvider != nil {
+					providers = append(providers, a.challengeHTTPProvider)
+				}
+			}
+			if len(providers) == 0 {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			vars := mux.Vars(req)
+			if token, ok := vars["token"]; ok {
+				domain, _, err := net.SplitHostPort(req.Host)
+				if err != nil {
+					log.Debugf("Unable to split host and port: %v. Fallback to request host.", err)
+					domain = req.Host
+				}
+				tokenValue := getTokenValue(providers, token, domain)
+				if len(tokenValue) > 0 {
+					rw.WriteHeader(http.StatusOK)
+					rw.Write(tokenValue)
+					return
+				}
+			}
+			rw.WriteHeader(http.StatusNotFound)
+		}))
+}
@@ -0,0 +1,85 @@
+package acme
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/containous/traefik/cluster"
+)
+
+const (
+	storageSchemeS3         = "s3"
+	storageSchemeKubernetes = "kubernetes"
+)
+
+// Storage reads and writes the account and certificates of a local store, serialized in JSON.
+type Storage interface {
+	// Read returns the stored data, empty when nothing is stored yet.
+	Read() ([]byte, error)
+	Write(data []byte) error
+	String() string
+}
+
+// NewStorage returns the storage of an ACME configuration outside of the cluster mode:
+// an object of an S3-compatible store for 's3://bucket/key', a Kubernetes Secret for 'kubernetes://namespace/name',
+// and a file otherwise.
+func NewStorage(storage string) (Storage, error) {
+	if !strings.Contains(storage, "://") {
+		return &fileStorage{file: storage}, nil
+	}
+
+	u, err := url.Parse(storage)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ACME storage %q: %v", storage, err)
+	}
+	name := strings.Trim(u.Path, "/")
+
+	switch u.Scheme {
+	case storageSchemeS3:
+		if len(u.Host) == 0 || len(name) == 0 {
+			return nil, fmt.Errorf("invalid ACME storage %q, expected s3://bucket/key", storage)
+		}
+		return newS3Storage(u.Host, name)
+	case storageSchemeKubernetes:
+		if len(u.Host) == 0 || len(name) == 0 || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid ACME storage %q, expected kubernetes://namespace/name", storage)
+		}
+		return newKubernetesStorage(u.Host, name)
+	}
+	return nil, fmt.Errorf("unknown ACME storage type %q", u.Scheme)
+}
+
+// newStore returns the store of the account and certificates in the storage,
+// a Kubernetes Secret being read again by each transaction for several instances to share it.
+func newStore(storage Storage) cluster.Store {
+	if secret, ok := storage.(*kubernetesStorage); ok {
+		return &kubernetesStore{storage: secret}
+	}
+	return NewStorageStore(storage)
+}
+
+// fileStorage stores the data in a file, readable only by its owner.
+type fileStorage struct {
+	file string
+}
+
+func (s *fileStorage) Read() ([]byte, error) {
+	if fileInfo, err := os.Stat(s.file); os.IsNotExist(err) || (err == nil && fileInfo.Size() == 0) {
+		return nil, nil
+	}
+	if err := checkPermissions(s.file); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(s.file)
+}
+
+func (s *fileStorage) Write(data []byte) error {
+	return ioutil.WriteFile(s.file, data, 0600)
+}
+
+func (s *fileStorage) String() string {
+	return s.file
+}
@@ -0,0 +1,200 @@
+package acme
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/containous/traefik/cluster"
+	"github.com/containous/traefik/log"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// kubernetesStorageDataKey is the data entry of the Secret holding the stored data.
+const kubernetesStorageDataKey = "acme.json"
+
+// secretsClient is the part of the Kubernetes Secrets API of a namespace used by the storage.
+type secretsClient interface {
+	Get(name string, options metav1.GetOptions) (*corev1.Secret, error)
+	Create(secret *corev1.Secret) (*corev1.Secret, error)
+	Update(secret *corev1.Secret) (*corev1.Secret, error)
+}
+
+// kubernetesStorage stores the data in a Kubernetes Secret, with the in-cluster configuration of the service account.
+// The Secret is created by the first write, and updated only if it wasn't modified since it was read,
+// another instance writing the same Secret making the write fail instead of losing its changes.
+type kubernetesStorage struct {
+	namespace string
+	name      string
+	secrets   secretsClient
+	lock      sync.Mutex
+	// resourceVersion is the version of the Secret when it was last read or written, empty if it doesn't exist.
+	resourceVersion string
+}
+
+func newKubernetesStorage(namespace, name string) (*kubernetesStorage, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-cluster configuration: %v", err)
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kubernetesStorage{
+		namespace: namespace,
+		name:      name,
+		secrets:   clientset.CoreV1().Secrets(namespace),
+	}, nil
+}
+
+func (s *kubernetesStorage) Read() ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	secret, err := s.secrets.Get(s.name, metav1.GetOptions{})
+	if kubeerrors.IsNotFound(err) {
+		s.resourceVersion = ""
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %v", s, err)
+	}
+	s.resourceVersion = secret.ResourceVersion
+	return secret.Data[kubernetesStorageDataKey], nil
+}
+
+func (s *kubernetesStorage) Write(data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            s.name,
+			Namespace:       s.namespace,
+			ResourceVersion: s.resourceVersion,
+			Labels:          map[string]string{"app.kubernetes.io/managed-by": "traefik"},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{kubernetesStorageDataKey: data},
+	}
+
+	var err error
+	if len(s.resourceVersion) == 0 {
+		secret, err = s.secrets.Create(secret)
+	} else {
+		secret, err = s.secrets.Update(secret)
+	}
+	if kubeerrors.IsAlreadyExists(err) || kubeerrors.IsConflict(err) {
+		return fmt.Errorf("unable to write %s, modified by another instance: %v", s, err)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write %s: %v", s, err)
+	}
+	s.resourceVersion = secret.ResourceVersion
+	return nil
+}
+
+func (s *kubernetesStorage) String() string {
+	return fmt.Sprintf("kubernetes://%s/%s", s.namespace, s.name)
+}
+
+var _ cluster.Store = (*kubernetesStore)(nil)
+
+// kubernetesStore is a store in a Kubernetes Secret shared by several instances.
+// A transaction reads the Secret again, for the changes of the other instances to be kept,
+// and commits only if the Secret wasn't modified since, by compare-and-set on its resource version.
+type kubernetesStore struct {
+	storage     *kubernetesStorage
+	accountLock sync.RWMutex
+	account     *Account
+	// transactionLock is held from the beginning of a transaction until it is committed.
+	transactionLock sync.Mutex
+}
+
+// Get returns the account read or committed last.
+func (s *kubernetesStore) Get() cluster.Object {
+	s.accountLock.RLock()
+	defer s.accountLock.RUnlock()
+	return s.account
+}
+
+// Load reads the account stored in the Secret.
+func (s *kubernetesStore) Load() (cluster.Object, error) {
+	account, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("no ACME account stored in %s", s.storage)
+	}
+	log.Infof("Loaded ACME config from store %s", s.storage)
+	return account, nil
+}
+
+// Begin reads the Secret again, returning the account stored to be changed and committed.
+func (s *kubernetesStore) Begin() (cluster.Transaction, cluster.Object, error) {
+	s.transactionLock.Lock()
+	if _, err := s.read(); err != nil {
+		s.transactionLock.Unlock()
+		return nil, nil, err
+	}
+	return &kubernetesTransaction{kubernetesStore: s}, s.Get(), nil
+}
+
+// read reads the account stored in the Secret, nil if none is stored yet, which becomes the current one.
+func (s *kubernetesStore) read() (*Account, error) {
+	data, err := s.storage.Read()
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	account := &Account{}
+	if err := json.Unmarshal(data, account); err != nil {
+		return nil, err
+	}
+	account.Init()
+
+	s.accountLock.Lock()
+	s.account = account
+	s.accountLock.Unlock()
+	return account, nil
+}
+
+var _ cluster.Transaction = (*kubernetesTransaction)(nil)
+
+type kubernetesTransaction struct {
+	*kubernetesStore
+	dirty bool
+}
+
+// Commit writes the account in the Secret, unless it was modified since the transaction began.
+// The Secret is then read again, for the next transaction to change the account of the other instance.
+func (t *kubernetesTransaction) Commit(object cluster.Object) error {
+	if t.dirty {
+		return fmt.Errorf("transaction already used, please begin a new one")
+	}
+	t.dirty = true
+	defer t.transactionLock.Unlock()
+
+	data, err := json.MarshalIndent(object, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := t.storage.Write(data); err != nil {
+		if _, readErr := t.read(); readErr != nil {
+			log.Errorf("Error reading %s again: %v", t.storage, readErr)
+		}
+		return err
+	}
+
+	t.accountLock.Lock()
+	t.account = object.(*Account)
+	t.accountLock.Unlock()
+	return nil
+}
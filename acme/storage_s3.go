@@ -0,0 +1,109 @@
+package acme
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+const defaultS3Region = "us-east-1"
+
+// s3Storage stores the data in an object of an S3-compatible store, addressed with a path-style URL.
+// The endpoint, region and credentials are read from the environment:
+// AWS_ENDPOINT_URL_S3 for the S3-compatible stores such as MinIO, AWS_REGION,
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY or the shared credentials file.
+type s3Storage struct {
+	bucket   string
+	key      string
+	endpoint string
+	region   string
+	signer   *v4.Signer
+	client   *http.Client
+}
+
+func newS3Storage(bucket, key string) (*s3Storage, error) {
+	region := os.Getenv("AWS_REGION")
+	if len(region) == 0 {
+		region = defaultS3Region
+	}
+	endpoint := strings.TrimRight(os.Getenv("AWS_ENDPOINT_URL_S3"), "/")
+	if len(endpoint) == 0 {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	if _, err := url.Parse(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint %q: %v", endpoint, err)
+	}
+
+	creds := credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvProvider{},
+		&credentials.SharedCredentialsProvider{},
+	})
+	return &s3Storage{
+		bucket:   bucket,
+		key:      key,
+		endpoint: endpoint,
+		region:   region,
+		signer:   v4.NewSigner(creds),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (s *s3Storage) Read() ([]byte, error) {
+	resp, err := s.do(http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return data, nil
+	case http.StatusNotFound:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unable to read %s: unexpected status code %d: %s", s, resp.StatusCode, data)
+}
+
+func (s *s3Storage) Write(data []byte) error {
+	resp, err := s.do(http.MethodPut, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unable to write %s: unexpected status code %d: %s", s, resp.StatusCode, body)
+	}
+	return nil
+}
+
+func (s *s3Storage) do(method string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, s.key), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if _, err := s.signer.Sign(req, bytes.NewReader(body), "s3", s.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("unable to sign the request to %s: %v", s, err)
+	}
+	return s.client.Do(req)
+}
+
+func (s *s3Storage) String() string {
+	return fmt.Sprintf("s3://%s/%s", s.bucket, s.key)
+}
@@ -0,0 +1,293 @@
+package acme
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewStorage(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		storage          string
+		expected         string
+		expectedErrorMsg string
+	}{
+		{
+			desc:     "file",
+			storage:  "/etc/traefik/acme.json",
+			expected: "/etc/traefik/acme.json",
+		},
+		{
+			desc:     "S3 object",
+			storage:  "s3://certificates/traefik/acme.json",
+			expected: "s3://certificates/traefik/acme.json",
+		},
+		{
+			desc:             "S3 object without key",
+			storage:          "s3://certificates",
+			expectedErrorMsg: `invalid ACME storage "s3://certificates", expected s3://bucket/key`,
+		},
+		{
+			desc:             "Kubernetes Secret without namespace",
+			storage:          "kubernetes:///traefik-acme",
+			expectedErrorMsg: `invalid ACME storage "kubernetes:///traefik-acme", expected kubernetes://namespace/name`,
+		},
+		{
+			desc:             "Kubernetes Secret with invalid name",
+			storage:          "kubernetes://kube-system/traefik/acme",
+			expectedErrorMsg: `invalid ACME storage "kubernetes://kube-system/traefik/acme", expected kubernetes://namespace/name`,
+		},
+		{
+			desc:             "unknown type",
+			storage:          "consul://traefik/acme",
+			expectedErrorMsg: `unknown ACME storage type "consul"`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			storage, err := NewStorage(test.storage)
+			if len(test.expectedErrorMsg) > 0 {
+				assert.EqualError(t, err, test.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, storage.String())
+		})
+	}
+}
+
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "acme")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	storage := &fileStorage{file: filepath.Join(dir, "acme.json")}
+
+	data, err := storage.Read()
+	require.NoError(t, err)
+	assert.Empty(t, data, "nothing is stored before the first write")
+
+	require.NoError(t, storage.Write([]byte(`{"Email":"foo@example.com"}`)))
+	data, err = storage.Read()
+	require.NoError(t, err)
+	assert.Equal(t, `{"Email":"foo@example.com"}`, string(data))
+}
+
+func TestS3Storage(t *testing.T) {
+	var lock sync.Mutex
+	objects := make(map[string][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			len(req.Header.Get("X-Amz-Content-Sha256")) == 0 {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		switch req.Method {
+		case http.MethodGet:
+			object, ok := objects[req.URL.Path]
+			if !ok {
+				rw.WriteHeader(http.StatusNotFound)
+				rw.Write([]byte("<Error><Code>NoSuchKey</Code></Error>"))
+				return
+			}
+			rw.Write(object)
+		case http.MethodPut:
+			body, err := ioutil.ReadAll(req.Body)
+			require.NoError(t, err)
+			objects[req.URL.Path] = body
+		}
+	}))
+	defer server.Close()
+
+	storage := &s3Storage{
+		bucket:   "certificates",
+		key:      "traefik/acme.json",
+		endpoint: server.URL,
+		region:   defaultS3Region,
+		signer:   v4.NewSigner(credentials.NewStaticCredentials("AKID", "SECRET", "")),
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+
+	data, err := storage.Read()
+	require.NoError(t, err)
+	assert.Empty(t, data, "nothing is stored before the first write")
+
+	require.NoError(t, storage.Write([]byte(`{"Email":"foo@example.com"}`)))
+	assert.Equal(t, `{"Email":"foo@example.com"}`, string(objects["/certificates/traefik/acme.json"]))
+
+	data, err = storage.Read()
+	require.NoError(t, err)
+	assert.Equal(t, `{"Email":"foo@example.com"}`, string(data))
+
+	storage.signer = v4.NewSigner(credentials.NewStaticCredentials("OTHER", "SECRET", ""))
+	_, err = storage.Read()
+	assert.EqualError(t, err, "unable to read s3://certificates/traefik/acme.json: unexpected status code 403: ")
+}
+
+// fakeSecrets is an in-memory Secrets API, versioning the Secrets as the API server does.
+type fakeSecrets struct {
+	lock    sync.Mutex
+	secrets map[string]*corev1.Secret
+	version int
+}
+
+func (f *fakeSecrets) Get(name string, options metav1.GetOptions) (*corev1.Secret, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	secret, ok := f.secrets[name]
+	if !ok {
+		return nil, kubeerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}
+	return secret.DeepCopy(), nil
+}
+
+func (f *fakeSecrets) Create(secret *corev1.Secret) (*corev1.Secret, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if _, ok := f.secrets[secret.Name]; ok {
+		return nil, kubeerrors.NewAlreadyExists(schema.GroupResource{Resource: "secrets"}, secret.Name)
+	}
+	return f.store(secret), nil
+}
+
+func (f *fakeSecrets) Update(secret *corev1.Secret) (*corev1.Secret, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	current, ok := f.secrets[secret.Name]
+	if !ok {
+		return nil, kubeerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, secret.Name)
+	}
+	if current.ResourceVersion != secret.ResourceVersion {
+		return nil, kubeerrors.NewConflict(schema.GroupResource{Resource: "secrets"}, secret.Name, nil)
+	}
+	return f.store(secret), nil
+}
+
+func (f *fakeSecrets) store(secret *corev1.Secret) *corev1.Secret {
+	f.version++
+	stored := secret.DeepCopy()
+	stored.ResourceVersion = strconv.Itoa(f.version)
+	f.secrets[secret.Name] = stored
+	return stored.DeepCopy()
+}
+
+func TestKubernetesStorage(t *testing.T) {
+	secrets := &fakeSecrets{secrets: make(map[string]*corev1.Secret)}
+	storage := &kubernetesStorage{namespace: "kube-system", name: "traefik-acme", secrets: secrets}
+	other := &kubernetesStorage{namespace: "kube-system", name: "traefik-acme", secrets: secrets}
+
+	data, err := storage.Read()
+	require.NoError(t, err)
+	assert.Empty(t, data, "nothing is stored before the first write")
+
+	require.NoError(t, storage.Write([]byte(`{"Email":"foo@example.com"}`)))
+	require.Contains(t, secrets.secrets, "traefik-acme")
+	assert.Equal(t, `{"Email":"foo@example.com"}`, string(secrets.secrets["traefik-acme"].Data["acme.json"]))
+
+	// the writes update the Secret written or read last
+	require.NoError(t, storage.Write([]byte(`{"Email":"bar@example.com"}`)))
+	data, err = other.Read()
+	require.NoError(t, err)
+	assert.Equal(t, `{"Email":"bar@example.com"}`, string(data))
+	require.NoError(t, other.Write([]byte(`{"Email":"baz@example.com"}`)))
+
+	err = storage.Write([]byte(`{"Email":"foo@example.com"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to write kubernetes://kube-system/traefik-acme, modified by another instance")
+	assert.Equal(t, `{"Email":"baz@example.com"}`, string(secrets.secrets["traefik-acme"].Data["acme.json"]))
+}
+
+func TestStorageStore(t *testing.T) {
+	storage := &kubernetesStorage{
+		namespace: "kube-system",
+		name:      "traefik-acme",
+		secrets:   &fakeSecrets{secrets: make(map[string]*corev1.Secret)},
+	}
+	store := NewStorageStore(storage)
+
+	_, err := store.Load()
+	assert.EqualError(t, err, "no ACME account stored in kubernetes://kube-system/traefik-acme")
+
+	account := &Account{Email: "foo@example.com"}
+	require.NoError(t, account.Init())
+	transaction, _, err := store.Begin()
+	require.NoError(t, err)
+	require.NoError(t, transaction.Commit(account))
+
+	object, err := NewStorageStore(storage).Load()
+	require.NoError(t, err)
+	require.IsType(t, &Account{}, object)
+	assert.Equal(t, "foo@example.com", object.(*Account).Email)
+}
+
+func TestKubernetesStore(t *testing.T) {
+	secrets := &fakeSecrets{secrets: make(map[string]*corev1.Secret)}
+	store := newStore(&kubernetesStorage{namespace: "kube-system", name: "traefik-acme", secrets: secrets})
+	other := newStore(&kubernetesStorage{namespace: "kube-system", name: "traefik-acme", secrets: secrets})
+	require.IsType(t, &kubernetesStore{}, store)
+
+	_, err := store.Load()
+	assert.EqualError(t, err, "no ACME account stored in kubernetes://kube-system/traefik-acme")
+
+	account := &Account{Email: "foo@example.com"}
+	require.NoError(t, account.Init())
+	transaction, _, err := store.Begin()
+	require.NoError(t, err)
+	require.NoError(t, transaction.Commit(account))
+
+	object, err := other.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "foo@example.com", object.(*Account).Email)
+
+	// the transaction began before the other instance committed its own one fails, without overwriting its changes
+	transaction, _, err = store.Begin()
+	require.NoError(t, err)
+
+	otherTransaction, object, err := other.Begin()
+	require.NoError(t, err)
+	object.(*Account).Email = "bar@example.com"
+	require.NoError(t, otherTransaction.Commit(object))
+
+	err = transaction.Commit(&Account{Email: "baz@example.com"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "modified by another instance")
+	assert.Equal(t, "bar@example.com", store.Get().(*Account).Email, "Secret not read again")
+
+	// the next transaction changes the account of the other instance
+	transaction, object, err = store.Begin()
+	require.NoError(t, err)
+	assert.Equal(t, "bar@example.com", object.(*Account).Email)
+	object.(*Account).Email = "baz@example.com"
+	require.NoError(t, transaction.Commit(object))
+
+	object, err = other.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "baz@example.com", object.(*Account).Email)
+}
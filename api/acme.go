@@ -0,0 +1,15 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/containous/traefik/acme"
+	"github.com/containous/traefik/log"
+)
+
+func getACMERenewalsHandler(response http.ResponseWriter, request *http.Request) {
+	err := templatesRenderer.JSON(response, http.StatusOK, acme.RenewalStatuses())
+	if err != nil {
+		log.Error(err)
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/containous/mux"
+	"github.com/containous/traefik/audit"
+	"github.com/containous/traefik/canary"
+	"github.com/containous/traefik/log"
+)
+
+func getCanariesHandler(response http.ResponseWriter, request *http.Request) {
+	err := templatesRenderer.JSON(response, http.StatusOK, canary.GetController().Statuses())
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// newCanaryActionHandler creates a handler applying an action to the canary release of the frontend of the request.
+func newCanaryActionHandler(action func(c *canary.Controller, frontend string) (canary.Status, error)) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		status, err := action(canary.GetController(), mux.Vars(request)["frontend"])
+
+		event := audit.Event{
+			Type:       audit.APIMutation,
+			RemoteAddr: request.RemoteAddr,
+			Method:     request.Method,
+			Path:       request.URL.Path,
+		}
+		if err != nil {
+			event.Error = err.Error()
+		}
+		audit.Record(event)
+
+		if err == canary.ErrUnknownRelease {
+			http.NotFound(response, request)
+			return
+		}
+		if err != nil {
+			http.Error(response, err.Error(), http.StatusConflict)
+			return
+		}
+
+		err = templatesRenderer.JSON(response, http.StatusOK, status)
+		if err != nil {
+			log.Error(err)
+		}
+	}
+}
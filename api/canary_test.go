@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/mux"
+	"github.com/containous/traefik/canary"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanaryHandlers(t *testing.T) {
+	release, err := canary.NewRelease("frontend", types.Canary{Backend: "canary", StepInterval: "1h"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	canary.GetController().SetReleases(ctx, map[string]*canary.Release{"frontend": release})
+	defer canary.GetController().SetReleases(ctx, nil)
+
+	router := mux.NewRouter()
+	Handler{}.AddRoutes(router)
+
+	testCases := []struct {
+		desc           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedState  string
+	}{
+		{
+			desc:           "pause",
+			method:         http.MethodPost,
+			path:           "/api/canaries/frontend/pause",
+			expectedStatus: http.StatusOK,
+			expectedState:  canary.StatusPaused,
+		},
+		{
+			desc:           "pause again",
+			method:         http.MethodPost,
+			path:           "/api/canaries/frontend/pause",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			desc:           "resume",
+			method:         http.MethodPost,
+			path:           "/api/canaries/frontend/resume",
+			expectedStatus: http.StatusOK,
+			expectedState:  canary.StatusProgressing,
+		},
+		{
+			desc:           "rollback",
+			method:         http.MethodPost,
+			path:           "/api/canaries/frontend/rollback",
+			expectedStatus: http.StatusOK,
+			expectedState:  canary.StatusRolledBack,
+		},
+		{
+			desc:           "promote",
+			method:         http.MethodPost,
+			path:           "/api/canaries/frontend/promote",
+			expectedStatus: http.StatusOK,
+			expectedState:  canary.StatusPromoted,
+		},
+		{
+			desc:           "unknown frontend",
+			method:         http.MethodPost,
+			path:           "/api/canaries/unknown/promote",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	// the actions are applied in sequence on the same release
+	for _, test := range testCases {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(test.method, "http://localhost"+test.path, nil))
+		require.Equal(t, test.expectedStatus, recorder.Code, test.desc)
+
+		if len(test.expectedState) > 0 {
+			status := canary.Status{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status), test.desc)
+			assert.Equal(t, "frontend", status.Frontend, test.desc)
+			assert.Equal(t, test.expectedState, status.Status, test.desc)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/api/canaries", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var statuses []canary.Status
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &statuses))
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "canary", statuses[0].Backend)
+	assert.Equal(t, canary.StatusPromoted, statuses[0].Status)
+	assert.Equal(t, 100, statuses[0].Weight)
+}
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/containous/traefik/drain"
+	"github.com/containous/traefik/log"
+)
+
+func getDrainHandler(response http.ResponseWriter, request *http.Request) {
+	err := templatesRenderer.JSON(response, http.StatusOK, drain.GetRegistry().Statuses())
+	if err != nil {
+		log.Error(err)
+	}
+}
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/provider/file"
+)
+
+func getFileErrorsHandler(response http.ResponseWriter, request *http.Request) {
+	err := templatesRenderer.JSON(response, http.StatusOK, file.ParseErrors())
+	if err != nil {
+		log.Error(err)
+	}
+}
@@ -4,11 +4,17 @@ import (
 	"net/http"
 
 	"github.com/containous/mux"
+	"github.com/containous/traefik/acme"
+	"github.com/containous/traefik/canary"
+	"github.com/containous/traefik/drain"
 	"github.com/containous/traefik/log"
 	"github.com/containous/traefik/middlewares"
+	"github.com/containous/traefik/provider/file"
+	"github.com/containous/traefik/provider/status"
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/types"
 	"github.com/containous/traefik/version"
+	"github.com/containous/traefik/whitelist"
 	thoas_stats "github.com/thoas/stats"
 	"github.com/unrolled/render"
 )
@@ -19,9 +25,13 @@ type Handler struct {
 	Dashboard             bool   `description:"Activate dashboard" export:"true"`
 	Debug                 bool   `export:"true"`
 	CurrentConfigurations *safe.Safe
+	ProviderConflicts     *safe.Safe                 `json:"-"`
 	Statistics            *types.Statistics          `description:"Enable more detailed statistics" export:"true"`
 	Stats                 *thoas_stats.Stats         `json:"-"`
 	StatsRecorder         *middlewares.StatsRecorder `json:"-"`
+	RBAC                  *RBAC                      `description:"Role-based access control of the internal routes" export:"true"`
+	SwaggerUI             bool                       `description:"Serve a documentation page of the OpenAPI specification under /api/docs" export:"true"`
+	TrustedIPs            *whitelist.IP              `json:"-"`
 }
 
 var (
@@ -36,26 +46,71 @@ func (p Handler) AddRoutes(router *mux.Router) {
 		DebugHandler{}.AddRoutes(router)
 	}
 
-	router.Methods(http.MethodGet).Path("/api").HandlerFunc(p.getConfigHandler)
-	router.Methods(http.MethodGet).Path("/api/providers").HandlerFunc(p.getConfigHandler)
-	router.Methods(http.MethodGet).Path("/api/providers/{provider}").HandlerFunc(p.getProviderHandler)
-	router.Methods(http.MethodGet).Path("/api/providers/{provider}/backends").HandlerFunc(p.getBackendsHandler)
-	router.Methods(http.MethodGet).Path("/api/providers/{provider}/backends/{backend}").HandlerFunc(p.getBackendHandler)
-	router.Methods(http.MethodGet).Path("/api/providers/{provider}/backends/{backend}/servers").HandlerFunc(p.getServersHandler)
-	router.Methods(http.MethodGet).Path("/api/providers/{provider}/backends/{backend}/servers/{server}").HandlerFunc(p.getServerHandler)
-	router.Methods(http.MethodGet).Path("/api/providers/{provider}/frontends").HandlerFunc(p.getFrontendsHandler)
-	router.Methods(http.MethodGet).Path("/api/providers/{provider}/frontends/{frontend}").HandlerFunc(p.getFrontendHandler)
-	router.Methods(http.MethodGet).Path("/api/providers/{provider}/frontends/{frontend}/routes").HandlerFunc(p.getRoutesHandler)
-	router.Methods(http.MethodGet).Path("/api/providers/{provider}/frontends/{frontend}/routes/{route}").HandlerFunc(p.getRouteHandler)
-
-	// health route
-	router.Methods(http.MethodGet).Path("/health").HandlerFunc(p.getHealthHandler)
+	for _, route := range p.apiRoutes() {
+		if route.handler != nil {
+			router.Methods(route.method).Path(route.path).HandlerFunc(route.handler)
+		}
+	}
 
 	version.Handler{}.AddRoutes(router)
 
 	if p.Dashboard {
 		DashboardHandler{}.AddRoutes(router)
 	}
+
+	if p.SwaggerUI {
+		router.Methods(http.MethodGet).Path("/api/docs").HandlerFunc(getSwaggerUIHandler)
+	}
+}
+
+// apiRoute describes a route of the API, used both to register it and to document it in the OpenAPI specification.
+type apiRoute struct {
+	method   string
+	path     string
+	summary  string
+	request  interface{}
+	response interface{}
+	handler  http.HandlerFunc
+}
+
+// apiRoutes returns the routes of the API, the routes without handler being registered by other handlers.
+func (p Handler) apiRoutes() []apiRoute {
+	return []apiRoute{
+		{method: http.MethodGet, path: "/api", summary: "Get the configurations of all the providers", response: types.Configurations{}, handler: p.getConfigHandler},
+		{method: http.MethodGet, path: "/api/providers", summary: "Get the configurations of all the providers", response: types.Configurations{}, handler: p.getConfigHandler},
+		{method: http.MethodGet, path: "/api/providers/{provider}", summary: "Get the configuration of a provider", response: &types.Configuration{}, handler: p.getProviderHandler},
+		{method: http.MethodPut, path: "/api/providers/{provider}", summary: "Update the configuration of the rest provider, when enabled", response: &types.Configuration{}, request: &types.Configuration{}},
+		{method: http.MethodGet, path: "/api/providers/{provider}/status", summary: "Get the synchronization state of a provider", response: status.Status{}, handler: getProviderStatusHandler},
+		{method: http.MethodGet, path: "/api/providers/{provider}/backends", summary: "List the backends of a provider", response: map[string]*types.Backend{}, handler: p.getBackendsHandler},
+		{method: http.MethodGet, path: "/api/providers/{provider}/backends/{backend}", summary: "Get a backend", response: &types.Backend{}, handler: p.getBackendHandler},
+		{method: http.MethodGet, path: "/api/providers/{provider}/backends/{backend}/servers", summary: "List the servers of a backend", response: map[string]types.Server{}, handler: p.getServersHandler},
+		{method: http.MethodGet, path: "/api/providers/{provider}/backends/{backend}/servers/{server}", summary: "Get a server of a backend", response: types.Server{}, handler: p.getServerHandler},
+		{method: http.MethodGet, path: "/api/providers/file/errors", summary: "List the configuration files skipped by the file provider because they could not be parsed", response: []file.ParseError{}, handler: getFileErrorsHandler},
+		{method: http.MethodGet, path: "/api/providers/{provider}/frontends", summary: "List the frontends of a provider", response: map[string]*types.Frontend{}, handler: p.getFrontendsHandler},
+		{method: http.MethodGet, path: "/api/providers/{provider}/frontends/{frontend}", summary: "Get a frontend", response: &types.Frontend{}, handler: p.getFrontendHandler},
+		{method: http.MethodGet, path: "/api/providers/{provider}/frontends/{frontend}/routes", summary: "List the routes of a frontend", response: map[string]types.Route{}, handler: p.getRoutesHandler},
+		{method: http.MethodGet, path: "/api/providers/{provider}/frontends/{frontend}/routes/{route}", summary: "Get a route of a frontend", response: types.Route{}, handler: p.getRouteHandler},
+		{method: http.MethodGet, path: "/api/conflicts", summary: "List the frontends defined by several providers", response: []types.ProviderConflict{}, handler: p.getConflictsHandler},
+		{method: http.MethodGet, path: "/api/canaries", summary: "List the canary releases of the frontends", response: []canary.Status{}, handler: getCanariesHandler},
+		{method: http.MethodPost, path: "/api/canaries/{frontend}/pause", summary: "Pause the canary release of a frontend at its current weight", response: canary.Status{}, handler: newCanaryActionHandler((*canary.Controller).Pause)},
+		{method: http.MethodPost, path: "/api/canaries/{frontend}/resume", summary: "Resume the canary release of a frontend", response: canary.Status{}, handler: newCanaryActionHandler((*canary.Controller).Resume)},
+		{method: http.MethodPost, path: "/api/canaries/{frontend}/rollback", summary: "Send all the traffic of a frontend back to its backend", response: canary.Status{}, handler: newCanaryActionHandler((*canary.Controller).Rollback)},
+		{method: http.MethodPost, path: "/api/canaries/{frontend}/promote", summary: "Send all the traffic of a frontend to its canary backend", response: canary.Status{}, handler: newCanaryActionHandler((*canary.Controller).Promote)},
+		{method: http.MethodGet, path: "/api/drain", summary: "Get the drain progress of the entrypoints on shutdown", response: []drain.Status{}, handler: getDrainHandler},
+		{method: http.MethodGet, path: "/api/acme/renewals", summary: "Get the renewal state of the ACME certificates", response: []acme.RenewalStatus{}, handler: getACMERenewalsHandler},
+		{method: http.MethodGet, path: "/api/version", summary: "Get the version of Traefik", response: struct{ Version, Codename string }{}},
+		{method: http.MethodGet, path: "/api/openapi.json", summary: "Get the OpenAPI specification of the API", response: map[string]interface{}{}, handler: p.getOpenAPIHandler},
+		{method: http.MethodGet, path: "/health", summary: "Get the health metrics", response: &healthResponse{}, handler: p.getHealthHandler},
+	}
+}
+
+// getCurrentConfigurations returns the current configurations, without their secrets if the request is not allowed to read them.
+func (p Handler) getCurrentConfigurations(request *http.Request) types.Configurations {
+	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
+	if canReadSecrets(request) {
+		return currentConfigurations
+	}
+	return redactConfigurations(currentConfigurations)
 }
 
 func getProviderIDFromVars(vars map[string]string) string {
@@ -68,7 +123,7 @@ func getProviderIDFromVars(vars map[string]string) string {
 }
 
 func (p Handler) getConfigHandler(response http.ResponseWriter, request *http.Request) {
-	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
+	currentConfigurations := p.getCurrentConfigurations(request)
 	err := templatesRenderer.JSON(response, http.StatusOK, currentConfigurations)
 	if err != nil {
 		log.Error(err)
@@ -78,7 +133,7 @@ func (p Handler) getConfigHandler(response http.ResponseWriter, request *http.Re
 func (p Handler) getProviderHandler(response http.ResponseWriter, request *http.Request) {
 	providerID := getProviderIDFromVars(mux.Vars(request))
 
-	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
+	currentConfigurations := p.getCurrentConfigurations(request)
 	if provider, ok := currentConfigurations[providerID]; ok {
 		err := templatesRenderer.JSON(response, http.StatusOK, provider)
 		if err != nil {
@@ -89,10 +144,25 @@ func (p Handler) getProviderHandler(response http.ResponseWriter, request *http.
 	}
 }
 
+func getProviderStatusHandler(response http.ResponseWriter, request *http.Request) {
+	providerID := getProviderIDFromVars(mux.Vars(request))
+
+	providerStatus, ok := status.Get(providerID)
+	if !ok {
+		http.NotFound(response, request)
+		return
+	}
+
+	err := templatesRenderer.JSON(response, http.StatusOK, providerStatus)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
 func (p Handler) getBackendsHandler(response http.ResponseWriter, request *http.Request) {
 	providerID := getProviderIDFromVars(mux.Vars(request))
 
-	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
+	currentConfigurations := p.getCurrentConfigurations(request)
 	if provider, ok := currentConfigurations[providerID]; ok {
 		err := templatesRenderer.JSON(response, http.StatusOK, provider.Backends)
 		if err != nil {
@@ -108,7 +178,7 @@ func (p Handler) getBackendHandler(response http.ResponseWriter, request *http.R
 	providerID := getProviderIDFromVars(vars)
 	backendID := vars["backend"]
 
-	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
+	currentConfigurations := p.getCurrentConfigurations(request)
 	if provider, ok := currentConfigurations[providerID]; ok {
 		if backend, ok := provider.Backends[backendID]; ok {
 			err := templatesRenderer.JSON(response, http.StatusOK, backend)
@@ -126,7 +196,7 @@ func (p Handler) getServersHandler(response http.ResponseWriter, request *http.R
 	providerID := getProviderIDFromVars(vars)
 	backendID := vars["backend"]
 
-	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
+	currentConfigurations := p.getCurrentConfigurations(request)
 	if provider, ok := currentConfigurations[providerID]; ok {
 		if backend, ok := provider.Backends[backendID]; ok {
 			err := templatesRenderer.JSON(response, http.StatusOK, backend.Servers)
@@ -145,7 +215,7 @@ func (p Handler) getServerHandler(response http.ResponseWriter, request *http.Re
 	backendID := vars["backend"]
 	serverID := vars["server"]
 
-	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
+	currentConfigurations := p.getCurrentConfigurations(request)
 	if provider, ok := currentConfigurations[providerID]; ok {
 		if backend, ok := provider.Backends[backendID]; ok {
 			if server, ok := backend.Servers[serverID]; ok {
@@ -163,7 +233,7 @@ func (p Handler) getServerHandler(response http.ResponseWriter, request *http.Re
 func (p Handler) getFrontendsHandler(response http.ResponseWriter, request *http.Request) {
 	providerID := getProviderIDFromVars(mux.Vars(request))
 
-	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
+	currentConfigurations := p.getCurrentConfigurations(request)
 	if provider, ok := currentConfigurations[providerID]; ok {
 		err := templatesRenderer.JSON(response, http.StatusOK, provider.Frontends)
 		if err != nil {
@@ -179,7 +249,7 @@ func (p Handler) getFrontendHandler(response http.ResponseWriter, request *http.
 	providerID := getProviderIDFromVars(vars)
 	frontendID := vars["frontend"]
 
-	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
+	currentConfigurations := p.getCurrentConfigurations(request)
 	if provider, ok := currentConfigurations[providerID]; ok {
 		if frontend, ok := provider.Frontends[frontendID]; ok {
 			err := templatesRenderer.JSON(response, http.StatusOK, frontend)
@@ -197,7 +267,7 @@ func (p Handler) getRoutesHandler(response http.ResponseWriter, request *http.Re
 	providerID := getProviderIDFromVars(vars)
 	frontendID := vars["frontend"]
 
-	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
+	currentConfigurations := p.getCurrentConfigurations(request)
 	if provider, ok := currentConfigurations[providerID]; ok {
 		if frontend, ok := provider.Frontends[frontendID]; ok {
 			err := templatesRenderer.JSON(response, http.StatusOK, frontend.Routes)
@@ -216,7 +286,7 @@ func (p Handler) getRouteHandler(response http.ResponseWriter, request *http.Req
 	frontendID := vars["frontend"]
 	routeID := vars["route"]
 
-	currentConfigurations := p.CurrentConfigurations.Get().(types.Configurations)
+	currentConfigurations := p.getCurrentConfigurations(request)
 	if provider, ok := currentConfigurations[providerID]; ok {
 		if frontend, ok := provider.Frontends[frontendID]; ok {
 			if route, ok := frontend.Routes[routeID]; ok {
@@ -248,3 +318,17 @@ func (p *Handler) getHealthHandler(response http.ResponseWriter, request *http.R
 		log.Error(err)
 	}
 }
+
+func (p Handler) getConflictsHandler(response http.ResponseWriter, request *http.Request) {
+	conflicts := make([]types.ProviderConflict, 0)
+	if p.ProviderConflicts != nil {
+		if current, ok := p.ProviderConflicts.Get().([]types.ProviderConflict); ok && current != nil {
+			conflicts = current
+		}
+	}
+
+	err := templatesRenderer.JSON(response, http.StatusOK, conflicts)
+	if err != nil {
+		log.Error(err)
+	}
+}
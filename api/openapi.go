@@ -0,0 +1,280 @@
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/version"
+)
+
+var pathParameterRegexp = regexp.MustCompile(`{([^}]+)}`)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+func (p Handler) getOpenAPIHandler(response http.ResponseWriter, request *http.Request) {
+	basePath := strings.TrimSuffix(request.URL.Path, "/api/openapi.json")
+	if p.isTrustedProxy(request) {
+		basePath = request.Header.Get("X-Forwarded-Prefix") + basePath
+	}
+	err := templatesRenderer.JSON(response, http.StatusOK, buildOpenAPISpecification(p.apiRoutes(), basePath))
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// isTrustedProxy checks whether the request comes from a trusted IP of the forwarded headers of the entrypoint,
+// its X-Forwarded-Prefix header being ignored otherwise.
+func (p Handler) isTrustedProxy(request *http.Request) bool {
+	if p.TrustedIPs == nil {
+		return false
+	}
+
+	clientIP, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	trusted, _, err := p.TrustedIPs.Contains(clientIP)
+	return err == nil && trusted
+}
+
+// buildOpenAPISpecification generates the OpenAPI 3 document of the routes, the schemas being generated from the Go types
+// of their requests and responses as they are encoded to JSON.
+func buildOpenAPISpecification(routes []apiRoute, basePath string) map[string]interface{} {
+	generator := &schemaGenerator{components: make(map[string]interface{})}
+
+	paths := make(map[string]interface{})
+	for _, route := range routes {
+		operations, ok := paths[route.path].(map[string]interface{})
+		if !ok {
+			operations = make(map[string]interface{})
+			paths[route.path] = operations
+		}
+
+		operation := map[string]interface{}{
+			"summary": route.summary,
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "OK",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": generator.schema(reflect.TypeOf(route.response))},
+					},
+				},
+				"404": map[string]interface{}{"description": "Not found"},
+			},
+		}
+
+		var parameters []interface{}
+		for _, match := range pathParameterRegexp.FindAllStringSubmatch(route.path, -1) {
+			parameters = append(parameters, map[string]interface{}{
+				"name":     match[1],
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]interface{}{"type": "string"},
+			})
+		}
+		if len(parameters) > 0 {
+			operation["parameters"] = parameters
+		}
+
+		if route.request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": generator.schema(reflect.TypeOf(route.request))},
+				},
+			}
+		}
+
+		operations[strings.ToLower(route.method)] = operation
+	}
+
+	if len(basePath) == 0 {
+		basePath = "/"
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":   "Traefik API",
+			"version": version.Version,
+		},
+		"servers":    []interface{}{map[string]interface{}{"url": basePath}},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": generator.components},
+	}
+}
+
+// schemaGenerator generates the JSON schemas of Go types, the named structs being referenced as components.
+type schemaGenerator struct {
+	components map[string]interface{}
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return map[string]interface{}{}
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if len(t.Name()) == 0 {
+			return g.objectSchema(t)
+		}
+
+		name := fmt.Sprintf("%s.%s", path.Base(t.PkgPath()), t.Name())
+		if _, ok := g.components[name]; !ok {
+			// registered before being generated, for the recursive types
+			g.components[name] = nil
+			g.components[name] = g.objectSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func (g *schemaGenerator) objectSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	g.addProperties(t, properties)
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+// addProperties adds the properties of the exported fields of a struct, along with the ones of its embedded structs.
+func (g *schemaGenerator) addProperties(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && len(name) == 0 && fieldType.Kind() == reflect.Struct &&
+			!fieldType.Implements(jsonMarshalerType) && !reflect.PtrTo(fieldType).Implements(jsonMarshalerType) {
+			g.addProperties(fieldType, properties)
+			continue
+		}
+
+		if len(field.PkgPath) > 0 {
+			// unexported field
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		properties[name] = g.schema(field.Type)
+	}
+}
+
+// swaggerUIPage renders the OpenAPI specification without any asset loaded from another origin,
+// for the page to work without Internet access.
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Traefik API</title>
+  <style>
+    body { font-family: sans-serif; margin: 2em; color: #333; }
+    .operation { border: 1px solid #ddd; border-radius: 4px; margin: 0.5em 0; padding: 0.5em 1em; }
+    .method { display: inline-block; min-width: 4em; font-weight: bold; text-transform: uppercase; }
+    .get { color: #2f8132; } .put { color: #c5862b; } .post { color: #186fcc; } .delete { color: #cf3030; }
+    .path { font-family: monospace; font-size: 1.1em; }
+    pre { background: #f6f8fa; padding: 0.5em; overflow: auto; }
+  </style>
+</head>
+<body>
+  <h1>Traefik API <small id="version"></small></h1>
+  <div id="operations"></div>
+  <h2>Schemas</h2>
+  <div id="schemas"></div>
+  <script>
+    function element(name, text, className) {
+      var e = document.createElement(name);
+      if (text) { e.textContent = text; }
+      if (className) { e.className = className; }
+      return e;
+    }
+
+    function details(summary, value) {
+      var d = element("details");
+      d.appendChild(element("summary", summary));
+      d.appendChild(element("pre", JSON.stringify(value, null, 2)));
+      return d;
+    }
+
+    fetch("openapi.json").then(function (response) { return response.json(); }).then(function (spec) {
+      document.getElementById("version").textContent = spec.info.version;
+
+      var operations = document.getElementById("operations");
+      Object.keys(spec.paths).sort().forEach(function (path) {
+        Object.keys(spec.paths[path]).forEach(function (method) {
+          var operation = spec.paths[path][method];
+          var div = element("div", null, "operation");
+          div.appendChild(element("span", method, "method " + method));
+          div.appendChild(element("span", spec.servers[0].url.replace(/\/$/, "") + path, "path"));
+          div.appendChild(element("p", operation.summary));
+          if (operation.parameters) { div.appendChild(details("Parameters", operation.parameters)); }
+          if (operation.requestBody) { div.appendChild(details("Request", operation.requestBody.content["application/json"].schema)); }
+          div.appendChild(details("Response", operation.responses["200"].content["application/json"].schema));
+          operations.appendChild(div);
+        });
+      });
+
+      var schemas = document.getElementById("schemas");
+      Object.keys(spec.components.schemas).sort().forEach(function (name) {
+        schemas.appendChild(details(name, spec.components.schemas[name]));
+      });
+    });
+  </script>
+</body>
+</html>
+`
+
+func getSwaggerUIHandler(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "text/html; charset=utf-8")
+	response.Header().Set("Content-Security-Policy", "default-src 'none'; connect-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'")
+	fmt.Fprint(response, swaggerUIPage)
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/containous/mux"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+	"github.com/containous/traefik/whitelist"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIHandler(t *testing.T) {
+	currentConfigurations := &safe.Safe{}
+	currentConfigurations.Set(types.Configurations{})
+
+	router := mux.NewRouter()
+	Handler{CurrentConfigurations: currentConfigurations}.AddRoutes(router.PathPrefix("/traefik").Subrouter())
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/traefik/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	spec := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &spec))
+
+	assert.Equal(t, "3.0.0", spec["openapi"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"url": "/traefik"}}, spec["servers"])
+
+	paths := spec["paths"].(map[string]interface{})
+	for _, route := range (Handler{}).apiRoutes() {
+		require.Contains(t, paths, route.path)
+		assert.Contains(t, paths[route.path], strings.ToLower(route.method), route.path)
+	}
+
+	getServer := paths["/api/providers/{provider}/backends/{backend}/servers/{server}"].(map[string]interface{})["get"].(map[string]interface{})
+	var parameterNames []string
+	for _, parameter := range getServer["parameters"].([]interface{}) {
+		parameterNames = append(parameterNames, parameter.(map[string]interface{})["name"].(string))
+	}
+	assert.Equal(t, []string{"provider", "backend", "server"}, parameterNames)
+
+	putProvider := paths["/api/providers/{provider}"].(map[string]interface{})["put"].(map[string]interface{})
+	assert.Contains(t, putProvider, "requestBody")
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	assertRefsResolved(t, spec, schemas)
+
+	frontend := schemas["types.Frontend"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}, frontend["entryPoints"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/types.Headers"}, frontend["headers"])
+
+	configuration := schemas["types.Configuration"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/components/schemas/tls.Configuration"}}, configuration["tls"])
+
+	health := schemas["api.healthResponse"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Contains(t, health, "pid")
+	assert.Contains(t, health, "total_status_code_count")
+}
+
+func TestOpenAPIHandlerForwardedPrefix(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		trustedIPs       []string
+		expectedBasePath string
+	}{
+		{
+			desc:             "trusted proxy",
+			trustedIPs:       []string{"192.0.2.0/24"},
+			expectedBasePath: "/gateway/traefik",
+		},
+		{
+			desc:             "untrusted proxy",
+			trustedIPs:       []string{"10.0.0.0/8"},
+			expectedBasePath: "/traefik",
+		},
+		{
+			desc:             "no trusted proxy",
+			expectedBasePath: "/traefik",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := Handler{}
+			if len(test.trustedIPs) > 0 {
+				trustedIPs, err := whitelist.NewIP(test.trustedIPs, false)
+				require.NoError(t, err)
+				handler.TrustedIPs = trustedIPs
+			}
+
+			router := mux.NewRouter()
+			handler.AddRoutes(router.PathPrefix("/traefik").Subrouter())
+
+			request := httptest.NewRequest(http.MethodGet, "http://localhost/traefik/api/openapi.json", nil)
+			request.RemoteAddr = "192.0.2.1:1234"
+			request.Header.Set("X-Forwarded-Prefix", "/gateway")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			spec := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &spec))
+			assert.Equal(t, []interface{}{map[string]interface{}{"url": test.expectedBasePath}}, spec["servers"])
+		})
+	}
+}
+
+func TestSwaggerUI(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		swaggerUI      bool
+		expectedStatus int
+	}{
+		{
+			desc:           "enabled",
+			swaggerUI:      true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "disabled",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router := mux.NewRouter()
+			Handler{SwaggerUI: test.swaggerUI}.AddRoutes(router)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/api/docs", nil))
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			if test.swaggerUI {
+				assert.NotContains(t, recorder.Body.String(), "https://")
+				assert.Contains(t, recorder.Header().Get("Content-Security-Policy"), "default-src 'none'")
+			}
+		})
+	}
+}
+
+// assertRefsResolved checks that all the references of the value are defined schemas.
+func assertRefsResolved(t *testing.T, value interface{}, schemas map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if key == "$ref" {
+				name := strings.TrimPrefix(child.(string), "#/components/schemas/")
+				assert.NotNil(t, schemas[name], "undefined schema %s", name)
+				continue
+			}
+			assertRefsResolved(t, child, schemas)
+		}
+	case []interface{}:
+		for _, child := range v {
+			assertRefsResolved(t, child, schemas)
+		}
+	}
+}
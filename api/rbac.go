@@ -0,0 +1,220 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/containous/traefik/log"
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/urfave/negroni"
+)
+
+// RBAC roles, each one granting the permissions of the previous ones.
+const (
+	// RoleViewer can read the configuration, the health and the metrics, without the secrets.
+	RoleViewer = "viewer"
+	// RoleOperator can also change the configuration through the REST API.
+	RoleOperator = "operator"
+	// RoleAdmin can also read the secrets of the configuration and the debug endpoints.
+	RoleAdmin = "admin"
+)
+
+const redactedSecret = "<redacted>"
+
+var roleLevels = map[string]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+type roleKey struct{}
+
+// RBAC maps the identities of the API clients to roles.
+// An identity is written as user:<basic auth user>, header:<identity header value>,
+// cn:<client certificate common name> or ou:<client certificate organizational unit>.
+type RBAC struct {
+	Viewers        []string `description:"Identities having the viewer role" export:"true"`
+	Operators      []string `description:"Identities having the operator role" export:"true"`
+	Admins         []string `description:"Identities having the admin role" export:"true"`
+	IdentityHeader string   `description:"Request header holding the identity set by the forward authentication" export:"true"`
+}
+
+// NewRBACMiddleware creates a middleware granting access to the internal routes under the base path according to the RBAC roles.
+func NewRBACMiddleware(rbac *RBAC, basePath string) negroni.Handler {
+	roles := make(map[string]string)
+	for role, identities := range map[string][]string{RoleViewer: rbac.Viewers, RoleOperator: rbac.Operators, RoleAdmin: rbac.Admins} {
+		for _, identity := range identities {
+			if roleLevels[role] > roleLevels[roles[identity]] {
+				roles[identity] = role
+			}
+		}
+	}
+
+	return negroni.HandlerFunc(func(rw http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+		role := resolveRole(roles, req, rbac.IdentityHeader)
+		required := requiredRole(req, basePath)
+		if roleLevels[role] < roleLevels[required] {
+			log.Debugf("RBAC: %s %s requires the %s role", req.Method, req.URL.Path, required)
+			http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), roleKey{}, role)))
+	})
+}
+
+// requiredRole returns the role required by a request: operator to mutate, admin for the debug endpoints, viewer otherwise.
+func requiredRole(req *http.Request, basePath string) string {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return RoleOperator
+	}
+
+	if strings.HasPrefix(strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(basePath, "/")), "/debug/") {
+		return RoleAdmin
+	}
+	return RoleViewer
+}
+
+// resolveRole returns the highest role of the identities of the request.
+func resolveRole(roles map[string]string, req *http.Request, identityHeader string) string {
+	var identities []string
+	if user, _, ok := req.BasicAuth(); ok {
+		identities = append(identities, "user:"+user)
+	}
+	if len(identityHeader) > 0 {
+		if value := req.Header.Get(identityHeader); len(value) > 0 {
+			identities = append(identities, "header:"+value)
+		}
+	}
+	if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+		subject := req.TLS.PeerCertificates[0].Subject
+		identities = append(identities, "cn:"+subject.CommonName)
+		for _, ou := range subject.OrganizationalUnit {
+			identities = append(identities, "ou:"+ou)
+		}
+	}
+
+	var role string
+	for _, identity := range identities {
+		if r, ok := roles[identity]; ok && roleLevels[r] > roleLevels[role] {
+			role = r
+		}
+	}
+	return role
+}
+
+// canReadSecrets reports whether the request is allowed to read the secrets of the configuration,
+// which is the case when RBAC is disabled.
+func canReadSecrets(req *http.Request) bool {
+	role, ok := req.Context().Value(roleKey{}).(string)
+	return !ok || role == RoleAdmin
+}
+
+// redactConfigurations returns copies of the configurations without the basic auth password hashes, the TLS private keys,
+// the passwords of the server and proxy URLs, and the headers of the health check probes.
+func redactConfigurations(configurations types.Configurations) types.Configurations {
+	redacted := make(types.Configurations, len(configurations))
+	for providerID, configuration := range configurations {
+		redacted[providerID] = redactConfiguration(configuration)
+	}
+	return redacted
+}
+
+func redactConfiguration(configuration *types.Configuration) *types.Configuration {
+	if configuration == nil {
+		return nil
+	}
+	redacted := *configuration
+
+	redacted.Frontends = make(map[string]*types.Frontend, len(configuration.Frontends))
+	for frontendID, frontend := range configuration.Frontends {
+		redacted.Frontends[frontendID] = redactFrontend(frontend)
+	}
+
+	redacted.Backends = make(map[string]*types.Backend, len(configuration.Backends))
+	for backendID, backend := range configuration.Backends {
+		redacted.Backends[backendID] = redactBackend(backend)
+	}
+
+	redacted.TLS = nil
+	for _, tlsConfiguration := range configuration.TLS {
+		if tlsConfiguration != nil && tlsConfiguration.Certificate != nil {
+			certificate := *tlsConfiguration.Certificate
+			certificate.KeyFile = redactedSecret
+			tlsConfiguration = &traefikTls.Configuration{EntryPoints: tlsConfiguration.EntryPoints, Certificate: &certificate}
+		}
+		redacted.TLS = append(redacted.TLS, tlsConfiguration)
+	}
+	return &redacted
+}
+
+func redactFrontend(frontend *types.Frontend) *types.Frontend {
+	if frontend == nil || len(frontend.BasicAuth) == 0 {
+		return frontend
+	}
+	redacted := *frontend
+	redacted.BasicAuth = make([]string, len(frontend.BasicAuth))
+	for i, user := range frontend.BasicAuth {
+		redacted.BasicAuth[i] = strings.SplitN(user, ":", 2)[0] + ":" + redactedSecret
+	}
+	return &redacted
+}
+
+func redactBackend(backend *types.Backend) *types.Backend {
+	if backend == nil {
+		return nil
+	}
+	redacted := *backend
+
+	redacted.Servers = make(map[string]types.Server, len(backend.Servers))
+	for serverID, server := range backend.Servers {
+		server.URL = redactURL(server.URL)
+		redacted.Servers[serverID] = server
+	}
+
+	if backend.Proxy != nil {
+		redacted.Proxy = &types.OutboundProxy{URL: redactURL(backend.Proxy.URL)}
+	}
+
+	// the probes may authenticate with their headers
+	if backend.HealthCheck != nil && len(backend.HealthCheck.Headers) > 0 {
+		healthCheck := *backend.HealthCheck
+		healthCheck.Headers = make(map[string]string, len(backend.HealthCheck.Headers))
+		for name := range backend.HealthCheck.Headers {
+			healthCheck.Headers[name] = redactedSecret
+		}
+		redacted.HealthCheck = &healthCheck
+	}
+
+	if backend.TLS != nil && backend.TLS.Certificate != nil {
+		backendTLS := *backend.TLS
+		certificate := *backend.TLS.Certificate
+		certificate.KeyFile = redactedSecret
+		backendTLS.Certificate = &certificate
+		redacted.TLS = &backendTLS
+	}
+	return &redacted
+}
+
+// redactURL replaces the password of the URL, the URLs which can't be parsed being redacted as a whole when they may hold one.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		if strings.Contains(rawURL, "@") {
+			return redactedSecret
+		}
+		return rawURL
+	}
+	if u.User == nil {
+		return rawURL
+	}
+	if _, ok := u.User.Password(); !ok {
+		return rawURL
+	}
+	u.User = url.UserPassword(u.User.Username(), redactedSecret)
+	return u.String()
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/mux"
+	"github.com/containous/traefik/provider/status"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderStatusHandler(t *testing.T) {
+	status.RecordSync("web", &types.Configuration{Frontends: map[string]*types.Frontend{"frontend1": {}}})
+
+	router := mux.NewRouter()
+	Handler{}.AddRoutes(router)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/providers/rest/status", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var providerStatus status.Status
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &providerStatus))
+	assert.Equal(t, "web", providerStatus.Provider)
+	assert.True(t, providerStatus.Connected)
+	assert.Equal(t, 1, providerStatus.Frontends)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/providers/unknown/status", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,244 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/log"
+)
+
+// Event types recorded in the audit log.
+const (
+	ConfigurationApplied  = "configuration.applied"
+	ConfigurationRejected = "configuration.rejected"
+	APIMutation           = "api.mutation"
+	CertificateIssued     = "certificate.issued"
+	CertificateRenewed    = "certificate.renewed"
+)
+
+// Event describes an audited operation.
+type Event struct {
+	Type       string   `json:"type"`
+	Provider   string   `json:"provider,omitempty"`
+	RemoteAddr string   `json:"remoteAddr,omitempty"`
+	Method     string   `json:"method,omitempty"`
+	Path       string   `json:"path,omitempty"`
+	Domains    []string `json:"domains,omitempty"`
+	Digest     string   `json:"digest,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// Entry is an event written to the audit log.
+// The entries are numbered and chained by their SHA-256 hashes, so that a removed, reordered or altered entry is detected.
+type Entry struct {
+	Sequence uint64    `json:"seq"`
+	Time     time.Time `json:"time"`
+	Event
+	PreviousHash string `json:"previousHash"`
+	Hash         string `json:"hash"`
+}
+
+var (
+	mu       sync.Mutex
+	file     *os.File
+	filePath string
+	sequence uint64
+	lastHash string
+)
+
+// OpenFile opens the audit log file, resuming the sequence and the hash chain from its last entry.
+func OpenFile(path string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return openFile(path)
+}
+
+func openFile(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	last, err := lastEntry(f)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("error reading audit log file %s: %v", path, err)
+	}
+	if last != nil {
+		sequence = last.Sequence
+		lastHash = last.Hash
+	}
+
+	file = f
+	filePath = path
+	return nil
+}
+
+// CloseFile closes the audit log file, the subsequent events being discarded.
+func CloseFile() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if file == nil {
+		return nil
+	}
+	err := file.Close()
+	file = nil
+	return err
+}
+
+// RotateFile closes and reopens the audit log file to allow for rotation by an external source,
+// the hash chain continuing in the new file.
+func RotateFile() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if file == nil {
+		return nil
+	}
+	file.Close()
+	file = nil
+
+	if err := openFile(filePath); err != nil {
+		return fmt.Errorf("error opening audit log file: %s", err)
+	}
+	return nil
+}
+
+// Record writes the event to the audit log, if enabled.
+func Record(event Event) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if file == nil {
+		return
+	}
+
+	entry := &Entry{
+		Sequence:     sequence + 1,
+		Time:         time.Now().UTC(),
+		Event:        event,
+		PreviousHash: lastHash,
+	}
+	line, err := entry.seal()
+	if err != nil {
+		log.Errorf("Error encoding audit log entry: %v", err)
+		return
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		log.Errorf("Error writing audit log entry %d: %v", entry.Sequence, err)
+		return
+	}
+	sequence = entry.Sequence
+	lastHash = entry.Hash
+}
+
+// Digest returns the SHA-256 hash of the JSON encoding of a value, e.g. of an applied configuration.
+func Digest(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify checks the sequence numbers and the hash chain of the audit log entries,
+// the first entry being trusted so that a rotated file can be verified on its own.
+func Verify(r io.Reader) error {
+	var previous *Entry
+	scanner := newScanner(r)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		entry := &Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return fmt.Errorf("invalid audit log entry after %d: %v", sequenceOf(previous), err)
+		}
+
+		hash, err := entry.hash()
+		if err != nil {
+			return err
+		}
+		if hash != entry.Hash {
+			return fmt.Errorf("audit log entry %d has been altered", entry.Sequence)
+		}
+		if previous != nil {
+			if entry.Sequence != previous.Sequence+1 {
+				return fmt.Errorf("audit log entry %d follows entry %d", entry.Sequence, previous.Sequence)
+			}
+			if entry.PreviousHash != previous.Hash {
+				return fmt.Errorf("audit log entry %d is not chained to entry %d", entry.Sequence, previous.Sequence)
+			}
+		}
+		previous = entry
+	}
+	return scanner.Err()
+}
+
+// seal computes the hash of the entry and returns its JSON encoding.
+func (e *Entry) seal() ([]byte, error) {
+	hash, err := e.hash()
+	if err != nil {
+		return nil, err
+	}
+	e.Hash = hash
+	return json.Marshal(e)
+}
+
+func (e *Entry) hash() (string, error) {
+	unsealed := *e
+	unsealed.Hash = ""
+	b, err := json.Marshal(unsealed)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func lastEntry(r io.Reader) (*Entry, error) {
+	var last []byte
+	scanner := newScanner(r)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) > 0 {
+			last = append(last[:0], scanner.Bytes()...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if last == nil {
+		return nil, nil
+	}
+
+	entry := &Entry{}
+	if err := json.Unmarshal(last, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return scanner
+}
+
+func sequenceOf(entry *Entry) uint64 {
+	if entry == nil {
+		return 0
+	}
+	return entry.Sequence
+}
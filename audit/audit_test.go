@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "traefik_audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "audit.log")
+	require.NoError(t, OpenFile(path))
+
+	Record(Event{Type: ConfigurationApplied, Provider: "file", Digest: Digest(map[string]string{"foo": "bar"})})
+	Record(Event{Type: APIMutation, Provider: "rest", RemoteAddr: "10.0.0.1:1234", Method: "PUT", Path: "/api/providers/rest"})
+	require.NoError(t, CloseFile())
+
+	// the events recorded after closing the file are discarded
+	Record(Event{Type: ConfigurationApplied, Provider: "file"})
+
+	// the sequence and the hash chain are resumed when the file is reopened
+	require.NoError(t, OpenFile(path))
+	Record(Event{Type: CertificateIssued, Domains: []string{"foo.localhost", "bar.localhost"}})
+	require.NoError(t, CloseFile())
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, Verify(bytes.NewReader(content)))
+
+	entries := readEntries(t, content)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, ConfigurationApplied, entries[0].Type)
+	assert.Equal(t, "file", entries[0].Provider)
+	assert.Len(t, entries[0].Digest, 64)
+
+	assert.Equal(t, APIMutation, entries[1].Type)
+	assert.Equal(t, "10.0.0.1:1234", entries[1].RemoteAddr)
+	assert.Equal(t, entries[0].Sequence+1, entries[1].Sequence)
+	assert.Equal(t, entries[0].Hash, entries[1].PreviousHash)
+
+	assert.Equal(t, CertificateIssued, entries[2].Type)
+	assert.Equal(t, []string{"foo.localhost", "bar.localhost"}, entries[2].Domains)
+	assert.Equal(t, entries[1].Sequence+1, entries[2].Sequence)
+	assert.Equal(t, entries[1].Hash, entries[2].PreviousHash)
+}
+
+func TestRotateFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "traefik_audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "audit.log")
+	rotatedPath := path + ".rotated"
+	require.NoError(t, OpenFile(path))
+	defer CloseFile()
+
+	Record(Event{Type: ConfigurationApplied, Provider: "file"})
+	require.NoError(t, os.Rename(path, rotatedPath))
+	require.NoError(t, RotateFile())
+	Record(Event{Type: ConfigurationApplied, Provider: "docker"})
+	require.NoError(t, CloseFile())
+
+	rotated, err := ioutil.ReadFile(rotatedPath)
+	require.NoError(t, err)
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	rotatedEntries := readEntries(t, rotated)
+	entries := readEntries(t, content)
+	require.Len(t, rotatedEntries, 1)
+	require.Len(t, entries, 1)
+
+	assert.Equal(t, rotatedEntries[0].Sequence+1, entries[0].Sequence)
+	assert.Equal(t, rotatedEntries[0].Hash, entries[0].PreviousHash)
+	assert.NoError(t, Verify(bytes.NewReader(append(rotated, content...))))
+}
+
+func TestVerify(t *testing.T) {
+	var lines []string
+	previousHash := ""
+	for i := uint64(1); i <= 3; i++ {
+		entry := &Entry{Sequence: i, Event: Event{Type: ConfigurationApplied, Provider: "file"}, PreviousHash: previousHash}
+		line, err := entry.seal()
+		require.NoError(t, err)
+		lines = append(lines, string(line))
+		previousHash = entry.Hash
+	}
+
+	testCases := []struct {
+		desc        string
+		lines       []string
+		expectedErr string
+	}{
+		{
+			desc:  "valid chain",
+			lines: lines,
+		},
+		{
+			desc:  "rotated file",
+			lines: lines[1:],
+		},
+		{
+			desc:        "removed entry",
+			lines:       []string{lines[0], lines[2]},
+			expectedErr: "audit log entry 3 follows entry 1",
+		},
+		{
+			desc:        "altered entry",
+			lines:       []string{lines[0], strings.Replace(lines[1], `"provider":"file"`, `"provider":"docker"`, 1), lines[2]},
+			expectedErr: "audit log entry 2 has been altered",
+		},
+		{
+			desc:        "reordered entries",
+			lines:       []string{lines[0], lines[2], lines[1]},
+			expectedErr: "audit log entry 3 follows entry 1",
+		},
+		{
+			desc:        "invalid entry",
+			lines:       []string{lines[0], "not an entry"},
+			expectedErr: "invalid audit log entry after 1: invalid character 'o' in literal null (expecting 'u')",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := Verify(strings.NewReader(strings.Join(test.lines, "\n")))
+			if len(test.expectedErr) > 0 {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func readEntries(t *testing.T, content []byte) []*Entry {
+	t.Helper()
+
+	var entries []*Entry
+	for _, line := range bytes.Split(bytes.TrimSpace(content), []byte("\n")) {
+		entry := &Entry{}
+		require.NoError(t, json.Unmarshal(line, entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
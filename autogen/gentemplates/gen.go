@@ -1,6 +1,7 @@
 // Code generated by go-bindata.
 // sources:
 // templates/consul_catalog.tmpl
+// templates/dns.tmpl
 // templates/docker.tmpl
 // templates/ecs.tmpl
 // templates/eureka.tmpl
@@ -8,6 +9,7 @@
 // templates/kv.tmpl
 // templates/marathon.tmpl
 // templates/mesos.tmpl
+// templates/nomad.tmpl
 // templates/notFound.tmpl
 // templates/rancher.tmpl
 // DO NOT EDIT!
@@ -100,11 +102,23 @@ var _templatesConsul_catalogTmpl = []byte(`[backends]
     retryExpression = "{{ $buffering.RetryExpression }}"
   {{end}}
 
+  {{ $connectTLS := getConnectTLS $service }}
+  {{if $connectTLS }}
+  [backends."backend-{{ $backendName }}".tls]
+    rootCAs = [{{range $connectTLS.RootCAs }}
+      """{{.}}""",
+      {{end}}]
+    certificateName = "{{ $connectTLS.CertificateName }}"
+    peerURIs = [{{range $connectTLS.PeerURIs }}
+      "{{.}}",
+      {{end}}]
+  {{end}}
+
 {{end}}
 {{range $index, $node := .Nodes}}
 
   [backends."backend-{{ getNodeBackendName $node }}".servers."{{ getServerName $node $index }}"]
-    url = "{{ getProtocol $node.Service.Tags }}://{{ getBackendAddress $node }}:{{ $node.Service.Port }}"
+    url = "{{if isConnectNode $node }}https{{else}}{{ getProtocol $node.Service.Tags }}{{end}}://{{ getBackendAddress $node }}:{{ $node.Service.Port }}"
     weight = {{ getWeight $node.Service.Tags }}
 
 {{end}}
@@ -244,6 +258,48 @@ func templatesConsul_catalogTmpl() (*asset, error) {
 	return a, nil
 }
 
+var _templatesDnsTmpl = []byte(`[backends]
+{{range $serviceName, $servers := .Servers }}
+  [backends."backend-{{ normalize $serviceName }}"]
+    {{range $serverName, $server := $servers }}
+    [backends."backend-{{ normalize $serviceName }}".servers."{{ $serverName }}"]
+      url = "{{ $server.URL }}"
+      weight = {{ $server.Weight }}
+    {{end}}
+{{end}}
+
+[frontends]
+{{range $serviceName, $servers := .Servers }}
+{{ $service := index $.Services $serviceName }}
+  [frontends."frontend-{{ normalize $serviceName }}"]
+    backend = "backend-{{ normalize $serviceName }}"
+    passHostHeader = {{ $service.PassHostHeader }}
+    {{if $service.EntryPoints }}
+    entryPoints = [{{range $service.EntryPoints }}
+      "{{.}}",
+      {{end}}]
+    {{end}}
+
+    [frontends."frontend-{{ normalize $serviceName }}".routes."route-{{ normalize $serviceName }}"]
+      rule = "{{ getFrontendRule $serviceName $service }}"
+{{end}}
+`)
+
+func templatesDnsTmplBytes() ([]byte, error) {
+	return _templatesDnsTmpl, nil
+}
+
+func templatesDnsTmpl() (*asset, error) {
+	bytes, err := templatesDnsTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/dns.tmpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
 [backends]
 {{range $backendName, $backend := .Backends}}
@@ -262,6 +318,8 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
       {{if $loadBalancer.Stickiness }}
       [backends."backend-{{ $backendName }}".loadBalancer.stickiness]
         cookieName = "{{ $loadBalancer.Stickiness.CookieName }}"
+        header = "{{ $loadBalancer.Stickiness.Header }}"
+        query = "{{ $loadBalancer.Stickiness.Query }}"
       {{end}}
   {{end}}
 
@@ -275,9 +333,35 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
   {{ $healthCheck := getHealthCheck $backend }}
   {{if $healthCheck }}
   [backends.backend-{{ $backendName }}.healthCheck]
+    type = "{{ $healthCheck.Type }}"
     path = "{{ $healthCheck.Path }}"
     port = {{ $healthCheck.Port }}
+    service = "{{ $healthCheck.Service }}"
     interval = "{{ $healthCheck.Interval }}"
+    timeout = "{{ $healthCheck.Timeout }}"
+    hostname = "{{ $healthCheck.Hostname }}"
+    proxyProtocol = {{ $healthCheck.ProxyProtocol }}
+    {{if $healthCheck.Headers }}
+    [backends.backend-{{ $backendName }}.healthCheck.headers]
+      {{range $k, $v := $healthCheck.Headers }}
+      {{$k}} = "{{$v}}"
+      {{end}}
+    {{end}}
+  {{end}}
+
+  {{ $backendTLS := getBackendTLS $backend }}
+  {{if $backendTLS }}
+  [backends.backend-{{ $backendName }}.tls]
+    serverName = "{{ $backendTLS.ServerName }}"
+    verification = "{{ $backendTLS.Verification }}"
+  {{end}}
+
+  {{ $adaptiveLimit := getAdaptiveLimit $backend }}
+  {{if $adaptiveLimit }}
+  [backends.backend-{{ $backendName }}.adaptiveLimit]
+    initialLimit = {{ $adaptiveLimit.InitialLimit }}
+    minLimit = {{ $adaptiveLimit.MinLimit }}
+    maxLimit = {{ $adaptiveLimit.MaxLimit }}
   {{end}}
 
   {{ $buffering := getBuffering $backend }}
@@ -303,6 +387,13 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
       [backends.backend-{{ $backendName }}.servers.server-{{ $server.Name | replace "/" "" | replace "." "-" }}]
         url = "{{ getProtocol $server }}://{{ getIPAddress $server }}:{{ getPort $server }}"
         weight = {{ getWeight $server }}
+      {{ $serverLabels := getServerLabels $server }}
+      {{if $serverLabels }}
+      [backends.backend-{{ $backendName }}.servers.server-{{ $server.Name | replace "/" "" | replace "." "-" }}.labels]
+        {{range $key, $value := $serverLabels }}
+        "{{ $key }}" = "{{ $value }}"
+        {{end}}
+      {{end}}
     {{end}}
   {{end}}
 
@@ -323,6 +414,10 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
     priority = {{ getServicePriority $container $serviceName }}
     passHostHeader = {{ getServicePassHostHeader $container $serviceName }}
     passTLSCert = {{ getServicePassTLSCert $container $serviceName }}
+    tenant = "{{ getTenant $container }}"
+    priorityClass = "{{ getPriorityClass $container }}"
+    acmeResolver = "{{ getACMEResolver $container }}"
+    tlsOptions = "{{ getTLSOptions $container }}"
 
     entryPoints = [{{range getServiceEntryPoints $container $serviceName }}
       "{{.}}",
@@ -339,6 +434,31 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
       "{{.}}",
       {{end}}]
 
+    {{ $hostHeader := getServiceHostHeader $container $serviceName }}
+    {{if $hostHeader }}
+    [frontends."frontend-{{ $ServiceFrontendName }}".hostHeader]
+      strategy = "{{ $hostHeader.Strategy }}"
+      value = "{{ $hostHeader.Value }}"
+    {{end}}
+
+    {{ $clientCA := getServiceClientCA $container $serviceName }}
+    {{if $clientCA }}
+    [frontends."frontend-{{ $ServiceFrontendName }}".clientCA]
+      optional = {{ $clientCA.Optional }}
+      files = [{{range $clientCA.Files }}
+        """{{.}}""",
+        {{end}}]
+    {{end}}
+
+    {{ $whitelistIPStrategy := getServiceWhitelistIPStrategy $container $serviceName }}
+    {{if $whitelistIPStrategy }}
+    [frontends."frontend-{{ $ServiceFrontendName }}".whitelistIPStrategy]
+      depth = {{ $whitelistIPStrategy.Depth }}
+      excludedIPs = [{{range $whitelistIPStrategy.ExcludedIPs }}
+        "{{.}}",
+        {{end}}]
+    {{end}}
+
     {{ $redirect := getServiceRedirect $container $serviceName }}
     {{if $redirect }}
     [frontends."frontend-{{ $ServiceFrontendName }}".redirect]
@@ -440,6 +560,12 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
     priority = {{ getPriority $container }}
     passHostHeader = {{ getPassHostHeader $container }}
     passTLSCert = {{ getPassTLSCert $container }}
+    serverSelector = "{{ getServerSelector $container }}"
+    tlsPassthrough = {{ getTLSPassthrough $container }}
+    tenant = "{{ getTenant $container }}"
+    priorityClass = "{{ getPriorityClass $container }}"
+    acmeResolver = "{{ getACMEResolver $container }}"
+    tlsOptions = "{{ getTLSOptions $container }}"
 
     entryPoints = [{{range getEntryPoints $container }}
       "{{.}}",
@@ -456,6 +582,31 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
       "{{.}}",
       {{end}}]
 
+    {{ $hostHeader := getHostHeader $container }}
+    {{if $hostHeader }}
+    [frontends."frontend-{{ $frontendName }}".hostHeader]
+      strategy = "{{ $hostHeader.Strategy }}"
+      value = "{{ $hostHeader.Value }}"
+    {{end}}
+
+    {{ $clientCA := getClientCA $container }}
+    {{if $clientCA }}
+    [frontends."frontend-{{ $frontendName }}".clientCA]
+      optional = {{ $clientCA.Optional }}
+      files = [{{range $clientCA.Files }}
+        """{{.}}""",
+        {{end}}]
+    {{end}}
+
+    {{ $whitelistIPStrategy := getWhitelistIPStrategy $container }}
+    {{if $whitelistIPStrategy }}
+    [frontends."frontend-{{ $frontendName }}".whitelistIPStrategy]
+      depth = {{ $whitelistIPStrategy.Depth }}
+      excludedIPs = [{{range $whitelistIPStrategy.ExcludedIPs }}
+        "{{.}}",
+        {{end}}]
+    {{end}}
+
     {{ $redirect := getRedirect $container }}
     {{if $redirect }}
     [frontends."frontend-{{ $frontendName }}".redirect]
@@ -991,6 +1142,8 @@ var _templatesKvTmpl = []byte(`[backends]
       {{if $loadBalancer.Stickiness }}
       [backends."{{ $backendName }}".loadBalancer.stickiness]
         cookieName = "{{ $loadBalancer.Stickiness.CookieName }}"
+        header = "{{ $loadBalancer.Stickiness.Header }}"
+        query = "{{ $loadBalancer.Stickiness.Query }}"
       {{end}}
   {{end}}
 
@@ -1004,9 +1157,38 @@ var _templatesKvTmpl = []byte(`[backends]
   {{ $healthCheck := getHealthCheck $backend }}
   {{if $healthCheck }}
   [backends.{{ $backendName }}.healthCheck]
+    type = "{{ $healthCheck.Type }}"
     path = "{{ $healthCheck.Path }}"
     port = {{ $healthCheck.Port }}
+    service = "{{ $healthCheck.Service }}"
     interval = "{{ $healthCheck.Interval }}"
+    timeout = "{{ $healthCheck.Timeout }}"
+    hostname = "{{ $healthCheck.Hostname }}"
+    proxyProtocol = {{ $healthCheck.ProxyProtocol }}
+    {{if $healthCheck.Headers }}
+    [backends.{{ $backendName }}.healthCheck.headers]
+      {{range $k, $v := $healthCheck.Headers }}
+      {{$k}} = "{{$v}}"
+      {{end}}
+    {{end}}
+  {{end}}
+
+  {{ $backendTLS := getBackendTLS $backend }}
+  {{if $backendTLS }}
+  [backends."{{ $backendName }}".tls]
+    serverName = "{{ $backendTLS.ServerName }}"
+    rootCAs = [{{range $backendTLS.RootCAs }}
+      """{{.}}""",
+      {{end}}]
+    verification = "{{ $backendTLS.Verification }}"
+  {{end}}
+
+  {{ $adaptiveLimit := getAdaptiveLimit $backend }}
+  {{if $adaptiveLimit }}
+  [backends.{{ $backendName }}.adaptiveLimit]
+    initialLimit = {{ $adaptiveLimit.InitialLimit }}
+    minLimit = {{ $adaptiveLimit.MinLimit }}
+    maxLimit = {{ $adaptiveLimit.MaxLimit }}
   {{end}}
 
   {{ $buffering := getBuffering $backend }}
@@ -1023,6 +1205,12 @@ var _templatesKvTmpl = []byte(`[backends]
   [backends."{{ $backendName }}".servers."{{ $serverName }}"]
     url = "{{ $server.URL }}"
     weight = {{ $server.Weight }}
+    {{if $server.Labels }}
+    [backends."{{ $backendName }}".servers."{{ $serverName }}".labels]
+      {{range $key, $value := $server.Labels }}
+      "{{ $key }}" = "{{ $value }}"
+      {{end}}
+    {{end}}
   {{end}}
 
 {{end}}
@@ -1036,6 +1224,12 @@ var _templatesKvTmpl = []byte(`[backends]
     priority = {{ getPriority $frontend }}
     passHostHeader = {{ getPassHostHeader $frontend }}
     passTLSCert = {{ getPassTLSCert $frontend }}
+    serverSelector = "{{ getServerSelector $frontend }}"
+    tlsPassthrough = {{ getTLSPassthrough $frontend }}
+    tenant = "{{ getTenant $frontend }}"
+    priorityClass = "{{ getPriorityClass $frontend }}"
+    acmeResolver = "{{ getACMEResolver $frontend }}"
+    tlsOptions = "{{ getTLSOptions $frontend }}"
 
     entryPoints = [{{range getEntryPoints $frontend }}
       "{{.}}",
@@ -1052,6 +1246,31 @@ var _templatesKvTmpl = []byte(`[backends]
       "{{.}}",
       {{end}}]
 
+    {{ $hostHeader := getHostHeader $frontend }}
+    {{if $hostHeader }}
+    [frontends."{{ $frontendName }}".hostHeader]
+      strategy = "{{ $hostHeader.Strategy }}"
+      value = "{{ $hostHeader.Value }}"
+    {{end}}
+
+    {{ $clientCA := getClientCA $frontend }}
+    {{if $clientCA }}
+    [frontends."{{ $frontendName }}".clientCA]
+      optional = {{ $clientCA.Optional }}
+      files = [{{range $clientCA.Files }}
+        """{{.}}""",
+        {{end}}]
+    {{end}}
+
+    {{ $whitelistIPStrategy := getWhitelistIPStrategy $frontend }}
+    {{if $whitelistIPStrategy }}
+    [frontends."{{ $frontendName }}".whitelistIPStrategy]
+      depth = {{ $whitelistIPStrategy.Depth }}
+      excludedIPs = [{{range $whitelistIPStrategy.ExcludedIPs }}
+        "{{.}}",
+        {{end}}]
+    {{end}}
+
     {{ $redirect := getRedirect $frontend }}
     {{if $redirect }}
     [frontends."{{ $frontendName }}".redirect]
@@ -1565,6 +1784,194 @@ func templatesMesosTmpl() (*asset, error) {
 	return a, nil
 }
 
+var _templatesNomadTmpl = []byte(`[backends]
+{{range $backendName, $backend := .Backends }}
+
+  [backends.backend-{{ $backendName }}]
+
+  {{ $circuitBreaker := getCircuitBreaker $backend }}
+  {{if $circuitBreaker }}
+  [backends."backend-{{ $backendName }}".circuitBreaker]
+    expression = "{{ $circuitBreaker.Expression }}"
+  {{end}}
+
+  {{ $loadBalancer := getLoadBalancer $backend }}
+  {{if $loadBalancer }}
+    [backends."backend-{{ $backendName }}".loadBalancer]
+      method = "{{ $loadBalancer.Method }}"
+      {{if $loadBalancer.Stickiness }}
+      [backends."backend-{{ $backendName }}".loadBalancer.stickiness]
+        cookieName = "{{ $loadBalancer.Stickiness.CookieName }}"
+      {{end}}
+  {{end}}
+
+  {{ $maxConn := getMaxConn $backend }}
+  {{if $maxConn }}
+  [backends."backend-{{ $backendName }}".maxConn]
+    extractorFunc = "{{ $maxConn.ExtractorFunc }}"
+    amount = {{ $maxConn.Amount }}
+  {{end}}
+
+  {{ $healthCheck := getHealthCheck $backend }}
+  {{if $healthCheck }}
+  [backends.backend-{{ $backendName }}.healthCheck]
+    path = "{{ $healthCheck.Path }}"
+    port = {{ $healthCheck.Port }}
+    interval = "{{ $healthCheck.Interval }}"
+  {{end}}
+
+  {{ $buffering := getBuffering $backend }}
+  {{if $buffering }}
+  [backends."backend-{{ $backendName }}".buffering]
+    maxRequestBodyBytes = {{ $buffering.MaxRequestBodyBytes }}
+    memRequestBodyBytes = {{ $buffering.MemRequestBodyBytes }}
+    maxResponseBodyBytes = {{ $buffering.MaxResponseBodyBytes }}
+    memResponseBodyBytes = {{ $buffering.MemResponseBodyBytes }}
+    retryExpression = "{{ $buffering.RetryExpression }}"
+  {{end}}
+
+  {{range $serverName, $server := getServers $backend}}
+  [backends.backend-{{ $backendName }}.servers.{{ $serverName }}]
+    url = "{{ $server.URL }}"
+    weight = {{ $server.Weight }}
+  {{end}}
+
+{{end}}
+
+[frontends]
+{{range $frontendName, $service := .Frontends }}
+
+  [frontends."frontend-{{ $frontendName }}"]
+    backend = "backend-{{ getBackendName $service }}"
+    priority = {{ getPriority $service }}
+    passHostHeader = {{ getPassHostHeader $service }}
+    passTLSCert = {{ getPassTLSCert $service }}
+
+    entryPoints = [{{range getEntryPoints $service }}
+      "{{.}}",
+      {{end}}]
+
+    {{ $whitelistSourceRange := getWhitelistSourceRange $service }}
+    {{if $whitelistSourceRange }}
+    whitelistSourceRange = [{{range $whitelistSourceRange }}
+      "{{.}}",
+      {{end}}]
+    {{end}}
+
+    basicAuth = [{{range getBasicAuth $service }}
+      "{{.}}",
+      {{end}}]
+
+    {{ $redirect := getRedirect $service }}
+    {{if $redirect }}
+    [frontends."frontend-{{ $frontendName }}".redirect]
+      entryPoint = "{{ $redirect.EntryPoint }}"
+      regex = "{{ $redirect.Regex }}"
+      replacement = "{{ $redirect.Replacement }}"
+      permanent = {{ $redirect.Permanent }}
+    {{end}}
+
+    {{ $errorPages := getErrorPages $service }}
+    {{if $errorPages }}
+    [frontends."frontend-{{ $frontendName }}".errors]
+      {{range $pageName, $page := $errorPages }}
+      [frontends."frontend-{{ $frontendName }}".errors.{{ $pageName }}]
+        status = [{{range $page.Status }}
+        "{{.}}",
+        {{end}}]
+        backend = "{{ $page.Backend }}"
+        query = "{{ $page.Query }}"
+      {{end}}
+    {{end}}
+
+    {{ $rateLimit := getRateLimit $service }}
+    {{if $rateLimit }}
+    [frontends."frontend-{{ $frontendName }}".rateLimit]
+      extractorFunc = "{{ $rateLimit.ExtractorFunc }}"
+      [frontends."frontend-{{ $frontendName }}".rateLimit.rateSet]
+        {{ range $limitName, $limit := $rateLimit.RateSet }}
+        [frontends."frontend-{{ $frontendName }}".rateLimit.rateSet.{{ $limitName }}]
+          period = "{{ $limit.Period }}"
+          average = {{ $limit.Average }}
+          burst = {{ $limit.Burst }}
+        {{end}}
+    {{end}}
+
+    {{ $headers := getHeaders $service }}
+    {{if $headers }}
+    [frontends."frontend-{{ $frontendName }}".headers]
+      SSLRedirect = {{ $headers.SSLRedirect }}
+      SSLTemporaryRedirect = {{ $headers.SSLTemporaryRedirect }}
+      SSLHost = "{{ $headers.SSLHost }}"
+      STSSeconds = {{ $headers.STSSeconds }}
+      STSIncludeSubdomains = {{ $headers.STSIncludeSubdomains }}
+      STSPreload = {{ $headers.STSPreload }}
+      ForceSTSHeader = {{ $headers.ForceSTSHeader }}
+      FrameDeny = {{ $headers.FrameDeny }}
+      CustomFrameOptionsValue = "{{ $headers.CustomFrameOptionsValue }}"
+      ContentTypeNosniff = {{ $headers.ContentTypeNosniff }}
+      BrowserXSSFilter = {{ $headers.BrowserXSSFilter }}
+      CustomBrowserXSSValue = "{{ $headers.CustomBrowserXSSValue }}"
+      ContentSecurityPolicy = "{{ $headers.ContentSecurityPolicy }}"
+      PublicKey = "{{ $headers.PublicKey }}"
+      ReferrerPolicy = "{{ $headers.ReferrerPolicy }}"
+      IsDevelopment = {{ $headers.IsDevelopment }}
+
+      {{if $headers.AllowedHosts }}
+      AllowedHosts = [{{range $headers.AllowedHosts }}
+        "{{.}}",
+        {{end}}]
+      {{end}}
+
+      {{if $headers.HostsProxyHeaders }}
+      HostsProxyHeaders = [{{range $headers.HostsProxyHeaders }}
+        "{{.}}",
+        {{end}}]
+      {{end}}
+
+      {{if $headers.CustomRequestHeaders }}
+      [frontends."frontend-{{ $frontendName }}".headers.customRequestHeaders]
+        {{range $k, $v := $headers.CustomRequestHeaders }}
+        {{$k}} = "{{$v}}"
+        {{end}}
+      {{end}}
+
+      {{if $headers.CustomResponseHeaders }}
+      [frontends."frontend-{{ $frontendName }}".headers.customResponseHeaders]
+        {{range $k, $v := $headers.CustomResponseHeaders }}
+        {{$k}} = "{{$v}}"
+        {{end}}
+      {{end}}
+
+      {{if $headers.SSLProxyHeaders }}
+      [frontends."frontend-{{ $frontendName }}".headers.SSLProxyHeaders]
+        {{range $k, $v := $headers.SSLProxyHeaders }}
+        {{$k}} = "{{$v}}"
+        {{end}}
+      {{end}}
+    {{end}}
+
+    [frontends."frontend-{{$frontendName}}".routes."route-frontend-{{$frontendName}}"]
+      rule = "{{getFrontendRule $service}}"
+
+{{end}}
+`)
+
+func templatesNomadTmplBytes() ([]byte, error) {
+	return _templatesNomadTmpl, nil
+}
+
+func templatesNomadTmpl() (*asset, error) {
+	bytes, err := templatesNomadTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/nomad.tmpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _templatesNotfoundTmpl = []byte(`<!DOCTYPE html>
 <html>
 <head>
@@ -1833,6 +2240,7 @@ func AssetNames() []string {
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
 	"templates/consul_catalog.tmpl": templatesConsul_catalogTmpl,
+	"templates/dns.tmpl":            templatesDnsTmpl,
 	"templates/docker.tmpl":         templatesDockerTmpl,
 	"templates/ecs.tmpl":            templatesEcsTmpl,
 	"templates/eureka.tmpl":         templatesEurekaTmpl,
@@ -1840,6 +2248,7 @@ var _bindata = map[string]func() (*asset, error){
 	"templates/kv.tmpl":             templatesKvTmpl,
 	"templates/marathon.tmpl":       templatesMarathonTmpl,
 	"templates/mesos.tmpl":          templatesMesosTmpl,
+	"templates/nomad.tmpl":          templatesNomadTmpl,
 	"templates/notFound.tmpl":       templatesNotfoundTmpl,
 	"templates/rancher.tmpl":        templatesRancherTmpl,
 }
@@ -1887,6 +2296,7 @@ type bintree struct {
 var _bintree = &bintree{nil, map[string]*bintree{
 	"templates": {nil, map[string]*bintree{
 		"consul_catalog.tmpl": {templatesConsul_catalogTmpl, map[string]*bintree{}},
+		"dns.tmpl":            {templatesDnsTmpl, map[string]*bintree{}},
 		"docker.tmpl":         {templatesDockerTmpl, map[string]*bintree{}},
 		"ecs.tmpl":            {templatesEcsTmpl, map[string]*bintree{}},
 		"eureka.tmpl":         {templatesEurekaTmpl, map[string]*bintree{}},
@@ -1894,6 +2304,7 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"kv.tmpl":             {templatesKvTmpl, map[string]*bintree{}},
 		"marathon.tmpl":       {templatesMarathonTmpl, map[string]*bintree{}},
 		"mesos.tmpl":          {templatesMesosTmpl, map[string]*bintree{}},
+		"nomad.tmpl":          {templatesNomadTmpl, map[string]*bintree{}},
 		"notFound.tmpl":       {templatesNotfoundTmpl, map[string]*bintree{}},
 		"rancher.tmpl":        {templatesRancherTmpl, map[string]*bintree{}},
 	}},
@@ -0,0 +1,483 @@
+package canary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// Statuses of a canary release.
+const (
+	StatusProgressing = "progressing"
+	StatusPaused      = "paused"
+	StatusRolledBack  = "rolledback"
+	StatusPromoted    = "promoted"
+)
+
+// Actions taken when the canary servers breach the thresholds.
+const (
+	ActionRollback = "rollback"
+	ActionPause    = "pause"
+)
+
+const defaultStepInterval = 5 * time.Minute
+
+var defaultSteps = []int{10, 25, 50, 100}
+
+// ErrUnknownRelease is returned when acting on the canary release of a frontend without one.
+var ErrUnknownRelease = errors.New("unknown canary release")
+
+var singleton *Controller
+var once sync.Once
+
+// GetController returns the canary controller which is guaranteed to be a singleton.
+func GetController() *Controller {
+	once.Do(func() {
+		singleton = &Controller{releases: make(map[string]*Release)}
+	})
+	return singleton
+}
+
+// LoadBalancer is the load-balancer of a frontend, whose server weights are shifted by the controller.
+type LoadBalancer interface {
+	RemoveServer(u *url.URL) error
+	UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error
+	Servers() []*url.URL
+}
+
+// WeightedLoadBalancer holds the canary weights of the servers of a load-balancer, and is the load-balancer
+// the health check goes through: the weight changes don't add back the servers removed by the health check,
+// and the servers recovered by the health check get back their canary weight.
+type WeightedLoadBalancer struct {
+	LoadBalancer
+	mu      sync.Mutex
+	weights map[string]int
+	down    map[string]bool
+}
+
+// NewWeightedLoadBalancer creates the load-balancer holding the canary weights of the servers of lb.
+func NewWeightedLoadBalancer(lb LoadBalancer) *WeightedLoadBalancer {
+	return &WeightedLoadBalancer{
+		LoadBalancer: lb,
+		weights:      make(map[string]int),
+		down:         make(map[string]bool),
+	}
+}
+
+// RemoveServer removes a server until it is upserted again.
+func (w *WeightedLoadBalancer) RemoveServer(u *url.URL) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.down[u.String()] = true
+	return w.LoadBalancer.RemoveServer(u)
+}
+
+// UpsertServer adds a server, with its canary weight if it has one.
+func (w *WeightedLoadBalancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.down, u.String())
+	if weight, ok := w.weights[u.String()]; ok {
+		return w.upsert(u, weight)
+	}
+	return w.LoadBalancer.UpsertServer(u, options...)
+}
+
+// setWeight sets the canary weight of a server, which is only applied once the server is up.
+func (w *WeightedLoadBalancer) setWeight(u *url.URL, weight int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.weights[u.String()] = weight
+	if w.down[u.String()] {
+		return nil
+	}
+	return w.upsert(u, weight)
+}
+
+func (w *WeightedLoadBalancer) upsert(u *url.URL, weight int) error {
+	if weight == 0 {
+		// the server may already be missing from the load-balancer
+		w.LoadBalancer.RemoveServer(u)
+		return nil
+	}
+	return w.LoadBalancer.UpsertServer(u, roundrobin.Weight(weight))
+}
+
+// Server is a server of a load-balancer along with its configured weight.
+type Server struct {
+	URL    *url.URL
+	Weight int
+}
+
+// Target is a load-balancer of the frontend, on one of its entry points,
+// holding both the stable servers and the canary ones.
+type Target struct {
+	LB       *WeightedLoadBalancer
+	Stable   []Server
+	Canary   []Server
+	Recorder *Recorder
+}
+
+// Status is the state of a canary release, as exposed by the API.
+type Status struct {
+	Frontend       string    `json:"frontend"`
+	Backend        string    `json:"backend"`
+	Status         string    `json:"status"`
+	Weight         int       `json:"weight"`
+	Reason         string    `json:"reason,omitempty"`
+	LastTransition time.Time `json:"lastTransition"`
+	Requests       int64     `json:"requests"`
+	ErrorRate      float64   `json:"errorRate"`
+	AverageLatency string    `json:"averageLatency"`
+}
+
+// Release is the canary release of a frontend: the share of its traffic sent to the canary servers
+// increases step by step as long as they stay under the thresholds.
+type Release struct {
+	Frontend string
+	Targets  []*Target
+
+	config       types.Canary
+	steps        []int
+	stepInterval time.Duration
+	maxLatency   time.Duration
+
+	mu             sync.Mutex
+	step           int
+	status         string
+	reason         string
+	lastTransition time.Time
+	lastStats      Stats
+}
+
+// NewRelease validates the canary configuration of a frontend and creates its release, starting at the first step.
+func NewRelease(frontend string, config types.Canary) (*Release, error) {
+	if len(config.Backend) == 0 {
+		return nil, errors.New("missing canary backend")
+	}
+
+	steps := config.Steps
+	if len(steps) == 0 {
+		steps = defaultSteps
+	}
+	for i, step := range steps {
+		if step <= 0 || step > 100 || (i > 0 && step <= steps[i-1]) {
+			return nil, fmt.Errorf("invalid canary steps %v: the weights must be increasing percentages", steps)
+		}
+	}
+	if steps[len(steps)-1] != 100 {
+		steps = append(append([]int{}, steps...), 100)
+	}
+
+	stepInterval := defaultStepInterval
+	if len(config.StepInterval) > 0 {
+		var err error
+		stepInterval, err = time.ParseDuration(config.StepInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid canary step interval: %v", err)
+		}
+		if stepInterval <= 0 {
+			return nil, fmt.Errorf("invalid canary step interval: %s", config.StepInterval)
+		}
+	}
+
+	var maxLatency time.Duration
+	if len(config.MaxLatency) > 0 {
+		var err error
+		maxLatency, err = time.ParseDuration(config.MaxLatency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid canary max latency: %v", err)
+		}
+	}
+
+	switch config.OnFailure {
+	case "", ActionRollback, ActionPause:
+	default:
+		return nil, fmt.Errorf("invalid canary failure action %q: rollback | pause", config.OnFailure)
+	}
+
+	return &Release{
+		Frontend:       frontend,
+		config:         config,
+		steps:          steps,
+		stepInterval:   stepInterval,
+		maxLatency:     maxLatency,
+		status:         StatusProgressing,
+		lastTransition: time.Now().UTC(),
+	}, nil
+}
+
+// Weight returns the percentage of the traffic currently sent to the canary servers.
+func (r *Release) Weight() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.weight()
+}
+
+func (r *Release) weight() int {
+	switch r.status {
+	case StatusRolledBack:
+		return 0
+	case StatusPromoted:
+		return 100
+	default:
+		return r.steps[r.step]
+	}
+}
+
+// Status returns the state of the release.
+func (r *Release) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Status{
+		Frontend:       r.Frontend,
+		Backend:        r.config.Backend,
+		Status:         r.status,
+		Weight:         r.weight(),
+		Reason:         r.reason,
+		LastTransition: r.lastTransition,
+		Requests:       r.lastStats.Requests,
+		ErrorRate:      r.lastStats.ErrorRate(),
+		AverageLatency: r.lastStats.AverageLatency().String(),
+	}
+}
+
+// transition changes the status of the release and applies the resulting weights to its load-balancers.
+func (r *Release) transition(status, reason string) {
+	r.status = status
+	r.reason = reason
+	r.lastTransition = time.Now().UTC()
+	log.Infof("Canary release of frontend %s %s at %d%%: %s", r.Frontend, status, r.weight(), reason)
+	r.apply()
+}
+
+// apply sets the weights of the stable and canary servers, so that the canary ones receive the current percentage of the traffic.
+func (r *Release) apply() {
+	weight := r.weight()
+	for _, target := range r.Targets {
+		stableTotal, canaryTotal := totalWeight(target.Stable), totalWeight(target.Canary)
+		for _, server := range target.Stable {
+			setWeight(target.LB, server.URL, serverWeight(server)*(100-weight)*canaryTotal)
+		}
+		for _, server := range target.Canary {
+			setWeight(target.LB, server.URL, serverWeight(server)*weight*stableTotal)
+		}
+	}
+}
+
+func setWeight(lb *WeightedLoadBalancer, u *url.URL, weight int) {
+	if err := lb.setWeight(u, weight); err != nil {
+		log.Errorf("Error setting the weight of server %s: %v", u, err)
+	}
+}
+
+func serverWeight(server Server) int {
+	if server.Weight <= 0 {
+		return 1
+	}
+	return server.Weight
+}
+
+func totalWeight(servers []Server) int {
+	var total int
+	for _, server := range servers {
+		total += serverWeight(server)
+	}
+	if total == 0 {
+		return 1
+	}
+	return total
+}
+
+// evaluate checks the canary servers against the thresholds since the previous evaluation,
+// moving to the next step when they are met, and rolling back or pausing when they are breached.
+func (r *Release) evaluate() {
+	var stats Stats
+	for _, target := range r.Targets {
+		stats = stats.add(target.Recorder.collect())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastStats = stats
+	if r.status != StatusProgressing {
+		return
+	}
+
+	if stats.Requests < r.config.MinRequests {
+		log.Debugf("Canary release of frontend %s waiting for %d requests, got %d", r.Frontend, r.config.MinRequests, stats.Requests)
+		return
+	}
+
+	if reason := r.breach(stats); len(reason) > 0 {
+		if r.config.OnFailure == ActionPause {
+			r.transition(StatusPaused, reason)
+		} else {
+			r.transition(StatusRolledBack, reason)
+		}
+		return
+	}
+
+	if r.step == len(r.steps)-1 {
+		r.transition(StatusPromoted, "all the steps succeeded")
+		return
+	}
+	r.step++
+	r.transition(StatusProgressing, fmt.Sprintf("step %d/%d succeeded", r.step, len(r.steps)))
+}
+
+func (r *Release) breach(stats Stats) string {
+	if r.config.MaxErrorRate > 0 && stats.ErrorRate() > r.config.MaxErrorRate {
+		return fmt.Sprintf("error rate %.3f above %.3f", stats.ErrorRate(), r.config.MaxErrorRate)
+	}
+	if r.maxLatency > 0 && stats.AverageLatency() > r.maxLatency {
+		return fmt.Sprintf("average latency %s above %s", stats.AverageLatency(), r.maxLatency)
+	}
+	return ""
+}
+
+func (r *Release) run(ctx context.Context) {
+	ticker := time.NewTicker(r.stepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.evaluate()
+		}
+	}
+}
+
+// Controller drives the canary releases of the frontends.
+type Controller struct {
+	mu       sync.RWMutex
+	releases map[string]*Release
+	cancel   context.CancelFunc
+}
+
+// SetReleases replaces the canary releases after a configuration reload.
+// A release whose configuration is unchanged carries on from its current step and status.
+func (c *Controller) SetReleases(parentCtx context.Context, releases map[string]*Release) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+	}
+	ctx, cancel := context.WithCancel(parentCtx)
+	c.cancel = cancel
+
+	for frontend, release := range releases {
+		if previous, ok := c.releases[frontend]; ok && reflect.DeepEqual(previous.config, release.config) {
+			previous.mu.Lock()
+			release.step = previous.step
+			release.status = previous.status
+			release.reason = previous.reason
+			release.lastTransition = previous.lastTransition
+			release.lastStats = previous.lastStats
+			previous.mu.Unlock()
+		}
+
+		release.mu.Lock()
+		release.apply()
+		release.mu.Unlock()
+
+		currentRelease := release
+		safe.Go(func() {
+			currentRelease.run(ctx)
+		})
+	}
+	c.releases = releases
+}
+
+// Statuses returns the states of the canary releases, sorted by frontend.
+func (c *Controller) Statuses() []Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(c.releases))
+	for _, release := range c.releases {
+		statuses = append(statuses, release.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Frontend < statuses[j].Frontend
+	})
+	return statuses
+}
+
+// Pause stops the canary release of a frontend at its current weight.
+func (c *Controller) Pause(frontend string) (Status, error) {
+	return c.act(frontend, func(r *Release) error {
+		if r.status != StatusProgressing {
+			return fmt.Errorf("canary release of frontend %s is %s", frontend, r.status)
+		}
+		r.transition(StatusPaused, "paused through the API")
+		return nil
+	})
+}
+
+// Resume carries on the canary release of a frontend, from its first step if it has been rolled back.
+func (c *Controller) Resume(frontend string) (Status, error) {
+	return c.act(frontend, func(r *Release) error {
+		switch r.status {
+		case StatusPaused:
+		case StatusRolledBack:
+			r.step = 0
+		default:
+			return fmt.Errorf("canary release of frontend %s is %s", frontend, r.status)
+		}
+		r.transition(StatusProgressing, "resumed through the API")
+		return nil
+	})
+}
+
+// Rollback sends all the traffic of a frontend back to its stable servers.
+func (c *Controller) Rollback(frontend string) (Status, error) {
+	return c.act(frontend, func(r *Release) error {
+		r.transition(StatusRolledBack, "rolled back through the API")
+		return nil
+	})
+}
+
+// Promote sends all the traffic of a frontend to its canary servers.
+func (c *Controller) Promote(frontend string) (Status, error) {
+	return c.act(frontend, func(r *Release) error {
+		r.step = len(r.steps) - 1
+		r.transition(StatusPromoted, "promoted through the API")
+		return nil
+	})
+}
+
+func (c *Controller) act(frontend string, action func(r *Release) error) (Status, error) {
+	c.mu.RLock()
+	release, ok := c.releases[frontend]
+	c.mu.RUnlock()
+	if !ok {
+		return Status{}, ErrUnknownRelease
+	}
+
+	release.mu.Lock()
+	err := action(release)
+	release.mu.Unlock()
+	if err != nil {
+		return Status{}, err
+	}
+	return release.Status(), nil
+}
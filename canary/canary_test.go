@@ -0,0 +1,328 @@
+package canary
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func newTestLoadBalancer(t *testing.T) *roundrobin.RoundRobin {
+	t.Helper()
+
+	lb, err := roundrobin.New(nil)
+	require.NoError(t, err)
+	return lb
+}
+
+func getWeights(lb *roundrobin.RoundRobin) map[string]int {
+	weights := make(map[string]int)
+	for _, u := range lb.Servers() {
+		weights[u.String()], _ = lb.ServerWeight(u)
+	}
+	return weights
+}
+
+func newTestTarget(lb LoadBalancer) *Target {
+	return &Target{
+		LB: NewWeightedLoadBalancer(lb),
+		Stable: []Server{
+			{URL: testhelpers.MustParseURL("http://stable1:80"), Weight: 1},
+			{URL: testhelpers.MustParseURL("http://stable2:80"), Weight: 3},
+		},
+		Canary: []Server{
+			{URL: testhelpers.MustParseURL("http://canary1:80"), Weight: 1},
+		},
+		Recorder: NewRecorder(nil, nil),
+	}
+}
+
+func TestNewRelease(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        types.Canary
+		expectedSteps []int
+		expectedError bool
+	}{
+		{
+			desc:          "default steps",
+			config:        types.Canary{Backend: "canary"},
+			expectedSteps: []int{10, 25, 50, 100},
+		},
+		{
+			desc:          "steps completed up to 100",
+			config:        types.Canary{Backend: "canary", Steps: []int{5, 20}},
+			expectedSteps: []int{5, 20, 100},
+		},
+		{
+			desc:          "missing backend",
+			config:        types.Canary{},
+			expectedError: true,
+		},
+		{
+			desc:          "decreasing steps",
+			config:        types.Canary{Backend: "canary", Steps: []int{50, 20}},
+			expectedError: true,
+		},
+		{
+			desc:          "step above 100",
+			config:        types.Canary{Backend: "canary", Steps: []int{50, 150}},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid step interval",
+			config:        types.Canary{Backend: "canary", StepInterval: "often"},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid max latency",
+			config:        types.Canary{Backend: "canary", MaxLatency: "fast"},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid failure action",
+			config:        types.Canary{Backend: "canary", OnFailure: "ignore"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			release, err := NewRelease("frontend", test.config)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedSteps, release.steps)
+			assert.Equal(t, StatusProgressing, release.status)
+			assert.Equal(t, test.expectedSteps[0], release.Weight())
+		})
+	}
+}
+
+func TestReleaseApply(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		status          string
+		step            int
+		expectedWeights map[string]int
+	}{
+		{
+			desc:   "first step",
+			status: StatusProgressing,
+			step:   0,
+			// stable: 90% of the traffic split 1:3, canary: 10%
+			expectedWeights: map[string]int{"http://stable1:80": 90, "http://stable2:80": 270, "http://canary1:80": 40},
+		},
+		{
+			desc:            "rolled back",
+			status:          StatusRolledBack,
+			step:            1,
+			expectedWeights: map[string]int{"http://stable1:80": 100, "http://stable2:80": 300},
+		},
+		{
+			desc:            "promoted",
+			status:          StatusPromoted,
+			step:            3,
+			expectedWeights: map[string]int{"http://canary1:80": 400},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			release, err := NewRelease("frontend", types.Canary{Backend: "canary"})
+			require.NoError(t, err)
+
+			lb := newTestLoadBalancer(t)
+			release.Targets = []*Target{newTestTarget(lb)}
+			release.status = test.status
+			release.step = test.step
+			release.apply()
+
+			assert.Equal(t, test.expectedWeights, getWeights(lb))
+		})
+	}
+}
+
+func TestReleaseEvaluate(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         types.Canary
+		status         string
+		step           int
+		stats          Stats
+		expectedStatus string
+		expectedWeight int
+	}{
+		{
+			desc:           "next step",
+			config:         types.Canary{Backend: "canary", MaxErrorRate: 0.1, MaxLatency: "100ms"},
+			status:         StatusProgressing,
+			stats:          Stats{Requests: 100, Errors: 5, Latency: 100 * 50 * time.Millisecond},
+			expectedStatus: StatusProgressing,
+			expectedWeight: 25,
+		},
+		{
+			desc:           "promoted after the last step",
+			config:         types.Canary{Backend: "canary"},
+			status:         StatusProgressing,
+			step:           3,
+			stats:          Stats{Requests: 100},
+			expectedStatus: StatusPromoted,
+			expectedWeight: 100,
+		},
+		{
+			desc:           "waiting for the minimum requests",
+			config:         types.Canary{Backend: "canary", MinRequests: 100, MaxErrorRate: 0.1},
+			status:         StatusProgressing,
+			stats:          Stats{Requests: 10, Errors: 10},
+			expectedStatus: StatusProgressing,
+			expectedWeight: 10,
+		},
+		{
+			desc:           "rolled back on error rate",
+			config:         types.Canary{Backend: "canary", MaxErrorRate: 0.1},
+			status:         StatusProgressing,
+			step:           2,
+			stats:          Stats{Requests: 100, Errors: 11},
+			expectedStatus: StatusRolledBack,
+			expectedWeight: 0,
+		},
+		{
+			desc:           "paused on latency",
+			config:         types.Canary{Backend: "canary", MaxLatency: "100ms", OnFailure: ActionPause},
+			status:         StatusProgressing,
+			step:           1,
+			stats:          Stats{Requests: 10, Latency: 10 * 200 * time.Millisecond},
+			expectedStatus: StatusPaused,
+			expectedWeight: 25,
+		},
+		{
+			desc:           "paused",
+			config:         types.Canary{Backend: "canary"},
+			status:         StatusPaused,
+			stats:          Stats{Requests: 100},
+			expectedStatus: StatusPaused,
+			expectedWeight: 10,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			release, err := NewRelease("frontend", test.config)
+			require.NoError(t, err)
+
+			lb := newTestLoadBalancer(t)
+			target := newTestTarget(lb)
+			target.Recorder.stats = test.stats
+			release.Targets = []*Target{target}
+			release.status = test.status
+			release.step = test.step
+
+			release.evaluate()
+
+			status := release.Status()
+			assert.Equal(t, test.expectedStatus, status.Status)
+			assert.Equal(t, test.expectedWeight, status.Weight)
+			assert.Equal(t, test.stats.Requests, status.Requests)
+			assert.Equal(t, Stats{}, target.Recorder.collect())
+		})
+	}
+}
+
+func TestControllerActions(t *testing.T) {
+	controller := &Controller{}
+	release, err := NewRelease("frontend", types.Canary{Backend: "canary", StepInterval: "1h"})
+	require.NoError(t, err)
+	lb := newTestLoadBalancer(t)
+	release.Targets = []*Target{newTestTarget(lb)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	controller.SetReleases(ctx, map[string]*Release{"frontend": release})
+	assert.Equal(t, 40, getWeights(lb)["http://canary1:80"])
+
+	_, err = controller.Pause("unknown")
+	assert.Equal(t, ErrUnknownRelease, err)
+
+	status, err := controller.Pause("frontend")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPaused, status.Status)
+
+	_, err = controller.Pause("frontend")
+	assert.EqualError(t, err, "canary release of frontend frontend is paused")
+
+	status, err = controller.Rollback("frontend")
+	require.NoError(t, err)
+	assert.Equal(t, StatusRolledBack, status.Status)
+	assert.Equal(t, 0, status.Weight)
+	assert.NotContains(t, getWeights(lb), "http://canary1:80")
+
+	status, err = controller.Resume("frontend")
+	require.NoError(t, err)
+	assert.Equal(t, StatusProgressing, status.Status)
+	assert.Equal(t, 10, status.Weight)
+
+	status, err = controller.Promote("frontend")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPromoted, status.Status)
+	assert.Equal(t, map[string]int{"http://canary1:80": 400}, getWeights(lb))
+
+	_, err = controller.Resume("frontend")
+	assert.EqualError(t, err, "canary release of frontend frontend is promoted")
+
+	// the state of an unchanged release is kept across the configuration reloads
+	reloaded, err := NewRelease("frontend", types.Canary{Backend: "canary", StepInterval: "1h"})
+	require.NoError(t, err)
+	reloadedLB := newTestLoadBalancer(t)
+	reloaded.Targets = []*Target{newTestTarget(reloadedLB)}
+	changed, err := NewRelease("other", types.Canary{Backend: "canary", StepInterval: "1h"})
+	require.NoError(t, err)
+
+	controller.SetReleases(ctx, map[string]*Release{"frontend": reloaded, "other": changed})
+	assert.Equal(t, map[string]int{"http://canary1:80": 400}, getWeights(reloadedLB))
+
+	statuses := controller.Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "frontend", statuses[0].Frontend)
+	assert.Equal(t, StatusPromoted, statuses[0].Status)
+	assert.Equal(t, "other", statuses[1].Frontend)
+	assert.Equal(t, StatusProgressing, statuses[1].Status)
+}
+
+func TestWeightedLoadBalancerHealthCheck(t *testing.T) {
+	lb := newTestLoadBalancer(t)
+	target := newTestTarget(lb)
+
+	release, err := NewRelease("frontend", types.Canary{Backend: "canary", Steps: []int{20}})
+	require.NoError(t, err)
+	release.Targets = []*Target{target}
+	release.apply()
+	assert.Equal(t, map[string]int{"http://stable1:80": 80, "http://stable2:80": 240, "http://canary1:80": 80}, getWeights(lb))
+
+	// the health check removes a server, which the promotion doesn't add back
+	canary1 := testhelpers.MustParseURL("http://canary1:80")
+	require.NoError(t, target.LB.RemoveServer(canary1))
+	release.step++
+	release.apply()
+	assert.Empty(t, getWeights(lb))
+
+	// the recovered server gets back its canary weight, not the health check one
+	require.NoError(t, target.LB.UpsertServer(canary1, roundrobin.Weight(1)))
+	assert.Equal(t, map[string]int{"http://canary1:80": 400}, getWeights(lb))
+}
@@ -0,0 +1,123 @@
+package canary
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Stats are the requests forwarded to the canary servers over a period.
+type Stats struct {
+	Requests int64
+	Errors   int64
+	Latency  time.Duration
+}
+
+// ErrorRate returns the share of the requests answered with a 5xx status.
+func (s Stats) ErrorRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Requests)
+}
+
+// AverageLatency returns the average duration of the requests.
+func (s Stats) AverageLatency() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.Latency / time.Duration(s.Requests)
+}
+
+func (s Stats) add(other Stats) Stats {
+	return Stats{
+		Requests: s.Requests + other.Requests,
+		Errors:   s.Errors + other.Errors,
+		Latency:  s.Latency + other.Latency,
+	}
+}
+
+// Recorder records the requests forwarded to the canary servers.
+// It wraps the forwarder of the load-balancer, the request URL being the one of the selected server.
+type Recorder struct {
+	next  http.Handler
+	hosts map[string]bool
+	mu    sync.Mutex
+	stats Stats
+	clock func() time.Time
+}
+
+// NewRecorder creates a recorder of the requests forwarded to the canary servers.
+func NewRecorder(next http.Handler, canaryURLs []*url.URL) *Recorder {
+	hosts := make(map[string]bool)
+	for _, u := range canaryURLs {
+		hosts[u.Scheme+"://"+u.Host] = true
+	}
+	return &Recorder{next: next, hosts: hosts, clock: time.Now}
+}
+
+func (r *Recorder) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !r.hosts[req.URL.Scheme+"://"+req.URL.Host] {
+		r.next.ServeHTTP(rw, req)
+		return
+	}
+
+	start := r.clock()
+	recorder := &statusRecorder{ResponseWriter: rw, statusCode: http.StatusOK}
+	r.next.ServeHTTP(recorder, req)
+	latency := r.clock().Sub(start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.Requests++
+	r.stats.Latency += latency
+	if recorder.statusCode >= http.StatusInternalServerError {
+		r.stats.Errors++
+	}
+}
+
+// collect returns the stats recorded since the previous collection.
+func (r *Recorder) collect() Stats {
+	if r == nil {
+		return Stats{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := r.stats
+	r.stats = Stats{}
+	return stats
+}
+
+// statusRecorder captures the status code of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+// WriteHeader captures the status code for later retrieval.
+func (r *statusRecorder) WriteHeader(status int) {
+	r.ResponseWriter.WriteHeader(status)
+	r.statusCode = status
+}
+
+// Hijack hijacks the connection
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// CloseNotify returns a channel that receives at most a
+// single value (true) when the client connection has gone
+// away.
+func (r *statusRecorder) CloseNotify() <-chan bool {
+	return r.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+// Flush sends any buffered data to the client.
+func (r *statusRecorder) Flush() {
+	r.ResponseWriter.(http.Flusher).Flush()
+}
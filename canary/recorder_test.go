@@ -0,0 +1,45 @@
+package canary
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/error" {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if req.URL.Path == "/notfound" {
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	recorder := NewRecorder(next, []*url.URL{testhelpers.MustParseURL("http://canary1:80")})
+	now := time.Date(2018, time.January, 10, 10, 0, 0, 0, time.UTC)
+	recorder.clock = func() time.Time {
+		now = now.Add(50 * time.Millisecond)
+		return now
+	}
+
+	for _, rawURL := range []string{"http://canary1:80/", "http://canary1:80/error", "http://canary1:80/notfound", "http://stable1:80/error"} {
+		recorder.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, rawURL, nil))
+	}
+
+	stats := recorder.collect()
+	assert.Equal(t, Stats{Requests: 3, Errors: 1, Latency: 150 * time.Millisecond}, stats)
+	assert.InDelta(t, 1.0/3, stats.ErrorRate(), 0.001)
+	assert.Equal(t, 50*time.Millisecond, stats.AverageLatency())
+
+	// the stats are reset by the collection
+	assert.Equal(t, Stats{}, recorder.collect())
+	assert.Equal(t, 0.0, Stats{}.ErrorRate())
+	assert.Equal(t, time.Duration(0), Stats{}.AverageLatency())
+}
@@ -15,16 +15,21 @@ import (
 	"github.com/containous/traefik/provider/boltdb"
 	"github.com/containous/traefik/provider/consul"
 	"github.com/containous/traefik/provider/consulcatalog"
+	"github.com/containous/traefik/provider/dns"
 	"github.com/containous/traefik/provider/docker"
 	"github.com/containous/traefik/provider/dynamodb"
 	"github.com/containous/traefik/provider/ecs"
 	"github.com/containous/traefik/provider/etcd"
 	"github.com/containous/traefik/provider/eureka"
 	"github.com/containous/traefik/provider/file"
+	"github.com/containous/traefik/provider/http"
 	"github.com/containous/traefik/provider/kubernetes"
+	"github.com/containous/traefik/provider/kubernetes/crd"
 	"github.com/containous/traefik/provider/marathon"
 	"github.com/containous/traefik/provider/mesos"
+	"github.com/containous/traefik/provider/nomad"
 	"github.com/containous/traefik/provider/rancher"
+	"github.com/containous/traefik/provider/redis"
 	"github.com/containous/traefik/provider/rest"
 	"github.com/containous/traefik/provider/zk"
 	"github.com/containous/traefik/types"
@@ -105,6 +110,7 @@ func NewTraefikDefaultPointersConfiguration() *TraefikConfiguration {
 	defaultConsulCatalog.Constraints = types.Constraints{}
 	defaultConsulCatalog.Prefix = "traefik"
 	defaultConsulCatalog.FrontEndRule = "Host:{{.ServiceName}}.{{.Domain}}"
+	defaultConsulCatalog.ServiceName = "traefik"
 
 	// default Etcd
 	var defaultEtcd etcd.Provider
@@ -127,11 +133,23 @@ func NewTraefikDefaultPointersConfiguration() *TraefikConfiguration {
 	defaultBoltDb.Prefix = "/traefik"
 	defaultBoltDb.Constraints = types.Constraints{}
 
+	//default Redis
+	var defaultRedis redis.Provider
+	defaultRedis.Watch = true
+	defaultRedis.Endpoint = "127.0.0.1:6379"
+	defaultRedis.Prefix = "traefik"
+	defaultRedis.Constraints = types.Constraints{}
+
 	//default Kubernetes
 	var defaultKubernetes kubernetes.Provider
 	defaultKubernetes.Watch = true
 	defaultKubernetes.Constraints = types.Constraints{}
 
+	//default Kubernetes CRD
+	var defaultKubernetesCRD crd.Provider
+	defaultKubernetesCRD.Watch = true
+	defaultKubernetesCRD.Constraints = types.Constraints{}
+
 	// default Mesos
 	var defaultMesos mesos.Provider
 	defaultMesos.Watch = true
@@ -168,6 +186,28 @@ func NewTraefikDefaultPointersConfiguration() *TraefikConfiguration {
 	var defaultEureka eureka.Provider
 	defaultEureka.Delay = flaeg.Duration(30 * time.Second)
 
+	// default DNS
+	var defaultDNS dns.Provider
+	defaultDNS.Watch = true
+	defaultDNS.RefreshInterval = flaeg.Duration(30 * time.Second)
+	defaultDNS.MinRefreshInterval = flaeg.Duration(5 * time.Second)
+
+	// default Nomad
+	var defaultNomad nomad.Provider
+	defaultNomad.Watch = true
+	defaultNomad.Endpoint = "http://127.0.0.1:4646"
+	defaultNomad.Namespace = "default"
+	defaultNomad.ExposedByDefault = true
+	defaultNomad.Constraints = types.Constraints{}
+	defaultNomad.Prefix = "traefik"
+	defaultNomad.FrontEndRule = "Host:{{.ServiceName}}.{{.Domain}}"
+
+	// default HTTP
+	var defaultHTTP http.Provider
+	defaultHTTP.Watch = true
+	defaultHTTP.PollInterval = flaeg.Duration(15 * time.Second)
+	defaultHTTP.PollTimeout = flaeg.Duration(5 * time.Second)
+
 	// default ServiceFabric
 	var defaultServiceFabric servicefabric.Provider
 	defaultServiceFabric.APIVersion = sf.DefaultAPIVersion
@@ -202,7 +242,13 @@ func NewTraefikDefaultPointersConfiguration() *TraefikConfiguration {
 
 	// default ForwardingTimeouts
 	forwardingTimeouts := configuration.ForwardingTimeouts{
-		DialTimeout: flaeg.Duration(configuration.DefaultDialTimeout),
+		DialTimeout:     flaeg.Duration(configuration.DefaultDialTimeout),
+		IdleConnTimeout: flaeg.Duration(configuration.DefaultIdleConnTimeout),
+	}
+
+	// default RoutingDebug
+	defaultRoutingDebug := configuration.RoutingDebug{
+		Header: configuration.DefaultRoutingDebugHeader,
 	}
 
 	// default Tracing
@@ -268,16 +314,22 @@ func NewTraefikDefaultPointersConfiguration() *TraefikConfiguration {
 		Etcd:               &defaultEtcd,
 		Zookeeper:          &defaultZookeeper,
 		Boltdb:             &defaultBoltDb,
+		Redis:              &defaultRedis,
 		Kubernetes:         &defaultKubernetes,
+		KubernetesCRD:      &defaultKubernetesCRD,
 		Mesos:              &defaultMesos,
 		ECS:                &defaultECS,
 		Rancher:            &defaultRancher,
 		Eureka:             &defaultEureka,
 		DynamoDB:           &defaultDynamoDB,
+		DNS:                &defaultDNS,
+		Nomad:              &defaultNomad,
+		HTTP:               &defaultHTTP,
 		Retry:              &configuration.Retry{},
 		HealthCheck:        &healthCheck,
 		RespondingTimeouts: &respondingTimeouts,
 		ForwardingTimeouts: &forwardingTimeouts,
+		RoutingDebug:       &defaultRoutingDebug,
 		TraefikLog:         &defaultTraefikLog,
 		AccessLog:          &defaultAccessLog,
 		LifeCycle:          &defaultLifeCycle,
@@ -1,9 +1,11 @@
 package healthcheck
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"time"
@@ -57,17 +59,24 @@ func Do(globalConfiguration configuration.GlobalConfiguration) (*http.Response,
 	}
 
 	client := &http.Client{Timeout: 5 * time.Second}
+	tr := &http.Transport{}
 	protocol := "http"
 	if pingEntryPoint.TLS != nil {
 		protocol = "https"
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	host := pingEntryPoint.Address
+	if network, address := pingEntryPoint.Network(); network == "unix" {
+		tr.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, address)
 		}
-		client.Transport = tr
+		host = "localhost"
 	}
+	client.Transport = tr
+
 	path := "/"
 	if globalConfiguration.Web != nil {
 		path = globalConfiguration.Web.Path
 	}
-	return client.Head(protocol + "://" + pingEntryPoint.Address + path + "ping")
+	return client.Head(protocol + "://" + host + path + "ping")
 }
@@ -0,0 +1,98 @@
+//go:build windows
+// +build windows
+
+package service
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// eventSourcesKey is the registry key of the event sources of the Application log.
+	eventSourcesKey = `SYSTEM\CurrentControlSet\Services\EventLog\Application\`
+	// eventMessageFile displays the messages of the events of ID 1 to 1000 as they are.
+	eventMessageFile = `%SystemRoot%\System32\EventCreate.exe`
+	eventID          = 1
+)
+
+var (
+	procRegCreateKeyExW = advapi32.NewProc("RegCreateKeyExW")
+	procRegSetValueExW  = advapi32.NewProc("RegSetValueExW")
+	procRegDeleteKeyW   = advapi32.NewProc("RegDeleteKeyW")
+)
+
+// eventLogHook writes the logs of the service to the Application event log.
+type eventLogHook struct {
+	handle windows.Handle
+}
+
+func newEventLogHook(source string) (*eventLogHook, error) {
+	handle, err := windows.RegisterEventSource(nil, windows.StringToUTF16Ptr(source))
+	if err != nil {
+		return nil, err
+	}
+	return &eventLogHook{handle: handle}, nil
+}
+
+// Levels returns the levels written to the event log, the debug logs being left out.
+func (h *eventLogHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel, logrus.InfoLevel}
+}
+
+func (h *eventLogHook) Fire(entry *logrus.Entry) error {
+	eventType := uint16(windows.EVENTLOG_INFORMATION_TYPE)
+	switch entry.Level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		eventType = windows.EVENTLOG_ERROR_TYPE
+	case logrus.WarnLevel:
+		eventType = windows.EVENTLOG_WARNING_TYPE
+	}
+
+	message, err := entry.String()
+	if err != nil {
+		return err
+	}
+	messages := []*uint16{windows.StringToUTF16Ptr(strings.Replace(strings.TrimSpace(message), "\x00", "", -1))}
+	return windows.ReportEvent(h.handle, eventType, 0, eventID, 0, 1, 0, &messages[0], nil)
+}
+
+// installEventSource registers the service as a source of the Application event log.
+func installEventSource(source string) error {
+	var key syscall.Handle
+	ret, _, _ := procRegCreateKeyExW.Call(uintptr(syscall.HKEY_LOCAL_MACHINE), uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(eventSourcesKey+source))),
+		0, 0, 0, uintptr(syscall.KEY_WRITE), 0, uintptr(unsafe.Pointer(&key)), 0)
+	if ret != 0 {
+		return fmt.Errorf("error creating event source %s: %v", source, syscall.Errno(ret))
+	}
+	defer syscall.RegCloseKey(key)
+
+	messageFile := syscall.StringToUTF16(eventMessageFile)
+	if err := setRegistryValue(key, "EventMessageFile", windows.REG_EXPAND_SZ, (*byte)(unsafe.Pointer(&messageFile[0])), uint32(len(messageFile)*2)); err != nil {
+		return err
+	}
+	typesSupported := uint32(windows.EVENTLOG_ERROR_TYPE | windows.EVENTLOG_WARNING_TYPE | windows.EVENTLOG_INFORMATION_TYPE)
+	return setRegistryValue(key, "TypesSupported", windows.REG_DWORD, (*byte)(unsafe.Pointer(&typesSupported)), 4)
+}
+
+func setRegistryValue(key syscall.Handle, name string, valueType uint32, data *byte, size uint32) error {
+	ret, _, _ := procRegSetValueExW.Call(uintptr(key), uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(name))), 0, uintptr(valueType), uintptr(unsafe.Pointer(data)), uintptr(size))
+	if ret != 0 {
+		return fmt.Errorf("error setting registry value %s: %v", name, syscall.Errno(ret))
+	}
+	return nil
+}
+
+// removeEventSource unregisters the service from the sources of the Application event log.
+func removeEventSource(source string) error {
+	ret, _, _ := procRegDeleteKeyW.Call(uintptr(syscall.HKEY_LOCAL_MACHINE), uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(eventSourcesKey+source))))
+	if ret != 0 {
+		return fmt.Errorf("error removing event source %s: %v", source, syscall.Errno(ret))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/containous/flaeg"
+)
+
+// Actions of the service command on the Windows service.
+const (
+	ActionInstall   = "install"
+	ActionUninstall = "uninstall"
+	ActionStart     = "start"
+	ActionStop      = "stop"
+)
+
+// Configuration is the configuration of the service command.
+type Configuration struct {
+	Action      string `description:"Action on the Windows service: install, uninstall, start or stop"`
+	Name        string `description:"Name of the Windows service"`
+	DisplayName string `description:"Display name of the Windows service"`
+	ConfigFile  string `description:"Configuration file used by the installed Windows service"`
+}
+
+// NewConfiguration returns the default configuration of the service command.
+func NewConfiguration() *Configuration {
+	return &Configuration{
+		Name:        "traefik",
+		DisplayName: "Træfik",
+	}
+}
+
+// NewCmd builds a new Service command, managing traefik as a Windows service.
+func NewCmd() *flaeg.Command {
+	config := NewConfiguration()
+	return &flaeg.Command{
+		Name:                  "service",
+		Description:           `Install, uninstall, start or stop traefik as a Windows service`,
+		Config:                config,
+		DefaultPointersConfig: NewConfiguration(),
+		Run: func() error {
+			return runCmd(config)
+		},
+	}
+}
+
+func runCmd(config *Configuration) error {
+	if len(config.Name) == 0 {
+		return fmt.Errorf("the name of the service is required")
+	}
+
+	switch config.Action {
+	case ActionInstall:
+		if err := install(config); err != nil {
+			return fmt.Errorf("error installing service %s: %v", config.Name, err)
+		}
+	case ActionUninstall:
+		if err := uninstall(config.Name); err != nil {
+			return fmt.Errorf("error uninstalling service %s: %v", config.Name, err)
+		}
+	case ActionStart:
+		if err := start(config.Name); err != nil {
+			return fmt.Errorf("error starting service %s: %v", config.Name, err)
+		}
+	case ActionStop:
+		if err := stop(config.Name); err != nil {
+			return fmt.Errorf("error stopping service %s: %v", config.Name, err)
+		}
+	default:
+		return fmt.Errorf("unknown action %q, must be one of %s, %s, %s or %s", config.Action, ActionInstall, ActionUninstall, ActionStart, ActionStop)
+	}
+
+	fmt.Printf("Service %s: %s done\n", config.Name, config.Action)
+	return nil
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+// +build !windows
+
+package service
+
+import "errors"
+
+var errNotWindows = errors.New("Windows services are only supported on Windows")
+
+// Service is traefik running as a Windows service.
+type Service struct{}
+
+// Start returns nil, as traefik only runs as a service on Windows.
+func Start(stop func()) (*Service, error) {
+	return nil, nil
+}
+
+// Stopped reports that traefik has stopped.
+func (s *Service) Stopped() {}
+
+func install(config *Configuration) error {
+	return errNotWindows
+}
+
+func uninstall(name string) error {
+	return errNotWindows
+}
+
+func start(name string) error {
+	return errNotWindows
+}
+
+func stop(name string) error {
+	return errNotWindows
+}
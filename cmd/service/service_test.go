@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCmd(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      *Configuration
+		expectedErr string
+	}{
+		{
+			desc:        "unknown action",
+			config:      &Configuration{Action: "restart", Name: "traefik"},
+			expectedErr: `unknown action "restart", must be one of install, uninstall, start or stop`,
+		},
+		{
+			desc:        "empty action",
+			config:      &Configuration{Name: "traefik"},
+			expectedErr: `unknown action "", must be one of install, uninstall, start or stop`,
+		},
+		{
+			desc:        "empty name",
+			config:      &Configuration{Action: ActionStart},
+			expectedErr: "the name of the service is required",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := runCmd(test.config)
+			require.Error(t, err)
+			assert.EqualError(t, err, test.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+//go:build windows
+// +build windows
+
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/containous/traefik/log"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// errFailedServiceControllerConnect is returned by the service control dispatcher of a process which is not a service.
+	errFailedServiceControllerConnect = syscall.Errno(1063)
+
+	// stopPendingInterval is the interval at which the progress of the stop is reported to the service control manager,
+	// for it not to consider the service as hung while the requests are drained.
+	stopPendingInterval = 5 * time.Second
+	// stopTimeout is the maximum time waited by the stop action for the service to stop.
+	stopTimeout = 2 * time.Minute
+)
+
+var (
+	advapi32                          = windows.NewLazySystemDLL("advapi32.dll")
+	procRegisterServiceCtrlHandlerExW = advapi32.NewProc("RegisterServiceCtrlHandlerExW")
+)
+
+// Service is traefik running as a Windows service.
+type Service struct {
+	stop     func()
+	started  chan error
+	stopped  chan struct{}
+	stopOnce sync.Once
+
+	lock   sync.Mutex
+	handle windows.Handle
+	status windows.SERVICE_STATUS
+}
+
+// current is the service started by the service control manager, reached by its callbacks.
+var current *Service
+
+// Start connects traefik to the Windows service control manager when it has been started by it:
+// the stop function is called when the service is stopped or when the system shuts down.
+// It returns a nil Service when traefik doesn't run as a service.
+func Start(stop func()) (*Service, error) {
+	current = &Service{
+		stop:    stop,
+		started: make(chan error, 2),
+		stopped: make(chan struct{}),
+	}
+
+	go func() {
+		// the name is ignored for the services running in their own process
+		table := []windows.SERVICE_TABLE_ENTRY{
+			{ServiceName: windows.StringToUTF16Ptr(""), ServiceProc: windows.NewCallback(serviceMain)},
+			{},
+		}
+		current.started <- windows.StartServiceCtrlDispatcher(&table[0])
+	}()
+
+	err := <-current.started
+	if err == errFailedServiceControllerConnect {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// Stopped reports to the service control manager that traefik has stopped.
+func (s *Service) Stopped() {
+	if s == nil {
+		return
+	}
+	close(s.stopped)
+	if err := s.setStatus(windows.SERVICE_STOPPED); err != nil {
+		log.Errorf("Error reporting the service status: %v", err)
+	}
+}
+
+// serviceMain is called by the service control dispatcher, in its own thread, when the service starts.
+func serviceMain(argc uintptr, argv **uint16) uintptr {
+	var name string
+	if argc > 0 {
+		name = syscall.UTF16ToString((*[1 << 16]uint16)(unsafe.Pointer(*argv))[:])
+	}
+
+	handle, _, err := procRegisterServiceCtrlHandlerExW.Call(uintptr(unsafe.Pointer(*argv)), windows.NewCallback(controlHandler), 0)
+	if handle == 0 {
+		current.started <- err
+		return 0
+	}
+	current.handle = windows.Handle(handle)
+
+	if hook, err := newEventLogHook(name); err != nil {
+		log.Errorf("Error opening the event log of service %s: %v", name, err)
+	} else {
+		log.AddHook(hook)
+	}
+
+	current.started <- current.setStatus(windows.SERVICE_RUNNING)
+	return 0
+}
+
+// controlHandler is called by the service control dispatcher on the requests of the service control manager.
+func controlHandler(control, eventType, eventData, context uintptr) uintptr {
+	switch uint32(control) {
+	case windows.SERVICE_CONTROL_STOP, windows.SERVICE_CONTROL_SHUTDOWN:
+		current.stopOnce.Do(func() {
+			log.Infof("Stop requested by the service control manager")
+			current.setStatus(windows.SERVICE_STOP_PENDING)
+			go current.reportStopPending()
+			go current.stop()
+		})
+	case windows.SERVICE_CONTROL_INTERROGATE:
+		current.lock.Lock()
+		state := current.status.CurrentState
+		current.lock.Unlock()
+		current.setStatus(state)
+	}
+	return 0
+}
+
+// reportStopPending reports the progress of the stop until traefik has stopped.
+func (s *Service) reportStopPending() {
+	ticker := time.NewTicker(stopPendingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopped:
+			return
+		case <-ticker.C:
+			s.setStatus(windows.SERVICE_STOP_PENDING)
+		}
+	}
+}
+
+func (s *Service) setStatus(state uint32) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.status.ServiceType = windows.SERVICE_WIN32_OWN_PROCESS
+	s.status.CurrentState = state
+	s.status.ControlsAccepted = 0
+	s.status.WaitHint = 0
+	switch state {
+	case windows.SERVICE_RUNNING:
+		s.status.ControlsAccepted = windows.SERVICE_ACCEPT_STOP | windows.SERVICE_ACCEPT_SHUTDOWN
+		s.status.CheckPoint = 0
+	case windows.SERVICE_STOP_PENDING:
+		// the check point is increased for the service control manager to see that the stop progresses
+		s.status.CheckPoint++
+		s.status.WaitHint = uint32(2 * stopPendingInterval / time.Millisecond)
+	default:
+		s.status.CheckPoint = 0
+	}
+	return windows.SetServiceStatus(s.handle, &s.status)
+}
+
+func install(config *Configuration) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	binaryPath := syscall.EscapeArg(exe)
+	if len(config.ConfigFile) > 0 {
+		configFile, err := filepath.Abs(config.ConfigFile)
+		if err != nil {
+			return err
+		}
+		binaryPath += " " + syscall.EscapeArg("--configFile="+configFile)
+	}
+
+	manager, err := windows.OpenSCManager(nil, nil, windows.SC_MANAGER_ALL_ACCESS)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseServiceHandle(manager)
+
+	service, err := windows.CreateService(manager, windows.StringToUTF16Ptr(config.Name), windows.StringToUTF16Ptr(config.DisplayName),
+		windows.SERVICE_ALL_ACCESS, windows.SERVICE_WIN32_OWN_PROCESS, windows.SERVICE_AUTO_START, windows.SERVICE_ERROR_NORMAL,
+		windows.StringToUTF16Ptr(binaryPath), nil, nil, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseServiceHandle(service)
+
+	description := windows.SERVICE_DESCRIPTION{
+		Description: windows.StringToUTF16Ptr("Træfik, a modern HTTP reverse proxy and load balancer"),
+	}
+	if err := windows.ChangeServiceConfig2(service, windows.SERVICE_CONFIG_DESCRIPTION, (*byte)(unsafe.Pointer(&description))); err != nil {
+		log.Warnf("Error setting the description of service %s: %v", config.Name, err)
+	}
+
+	if err := installEventSource(config.Name); err != nil {
+		windows.DeleteService(service)
+		return err
+	}
+	return nil
+}
+
+func uninstall(name string) error {
+	return withService(name, func(service windows.Handle) error {
+		if err := windows.DeleteService(service); err != nil {
+			return err
+		}
+		return removeEventSource(name)
+	})
+}
+
+func start(name string) error {
+	return withService(name, func(service windows.Handle) error {
+		return windows.StartService(service, 0, nil)
+	})
+}
+
+// stop requests the service to stop, and waits for it to have drained its requests.
+func stop(name string) error {
+	return withService(name, func(service windows.Handle) error {
+		var status windows.SERVICE_STATUS
+		if err := windows.ControlService(service, windows.SERVICE_CONTROL_STOP, &status); err != nil {
+			return err
+		}
+
+		deadline := time.Now().Add(stopTimeout)
+		for status.CurrentState != windows.SERVICE_STOPPED {
+			if time.Now().After(deadline) {
+				return errors.New("timeout waiting for the service to stop")
+			}
+			time.Sleep(300 * time.Millisecond)
+			if err := windows.QueryServiceStatus(service, &status); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func withService(name string, fn func(service windows.Handle) error) error {
+	manager, err := windows.OpenSCManager(nil, nil, windows.SC_MANAGER_ALL_ACCESS)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseServiceHandle(manager)
+
+	service, err := windows.OpenService(manager, windows.StringToUTF16Ptr(name), windows.SERVICE_ALL_ACCESS)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseServiceHandle(service)
+
+	return fn(service)
+}
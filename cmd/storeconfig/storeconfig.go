@@ -147,6 +147,12 @@ func CreateKvSource(traefikConfiguration *cmd.TraefikConfiguration) (*staert.KvS
 			Store:  kvStore,
 			Prefix: traefikConfiguration.Boltdb.Prefix,
 		}
+	case traefikConfiguration.Redis != nil:
+		kvStore, err = traefikConfiguration.Redis.CreateStore()
+		kv = &staert.KvSource{
+			Store:  kvStore,
+			Prefix: traefikConfiguration.Redis.Prefix,
+		}
 	}
 	return kv, err
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	fmtlog "log"
 	"net/http"
 	"os"
@@ -17,13 +18,16 @@ import (
 	"github.com/containous/traefik/cmd"
 	"github.com/containous/traefik/cmd/bug"
 	"github.com/containous/traefik/cmd/healthcheck"
+	"github.com/containous/traefik/cmd/service"
 	"github.com/containous/traefik/cmd/storeconfig"
 	cmdVersion "github.com/containous/traefik/cmd/version"
 	"github.com/containous/traefik/collector"
 	"github.com/containous/traefik/configuration"
 	"github.com/containous/traefik/job"
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/provider/consulcatalog"
 	"github.com/containous/traefik/provider/ecs"
+	"github.com/containous/traefik/provider/file"
 	"github.com/containous/traefik/provider/kubernetes"
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/server"
@@ -38,6 +42,7 @@ import (
 
 func main() {
 	// traefik config inits
+	var staticConfigurationLoader func() (*configuration.GlobalConfiguration, error)
 	traefikConfiguration := cmd.NewTraefikConfiguration()
 	traefikPointersConfiguration := cmd.NewTraefikDefaultPointersConfiguration()
 
@@ -49,7 +54,7 @@ Complete documentation is available at https://traefik.io`,
 		Config:                traefikConfiguration,
 		DefaultPointersConfig: traefikPointersConfiguration,
 		Run: func() error {
-			runCmd(&traefikConfiguration.GlobalConfiguration, traefikConfiguration.ConfigFile)
+			runCmd(&traefikConfiguration.GlobalConfiguration, traefikConfiguration.ConfigFile, staticConfigurationLoader)
 			return nil
 		},
 	}
@@ -60,20 +65,14 @@ Complete documentation is available at https://traefik.io`,
 	// init flaeg source
 	f := flaeg.New(traefikCmd, os.Args[1:])
 	// add custom parsers
-	f.AddParser(reflect.TypeOf(configuration.EntryPoints{}), &configuration.EntryPoints{})
-	f.AddParser(reflect.TypeOf(configuration.DefaultEntryPoints{}), &configuration.DefaultEntryPoints{})
-	f.AddParser(reflect.TypeOf(traefikTls.RootCAs{}), &traefikTls.RootCAs{})
-	f.AddParser(reflect.TypeOf(types.Constraints{}), &types.Constraints{})
-	f.AddParser(reflect.TypeOf(kubernetes.Namespaces{}), &kubernetes.Namespaces{})
-	f.AddParser(reflect.TypeOf(ecs.Clusters{}), &ecs.Clusters{})
-	f.AddParser(reflect.TypeOf([]acme.Domain{}), &acme.Domains{})
-	f.AddParser(reflect.TypeOf(types.Buckets{}), &types.Buckets{})
+	addParsers(f)
 
 	// add commands
 	f.AddCommand(cmdVersion.NewCmd())
 	f.AddCommand(bug.NewCmd(traefikConfiguration, traefikPointersConfiguration))
 	f.AddCommand(storeConfigCmd)
 	f.AddCommand(healthcheck.NewCmd(traefikConfiguration, traefikPointersConfiguration))
+	f.AddCommand(service.NewCmd())
 
 	usedCmd, err := f.GetCommand()
 	if err != nil {
@@ -111,6 +110,13 @@ Complete documentation is available at https://traefik.io`,
 	}
 	storeConfigCmd.Run = storeconfig.Run(kv, traefikConfiguration)
 
+	// the static configuration stored in a KV store isn't reloaded
+	if kv == nil {
+		staticConfigurationLoader = func() (*configuration.GlobalConfiguration, error) {
+			return loadStaticConfiguration(os.Args[1:])
+		}
+	}
+
 	// if a KV Store is enable and no sub-command called in args
 	if kv != nil && usedCmd == traefikCmd {
 		if traefikConfiguration.Cluster == nil {
@@ -142,7 +148,64 @@ Complete documentation is available at https://traefik.io`,
 	os.Exit(0)
 }
 
-func runCmd(globalConfiguration *configuration.GlobalConfiguration, configFile string) {
+// addParsers adds the parsers of the custom types of the configuration.
+func addParsers(f *flaeg.Flaeg) {
+	f.AddParser(reflect.TypeOf(configuration.EntryPoints{}), &configuration.EntryPoints{})
+	f.AddParser(reflect.TypeOf(configuration.DefaultEntryPoints{}), &configuration.DefaultEntryPoints{})
+	f.AddParser(reflect.TypeOf(traefikTls.RootCAs{}), &traefikTls.RootCAs{})
+	f.AddParser(reflect.TypeOf(types.Constraints{}), &types.Constraints{})
+	f.AddParser(reflect.TypeOf(kubernetes.Namespaces{}), &kubernetes.Namespaces{})
+	f.AddParser(reflect.TypeOf(kubernetes.IngressClasses{}), &kubernetes.IngressClasses{})
+	f.AddParser(reflect.TypeOf(ecs.Clusters{}), &ecs.Clusters{})
+	f.AddParser(reflect.TypeOf(consulcatalog.Datacenters{}), &consulcatalog.Datacenters{})
+	f.AddParser(reflect.TypeOf(file.Globs{}), &file.Globs{})
+	f.AddParser(reflect.TypeOf([]acme.Domain{}), &acme.Domains{})
+	f.AddParser(reflect.TypeOf(acme.CAServerRules{}), &acme.CAServerRules{})
+	f.AddParser(reflect.TypeOf(acme.KeyTypeRules{}), &acme.KeyTypeRules{})
+	f.AddParser(reflect.TypeOf(acme.Resolvers{}), &acme.Resolvers{})
+	f.AddParser(reflect.TypeOf(types.Buckets{}), &types.Buckets{})
+	f.AddParser(reflect.TypeOf(types.Webhooks{}), &types.Webhooks{})
+	f.AddParser(reflect.TypeOf(types.TenantQuotas{}), &types.TenantQuotas{})
+	f.AddParser(reflect.TypeOf(types.PriorityClasses{}), &types.PriorityClasses{})
+	f.AddParser(reflect.TypeOf(types.ProviderPriorities{}), &types.ProviderPriorities{})
+}
+
+// loadStaticConfiguration reads the static configuration again from the TOML file and the flags,
+// for the server to apply it on SIGHUP.
+func loadStaticConfiguration(args []string) (*configuration.GlobalConfiguration, error) {
+	traefikConfiguration := cmd.NewTraefikConfiguration()
+	traefikCmd := &flaeg.Command{
+		Name:                  "traefik",
+		Config:                traefikConfiguration,
+		DefaultPointersConfig: cmd.NewTraefikDefaultPointersConfiguration(),
+		Run: func() error {
+			return nil
+		},
+	}
+
+	f := flaeg.New(traefikCmd, args)
+	addParsers(f)
+	if _, err := f.Parse(traefikCmd); err != nil {
+		return nil, err
+	}
+
+	s := staert.NewStaert(traefikCmd)
+	toml := staert.NewTomlSource("traefik", []string{traefikConfiguration.ConfigFile, "/etc/traefik/", "$HOME/.traefik/", "."})
+	s.AddSource(toml)
+	s.AddSource(f)
+	if _, err := s.LoadConfig(); err != nil {
+		return nil, fmt.Errorf("error reading TOML config file %s: %v", toml.ConfigFileUsed(), err)
+	}
+
+	globalConfiguration := &traefikConfiguration.GlobalConfiguration
+	globalConfiguration.SetEffectiveConfiguration(toml.ConfigFileUsed())
+	if err := globalConfiguration.Validate(); err != nil {
+		return nil, err
+	}
+	return globalConfiguration, nil
+}
+
+func runCmd(globalConfiguration *configuration.GlobalConfiguration, configFile string, staticConfigurationLoader func() (*configuration.GlobalConfiguration, error)) {
 	configureLogging(globalConfiguration)
 
 	if len(configFile) > 0 {
@@ -165,9 +228,15 @@ func runCmd(globalConfiguration *configuration.GlobalConfiguration, configFile s
 
 	log.Debugf("Global configuration loaded %s", string(jsonConf))
 	svr := server.NewServer(*globalConfiguration, configuration.NewProviderAggregator(globalConfiguration))
+	svr.SetStaticConfigurationLoader(staticConfigurationLoader)
 	svr.Start()
 	defer svr.Close()
 
+	winService, err := service.Start(svr.StopGracefully)
+	if err != nil {
+		log.Errorf("Error starting the Windows service: %v", err)
+	}
+
 	sent, err := daemon.SdNotify(false, "READY=1")
 	if !sent && err != nil {
 		log.Error("Fail to notify", err)
@@ -197,6 +266,7 @@ func runCmd(globalConfiguration *configuration.GlobalConfiguration, configFile s
 
 	svr.Wait()
 	log.Info("Shutting down")
+	winService.Stopped()
 	logrus.Exit(0)
 }
 
@@ -15,16 +15,21 @@ import (
 	"github.com/containous/traefik/provider/boltdb"
 	"github.com/containous/traefik/provider/consul"
 	"github.com/containous/traefik/provider/consulcatalog"
+	"github.com/containous/traefik/provider/dns"
 	"github.com/containous/traefik/provider/docker"
 	"github.com/containous/traefik/provider/dynamodb"
 	"github.com/containous/traefik/provider/ecs"
 	"github.com/containous/traefik/provider/etcd"
 	"github.com/containous/traefik/provider/eureka"
 	"github.com/containous/traefik/provider/file"
+	"github.com/containous/traefik/provider/http"
 	"github.com/containous/traefik/provider/kubernetes"
+	"github.com/containous/traefik/provider/kubernetes/crd"
 	"github.com/containous/traefik/provider/marathon"
 	"github.com/containous/traefik/provider/mesos"
+	"github.com/containous/traefik/provider/nomad"
 	"github.com/containous/traefik/provider/rancher"
+	"github.com/containous/traefik/provider/redis"
 	"github.com/containous/traefik/provider/rest"
 	"github.com/containous/traefik/provider/zk"
 	"github.com/containous/traefik/tls"
@@ -44,9 +49,18 @@ const (
 	// DefaultIdleTimeout before closing an idle connection.
 	DefaultIdleTimeout = 180 * time.Second
 
+	// DefaultIdleConnTimeout before closing an idle connection to a backend server.
+	DefaultIdleConnTimeout = 90 * time.Second
+
+	// DefaultUDPSessionTimeout before forgetting the backend server of an idle UDP client.
+	DefaultUDPSessionTimeout = 3 * time.Second
+
 	// DefaultGraceTimeout controls how long Traefik serves pending requests
 	// prior to shutting down.
 	DefaultGraceTimeout = 10 * time.Second
+
+	// DefaultRoutingDebugHeader is the default request header enabling the routing debug.
+	DefaultRoutingDebugHeader = "X-Traefik-Debug"
 )
 
 // GlobalConfiguration holds global configuration (with providers, etc.).
@@ -61,22 +75,36 @@ type GlobalConfiguration struct {
 	AccessLog                 *types.AccessLog        `description:"Access log settings" export:"true"`
 	TraefikLogsFile           string                  `description:"(Deprecated) Traefik logs file. Stdout is used when omitted or empty" export:"true"` // Deprecated
 	TraefikLog                *types.TraefikLog       `description:"Traefik log settings" export:"true"`
+	AuditLog                  *types.AuditLog         `description:"Audit log settings" export:"true"`
+	Notifications             *types.Notifications    `description:"Notifications of operational events" export:"true"`
+	CTMonitor                 *types.CTMonitor        `description:"Monitor the Certificate Transparency logs for unexpected certificates" export:"true"`
+	OCSP                      *types.OCSP             `description:"Staple the OCSP responses of the served certificates" export:"true"`
+	SessionTickets            *types.SessionTickets   `description:"Rotate the TLS session ticket keys, shared by the nodes in cluster mode" export:"true"`
+	Tenancy                   *types.Tenancy          `description:"Quotas of the tenants of the frontends" export:"true"`
+	Overload                  *types.Overload         `description:"Queue the requests by priority beyond a concurrency limit" export:"true"`
+	ProvidersMerge            *types.ProvidersMerge   `description:"Priorities of the providers and policy applied to the frontends defined by several of them" export:"true"`
 	Tracing                   *tracing.Tracing        `description:"OpenTracing configuration" export:"true"`
 	LogLevel                  string                  `short:"l" description:"Log level" export:"true"`
 	EntryPoints               EntryPoints             `description:"Entrypoints definition using format: --entryPoints='Name:http Address::8000 Redirect.EntryPoint:https' --entryPoints='Name:https Address::4442 TLS:tests/traefik.crt,tests/traefik.key;prod/traefik.crt,prod/traefik.key'" export:"true"`
+	EntryPointTemplates       EntryPoints             `description:"Entrypoint templates extended by the entrypoints, using the entrypoints format: --entryPointTemplates='Name:base ProxyProtocol.TrustedIPs:10.0.0.0/8' --entryPoints='Name:http Address::80 Extends:base'" export:"true"`
 	Cluster                   *types.Cluster          `description:"Enable clustering" export:"true"`
 	Constraints               types.Constraints       `description:"Filter services by constraint, matching with service tags" export:"true"`
 	ACME                      *acme.ACME              `description:"Enable ACME (Let's Encrypt): automatic SSL" export:"true"`
+	ACMEResolvers             acme.Resolvers          `description:"Named ACME resolvers selected by the frontends: 'Name:internal CAServer:https://ca.example.com/acme/acme/directory Email:ops@example.com Storage:internal.json EntryPoint:https'" export:"true"`
+	VaultPKI                  *types.VaultPKI         `description:"Issue the certificates from the PKI secrets engine of Vault" export:"true"`
 	DefaultEntryPoints        DefaultEntryPoints      `description:"Entrypoints to be used by frontends that do not specify any entrypoint" export:"true"`
 	ProvidersThrottleDuration flaeg.Duration          `description:"Backends throttle duration: minimum duration between 2 events from providers before applying a new configuration. It avoids unnecessary reloads if multiples events are sent in a short amount of time." export:"true"`
 	MaxIdleConnsPerHost       int                     `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host.  If zero, DefaultMaxIdleConnsPerHost is used" export:"true"`
 	IdleTimeout               flaeg.Duration          `description:"(Deprecated) maximum amount of time an idle (keep-alive) connection will remain idle before closing itself." export:"true"` // Deprecated
 	InsecureSkipVerify        bool                    `description:"Disable SSL certificate verification" export:"true"`
 	RootCAs                   tls.RootCAs             `description:"Add cert file for self-signed certificate"`
+	DefaultCertificate        *tls.DefaultCertificate `description:"Generated certificate served when no certificate matches the SNI" export:"true"`
 	Retry                     *Retry                  `description:"Enable retry sending request if network error" export:"true"`
 	HealthCheck               *HealthCheckConfig      `description:"Health check parameters" export:"true"`
 	RespondingTimeouts        *RespondingTimeouts     `description:"Timeouts for incoming requests to the Traefik instance" export:"true"`
 	ForwardingTimeouts        *ForwardingTimeouts     `description:"Timeouts for requests forwarded to the backend servers" export:"true"`
+	RoutingDebug              *RoutingDebug           `description:"Describe the routing of requests carrying a secret header in response headers" export:"true"`
+	DefaultMiddlewares        *DefaultMiddlewares     `description:"Middlewares applied to the frontends of all the providers, before their own middlewares" export:"true"`
 	Web                       *WebCompatibility       `description:"(Deprecated) Enable Web backend with default settings" export:"true"` // Deprecated
 	Docker                    *docker.Provider        `description:"Enable Docker backend with default settings" export:"true"`
 	File                      *file.Provider          `description:"Enable File backend with default settings" export:"true"`
@@ -86,13 +114,18 @@ type GlobalConfiguration struct {
 	Etcd                      *etcd.Provider          `description:"Enable Etcd backend with default settings" export:"true"`
 	Zookeeper                 *zk.Provider            `description:"Enable Zookeeper backend with default settings" export:"true"`
 	Boltdb                    *boltdb.Provider        `description:"Enable Boltdb backend with default settings" export:"true"`
+	Redis                     *redis.Provider         `description:"Enable Redis backend with default settings" export:"true"`
 	Kubernetes                *kubernetes.Provider    `description:"Enable Kubernetes backend with default settings" export:"true"`
+	KubernetesCRD             *crd.Provider           `description:"Enable Kubernetes CRD backend with default settings" export:"true"`
 	Mesos                     *mesos.Provider         `description:"Enable Mesos backend with default settings" export:"true"`
 	Eureka                    *eureka.Provider        `description:"Enable Eureka backend with default settings" export:"true"`
 	ECS                       *ecs.Provider           `description:"Enable ECS backend with default settings" export:"true"`
 	Rancher                   *rancher.Provider       `description:"Enable Rancher backend with default settings" export:"true"`
 	DynamoDB                  *dynamodb.Provider      `description:"Enable DynamoDB backend with default settings" export:"true"`
 	ServiceFabric             *servicefabric.Provider `description:"Enable Service Fabric backend with default settings" export:"true"`
+	DNS                       *dns.Provider           `description:"Enable DNS backend with default settings" export:"true"`
+	Nomad                     *nomad.Provider         `description:"Enable Nomad backend with default settings" export:"true"`
+	HTTP                      *http.Provider          `description:"Enable HTTP backend with default settings" export:"true"`
 	Rest                      *rest.Provider          `description:"Enable Rest backend with default settings" export:"true"`
 	API                       *api.Handler            `description:"Enable api/dashboard" export:"true"`
 	Metrics                   *types.Metrics          `description:"Enable a metrics exporter" export:"true"`
@@ -181,6 +214,15 @@ func (gc *GlobalConfiguration) SetEffectiveConfiguration(configFile string) {
 		}
 	}
 
+	for entryPointName, entryPoint := range gc.EntryPoints {
+		// the unknown templates are reported by ValidateConfiguration
+		if template, ok := gc.EntryPointTemplates[entryPoint.Extends]; ok && len(entryPoint.Extends) > 0 {
+			if err := entryPoint.extend(template); err != nil {
+				log.Errorf("Error extending the template %s on entrypoint %s: %v", entryPoint.Extends, entryPointName, err)
+			}
+		}
+	}
+
 	// ForwardedHeaders must be remove in the next breaking version
 	for entryPointName := range gc.EntryPoints {
 		entryPoint := gc.EntryPoints[entryPointName]
@@ -260,19 +302,159 @@ func (gc *GlobalConfiguration) SetEffectiveConfiguration(configFile string) {
 			log.Warn("ACME.OnDemand is deprecated")
 		}
 	}
+
+	if gc.RoutingDebug != nil {
+		if len(gc.RoutingDebug.Secret) == 0 {
+			log.Error("Routing debug is disabled: no secret defined")
+			gc.RoutingDebug = nil
+		} else if len(gc.RoutingDebug.Header) == 0 {
+			gc.RoutingDebug.Header = DefaultRoutingDebugHeader
+		}
+	}
+
+	if gc.Overload != nil && gc.Overload.MaxConcurrency <= 0 {
+		log.Error("Overload protection is disabled: no maximum concurrency defined")
+		gc.Overload = nil
+	}
 }
 
 // ValidateConfiguration validate that configuration is coherent
 func (gc *GlobalConfiguration) ValidateConfiguration() {
-	if gc.ACME != nil {
-		if _, ok := gc.EntryPoints[gc.ACME.EntryPoint]; !ok {
-			log.Fatalf("Unknown entrypoint %q for ACME configuration", gc.ACME.EntryPoint)
-		} else {
-			if gc.EntryPoints[gc.ACME.EntryPoint].TLS == nil {
-				log.Fatalf("Entrypoint without TLS %q for ACME configuration", gc.ACME.EntryPoint)
+	if err := gc.Validate(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Validate returns an error when the configuration is not coherent.
+func (gc *GlobalConfiguration) Validate() error {
+	for templateName, template := range gc.EntryPointTemplates {
+		if len(template.Extends) > 0 {
+			return fmt.Errorf("entrypoint template %q can't extend another template", templateName)
+		}
+	}
+
+	for entryPointName, entryPoint := range gc.EntryPoints {
+		if _, ok := gc.EntryPointTemplates[entryPoint.Extends]; len(entryPoint.Extends) > 0 && !ok {
+			return fmt.Errorf("unknown template %q extended by entrypoint %q", entryPoint.Extends, entryPointName)
+		}
+
+		switch entryPoint.Protocol {
+		case "", EntryPointProtocolHTTP:
+			if entryPoint.HTTP2 != nil && entryPoint.HTTP2.Cleartext {
+				if entryPoint.TLS != nil {
+					return fmt.Errorf("TLS entrypoint %q can't serve HTTP/2 cleartext", entryPointName)
+				}
+				if entryPoint.HTTP2.Disabled {
+					return fmt.Errorf("entrypoint %q can't serve HTTP/2 cleartext with HTTP/2 disabled", entryPointName)
+				}
+			}
+		case EntryPointProtocolTCP:
+			if entryPoint.MaxConcurrentRequests > 0 {
+				return fmt.Errorf("TCP entrypoint %q can't limit its concurrent requests", entryPointName)
+			}
+			if entryPoint.MaxRequestBodyBytes > 0 {
+				return fmt.Errorf("TCP entrypoint %q can't limit the size of its request bodies", entryPointName)
+			}
+			if entryPoint.Headers != nil {
+				return fmt.Errorf("TCP entrypoint %q can't set HTTP headers", entryPointName)
+			}
+			if entryPoint.Drain != nil && (entryPoint.Drain.CloseConnections || entryPoint.Drain.RejectRequests) {
+				return fmt.Errorf("TCP entrypoint %q can't close connections or reject requests while draining", entryPointName)
 			}
+		case EntryPointProtocolUDP:
+			if entryPoint.TLS != nil {
+				return fmt.Errorf("UDP entrypoint %q can't use TLS", entryPointName)
+			}
+			if entryPoint.MaxConnections > 0 || entryPoint.MaxConcurrentRequests > 0 || entryPoint.MaxRequestBodyBytes > 0 {
+				return fmt.Errorf("UDP entrypoint %q can't limit its connections, concurrent requests or request bodies", entryPointName)
+			}
+			if network, _ := entryPoint.Network(); network != "tcp" {
+				return fmt.Errorf("UDP entrypoint %q can't listen on a Unix domain socket", entryPointName)
+			}
+			if len(entryPoint.Addresses) > 0 {
+				return fmt.Errorf("UDP entrypoint %q can't listen on several addresses", entryPointName)
+			}
+			if entryPoint.Headers != nil {
+				return fmt.Errorf("UDP entrypoint %q can't set HTTP headers", entryPointName)
+			}
+			if entryPoint.Drain != nil && (entryPoint.Drain.CloseConnections || entryPoint.Drain.RejectRequests) {
+				return fmt.Errorf("UDP entrypoint %q can't close connections or reject requests while draining", entryPointName)
+			}
+		default:
+			return fmt.Errorf("unknown protocol %q for entrypoint %q", entryPoint.Protocol, entryPointName)
+		}
+	}
+	for _, entryPointName := range gc.DefaultEntryPoints {
+		if entryPoint, ok := gc.EntryPoints[entryPointName]; ok && (entryPoint.Protocol == EntryPointProtocolTCP || entryPoint.Protocol == EntryPointProtocolUDP) {
+			return fmt.Errorf("%s entrypoint %q can't be a default entrypoint", strings.ToUpper(entryPoint.Protocol), entryPointName)
 		}
 	}
+	if gc.ACME != nil {
+		if err := gc.validateACME(gc.ACME, "ACME"); err != nil {
+			return err
+		}
+	}
+	resolverNames := make(map[string]bool)
+	storages := make(map[string]bool)
+	if gc.ACME != nil {
+		storages[gc.ACME.Storage] = true
+	}
+	for _, resolver := range gc.ACMEResolvers {
+		description := fmt.Sprintf("ACME resolver %q", resolver.Name)
+		if resolverNames[resolver.Name] {
+			return fmt.Errorf("duplicate %s", description)
+		}
+		resolverNames[resolver.Name] = true
+		if storages[resolver.Storage] || len(resolver.Storage) == 0 {
+			return fmt.Errorf("%s must have its own storage", description)
+		}
+		storages[resolver.Storage] = true
+		if err := gc.validateACME(&resolver.ACME, description); err != nil {
+			return err
+		}
+	}
+	if gc.ProvidersMerge != nil {
+		switch gc.ProvidersMerge.ConflictPolicy {
+		case "", types.ConflictPolicyError, types.ConflictPolicyFirstWins, types.ConflictPolicyMerge:
+		default:
+			return fmt.Errorf("unknown providers conflict policy %q", gc.ProvidersMerge.ConflictPolicy)
+		}
+	}
+	if gc.VaultPKI != nil {
+		entryPoint, ok := gc.EntryPoints[gc.VaultPKI.EntryPoint]
+		if !ok {
+			return fmt.Errorf("unknown entrypoint %q for Vault PKI configuration", gc.VaultPKI.EntryPoint)
+		}
+		if entryPoint.TLS == nil {
+			return fmt.Errorf("entrypoint without TLS %q for Vault PKI configuration", gc.VaultPKI.EntryPoint)
+		}
+		if len(gc.VaultPKI.Storage) > 0 && storages[gc.VaultPKI.Storage] {
+			return fmt.Errorf("Vault PKI storage %q is an ACME storage", gc.VaultPKI.Storage)
+		}
+	}
+	return nil
+}
+
+func (gc *GlobalConfiguration) validateACME(acmeConfig *acme.ACME, description string) error {
+	if err := acmeConfig.ValidateKeyTypes(); err != nil {
+		return fmt.Errorf("%s configuration: %v", description, err)
+	}
+	if err := acmeConfig.ValidateOnDemandRules(); err != nil {
+		return fmt.Errorf("%s configuration: %v", description, err)
+	}
+	entryPoint, ok := gc.EntryPoints[acmeConfig.EntryPoint]
+	if !ok {
+		return fmt.Errorf("unknown entrypoint %q for %s configuration", acmeConfig.EntryPoint, description)
+	}
+	if entryPoint.TLS == nil {
+		return fmt.Errorf("entrypoint without TLS %q for %s configuration", acmeConfig.EntryPoint, description)
+	}
+	if acmeConfig.HTTPChallenge != nil && len(acmeConfig.HTTPChallenge.EntryPoint) > 0 {
+		if _, ok := gc.EntryPoints[acmeConfig.HTTPChallenge.EntryPoint]; !ok {
+			return fmt.Errorf("unknown entrypoint %q for %s HTTP challenge", acmeConfig.HTTPChallenge.EntryPoint, description)
+		}
+	}
+	return nil
 }
 
 // DefaultEntryPoints holds default entry points
@@ -334,6 +516,25 @@ type RespondingTimeouts struct {
 type ForwardingTimeouts struct {
 	DialTimeout           flaeg.Duration `description:"The amount of time to wait until a connection to a backend server can be established. Defaults to 30 seconds. If zero, no timeout exists" export:"true"`
 	ResponseHeaderTimeout flaeg.Duration `description:"The amount of time to wait for a server's response headers after fully writing the request (including its body, if any). If zero, no timeout exists" export:"true"`
+	IdleConnTimeout       flaeg.Duration `description:"The maximum amount of time an idle (keep-alive) connection to a backend server will remain idle before closing itself. Defaults to 90 seconds. If zero, no timeout exists" export:"true"`
+}
+
+// RoutingDebug contains the configuration of the routing debug:
+// requests carrying the header with the secret value get response headers describing their routing.
+type RoutingDebug struct {
+	Header string `description:"Request header enabling the routing debug" export:"true"`
+	Secret string `description:"Secret value of the request header"`
+}
+
+// DefaultMiddlewares contains the middlewares applied to the frontends of all the providers, before their own middlewares,
+// to enforce platform-wide policies.
+type DefaultMiddlewares struct {
+	WhitelistSourceRange []string           `description:"IP ranges allowed to reach the frontends" export:"true"`
+	WhitelistIPStrategy  *types.IPStrategy  `description:"Strategy getting the IP of the client checked by the whitelist" export:"true"`
+	RequestIDHeader      string             `description:"Request header set to a unique ID when missing, and returned in the response" export:"true"`
+	Headers              *types.Headers     `description:"Custom and security headers of the frontends" export:"true"`
+	Compress             bool               `description:"Compress the responses" export:"true"`
+	Compression          *types.Compression `description:"Filter the compressed responses" export:"true"`
 }
 
 // ProxyProtocol contains Proxy-Protocol configuration
@@ -348,6 +549,38 @@ type ForwardedHeaders struct {
 	TrustedIPs []string
 }
 
+// KeepAlive contains the keep-alive configuration of the client connections of an entry point,
+// allowing long-lived connections to be recycled.
+type KeepAlive struct {
+	MaxRequests int            `description:"Maximum number of requests served on a connection before closing it. If zero, no limit is set" export:"true"`
+	MaxAge      flaeg.Duration `description:"Maximum duration a connection is kept open, the connection being closed after its current request. If zero, no limit is set" export:"true"`
+	TCPPeriod   flaeg.Duration `description:"Period of the TCP keep-alive probes. If zero, the default period is used. If negative, the probes are disabled" export:"true"`
+}
+
+// Drain contains the configuration of the drain of an entry point on shutdown.
+type Drain struct {
+	Timeout          flaeg.Duration `description:"Duration to give the in-flight requests a chance to finish before the entry point is closed. Defaults to the grace timeout of the life cycle" export:"true"`
+	CloseConnections bool           `description:"Serve the requests arriving during the drain with a Connection: close header" export:"true"`
+	RejectRequests   bool           `description:"Reject the requests arriving during the drain with a 503 status code and a Connection: close header" export:"true"`
+}
+
+// RequestLimits contains the limits of the requests accepted by an entry point.
+type RequestLimits struct {
+	MaxHeaderBytes int `description:"Maximum size of the request line and headers, larger requests being rejected with a 431 status code. If zero, the default of 1MB is used" export:"true"`
+	MaxHeaderCount int `description:"Maximum number of header fields, requests with more fields being rejected with a 431 status code. If zero, no limit is set" export:"true"`
+	MaxURILength   int `description:"Maximum length of the request URI, longer URIs being rejected with a 414 status code. If zero, no limit is set" export:"true"`
+}
+
+// HTTP2 contains the HTTP/2 settings of an entry point, a zero value keeping the default.
+type HTTP2 struct {
+	Disabled                    bool `description:"Disable HTTP/2, the clients using HTTP/1.1" export:"true"`
+	Cleartext                   bool `description:"Serve HTTP/2 without TLS (h2c) to the clients starting with the HTTP/2 connection preface" export:"true"`
+	MaxConcurrentStreams        int  `description:"Maximum number of concurrent streams per connection. Defaults to 250" export:"true"`
+	InitialConnectionWindowSize int  `description:"Initial flow control window size of a connection, in bytes. Defaults to 1MB" export:"true"`
+	InitialStreamWindowSize     int  `description:"Initial flow control window size of a stream, in bytes. Defaults to 1MB" export:"true"`
+	MaxFrameSize                int  `description:"Maximum size of the frames read, between 16KB and 16MB. Defaults to 1MB" export:"true"`
+}
+
 // LifeCycle contains configurations relevant to the lifecycle (such as the
 // shutdown phase) of Traefik.
 type LifeCycle struct {
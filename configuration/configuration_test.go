@@ -5,8 +5,12 @@ import (
 	"time"
 
 	"github.com/containous/flaeg"
+	"github.com/containous/traefik/acme"
 	"github.com/containous/traefik/provider"
 	"github.com/containous/traefik/provider/file"
+	"github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
 )
 
 const defaultConfigFile = "traefik.toml"
@@ -96,3 +100,217 @@ func TestSetEffectiveConfigurationFileProviderFilename(t *testing.T) {
 		})
 	}
 }
+
+func TestSetEffectiveConfigurationEntryPointTemplates(t *testing.T) {
+	templates := EntryPoints{
+		"base": &EntryPoint{
+			Compress:      true,
+			Auth:          &types.Auth{HeaderField: "X-WebAuth-User"},
+			ProxyProtocol: &ProxyProtocol{TrustedIPs: []string{"10.0.0.0/8"}},
+			RespondingTimeouts: &RespondingTimeouts{
+				IdleTimeout: flaeg.Duration(time.Minute),
+			},
+			ForwardedHeaders: &ForwardedHeaders{TrustedIPs: []string{"10.0.0.0/8"}},
+		},
+	}
+
+	tests := []struct {
+		desc       string
+		entryPoint *EntryPoint
+		expected   *EntryPoint
+	}{
+		{
+			desc:       "no template",
+			entryPoint: &EntryPoint{Address: ":80"},
+			expected: &EntryPoint{
+				Address:          ":80",
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			desc:       "options inherited",
+			entryPoint: &EntryPoint{Address: ":80", Extends: "base"},
+			expected: &EntryPoint{
+				Address:       ":80",
+				Extends:       "base",
+				Compress:      true,
+				Auth:          &types.Auth{HeaderField: "X-WebAuth-User"},
+				ProxyProtocol: &ProxyProtocol{TrustedIPs: []string{"10.0.0.0/8"}},
+				RespondingTimeouts: &RespondingTimeouts{
+					IdleTimeout: flaeg.Duration(time.Minute),
+				},
+				ForwardedHeaders: &ForwardedHeaders{TrustedIPs: []string{"10.0.0.0/8"}},
+			},
+		},
+		{
+			desc: "sections overridden as a whole",
+			entryPoint: &EntryPoint{
+				Address: ":80",
+				Extends: "base",
+				RespondingTimeouts: &RespondingTimeouts{
+					ReadTimeout: flaeg.Duration(time.Second),
+				},
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+			expected: &EntryPoint{
+				Address:       ":80",
+				Extends:       "base",
+				Compress:      true,
+				Auth:          &types.Auth{HeaderField: "X-WebAuth-User"},
+				ProxyProtocol: &ProxyProtocol{TrustedIPs: []string{"10.0.0.0/8"}},
+				RespondingTimeouts: &RespondingTimeouts{
+					ReadTimeout: flaeg.Duration(time.Second),
+				},
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			gc := &GlobalConfiguration{
+				EntryPoints:         EntryPoints{"http": test.entryPoint},
+				EntryPointTemplates: templates,
+			}
+
+			gc.SetEffectiveConfiguration(defaultConfigFile)
+
+			assert.Equal(t, test.expected, gc.EntryPoints["http"])
+			// the entry points don't share the sections of the template
+			if test.entryPoint.Auth != nil {
+				assert.False(t, test.entryPoint.Auth == templates["base"].Auth)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		desc          string
+		gc            *GlobalConfiguration
+		expectedError bool
+	}{
+		{
+			desc: "valid configuration",
+			gc: &GlobalConfiguration{
+				EntryPoints:        EntryPoints{"http": &EntryPoint{Address: ":80"}},
+				DefaultEntryPoints: DefaultEntryPoints{"http"},
+			},
+		},
+		{
+			desc: "unknown template",
+			gc: &GlobalConfiguration{
+				EntryPoints: EntryPoints{"http": &EntryPoint{Address: ":80", Extends: "base"}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "unknown protocol",
+			gc: &GlobalConfiguration{
+				EntryPoints: EntryPoints{"http": &EntryPoint{Address: ":80", Protocol: "sctp"}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "TCP default entrypoint",
+			gc: &GlobalConfiguration{
+				EntryPoints:        EntryPoints{"db": &EntryPoint{Address: ":5432", Protocol: EntryPointProtocolTCP}},
+				DefaultEntryPoints: DefaultEntryPoints{"db"},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "ACME resolvers",
+			gc: &GlobalConfiguration{
+				EntryPoints: EntryPoints{"https": &EntryPoint{Address: ":443", TLS: &tls.TLS{}}},
+				ACME:        &acme.ACME{EntryPoint: "https", Storage: "acme.json"},
+				ACMEResolvers: acme.Resolvers{
+					{Name: "internal", ACME: acme.ACME{EntryPoint: "https", Storage: "internal.json"}},
+				},
+			},
+		},
+		{
+			desc: "ACME resolver sharing the storage",
+			gc: &GlobalConfiguration{
+				EntryPoints: EntryPoints{"https": &EntryPoint{Address: ":443", TLS: &tls.TLS{}}},
+				ACME:        &acme.ACME{EntryPoint: "https", Storage: "acme.json"},
+				ACMEResolvers: acme.Resolvers{
+					{Name: "internal", ACME: acme.ACME{EntryPoint: "https", Storage: "acme.json"}},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "duplicate ACME resolver",
+			gc: &GlobalConfiguration{
+				EntryPoints: EntryPoints{"https": &EntryPoint{Address: ":443", TLS: &tls.TLS{}}},
+				ACMEResolvers: acme.Resolvers{
+					{Name: "internal", ACME: acme.ACME{EntryPoint: "https", Storage: "internal.json"}},
+					{Name: "internal", ACME: acme.ACME{EntryPoint: "https", Storage: "other.json"}},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "ACME resolver on an entrypoint without TLS",
+			gc: &GlobalConfiguration{
+				EntryPoints: EntryPoints{"http": &EntryPoint{Address: ":80"}},
+				ACMEResolvers: acme.Resolvers{
+					{Name: "internal", ACME: acme.ACME{EntryPoint: "http", Storage: "internal.json"}},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "ACME resolver with an invalid key type",
+			gc: &GlobalConfiguration{
+				EntryPoints: EntryPoints{"https": &EntryPoint{Address: ":443", TLS: &tls.TLS{}}},
+				ACMEResolvers: acme.Resolvers{
+					{Name: "internal", ACME: acme.ACME{EntryPoint: "https", Storage: "internal.json", KeyType: "RSA1024"}},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "Vault PKI",
+			gc: &GlobalConfiguration{
+				EntryPoints: EntryPoints{"https": &EntryPoint{Address: ":443", TLS: &tls.TLS{}}},
+				ACME:        &acme.ACME{EntryPoint: "https", Storage: "acme.json"},
+				VaultPKI:    &types.VaultPKI{EntryPoint: "https", Storage: "vault.json"},
+			},
+		},
+		{
+			desc: "Vault PKI on an entrypoint without TLS",
+			gc: &GlobalConfiguration{
+				EntryPoints: EntryPoints{"http": &EntryPoint{Address: ":80"}},
+				VaultPKI:    &types.VaultPKI{EntryPoint: "http"},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "Vault PKI sharing the ACME storage",
+			gc: &GlobalConfiguration{
+				EntryPoints: EntryPoints{"https": &EntryPoint{Address: ":443", TLS: &tls.TLS{}}},
+				ACME:        &acme.ACME{EntryPoint: "https", Storage: "acme.json"},
+				VaultPKI:    &types.VaultPKI{EntryPoint: "https", Storage: "acme.json"},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.gc.Validate()
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,23 +2,104 @@ package configuration
 
 import (
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/provider/label"
 	"github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
+	"github.com/mitchellh/copystructure"
 )
 
+// Protocols of the entry points.
+const (
+	// EntryPointProtocolHTTP is the protocol of the entry points serving HTTP frontends, used by default.
+	EntryPointProtocolHTTP = "http"
+	// EntryPointProtocolTCP is the protocol of the entry points forwarding the raw TCP connections to their frontends.
+	EntryPointProtocolTCP = "tcp"
+	// EntryPointProtocolUDP is the protocol of the entry points forwarding the UDP datagrams to their frontend.
+	EntryPointProtocolUDP = "udp"
+)
+
+// unixAddressPrefix is the prefix of the addresses of the entry points listening on a Unix domain socket.
+const unixAddressPrefix = "unix://"
+
 // EntryPoint holds an entry point configuration of the reverse proxy (ip, port, TLS...)
 type EntryPoint struct {
-	Address              string
-	TLS                  *tls.TLS        `export:"true"`
-	Redirect             *types.Redirect `export:"true"`
-	Auth                 *types.Auth     `export:"true"`
-	WhitelistSourceRange []string
-	Compress             bool              `export:"true"`
-	ProxyProtocol        *ProxyProtocol    `export:"true"`
-	ForwardedHeaders     *ForwardedHeaders `export:"true"`
+	Address               string
+	Addresses             []string
+	Extends               string          `export:"true"`
+	Protocol              string          `export:"true"`
+	TLS                   *tls.TLS        `export:"true"`
+	Redirect              *types.Redirect `export:"true"`
+	Auth                  *types.Auth     `export:"true"`
+	WhitelistSourceRange  []string
+	WhitelistIPStrategy   *types.IPStrategy          `export:"true"`
+	Compress              bool                       `export:"true"`
+	Compression           *types.Compression         `export:"true"`
+	ProxyProtocol         *ProxyProtocol             `export:"true"`
+	ForwardedHeaders      *ForwardedHeaders          `export:"true"`
+	HeaderNormalization   *types.HeaderNormalization `export:"true"`
+	Headers               *types.Headers             `export:"true"`
+	KeepAlive             *KeepAlive                 `export:"true"`
+	RequestLimits         *RequestLimits             `export:"true"`
+	HTTP2                 *HTTP2                     `export:"true"`
+	RespondingTimeouts    *RespondingTimeouts        `export:"true"`
+	Drain                 *Drain                     `export:"true"`
+	MaxConnections        int                        `export:"true"`
+	MaxConcurrentRequests int                        `export:"true"`
+	MaxRequestBodyBytes   int64                      `export:"true"`
+}
+
+// ListenAddress is a network address an entry point listens on.
+type ListenAddress struct {
+	Network string
+	Address string
+}
+
+// Network returns the network and the address the entry point listens on:
+// the path of a Unix domain socket for the addresses starting with unix://, a TCP address otherwise.
+func (ep *EntryPoint) Network() (network string, address string) {
+	listenAddress := parseListenAddress(ep.Address)
+	return listenAddress.Network, listenAddress.Address
+}
+
+// ListenAddresses returns all the network addresses the entry point listens on,
+// its main Address followed by its additional Addresses.
+func (ep *EntryPoint) ListenAddresses() []ListenAddress {
+	listenAddresses := []ListenAddress{parseListenAddress(ep.Address)}
+	for _, address := range ep.Addresses {
+		listenAddresses = append(listenAddresses, parseListenAddress(address))
+	}
+	return listenAddresses
+}
+
+// extend sets the options of the entry point which are not defined to the ones of the template.
+// The options are inherited as a whole: an entry point defining a section, e.g. its TLS configuration,
+// doesn't inherit any option of the section of the template.
+func (ep *EntryPoint) extend(template *EntryPoint) error {
+	copied, err := copystructure.Copy(template)
+	if err != nil {
+		return err
+	}
+
+	src := reflect.ValueOf(copied).Elem()
+	dst := reflect.ValueOf(ep).Elem()
+	for i := 0; i < dst.NumField(); i++ {
+		if field := dst.Field(i); reflect.DeepEqual(field.Interface(), reflect.Zero(field.Type()).Interface()) {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+	return nil
+}
+
+func parseListenAddress(address string) ListenAddress {
+	if strings.HasPrefix(address, unixAddressPrefix) {
+		return ListenAddress{Network: "unix", Address: strings.TrimPrefix(address, unixAddressPrefix)}
+	}
+	return ListenAddress{Network: "tcp", Address: address}
 }
 
 // EntryPoints holds entry points configuration of the reverse proxy (ip, port, TLS...)
@@ -51,11 +132,26 @@ func (ep *EntryPoints) Type() string {
 func (ep *EntryPoints) Set(value string) error {
 	result := parseEntryPointsConfiguration(value)
 
+	var addresses []string
+	if len(result["addresses"]) > 0 {
+		addresses = strings.Split(result["addresses"], ",")
+	}
+	for _, address := range append([]string{result["address"]}, addresses...) {
+		if strings.HasPrefix(address, unixAddressPrefix) && len(address) == len(unixAddressPrefix) {
+			return fmt.Errorf("missing socket path in address %q", address)
+		}
+	}
+
 	var whiteListSourceRange []string
 	if len(result["whitelistsourcerange"]) > 0 {
 		whiteListSourceRange = strings.Split(result["whitelistsourcerange"], ",")
 	}
 
+	whitelistIPStrategy, err := makeEntryPointWhitelistIPStrategy(result)
+	if err != nil {
+		return err
+	}
+
 	compress := toBool(result, "compress")
 
 	configTLS, err := makeEntryPointTLS(result)
@@ -63,15 +159,91 @@ func (ep *EntryPoints) Set(value string) error {
 		return err
 	}
 
+	compression, err := makeEntryPointCompression(result)
+	if err != nil {
+		return err
+	}
+
+	keepAlive, err := makeEntryPointKeepAlive(result)
+	if err != nil {
+		return err
+	}
+
+	headers, err := makeEntryPointHeaders(result)
+	if err != nil {
+		return err
+	}
+
+	requestLimits, err := makeEntryPointRequestLimits(result)
+	if err != nil {
+		return err
+	}
+
+	http2, err := makeEntryPointHTTP2(result)
+	if err != nil {
+		return err
+	}
+
+	respondingTimeouts, err := makeEntryPointRespondingTimeouts(result)
+	if err != nil {
+		return err
+	}
+
+	drain, err := makeEntryPointDrain(result)
+	if err != nil {
+		return err
+	}
+
+	maxConnections, err := toInt(result, "maxconnections")
+	if err != nil {
+		return err
+	}
+	maxConcurrentRequests, err := toInt(result, "maxconcurrentrequests")
+	if err != nil {
+		return err
+	}
+	if maxConnections < 0 || maxConcurrentRequests < 0 {
+		return fmt.Errorf("negative concurrency limit in entrypoint %s", result["name"])
+	}
+
+	maxRequestBodyBytes, err := toInt64(result, "maxrequestbodybytes")
+	if err != nil {
+		return err
+	}
+	if maxRequestBodyBytes < 0 {
+		return fmt.Errorf("negative request body size limit in entrypoint %s", result["name"])
+	}
+
+	forwardedHeaders := makeEntryPointForwardedHeaders(result)
+	if len(result["extends"]) > 0 && len(result["forwardedheaders_insecure"]) == 0 && len(result["forwardedheaders_trustedips"]) == 0 {
+		// inherited from the template, the default being set afterwards
+		forwardedHeaders = nil
+	}
+
 	(*ep)[result["name"]] = &EntryPoint{
-		Address:              result["address"],
-		TLS:                  configTLS,
-		Auth:                 makeEntryPointAuth(result),
-		Redirect:             makeEntryPointRedirect(result),
-		Compress:             compress,
-		WhitelistSourceRange: whiteListSourceRange,
-		ProxyProtocol:        makeEntryPointProxyProtocol(result),
-		ForwardedHeaders:     makeEntryPointForwardedHeaders(result),
+		Address:               result["address"],
+		Addresses:             addresses,
+		Extends:               result["extends"],
+		Protocol:              strings.ToLower(result["protocol"]),
+		TLS:                   configTLS,
+		Auth:                  makeEntryPointAuth(result),
+		Redirect:              makeEntryPointRedirect(result),
+		Compress:              compress,
+		Compression:           compression,
+		WhitelistSourceRange:  whiteListSourceRange,
+		WhitelistIPStrategy:   whitelistIPStrategy,
+		ProxyProtocol:         makeEntryPointProxyProtocol(result),
+		ForwardedHeaders:      forwardedHeaders,
+		HeaderNormalization:   makeEntryPointHeaderNormalization(result),
+		Headers:               headers,
+		KeepAlive:             keepAlive,
+		RequestLimits:         requestLimits,
+		HTTP2:                 http2,
+		RespondingTimeouts:    respondingTimeouts,
+		Drain:                 drain,
+		MaxConnections:        maxConnections,
+		MaxConcurrentRequests: maxConcurrentRequests,
+		MaxRequestBodyBytes:   maxRequestBodyBytes,
 	}
 
 	return nil
@@ -130,6 +302,33 @@ func makeEntryPointAuth(result map[string]string) *types.Auth {
 	return auth
 }
 
+func makeEntryPointCompression(result map[string]string) (*types.Compression, error) {
+	var compression *types.Compression
+
+	if len(result["compression_includedcontenttypes"]) > 0 ||
+		len(result["compression_excludedcontenttypes"]) > 0 ||
+		len(result["compression_excludedpaths"]) > 0 ||
+		len(result["compression_minresponsebodybytes"]) > 0 {
+		minResponseBodyBytes, err := toInt(result, "compression_minresponsebodybytes")
+		if err != nil {
+			return nil, err
+		}
+
+		compression = &types.Compression{MinResponseBodyBytes: minResponseBodyBytes}
+		if v := result["compression_includedcontenttypes"]; len(v) > 0 {
+			compression.IncludedContentTypes = strings.Split(v, ",")
+		}
+		if v := result["compression_excludedcontenttypes"]; len(v) > 0 {
+			compression.ExcludedContentTypes = strings.Split(v, ",")
+		}
+		if v := result["compression_excludedpaths"]; len(v) > 0 {
+			compression.ExcludedPaths = strings.Split(v, ",")
+		}
+	}
+
+	return compression, nil
+}
+
 func makeEntryPointProxyProtocol(result map[string]string) *ProxyProtocol {
 	var proxyProtocol *ProxyProtocol
 
@@ -150,6 +349,26 @@ func makeEntryPointProxyProtocol(result map[string]string) *ProxyProtocol {
 	return proxyProtocol
 }
 
+func makeEntryPointWhitelistIPStrategy(result map[string]string) (*types.IPStrategy, error) {
+	if len(result["whitelistipstrategy_depth"]) == 0 && len(result["whitelistipstrategy_excludedips"]) == 0 {
+		return nil, nil
+	}
+
+	depth, err := toInt(result, "whitelistipstrategy_depth")
+	if err != nil {
+		return nil, err
+	}
+	if depth < 0 {
+		return nil, fmt.Errorf("negative WhiteListIPStrategy.Depth in entrypoint %s", result["name"])
+	}
+
+	ipStrategy := &types.IPStrategy{Depth: depth}
+	if excludedIPs := result["whitelistipstrategy_excludedips"]; len(excludedIPs) > 0 {
+		ipStrategy.ExcludedIPs = strings.Split(excludedIPs, ",")
+	}
+	return ipStrategy, nil
+}
+
 func makeEntryPointForwardedHeaders(result map[string]string) *ForwardedHeaders {
 	// TODO must be changed to false by default in the next breaking version.
 	forwardedHeaders := &ForwardedHeaders{Insecure: true}
@@ -167,6 +386,194 @@ func makeEntryPointForwardedHeaders(result map[string]string) *ForwardedHeaders
 	return forwardedHeaders
 }
 
+func makeEntryPointHeaderNormalization(result map[string]string) *types.HeaderNormalization {
+	var headerNormalization *types.HeaderNormalization
+
+	if len(result["headernormalization_mergeduplicates"]) > 0 ||
+		len(result["headernormalization_rejectduplicates"]) > 0 ||
+		len(result["headernormalization_hopbyhopheaders"]) > 0 ||
+		len(result["headernormalization_canonicalize"]) > 0 {
+		headerNormalization = &types.HeaderNormalization{
+			Canonicalize: toBool(result, "headernormalization_canonicalize"),
+		}
+		if v := result["headernormalization_mergeduplicates"]; len(v) > 0 {
+			headerNormalization.MergeDuplicates = strings.Split(v, ",")
+		}
+		if v := result["headernormalization_rejectduplicates"]; len(v) > 0 {
+			headerNormalization.RejectDuplicates = strings.Split(v, ",")
+		}
+		if v := result["headernormalization_hopbyhopheaders"]; len(v) > 0 {
+			headerNormalization.HopByHopHeaders = strings.Split(v, ",")
+		}
+	}
+
+	return headerNormalization
+}
+
+func makeEntryPointHeaders(result map[string]string) (*types.Headers, error) {
+	var headers *types.Headers
+
+	if len(result["headers_customrequestheaders"]) > 0 ||
+		len(result["headers_customresponseheaders"]) > 0 ||
+		len(result["headers_stsseconds"]) > 0 ||
+		len(result["headers_stsincludesubdomains"]) > 0 ||
+		len(result["headers_stspreload"]) > 0 ||
+		len(result["headers_forcestsheader"]) > 0 ||
+		len(result["headers_framedeny"]) > 0 ||
+		len(result["headers_contenttypenosniff"]) > 0 ||
+		len(result["headers_browserxssfilter"]) > 0 ||
+		len(result["headers_referrerpolicy"]) > 0 {
+		stsSeconds, err := toInt64(result, "headers_stsseconds")
+		if err != nil {
+			return nil, err
+		}
+
+		headers = &types.Headers{
+			STSSeconds:           stsSeconds,
+			STSIncludeSubdomains: toBool(result, "headers_stsincludesubdomains"),
+			STSPreload:           toBool(result, "headers_stspreload"),
+			ForceSTSHeader:       toBool(result, "headers_forcestsheader"),
+			FrameDeny:            toBool(result, "headers_framedeny"),
+			ContentTypeNosniff:   toBool(result, "headers_contenttypenosniff"),
+			BrowserXSSFilter:     toBool(result, "headers_browserxssfilter"),
+			ReferrerPolicy:       result["headers_referrerpolicy"],
+		}
+		if v := result["headers_customrequestheaders"]; len(v) > 0 {
+			headers.CustomRequestHeaders = label.ParseMapValue("Headers.CustomRequestHeaders", v)
+		}
+		if v := result["headers_customresponseheaders"]; len(v) > 0 {
+			headers.CustomResponseHeaders = label.ParseMapValue("Headers.CustomResponseHeaders", v)
+		}
+	}
+
+	return headers, nil
+}
+
+func makeEntryPointKeepAlive(result map[string]string) (*KeepAlive, error) {
+	if len(result["keepalive_maxrequests"]) == 0 && len(result["keepalive_maxage"]) == 0 && len(result["keepalive_tcpperiod"]) == 0 {
+		return nil, nil
+	}
+
+	maxRequests, err := toInt(result, "keepalive_maxrequests")
+	if err != nil {
+		return nil, err
+	}
+
+	keepAlive := &KeepAlive{MaxRequests: maxRequests}
+	if v := result["keepalive_maxage"]; len(v) > 0 {
+		if err := keepAlive.MaxAge.Set(v); err != nil {
+			return nil, fmt.Errorf("invalid KeepAlive.MaxAge %q: %v", v, err)
+		}
+	}
+	if v := result["keepalive_tcpperiod"]; len(v) > 0 {
+		if err := keepAlive.TCPPeriod.Set(v); err != nil {
+			return nil, fmt.Errorf("invalid KeepAlive.TCPPeriod %q: %v", v, err)
+		}
+	}
+
+	return keepAlive, nil
+}
+
+func makeEntryPointRequestLimits(result map[string]string) (*RequestLimits, error) {
+	if len(result["requestlimits_maxheaderbytes"]) == 0 && len(result["requestlimits_maxheadercount"]) == 0 && len(result["requestlimits_maxurilength"]) == 0 {
+		return nil, nil
+	}
+
+	maxHeaderBytes, err := toInt(result, "requestlimits_maxheaderbytes")
+	if err != nil {
+		return nil, err
+	}
+	maxHeaderCount, err := toInt(result, "requestlimits_maxheadercount")
+	if err != nil {
+		return nil, err
+	}
+	maxURILength, err := toInt(result, "requestlimits_maxurilength")
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestLimits{
+		MaxHeaderBytes: maxHeaderBytes,
+		MaxHeaderCount: maxHeaderCount,
+		MaxURILength:   maxURILength,
+	}, nil
+}
+
+func makeEntryPointHTTP2(result map[string]string) (*HTTP2, error) {
+	var http2 *HTTP2
+	for key := range result {
+		if strings.HasPrefix(key, "http2_") {
+			http2 = &HTTP2{}
+			break
+		}
+	}
+	if http2 == nil {
+		return nil, nil
+	}
+
+	http2.Disabled = toBool(result, "http2_disabled")
+	http2.Cleartext = toBool(result, "http2_cleartext")
+
+	var err error
+	if http2.MaxConcurrentStreams, err = toInt(result, "http2_maxconcurrentstreams"); err != nil {
+		return nil, err
+	}
+	if http2.InitialConnectionWindowSize, err = toInt(result, "http2_initialconnectionwindowsize"); err != nil {
+		return nil, err
+	}
+	if http2.InitialStreamWindowSize, err = toInt(result, "http2_initialstreamwindowsize"); err != nil {
+		return nil, err
+	}
+	if http2.MaxFrameSize, err = toInt(result, "http2_maxframesize"); err != nil {
+		return nil, err
+	}
+
+	return http2, nil
+}
+
+func makeEntryPointRespondingTimeouts(result map[string]string) (*RespondingTimeouts, error) {
+	if len(result["respondingtimeouts_readtimeout"]) == 0 && len(result["respondingtimeouts_writetimeout"]) == 0 && len(result["respondingtimeouts_idletimeout"]) == 0 {
+		return nil, nil
+	}
+
+	respondingTimeouts := &RespondingTimeouts{}
+	if v := result["respondingtimeouts_readtimeout"]; len(v) > 0 {
+		if err := respondingTimeouts.ReadTimeout.Set(v); err != nil {
+			return nil, fmt.Errorf("invalid RespondingTimeouts.ReadTimeout %q: %v", v, err)
+		}
+	}
+	if v := result["respondingtimeouts_writetimeout"]; len(v) > 0 {
+		if err := respondingTimeouts.WriteTimeout.Set(v); err != nil {
+			return nil, fmt.Errorf("invalid RespondingTimeouts.WriteTimeout %q: %v", v, err)
+		}
+	}
+	if v := result["respondingtimeouts_idletimeout"]; len(v) > 0 {
+		if err := respondingTimeouts.IdleTimeout.Set(v); err != nil {
+			return nil, fmt.Errorf("invalid RespondingTimeouts.IdleTimeout %q: %v", v, err)
+		}
+	}
+
+	return respondingTimeouts, nil
+}
+
+func makeEntryPointDrain(result map[string]string) (*Drain, error) {
+	if len(result["drain_timeout"]) == 0 && len(result["drain_closeconnections"]) == 0 && len(result["drain_rejectrequests"]) == 0 {
+		return nil, nil
+	}
+
+	drain := &Drain{
+		CloseConnections: toBool(result, "drain_closeconnections"),
+		RejectRequests:   toBool(result, "drain_rejectrequests"),
+	}
+	if v := result["drain_timeout"]; len(v) > 0 {
+		if err := drain.Timeout.Set(v); err != nil {
+			return nil, fmt.Errorf("invalid Drain.Timeout %q: %v", v, err)
+		}
+	}
+
+	return drain, nil
+}
+
 func makeEntryPointRedirect(result map[string]string) *types.Redirect {
 	var redirect *types.Redirect
 
@@ -199,12 +606,50 @@ func makeEntryPointTLS(result map[string]string) (*tls.TLS, error) {
 		}
 	}
 
+	if configTLS != nil {
+		configTLS.MinVersion = result["tls_minversion"]
+		configTLS.MaxVersion = result["tls_maxversion"]
+		if len(result["tls_ciphersuites"]) > 0 {
+			configTLS.CipherSuites = strings.Split(result["tls_ciphersuites"], ",")
+		}
+		if len(result["tls_curvepreferences"]) > 0 {
+			configTLS.CurvePreferences = strings.Split(result["tls_curvepreferences"], ",")
+		}
+		configTLS.SNIStrict = toBool(result, "tls_snistrict")
+		if len(result["tls_defaultcertificate"]) > 0 {
+			certs := tls.Certificates{}
+			if err := certs.Set(result["tls_defaultcertificate"]); err != nil {
+				return nil, err
+			}
+			if len(certs) != 1 {
+				return nil, fmt.Errorf("bad default certificate format: %s", result["tls_defaultcertificate"])
+			}
+			configTLS.DefaultCertificate = &certs[0]
+		}
+	}
+
 	if len(result["ca"]) > 0 {
 		files := strings.Split(result["ca"], ",")
 		optional := toBool(result, "ca_optional")
 		configTLS.ClientCA = tls.ClientCA{
-			Files:    files,
-			Optional: optional,
+			Files:        files,
+			Optional:     optional,
+			OCSP:         toBool(result, "ca_ocsp"),
+			OCSPSoftFail: toBool(result, "ca_ocspsoftfail"),
+		}
+		if len(result["ca_crlfiles"]) > 0 {
+			configTLS.ClientCA.CRLFiles = strings.Split(result["ca_crlfiles"], ",")
+		}
+		if len(result["ca_headers_pem"]) > 0 ||
+			len(result["ca_headers_subject"]) > 0 ||
+			len(result["ca_headers_sans"]) > 0 ||
+			len(result["ca_headers_serial"]) > 0 {
+			configTLS.ClientCA.Headers = &tls.ClientCertHeaders{
+				PEM:     result["ca_headers_pem"],
+				Subject: result["ca_headers_subject"],
+				SANs:    result["ca_headers_sans"],
+				Serial:  result["ca_headers_serial"],
+			}
 		}
 	}
 
@@ -239,3 +684,27 @@ func toBool(conf map[string]string, key string) bool {
 	}
 	return false
 }
+
+func toInt(conf map[string]string, key string) (int, error) {
+	val, ok := conf[key]
+	if !ok || len(val) == 0 {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q for %s: %v", val, key, err)
+	}
+	return n, nil
+}
+
+func toInt64(conf map[string]string, key string) (int64, error) {
+	val, ok := conf[key]
+	if !ok || len(val) == 0 {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q for %s: %v", val, key, err)
+	}
+	return n, nil
+}
@@ -2,7 +2,9 @@ package configuration
 
 import (
 	"testing"
+	"time"
 
+	"github.com/containous/flaeg"
 	"github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
 	"github.com/stretchr/testify/assert"
@@ -57,15 +59,15 @@ func Test_parseEntryPointsConfiguration(t *testing.T) {
 				"ca_optional":                         "true",
 				"compress":                            "true",
 				"forwardedheaders_trustedips":         "10.0.0.3/24,20.0.0.3/24",
-				"name": "foo",
-				"proxyprotocol_trustedips": "192.168.0.1",
-				"redirect_entrypoint":      "https",
-				"redirect_permanent":       "true",
-				"redirect_regex":           "http://localhost/(.*)",
-				"redirect_replacement":     "http://mydomain/$1",
-				"tls":                  "goo,gii",
-				"tls_acme":             "TLS",
-				"whitelistsourcerange": "10.42.0.0/16,152.89.1.33/32,afed:be44::/16",
+				"name":                                "foo",
+				"proxyprotocol_trustedips":            "192.168.0.1",
+				"redirect_entrypoint":                 "https",
+				"redirect_permanent":                  "true",
+				"redirect_regex":                      "http://localhost/(.*)",
+				"redirect_replacement":                "http://mydomain/$1",
+				"tls":                                 "goo,gii",
+				"tls_acme":                            "TLS",
+				"whitelistsourcerange":                "10.42.0.0/16,152.89.1.33/32,afed:be44::/16",
 			},
 		},
 		{
@@ -427,6 +429,299 @@ func TestEntryPoints_Set(t *testing.T) {
 				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
 			},
 		},
+		{
+			name: "compression filters",
+			expression: "Name:foo Compress:true " +
+				"Compression.IncludedContentTypes:text/*,application/json " +
+				"Compression.ExcludedContentTypes:text/event-stream " +
+				"Compression.ExcludedPaths:^/downloads/ " +
+				"Compression.MinResponseBodyBytes:1024",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				Compress: true,
+				Compression: &types.Compression{
+					IncludedContentTypes: []string{"text/*", "application/json"},
+					ExcludedContentTypes: []string{"text/event-stream"},
+					ExcludedPaths:        []string{"^/downloads/"},
+					MinResponseBodyBytes: 1024,
+				},
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name: "HeaderNormalization",
+			expression: "Name:foo " +
+				"HeaderNormalization.MergeDuplicates:X-Forwarded-For,Via " +
+				"HeaderNormalization.RejectDuplicates:Authorization " +
+				"HeaderNormalization.HopByHopHeaders:X-Internal " +
+				"HeaderNormalization.Canonicalize:true",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+				HeaderNormalization: &types.HeaderNormalization{
+					MergeDuplicates:  []string{"X-Forwarded-For", "Via"},
+					RejectDuplicates: []string{"Authorization"},
+					HopByHopHeaders:  []string{"X-Internal"},
+					Canonicalize:     true,
+				},
+			},
+		},
+		{
+			name: "KeepAlive",
+			expression: "Name:foo " +
+				"KeepAlive.MaxRequests:1000 " +
+				"KeepAlive.MaxAge:10m " +
+				"KeepAlive.TCPPeriod:30",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+				KeepAlive: &KeepAlive{
+					MaxRequests: 1000,
+					MaxAge:      flaeg.Duration(10 * time.Minute),
+					TCPPeriod:   flaeg.Duration(30 * time.Second),
+				},
+			},
+		},
+		{
+			name: "RequestLimits",
+			expression: "Name:foo " +
+				"RequestLimits.MaxHeaderBytes:65536 " +
+				"RequestLimits.MaxHeaderCount:100 " +
+				"RequestLimits.MaxURILength:8192",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+				RequestLimits: &RequestLimits{
+					MaxHeaderBytes: 65536,
+					MaxHeaderCount: 100,
+					MaxURILength:   8192,
+				},
+			},
+		},
+		{
+			name: "HTTP2",
+			expression: "Name:foo " +
+				"HTTP2.MaxConcurrentStreams:100 " +
+				"HTTP2.InitialConnectionWindowSize:4194304 " +
+				"HTTP2.InitialStreamWindowSize:1048576 " +
+				"HTTP2.MaxFrameSize:65536",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+				HTTP2: &HTTP2{
+					MaxConcurrentStreams:        100,
+					InitialConnectionWindowSize: 4194304,
+					InitialStreamWindowSize:     1048576,
+					MaxFrameSize:                65536,
+				},
+			},
+		},
+		{
+			name: "client certificate headers",
+			expression: "Name:foo TLS CA:car " +
+				"CA.Headers.PEM:X-Forwarded-Tls-Client-Cert " +
+				"CA.Headers.Subject:X-Forwarded-Tls-Client-Cert-Subject",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				TLS: &tls.TLS{
+					Certificates: tls.Certificates{},
+					ClientCA: tls.ClientCA{
+						Files: []string{"car"},
+						Headers: &tls.ClientCertHeaders{
+							PEM:     "X-Forwarded-Tls-Client-Cert",
+							Subject: "X-Forwarded-Tls-Client-Cert-Subject",
+						},
+					},
+				},
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name:                   "client certificates revocation",
+			expression:             "Name:foo TLS CA:car CA.CRLFiles:ca.crl,intermediate.crl CA.OCSP:true CA.OCSPSoftFail:true",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				TLS: &tls.TLS{
+					Certificates: tls.Certificates{},
+					ClientCA: tls.ClientCA{
+						Files:        []string{"car"},
+						CRLFiles:     []string{"ca.crl", "intermediate.crl"},
+						OCSP:         true,
+						OCSPSoftFail: true,
+					},
+				},
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name: "TLS options",
+			expression: "Name:foo TLS TLS.MinVersion:VersionTLS11 TLS.MaxVersion:VersionTLS12 " +
+				"TLS.CipherSuites:TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384 " +
+				"TLS.CurvePreferences:X25519,CurveP256 TLS.SNIStrict:true",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				TLS: &tls.TLS{
+					MinVersion:       "VersionTLS11",
+					MaxVersion:       "VersionTLS12",
+					CipherSuites:     []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+					CurvePreferences: []string{"X25519", "CurveP256"},
+					Certificates:     tls.Certificates{},
+					SNIStrict:        true,
+				},
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name:                   "TLS default certificate",
+			expression:             "Name:foo TLS:goo,gii TLS.DefaultCertificate:default.crt,default.key",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				TLS: &tls.TLS{
+					Certificates: tls.Certificates{
+						{CertFile: tls.FileOrContent("goo"), KeyFile: tls.FileOrContent("gii")},
+					},
+					DefaultCertificate: &tls.Certificate{
+						CertFile: tls.FileOrContent("default.crt"),
+						KeyFile:  tls.FileOrContent("default.key"),
+					},
+				},
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name:                   "HTTP2 disabled",
+			expression:             "Name:foo HTTP2.Disabled:true",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+				HTTP2:            &HTTP2{Disabled: true},
+			},
+		},
+		{
+			name:                   "Unix domain socket",
+			expression:             "Name:admin Address:unix:///var/run/traefik.sock",
+			expectedEntryPointName: "admin",
+			expectedEntryPoint: &EntryPoint{
+				Address:          "unix:///var/run/traefik.sock",
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name:                   "several addresses",
+			expression:             "Name:http Address::80 Addresses:[::1]:8080,unix:///var/run/traefik.sock",
+			expectedEntryPointName: "http",
+			expectedEntryPoint: &EntryPoint{
+				Address:          ":80",
+				Addresses:        []string{"[::1]:8080", "unix:///var/run/traefik.sock"},
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name:                   "TCP protocol",
+			expression:             "Name:mysql Address::3306 Protocol:TCP",
+			expectedEntryPointName: "mysql",
+			expectedEntryPoint: &EntryPoint{
+				Address:          ":3306",
+				Protocol:         "tcp",
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name:                   "concurrency limits",
+			expression:             "Name:foo MaxConnections:1000 MaxConcurrentRequests:200",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				ForwardedHeaders:      &ForwardedHeaders{Insecure: true},
+				MaxConnections:        1000,
+				MaxConcurrentRequests: 200,
+			},
+		},
+		{
+			name: "Headers",
+			expression: "Name:foo " +
+				"Headers.CustomResponseHeaders:X-Platform:traefik||Server: " +
+				"Headers.STSSeconds:31536000 " +
+				"Headers.STSIncludeSubdomains:true " +
+				"Headers.ContentTypeNosniff:true",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+				Headers: &types.Headers{
+					CustomResponseHeaders: map[string]string{"X-Platform": "traefik", "Server": ""},
+					STSSeconds:            31536000,
+					STSIncludeSubdomains:  true,
+					ContentTypeNosniff:    true,
+				},
+			},
+		},
+		{
+			name:                   "drain",
+			expression:             "Name:foo Drain.Timeout:30s Drain.RejectRequests:true",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+				Drain: &Drain{
+					Timeout:        flaeg.Duration(30 * time.Second),
+					RejectRequests: true,
+				},
+			},
+		},
+		{
+			name:                   "whitelist IP strategy",
+			expression:             "Name:foo WhiteListSourceRange:10.42.0.0/16 WhiteListIPStrategy.Depth:1 WhiteListIPStrategy.ExcludedIPs:10.0.0.0/8,192.168.0.1",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				WhitelistSourceRange: []string{"10.42.0.0/16"},
+				WhitelistIPStrategy: &types.IPStrategy{
+					Depth:       1,
+					ExcludedIPs: []string{"10.0.0.0/8", "192.168.0.1"},
+				},
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name:                   "template extended",
+			expression:             "Name:foo Address::8000 Extends:base",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				Address: ":8000",
+				Extends: "base",
+			},
+		},
+		{
+			name:                   "template extended with forwarded headers",
+			expression:             "Name:foo Extends:base ForwardedHeaders.TrustedIPs:10.0.0.0/8",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				Extends:          "base",
+				ForwardedHeaders: &ForwardedHeaders{TrustedIPs: []string{"10.0.0.0/8"}},
+			},
+		},
+		{
+			name:                   "request body size limit",
+			expression:             "Name:foo MaxRequestBodyBytes:10485760",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				ForwardedHeaders:    &ForwardedHeaders{Insecure: true},
+				MaxRequestBodyBytes: 10485760,
+			},
+		},
+		{
+			name: "RespondingTimeouts",
+			expression: "Name:foo " +
+				"RespondingTimeouts.ReadTimeout:5s " +
+				"RespondingTimeouts.WriteTimeout:10m " +
+				"RespondingTimeouts.IdleTimeout:20m",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+				RespondingTimeouts: &RespondingTimeouts{
+					ReadTimeout:  flaeg.Duration(5 * time.Second),
+					WriteTimeout: flaeg.Duration(10 * time.Minute),
+					IdleTimeout:  flaeg.Duration(20 * time.Minute),
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -443,3 +738,153 @@ func TestEntryPoints_Set(t *testing.T) {
 		})
 	}
 }
+
+func TestEntryPoints_SetInvalidLimits(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		expression string
+	}{
+		{
+			desc:       "invalid max requests",
+			expression: "Name:foo KeepAlive.MaxRequests:many",
+		},
+		{
+			desc:       "invalid max age",
+			expression: "Name:foo KeepAlive.MaxAge:forever",
+		},
+		{
+			desc:       "invalid TCP period",
+			expression: "Name:foo KeepAlive.TCPPeriod:often",
+		},
+		{
+			desc:       "invalid max header bytes",
+			expression: "Name:foo RequestLimits.MaxHeaderBytes:1MB",
+		},
+		{
+			desc:       "invalid max URI length",
+			expression: "Name:foo RequestLimits.MaxURILength:long",
+		},
+		{
+			desc:       "invalid compression minimum response body size",
+			expression: "Name:foo Compress:true Compression.MinResponseBodyBytes:1KB",
+		},
+		{
+			desc:       "invalid HTTP/2 max concurrent streams",
+			expression: "Name:foo HTTP2.MaxConcurrentStreams:lots",
+		},
+		{
+			desc:       "invalid HTTP/2 max frame size",
+			expression: "Name:foo HTTP2.MaxFrameSize:huge",
+		},
+		{
+			desc:       "invalid write timeout",
+			expression: "Name:foo RespondingTimeouts.WriteTimeout:never",
+		},
+		{
+			desc:       "invalid max connections",
+			expression: "Name:foo MaxConnections:unlimited",
+		},
+		{
+			desc:       "negative max concurrent requests",
+			expression: "Name:foo MaxConcurrentRequests:-1",
+		},
+		{
+			desc:       "invalid STS seconds",
+			expression: "Name:foo Headers.STSSeconds:1y",
+		},
+		{
+			desc:       "invalid max request body bytes",
+			expression: "Name:foo MaxRequestBodyBytes:10MB",
+		},
+		{
+			desc:       "negative max request body bytes",
+			expression: "Name:foo MaxRequestBodyBytes:-1",
+		},
+		{
+			desc:       "invalid drain timeout",
+			expression: "Name:foo Drain.Timeout:soon",
+		},
+		{
+			desc:       "negative whitelist IP strategy depth",
+			expression: "Name:foo WhiteListIPStrategy.Depth:-1",
+		},
+		{
+			desc:       "missing socket path",
+			expression: "Name:foo Address:unix://",
+		},
+		{
+			desc:       "missing socket path in the additional addresses",
+			expression: "Name:foo Address::80 Addresses::8080,unix://",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			eps := EntryPoints{}
+			err := eps.Set(test.expression)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEntryPoint_Network(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		address         string
+		expectedNetwork string
+		expectedAddress string
+	}{
+		{
+			desc:            "port",
+			address:         ":80",
+			expectedNetwork: "tcp",
+			expectedAddress: ":80",
+		},
+		{
+			desc:            "host and port",
+			address:         "127.0.0.1:8080",
+			expectedNetwork: "tcp",
+			expectedAddress: "127.0.0.1:8080",
+		},
+		{
+			desc:            "Unix domain socket",
+			address:         "unix:///var/run/traefik.sock",
+			expectedNetwork: "unix",
+			expectedAddress: "/var/run/traefik.sock",
+		},
+		{
+			desc:            "relative Unix domain socket",
+			address:         "unix://traefik.sock",
+			expectedNetwork: "unix",
+			expectedAddress: "traefik.sock",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			network, address := (&EntryPoint{Address: test.address}).Network()
+			assert.Equal(t, test.expectedNetwork, network)
+			assert.Equal(t, test.expectedAddress, address)
+		})
+	}
+}
+
+func TestEntryPoint_ListenAddresses(t *testing.T) {
+	entryPoint := &EntryPoint{
+		Address:   ":80",
+		Addresses: []string{"[::1]:8080", "unix:///var/run/traefik.sock"},
+	}
+
+	expected := []ListenAddress{
+		{Network: "tcp", Address: ":80"},
+		{Network: "tcp", Address: "[::1]:8080"},
+		{Network: "unix", Address: "/var/run/traefik.sock"},
+	}
+	assert.Equal(t, expected, entryPoint.ListenAddresses())
+}
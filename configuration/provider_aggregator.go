@@ -44,9 +44,15 @@ func NewProviderAggregator(gc *GlobalConfiguration) provider.Provider {
 	if gc.Boltdb != nil {
 		provider.providers = append(provider.providers, gc.Boltdb)
 	}
+	if gc.Redis != nil {
+		provider.providers = append(provider.providers, gc.Redis)
+	}
 	if gc.Kubernetes != nil {
 		provider.providers = append(provider.providers, gc.Kubernetes)
 	}
+	if gc.KubernetesCRD != nil {
+		provider.providers = append(provider.providers, gc.KubernetesCRD)
+	}
 	if gc.Mesos != nil {
 		provider.providers = append(provider.providers, gc.Mesos)
 	}
@@ -65,12 +71,26 @@ func NewProviderAggregator(gc *GlobalConfiguration) provider.Provider {
 	if gc.ServiceFabric != nil {
 		provider.providers = append(provider.providers, gc.ServiceFabric)
 	}
+	if gc.DNS != nil {
+		provider.providers = append(provider.providers, gc.DNS)
+	}
+	if gc.Nomad != nil {
+		provider.providers = append(provider.providers, gc.Nomad)
+	}
+	if gc.HTTP != nil {
+		provider.providers = append(provider.providers, gc.HTTP)
+	}
 	if len(provider.providers) == 1 {
 		return provider.providers[0]
 	}
 	return provider
 }
 
+// MarshalJSON returns the configurations of the providers, to log them or detect their changes.
+func (p providerAggregator) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.providers)
+}
+
 func (p providerAggregator) Provide(configurationChan chan<- types.ConfigMessage, pool *safe.Pool, constraints types.Constraints) error {
 	for _, p := range p.providers {
 		providerType := reflect.TypeOf(p)
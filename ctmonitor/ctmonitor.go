@@ -0,0 +1,187 @@
+package ctmonitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
+	"github.com/containous/traefik/types"
+)
+
+const (
+	defaultURL      = "https://crt.sh/"
+	defaultInterval = time.Hour
+)
+
+// entry is a certificate logged in the Certificate Transparency logs, as returned by crt.sh.
+type entry struct {
+	ID           int64  `json:"id"`
+	IssuerName   string `json:"issuer_name"`
+	NameValue    string `json:"name_value"`
+	SerialNumber string `json:"serial_number"`
+	NotBefore    string `json:"not_before"`
+}
+
+// Monitor checks the certificates logged in the Certificate Transparency logs for the domains,
+// and raises an event for each new certificate neither issued by Traefik nor by an expected issuer.
+type Monitor struct {
+	domains        []string
+	allowedIssuers []string
+	// issuedSerials returns the serial numbers of the certificates issued by Traefik, in lowercase hexadecimal.
+	issuedSerials func() map[string]bool
+	interval      time.Duration
+	url           string
+	client        *http.Client
+	notify        func(notification.Event)
+	// lastIDs are the highest certificate IDs seen for each domain.
+	lastIDs map[string]int64
+}
+
+// New creates a monitor from its configuration,
+// the certificates whose serial numbers are returned by issuedSerials being expected.
+func New(config *types.CTMonitor, issuedSerials func() map[string]bool) (*Monitor, error) {
+	if len(config.Domains) == 0 {
+		return nil, errors.New("no domains to monitor")
+	}
+
+	interval := defaultInterval
+	if len(config.Interval) > 0 {
+		var err error
+		interval, err = time.ParseDuration(config.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval: %v", err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("invalid interval: %s", config.Interval)
+		}
+	}
+
+	searchURL := defaultURL
+	if len(config.URL) > 0 {
+		searchURL = config.URL
+	}
+
+	return &Monitor{
+		domains:        config.Domains,
+		allowedIssuers: config.AllowedIssuers,
+		issuedSerials:  issuedSerials,
+		interval:       interval,
+		url:            searchURL,
+		client:         &http.Client{Timeout: 30 * time.Second},
+		notify:         notification.Notify,
+		lastIDs:        make(map[string]int64),
+	}, nil
+}
+
+// Run checks the domains at every interval until the context is done.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check raises an event for each unexpected certificate logged since the previous check.
+// The certificates logged before the first check of a domain are only recorded, to not alert on the history.
+func (m *Monitor) check(ctx context.Context) {
+	for _, domain := range m.domains {
+		entries, err := m.search(ctx, domain)
+		if err != nil {
+			log.Errorf("Unable to search the Certificate Transparency logs for %s: %v", domain, err)
+			continue
+		}
+
+		lastID, checked := m.lastIDs[domain]
+		maxID := lastID
+		var issuedSerials map[string]bool
+		for _, e := range entries {
+			if e.ID > maxID {
+				maxID = e.ID
+			}
+			if !checked || e.ID <= lastID {
+				continue
+			}
+
+			if issuedSerials == nil && m.issuedSerials != nil {
+				issuedSerials = m.issuedSerials()
+			}
+			if issuedSerials[normalizeSerial(e.SerialNumber)] || m.allowed(e.IssuerName) {
+				continue
+			}
+
+			log.Warnf("Unexpected certificate for %s logged in the Certificate Transparency logs: issuer %q, serial %s", domain, e.IssuerName, e.SerialNumber)
+			m.notify(notification.Event{
+				Type:    notification.CertificateUnexpected,
+				Message: fmt.Sprintf("Unexpected certificate logged in the Certificate Transparency logs, issued by %s (serial %s, valid from %s)", e.IssuerName, e.SerialNumber, e.NotBefore),
+				Domains: strings.Fields(e.NameValue),
+			})
+		}
+		m.lastIDs[domain] = maxID
+	}
+}
+
+// allowed returns whether the certificates of the issuer are expected, all of them being unexpected when no issuer is allowed.
+func (m *Monitor) allowed(issuer string) bool {
+	for _, allowedIssuer := range m.allowedIssuers {
+		if strings.Contains(strings.ToLower(issuer), strings.ToLower(allowedIssuer)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeSerial returns the serial number in lowercase hexadecimal, without separators nor leading zeros.
+func normalizeSerial(serial string) string {
+	serial = strings.TrimLeft(strings.ToLower(strings.Replace(serial, ":", "", -1)), "0")
+	if len(serial) == 0 {
+		return "0"
+	}
+	return serial
+}
+
+func (m *Monitor) search(ctx context.Context, domain string) ([]entry, error) {
+	u, err := url.Parse(m.url)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	query.Set("q", domain)
+	query.Set("output", "json")
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := m.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var entries []entry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
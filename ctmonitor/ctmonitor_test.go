@@ -0,0 +1,134 @@
+package ctmonitor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/notification"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        types.CTMonitor
+		expectedError bool
+	}{
+		{
+			desc:   "defaults",
+			config: types.CTMonitor{Domains: []string{"example.com"}},
+		},
+		{
+			desc:          "no domains",
+			config:        types.CTMonitor{},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid interval",
+			config:        types.CTMonitor{Domains: []string{"example.com"}, Interval: "hourly"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			monitor, err := New(&test.config, nil)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, defaultInterval, monitor.interval)
+			assert.Equal(t, defaultURL, monitor.url)
+		})
+	}
+}
+
+func TestMonitorCheck(t *testing.T) {
+	responses := []string{
+		`[{"id":1,"issuer_name":"C=US, O=Let's Encrypt, CN=R3","name_value":"example.com","serial_number":"01"},
+		  {"id":2,"issuer_name":"C=XX, O=Rogue CA","name_value":"old.example.com","serial_number":"02"}]`,
+		`[{"id":1,"issuer_name":"C=US, O=Let's Encrypt, CN=R3","name_value":"example.com","serial_number":"01"},
+		  {"id":2,"issuer_name":"C=XX, O=Rogue CA","name_value":"old.example.com","serial_number":"02"},
+		  {"id":3,"issuer_name":"C=US, O=Let's Encrypt, CN=R3","name_value":"www.example.com","serial_number":"03"},
+		  {"id":4,"issuer_name":"C=XX, O=Rogue CA","name_value":"example.com\nwww.example.com","serial_number":"04"},
+		  {"id":5,"issuer_name":"C=US, O=Other ACME CA","name_value":"api.example.com","serial_number":"00:0A:BC"}]`,
+	}
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "example.com", req.URL.Query().Get("q"))
+		assert.Equal(t, "json", req.URL.Query().Get("output"))
+		fmt.Fprint(rw, responses[requests])
+		requests++
+	}))
+	defer server.Close()
+
+	monitor, err := New(&types.CTMonitor{
+		Domains:        []string{"example.com"},
+		AllowedIssuers: []string{"let's encrypt"},
+		URL:            server.URL,
+	}, func() map[string]bool {
+		return map[string]bool{"abc": true}
+	})
+	require.NoError(t, err)
+
+	var events []notification.Event
+	monitor.notify = func(event notification.Event) {
+		events = append(events, event)
+	}
+
+	// the first check only records the certificates already logged
+	monitor.check(context.Background())
+	assert.Empty(t, events)
+
+	monitor.check(context.Background())
+	require.Len(t, events, 1)
+	assert.Equal(t, notification.CertificateUnexpected, events[0].Type)
+	assert.Equal(t, []string{"example.com", "www.example.com"}, events[0].Domains)
+	assert.Contains(t, events[0].Message, "Rogue CA")
+	assert.Equal(t, int64(5), monitor.lastIDs["example.com"])
+}
+
+func TestNormalizeSerial(t *testing.T) {
+	testCases := []struct {
+		serial   string
+		expected string
+	}{
+		{serial: "03ABCDEF", expected: "3abcdef"},
+		{serial: "03:ab:cd:ef", expected: "3abcdef"},
+		{serial: "3abcdef", expected: "3abcdef"},
+		{serial: "00", expected: "0"},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.serial, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, normalizeSerial(test.serial))
+		})
+	}
+}
+
+func TestMonitorCheckError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	monitor, err := New(&types.CTMonitor{Domains: []string{"example.com"}, URL: server.URL}, nil)
+	require.NoError(t, err)
+
+	monitor.check(context.Background())
+	_, checked := monitor.lastIDs["example.com"]
+	assert.False(t, checked)
+}
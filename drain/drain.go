@@ -0,0 +1,160 @@
+package drain
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var singleton *Registry
+var once sync.Once
+
+// GetRegistry returns the drain registry which is guaranteed to be a singleton.
+func GetRegistry() *Registry {
+	once.Do(func() {
+		singleton = &Registry{entryPoints: make(map[string]*EntryPoint)}
+	})
+	return singleton
+}
+
+// Status is the state of the drain of an entry point, as exposed by the API.
+type Status struct {
+	EntryPoint       string     `json:"entryPoint"`
+	Draining         bool       `json:"draining"`
+	Timeout          string     `json:"timeout"`
+	StartedAt        *time.Time `json:"startedAt,omitempty"`
+	Deadline         *time.Time `json:"deadline,omitempty"`
+	InFlightRequests int64      `json:"inFlightRequests"`
+	RejectedRequests int64      `json:"rejectedRequests"`
+}
+
+// EntryPoint tracks the in-flight requests of an entry point, and its drain on shutdown:
+// once the drain started, the new requests can be served with a Connection: close header,
+// or rejected with a 503 status code, while the in-flight ones finish.
+type EntryPoint struct {
+	name             string
+	timeout          time.Duration
+	closeConnections bool
+	rejectRequests   bool
+
+	inFlight int64
+	rejected int64
+
+	mu        sync.RWMutex
+	startedAt time.Time
+}
+
+// Timeout returns the duration given to the in-flight requests to finish.
+func (e *EntryPoint) Timeout() time.Duration {
+	return e.timeout
+}
+
+// Start starts the drain, a drain already started being kept.
+func (e *EntryPoint) Start() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.startedAt.IsZero() {
+		e.startedAt = time.Now()
+	}
+}
+
+func (e *EntryPoint) draining() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return !e.startedAt.IsZero()
+}
+
+// Status returns the state of the drain.
+func (e *EntryPoint) Status() Status {
+	e.mu.RLock()
+	startedAt := e.startedAt
+	e.mu.RUnlock()
+
+	status := Status{
+		EntryPoint:       e.name,
+		Draining:         !startedAt.IsZero(),
+		Timeout:          e.timeout.String(),
+		InFlightRequests: atomic.LoadInt64(&e.inFlight),
+		RejectedRequests: atomic.LoadInt64(&e.rejected),
+	}
+	if status.Draining {
+		deadline := startedAt.Add(e.timeout)
+		status.StartedAt = &startedAt
+		status.Deadline = &deadline
+	}
+	return status
+}
+
+func (e *EntryPoint) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	if (e.closeConnections || e.rejectRequests) && e.draining() {
+		// the clients reconnect, possibly to another instance, for their next requests
+		rw.Header().Set("Connection", "close")
+		if e.rejectRequests {
+			atomic.AddInt64(&e.rejected, 1)
+			http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	atomic.AddInt64(&e.inFlight, 1)
+	defer atomic.AddInt64(&e.inFlight, -1)
+
+	next.ServeHTTP(rw, req)
+}
+
+// Registry holds the drains of the entry points.
+type Registry struct {
+	mu          sync.RWMutex
+	entryPoints map[string]*EntryPoint
+}
+
+// Add registers the drain of an entry point, replacing the previous one of the entry point.
+func (r *Registry) Add(name string, timeout time.Duration, closeConnections bool, rejectRequests bool) *EntryPoint {
+	entryPoint := &EntryPoint{
+		name:             name,
+		timeout:          timeout,
+		closeConnections: closeConnections,
+		rejectRequests:   rejectRequests,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entryPoints[name] = entryPoint
+	return entryPoint
+}
+
+// Restore registers again the drain of an entry point replaced by Add, when its replacement couldn't start.
+func (r *Registry) Restore(entryPoint *EntryPoint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entryPoints[entryPoint.name] = entryPoint
+}
+
+// Remove unregisters the drain of an entry point which is not served anymore.
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entryPoints, name)
+}
+
+// Statuses returns the states of the drains of the entry points, sorted by entry point.
+func (r *Registry) Statuses() []Status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(r.entryPoints))
+	for _, entryPoint := range r.entryPoints {
+		statuses = append(statuses, entryPoint.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].EntryPoint < statuses[j].EntryPoint
+	})
+	return statuses
+}
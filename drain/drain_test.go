@@ -0,0 +1,126 @@
+package drain
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryPointServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		closeConnections bool
+		rejectRequests   bool
+		draining         bool
+		expectedCode     int
+		expectedClose    bool
+		expectedRejected int64
+	}{
+		{
+			desc:         "not draining",
+			draining:     false,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "draining",
+			draining:     true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:             "closing the connections before the drain",
+			closeConnections: true,
+			draining:         false,
+			expectedCode:     http.StatusOK,
+		},
+		{
+			desc:             "closing the connections while draining",
+			closeConnections: true,
+			draining:         true,
+			expectedCode:     http.StatusOK,
+			expectedClose:    true,
+		},
+		{
+			desc:             "rejecting the requests while draining",
+			rejectRequests:   true,
+			draining:         true,
+			expectedCode:     http.StatusServiceUnavailable,
+			expectedClose:    true,
+			expectedRejected: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			entryPoint := &EntryPoint{
+				name:             "http",
+				timeout:          time.Second,
+				closeConnections: test.closeConnections,
+				rejectRequests:   test.rejectRequests,
+			}
+			if test.draining {
+				entryPoint.Start()
+			}
+
+			var inFlight int64
+			next := func(rw http.ResponseWriter, req *http.Request) {
+				inFlight = entryPoint.Status().InFlightRequests
+				rw.WriteHeader(http.StatusOK)
+			}
+
+			recorder := httptest.NewRecorder()
+			entryPoint.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://localhost/", nil), next)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedClose, recorder.Header().Get("Connection") == "close")
+
+			status := entryPoint.Status()
+			assert.Equal(t, test.expectedRejected, status.RejectedRequests)
+			assert.Zero(t, status.InFlightRequests)
+			if test.expectedCode == http.StatusOK {
+				assert.EqualValues(t, 1, inFlight)
+			}
+		})
+	}
+}
+
+func TestRegistryStatuses(t *testing.T) {
+	registry := &Registry{entryPoints: make(map[string]*EntryPoint)}
+	registry.Add("https", 10*time.Second, false, false)
+	previous := registry.Add("http", time.Second, false, false)
+	registry.Add("admin", time.Second, false, false)
+	// the entry points are replaced, restored or removed on reload
+	registry.Add("http", 5*time.Second, false, false)
+	registry.Restore(previous)
+	registry.Remove("admin")
+	assert.Equal(t, "1s", registry.Statuses()[0].Timeout)
+	entryPoint := registry.Add("http", 30*time.Second, true, false)
+
+	before := time.Now()
+	entryPoint.Start()
+	startedAt := entryPoint.Status().StartedAt
+	entryPoint.Start()
+
+	statuses := registry.Statuses()
+	require.Len(t, statuses, 2)
+
+	assert.Equal(t, "http", statuses[0].EntryPoint)
+	assert.True(t, statuses[0].Draining)
+	assert.Equal(t, "30s", statuses[0].Timeout)
+	require.NotNil(t, statuses[0].StartedAt)
+	assert.Equal(t, startedAt, statuses[0].StartedAt)
+	assert.False(t, statuses[0].StartedAt.Before(before))
+	assert.Equal(t, statuses[0].StartedAt.Add(30*time.Second), *statuses[0].Deadline)
+
+	assert.Equal(t, "https", statuses[1].EntryPoint)
+	assert.False(t, statuses[1].Draining)
+	assert.Nil(t, statuses[1].StartedAt)
+	assert.Nil(t, statuses[1].Deadline)
+}
@@ -0,0 +1,153 @@
+package geoip
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/log"
+)
+
+// Location holds the GeoIP and ASN data of an IP address.
+type Location struct {
+	Country      string
+	City         string
+	ASN          uint64
+	Organization string
+}
+
+// Database looks up the locations in MaxMind DB files (e.g. GeoLite2-City and GeoLite2-ASN),
+// reloading the files when they are modified.
+type Database struct {
+	paths   []string
+	mu      sync.RWMutex
+	readers []*reader
+	modTime []time.Time
+	stop    chan struct{}
+}
+
+// NewDatabase opens the MaxMind DB files, and checks them for modifications at the given interval, if not zero.
+func NewDatabase(paths []string, reloadInterval time.Duration) (*Database, error) {
+	db := &Database{
+		paths:   paths,
+		readers: make([]*reader, len(paths)),
+		modTime: make([]time.Time, len(paths)),
+		stop:    make(chan struct{}),
+	}
+
+	for i := range paths {
+		if _, err := db.load(i); err != nil {
+			return nil, err
+		}
+	}
+
+	if reloadInterval > 0 {
+		go db.watch(reloadInterval)
+	}
+	return db, nil
+}
+
+// Lookup returns the location of the IP address, the fields missing from the databases being empty.
+func (db *Database) Lookup(ip net.IP) (*Location, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	location := &Location{}
+	for _, r := range db.readers {
+		record, err := r.lookup(ip)
+		if err != nil {
+			return nil, err
+		}
+		fields, ok := record.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if country, ok := lookupString(fields, "country", "iso_code"); ok {
+			location.Country = country
+		}
+		if city, ok := lookupString(fields, "city", "names", "en"); ok {
+			location.City = city
+		}
+		if asn, ok := fields["autonomous_system_number"].(uint64); ok {
+			location.ASN = asn
+		}
+		if organization, ok := fields["autonomous_system_organization"].(string); ok {
+			location.Organization = organization
+		}
+	}
+	return location, nil
+}
+
+// Close stops checking the files for modifications.
+func (db *Database) Close() {
+	close(db.stop)
+}
+
+func (db *Database) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.stop:
+			return
+		case <-ticker.C:
+			for i, path := range db.paths {
+				reloaded, err := db.load(i)
+				if err != nil {
+					log.Errorf("Error reloading GeoIP database %s: %v", path, err)
+				} else if reloaded {
+					log.Infof("GeoIP database %s reloaded", path)
+				}
+			}
+		}
+	}
+}
+
+// load reads the i-th file if it was modified since it was last read.
+func (db *Database) load(i int) (bool, error) {
+	path := db.paths[i]
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("error opening GeoIP database %s: %v", path, err)
+	}
+
+	db.mu.RLock()
+	unchanged := db.readers[i] != nil && info.ModTime().Equal(db.modTime[i])
+	db.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	buffer, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("error reading GeoIP database %s: %v", path, err)
+	}
+	r, err := newReader(buffer)
+	if err != nil {
+		return false, fmt.Errorf("error reading GeoIP database %s: %v", path, err)
+	}
+
+	db.mu.Lock()
+	db.readers[i] = r
+	db.modTime[i] = info.ModTime()
+	db.mu.Unlock()
+	return true, nil
+}
+
+func lookupString(fields map[string]interface{}, keys ...string) (string, bool) {
+	var value interface{} = fields
+	for _, key := range keys {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		value = m[key]
+	}
+	s, ok := value.(string)
+	return s, ok
+}
@@ -0,0 +1,146 @@
+package geoip
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseLookup(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "traefik_geoip")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	cityPath := filepath.Join(tempDir, "city.mmdb")
+	err = ioutil.WriteFile(cityPath, buildDatabase(t, 6, []testNetwork{
+		{
+			cidr: "1.2.3.0/24",
+			data: map[string]interface{}{
+				"country": map[string]interface{}{"iso_code": "FR"},
+				"city":    map[string]interface{}{"names": map[string]interface{}{"en": "Paris", "fr": "Paris"}},
+			},
+		},
+		{
+			cidr: "2001:db8::/32",
+			data: map[string]interface{}{
+				"country": map[string]interface{}{"iso_code": "DE"},
+			},
+		},
+	}), 0644)
+	require.NoError(t, err)
+
+	asnPath := filepath.Join(tempDir, "asn.mmdb")
+	err = ioutil.WriteFile(asnPath, buildDatabase(t, 4, []testNetwork{
+		{
+			cidr: "1.2.0.0/16",
+			data: map[string]interface{}{
+				"autonomous_system_number":       uint32(64500),
+				"autonomous_system_organization": "Example",
+			},
+		},
+	}), 0644)
+	require.NoError(t, err)
+
+	db, err := NewDatabase([]string{cityPath, asnPath}, 0)
+	require.NoError(t, err)
+	defer db.Close()
+
+	testCases := []struct {
+		desc     string
+		ip       string
+		expected *Location
+	}{
+		{
+			desc:     "all fields",
+			ip:       "1.2.3.4",
+			expected: &Location{Country: "FR", City: "Paris", ASN: 64500, Organization: "Example"},
+		},
+		{
+			desc:     "ASN fields only",
+			ip:       "1.2.4.4",
+			expected: &Location{ASN: 64500, Organization: "Example"},
+		},
+		{
+			desc:     "country only",
+			ip:       "2001:db8::1",
+			expected: &Location{Country: "DE"},
+		},
+		{
+			desc:     "unknown address",
+			ip:       "10.0.0.1",
+			expected: &Location{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			location, err := db.Lookup(net.ParseIP(test.ip))
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, location)
+		})
+	}
+}
+
+func TestNewDatabaseInvalidFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "traefik_geoip")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	invalidPath := filepath.Join(tempDir, "invalid.mmdb")
+	err = ioutil.WriteFile(invalidPath, []byte("not a database"), 0644)
+	require.NoError(t, err)
+
+	_, err = NewDatabase([]string{invalidPath}, 0)
+	assert.Error(t, err)
+
+	_, err = NewDatabase([]string{filepath.Join(tempDir, "missing.mmdb")}, 0)
+	assert.Error(t, err)
+}
+
+func TestDatabaseReload(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "traefik_geoip")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "country.mmdb")
+	writeCountry := func(country string, modTime time.Time) {
+		err := ioutil.WriteFile(path, buildDatabase(t, 4, []testNetwork{
+			{cidr: "1.2.3.0/24", data: map[string]interface{}{"country": map[string]interface{}{"iso_code": country}}},
+		}), 0644)
+		require.NoError(t, err)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	now := time.Now()
+	writeCountry("FR", now.Add(-time.Hour))
+
+	db, err := NewDatabase([]string{path}, 10*time.Millisecond)
+	require.NoError(t, err)
+	defer db.Close()
+
+	location, err := db.Lookup(net.ParseIP("1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, "FR", location.Country)
+
+	writeCountry("DE", now)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		location, err = db.Lookup(net.ParseIP("1.2.3.4"))
+		require.NoError(t, err)
+		if location.Country == "DE" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("GeoIP database not reloaded, got country %q", location.Country)
+}
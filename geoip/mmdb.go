@@ -0,0 +1,349 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+)
+
+// metadataStartMarker precedes the metadata section at the end of a MaxMind DB file.
+var metadataStartMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparatorSize is the size of the zeroed separator between the search tree and the data section.
+const dataSectionSeparatorSize = 16
+
+// maxDecodeDepth bounds the nesting of the maps, arrays and pointers decoded,
+// the pointers of a malformed file being able to loop.
+const maxDecodeDepth = 512
+
+// MaxMind DB data types.
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+// reader reads the records of a MaxMind DB file, as specified by https://maxmind.github.io/MaxMind-DB/.
+type reader struct {
+	databaseType string
+	ipVersion    uint
+	nodeCount    uint
+	recordSize   uint
+	tree         []byte
+	data         decoder
+	ipv4Start    uint
+}
+
+func newReader(buffer []byte) (*reader, error) {
+	metadataStart := bytes.LastIndex(buffer, metadataStartMarker)
+	if metadataStart == -1 {
+		return nil, errors.New("invalid MaxMind DB file: metadata not found")
+	}
+	metadataStart += len(metadataStartMarker)
+
+	metadata, _, err := decoder(buffer[metadataStart:]).decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MaxMind DB metadata: %v", err)
+	}
+	fields, ok := metadata.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid MaxMind DB metadata: not a map")
+	}
+
+	r := &reader{}
+	r.databaseType, _ = fields["database_type"].(string)
+	nodeCount, _ := fields["node_count"].(uint64)
+	recordSize, _ := fields["record_size"].(uint64)
+	ipVersion, _ := fields["ip_version"].(uint64)
+	r.nodeCount, r.recordSize, r.ipVersion = uint(nodeCount), uint(recordSize), uint(ipVersion)
+
+	if r.recordSize != 24 && r.recordSize != 28 && r.recordSize != 32 {
+		return nil, fmt.Errorf("unsupported MaxMind DB record size %d", r.recordSize)
+	}
+	if r.ipVersion != 4 && r.ipVersion != 6 {
+		return nil, fmt.Errorf("unsupported MaxMind DB IP version %d", r.ipVersion)
+	}
+
+	treeSize := r.nodeCount * r.recordSize / 4
+	dataStart := treeSize + dataSectionSeparatorSize
+	if dataStart > uint(metadataStart-len(metadataStartMarker)) {
+		return nil, errors.New("invalid MaxMind DB file: truncated search tree")
+	}
+	r.tree = buffer[:treeSize]
+	r.data = decoder(buffer[dataStart : metadataStart-len(metadataStartMarker)])
+
+	// the IPv4 addresses are looked up in the ::/96 subtree of the IPv6 databases
+	if r.ipVersion == 6 {
+		for i := 0; i < 96 && r.ipv4Start < r.nodeCount; i++ {
+			r.ipv4Start, err = r.readNode(r.ipv4Start, 0)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// lookup returns the record of the network containing the IP, or nil if there is none.
+func (r *reader) lookup(ip net.IP) (interface{}, error) {
+	node := uint(0)
+	bitCount := 128
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip = ipv4
+		bitCount = 32
+		node = r.ipv4Start
+	} else if r.ipVersion == 4 {
+		return nil, nil
+	}
+
+	var err error
+	for i := 0; i < bitCount && node < r.nodeCount; i++ {
+		bit := uint(ip[i>>3]>>(7-uint(i%8))) & 1
+		node, err = r.readNode(node, bit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if node == r.nodeCount {
+		return nil, nil
+	}
+	if node < r.nodeCount+dataSectionSeparatorSize {
+		return nil, errors.New("invalid MaxMind DB search tree")
+	}
+
+	offset := node - r.nodeCount - dataSectionSeparatorSize
+	value, _, err := r.data.decode(offset)
+	return value, err
+}
+
+// readNode returns the left (bit 0) or right (bit 1) record of a node of the search tree.
+func (r *reader) readNode(node, bit uint) (uint, error) {
+	offset := node * r.recordSize / 4
+	if offset+r.recordSize/4 > uint(len(r.tree)) {
+		return 0, errors.New("invalid MaxMind DB search tree")
+	}
+	b := r.tree[offset:]
+
+	switch r.recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6]), nil
+	default:
+		return uint(binary.BigEndian.Uint32(b[bit*4:])), nil
+	}
+}
+
+// decoder decodes the values of a MaxMind DB data section.
+type decoder []byte
+
+// decode returns the value at the offset, along with the offset following it.
+func (d decoder) decode(offset uint) (interface{}, uint, error) {
+	return d.decodeValue(offset, 0)
+}
+
+func (d decoder) decodeValue(offset uint, depth int) (interface{}, uint, error) {
+	if depth > maxDecodeDepth {
+		return nil, 0, errors.New("maximum data structure depth exceeded")
+	}
+	if offset >= uint(len(d)) {
+		return nil, 0, errors.New("unexpected end of data")
+	}
+
+	ctrl := d[offset]
+	offset++
+	dataType := uint(ctrl >> 5)
+
+	if dataType == typePointer {
+		pointer, next, err := d.decodePointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		if pointer < uint(len(d)) && d[pointer]>>5 == typePointer {
+			return nil, 0, errors.New("invalid pointer to a pointer")
+		}
+		value, _, err := d.decodeValue(pointer, depth+1)
+		return value, next, err
+	}
+
+	if dataType == typeExtended {
+		if offset >= uint(len(d)) {
+			return nil, 0, errors.New("unexpected end of data")
+		}
+		dataType = 7 + uint(d[offset])
+		offset++
+	}
+
+	size, offset, err := d.decodeSize(ctrl, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch dataType {
+	case typeMap:
+		return d.decodeMap(size, offset, depth+1)
+	case typeArray:
+		return d.decodeArray(size, offset, depth+1)
+	case typeBool:
+		return size != 0, offset, nil
+	}
+
+	if offset+size > uint(len(d)) {
+		return nil, 0, errors.New("unexpected end of data")
+	}
+	b := d[offset : offset+size]
+	next := offset + size
+
+	switch dataType {
+	case typeString:
+		return string(b), next, nil
+	case typeBytes:
+		return append([]byte(nil), b...), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %d", size)
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("invalid unsigned integer size %d", size)
+		}
+		var value uint64
+		for _, c := range b {
+			value = value<<8 | uint64(c)
+		}
+		return value, next, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("invalid int32 size %d", size)
+		}
+		var value uint32
+		for _, c := range b {
+			value = value<<8 | uint32(c)
+		}
+		return int64(int32(value)), next, nil
+	case typeUint128:
+		// the 128-bit integers are returned as their big-endian bytes
+		return append([]byte(nil), b...), next, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported data type %d", dataType)
+	}
+}
+
+func (d decoder) decodeSize(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl & 0x1f)
+	if size < 29 {
+		return size, offset, nil
+	}
+
+	n := size - 28
+	if offset+n > uint(len(d)) {
+		return 0, 0, errors.New("unexpected end of data")
+	}
+	var extra uint
+	for _, c := range d[offset : offset+n] {
+		extra = extra<<8 | uint(c)
+	}
+
+	switch size {
+	case 29:
+		return 29 + extra, offset + n, nil
+	case 30:
+		return 285 + extra, offset + n, nil
+	default:
+		return 65821 + extra, offset + n, nil
+	}
+}
+
+func (d decoder) decodePointer(ctrl byte, offset uint) (uint, uint, error) {
+	n := uint((ctrl>>3)&0x3) + 1
+	if offset+n > uint(len(d)) {
+		return 0, 0, errors.New("unexpected end of data")
+	}
+	b := d[offset : offset+n]
+
+	var pointer uint
+	if n < 4 {
+		pointer = uint(ctrl & 0x7)
+	}
+	for _, c := range b {
+		pointer = pointer<<8 | uint(c)
+	}
+
+	switch n {
+	case 2:
+		pointer += 2048
+	case 3:
+		pointer += 526336
+	}
+	return pointer, offset + n, nil
+}
+
+func (d decoder) decodeMap(size, offset uint, depth int) (interface{}, uint, error) {
+	// each entry takes at least one byte for its key and one for its value
+	if size > (uint(len(d))-offset)/2 {
+		return nil, 0, errors.New("unexpected end of data")
+	}
+
+	values := make(map[string]interface{}, size)
+	for i := uint(0); i < size; i++ {
+		key, next, err := d.decodeValue(offset, depth)
+		if err != nil {
+			return nil, 0, err
+		}
+		k, ok := key.(string)
+		if !ok {
+			return nil, 0, errors.New("invalid map key")
+		}
+
+		values[k], offset, err = d.decodeValue(next, depth)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return values, offset, nil
+}
+
+func (d decoder) decodeArray(size, offset uint, depth int) (interface{}, uint, error) {
+	// each element takes at least one byte
+	if size > uint(len(d))-offset {
+		return nil, 0, errors.New("unexpected end of data")
+	}
+
+	values := make([]interface{}, size)
+	for i := range values {
+		var err error
+		values[i], offset, err = d.decodeValue(offset, depth)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return values, offset, nil
+}
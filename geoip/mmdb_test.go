@@ -0,0 +1,336 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"math"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testNetwork struct {
+	cidr string
+	data map[string]interface{}
+}
+
+// buildDatabase builds a MaxMind DB file with 24 bits records, the IPv4 networks being
+// stored in the ::/96 subtree of the IPv6 databases.
+func buildDatabase(t *testing.T, ipVersion int, networks []testNetwork) []byte {
+	t.Helper()
+
+	type record struct {
+		node int
+		data int
+	}
+	// a node record refers to another node when node > 0, to data when data >= 0, and is empty otherwise
+	nodes := [][2]record{{{data: -1}, {data: -1}}}
+	var dataSection []byte
+
+	for _, network := range networks {
+		_, ipNet, err := net.ParseCIDR(network.cidr)
+		require.NoError(t, err)
+
+		ip := ipNet.IP
+		prefixLen, _ := ipNet.Mask.Size()
+		if ipv4 := ip.To4(); ipv4 != nil && ipVersion == 6 {
+			ip = make(net.IP, net.IPv6len)
+			copy(ip[12:], ipv4)
+			prefixLen += 96
+		} else if ipv4 != nil {
+			ip = ipv4
+		}
+
+		dataOffset := len(dataSection)
+		dataSection = append(dataSection, encodeValue(network.data)...)
+
+		node := 0
+		for i := 0; i < prefixLen; i++ {
+			bit := int(ip[i>>3]>>(7-uint(i%8))) & 1
+			if i == prefixLen-1 {
+				nodes[node][bit] = record{data: dataOffset}
+				break
+			}
+			if nodes[node][bit].node == 0 {
+				nodes = append(nodes, [2]record{{data: -1}, {data: -1}})
+				nodes[node][bit] = record{node: len(nodes) - 1, data: -1}
+			}
+			node = nodes[node][bit].node
+		}
+	}
+
+	var buffer []byte
+	for _, n := range nodes {
+		for _, r := range n {
+			value := len(nodes)
+			if r.node > 0 {
+				value = r.node
+			} else if r.data >= 0 {
+				value = len(nodes) + dataSectionSeparatorSize + r.data
+			}
+			buffer = append(buffer, byte(value>>16), byte(value>>8), byte(value))
+		}
+	}
+	buffer = append(buffer, make([]byte, dataSectionSeparatorSize)...)
+	buffer = append(buffer, dataSection...)
+	buffer = append(buffer, metadataStartMarker...)
+	buffer = append(buffer, encodeValue(map[string]interface{}{
+		"node_count":    uint32(len(nodes)),
+		"record_size":   uint32(24),
+		"ip_version":    uint32(ipVersion),
+		"database_type": "Test",
+	})...)
+	return buffer
+}
+
+// encodeValue encodes the strings shorter than 285 bytes, 32 bits unsigned integers and maps of a MaxMind DB data section.
+func encodeValue(value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		if len(v) >= 29 {
+			return append([]byte{typeString<<5 | 29, byte(len(v) - 29)}, v...)
+		}
+		return append([]byte{typeString<<5 | byte(len(v))}, v...)
+	case uint32:
+		b := []byte{typeUint32<<5 | 4, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(b[1:], v)
+		return b
+	case map[string]interface{}:
+		var keys []string
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		b := []byte{typeMap<<5 | byte(len(v))}
+		for _, key := range keys {
+			b = append(b, encodeValue(key)...)
+			b = append(b, encodeValue(v[key])...)
+		}
+		return b
+	default:
+		panic("unsupported value")
+	}
+}
+
+func TestReaderLookup(t *testing.T) {
+	ipv4Networks := []testNetwork{
+		{cidr: "1.2.3.0/24", data: map[string]interface{}{"name": "IPv4 network"}},
+	}
+	ipv6Networks := append([]testNetwork{
+		{cidr: "2001:db8::/32", data: map[string]interface{}{"name": "IPv6 network"}},
+	}, ipv4Networks...)
+
+	testCases := []struct {
+		desc      string
+		ipVersion int
+		ip        string
+		expected  interface{}
+	}{
+		{
+			desc:      "IPv4 address in IPv6 database",
+			ipVersion: 6,
+			ip:        "1.2.3.4",
+			expected:  map[string]interface{}{"name": "IPv4 network"},
+		},
+		{
+			desc:      "IPv6 address in IPv6 database",
+			ipVersion: 6,
+			ip:        "2001:db8::1",
+			expected:  map[string]interface{}{"name": "IPv6 network"},
+		},
+		{
+			desc:      "unknown IPv4 address in IPv6 database",
+			ipVersion: 6,
+			ip:        "1.2.4.4",
+		},
+		{
+			desc:      "unknown IPv6 address in IPv6 database",
+			ipVersion: 6,
+			ip:        "2001:db9::1",
+		},
+		{
+			desc:      "IPv4 address in IPv4 database",
+			ipVersion: 4,
+			ip:        "1.2.3.4",
+			expected:  map[string]interface{}{"name": "IPv4 network"},
+		},
+		{
+			desc:      "IPv6 address in IPv4 database",
+			ipVersion: 4,
+			ip:        "2001:db8::1",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			networks := ipv6Networks
+			if test.ipVersion == 4 {
+				networks = ipv4Networks
+			}
+
+			r, err := newReader(buildDatabase(t, test.ipVersion, networks))
+			require.NoError(t, err)
+			assert.Equal(t, "Test", r.databaseType)
+
+			record, err := r.lookup(net.ParseIP(test.ip))
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, record)
+		})
+	}
+}
+
+func TestNewReaderInvalidDatabase(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		buffer []byte
+	}{
+		{
+			desc:   "missing metadata",
+			buffer: []byte("not a database"),
+		},
+		{
+			desc:   "invalid record size",
+			buffer: append(append([]byte(nil), metadataStartMarker...), encodeValue(map[string]interface{}{"node_count": uint32(0), "record_size": uint32(16), "ip_version": uint32(6)})...),
+		},
+		{
+			desc:   "truncated search tree",
+			buffer: append(append([]byte(nil), metadataStartMarker...), encodeValue(map[string]interface{}{"node_count": uint32(10), "record_size": uint32(24), "ip_version": uint32(4)})...),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newReader(test.buffer)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecoderDecode(t *testing.T) {
+	longString := string(make([]byte, 300))
+
+	testCases := []struct {
+		desc        string
+		data        []byte
+		offset      uint
+		expected    interface{}
+		expectedErr bool
+	}{
+		{
+			desc:     "string",
+			data:     []byte{0x43, 'f', 'o', 'o'},
+			expected: "foo",
+		},
+		{
+			desc:     "long string",
+			data:     append([]byte{0x5e, 0x00, 0x0f}, longString...),
+			expected: longString,
+		},
+		{
+			desc:     "double",
+			data:     append([]byte{0x68}, float64Bytes(1.5)...),
+			expected: 1.5,
+		},
+		{
+			desc:     "uint16",
+			data:     []byte{0xa2, 0x01, 0x00},
+			expected: uint64(256),
+		},
+		{
+			desc:     "uint64",
+			data:     []byte{0x03, 0x02, 0x01, 0x00, 0x00},
+			expected: uint64(65536),
+		},
+		{
+			desc:     "negative int32",
+			data:     []byte{0x04, 0x01, 0xff, 0xff, 0xff, 0xfe},
+			expected: int64(-2),
+		},
+		{
+			desc:     "true",
+			data:     []byte{0x01, 0x07},
+			expected: true,
+		},
+		{
+			desc:     "false",
+			data:     []byte{0x00, 0x07},
+			expected: false,
+		},
+		{
+			desc:     "array",
+			data:     []byte{0x02, 0x04, 0x41, 'a', 0x41, 'b'},
+			expected: []interface{}{"a", "b"},
+		},
+		{
+			desc:     "map",
+			data:     []byte{0xe1, 0x41, 'k', 0x41, 'v'},
+			expected: map[string]interface{}{"k": "v"},
+		},
+		{
+			desc:     "pointer",
+			data:     []byte{0x43, 'f', 'o', 'o', 0x20, 0x00},
+			offset:   4,
+			expected: "foo",
+		},
+		{
+			desc:        "pointer to a pointer",
+			data:        []byte{0x20, 0x02, 0x20, 0x00},
+			expectedErr: true,
+		},
+		{
+			desc:        "truncated string",
+			data:        []byte{0x43, 'f', 'o'},
+			expectedErr: true,
+		},
+		{
+			desc:        "non-string map key",
+			data:        []byte{0xe1, 0xa1, 0x01, 0x41, 'v'},
+			expectedErr: true,
+		},
+		{
+			desc:        "array larger than the data",
+			data:        []byte{0x1f, 0x04, 0xff, 0xff, 0xff},
+			expectedErr: true,
+		},
+		{
+			desc:        "map larger than the data",
+			data:        []byte{0xff, 0xff, 0xff, 0xff},
+			expectedErr: true,
+		},
+		{
+			desc:        "map containing a pointer to itself",
+			data:        []byte{0xe1, 0x41, 'k', 0x20, 0x00},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			value, _, err := decoder(test.data).decode(test.offset)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func float64Bytes(f float64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, math.Float64bits(f))
+	return b
+}
@@ -0,0 +1,166 @@
+package healthcheck
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/http2"
+)
+
+const (
+	grpcHealthCheckPath = "/grpc.health.v1.Health/Check"
+
+	// grpcServing is the SERVING value of the grpc.health.v1.HealthCheckResponse.ServingStatus enum.
+	grpcServing = 1
+)
+
+// h2cTransport reaches the gRPC servers over HTTP/2 without TLS.
+var h2cTransport = &http2.Transport{
+	AllowHTTP: true,
+	DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+		return net.Dial(network, addr)
+	},
+}
+
+func (backend *BackendHealthCheck) newGRPCRequest(serverURL *url.URL) (*http.Request, error) {
+	u := &url.URL{}
+	*u = *serverURL
+	if backend.Port != 0 {
+		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(backend.Port))
+	}
+	u.Path = grpcHealthCheckPath
+	u.RawPath = ""
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(encodeGRPCHealthCheckRequest(backend.Service)))
+	if err != nil {
+		return nil, err
+	}
+	backend.setProbeHeaders(req)
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	return req, nil
+}
+
+// checkGRPCHealth calls the grpc.health.v1.Health/Check RPC of the server,
+// over HTTP/2 with TLS for the https servers and without TLS (h2c) otherwise.
+func checkGRPCHealth(serverURL *url.URL, backend *BackendHealthCheck) error {
+	transport := backend.Options.Transport
+	if serverURL.Scheme != "https" {
+		transport = backend.h2cTransport
+	}
+	client := http.Client{
+		Timeout:   backend.requestTimeout,
+		Transport: transport,
+	}
+
+	req, err := backend.newGRPCRequest(serverURL)
+	if err != nil {
+		return fmt.Errorf("failed to create gRPC request: %s", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("gRPC request failed: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received non-200 status code: %v", resp.StatusCode)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/grpc") {
+		return fmt.Errorf("received non-gRPC content type: %q", resp.Header.Get("Content-Type"))
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read gRPC response: %s", err)
+	}
+
+	// the status is sent in the trailers, or in the headers of the responses without message
+	status := resp.Trailer.Get("Grpc-Status")
+	if len(status) == 0 {
+		status = resp.Header.Get("Grpc-Status")
+	}
+	if status != "0" {
+		return fmt.Errorf("received gRPC status %s: %s", status, resp.Trailer.Get("Grpc-Message"))
+	}
+
+	servingStatus, err := decodeGRPCHealthCheckResponse(body)
+	if err != nil {
+		return fmt.Errorf("failed to decode gRPC response: %s", err)
+	}
+	if servingStatus != grpcServing {
+		return fmt.Errorf("received non-serving status: %d", servingStatus)
+	}
+	return nil
+}
+
+// encodeGRPCHealthCheckRequest returns the length-prefixed message of a grpc.health.v1.HealthCheckRequest.
+func encodeGRPCHealthCheckRequest(service string) []byte {
+	var message []byte
+	if len(service) > 0 {
+		// field 1 (service), length-delimited
+		message = append(message, 1<<3|proto.WireBytes)
+		message = append(message, proto.EncodeVarint(uint64(len(service)))...)
+		message = append(message, service...)
+	}
+
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	return append(frame, message...)
+}
+
+// decodeGRPCHealthCheckResponse returns the status of a length-prefixed grpc.health.v1.HealthCheckResponse.
+func decodeGRPCHealthCheckResponse(frame []byte) (uint64, error) {
+	if len(frame) < 5 {
+		return 0, errors.New("truncated message")
+	}
+	if frame[0] != 0 {
+		return 0, errors.New("compressed message")
+	}
+	length := binary.BigEndian.Uint32(frame[1:5])
+	if uint32(len(frame)-5) < length {
+		return 0, errors.New("truncated message")
+	}
+
+	var status uint64
+	message := frame[5 : 5+length]
+	for len(message) > 0 {
+		key, n := proto.DecodeVarint(message)
+		if n == 0 {
+			return 0, errors.New("invalid field key")
+		}
+		message = message[n:]
+
+		switch key & 7 {
+		case proto.WireVarint:
+			value, n := proto.DecodeVarint(message)
+			if n == 0 {
+				return 0, errors.New("invalid varint")
+			}
+			message = message[n:]
+			if key>>3 == 1 {
+				status = value
+			}
+		case proto.WireBytes:
+			length, n := proto.DecodeVarint(message)
+			if n == 0 || uint64(len(message)-n) < length {
+				return 0, errors.New("invalid length-delimited field")
+			}
+			message = message[uint64(n)+length:]
+		default:
+			return 0, fmt.Errorf("unexpected wire type %d", key&7)
+		}
+	}
+	return status, nil
+}
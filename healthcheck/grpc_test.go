@@ -0,0 +1,193 @@
+package healthcheck
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+)
+
+// startHTTP2TLSServer starts a TLS test server speaking HTTP/2, and returns it along with a transport reaching it.
+func startHTTP2TLSServer(t *testing.T, handler http.Handler) (*httptest.Server, http.RoundTripper) {
+	ts := httptest.NewUnstartedServer(handler)
+	require.NoError(t, http2.ConfigureServer(ts.Config, nil))
+	ts.TLS = &tls.Config{NextProtos: []string{http2.NextProtoTLS}}
+	ts.StartTLS()
+
+	transport := ts.Client().Transport.(*http.Transport)
+	require.NoError(t, http2.ConfigureTransport(transport))
+	return ts, transport
+}
+
+// grpcHealthHandler answers the grpc.health.v1.Health/Check RPC with the given serving status and gRPC status.
+func grpcHealthHandler(t *testing.T, expectedService string, servingStatus byte, grpcStatus string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, grpcHealthCheckPath, req.URL.Path)
+		assert.Equal(t, "application/grpc", req.Header.Get("Content-Type"))
+
+		body := make([]byte, 512)
+		n, _ := req.Body.Read(body)
+		assert.Equal(t, encodeGRPCHealthCheckRequest(expectedService), body[:n])
+
+		rw.Header().Set("Content-Type", "application/grpc")
+		rw.Header().Set("Trailer", "Grpc-Status")
+		rw.WriteHeader(http.StatusOK)
+		if grpcStatus == "0" {
+			rw.Write([]byte{0, 0, 0, 0, 2, 1 << 3, servingStatus})
+		}
+		rw.Header().Set("Grpc-Status", grpcStatus)
+	})
+}
+
+func TestCheckGRPCHealth(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		service       string
+		servingStatus byte
+		grpcStatus    string
+		expectedErr   bool
+	}{
+		{
+			desc:          "serving server",
+			servingStatus: 1,
+			grpcStatus:    "0",
+		},
+		{
+			desc:          "serving service",
+			service:       "grpc.health.v1.Health",
+			servingStatus: 1,
+			grpcStatus:    "0",
+		},
+		{
+			desc:          "not serving server",
+			servingStatus: 2,
+			grpcStatus:    "0",
+			expectedErr:   true,
+		},
+		{
+			desc:        "unknown service",
+			service:     "foo",
+			grpcStatus:  "5",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ts, transport := startHTTP2TLSServer(t, grpcHealthHandler(t, test.service, test.servingStatus, test.grpcStatus))
+			defer ts.Close()
+
+			backend := NewBackendHealthCheck(Options{
+				Type:      types.HealthCheckTypeGRPC,
+				Service:   test.service,
+				Transport: transport,
+			}, "backendName")
+
+			err := checkHealth(testhelpers.MustParseURL(ts.URL), backend)
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckGRPCHealthH2C(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	h2s := &http2.Server{}
+	handler := grpcHealthHandler(t, "", 1, "0")
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go h2s.ServeConn(conn, &http2.ServeConnOpts{Handler: handler})
+		}
+	}()
+
+	backend := NewBackendHealthCheck(Options{Type: types.HealthCheckTypeGRPC}, "backendName")
+
+	err = checkHealth(testhelpers.MustParseURL("http://"+listener.Addr().String()), backend)
+	assert.NoError(t, err)
+}
+
+func TestCheckGRPCHealthNonGRPCServer(t *testing.T) {
+	ts, transport := startHTTP2TLSServer(t, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	backend := NewBackendHealthCheck(Options{
+		Type:      types.HealthCheckTypeGRPC,
+		Transport: transport,
+	}, "backendName")
+
+	err := checkHealth(testhelpers.MustParseURL(ts.URL), backend)
+	assert.Error(t, err)
+}
+
+func TestDecodeGRPCHealthCheckResponse(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		frame          []byte
+		expectedStatus uint64
+		expectedErr    bool
+	}{
+		{
+			desc:           "serving",
+			frame:          []byte{0, 0, 0, 0, 2, 0x08, 1},
+			expectedStatus: 1,
+		},
+		{
+			desc:           "empty message",
+			frame:          []byte{0, 0, 0, 0, 0},
+			expectedStatus: 0,
+		},
+		{
+			desc:           "unknown field skipped",
+			frame:          []byte{0, 0, 0, 0, 5, 0x12, 1, 'a', 0x08, 1},
+			expectedStatus: 1,
+		},
+		{
+			desc:        "truncated",
+			frame:       []byte{0, 0, 0, 0, 4, 0x08, 1},
+			expectedErr: true,
+		},
+		{
+			desc:        "compressed",
+			frame:       []byte{1, 0, 0, 0, 2, 0x08, 1},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			status, err := decodeGRPCHealthCheckResponse(test.frame)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedStatus, status)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package healthcheck
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
@@ -11,9 +12,13 @@ import (
 	"time"
 
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
+	"github.com/containous/traefik/proxyprotocol"
 	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
 	"github.com/go-kit/kit/metrics"
 	"github.com/vulcand/oxy/roundrobin"
+	"golang.org/x/net/http2"
 )
 
 var singleton *HealthCheck
@@ -29,15 +34,29 @@ func GetHealthCheck(metrics metricsRegistry) *HealthCheck {
 
 // Options are the public health check options.
 type Options struct {
-	Path      string
-	Port      int
-	Transport http.RoundTripper
-	Interval  time.Duration
-	LB        LoadBalancer
+	Type     string
+	Path     string
+	Port     int
+	Service  string
+	Hostname string
+	Headers  map[string]string
+	// ProxyProtocol is the version of the PROXY protocol header sent by the probes, 0 to disable it.
+	ProxyProtocol int
+	Transport     http.RoundTripper
+	Interval      time.Duration
+	Timeout       time.Duration
+	LB            LoadBalancer
 }
 
 func (opt Options) String() string {
-	return fmt.Sprintf("[Path: %s Port: %d Interval: %s]", opt.Path, opt.Port, opt.Interval)
+	switch opt.Type {
+	case types.HealthCheckTypeGRPC:
+		return fmt.Sprintf("[Type: %s Service: %s Port: %d Interval: %s Timeout: %s]", opt.Type, opt.Service, opt.Port, opt.Interval, opt.Timeout)
+	case types.HealthCheckTypeTCP:
+		return fmt.Sprintf("[Type: %s Port: %d Interval: %s Timeout: %s]", opt.Type, opt.Port, opt.Interval, opt.Timeout)
+	default:
+		return fmt.Sprintf("[Path: %s Port: %d Interval: %s Timeout: %s]", opt.Path, opt.Port, opt.Interval, opt.Timeout)
+	}
 }
 
 // BackendHealthCheck HealthCheck configuration for a backend
@@ -46,6 +65,8 @@ type BackendHealthCheck struct {
 	name           string
 	disabledURLs   []*url.URL
 	requestTimeout time.Duration
+	// h2cTransport reaches the gRPC servers without TLS.
+	h2cTransport http.RoundTripper
 }
 
 //HealthCheck struct
@@ -77,10 +98,100 @@ type metricsRegistry interface {
 
 // NewBackendHealthCheck Instantiate a new BackendHealthCheck
 func NewBackendHealthCheck(options Options, backendName string) *BackendHealthCheck {
-	return &BackendHealthCheck{
+	requestTimeout := 5 * time.Second
+	if options.Timeout > 0 {
+		requestTimeout = options.Timeout
+	}
+
+	backend := &BackendHealthCheck{
 		Options:        options,
 		name:           backendName,
-		requestTimeout: 5 * time.Second,
+		requestTimeout: requestTimeout,
+		h2cTransport:   h2cTransport,
+	}
+	if options.ProxyProtocol > 0 || len(options.Hostname) > 0 {
+		backend.Transport = newProbeTransport(options)
+	}
+	if options.ProxyProtocol > 0 {
+		dial := proxyprotocol.Dialer((&net.Dialer{}).DialContext, options.ProxyProtocol)
+		backend.h2cTransport = &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return dial(context.Background(), network, addr)
+			},
+		}
+	}
+	return backend
+}
+
+// cloneTransport returns a transport with the settings of the given one, without its connections.
+func cloneTransport(transport *http.Transport) *http.Transport {
+	clone := &http.Transport{
+		Proxy:                  transport.Proxy,
+		DialContext:            transport.DialContext,
+		Dial:                   transport.Dial,
+		DialTLS:                transport.DialTLS,
+		TLSHandshakeTimeout:    transport.TLSHandshakeTimeout,
+		DisableKeepAlives:      transport.DisableKeepAlives,
+		DisableCompression:     transport.DisableCompression,
+		MaxIdleConns:           transport.MaxIdleConns,
+		MaxIdleConnsPerHost:    transport.MaxIdleConnsPerHost,
+		IdleConnTimeout:        transport.IdleConnTimeout,
+		ResponseHeaderTimeout:  transport.ResponseHeaderTimeout,
+		ExpectContinueTimeout:  transport.ExpectContinueTimeout,
+		ProxyConnectHeader:     transport.ProxyConnectHeader,
+		MaxResponseHeaderBytes: transport.MaxResponseHeaderBytes,
+	}
+	if transport.TLSClientConfig != nil {
+		clone.TLSClientConfig = transport.TLSClientConfig.Clone()
+	}
+	if transport.TLSNextProto != nil {
+		clone.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper, len(transport.TLSNextProto))
+		for proto, roundTripper := range transport.TLSNextProto {
+			clone.TLSNextProto[proto] = roundTripper
+		}
+	}
+	return clone
+}
+
+// newProbeTransport returns a copy of the forwarding transport sending the PROXY protocol header
+// and the server name of the probes, leaving the connections of the forwarded requests untouched.
+func newProbeTransport(options Options) *http.Transport {
+	transport := &http.Transport{}
+	if forwarding, ok := options.Transport.(*http.Transport); ok {
+		transport = cloneTransport(forwarding)
+	}
+
+	if len(options.Hostname) > 0 {
+		if transport.TLSClientConfig != nil {
+			transport.TLSClientConfig = transport.TLSClientConfig.Clone()
+		} else {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.ServerName = options.Hostname
+	}
+
+	if options.ProxyProtocol > 0 {
+		dial := transport.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		transport.DialContext = proxyprotocol.Dialer(dial, options.ProxyProtocol)
+	}
+
+	// the HTTP/2 connections must not be shared with the forwarding transport
+	transport.TLSNextProto = nil
+	http2.ConfigureTransport(transport)
+	return transport
+}
+
+// setProbeHeaders sets the Host header and the custom headers of the probes.
+func (backend *BackendHealthCheck) setProbeHeaders(req *http.Request) {
+	for name, value := range backend.Headers {
+		req.Header.Set(name, value)
+	}
+	if len(backend.Hostname) > 0 {
+		req.Host = backend.Hostname
 	}
 }
 
@@ -147,6 +258,14 @@ func (hc *HealthCheck) checkBackend(backend *BackendHealthCheck) {
 		labelValues := []string{"backend", backend.name, "url", url.String()}
 		hc.metrics.BackendServerUpGauge().With(labelValues...).Set(serverUpMetricValue)
 	}
+
+	if len(enabledURLs) > 0 && len(backend.LB.Servers()) == 0 {
+		notification.Notify(notification.Event{
+			Type:    notification.BackendDown,
+			Message: "All the servers of the backend failed their health check",
+			Backend: backend.name,
+		})
+	}
 }
 
 func (backend *BackendHealthCheck) newRequest(serverURL *url.URL) (*http.Request, error) {
@@ -166,6 +285,13 @@ func (backend *BackendHealthCheck) newRequest(serverURL *url.URL) (*http.Request
 // checkHealth returns a nil error in case it was successful and otherwise
 // a non-nil error with a meaningful description why the health check failed.
 func checkHealth(serverURL *url.URL, backend *BackendHealthCheck) error {
+	switch backend.Type {
+	case types.HealthCheckTypeGRPC:
+		return checkGRPCHealth(serverURL, backend)
+	case types.HealthCheckTypeTCP:
+		return checkTCPHealth(serverURL, backend)
+	}
+
 	client := http.Client{
 		Timeout:   backend.requestTimeout,
 		Transport: backend.Options.Transport,
@@ -174,6 +300,7 @@ func checkHealth(serverURL *url.URL, backend *BackendHealthCheck) error {
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP request: %s", err)
 	}
+	backend.setProbeHeaders(req)
 
 	resp, err := client.Do(req)
 	if err == nil {
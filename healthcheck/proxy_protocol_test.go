@@ -0,0 +1,113 @@
+package healthcheck
+
+import (
+	"bufio"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// proxyProtocolServer answers the HTTP requests sent after a PROXY protocol header,
+// recording the header line and the request.
+func proxyProtocolServer(t *testing.T) (net.Listener, <-chan string, <-chan *http.Request) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	headers := make(chan string, 10)
+	requests := make(chan *http.Request, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			reader := bufio.NewReader(conn)
+			header, _ := reader.ReadString('\n')
+			headers <- header
+			if req, err := http.ReadRequest(reader); err == nil {
+				requests <- req
+				io.WriteString(conn, "HTTP/1.1 200 OK\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+			}
+			conn.Close()
+		}
+	}()
+	return listener, headers, requests
+}
+
+func TestCheckHealthProbeOptions(t *testing.T) {
+	listener, headers, requests := proxyProtocolServer(t)
+	defer listener.Close()
+
+	backend := NewBackendHealthCheck(Options{
+		Path:          "/health",
+		Hostname:      "foo.com",
+		Headers:       map[string]string{"X-Probe": "traefik"},
+		ProxyProtocol: 1,
+		Transport:     &http.Transport{},
+		Timeout:       time.Second,
+	}, "backendName")
+
+	err := checkHealth(testhelpers.MustParseURL("http://"+listener.Addr().String()), backend)
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^PROXY TCP4 127\.0\.0\.1 127\.0\.0\.1 \d+ \d+\r\n$`, <-headers)
+	req := <-requests
+	assert.Equal(t, "foo.com", req.Host)
+	assert.Equal(t, "traefik", req.Header.Get("X-Probe"))
+	assert.Equal(t, "/health", req.URL.Path)
+}
+
+func TestCheckTCPHealthProxyProtocol(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		header := make([]byte, 16)
+		io.ReadFull(conn, header)
+		received <- header
+	}()
+
+	backend := NewBackendHealthCheck(Options{
+		Type:          types.HealthCheckTypeTCP,
+		ProxyProtocol: 2,
+		Timeout:       time.Second,
+	}, "backendName")
+
+	err = checkHealth(testhelpers.MustParseURL("http://"+listener.Addr().String()), backend)
+	require.NoError(t, err)
+
+	header := <-received
+	assert.Equal(t, []byte("\r\n\r\n\x00\r\nQUIT\n"), header[:12])
+	assert.Equal(t, []byte{0x21, 0x11, 0x00, 12}, header[12:16])
+}
+
+func TestNewProbeTransport(t *testing.T) {
+	forwarding := &http.Transport{
+		TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
+		ResponseHeaderTimeout: time.Second,
+		MaxIdleConnsPerHost:   10,
+	}
+
+	transport := newProbeTransport(Options{Hostname: "foo.com", Transport: forwarding})
+
+	assert.Equal(t, time.Second, transport.ResponseHeaderTimeout)
+	assert.Equal(t, 10, transport.MaxIdleConnsPerHost)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+	assert.Equal(t, "foo.com", transport.TLSClientConfig.ServerName)
+	assert.Empty(t, forwarding.TLSClientConfig.ServerName, "the forwarding transport is modified")
+}
@@ -0,0 +1,67 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/containous/traefik/proxyprotocol"
+)
+
+// checkTCPHealth only validates that a TCP connection to the server can be established,
+// followed by a TLS handshake for the https servers.
+func checkTCPHealth(serverURL *url.URL, backend *BackendHealthCheck) error {
+	port := serverURL.Port()
+	if backend.Port != 0 {
+		port = strconv.Itoa(backend.Port)
+	}
+	if len(port) == 0 {
+		port = "80"
+		if serverURL.Scheme == "https" {
+			port = "443"
+		}
+	}
+	address := net.JoinHostPort(serverURL.Hostname(), port)
+
+	deadline := time.Now().Add(backend.requestTimeout)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	dial := (&net.Dialer{}).DialContext
+	if backend.ProxyProtocol > 0 {
+		dial = proxyprotocol.Dialer(dial, backend.ProxyProtocol)
+	}
+	conn, err := dial(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("TCP connection failed: %s", err)
+	}
+	defer conn.Close()
+
+	if serverURL.Scheme != "https" {
+		return nil
+	}
+
+	tlsConn := tls.Client(conn, backend.tlsClientConfig(serverURL.Hostname()))
+	tlsConn.SetDeadline(deadline)
+	if err := tlsConn.Handshake(); err != nil {
+		return fmt.Errorf("TLS handshake failed: %s", err)
+	}
+	return nil
+}
+
+// tlsClientConfig returns the TLS configuration of the forwarding transport, if any.
+func (backend *BackendHealthCheck) tlsClientConfig(serverName string) *tls.Config {
+	config := &tls.Config{}
+	if transport, ok := backend.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		config = transport.TLSClientConfig.Clone()
+	}
+	if len(config.ServerName) == 0 {
+		config.ServerName = serverName
+	}
+	return config
+}
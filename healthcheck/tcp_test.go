@@ -0,0 +1,87 @@
+package healthcheck
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTCPHealth(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddr := closedListener.Addr().String()
+	closedListener.Close()
+
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+
+	testCases := []struct {
+		desc        string
+		serverURL   string
+		transport   http.RoundTripper
+		expectedErr bool
+	}{
+		{
+			desc:      "accepting server",
+			serverURL: "http://" + listener.Addr().String(),
+		},
+		{
+			desc:        "closed port",
+			serverURL:   "http://" + closedAddr,
+			expectedErr: true,
+		},
+		{
+			desc:      "TLS handshake",
+			serverURL: tlsServer.URL,
+			transport: tlsServer.Client().Transport,
+		},
+		{
+			desc:        "TLS handshake with an untrusted certificate",
+			serverURL:   tlsServer.URL,
+			expectedErr: true,
+		},
+		{
+			desc:        "TLS handshake with a non-TLS server",
+			serverURL:   "https://" + listener.Addr().String(),
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			backend := NewBackendHealthCheck(Options{
+				Type:      types.HealthCheckTypeTCP,
+				Transport: test.transport,
+				Timeout:   time.Second,
+			}, "backendName")
+
+			err := checkHealth(testhelpers.MustParseURL(test.serverURL), backend)
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
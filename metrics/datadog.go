@@ -29,8 +29,12 @@ const (
 	ddEntrypointReqsName          = "entrypoint.request.total"
 	ddEntrypointReqDurationName   = "entrypoint.request.duration"
 	ddEntrypointOpenConnsName     = "entrypoint.connections.open"
+	ddEntrypointClientConnsName   = "entrypoint.connections.client"
+	ddEntrypointRejectedReqsName  = "entrypoint.request.rejected.total"
 	ddOpenConnsName               = "backend.connections.open"
 	ddServerUpName                = "backend.server.up"
+	ddConcurrencyLimitName        = "backend.concurrency.limit"
+	ddTLSCertsNotAfterName        = "tls.certs.notAfterTimestamp"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -48,11 +52,15 @@ func RegisterDatadog(config *types.Datadog) Registry {
 		entrypointReqsCounter:          datadogClient.NewCounter(ddEntrypointReqsName, 1.0),
 		entrypointReqDurationHistogram: datadogClient.NewHistogram(ddEntrypointReqDurationName, 1.0),
 		entrypointOpenConnsGauge:       datadogClient.NewGauge(ddEntrypointOpenConnsName),
+		entrypointClientConnsGauge:     datadogClient.NewGauge(ddEntrypointClientConnsName),
+		entrypointRejectedReqsCounter:  datadogClient.NewCounter(ddEntrypointRejectedReqsName, 1.0),
 		backendReqsCounter:             datadogClient.NewCounter(ddMetricsBackendReqsName, 1.0),
 		backendReqDurationHistogram:    datadogClient.NewHistogram(ddMetricsBackendLatencyName, 1.0),
 		backendRetriesCounter:          datadogClient.NewCounter(ddRetriesTotalName, 1.0),
 		backendOpenConnsGauge:          datadogClient.NewGauge(ddOpenConnsName),
 		backendServerUpGauge:           datadogClient.NewGauge(ddServerUpName),
+		backendConcurrencyLimitGauge:   datadogClient.NewGauge(ddConcurrencyLimitName),
+		tlsCertsNotAfterGauge:          datadogClient.NewGauge(ddTLSCertsNotAfterName),
 	}
 
 	return registry
@@ -33,7 +33,11 @@ func TestDatadog(t *testing.T) {
 		"traefik.entrypoint.request.total:1.000000|c|#entrypoint:test\n",
 		"traefik.entrypoint.request.duration:10000.000000|h|#entrypoint:test\n",
 		"traefik.entrypoint.connections.open:1.000000|g|#entrypoint:test\n",
+		"traefik.entrypoint.connections.client:3.000000|g|#entrypoint:test\n",
+		"traefik.entrypoint.request.rejected.total:1.000000|c|#entrypoint:test\n",
 		"traefik.backend.server.up:1.000000|g|#backend:test,url:http://127.0.0.1,one:two\n",
+		"traefik.backend.concurrency.limit:20.000000|g|#backend:test\n",
+		"traefik.tls.certs.notAfterTimestamp:1700000000.000000|g|#cn:example.com,serial:42,sans:example.com\n",
 	}
 
 	udp.ShouldReceiveAll(t, expected, func() {
@@ -47,6 +51,10 @@ func TestDatadog(t *testing.T) {
 		datadogRegistry.EntrypointReqsCounter().With("entrypoint", "test").Add(1)
 		datadogRegistry.EntrypointReqDurationHistogram().With("entrypoint", "test").Observe(10000)
 		datadogRegistry.EntrypointOpenConnsGauge().With("entrypoint", "test").Set(1)
+		datadogRegistry.EntrypointClientConnsGauge().With("entrypoint", "test").Set(3)
+		datadogRegistry.EntrypointRejectedReqsCounter().With("entrypoint", "test").Add(1)
 		datadogRegistry.BackendServerUpGauge().With("backend", "test", "url", "http://127.0.0.1", "one", "two").Set(1)
+		datadogRegistry.BackendConcurrencyLimitGauge().With("backend", "test").Set(20)
+		datadogRegistry.TLSCertsNotAfterGauge().With("cn", "example.com", "serial", "42", "sans", "example.com").Set(1700000000)
 	})
 }
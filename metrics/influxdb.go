@@ -28,6 +28,7 @@ const (
 	influxDBMetricsReqsName    = "traefik.requests.total"
 	influxDBMetricsLatencyName = "traefik.request.duration"
 	influxDBRetriesTotalName   = "traefik.backend.retries.total"
+	influxDBTLSCertsNotAfter   = "traefik.tls.certs.notAfterTimestamp"
 )
 
 // RegisterInfluxDB registers the metrics pusher if this didn't happen yet and creates a InfluxDB Registry instance.
@@ -41,6 +42,7 @@ func RegisterInfluxDB(config *types.InfluxDB) Registry {
 		backendReqsCounter:          influxDBClient.NewCounter(influxDBMetricsReqsName),
 		backendReqDurationHistogram: influxDBClient.NewHistogram(influxDBMetricsLatencyName),
 		backendRetriesCounter:       influxDBClient.NewCounter(influxDBRetriesTotalName),
+		tlsCertsNotAfterGauge:       influxDBClient.NewGauge(influxDBTLSCertsNotAfter),
 	}
 }
 
@@ -19,6 +19,8 @@ type Registry interface {
 	EntrypointReqsCounter() metrics.Counter
 	EntrypointReqDurationHistogram() metrics.Histogram
 	EntrypointOpenConnsGauge() metrics.Gauge
+	EntrypointClientConnsGauge() metrics.Gauge
+	EntrypointRejectedReqsCounter() metrics.Counter
 
 	// backend metrics
 	BackendReqsCounter() metrics.Counter
@@ -26,6 +28,17 @@ type Registry interface {
 	BackendOpenConnsGauge() metrics.Gauge
 	BackendRetriesCounter() metrics.Counter
 	BackendServerUpGauge() metrics.Gauge
+	BackendConcurrencyLimitGauge() metrics.Gauge
+
+	// tenant metrics
+	TenantReqsCounter() metrics.Counter
+	TenantReqDurationHistogram() metrics.Histogram
+	TenantOpenConnsGauge() metrics.Gauge
+	TenantQuotaRejectionsCounter() metrics.Counter
+
+	// TLS metrics
+	OCSPStapleAgeGauge() metrics.Gauge
+	TLSCertsNotAfterGauge() metrics.Gauge
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -45,11 +58,20 @@ func NewMultiRegistry(registries []Registry) Registry {
 	entrypointReqsCounter := []metrics.Counter{}
 	entrypointReqDurationHistogram := []metrics.Histogram{}
 	entrypointOpenConnsGauge := []metrics.Gauge{}
+	entrypointClientConnsGauge := []metrics.Gauge{}
+	entrypointRejectedReqsCounter := []metrics.Counter{}
 	backendReqsCounter := []metrics.Counter{}
 	backendReqDurationHistogram := []metrics.Histogram{}
 	backendOpenConnsGauge := []metrics.Gauge{}
 	backendRetriesCounter := []metrics.Counter{}
 	backendServerUpGauge := []metrics.Gauge{}
+	backendConcurrencyLimitGauge := []metrics.Gauge{}
+	tenantReqsCounter := []metrics.Counter{}
+	tenantReqDurationHistogram := []metrics.Histogram{}
+	tenantOpenConnsGauge := []metrics.Gauge{}
+	tenantQuotaRejectionsCounter := []metrics.Counter{}
+	ocspStapleAgeGauge := []metrics.Gauge{}
+	tlsCertsNotAfterGauge := []metrics.Gauge{}
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -73,6 +95,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.EntrypointOpenConnsGauge() != nil {
 			entrypointOpenConnsGauge = append(entrypointOpenConnsGauge, r.EntrypointOpenConnsGauge())
 		}
+		if r.EntrypointClientConnsGauge() != nil {
+			entrypointClientConnsGauge = append(entrypointClientConnsGauge, r.EntrypointClientConnsGauge())
+		}
+		if r.EntrypointRejectedReqsCounter() != nil {
+			entrypointRejectedReqsCounter = append(entrypointRejectedReqsCounter, r.EntrypointRejectedReqsCounter())
+		}
 		if r.BackendReqsCounter() != nil {
 			backendReqsCounter = append(backendReqsCounter, r.BackendReqsCounter())
 		}
@@ -88,6 +116,27 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.BackendServerUpGauge() != nil {
 			backendServerUpGauge = append(backendServerUpGauge, r.BackendServerUpGauge())
 		}
+		if r.BackendConcurrencyLimitGauge() != nil {
+			backendConcurrencyLimitGauge = append(backendConcurrencyLimitGauge, r.BackendConcurrencyLimitGauge())
+		}
+		if r.TenantReqsCounter() != nil {
+			tenantReqsCounter = append(tenantReqsCounter, r.TenantReqsCounter())
+		}
+		if r.TenantReqDurationHistogram() != nil {
+			tenantReqDurationHistogram = append(tenantReqDurationHistogram, r.TenantReqDurationHistogram())
+		}
+		if r.TenantOpenConnsGauge() != nil {
+			tenantOpenConnsGauge = append(tenantOpenConnsGauge, r.TenantOpenConnsGauge())
+		}
+		if r.TenantQuotaRejectionsCounter() != nil {
+			tenantQuotaRejectionsCounter = append(tenantQuotaRejectionsCounter, r.TenantQuotaRejectionsCounter())
+		}
+		if r.OCSPStapleAgeGauge() != nil {
+			ocspStapleAgeGauge = append(ocspStapleAgeGauge, r.OCSPStapleAgeGauge())
+		}
+		if r.TLSCertsNotAfterGauge() != nil {
+			tlsCertsNotAfterGauge = append(tlsCertsNotAfterGauge, r.TLSCertsNotAfterGauge())
+		}
 	}
 
 	return &standardRegistry{
@@ -99,11 +148,20 @@ func NewMultiRegistry(registries []Registry) Registry {
 		entrypointReqsCounter:          multi.NewCounter(entrypointReqsCounter...),
 		entrypointReqDurationHistogram: multi.NewHistogram(entrypointReqDurationHistogram...),
 		entrypointOpenConnsGauge:       multi.NewGauge(entrypointOpenConnsGauge...),
+		entrypointClientConnsGauge:     multi.NewGauge(entrypointClientConnsGauge...),
+		entrypointRejectedReqsCounter:  multi.NewCounter(entrypointRejectedReqsCounter...),
 		backendReqsCounter:             multi.NewCounter(backendReqsCounter...),
 		backendReqDurationHistogram:    multi.NewHistogram(backendReqDurationHistogram...),
 		backendOpenConnsGauge:          multi.NewGauge(backendOpenConnsGauge...),
 		backendRetriesCounter:          multi.NewCounter(backendRetriesCounter...),
 		backendServerUpGauge:           multi.NewGauge(backendServerUpGauge...),
+		backendConcurrencyLimitGauge:   multi.NewGauge(backendConcurrencyLimitGauge...),
+		tenantReqsCounter:              multi.NewCounter(tenantReqsCounter...),
+		tenantReqDurationHistogram:     multi.NewHistogram(tenantReqDurationHistogram...),
+		tenantOpenConnsGauge:           multi.NewGauge(tenantOpenConnsGauge...),
+		tenantQuotaRejectionsCounter:   multi.NewCounter(tenantQuotaRejectionsCounter...),
+		ocspStapleAgeGauge:             multi.NewGauge(ocspStapleAgeGauge...),
+		tlsCertsNotAfterGauge:          multi.NewGauge(tlsCertsNotAfterGauge...),
 	}
 }
 
@@ -116,11 +174,20 @@ type standardRegistry struct {
 	entrypointReqsCounter          metrics.Counter
 	entrypointReqDurationHistogram metrics.Histogram
 	entrypointOpenConnsGauge       metrics.Gauge
+	entrypointClientConnsGauge     metrics.Gauge
+	entrypointRejectedReqsCounter  metrics.Counter
 	backendReqsCounter             metrics.Counter
 	backendReqDurationHistogram    metrics.Histogram
 	backendOpenConnsGauge          metrics.Gauge
 	backendRetriesCounter          metrics.Counter
 	backendServerUpGauge           metrics.Gauge
+	backendConcurrencyLimitGauge   metrics.Gauge
+	tenantReqsCounter              metrics.Counter
+	tenantReqDurationHistogram     metrics.Histogram
+	tenantOpenConnsGauge           metrics.Gauge
+	tenantQuotaRejectionsCounter   metrics.Counter
+	ocspStapleAgeGauge             metrics.Gauge
+	tlsCertsNotAfterGauge          metrics.Gauge
 }
 
 func (r *standardRegistry) IsEnabled() bool {
@@ -155,6 +222,14 @@ func (r *standardRegistry) EntrypointOpenConnsGauge() metrics.Gauge {
 	return r.entrypointOpenConnsGauge
 }
 
+func (r *standardRegistry) EntrypointClientConnsGauge() metrics.Gauge {
+	return r.entrypointClientConnsGauge
+}
+
+func (r *standardRegistry) EntrypointRejectedReqsCounter() metrics.Counter {
+	return r.entrypointRejectedReqsCounter
+}
+
 func (r *standardRegistry) BackendReqsCounter() metrics.Counter {
 	return r.backendReqsCounter
 }
@@ -174,3 +249,31 @@ func (r *standardRegistry) BackendRetriesCounter() metrics.Counter {
 func (r *standardRegistry) BackendServerUpGauge() metrics.Gauge {
 	return r.backendServerUpGauge
 }
+
+func (r *standardRegistry) BackendConcurrencyLimitGauge() metrics.Gauge {
+	return r.backendConcurrencyLimitGauge
+}
+
+func (r *standardRegistry) TenantReqsCounter() metrics.Counter {
+	return r.tenantReqsCounter
+}
+
+func (r *standardRegistry) TenantReqDurationHistogram() metrics.Histogram {
+	return r.tenantReqDurationHistogram
+}
+
+func (r *standardRegistry) TenantOpenConnsGauge() metrics.Gauge {
+	return r.tenantOpenConnsGauge
+}
+
+func (r *standardRegistry) TenantQuotaRejectionsCounter() metrics.Counter {
+	return r.tenantQuotaRejectionsCounter
+}
+
+func (r *standardRegistry) OCSPStapleAgeGauge() metrics.Gauge {
+	return r.ocspStapleAgeGauge
+}
+
+func (r *standardRegistry) TLSCertsNotAfterGauge() metrics.Gauge {
+	return r.tlsCertsNotAfterGauge
+}
@@ -24,16 +24,29 @@ const (
 	configLastReloadFailureName    = metricNamePrefix + "config_last_reload_failure"
 
 	// entrypoint
-	entrypointReqsTotalName   = metricNamePrefix + "entrypoint_requests_total"
-	entrypointReqDurationName = metricNamePrefix + "entrypoint_request_duration_seconds"
-	entrypointOpenConnsName   = metricNamePrefix + "entrypoint_open_connections"
+	entrypointReqsTotalName         = metricNamePrefix + "entrypoint_requests_total"
+	entrypointReqDurationName       = metricNamePrefix + "entrypoint_request_duration_seconds"
+	entrypointOpenConnsName         = metricNamePrefix + "entrypoint_open_connections"
+	entrypointClientConnsName       = metricNamePrefix + "entrypoint_client_connections"
+	entrypointRejectedReqsTotalName = metricNamePrefix + "entrypoint_rejected_requests_total"
 
 	// backend level
-	backendReqsTotalName    = metricNamePrefix + "backend_requests_total"
-	backendReqDurationName  = metricNamePrefix + "backend_request_duration_seconds"
-	backendOpenConnsName    = metricNamePrefix + "backend_open_connections"
-	backendRetriesTotalName = metricNamePrefix + "backend_retries_total"
-	backendServerUpName     = metricNamePrefix + "backend_server_up"
+	backendReqsTotalName        = metricNamePrefix + "backend_requests_total"
+	backendReqDurationName      = metricNamePrefix + "backend_request_duration_seconds"
+	backendOpenConnsName        = metricNamePrefix + "backend_open_connections"
+	backendRetriesTotalName     = metricNamePrefix + "backend_retries_total"
+	backendServerUpName         = metricNamePrefix + "backend_server_up"
+	backendConcurrencyLimitName = metricNamePrefix + "backend_concurrency_limit"
+
+	// tenant level
+	tenantReqsTotalName            = metricNamePrefix + "tenant_requests_total"
+	tenantReqDurationName          = metricNamePrefix + "tenant_request_duration_seconds"
+	tenantOpenConnsName            = metricNamePrefix + "tenant_open_connections"
+	tenantQuotaRejectionsTotalName = metricNamePrefix + "tenant_quota_rejections_total"
+
+	// TLS
+	ocspStapleAgeName    = metricNamePrefix + "tls_ocsp_staple_age_seconds"
+	tlsCertsNotAfterName = metricNamePrefix + "tls_certs_not_after"
 )
 
 const (
@@ -77,6 +90,10 @@ func RegisterPrometheus(config *types.Prometheus) Registry {
 		promState.ListenValueUpdates()
 	})
 
+	if config.Push != nil && prometheusPushTicker == nil {
+		prometheusPushTicker = initPrometheusPushTicker(config.Push)
+	}
+
 	configReloads := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 		Name: configReloadsTotalName,
 		Help: "Config reloads",
@@ -107,6 +124,14 @@ func RegisterPrometheus(config *types.Prometheus) Registry {
 		Name: entrypointOpenConnsName,
 		Help: "How many open connections exist on an entrypoint, partitioned by method and protocol.",
 	}, []string{"method", "protocol", "entrypoint"})
+	entrypointClientConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: entrypointClientConnsName,
+		Help: "How many client connections are accepted and not yet closed on an entrypoint.",
+	}, []string{"entrypoint"})
+	entrypointRejectedReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: entrypointRejectedReqsTotalName,
+		Help: "How many requests have been rejected on an entrypoint because of its concurrent requests limit.",
+	}, []string{"entrypoint"})
 
 	backendReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 		Name: backendReqsTotalName,
@@ -129,6 +154,37 @@ func RegisterPrometheus(config *types.Prometheus) Registry {
 		Name: backendServerUpName,
 		Help: "Backend server is up, described by gauge value of 0 or 1.",
 	}, []string{"backend", "url"})
+	backendConcurrencyLimit := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: backendConcurrencyLimitName,
+		Help: "Current adaptive concurrency limit of a backend.",
+	}, []string{"backend"})
+
+	tenantReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: tenantReqsTotalName,
+		Help: "How many HTTP requests processed for a tenant, partitioned by status code, protocol, and method.",
+	}, []string{"code", "method", "protocol", "tenant"})
+	tenantReqDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+		Name:    tenantReqDurationName,
+		Help:    "How long it took to process the request for a tenant, partitioned by status code, protocol, and method.",
+		Buckets: buckets,
+	}, []string{"code", "method", "protocol", "tenant"})
+	tenantOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: tenantOpenConnsName,
+		Help: "How many open connections exist for a tenant, partitioned by method and protocol.",
+	}, []string{"method", "protocol", "tenant"})
+	tenantQuotaRejections := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: tenantQuotaRejectionsTotalName,
+		Help: "How many requests of a tenant have been rejected, partitioned by exceeded quota.",
+	}, []string{"quota", "tenant"})
+
+	ocspStapleAge := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: ocspStapleAgeName,
+		Help: "Time elapsed since the stapled OCSP response of a certificate was produced, partitioned by domain.",
+	}, []string{"domain"})
+	tlsCertsNotAfter := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: tlsCertsNotAfterName,
+		Help: "Certificate expiration timestamp, partitioned by common name, serial number and SANs.",
+	}, []string{"cn", "serial", "sans"})
 
 	promState.describers = []func(chan<- *stdprometheus.Desc){
 		configReloads.cv.Describe,
@@ -138,11 +194,20 @@ func RegisterPrometheus(config *types.Prometheus) Registry {
 		entrypointReqs.cv.Describe,
 		entrypointReqDurations.hv.Describe,
 		entrypointOpenConns.gv.Describe,
+		entrypointClientConns.gv.Describe,
+		entrypointRejectedReqs.cv.Describe,
 		backendReqs.cv.Describe,
 		backendReqDurations.hv.Describe,
 		backendOpenConns.gv.Describe,
 		backendRetries.cv.Describe,
 		backendServerUp.gv.Describe,
+		backendConcurrencyLimit.gv.Describe,
+		tenantReqs.cv.Describe,
+		tenantReqDurations.hv.Describe,
+		tenantOpenConns.gv.Describe,
+		tenantQuotaRejections.cv.Describe,
+		ocspStapleAge.gv.Describe,
+		tlsCertsNotAfter.gv.Describe,
 	}
 	stdprometheus.MustRegister(promState)
 
@@ -155,11 +220,20 @@ func RegisterPrometheus(config *types.Prometheus) Registry {
 		entrypointReqsCounter:          entrypointReqs,
 		entrypointReqDurationHistogram: entrypointReqDurations,
 		entrypointOpenConnsGauge:       entrypointOpenConns,
+		entrypointClientConnsGauge:     entrypointClientConns,
+		entrypointRejectedReqsCounter:  entrypointRejectedReqs,
 		backendReqsCounter:             backendReqs,
 		backendReqDurationHistogram:    backendReqDurations,
 		backendOpenConnsGauge:          backendOpenConns,
 		backendRetriesCounter:          backendRetries,
 		backendServerUpGauge:           backendServerUp,
+		backendConcurrencyLimitGauge:   backendConcurrencyLimit,
+		tenantReqsCounter:              tenantReqs,
+		tenantReqDurationHistogram:     tenantReqDurations,
+		tenantOpenConnsGauge:           tenantOpenConns,
+		tenantQuotaRejectionsCounter:   tenantQuotaRejections,
+		ocspStapleAgeGauge:             ocspStapleAge,
+		tlsCertsNotAfterGauge:          tlsCertsNotAfter,
 	}
 }
 
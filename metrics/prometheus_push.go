@@ -0,0 +1,288 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	// PrometheusPushGateway pushes the metrics to a Pushgateway.
+	PrometheusPushGateway = "pushgateway"
+	// PrometheusRemoteWrite pushes the metrics to a Prometheus remote-write endpoint.
+	PrometheusRemoteWrite = "remotewrite"
+
+	defaultPrometheusPushJob = "traefik"
+)
+
+var prometheusPushTicker *time.Ticker
+
+// prometheusPusher pushes the gathered Prometheus metrics to a Pushgateway or a remote-write endpoint.
+type prometheusPusher struct {
+	protocol string
+	address  string
+	job      string
+	instance string
+	gatherer stdprometheus.Gatherer
+	client   *http.Client
+}
+
+func newPrometheusPusher(config *types.PrometheusPush, gatherer stdprometheus.Gatherer) (*prometheusPusher, error) {
+	protocol := strings.ToLower(config.Protocol)
+	if len(protocol) == 0 {
+		protocol = PrometheusPushGateway
+	}
+	if protocol != PrometheusPushGateway && protocol != PrometheusRemoteWrite {
+		return nil, fmt.Errorf("unsupported Prometheus push protocol: %s", config.Protocol)
+	}
+
+	if _, err := url.ParseRequestURI(config.Address); err != nil {
+		return nil, fmt.Errorf("invalid Prometheus push address %q: %v", config.Address, err)
+	}
+
+	job := config.Job
+	if len(job) == 0 {
+		job = defaultPrometheusPushJob
+	}
+
+	instance, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	return &prometheusPusher{
+		protocol: protocol,
+		address:  strings.TrimSuffix(config.Address, "/"),
+		job:      job,
+		instance: instance,
+		gatherer: gatherer,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// initPrometheusPushTicker starts pushing the metrics of the default Prometheus registry.
+func initPrometheusPushTicker(config *types.PrometheusPush) *time.Ticker {
+	pusher, err := newPrometheusPusher(config, stdprometheus.DefaultGatherer)
+	if err != nil {
+		log.Errorf("Unable to push Prometheus metrics: %v", err)
+		return nil
+	}
+
+	pushInterval, err := time.ParseDuration(config.PushInterval)
+	if err != nil {
+		log.Warnf("Unable to parse %s into pushInterval, using 10s as default value", config.PushInterval)
+		pushInterval = 10 * time.Second
+	}
+
+	report := time.NewTicker(pushInterval)
+
+	safe.Go(func() {
+		for range report.C {
+			if err := pusher.push(); err != nil {
+				log.Errorf("Error pushing Prometheus metrics to %s: %v", pusher.address, err)
+			}
+		}
+	})
+
+	return report
+}
+
+// StopPrometheusPush stops internal prometheusPushTicker which controls the pushing of Prometheus metrics and resets it to `nil`.
+func StopPrometheusPush() {
+	if prometheusPushTicker != nil {
+		prometheusPushTicker.Stop()
+	}
+	prometheusPushTicker = nil
+}
+
+func (p *prometheusPusher) push() error {
+	families, err := p.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+
+	if p.protocol == PrometheusRemoteWrite {
+		return p.pushRemoteWrite(families)
+	}
+	return p.pushToGateway(families)
+}
+
+// pushToGateway replaces the metrics of the job and instance grouping key on the Pushgateway.
+func (p *prometheusPusher) pushToGateway(families []*dto.MetricFamily) error {
+	buf := &bytes.Buffer{}
+	encoder := expfmt.NewEncoder(buf, expfmt.FmtText)
+	for _, family := range families {
+		if err := encoder.Encode(family); err != nil {
+			return err
+		}
+	}
+
+	pushURL := fmt.Sprintf("%s/metrics/job/%s/instance/%s", p.address, url.PathEscape(p.job), url.PathEscape(p.instance))
+	req, err := http.NewRequest(http.MethodPut, pushURL, buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", string(expfmt.FmtText))
+
+	return p.send(req)
+}
+
+// pushRemoteWrite sends the samples of the metrics as a snappy-compressed remote-write request.
+func (p *prometheusPusher) pushRemoteWrite(families []*dto.MetricFamily) error {
+	extraLabels := []*dto.LabelPair{
+		{Name: proto.String("instance"), Value: proto.String(p.instance)},
+		{Name: proto.String("job"), Value: proto.String(p.job)},
+	}
+	series := remoteWriteTimeSeries(families, extraLabels, time.Now())
+
+	req, err := http.NewRequest(http.MethodPost, p.address, bytes.NewReader(snappy.Encode(nil, encodeWriteRequest(series))))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	return p.send(req)
+}
+
+func (p *prometheusPusher) send(req *http.Request) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// timeSeries is a sample of a remote-write request, its labels being sorted by name.
+type timeSeries struct {
+	labels    []*dto.LabelPair
+	value     float64
+	timestamp int64
+}
+
+// remoteWriteTimeSeries flattens the metric families into samples, expanding the histograms and summaries
+// into their bucket, quantile, sum and count series, as the Prometheus text format does.
+func remoteWriteTimeSeries(families []*dto.MetricFamily, extraLabels []*dto.LabelPair, now time.Time) []timeSeries {
+	timestamp := now.UnixNano() / int64(time.Millisecond)
+
+	var series []timeSeries
+	for _, family := range families {
+		name := family.GetName()
+		for _, m := range family.Metric {
+			add := func(suffix string, value float64, labels ...*dto.LabelPair) {
+				ts := timeSeries{value: value, timestamp: timestamp}
+				if m.TimestampMs != nil {
+					ts.timestamp = m.GetTimestampMs()
+				}
+				ts.labels = append(ts.labels, &dto.LabelPair{Name: proto.String("__name__"), Value: proto.String(name + suffix)})
+				ts.labels = append(ts.labels, m.Label...)
+				ts.labels = append(ts.labels, labels...)
+				ts.labels = append(ts.labels, extraLabels...)
+				sort.Slice(ts.labels, func(i, j int) bool {
+					return ts.labels[i].GetName() < ts.labels[j].GetName()
+				})
+				series = append(series, ts)
+			}
+
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				add("", m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add("", m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add("", m.GetUntyped().GetValue())
+			case dto.MetricType_SUMMARY:
+				for _, q := range m.GetSummary().Quantile {
+					add("", q.GetValue(), &dto.LabelPair{Name: proto.String("quantile"), Value: proto.String(formatFloat(q.GetQuantile()))})
+				}
+				add("_sum", m.GetSummary().GetSampleSum())
+				add("_count", float64(m.GetSummary().GetSampleCount()))
+			case dto.MetricType_HISTOGRAM:
+				for _, b := range m.GetHistogram().Bucket {
+					add("_bucket", float64(b.GetCumulativeCount()), &dto.LabelPair{Name: proto.String("le"), Value: proto.String(formatFloat(b.GetUpperBound()))})
+				}
+				add("_bucket", float64(m.GetHistogram().GetSampleCount()), &dto.LabelPair{Name: proto.String("le"), Value: proto.String("+Inf")})
+				add("_sum", m.GetHistogram().GetSampleSum())
+				add("_count", float64(m.GetHistogram().GetSampleCount()))
+			}
+		}
+	}
+	return series
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// encodeWriteRequest encodes the samples as a remote-write WriteRequest protobuf message:
+//
+//   message WriteRequest { repeated TimeSeries timeseries = 1; }
+//   message TimeSeries { repeated Label labels = 1; repeated Sample samples = 2; }
+//   message Label { string name = 1; string value = 2; }
+//   message Sample { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(series []timeSeries) []byte {
+	const (
+		wireVarint  = 0
+		wireFixed64 = 1
+		wireBytes   = 2
+	)
+	tag := func(buf *proto.Buffer, field, wireType uint64) {
+		buf.EncodeVarint(field<<3 | wireType)
+	}
+
+	request := proto.NewBuffer(nil)
+	for _, ts := range series {
+		message := proto.NewBuffer(nil)
+		for _, label := range ts.labels {
+			labelMessage := proto.NewBuffer(nil)
+			tag(labelMessage, 1, wireBytes)
+			labelMessage.EncodeStringBytes(label.GetName())
+			tag(labelMessage, 2, wireBytes)
+			labelMessage.EncodeStringBytes(label.GetValue())
+
+			tag(message, 1, wireBytes)
+			message.EncodeRawBytes(labelMessage.Bytes())
+		}
+
+		sample := proto.NewBuffer(nil)
+		tag(sample, 1, wireFixed64)
+		sample.EncodeFixed64(math.Float64bits(ts.value))
+		tag(sample, 2, wireVarint)
+		sample.EncodeVarint(uint64(ts.timestamp))
+
+		tag(message, 2, wireBytes)
+		message.EncodeRawBytes(sample.Bytes())
+
+		tag(request, 1, wireBytes)
+		request.EncodeRawBytes(message.Bytes())
+	}
+	return request.Bytes()
+}
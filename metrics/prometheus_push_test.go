@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/types"
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusPushGateway(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "traefik_test_total", Help: "Test counter"})
+	registry.MustRegister(counter)
+	counter.Add(2)
+
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		method, path = req.Method, req.URL.Path
+		b, _ := ioutil.ReadAll(req.Body)
+		body = string(b)
+	}))
+	defer server.Close()
+
+	pusher, err := newPrometheusPusher(&types.PrometheusPush{Address: server.URL + "/", Job: "edge"}, registry)
+	require.NoError(t, err)
+
+	err = pusher.push()
+	require.NoError(t, err)
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/metrics/job/edge/instance/"+hostname, path)
+	assert.Contains(t, body, "traefik_test_total 2")
+}
+
+func TestPrometheusRemoteWrite(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "traefik_test_total", Help: "Test counter"})
+	registry.MustRegister(counter)
+
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		header = req.Header
+		b, _ := ioutil.ReadAll(req.Body)
+		body, _ = snappy.Decode(nil, b)
+	}))
+	defer server.Close()
+
+	pusher, err := newPrometheusPusher(&types.PrometheusPush{Protocol: "remoteWrite", Address: server.URL + "/api/v1/write"}, registry)
+	require.NoError(t, err)
+
+	err = pusher.push()
+	require.NoError(t, err)
+
+	assert.Equal(t, "snappy", header.Get("Content-Encoding"))
+	assert.Equal(t, "application/x-protobuf", header.Get("Content-Type"))
+	assert.True(t, bytes.Contains(body, []byte("traefik_test_total")), "metric name not found in the write request")
+	assert.True(t, bytes.Contains(body, []byte(defaultPrometheusPushJob)), "job label not found in the write request")
+}
+
+func TestPrometheusPushErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	pusher, err := newPrometheusPusher(&types.PrometheusPush{Address: server.URL}, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	err = pusher.push()
+	assert.EqualError(t, err, "unexpected status code 401: unauthorized")
+}
+
+func TestNewPrometheusPusherInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config *types.PrometheusPush
+	}{
+		{
+			desc:   "unknown protocol",
+			config: &types.PrometheusPush{Protocol: "graphite", Address: "http://localhost:9091"},
+		},
+		{
+			desc:   "missing address",
+			config: &types.PrometheusPush{Protocol: PrometheusRemoteWrite},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newPrometheusPusher(test.config, prometheus.NewRegistry())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRemoteWriteTimeSeries(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "traefik_test_duration_seconds",
+		Help:    "Test histogram",
+		Buckets: []float64{0.5},
+	}, []string{"code"})
+	registry.MustRegister(histogram)
+	histogram.WithLabelValues("200").Observe(0.1)
+	histogram.WithLabelValues("200").Observe(1)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	now := time.Unix(1500000000, 0)
+	series := remoteWriteTimeSeries(families, nil, now)
+
+	type sample struct {
+		labels map[string]string
+		value  float64
+	}
+	var samples []sample
+	for _, ts := range series {
+		assert.Equal(t, int64(1500000000000), ts.timestamp)
+
+		labels := map[string]string{}
+		for i, label := range ts.labels {
+			if i > 0 {
+				assert.True(t, ts.labels[i-1].GetName() < label.GetName(), "labels are not sorted")
+			}
+			labels[label.GetName()] = label.GetValue()
+		}
+		samples = append(samples, sample{labels: labels, value: ts.value})
+	}
+
+	expected := []sample{
+		{labels: map[string]string{"__name__": "traefik_test_duration_seconds_bucket", "code": "200", "le": "0.5"}, value: 1},
+		{labels: map[string]string{"__name__": "traefik_test_duration_seconds_bucket", "code": "200", "le": "+Inf"}, value: 2},
+		{labels: map[string]string{"__name__": "traefik_test_duration_seconds_sum", "code": "200"}, value: 1.1},
+		{labels: map[string]string{"__name__": "traefik_test_duration_seconds_count", "code": "200"}, value: 2},
+	}
+	assert.Equal(t, expected, samples)
+}
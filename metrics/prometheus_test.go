@@ -37,6 +37,14 @@ func TestPrometheus(t *testing.T) {
 		EntrypointOpenConnsGauge().
 		With("method", http.MethodGet, "protocol", "http", "entrypoint", "http").
 		Set(1)
+	prometheusRegistry.
+		EntrypointClientConnsGauge().
+		With("entrypoint", "http").
+		Set(3)
+	prometheusRegistry.
+		EntrypointRejectedReqsCounter().
+		With("entrypoint", "http").
+		Add(1)
 
 	prometheusRegistry.
 		BackendReqsCounter().
@@ -59,6 +67,35 @@ func TestPrometheus(t *testing.T) {
 		With("backend", "backend1", "url", "http://127.0.0.10:80").
 		Set(1)
 
+	prometheusRegistry.
+		BackendConcurrencyLimitGauge().
+		With("backend", "backend1").
+		Set(20)
+	prometheusRegistry.
+		TenantReqsCounter().
+		With("tenant", "team-a", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
+		Add(1)
+	prometheusRegistry.
+		TenantReqDurationHistogram().
+		With("tenant", "team-a", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
+		Observe(1)
+	prometheusRegistry.
+		TenantOpenConnsGauge().
+		With("tenant", "team-a", "method", http.MethodGet, "protocol", "http").
+		Set(1)
+	prometheusRegistry.
+		TenantQuotaRejectionsCounter().
+		With("tenant", "team-a", "quota", "rps").
+		Add(1)
+	prometheusRegistry.
+		OCSPStapleAgeGauge().
+		With("domain", "example.com").
+		Set(60)
+	prometheusRegistry.
+		TLSCertsNotAfterGauge().
+		With("cn", "example.com", "serial", "42", "sans", "example.com,www.example.com").
+		Set(1700000000)
+
 	delayForTrackingCompletion()
 
 	metricsFamilies := mustScrape()
@@ -113,6 +150,20 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, entrypointOpenConnsName, 1),
 		},
+		{
+			name: entrypointClientConnsName,
+			labels: map[string]string{
+				"entrypoint": "http",
+			},
+			assert: buildGaugeAssert(t, entrypointClientConnsName, 3),
+		},
+		{
+			name: entrypointRejectedReqsTotalName,
+			labels: map[string]string{
+				"entrypoint": "http",
+			},
+			assert: buildCounterAssert(t, entrypointRejectedReqsTotalName, 1),
+		},
 		{
 			name: backendReqsTotalName,
 			labels: map[string]string{
@@ -157,6 +208,66 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, backendServerUpName, 1),
 		},
+		{
+			name: backendConcurrencyLimitName,
+			labels: map[string]string{
+				"backend": "backend1",
+			},
+			assert: buildGaugeAssert(t, backendConcurrencyLimitName, 20),
+		},
+		{
+			name: tenantReqsTotalName,
+			labels: map[string]string{
+				"code":     "200",
+				"method":   http.MethodGet,
+				"protocol": "http",
+				"tenant":   "team-a",
+			},
+			assert: buildCounterAssert(t, tenantReqsTotalName, 1),
+		},
+		{
+			name: tenantReqDurationName,
+			labels: map[string]string{
+				"code":     "200",
+				"method":   http.MethodGet,
+				"protocol": "http",
+				"tenant":   "team-a",
+			},
+			assert: buildHistogramAssert(t, tenantReqDurationName, 1),
+		},
+		{
+			name: tenantOpenConnsName,
+			labels: map[string]string{
+				"method":   http.MethodGet,
+				"protocol": "http",
+				"tenant":   "team-a",
+			},
+			assert: buildGaugeAssert(t, tenantOpenConnsName, 1),
+		},
+		{
+			name: tenantQuotaRejectionsTotalName,
+			labels: map[string]string{
+				"quota":  "rps",
+				"tenant": "team-a",
+			},
+			assert: buildCounterAssert(t, tenantQuotaRejectionsTotalName, 1),
+		},
+		{
+			name: ocspStapleAgeName,
+			labels: map[string]string{
+				"domain": "example.com",
+			},
+			assert: buildGaugeAssert(t, ocspStapleAgeName, 60),
+		},
+		{
+			name: tlsCertsNotAfterName,
+			labels: map[string]string{
+				"cn":     "example.com",
+				"serial": "42",
+				"sans":   "example.com,www.example.com",
+			},
+			assert: buildGaugeAssert(t, tlsCertsNotAfterName, 1700000000),
+		},
 	}
 
 	for _, test := range tests {
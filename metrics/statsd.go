@@ -28,8 +28,12 @@ const (
 	statsdEntrypointReqsName          = "entrypoint.request.total"
 	statsdEntrypointReqDurationName   = "entrypoint.request.duration"
 	statsdEntrypointOpenConnsName     = "entrypoint.connections.open"
+	statsdEntrypointClientConnsName   = "entrypoint.connections.client"
+	statsdEntrypointRejectedReqsName  = "entrypoint.request.rejected.total"
 	statsdOpenConnsName               = "backend.connections.open"
 	statsdServerUpName                = "backend.server.up"
+	statsdConcurrencyLimitName        = "backend.concurrency.limit"
+	statsdTLSCertsNotAfterName        = "tls.certs.notAfterTimestamp"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -47,11 +51,15 @@ func RegisterStatsd(config *types.Statsd) Registry {
 		entrypointReqsCounter:          statsdClient.NewCounter(statsdEntrypointReqsName, 1.0),
 		entrypointReqDurationHistogram: statsdClient.NewTiming(statsdEntrypointReqDurationName, 1.0),
 		entrypointOpenConnsGauge:       statsdClient.NewGauge(statsdEntrypointOpenConnsName),
+		entrypointClientConnsGauge:     statsdClient.NewGauge(statsdEntrypointClientConnsName),
+		entrypointRejectedReqsCounter:  statsdClient.NewCounter(statsdEntrypointRejectedReqsName, 1.0),
 		backendReqsCounter:             statsdClient.NewCounter(statsdMetricsBackendReqsName, 1.0),
 		backendReqDurationHistogram:    statsdClient.NewTiming(statsdMetricsBackendLatencyName, 1.0),
 		backendRetriesCounter:          statsdClient.NewCounter(statsdRetriesTotalName, 1.0),
 		backendOpenConnsGauge:          statsdClient.NewGauge(statsdOpenConnsName),
 		backendServerUpGauge:           statsdClient.NewGauge(statsdServerUpName),
+		backendConcurrencyLimitGauge:   statsdClient.NewGauge(statsdConcurrencyLimitName),
+		tlsCertsNotAfterGauge:          statsdClient.NewGauge(statsdTLSCertsNotAfterName),
 	}
 }
 
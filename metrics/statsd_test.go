@@ -31,7 +31,11 @@ func TestStatsD(t *testing.T) {
 		"traefik.entrypoint.request.total:1.000000|c\n",
 		"traefik.entrypoint.request.duration:10000.000000|ms",
 		"traefik.entrypoint.connections.open:1.000000|g\n",
+		"traefik.entrypoint.connections.client:3.000000|g\n",
+		"traefik.entrypoint.request.rejected.total:1.000000|c\n",
 		"traefik.backend.server.up:1.000000|g\n",
+		"traefik.backend.concurrency.limit:20.000000|g\n",
+		"traefik.tls.certs.notAfterTimestamp:1700000000.000000|g\n",
 	}
 
 	udp.ShouldReceiveAll(t, expected, func() {
@@ -45,6 +49,10 @@ func TestStatsD(t *testing.T) {
 		statsdRegistry.EntrypointReqsCounter().With("entrypoint", "test").Add(1)
 		statsdRegistry.EntrypointReqDurationHistogram().With("entrypoint", "test").Observe(10000)
 		statsdRegistry.EntrypointOpenConnsGauge().With("entrypoint", "test").Set(1)
+		statsdRegistry.EntrypointClientConnsGauge().With("entrypoint", "test").Set(3)
+		statsdRegistry.EntrypointRejectedReqsCounter().With("entrypoint", "test").Add(1)
 		statsdRegistry.BackendServerUpGauge().With("backend:test", "url", "http://127.0.0.1").Set(1)
+		statsdRegistry.BackendConcurrencyLimitGauge().With("backend", "test").Set(20)
+		statsdRegistry.TLSCertsNotAfterGauge().With("cn", "example.com", "serial", "42", "sans", "example.com").Set(1700000000)
 	})
 }
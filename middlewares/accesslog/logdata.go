@@ -26,6 +26,14 @@ const (
 	ClientHost = "ClientHost"
 	// ClientPort is the map key used for the remote TCP port from which the client request was received.
 	ClientPort = "ClientPort"
+	// ClientCountry is the map key used for the ISO country code of the client IP address, if GeoIP is enabled.
+	ClientCountry = "ClientCountry"
+	// ClientCity is the map key used for the city of the client IP address, if GeoIP is enabled.
+	ClientCity = "ClientCity"
+	// ClientASN is the map key used for the autonomous system number of the client IP address, if GeoIP is enabled.
+	ClientASN = "ClientASN"
+	// ClientOrganization is the map key used for the autonomous system organization of the client IP address, if GeoIP is enabled.
+	ClientOrganization = "ClientOrganization"
 	// ClientUsername is the map key used for the username provided in the URL, if present.
 	ClientUsername = "ClientUsername"
 	// RequestAddr is the map key used for the HTTP Host header (usually IP:port). This is treated as not a header by the Go API.
@@ -113,6 +121,10 @@ func init() {
 	allCoreKeys[StartLocal] = struct{}{}
 	allCoreKeys[Overhead] = struct{}{}
 	allCoreKeys[RetryAttempts] = struct{}{}
+	allCoreKeys[ClientCountry] = struct{}{}
+	allCoreKeys[ClientCity] = struct{}{}
+	allCoreKeys[ClientASN] = struct{}{}
+	allCoreKeys[ClientOrganization] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
@@ -8,10 +8,13 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/containous/traefik/geoip"
+	"github.com/containous/traefik/log"
 	"github.com/containous/traefik/types"
 	"github.com/sirupsen/logrus"
 )
@@ -35,6 +38,7 @@ type LogHandler struct {
 	logger   *logrus.Logger
 	file     *os.File
 	filePath string
+	geoIP    *geoip.Database
 	mu       sync.Mutex
 }
 
@@ -60,13 +64,29 @@ func NewLogHandler(config *types.AccessLog) (*LogHandler, error) {
 		return nil, fmt.Errorf("unsupported access log format: %s", config.Format)
 	}
 
+	var geoIP *geoip.Database
+	if config.GeoIP != nil {
+		var paths []string
+		for _, path := range []string{config.GeoIP.CityDatabase, config.GeoIP.ASNDatabase} {
+			if len(path) > 0 {
+				paths = append(paths, path)
+			}
+		}
+
+		var err error
+		geoIP, err = geoip.NewDatabase(paths, time.Duration(config.GeoIP.ReloadInterval))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	logger := &logrus.Logger{
 		Out:       file,
 		Formatter: formatter,
 		Hooks:     make(logrus.LevelHooks),
 		Level:     logrus.InfoLevel,
 	}
-	return &LogHandler{logger: logger, file: file, filePath: config.FilePath}, nil
+	return &LogHandler{logger: logger, file: file, filePath: config.FilePath, geoIP: geoIP}, nil
 }
 
 func openAccessLogFile(filePath string) (*os.File, error) {
@@ -144,6 +164,9 @@ func (l *LogHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next h
 
 // Close closes the Logger (i.e. the file etc).
 func (l *LogHandler) Close() error {
+	if l.geoIP != nil {
+		l.geoIP.Close()
+	}
 	return l.file.Close()
 }
 
@@ -168,6 +191,34 @@ func (l *LogHandler) Rotate() error {
 	return nil
 }
 
+// addClientLocation adds the GeoIP and ASN fields of the client IP address, the first one of X-Forwarded-For if any.
+func (l *LogHandler) addClientLocation(core CoreLogData) {
+	clientHost, _ := core[ClientHost].(string)
+	ip := net.ParseIP(strings.TrimSpace(strings.Split(clientHost, ",")[0]))
+	if ip == nil {
+		return
+	}
+
+	location, err := l.geoIP.Lookup(ip)
+	if err != nil {
+		log.Debugf("Error looking up the location of %s: %v", ip, err)
+		return
+	}
+
+	if len(location.Country) > 0 {
+		core[ClientCountry] = location.Country
+	}
+	if len(location.City) > 0 {
+		core[ClientCity] = location.City
+	}
+	if location.ASN > 0 {
+		core[ClientASN] = location.ASN
+	}
+	if len(location.Organization) > 0 {
+		core[ClientOrganization] = location.Organization
+	}
+}
+
 func silentSplitHostPort(value string) (host string, port string) {
 	host, port, err := net.SplitHostPort(value)
 	if err != nil {
@@ -193,6 +244,9 @@ func (l *LogHandler) logTheRoundTrip(logDataTable *LogData, crr *captureRequestR
 	if core[RetryAttempts] == nil {
 		core[RetryAttempts] = 0
 	}
+	if l.geoIP != nil {
+		l.addClientLocation(core)
+	}
 	if crr != nil {
 		core[RequestContentSize] = crr.count
 	}
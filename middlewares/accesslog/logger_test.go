@@ -361,3 +361,16 @@ func logWriterTestHandlerFunc(rw http.ResponseWriter, r *http.Request) {
 	logDataTable.Core[OriginContentSize] = testContentSize
 	logDataTable.Core[RetryAttempts] = testRetryAttempts
 }
+
+func TestNewLogHandlerInvalidGeoIPDatabase(t *testing.T) {
+	tmpDir := createTempDir(t, "traefik_geoip")
+	defer os.RemoveAll(tmpDir)
+
+	config := &types.AccessLog{
+		FilePath: filepath.Join(tmpDir, "access.log"),
+		Format:   JSONFormat,
+		GeoIP:    &types.GeoIP{CityDatabase: filepath.Join(tmpDir, "missing.mmdb")},
+	}
+	_, err := NewLogHandler(config)
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package middlewares
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/middlewares/tracing"
+	"github.com/containous/traefik/types"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+const (
+	defaultAdaptiveInitialLimit = 20
+	defaultAdaptiveMinLimit     = 1
+	defaultAdaptiveMaxLimit     = 1000
+
+	// adaptiveBackoffRatio is applied to the limit when the backend fails under load.
+	adaptiveBackoffRatio = 0.9
+	// adaptiveSmoothing is the weight of a new limit against the current one.
+	adaptiveSmoothing = 0.2
+	// adaptiveRTTResetSamples is the number of samples after which the no-load latency is measured again,
+	// for the limit to follow a lasting change of the backend latency.
+	adaptiveRTTResetSamples = 1000
+)
+
+// AdaptiveLimiter is a middleware limiting the number of concurrent requests to a backend,
+// rejecting the other requests (503).
+// The limit follows the gradient between the latency without load and the current latency of the requests:
+// it grows while the latency stays close to the latency without load, and shrinks when the latency increases
+// or when the backend fails (502, 503, 504).
+type AdaptiveLimiter struct {
+	backendName string
+	gauge       gokitmetrics.Gauge
+	minLimit    float64
+	maxLimit    float64
+
+	lock        sync.Mutex
+	limit       float64
+	inFlight    int
+	samples     int
+	noLoadRTT   time.Duration
+	smoothedRTT time.Duration
+}
+
+// NewAdaptiveLimiter returns a new AdaptiveLimiter, to be shared by all the handlers of the backend.
+func NewAdaptiveLimiter(config *types.AdaptiveLimit, backendName string, gauge gokitmetrics.Gauge) *AdaptiveLimiter {
+	minLimit := defaultAdaptiveMinLimit
+	if config.MinLimit > 0 {
+		minLimit = config.MinLimit
+	}
+	maxLimit := defaultAdaptiveMaxLimit
+	if config.MaxLimit > 0 {
+		maxLimit = config.MaxLimit
+	}
+	if maxLimit < minLimit {
+		log.Warnf("Adaptive limit of backend %s: the maximum limit %d is lower than the minimum limit %d", backendName, maxLimit, minLimit)
+		maxLimit = minLimit
+	}
+	initialLimit := defaultAdaptiveInitialLimit
+	if config.InitialLimit > 0 {
+		initialLimit = config.InitialLimit
+	}
+
+	l := &AdaptiveLimiter{
+		backendName: backendName,
+		gauge:       gauge,
+		minLimit:    float64(minLimit),
+		maxLimit:    float64(maxLimit),
+	}
+	l.setLimit(float64(initialLimit))
+	return l
+}
+
+func (l *AdaptiveLimiter) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if !l.acquire() {
+		tracing.SetErrorAndDebugLog(r, "concurrency limit of backend %s reached - rejecting", l.backendName)
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	start := time.Now()
+	recorder := &responseRecorder{rw, http.StatusOK}
+	// the request is released even when the next handler panics, which counts as a failure
+	completed := false
+	defer func() {
+		switch {
+		case !completed:
+			l.release(0, true)
+		case recorder.statusCode == http.StatusBadGateway, recorder.statusCode == http.StatusServiceUnavailable, recorder.statusCode == http.StatusGatewayTimeout:
+			l.release(0, true)
+		default:
+			l.release(time.Since(start), false)
+		}
+	}()
+
+	next(recorder, r)
+	completed = true
+}
+
+func (l *AdaptiveLimiter) acquire() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.inFlight >= int(l.limit) {
+		return false
+	}
+	l.inFlight++
+	return true
+}
+
+// release records the latency of a finished request, or its failure, and adjusts the limit accordingly.
+func (l *AdaptiveLimiter) release(rtt time.Duration, failed bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	inFlight := l.inFlight
+	l.inFlight--
+
+	if failed {
+		l.setLimit(l.limit * adaptiveBackoffRatio)
+		return
+	}
+
+	l.samples++
+	if l.noLoadRTT == 0 || rtt < l.noLoadRTT || l.samples%adaptiveRTTResetSamples == 0 {
+		l.noLoadRTT = rtt
+	}
+	if l.smoothedRTT == 0 {
+		l.smoothedRTT = rtt
+	} else {
+		l.smoothedRTT = time.Duration((1-adaptiveSmoothing)*float64(l.smoothedRTT) + adaptiveSmoothing*float64(rtt))
+	}
+	if l.smoothedRTT <= 0 {
+		return
+	}
+
+	gradient := math.Max(0.5, math.Min(1, float64(l.noLoadRTT)/float64(l.smoothedRTT)))
+	newLimit := l.limit*gradient + math.Sqrt(l.limit)
+	if newLimit > l.limit && float64(inFlight) < l.limit/2 {
+		// the limit is not reached: its increase would not be backed by the latency
+		return
+	}
+	l.setLimit((1-adaptiveSmoothing)*l.limit + adaptiveSmoothing*newLimit)
+}
+
+func (l *AdaptiveLimiter) setLimit(limit float64) {
+	l.limit = math.Max(l.minLimit, math.Min(l.maxLimit, limit))
+	l.gauge.With("backend", l.backendName).Set(math.Floor(l.limit))
+}
@@ -0,0 +1,135 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveLimiterBounds(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        types.AdaptiveLimit
+		expectedLimit float64
+	}{
+		{
+			desc:          "defaults",
+			expectedLimit: defaultAdaptiveInitialLimit,
+		},
+		{
+			desc:          "initial limit",
+			config:        types.AdaptiveLimit{InitialLimit: 50},
+			expectedLimit: 50,
+		},
+		{
+			desc:          "initial limit above the maximum",
+			config:        types.AdaptiveLimit{InitialLimit: 50, MaxLimit: 10},
+			expectedLimit: 10,
+		},
+		{
+			desc:          "maximum below the minimum",
+			config:        types.AdaptiveLimit{MinLimit: 30, MaxLimit: 10},
+			expectedLimit: 30,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			gauge := &testhelpers.CollectingGauge{}
+			limiter := NewAdaptiveLimiter(&test.config, "backend1", gauge)
+
+			assert.Equal(t, test.expectedLimit, limiter.limit)
+			assert.Equal(t, test.expectedLimit, gauge.GaugeValue)
+			assert.Equal(t, []string{"backend", "backend1"}, gauge.LastLabelValues)
+		})
+	}
+}
+
+func TestAdaptiveLimiterRejection(t *testing.T) {
+	limiter := NewAdaptiveLimiter(&types.AdaptiveLimit{InitialLimit: 1, MaxLimit: 1}, "backend1", &testhelpers.CollectingGauge{})
+	assert.True(t, limiter.acquire())
+
+	recorder := httptest.NewRecorder()
+	limiter.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil), func(rw http.ResponseWriter, r *http.Request) {})
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	limiter.release(time.Millisecond, false)
+	recorder = httptest.NewRecorder()
+	limiter.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil), func(rw http.ResponseWriter, r *http.Request) {})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestAdaptiveLimiterReleaseOnPanic(t *testing.T) {
+	limiter := NewAdaptiveLimiter(&types.AdaptiveLimit{InitialLimit: 1, MaxLimit: 1}, "backend1", &testhelpers.CollectingGauge{})
+
+	assert.Panics(t, func() {
+		limiter.ServeHTTP(httptest.NewRecorder(), testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil), func(rw http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})
+	})
+	assert.Zero(t, limiter.inFlight)
+}
+
+func TestAdaptiveLimiterAdjustment(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		inFlight int
+		rtt      time.Duration
+		failed   bool
+		expected func(t *testing.T, initial, limit float64)
+	}{
+		{
+			desc:     "grows at a steady latency under load",
+			inFlight: 20,
+			rtt:      10 * time.Millisecond,
+			expected: func(t *testing.T, initial, limit float64) {
+				assert.True(t, limit > initial, "%f <= %f", limit, initial)
+			},
+		},
+		{
+			desc:     "doesn't grow without load",
+			inFlight: 1,
+			rtt:      10 * time.Millisecond,
+			expected: func(t *testing.T, initial, limit float64) { assert.Equal(t, initial, limit) },
+		},
+		{
+			desc:     "shrinks when the latency increases",
+			inFlight: 20,
+			rtt:      100 * time.Millisecond,
+			expected: func(t *testing.T, initial, limit float64) {
+				assert.True(t, limit < initial, "%f >= %f", limit, initial)
+			},
+		},
+		{
+			desc:     "backs off on failures",
+			inFlight: 20,
+			failed:   true,
+			expected: func(t *testing.T, initial, limit float64) { assert.Equal(t, initial*adaptiveBackoffRatio, limit) },
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			limiter := NewAdaptiveLimiter(&types.AdaptiveLimit{}, "backend1", &testhelpers.CollectingGauge{})
+			limiter.noLoadRTT = 10 * time.Millisecond
+			limiter.smoothedRTT = 10 * time.Millisecond
+			initial := limiter.limit
+
+			limiter.inFlight = test.inFlight
+			limiter.release(test.rtt, test.failed)
+
+			test.expected(t, initial, limiter.limit)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/containous/traefik/middlewares/tracing"
+)
+
+// errBodyTooLargeMessage is the message of the error returned by http.MaxBytesReader when reading past the limit.
+const errBodyTooLargeMessage = "http: request body too large"
+
+// BodyLimiter is a middleware rejecting the requests with a body larger than the limit with a 413 status code.
+// The requests announcing a larger Content-Length are rejected right away,
+// the other ones failing when reading past the limit, for instance while being forwarded to the backend.
+type BodyLimiter struct {
+	maxBodyBytes int64
+}
+
+// NewBodyLimiter returns a new BodyLimiter instance.
+func NewBodyLimiter(maxBodyBytes int64) *BodyLimiter {
+	return &BodyLimiter{maxBodyBytes: maxBodyBytes}
+}
+
+func (l *BodyLimiter) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if r.ContentLength > l.maxBodyBytes {
+		tracing.SetErrorAndDebugLog(r, "request body of %d bytes exceeding the limit of %d - rejecting", r.ContentLength, l.maxBodyBytes)
+		http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = http.MaxBytesReader(rw, r.Body, l.maxBodyBytes)
+	}
+
+	next(rw, r)
+}
+
+// IsBodyTooLarge reports whether the error comes from reading a request body past the limit of a BodyLimiter.
+func IsBodyTooLarge(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errBodyTooLargeMessage)
+}
@@ -0,0 +1,72 @@
+package middlewares
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimiter(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		body             string
+		contentLength    int64
+		expectedStatus   int
+		expectedReadErr  bool
+		expectedNextCall bool
+	}{
+		{
+			desc:             "no body",
+			expectedStatus:   http.StatusOK,
+			expectedNextCall: true,
+		},
+		{
+			desc:             "body within the limit",
+			body:             "0123456789",
+			contentLength:    10,
+			expectedStatus:   http.StatusOK,
+			expectedNextCall: true,
+		},
+		{
+			desc:           "announced body exceeding the limit",
+			body:           "0123456789a",
+			contentLength:  11,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:             "chunked body exceeding the limit",
+			body:             "0123456789a",
+			contentLength:    -1,
+			expectedStatus:   http.StatusOK,
+			expectedReadErr:  true,
+			expectedNextCall: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := testhelpers.MustNewRequest(http.MethodPost, "http://localhost", strings.NewReader(test.body))
+			req.ContentLength = test.contentLength
+
+			var nextCalled bool
+			var readErr error
+			recorder := httptest.NewRecorder()
+			NewBodyLimiter(10).ServeHTTP(recorder, req, func(rw http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				_, readErr = ioutil.ReadAll(r.Body)
+			})
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedNextCall, nextCalled)
+			assert.Equal(t, test.expectedReadErr, IsBodyTooLarge(readErr))
+		})
+	}
+}
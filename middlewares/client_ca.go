@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"crypto/x509"
+	"net/http"
+
+	"github.com/containous/traefik/middlewares/tracing"
+)
+
+// ClientCAVerifier is a middleware rejecting the requests whose client certificate isn't issued by the CAs of a frontend.
+// The handshake already requests the certificate of the client for the server name of the frontend,
+// the certificate is verified again on each request since the Host of a request may not match the server name of its connection.
+type ClientCAVerifier struct {
+	pool     *x509.CertPool
+	optional bool
+}
+
+// NewClientCAVerifier returns a new ClientCAVerifier, accepting the requests without client certificate when optional.
+func NewClientCAVerifier(pool *x509.CertPool, optional bool) *ClientCAVerifier {
+	return &ClientCAVerifier{
+		pool:     pool,
+		optional: optional,
+	}
+}
+
+func (v *ClientCAVerifier) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		if v.optional {
+			next(rw, r)
+			return
+		}
+		tracing.SetErrorAndDebugLog(r, "no client certificate - rejecting")
+		reject(rw)
+		return
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range r.TLS.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := r.TLS.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         v.pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		tracing.SetErrorAndDebugLog(r, "client certificate not issued by the frontend CAs: %v - rejecting", err)
+		reject(rw)
+		return
+	}
+
+	next(rw, r)
+}
@@ -0,0 +1,121 @@
+package middlewares
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateClientCertificate generates a CA, and a client certificate it issued.
+func generateClientCertificate(t *testing.T) (*x509.Certificate, *x509.Certificate) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Tenant CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return ca, cert
+}
+
+func TestClientCAVerifier(t *testing.T) {
+	ca, cert := generateClientCertificate(t)
+	otherCA, otherCert := generateClientCertificate(t)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	testCases := []struct {
+		desc           string
+		optional       bool
+		connState      *tls.ConnectionState
+		expectedStatus int
+	}{
+		{
+			desc:           "certificate issued by the CA",
+			connState:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "certificate issued by another CA",
+			connState:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{otherCert, otherCA}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "certificate issued by another CA, optional",
+			optional:       true,
+			connState:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{otherCert}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "no certificate",
+			connState:      &tls.ConnectionState{},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "no certificate, optional",
+			optional:       true,
+			connState:      &tls.ConnectionState{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "no TLS",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			verifier := NewClientCAVerifier(pool, test.optional)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "https://tenant.example.com", nil)
+			req.TLS = test.connState
+			recorder := httptest.NewRecorder()
+			verifier.ServeHTTP(recorder, req, func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
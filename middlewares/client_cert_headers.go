@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	traefikTls "github.com/containous/traefik/tls"
+)
+
+// ClientCertHeaders is a middleware forwarding the details of the verified client certificate to the backends in request headers.
+// The headers sent by the clients themselves are always removed, so that they can't be spoofed.
+type ClientCertHeaders struct {
+	details []clientCertDetail
+}
+
+type clientCertDetail struct {
+	header string
+	value  func(*x509.Certificate) string
+}
+
+// NewClientCertHeaders returns a new ClientCertHeaders instance.
+func NewClientCertHeaders(headers *traefikTls.ClientCertHeaders) *ClientCertHeaders {
+	c := &ClientCertHeaders{}
+	for _, detail := range []clientCertDetail{
+		{header: headers.PEM, value: certificatePEM},
+		{header: headers.Subject, value: func(cert *x509.Certificate) string { return cert.Subject.String() }},
+		{header: headers.SANs, value: certificateSANs},
+		{header: headers.Serial, value: func(cert *x509.Certificate) string { return fmt.Sprintf("%X", cert.SerialNumber) }},
+	} {
+		if len(detail.header) > 0 {
+			c.details = append(c.details, detail)
+		}
+	}
+	return c
+}
+
+func (c *ClientCertHeaders) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	for _, detail := range c.details {
+		r.Header.Del(detail.header)
+	}
+
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		cert := r.TLS.PeerCertificates[0]
+		for _, detail := range c.details {
+			r.Header.Set(detail.header, detail.value(cert))
+		}
+	}
+
+	next(rw, r)
+}
+
+// certificatePEM returns the URL-escaped PEM encoding of the certificate, the line breaks being forbidden in a header value.
+func certificatePEM(cert *x509.Certificate) string {
+	return url.QueryEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})))
+}
+
+// certificateSANs returns the comma-separated subject alternative names of the certificate.
+func certificateSANs(cert *x509.Certificate) string {
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return strings.Join(sans, ",")
+}
@@ -0,0 +1,101 @@
+package middlewares
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCertHeaders(t *testing.T) {
+	cert := &x509.Certificate{
+		Raw:            []byte("certificate"),
+		Subject:        pkix.Name{CommonName: "client.example.com", Organization: []string{"Example"}},
+		SerialNumber:   big.NewInt(0x83E81F36),
+		DNSNames:       []string{"client.example.com", "client.example.org"},
+		EmailAddresses: []string{"client@example.com"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+	}
+
+	testCases := []struct {
+		desc            string
+		headers         *traefikTls.ClientCertHeaders
+		connState       *tls.ConnectionState
+		requestHeaders  map[string]string
+		expectedHeaders map[string]string
+	}{
+		{
+			desc: "all the details",
+			headers: &traefikTls.ClientCertHeaders{
+				PEM:     "X-Forwarded-Tls-Client-Cert",
+				Subject: "X-Forwarded-Tls-Client-Cert-Subject",
+				SANs:    "X-Forwarded-Tls-Client-Cert-Sans",
+				Serial:  "X-Forwarded-Tls-Client-Cert-Serial",
+			},
+			connState: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+			expectedHeaders: map[string]string{
+				"X-Forwarded-Tls-Client-Cert":         url.QueryEscape("-----BEGIN CERTIFICATE-----\nY2VydGlmaWNhdGU=\n-----END CERTIFICATE-----\n"),
+				"X-Forwarded-Tls-Client-Cert-Subject": "CN=client.example.com,O=Example",
+				"X-Forwarded-Tls-Client-Cert-Sans":    "client.example.com,client.example.org,client@example.com,10.0.0.1",
+				"X-Forwarded-Tls-Client-Cert-Serial":  "83E81F36",
+			},
+		},
+		{
+			desc:            "some details",
+			headers:         &traefikTls.ClientCertHeaders{Subject: "X-Client-Subject"},
+			connState:       &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+			expectedHeaders: map[string]string{"X-Client-Subject": "CN=client.example.com,O=Example"},
+		},
+		{
+			desc:            "spoofed headers without client certificate",
+			headers:         &traefikTls.ClientCertHeaders{Subject: "X-Client-Subject"},
+			connState:       &tls.ConnectionState{},
+			requestHeaders:  map[string]string{"X-Client-Subject": "CN=admin"},
+			expectedHeaders: map[string]string{"X-Client-Subject": ""},
+		},
+		{
+			desc:            "spoofed headers without TLS",
+			headers:         &traefikTls.ClientCertHeaders{Subject: "X-Client-Subject"},
+			requestHeaders:  map[string]string{"X-Client-Subject": "CN=admin"},
+			expectedHeaders: map[string]string{"X-Client-Subject": ""},
+		},
+		{
+			desc:            "spoofed headers with a client certificate",
+			headers:         &traefikTls.ClientCertHeaders{Subject: "X-Client-Subject"},
+			connState:       &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+			requestHeaders:  map[string]string{"X-Client-Subject": "CN=admin"},
+			expectedHeaders: map[string]string{"X-Client-Subject": "CN=client.example.com,O=Example"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "https://localhost", nil)
+			req.TLS = test.connState
+			for name, value := range test.requestHeaders {
+				req.Header.Set(name, value)
+			}
+
+			var forwarded *http.Request
+			NewClientCertHeaders(test.headers).ServeHTTP(httptest.NewRecorder(), req, func(rw http.ResponseWriter, r *http.Request) {
+				forwarded = r
+			})
+
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, forwarded.Header.Get(name), name)
+			}
+		})
+	}
+}
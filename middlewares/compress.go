@@ -2,30 +2,157 @@ package middlewares
 
 import (
 	"compress/gzip"
+	"fmt"
+	"mime"
 	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/NYTimes/gziphandler"
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
 )
 
 // Compress is a middleware that allows redirection
-type Compress struct{}
+type Compress struct {
+	includedContentTypes []string
+	excludedContentTypes []string
+	excludedPaths        []*regexp.Regexp
+	minSize              int
+}
+
+// NewCompress returns a new Compress instance filtering the compressed responses with the given configuration.
+func NewCompress(config *types.Compression) (*Compress, error) {
+	c := &Compress{}
+	if config == nil {
+		return c, nil
+	}
+
+	if config.MinResponseBodyBytes < 0 {
+		return nil, fmt.Errorf("negative minimum response body size %d", config.MinResponseBodyBytes)
+	}
+	c.minSize = config.MinResponseBodyBytes
+
+	c.includedContentTypes = mediaTypes(config.IncludedContentTypes)
+	c.excludedContentTypes = mediaTypes(config.ExcludedContentTypes)
+	for _, path := range config.ExcludedPaths {
+		exp, err := regexp.Compile(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid excluded path %q: %v", path, err)
+		}
+		c.excludedPaths = append(c.excludedPaths, exp)
+	}
+	return c, nil
+}
 
 // ServerHTTP is a function used by Negroni
 func (c *Compress) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	contentType := r.Header.Get("Content-Type")
-	if strings.HasPrefix(contentType, "application/grpc") {
+	if strings.HasPrefix(contentType, "application/grpc") || c.isExcludedPath(r.URL.Path) {
 		next.ServeHTTP(rw, r)
+	} else if len(c.includedContentTypes) > 0 || len(c.excludedContentTypes) > 0 {
+		gzipHandler(http.HandlerFunc(func(gzipRW http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(&compressSelector{ResponseWriter: rw, gzipRW: gzipRW, compress: c.compressible}, r)
+		}), c.minSize).ServeHTTP(rw, r)
 	} else {
-		gzipHandler(next).ServeHTTP(rw, r)
+		gzipHandler(next, c.minSize).ServeHTTP(rw, r)
+	}
+}
+
+func (c *Compress) isExcludedPath(path string) bool {
+	for _, exp := range c.excludedPaths {
+		if exp.MatchString(path) {
+			return true
+		}
 	}
+	return false
 }
 
-func gzipHandler(h http.Handler) http.Handler {
+// compressible checks the content type of a response against the included and excluded content types.
+func (c *Compress) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	if matchMediaType(c.excludedContentTypes, mediaType) {
+		return false
+	}
+	return len(c.includedContentTypes) == 0 || matchMediaType(c.includedContentTypes, mediaType)
+}
+
+// matchMediaType matches a media type against a list of media types, which can be wildcards such as image/*.
+func matchMediaType(mediaTypes []string, mediaType string) bool {
+	for _, t := range mediaTypes {
+		if t == mediaType || strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(t, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+func mediaTypes(contentTypes []string) []string {
+	var result []string
+	for _, contentType := range contentTypes {
+		result = append(result, strings.ToLower(strings.TrimSpace(contentType)))
+	}
+	return result
+}
+
+// compressSelector writes the response either to the gzip response writer or to the original one,
+// depending on the response content type known when the response starts being written.
+type compressSelector struct {
+	http.ResponseWriter
+	gzipRW   http.ResponseWriter
+	compress func(contentType string) bool
+	target   http.ResponseWriter
+}
+
+func (s *compressSelector) selectTarget() {
+	if s.target != nil {
+		return
+	}
+	s.target = s.ResponseWriter
+	if s.compress(s.Header().Get("Content-Type")) {
+		s.target = s.gzipRW
+	}
+}
+
+func (s *compressSelector) WriteHeader(code int) {
+	s.selectTarget()
+	s.target.WriteHeader(code)
+}
+
+func (s *compressSelector) Write(b []byte) (int, error) {
+	if s.target == nil && len(s.Header().Get("Content-Type")) == 0 {
+		s.Header().Set("Content-Type", http.DetectContentType(b))
+	}
+	s.selectTarget()
+	return s.target.Write(b)
+}
+
+func (s *compressSelector) Flush() {
+	s.selectTarget()
+	if flusher, ok := s.target.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (s *compressSelector) CloseNotify() <-chan bool {
+	if notifier, ok := s.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+	return nil
+}
+
+// gzipHandler compresses the responses larger than minSize, or than the default minimum size if zero.
+func gzipHandler(h http.Handler, minSize int) http.Handler {
+	if minSize == 0 {
+		minSize = gziphandler.DefaultMinSize
+	}
 	wrapper, err := gziphandler.GzipHandlerWithOpts(
 		gziphandler.CompressionLevel(gzip.DefaultCompression),
-		gziphandler.MinSize(gziphandler.DefaultMinSize))
+		gziphandler.MinSize(minSize))
 	if err != nil {
 		log.Error(err)
 	}
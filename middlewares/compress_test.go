@@ -4,10 +4,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/NYTimes/gziphandler"
 	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/negroni"
@@ -238,6 +240,203 @@ func TestIntegrationShouldCompress(t *testing.T) {
 	}
 }
 
+func TestCompressFilters(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           *types.Compression
+		path             string
+		contentType      string
+		expectCompressed bool
+	}{
+		{
+			desc:             "no filters",
+			path:             "/",
+			contentType:      "image/png",
+			expectCompressed: true,
+		},
+		{
+			desc:             "excluded content type",
+			config:           &types.Compression{ExcludedContentTypes: []string{"image/png"}},
+			path:             "/",
+			contentType:      "image/png",
+			expectCompressed: false,
+		},
+		{
+			desc:             "excluded content type wildcard",
+			config:           &types.Compression{ExcludedContentTypes: []string{"image/*", "video/*"}},
+			path:             "/",
+			contentType:      "Image/JPEG",
+			expectCompressed: false,
+		},
+		{
+			desc:             "not excluded content type",
+			config:           &types.Compression{ExcludedContentTypes: []string{"image/*"}},
+			path:             "/",
+			contentType:      "text/html; charset=utf-8",
+			expectCompressed: true,
+		},
+		{
+			desc:             "included content type with parameters",
+			config:           &types.Compression{IncludedContentTypes: []string{"text/html", "application/json"}},
+			path:             "/",
+			contentType:      "text/html; charset=utf-8",
+			expectCompressed: true,
+		},
+		{
+			desc:             "not included content type",
+			config:           &types.Compression{IncludedContentTypes: []string{"text/html", "application/json"}},
+			path:             "/",
+			contentType:      "application/zip",
+			expectCompressed: false,
+		},
+		{
+			desc:             "included and excluded content type",
+			config:           &types.Compression{IncludedContentTypes: []string{"text/*"}, ExcludedContentTypes: []string{"text/event-stream"}},
+			path:             "/",
+			contentType:      "text/event-stream",
+			expectCompressed: false,
+		},
+		{
+			desc:             "detected content type",
+			config:           &types.Compression{IncludedContentTypes: []string{"text/plain"}},
+			path:             "/",
+			expectCompressed: true,
+		},
+		{
+			desc:             "excluded path",
+			config:           &types.Compression{ExcludedPaths: []string{"^/downloads/", `\.zip$`}},
+			path:             "/archive.zip",
+			contentType:      "application/octet-stream",
+			expectCompressed: false,
+		},
+		{
+			desc:             "not excluded path",
+			config:           &types.Compression{ExcludedPaths: []string{"^/downloads/"}},
+			path:             "/api/downloads/",
+			contentType:      "application/json",
+			expectCompressed: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := NewCompress(test.config)
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost"+test.path, nil)
+			req.Header.Add(acceptEncodingHeader, gzipValue)
+
+			baseBody := []byte(strings.Repeat("a", gziphandler.DefaultMinSize))
+			next := func(rw http.ResponseWriter, r *http.Request) {
+				if len(test.contentType) > 0 {
+					rw.Header().Set(contentTypeHeader, test.contentType)
+				}
+				rw.Write(baseBody)
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req, next)
+
+			if test.expectCompressed {
+				assert.Equal(t, gzipValue, rw.Header().Get(contentEncodingHeader))
+				assert.NotEqual(t, baseBody, rw.Body.Bytes())
+			} else {
+				assert.Empty(t, rw.Header().Get(contentEncodingHeader))
+				assert.Equal(t, baseBody, rw.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestCompressMinResponseBodyBytes(t *testing.T) {
+	testCases := []struct {
+		desc                 string
+		minResponseBodyBytes int
+		bodySize             int
+		expectCompressed     bool
+	}{
+		{
+			desc:             "default threshold reached",
+			bodySize:         gziphandler.DefaultMinSize,
+			expectCompressed: true,
+		},
+		{
+			desc:             "default threshold not reached",
+			bodySize:         gziphandler.DefaultMinSize - 1,
+			expectCompressed: false,
+		},
+		{
+			desc:                 "custom threshold reached",
+			minResponseBodyBytes: 1024,
+			bodySize:             1024,
+			expectCompressed:     true,
+		},
+		{
+			desc:                 "custom threshold not reached",
+			minResponseBodyBytes: 1024,
+			bodySize:             1023,
+			expectCompressed:     false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := NewCompress(&types.Compression{MinResponseBodyBytes: test.minResponseBodyBytes})
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Add(acceptEncodingHeader, gzipValue)
+
+			baseBody := []byte(strings.Repeat("a", test.bodySize))
+			next := func(rw http.ResponseWriter, r *http.Request) {
+				rw.Write(baseBody)
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req, next)
+
+			if test.expectCompressed {
+				assert.Equal(t, gzipValue, rw.Header().Get(contentEncodingHeader))
+			} else {
+				assert.Empty(t, rw.Header().Get(contentEncodingHeader))
+				assert.Equal(t, baseBody, rw.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestNewCompressInvalidConfiguration(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config *types.Compression
+	}{
+		{
+			desc:   "invalid excluded path",
+			config: &types.Compression{ExcludedPaths: []string{"("}},
+		},
+		{
+			desc:   "negative minimum response body size",
+			config: &types.Compression{MinResponseBodyBytes: -1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewCompress(test.config)
+			assert.Error(t, err)
+		})
+	}
+}
+
 func generateBytes(len int) []byte {
 	var value []byte
 	for i := 0; i < len; i++ {
@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/containous/traefik/middlewares/tracing"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+// ConcurrencyLimiter is a middleware rejecting the requests of an entry point with a 503 status code
+// while the maximum number of requests are already being handled.
+type ConcurrencyLimiter struct {
+	entryPointName string
+	slots          chan struct{}
+	rejected       gokitmetrics.Counter
+}
+
+// NewConcurrencyLimiter returns a new ConcurrencyLimiter, counting the rejected requests with the given counter.
+func NewConcurrencyLimiter(entryPointName string, maxConcurrentRequests int, rejected gokitmetrics.Counter) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		entryPointName: entryPointName,
+		slots:          make(chan struct{}, maxConcurrentRequests),
+		rejected:       rejected,
+	}
+}
+
+func (l *ConcurrencyLimiter) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	select {
+	case l.slots <- struct{}{}:
+	default:
+		l.rejected.With("entrypoint", l.entryPointName).Add(1)
+		tracing.SetErrorAndDebugLog(r, "concurrent requests limit of entrypoint %s reached - rejecting", l.entryPointName)
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	defer func() { <-l.slots }()
+
+	next(rw, r)
+}
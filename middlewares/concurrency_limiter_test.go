@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	counter := &testhelpers.CollectingCounter{}
+	limiter := NewConcurrencyLimiter("http", 1, counter)
+
+	var nestedStatus int
+	recorder := httptest.NewRecorder()
+	limiter.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil), func(rw http.ResponseWriter, r *http.Request) {
+		// the slot of the request being handled is still taken
+		nestedRecorder := httptest.NewRecorder()
+		limiter.ServeHTTP(nestedRecorder, r, func(rw http.ResponseWriter, r *http.Request) {})
+		nestedStatus = nestedRecorder.Code
+	})
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, http.StatusServiceUnavailable, nestedStatus)
+	assert.Equal(t, float64(1), counter.CounterValue)
+	assert.Equal(t, []string{"entrypoint", "http"}, counter.LastLabelValues)
+
+	recorder = httptest.NewRecorder()
+	limiter.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil), func(rw http.ResponseWriter, r *http.Request) {})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, float64(1), counter.CounterValue)
+}
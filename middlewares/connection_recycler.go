@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// connectionState tracks a client connection across its requests.
+type connectionState struct {
+	created  time.Time
+	requests int64
+}
+
+// ConnectionRecycler is a middleware closing the client connections once they have served
+// a maximum number of requests, or once they have reached a maximum age,
+// so that long-lived connections get rebalanced by the load balancers in front of Traefik.
+type ConnectionRecycler struct {
+	maxRequests int64
+	maxAge      time.Duration
+
+	lock  sync.RWMutex
+	conns map[string]*connectionState
+}
+
+// NewConnectionRecycler returns a new ConnectionRecycler instance, a zero limit being ignored.
+func NewConnectionRecycler(maxRequests int, maxAge time.Duration) *ConnectionRecycler {
+	return &ConnectionRecycler{
+		maxRequests: int64(maxRequests),
+		maxAge:      maxAge,
+		conns:       make(map[string]*connectionState),
+	}
+}
+
+// ConnState tracks the connections by remote address, the one of their requests, it must be set as the http.Server ConnState.
+func (c *ConnectionRecycler) ConnState(conn net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		c.lock.Lock()
+		c.conns[conn.RemoteAddr().String()] = &connectionState{created: time.Now()}
+		c.lock.Unlock()
+	case http.StateHijacked, http.StateClosed:
+		c.lock.Lock()
+		delete(c.conns, conn.RemoteAddr().String())
+		c.lock.Unlock()
+	}
+}
+
+func (c *ConnectionRecycler) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	c.lock.RLock()
+	state, ok := c.conns[r.RemoteAddr]
+	c.lock.RUnlock()
+
+	if ok {
+		requests := atomic.AddInt64(&state.requests, 1)
+		if c.maxRequests > 0 && requests >= c.maxRequests || c.maxAge > 0 && time.Since(state.created) >= c.maxAge {
+			// the HTTP/1 connections are closed after the response,
+			// and the HTTP/2 connections are gracefully shut down.
+			rw.Header().Set("Connection", "close")
+		}
+	}
+
+	next(rw, r)
+}
@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/negroni"
+)
+
+func TestConnectionRecycler(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		maxRequests   int
+		maxAge        time.Duration
+		requests      int
+		expectedConns int
+	}{
+		{
+			desc:          "no limit",
+			requests:      4,
+			expectedConns: 1,
+		},
+		{
+			desc:          "max requests",
+			maxRequests:   2,
+			requests:      5,
+			expectedConns: 3,
+		},
+		{
+			desc:          "max age",
+			maxAge:        time.Nanosecond,
+			requests:      3,
+			expectedConns: 3,
+		},
+		{
+			desc:          "max age not reached",
+			maxAge:        time.Hour,
+			requests:      3,
+			expectedConns: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recycler := NewConnectionRecycler(test.maxRequests, test.maxAge)
+			n := negroni.New(recycler)
+			n.UseHandler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Write([]byte(r.RemoteAddr))
+			}))
+
+			server := httptest.NewUnstartedServer(n)
+			server.Config.ConnState = recycler.ConnState
+			server.Start()
+			defer server.Close()
+
+			client := &http.Client{Transport: &http.Transport{}}
+			conns := map[string]struct{}{}
+			for i := 0; i < test.requests; i++ {
+				resp, err := client.Get(server.URL)
+				require.NoError(t, err)
+
+				remoteAddr, err := ioutil.ReadAll(resp.Body)
+				resp.Body.Close()
+				require.NoError(t, err)
+
+				conns[string(remoteAddr)] = struct{}{}
+			}
+
+			assert.Len(t, conns, test.expectedConns)
+
+			server.Close()
+			recycler.lock.RLock()
+			defer recycler.lock.RUnlock()
+			assert.Empty(t, recycler.conns, "the closed connections are still tracked")
+		})
+	}
+}
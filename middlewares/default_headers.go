@@ -0,0 +1,130 @@
+package middlewares
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"github.com/containous/traefik/types"
+	"github.com/unrolled/secure"
+)
+
+// DefaultHeaders is a middleware applying custom and security headers to the requests of several frontends.
+// Unlike the headers of a frontend, its response headers override the ones set by the backend servers.
+type DefaultHeaders struct {
+	headers *HeaderStruct
+	secure  *secure.Secure
+	// removedResponseHeaders are the custom response headers with an empty value.
+	removedResponseHeaders []string
+}
+
+// NewDefaultHeaders returns a new DefaultHeaders instance, or nil when no header is defined.
+func NewDefaultHeaders(headers *types.Headers) *DefaultHeaders {
+	h := &DefaultHeaders{
+		headers: NewHeaderFromStruct(headers),
+		secure:  NewSecure(headers),
+	}
+	if h.headers == nil && h.secure == nil {
+		return nil
+	}
+	if h.headers != nil {
+		for header, value := range h.headers.opt.CustomResponseHeaders {
+			if value == "" {
+				h.removedResponseHeaders = append(h.removedResponseHeaders, header)
+			}
+		}
+	}
+	return h
+}
+
+func (h *DefaultHeaders) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if h.headers != nil {
+		h.headers.ModifyRequestHeaders(r)
+	}
+
+	// the security headers are written before calling the next handler, as the response may be a redirect
+	forced := http.Header{}
+	if h.secure != nil {
+		if err := h.secure.Process(&headerCollector{ResponseWriter: rw, header: forced}, r); err != nil {
+			return
+		}
+	}
+	if h.headers != nil {
+		for header, value := range h.headers.opt.CustomResponseHeaders {
+			if value != "" {
+				forced.Set(header, value)
+			}
+		}
+	}
+
+	next(&forcedHeadersWriter{ResponseWriter: rw, forced: forced, removed: h.removedResponseHeaders}, r)
+}
+
+// headerCollector collects the headers set by the secure middleware,
+// which are written to the response writer when the secure middleware responds by itself.
+type headerCollector struct {
+	http.ResponseWriter
+	header http.Header
+}
+
+func (c *headerCollector) Header() http.Header {
+	return c.header
+}
+
+func (c *headerCollector) WriteHeader(code int) {
+	for header, values := range c.header {
+		c.ResponseWriter.Header()[header] = values
+	}
+	c.ResponseWriter.WriteHeader(code)
+}
+
+func (c *headerCollector) Write(b []byte) (int, error) {
+	return c.ResponseWriter.Write(b)
+}
+
+// forcedHeadersWriter sets and removes response headers just before they are written.
+type forcedHeadersWriter struct {
+	http.ResponseWriter
+	forced      http.Header
+	removed     []string
+	wroteHeader bool
+}
+
+func (w *forcedHeadersWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		for header, values := range w.forced {
+			w.ResponseWriter.Header()[header] = values
+		}
+		for _, header := range w.removed {
+			w.ResponseWriter.Header().Del(header)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *forcedHeadersWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Hijack hijacks the connection
+func (w *forcedHeadersWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// CloseNotify returns a channel that receives at most a
+// single value (true) when the client connection has gone
+// away.
+func (w *forcedHeadersWriter) CloseNotify() <-chan bool {
+	return w.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+// Flush sends any buffered data to the client.
+func (w *forcedHeadersWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
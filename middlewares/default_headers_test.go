@@ -0,0 +1,124 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultHeaders(t *testing.T) {
+	testCases := []struct {
+		desc                    string
+		headers                 *types.Headers
+		backendHeaders          http.Header
+		expectedRequestHeaders  http.Header
+		expectedResponseHeaders http.Header
+		expectedStatus          int
+	}{
+		{
+			desc:           "custom headers overriding the backend ones",
+			headers:        &types.Headers{CustomRequestHeaders: map[string]string{"X-Platform": "traefik"}, CustomResponseHeaders: map[string]string{"X-Frame-Options": "DENY", "Server": ""}},
+			backendHeaders: http.Header{"X-Frame-Options": {"SAMEORIGIN"}, "Server": {"nginx"}},
+			expectedRequestHeaders: http.Header{
+				"X-Platform": {"traefik"},
+			},
+			expectedResponseHeaders: http.Header{
+				"X-Frame-Options": {"DENY"},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "security headers",
+			headers:        &types.Headers{ContentTypeNosniff: true},
+			backendHeaders: http.Header{"X-Content-Type-Options": {"foo"}},
+			expectedResponseHeaders: http.Header{
+				"X-Content-Type-Options": {"nosniff"},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "disallowed host",
+			headers:        &types.Headers{AllowedHosts: []string{"example.com"}},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			headers := NewDefaultHeaders(test.headers)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			recorder := httptest.NewRecorder()
+			headers.ServeHTTP(recorder, req, func(rw http.ResponseWriter, r *http.Request) {
+				for name, values := range test.expectedRequestHeaders {
+					assert.Equal(t, values, r.Header[name])
+				}
+				for name, values := range test.backendHeaders {
+					rw.Header()[name] = values
+				}
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			for name, values := range test.expectedResponseHeaders {
+				assert.Equal(t, values, recorder.Header()[name])
+			}
+			if test.headers.CustomResponseHeaders != nil {
+				assert.Empty(t, recorder.Header().Get("Server"))
+			}
+		})
+	}
+}
+
+func TestNewDefaultHeadersWithoutHeaders(t *testing.T) {
+	assert.Nil(t, NewDefaultHeaders(nil))
+	assert.Nil(t, NewDefaultHeaders(&types.Headers{}))
+}
+
+func TestRequestID(t *testing.T) {
+	requestID := NewRequestID("X-Request-Id")
+
+	testCases := []struct {
+		desc       string
+		requestID  string
+		expectedID func(t *testing.T, id string)
+	}{
+		{
+			desc:       "generated ID",
+			expectedID: func(t *testing.T, id string) { assert.Len(t, id, 36) },
+		},
+		{
+			desc:       "existing ID",
+			requestID:  "foo",
+			expectedID: func(t *testing.T, id string) { assert.Equal(t, "foo", id) },
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			if len(test.requestID) > 0 {
+				req.Header.Set("X-Request-Id", test.requestID)
+			}
+
+			var forwardedID string
+			recorder := httptest.NewRecorder()
+			requestID.ServeHTTP(recorder, req, func(rw http.ResponseWriter, r *http.Request) {
+				forwardedID = r.Header.Get("X-Request-Id")
+			})
+
+			test.expectedID(t, forwardedID)
+			assert.Equal(t, forwardedID, recorder.Header().Get("X-Request-Id"))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+)
+
+// H2CUpgradeIgnorer is a middleware ignoring the requests to upgrade an HTTP/1.1 connection to h2c (RFC 7540 section 3.2).
+// The entry points only serve h2c to the clients starting with the HTTP/2 connection preface,
+// the upgrade requests being answered over HTTP/1.1 as allowed by the RFC:
+// their upgrade headers are removed, for the backends not to upgrade the connection themselves.
+type H2CUpgradeIgnorer struct{}
+
+// NewH2CUpgradeIgnorer returns a new H2CUpgradeIgnorer instance.
+func NewH2CUpgradeIgnorer() *H2CUpgradeIgnorer {
+	return &H2CUpgradeIgnorer{}
+}
+
+func (h *H2CUpgradeIgnorer) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if r.ProtoMajor == 1 && strings.EqualFold(strings.TrimSpace(r.Header.Get("Upgrade")), "h2c") {
+		r.Header.Del("Upgrade")
+		r.Header.Del("Http2-Settings")
+
+		var tokens []string
+		for _, value := range r.Header["Connection"] {
+			for _, token := range strings.Split(value, ",") {
+				token = strings.TrimSpace(token)
+				if len(token) > 0 && !strings.EqualFold(token, "Upgrade") && !strings.EqualFold(token, "HTTP2-Settings") {
+					tokens = append(tokens, token)
+				}
+			}
+		}
+		if len(tokens) > 0 {
+			r.Header.Set("Connection", strings.Join(tokens, ", "))
+		} else {
+			r.Header.Del("Connection")
+		}
+	}
+
+	next(rw, r)
+}
@@ -0,0 +1,70 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestH2CUpgradeIgnorer(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		headers         http.Header
+		expectedHeaders http.Header
+	}{
+		{
+			desc:            "no upgrade",
+			headers:         http.Header{"Connection": {"keep-alive"}},
+			expectedHeaders: http.Header{"Connection": {"keep-alive"}},
+		},
+		{
+			desc: "h2c upgrade",
+			headers: http.Header{
+				"Connection":     {"Upgrade, HTTP2-Settings"},
+				"Upgrade":        {"h2c"},
+				"Http2-Settings": {"AAMAAABkAARAAAAAAAIAAAAA"},
+			},
+			expectedHeaders: http.Header{},
+		},
+		{
+			desc: "h2c upgrade with other connection options",
+			headers: http.Header{
+				"Connection":     {"keep-alive, Upgrade, HTTP2-Settings"},
+				"Upgrade":        {"h2c"},
+				"Http2-Settings": {"AAMAAABkAARAAAAAAAIAAAAA"},
+			},
+			expectedHeaders: http.Header{"Connection": {"keep-alive"}},
+		},
+		{
+			desc: "websocket upgrade",
+			headers: http.Header{
+				"Connection": {"Upgrade"},
+				"Upgrade":    {"websocket"},
+			},
+			expectedHeaders: http.Header{
+				"Connection": {"Upgrade"},
+				"Upgrade":    {"websocket"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header = test.headers
+
+			var forwarded http.Header
+			NewH2CUpgradeIgnorer().ServeHTTP(httptest.NewRecorder(), req, func(rw http.ResponseWriter, r *http.Request) {
+				forwarded = r.Header
+			})
+
+			assert.Equal(t, test.expectedHeaders, forwarded)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package middlewares
+
+import (
+	"hash/fnv"
+	"net/http"
+	"net/url"
+
+	"github.com/containous/traefik/healthcheck"
+)
+
+// HashAffinity is a middleware sending the requests carrying the same value of a header
+// or of a query parameter to the same server, for clients that don't keep cookies.
+// The requests without value are load-balanced.
+type HashAffinity struct {
+	lb      healthcheck.LoadBalancer
+	next    http.Handler
+	balance http.Handler
+	header  string
+	query   string
+}
+
+// NewHashAffinity creates a new HashAffinity instance.
+// next forwards the requests to the server set in their URL, and balance load-balances them.
+func NewHashAffinity(lb healthcheck.LoadBalancer, next http.Handler, balance http.Handler, header, query string) *HashAffinity {
+	return &HashAffinity{
+		lb:      lb,
+		next:    next,
+		balance: balance,
+		header:  header,
+		query:   query,
+	}
+}
+
+func (h *HashAffinity) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	var key string
+	if len(h.header) > 0 {
+		key = r.Header.Get(h.header)
+	}
+	if len(key) == 0 && len(h.query) > 0 {
+		key = r.URL.Query().Get(h.query)
+	}
+
+	server := selectServer(key, h.lb.Servers())
+	if server == nil {
+		h.balance.ServeHTTP(rw, r)
+		return
+	}
+
+	// make shallow copy of request before changing anything to avoid side effects
+	newReq := *r
+	newReq.URL = server
+	h.next.ServeHTTP(rw, &newReq)
+}
+
+// selectServer uses rendezvous hashing, so that the removal of a server
+// only moves the keys which were sent to it.
+func selectServer(key string, servers []*url.URL) *url.URL {
+	if len(key) == 0 {
+		return nil
+	}
+
+	var selected *url.URL
+	var maxScore uint64
+	for _, server := range servers {
+		hash := fnv.New64a()
+		hash.Write([]byte(key))
+		hash.Write([]byte(server.String()))
+		if score := hash.Sum64(); selected == nil || score > maxScore {
+			selected = server
+			maxScore = score
+		}
+	}
+	return selected
+}
@@ -0,0 +1,115 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+type serversLoadBalancer struct {
+	servers []*url.URL
+}
+
+func (lb *serversLoadBalancer) RemoveServer(u *url.URL) error {
+	return nil
+}
+
+func (lb *serversLoadBalancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	return nil
+}
+
+func (lb *serversLoadBalancer) Servers() []*url.URL {
+	return lb.servers
+}
+
+func TestHashAffinity(t *testing.T) {
+	servers := []*url.URL{
+		testhelpers.MustParseURL("http://10.0.0.1:80"),
+		testhelpers.MustParseURL("http://10.0.0.2:80"),
+		testhelpers.MustParseURL("http://10.0.0.3:80"),
+	}
+
+	serve := func(header, query string, target string, mutators ...func(*http.Request)) string {
+		var server string
+		next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			server = r.URL.String()
+		})
+		balance := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			server = "balanced"
+		})
+		handler := NewHashAffinity(&serversLoadBalancer{servers: servers}, next, balance, header, query)
+
+		req := testhelpers.MustNewRequest(http.MethodGet, target, nil)
+		for _, mutator := range mutators {
+			mutator(req)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return server
+	}
+
+	withTenant := func(tenant string) func(*http.Request) {
+		return func(req *http.Request) {
+			req.Header.Set("X-Tenant-ID", tenant)
+		}
+	}
+
+	t.Run("without value", func(t *testing.T) {
+		assert.Equal(t, "balanced", serve("X-Tenant-ID", "tenant", "http://localhost/"))
+	})
+
+	t.Run("same header value", func(t *testing.T) {
+		first := serve("X-Tenant-ID", "", "http://localhost/", withTenant("foo"))
+		assert.NotEqual(t, "balanced", first)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, first, serve("X-Tenant-ID", "", "http://localhost/", withTenant("foo")))
+		}
+	})
+
+	t.Run("query value", func(t *testing.T) {
+		fromQuery := serve("", "tenant", "http://localhost/?tenant=foo")
+		assert.NotEqual(t, "balanced", fromQuery)
+		assert.Equal(t, fromQuery, serve("", "tenant", "http://localhost/?tenant=foo"))
+	})
+
+	t.Run("header takes precedence over query", func(t *testing.T) {
+		fromHeader := serve("X-Tenant-ID", "", "http://localhost/", withTenant("foo"))
+		assert.Equal(t, fromHeader, serve("X-Tenant-ID", "tenant", "http://localhost/?tenant=bar", withTenant("foo")))
+	})
+
+	t.Run("values are spread over the servers", func(t *testing.T) {
+		selected := make(map[string]bool)
+		for _, tenant := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+			selected[serve("X-Tenant-ID", "", "http://localhost/", withTenant(tenant))] = true
+		}
+		assert.True(t, len(selected) > 1)
+	})
+}
+
+func TestSelectServerStableOnRemoval(t *testing.T) {
+	servers := []*url.URL{
+		testhelpers.MustParseURL("http://10.0.0.1:80"),
+		testhelpers.MustParseURL("http://10.0.0.2:80"),
+		testhelpers.MustParseURL("http://10.0.0.3:80"),
+	}
+
+	for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		selected := selectServer(key, servers)
+
+		var remaining []*url.URL
+		for _, server := range servers {
+			if server != selected {
+				remaining = append(remaining, server)
+			}
+		}
+		removed := remaining[0]
+		remaining = remaining[1:]
+		remaining = append(remaining, selected)
+
+		assert.Equal(t, selected, selectServer(key, remaining), "removal of %s moved the key %s", removed, key)
+	}
+}
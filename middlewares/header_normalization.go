@@ -0,0 +1,78 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/containous/traefik/middlewares/tracing"
+	"github.com/containous/traefik/types"
+)
+
+// HeaderNormalizer is a middleware normalizing the request headers before the rule matching,
+// so that the backends get predictable headers.
+// The header keys are already canonicalized, and the duplicate Host headers and conflicting Content-Length headers
+// already rejected by the HTTP server.
+type HeaderNormalizer struct {
+	mergeDuplicates  []string
+	rejectDuplicates []string
+	hopByHopHeaders  []string
+	canonicalize     bool
+}
+
+// NewHeaderNormalizer returns a new HeaderNormalizer instance
+func NewHeaderNormalizer(config *types.HeaderNormalization) *HeaderNormalizer {
+	return &HeaderNormalizer{
+		mergeDuplicates:  canonicalKeys(config.MergeDuplicates),
+		rejectDuplicates: canonicalKeys(config.RejectDuplicates),
+		hopByHopHeaders:  canonicalKeys(config.HopByHopHeaders),
+		canonicalize:     config.Canonicalize,
+	}
+}
+
+func (h *HeaderNormalizer) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if h.canonicalize {
+		r.Host = canonicalHost(r.Host)
+	}
+
+	for _, key := range h.rejectDuplicates {
+		if len(r.Header[key]) > 1 {
+			tracing.SetErrorAndDebugLog(r, "duplicate %s header - rejecting", key)
+			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+	}
+
+	for _, key := range h.mergeDuplicates {
+		if values := r.Header[key]; len(values) > 1 {
+			r.Header.Set(key, strings.Join(values, ", "))
+		}
+	}
+
+	for _, key := range h.hopByHopHeaders {
+		r.Header.Del(key)
+	}
+
+	next.ServeHTTP(rw, r)
+}
+
+func canonicalKeys(keys []string) []string {
+	var canonical []string
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); len(key) > 0 {
+			canonical = append(canonical, http.CanonicalHeaderKey(key))
+		}
+	}
+	return canonical
+}
+
+// canonicalHost lowercases the host and removes the trailing dot of a fully qualified domain name.
+func canonicalHost(host string) string {
+	host = strings.ToLower(host)
+
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		return strings.TrimSuffix(host, ".")
+	}
+	return net.JoinHostPort(strings.TrimSuffix(hostname, "."), port)
+}
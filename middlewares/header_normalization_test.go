@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderNormalizer(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         *types.HeaderNormalization
+		host           string
+		headers        http.Header
+		expectedStatus int
+		expectedHost   string
+		expectedHeader http.Header
+	}{
+		{
+			desc:           "no normalization",
+			config:         &types.HeaderNormalization{},
+			host:           "Foo.Example.com.",
+			headers:        http.Header{"Via": {"1.1 a", "1.1 b"}},
+			expectedStatus: http.StatusOK,
+			expectedHost:   "Foo.Example.com.",
+			expectedHeader: http.Header{"Via": {"1.1 a", "1.1 b"}},
+		},
+		{
+			desc:           "merge duplicates",
+			config:         &types.HeaderNormalization{MergeDuplicates: []string{"via"}},
+			host:           "foo.example.com",
+			headers:        http.Header{"Via": {"1.1 a", "1.1 b"}, "Accept": {"text/html", "text/plain"}},
+			expectedStatus: http.StatusOK,
+			expectedHost:   "foo.example.com",
+			expectedHeader: http.Header{"Via": {"1.1 a, 1.1 b"}, "Accept": {"text/html", "text/plain"}},
+		},
+		{
+			desc:           "reject duplicates",
+			config:         &types.HeaderNormalization{RejectDuplicates: []string{"Authorization"}},
+			host:           "foo.example.com",
+			headers:        http.Header{"Authorization": {"Basic foo", "Basic bar"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "single value is not rejected",
+			config:         &types.HeaderNormalization{RejectDuplicates: []string{"Authorization"}},
+			host:           "foo.example.com",
+			headers:        http.Header{"Authorization": {"Basic foo"}},
+			expectedStatus: http.StatusOK,
+			expectedHost:   "foo.example.com",
+			expectedHeader: http.Header{"Authorization": {"Basic foo"}},
+		},
+		{
+			desc:           "strip hop-by-hop headers",
+			config:         &types.HeaderNormalization{HopByHopHeaders: []string{"X-Internal-Hop"}},
+			host:           "foo.example.com",
+			headers:        http.Header{"X-Internal-Hop": {"1"}, "X-Foo": {"bar"}},
+			expectedStatus: http.StatusOK,
+			expectedHost:   "foo.example.com",
+			expectedHeader: http.Header{"X-Foo": {"bar"}},
+		},
+		{
+			desc:           "canonicalize host",
+			config:         &types.HeaderNormalization{Canonicalize: true},
+			host:           "Foo.Example.com.:8080",
+			headers:        http.Header{},
+			expectedStatus: http.StatusOK,
+			expectedHost:   "foo.example.com:8080",
+			expectedHeader: http.Header{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Host = test.host
+			req.Header = test.headers
+
+			var nextReq *http.Request
+			next := func(rw http.ResponseWriter, r *http.Request) {
+				nextReq = r
+			}
+
+			rw := httptest.NewRecorder()
+			NewHeaderNormalizer(test.config).ServeHTTP(rw, req, next)
+
+			assert.Equal(t, test.expectedStatus, rw.Code)
+			if test.expectedStatus != http.StatusOK {
+				assert.Nil(t, nextReq)
+				return
+			}
+			assert.Equal(t, test.expectedHost, nextReq.Host)
+			assert.Equal(t, test.expectedHeader, nextReq.Header)
+		})
+	}
+}
@@ -2,11 +2,11 @@ package middlewares
 
 import (
 	"fmt"
-	"net"
 	"net/http"
 
 	"github.com/containous/traefik/log"
 	"github.com/containous/traefik/middlewares/tracing"
+	"github.com/containous/traefik/types"
 	"github.com/containous/traefik/whitelist"
 	"github.com/pkg/errors"
 	"github.com/urfave/negroni"
@@ -16,10 +16,12 @@ import (
 type IPWhiteLister struct {
 	handler     negroni.Handler
 	whiteLister *whitelist.IP
+	strategy    whitelist.Strategy
 }
 
-// NewIPWhitelister builds a new IPWhiteLister given a list of CIDR-Strings to whitelist
-func NewIPWhitelister(whitelistStrings []string) (*IPWhiteLister, error) {
+// NewIPWhitelister builds a new IPWhiteLister given a list of CIDR-Strings to whitelist,
+// and the strategy getting the IP of the client, the address of the connection when it is nil
+func NewIPWhitelister(whitelistStrings []string, ipStrategy *types.IPStrategy) (*IPWhiteLister, error) {
 
 	if len(whitelistStrings) == 0 {
 		return nil, errors.New("no whitelists provided")
@@ -33,6 +35,12 @@ func NewIPWhitelister(whitelistStrings []string) (*IPWhiteLister, error) {
 	}
 	whiteLister.whiteLister = ip
 
+	strategy, err := whitelist.NewStrategy(ipStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("creating IP strategy: %v", err)
+	}
+	whiteLister.strategy = strategy
+
 	whiteLister.handler = negroni.HandlerFunc(whiteLister.handle)
 	log.Debugf("configured %u IP whitelists: %s", len(whitelistStrings), whitelistStrings)
 
@@ -40,9 +48,9 @@ func NewIPWhitelister(whitelistStrings []string) (*IPWhiteLister, error) {
 }
 
 func (wl *IPWhiteLister) handle(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
-	ipAddress, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		tracing.SetErrorAndWarnLog(r, "unable to parse remote-address from header: %s - rejecting", r.RemoteAddr)
+	ipAddress := wl.strategy.GetIP(r)
+	if len(ipAddress) == 0 {
+		tracing.SetErrorAndWarnLog(r, "unable to get the source-IP of remote-address %s - rejecting", r.RemoteAddr)
 		reject(w)
 		return
 	}
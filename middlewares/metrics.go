@@ -40,6 +40,16 @@ func NewBackendMetricsMiddleware(registry metrics.Registry, backendName string)
 	}
 }
 
+// NewTenantMetricsMiddleware creates a new metrics middleware for a tenant, to be shared by the frontends of the tenant.
+func NewTenantMetricsMiddleware(registry metrics.Registry, tenant string) negroni.Handler {
+	return &metricsMiddleware{
+		reqsCounter:          registry.TenantReqsCounter(),
+		reqDurationHistogram: registry.TenantReqDurationHistogram(),
+		openConnsGauge:       registry.TenantOpenConnsGauge(),
+		baseLabels:           []string{"tenant", tenant},
+	}
+}
+
 type metricsMiddleware struct {
 	reqsCounter          gokitmetrics.Counter
 	reqDurationHistogram gokitmetrics.Histogram
@@ -0,0 +1,142 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/middlewares/tracing"
+	"github.com/containous/traefik/types"
+)
+
+// OverloadLimiter limits the number of requests handled concurrently, the other requests waiting in a queue
+// ordered by priority. When the queue is full, the request with the lowest priority is shed.
+type OverloadLimiter struct {
+	maxConcurrency int
+	maxQueueSize   int
+	queueTimeout   time.Duration
+
+	lock     sync.Mutex
+	inFlight int
+	// queue is sorted by decreasing priority, the requests of a same priority in their arrival order.
+	queue []*overloadWaiter
+}
+
+// overloadWaiter is a queued request, receiving whether it is admitted or shed.
+type overloadWaiter struct {
+	priority int
+	ready    chan bool
+}
+
+// NewOverloadLimiter returns a new OverloadLimiter, shared by all the frontends.
+func NewOverloadLimiter(config *types.Overload) *OverloadLimiter {
+	return &OverloadLimiter{
+		maxConcurrency: config.MaxConcurrency,
+		maxQueueSize:   config.MaxQueueSize,
+		queueTimeout:   time.Duration(config.QueueTimeout),
+	}
+}
+
+// acquire waits for a request of the given priority to be admitted, and returns false when it is shed.
+// An admitted request must be followed by a call to release.
+func (l *OverloadLimiter) acquire(ctx context.Context, priority int) bool {
+	l.lock.Lock()
+	if l.inFlight < l.maxConcurrency && len(l.queue) == 0 {
+		l.inFlight++
+		l.lock.Unlock()
+		return true
+	}
+
+	if len(l.queue) >= l.maxQueueSize {
+		if len(l.queue) == 0 || l.queue[len(l.queue)-1].priority >= priority {
+			l.lock.Unlock()
+			return false
+		}
+		lowest := l.queue[len(l.queue)-1]
+		l.queue = l.queue[:len(l.queue)-1]
+		lowest.ready <- false
+	}
+
+	waiter := &overloadWaiter{priority: priority, ready: make(chan bool, 1)}
+	index := sort.Search(len(l.queue), func(i int) bool { return l.queue[i].priority < priority })
+	l.queue = append(l.queue, nil)
+	copy(l.queue[index+1:], l.queue[index:])
+	l.queue[index] = waiter
+	l.lock.Unlock()
+
+	var timeout <-chan time.Time
+	if l.queueTimeout > 0 {
+		timer := time.NewTimer(l.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case admitted := <-waiter.ready:
+		return admitted
+	case <-ctx.Done():
+	case <-timeout:
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for i, w := range l.queue {
+		if w == waiter {
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			return false
+		}
+	}
+	// the request has been admitted or shed in the meantime
+	return <-waiter.ready
+}
+
+// release hands the slot of a finished request over to the queued request with the highest priority.
+func (l *OverloadLimiter) release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if len(l.queue) == 0 {
+		l.inFlight--
+		return
+	}
+	next := l.queue[0]
+	l.queue = l.queue[1:]
+	next.ready <- true
+}
+
+// OverloadProtection is a middleware applying the OverloadLimiter to the requests of a frontend,
+// rejecting the shed requests (503).
+type OverloadProtection struct {
+	limiter *OverloadLimiter
+	config  *types.Overload
+	class   string
+}
+
+// NewOverloadProtection returns a new OverloadProtection instance for a frontend of the given priority class.
+func NewOverloadProtection(limiter *OverloadLimiter, config *types.Overload, class string) *OverloadProtection {
+	return &OverloadProtection{
+		limiter: limiter,
+		config:  config,
+		class:   class,
+	}
+}
+
+func (o *OverloadProtection) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	class := o.class
+	if len(o.config.ClassHeader) > 0 {
+		if headerClass := r.Header.Get(o.config.ClassHeader); len(headerClass) > 0 {
+			class = headerClass
+		}
+	}
+
+	if !o.limiter.acquire(r.Context(), o.config.Priority(class)) {
+		tracing.SetErrorAndDebugLog(r, "overloaded, shedding request of priority class %q", class)
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	defer o.limiter.release()
+
+	next(rw, r)
+}
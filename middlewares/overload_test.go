@@ -0,0 +1,111 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitQueued waits for the limiter to hold the given number of queued requests.
+func waitQueued(t *testing.T, limiter *OverloadLimiter, count int) {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		limiter.lock.Lock()
+		queued := len(limiter.queue)
+		limiter.lock.Unlock()
+		if queued == count {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected %d queued requests", count)
+}
+
+func TestOverloadLimiterPriorityOrder(t *testing.T) {
+	limiter := NewOverloadLimiter(&types.Overload{MaxConcurrency: 1, MaxQueueSize: 2})
+	require.True(t, limiter.acquire(context.Background(), 0))
+
+	admitted := make(chan int, 2)
+	for i, priority := range []int{1, 10} {
+		go func(priority int) {
+			if limiter.acquire(context.Background(), priority) {
+				admitted <- priority
+				limiter.release()
+			}
+		}(priority)
+		waitQueued(t, limiter, i+1)
+	}
+
+	limiter.release()
+	assert.Equal(t, 10, <-admitted)
+	assert.Equal(t, 1, <-admitted)
+}
+
+func TestOverloadLimiterShedding(t *testing.T) {
+	limiter := NewOverloadLimiter(&types.Overload{MaxConcurrency: 1, MaxQueueSize: 1})
+	require.True(t, limiter.acquire(context.Background(), 0))
+
+	lowPriority := make(chan bool)
+	go func() {
+		lowPriority <- limiter.acquire(context.Background(), 1)
+	}()
+	waitQueued(t, limiter, 1)
+
+	// a request of a lower or equal priority is shed when the queue is full
+	assert.False(t, limiter.acquire(context.Background(), 1))
+
+	// a request of a higher priority takes the place of the lowest priority one
+	highPriority := make(chan bool)
+	go func() {
+		highPriority <- limiter.acquire(context.Background(), 10)
+	}()
+	assert.False(t, <-lowPriority)
+
+	limiter.release()
+	assert.True(t, <-highPriority)
+}
+
+func TestOverloadLimiterQueueTimeout(t *testing.T) {
+	limiter := NewOverloadLimiter(&types.Overload{MaxConcurrency: 1, MaxQueueSize: 1, QueueTimeout: flaeg.Duration(10 * time.Millisecond)})
+	require.True(t, limiter.acquire(context.Background(), 0))
+
+	assert.False(t, limiter.acquire(context.Background(), 0))
+	waitQueued(t, limiter, 0)
+
+	limiter.release()
+	assert.True(t, limiter.acquire(context.Background(), 0))
+}
+
+func TestOverloadProtection(t *testing.T) {
+	config := &types.Overload{
+		MaxConcurrency: 1,
+		ClassHeader:    "X-Priority-Class",
+		Classes:        types.PriorityClasses{{Name: "critical", Priority: 10}},
+	}
+	limiter := NewOverloadLimiter(config)
+	require.True(t, limiter.acquire(context.Background(), 0))
+
+	protection := NewOverloadProtection(limiter, config, "")
+	next := func(rw http.ResponseWriter, r *http.Request) {}
+
+	// without a queue, any request is shed while the limiter is full
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+	req.Header.Set("X-Priority-Class", "critical")
+	recorder := httptest.NewRecorder()
+	protection.ServeHTTP(recorder, req, next)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	limiter.release()
+	recorder = httptest.NewRecorder()
+	protection.ServeHTTP(recorder, req, next)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/satori/go.uuid"
+)
+
+// RequestID is a middleware setting a unique ID in a request header when it is missing,
+// and returning the ID in the same response header.
+type RequestID struct {
+	header string
+}
+
+// NewRequestID returns a new RequestID instance using the given header.
+func NewRequestID(header string) *RequestID {
+	return &RequestID{header: header}
+}
+
+func (i *RequestID) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	id := r.Header.Get(i.header)
+	if len(id) == 0 {
+		id = uuid.NewV4().String()
+		r.Header.Set(i.header, id)
+	}
+	rw.Header().Set(i.header, id)
+
+	next(rw, r)
+}
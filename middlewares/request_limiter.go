@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/containous/traefik/middlewares/tracing"
+)
+
+// RequestLimiter is a middleware rejecting the requests with too many header fields (431),
+// or with a too long URI (414).
+// The size of the headers is limited by the HTTP server itself.
+type RequestLimiter struct {
+	maxHeaderCount int
+	maxURILength   int
+}
+
+// NewRequestLimiter returns a new RequestLimiter instance, a zero limit being ignored.
+func NewRequestLimiter(maxHeaderCount, maxURILength int) *RequestLimiter {
+	return &RequestLimiter{
+		maxHeaderCount: maxHeaderCount,
+		maxURILength:   maxURILength,
+	}
+}
+
+func (l *RequestLimiter) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if l.maxURILength > 0 && len(r.RequestURI) > l.maxURILength {
+		tracing.SetErrorAndDebugLog(r, "request URI of %d bytes exceeding the limit of %d - rejecting", len(r.RequestURI), l.maxURILength)
+		http.Error(rw, http.StatusText(http.StatusRequestURITooLong), http.StatusRequestURITooLong)
+		return
+	}
+
+	if l.maxHeaderCount > 0 {
+		var count int
+		for _, values := range r.Header {
+			count += len(values)
+		}
+		if count > l.maxHeaderCount {
+			tracing.SetErrorAndDebugLog(r, "%d header fields exceeding the limit of %d - rejecting", count, l.maxHeaderCount)
+			http.Error(rw, http.StatusText(http.StatusRequestHeaderFieldsTooLarge), http.StatusRequestHeaderFieldsTooLarge)
+			return
+		}
+	}
+
+	next(rw, r)
+}
@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLimiter(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		maxHeaderCount int
+		maxURILength   int
+		requestURI     string
+		headers        http.Header
+		expectedStatus int
+	}{
+		{
+			desc:           "no limits",
+			requestURI:     "/foo?bar=baz",
+			headers:        http.Header{"X-Foo": {"a", "b"}, "X-Bar": {"c"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "URI within the limit",
+			maxURILength:   12,
+			requestURI:     "/foo?bar=baz",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "URI exceeding the limit",
+			maxURILength:   11,
+			requestURI:     "/foo?bar=baz",
+			expectedStatus: http.StatusRequestURITooLong,
+		},
+		{
+			desc:           "header count within the limit",
+			maxHeaderCount: 3,
+			requestURI:     "/",
+			headers:        http.Header{"X-Foo": {"a", "b"}, "X-Bar": {"c"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "header count exceeding the limit",
+			maxHeaderCount: 2,
+			requestURI:     "/",
+			headers:        http.Header{"X-Foo": {"a", "b"}, "X-Bar": {"c"}},
+			expectedStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost"+test.requestURI, nil)
+			req.RequestURI = test.requestURI
+			req.Header = test.headers
+
+			var called bool
+			next := func(rw http.ResponseWriter, r *http.Request) {
+				called = true
+			}
+
+			rw := httptest.NewRecorder()
+			NewRequestLimiter(test.maxHeaderCount, test.maxURILength).ServeHTTP(rw, req, next)
+
+			assert.Equal(t, test.expectedStatus, rw.Code)
+			assert.Equal(t, test.expectedStatus == http.StatusOK, called)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Response headers describing how a request has been routed.
+const (
+	RoutingDebugEntryPointHeader  = "X-Traefik-Debug-Entrypoint"
+	RoutingDebugFrontendHeader    = "X-Traefik-Debug-Frontend"
+	RoutingDebugRuleHeader        = "X-Traefik-Debug-Rule"
+	RoutingDebugServerHeader      = "X-Traefik-Debug-Server"
+	RoutingDebugMiddlewaresHeader = "X-Traefik-Debug-Middlewares"
+)
+
+// routingDebugCtxKey is a custom type that is used as key for the context.
+type routingDebugCtxKey string
+
+// defaultRoutingDebugCtxKey is the key which value tells if the routing of the request must be described.
+var defaultRoutingDebugCtxKey routingDebugCtxKey = "RoutingDebugCtxKey"
+
+// RoutingDebug is a middleware that adds response headers describing the routing of the request
+// (entrypoint, frontend, rule, middlewares and selected server),
+// only when the request carries the debug header with the expected secret.
+type RoutingDebug struct {
+	header      string
+	secret      string
+	entryPoint  string
+	frontend    string
+	rules       []string
+	Middlewares []string
+}
+
+// NewRoutingDebug returns a new RoutingDebug instance
+func NewRoutingDebug(header, secret, entryPoint, frontend string, rules []string) *RoutingDebug {
+	return &RoutingDebug{
+		header:     header,
+		secret:     secret,
+		entryPoint: entryPoint,
+		frontend:   frontend,
+		rules:      rules,
+	}
+}
+
+func (rd *RoutingDebug) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	value := r.Header.Get(rd.header)
+	if len(value) == 0 || subtle.ConstantTimeCompare([]byte(value), []byte(rd.secret)) != 1 {
+		next(rw, r)
+		return
+	}
+
+	// the secret must never reach the backend servers
+	r.Header.Del(rd.header)
+
+	rw.Header().Set(RoutingDebugEntryPointHeader, rd.entryPoint)
+	rw.Header().Set(RoutingDebugFrontendHeader, rd.frontend)
+	rw.Header().Set(RoutingDebugRuleHeader, strings.Join(rd.rules, " | "))
+	rw.Header().Set(RoutingDebugMiddlewaresHeader, strings.Join(rd.Middlewares, ","))
+
+	next(rw, r.WithContext(context.WithValue(r.Context(), defaultRoutingDebugCtxKey, true)))
+}
+
+// NewRoutingDebugServer returns a handler adding the URL of the selected server to the response headers
+// when the routing of the request must be described.
+// It must be placed between the load-balancer and the forwarder.
+func NewRoutingDebugServer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if enabled, ok := r.Context().Value(defaultRoutingDebugCtxKey).(bool); ok && enabled {
+			rw.Header().Set(RoutingDebugServerHeader, r.URL.Scheme+"://"+r.URL.Host)
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
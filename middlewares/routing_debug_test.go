@@ -0,0 +1,96 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/negroni"
+)
+
+func TestRoutingDebug(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		headerValue     string
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:        "without debug header",
+			headerValue: "",
+			expectedHeaders: map[string]string{
+				RoutingDebugEntryPointHeader:  "",
+				RoutingDebugFrontendHeader:    "",
+				RoutingDebugRuleHeader:        "",
+				RoutingDebugMiddlewaresHeader: "",
+				RoutingDebugServerHeader:      "",
+			},
+		},
+		{
+			desc:        "with wrong secret",
+			headerValue: "wrong",
+			expectedHeaders: map[string]string{
+				RoutingDebugEntryPointHeader:  "",
+				RoutingDebugFrontendHeader:    "",
+				RoutingDebugRuleHeader:        "",
+				RoutingDebugMiddlewaresHeader: "",
+				RoutingDebugServerHeader:      "",
+			},
+		},
+		{
+			desc:        "with expected secret",
+			headerValue: "s3cr3t",
+			expectedHeaders: map[string]string{
+				RoutingDebugEntryPointHeader:  "http",
+				RoutingDebugFrontendHeader:    "frontend1",
+				RoutingDebugRuleHeader:        "Host:foo.bar | Path:/api",
+				RoutingDebugMiddlewaresHeader: "headers,retry",
+				RoutingDebugServerHeader:      "http://10.0.0.1:80",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var forwardedHeader string
+			backend := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				forwardedHeader = r.Header.Get("X-Traefik-Debug")
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			// simulates the load-balancer selecting a server
+			serverURL, _ := url.Parse("http://10.0.0.1:80/")
+			lb := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				newReq := *r
+				newReq.URL = serverURL
+				NewRoutingDebugServer(backend).ServeHTTP(rw, &newReq)
+			})
+
+			routingDebug := NewRoutingDebug("X-Traefik-Debug", "s3cr3t", "http", "frontend1", []string{"Host:foo.bar", "Path:/api"})
+			routingDebug.Middlewares = []string{"headers", "retry"}
+			handler := negroni.New(routingDebug, negroni.Wrap(lb))
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar/api", nil)
+			if len(test.headerValue) > 0 {
+				req.Header.Set("X-Traefik-Debug", test.headerValue)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, recorder.Header().Get(name), name)
+			}
+			if test.headerValue == "s3cr3t" {
+				assert.Empty(t, forwardedHeader)
+			} else {
+				assert.Equal(t, test.headerValue, forwardedHeader)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/containous/traefik/metrics"
+	"github.com/containous/traefik/middlewares/tracing"
+	"github.com/containous/traefik/types"
+	"golang.org/x/time/rate"
+)
+
+// TenantQuota is a middleware rejecting the requests (429) exceeding the requests per second,
+// or the concurrent connections, allowed to a tenant across all its frontends.
+type TenantQuota struct {
+	tenant         string
+	limiter        *rate.Limiter
+	maxConnections int64
+	connections    int64
+	registry       metrics.Registry
+}
+
+// NewTenantQuota returns a new TenantQuota instance, to be shared by the frontends of the tenant.
+func NewTenantQuota(quota *types.TenantQuota, tenant string, registry metrics.Registry) *TenantQuota {
+	q := &TenantQuota{
+		tenant:         tenant,
+		maxConnections: quota.MaxConnections,
+		registry:       registry,
+	}
+	if quota.MaxRPS > 0 {
+		q.limiter = rate.NewLimiter(rate.Limit(quota.MaxRPS), quota.MaxRPS)
+	}
+	return q
+}
+
+func (q *TenantQuota) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if q.limiter != nil && !q.limiter.Allow() {
+		q.reject(rw, r, "rps")
+		return
+	}
+
+	if q.maxConnections > 0 {
+		defer atomic.AddInt64(&q.connections, -1)
+		if atomic.AddInt64(&q.connections, 1) > q.maxConnections {
+			q.reject(rw, r, "connections")
+			return
+		}
+	}
+
+	next(rw, r)
+}
+
+func (q *TenantQuota) reject(rw http.ResponseWriter, r *http.Request, quota string) {
+	tracing.SetErrorAndDebugLog(r, "%s quota of tenant %s exceeded - rejecting", quota, q.tenant)
+	q.registry.TenantQuotaRejectionsCounter().With("quota", quota, "tenant", q.tenant).Add(1)
+	http.Error(rw, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+}
@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/containous/traefik/metrics"
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantQuotaRPS(t *testing.T) {
+	quota := NewTenantQuota(&types.TenantQuota{MaxRPS: 2}, "team-a", metrics.NewVoidRegistry())
+	next := func(rw http.ResponseWriter, r *http.Request) {}
+
+	var statuses []int
+	for i := 0; i < 3; i++ {
+		recorder := httptest.NewRecorder()
+		quota.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil), next)
+		statuses = append(statuses, recorder.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, statuses)
+}
+
+func TestTenantQuotaConnections(t *testing.T) {
+	quota := NewTenantQuota(&types.TenantQuota{MaxConnections: 1}, "team-a", metrics.NewVoidRegistry())
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	next := func(rw http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	first := httptest.NewRecorder()
+	go func() {
+		defer wg.Done()
+		quota.ServeHTTP(first, testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil), next)
+	}()
+	<-started
+
+	second := httptest.NewRecorder()
+	quota.ServeHTTP(second, testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil), next)
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, http.StatusOK, first.Code)
+
+	third := httptest.NewRecorder()
+	quota.ServeHTTP(third, testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil), func(rw http.ResponseWriter, r *http.Request) {})
+	assert.Equal(t, http.StatusOK, third.Code)
+}
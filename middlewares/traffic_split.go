@@ -0,0 +1,69 @@
+package middlewares
+
+import (
+	"hash/fnv"
+	"net/http"
+	"net/url"
+)
+
+// TrafficSplit is a middleware sending a stable share of the users of a frontend to the servers of an alternate backend.
+// A user is identified by the value of a header or of a cookie, whose hash decides once and for all whether its requests
+// go to the alternate servers: unlike a random weighted split, a user doesn't switch between both across its requests.
+// The requests without value are load-balanced.
+type TrafficSplit struct {
+	servers    []*url.URL
+	next       http.Handler
+	balance    http.Handler
+	percentage int
+	header     string
+	cookie     string
+}
+
+// NewTrafficSplit creates a new TrafficSplit instance sending the given percentage of the users to the alternate servers.
+// next forwards the requests to the server set in their URL, and balance load-balances them.
+func NewTrafficSplit(servers []*url.URL, next http.Handler, balance http.Handler, percentage int, header, cookie string) *TrafficSplit {
+	return &TrafficSplit{
+		servers:    servers,
+		next:       next,
+		balance:    balance,
+		percentage: percentage,
+		header:     header,
+		cookie:     cookie,
+	}
+}
+
+func (t *TrafficSplit) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	var key string
+	if len(t.header) > 0 {
+		key = r.Header.Get(t.header)
+	}
+	if len(key) == 0 && len(t.cookie) > 0 {
+		if cookie, err := r.Cookie(t.cookie); err == nil {
+			key = cookie.Value
+		}
+	}
+
+	if len(key) == 0 || splitBucket(key) >= t.percentage {
+		t.balance.ServeHTTP(rw, r)
+		return
+	}
+
+	server := selectServer(key, t.servers)
+	if server == nil {
+		t.balance.ServeHTTP(rw, r)
+		return
+	}
+
+	// make shallow copy of request before changing anything to avoid side effects
+	newReq := *r
+	newReq.URL = server
+	t.next.ServeHTTP(rw, &newReq)
+}
+
+// splitBucket assigns a key to one of 100 buckets, the users of the first buckets going to the alternate servers,
+// so that increasing the percentage only moves users to the alternate servers.
+func splitBucket(key string) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return int(hash.Sum32() % 100)
+}
@@ -0,0 +1,101 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrafficSplit(t *testing.T) {
+	servers := []*url.URL{
+		testhelpers.MustParseURL("http://10.0.0.1:80"),
+		testhelpers.MustParseURL("http://10.0.0.2:80"),
+	}
+
+	serve := func(percentage int, mutators ...func(*http.Request)) string {
+		var server string
+		next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			server = r.URL.String()
+		})
+		balance := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			server = "balanced"
+		})
+		handler := NewTrafficSplit(servers, next, balance, percentage, "X-User-ID", "user")
+
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost/", nil)
+		for _, mutator := range mutators {
+			mutator(req)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return server
+	}
+
+	withUser := func(user string) func(*http.Request) {
+		return func(req *http.Request) {
+			req.Header.Set("X-User-ID", user)
+		}
+	}
+
+	withCookie := func(user string) func(*http.Request) {
+		return func(req *http.Request) {
+			req.AddCookie(&http.Cookie{Name: "user", Value: user})
+		}
+	}
+
+	t.Run("without value", func(t *testing.T) {
+		assert.Equal(t, "balanced", serve(100))
+	})
+
+	t.Run("no user with 0 percent", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			assert.Equal(t, "balanced", serve(0, withUser(fmt.Sprintf("user%d", i))))
+		}
+	})
+
+	t.Run("all the users with 100 percent", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			assert.NotEqual(t, "balanced", serve(100, withUser(fmt.Sprintf("user%d", i))))
+		}
+	})
+
+	t.Run("same user across requests", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			user := fmt.Sprintf("user%d", i)
+			first := serve(50, withUser(user))
+			for j := 0; j < 5; j++ {
+				assert.Equal(t, first, serve(50, withUser(user)))
+			}
+		}
+	})
+
+	t.Run("cookie value", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			user := fmt.Sprintf("user%d", i)
+			assert.Equal(t, serve(50, withUser(user)), serve(50, withCookie(user)))
+		}
+	})
+
+	t.Run("share of the users", func(t *testing.T) {
+		var split int
+		for i := 0; i < 1000; i++ {
+			if serve(20, withUser(fmt.Sprintf("user%d", i))) != "balanced" {
+				split++
+			}
+		}
+		assert.InDelta(t, 200, split, 50)
+	})
+
+	t.Run("increasing the percentage keeps the split users", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			user := fmt.Sprintf("user%d", i)
+			if server := serve(10, withUser(user)); server != "balanced" {
+				assert.Equal(t, server, serve(30, withUser(user)))
+			}
+		}
+	})
+}
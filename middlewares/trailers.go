@@ -0,0 +1,107 @@
+package middlewares
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/vulcand/oxy/forward"
+)
+
+// requestTrailersCtxKey is a custom type that is used as key for the context.
+type requestTrailersCtxKey string
+
+// defaultRequestTrailersCtxKey is the key which value holds the trailers of the incoming request.
+var defaultRequestTrailersCtxKey requestTrailersCtxKey = "RequestTrailersCtxKey"
+
+// Trailers is a middleware wrapping the forwarder so that the request and response trailers
+// are forwarded intact, even when the bodies are buffered by the upstream middlewares.
+// It must be used along with the request rewriter returned by NewTrailersRewriter.
+type Trailers struct {
+	next http.Handler
+}
+
+// NewTrailers returns a new Trailers instance
+func NewTrailers(next http.Handler) *Trailers {
+	return &Trailers{next: next}
+}
+
+func (t *Trailers) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if len(req.Trailer) > 0 {
+		// the trailers of the incoming request are only known once its body has been read
+		req = req.WithContext(context.WithValue(req.Context(), defaultRequestTrailersCtxKey, req.Trailer))
+	}
+
+	t.next.ServeHTTP(rw, req)
+
+	// The trailers announced by the backend are set as plain header keys once the body has been copied.
+	// A middleware writing the response headers after the forwarder (buffering, compression)
+	// would send them as headers, hence they are moved under the trailer prefix.
+	header := rw.Header()
+	for _, announced := range header["Trailer"] {
+		for _, key := range strings.Split(announced, ",") {
+			key = http.CanonicalHeaderKey(strings.TrimSpace(key))
+			if values, ok := header[key]; ok {
+				delete(header, key)
+				header[http.TrailerPrefix+key] = values
+			}
+		}
+	}
+}
+
+// NewTrailersRewriter wraps a request rewriter to forward the trailers of the outgoing requests.
+func NewTrailersRewriter(next forward.ReqRewriter) forward.ReqRewriter {
+	return &trailersRewriter{next: next}
+}
+
+type trailersRewriter struct {
+	next forward.ReqRewriter
+}
+
+func (t *trailersRewriter) Rewrite(req *http.Request) {
+	// the TE header is removed as an hop-by-hop header, but gRPC backends require "TE: trailers"
+	if acceptsTrailers(req) {
+		defer req.Header.Set(forward.Te, "trailers")
+	}
+
+	t.next.Rewrite(req)
+
+	trailer, ok := req.Context().Value(defaultRequestTrailersCtxKey).(http.Header)
+	if !ok || req.Body == nil || req.Trailer == nil {
+		return
+	}
+
+	// a buffered request gets a content length, which prevents the transport from sending the trailers
+	req.ContentLength = -1
+	req.Body = &trailersBody{ReadCloser: req.Body, in: trailer, out: req.Trailer}
+}
+
+func acceptsTrailers(req *http.Request) bool {
+	for _, te := range req.Header[forward.Te] {
+		for _, value := range strings.Split(te, ",") {
+			if strings.EqualFold(strings.TrimSpace(value), "trailers") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// trailersBody copies the trailers of the incoming request into the outgoing one
+// once the body has been read, as the transport writes them right after the body.
+type trailersBody struct {
+	io.ReadCloser
+	in  http.Header
+	out http.Header
+}
+
+func (b *trailersBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		for key, values := range b.in {
+			b.out[key] = values
+		}
+	}
+	return n, err
+}
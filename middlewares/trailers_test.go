@@ -0,0 +1,83 @@
+package middlewares
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/buffer"
+	"github.com/vulcand/oxy/forward"
+)
+
+func TestTrailers(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		buffering bool
+	}{
+		{
+			desc: "without buffering",
+		},
+		{
+			desc:      "with buffering",
+			buffering: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				rw.Header().Set("Trailer", "Grpc-Status")
+				rw.Header().Set("X-Request-Checksum", req.Trailer.Get("X-Checksum"))
+				rw.Header().Set("X-Request-Te", req.Header.Get("Te"))
+				rw.WriteHeader(http.StatusOK)
+				rw.Write([]byte("body"))
+				rw.Header().Set("Grpc-Status", "0")
+			}))
+			defer backend.Close()
+
+			fwd, err := forward.New(forward.Rewriter(NewTrailersRewriter(&forward.HeaderRewriter{})))
+			require.NoError(t, err)
+
+			var handler http.Handler = NewTrailers(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				req.URL = testhelpers.MustParseURL(backend.URL)
+				fwd.ServeHTTP(rw, req)
+			}))
+			if test.buffering {
+				handler, err = buffer.New(handler)
+				require.NoError(t, err)
+			}
+
+			proxy := httptest.NewServer(handler)
+			defer proxy.Close()
+
+			req := testhelpers.MustNewRequest(http.MethodPost, proxy.URL, ioutil.NopCloser(strings.NewReader("request")))
+			req.ContentLength = -1
+			req.Trailer = http.Header{"X-Checksum": {"42"}}
+			req.Header.Set("Te", "trailers")
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			_, err = io.Copy(ioutil.Discard, resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, "42", resp.Header.Get("X-Request-Checksum"))
+			assert.Equal(t, "trailers", resp.Header.Get("X-Request-Te"))
+			assert.Empty(t, resp.Header.Get("Grpc-Status"))
+			assert.Equal(t, "0", resp.Trailer.Get("Grpc-Status"))
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/cenk/backoff"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/provider/status"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+)
+
+// Operational event types sent to the webhooks.
+const (
+	CertificateExpiring   = "certificate.expiring"
+	ACMEFailure           = "acme.failure"
+	ProviderDisconnected  = "provider.disconnected"
+	BackendDown           = "backend.down"
+	ConfigurationError    = "configuration.error"
+	CertificateUnexpected = "certificate.unexpected"
+)
+
+// Severities of the events, as defined by PagerDuty.
+const (
+	SeverityCritical = "critical"
+	SeverityError    = "error"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+var severities = map[string]string{
+	CertificateExpiring:   SeverityWarning,
+	ACMEFailure:           SeverityError,
+	ProviderDisconnected:  SeverityError,
+	BackendDown:           SeverityCritical,
+	ConfigurationError:    SeverityError,
+	CertificateUnexpected: SeverityCritical,
+}
+
+const defaultTemplate = `[{{.Severity}}] {{.Message}} on {{.Hostname}}` +
+	`{{with .Provider}}, provider: {{.}}{{end}}{{with .Backend}}, backend: {{.}}{{end}}` +
+	`{{with .Domains}}, domains: {{join . ", "}}{{end}}{{with .Error}}: {{.}}{{end}}`
+
+const queueSize = 100
+
+// Event describes an operational event.
+type Event struct {
+	Type     string    `json:"type"`
+	Severity string    `json:"severity"`
+	Time     time.Time `json:"time"`
+	Hostname string    `json:"hostname,omitempty"`
+	Message  string    `json:"message"`
+	Provider string    `json:"provider,omitempty"`
+	Backend  string    `json:"backend,omitempty"`
+	Domains  []string  `json:"domains,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// key identifies the events about the same issue, to deduplicate them.
+func (e Event) key() string {
+	return strings.Join([]string{"traefik", e.Type, e.Hostname, e.Provider, e.Backend, strings.Join(e.Domains, ",")}, "/")
+}
+
+var (
+	mu      sync.RWMutex
+	senders []*sender
+)
+
+// Setup starts sending the events to the webhooks, Slack and PagerDuty, replacing the previous senders.
+func Setup(config *types.Notifications) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	stop()
+	if config == nil {
+		return
+	}
+
+	var dedupWindow time.Duration
+	if len(config.DedupWindow) > 0 {
+		var err error
+		dedupWindow, err = time.ParseDuration(config.DedupWindow)
+		if err != nil {
+			log.Errorf("Invalid notifications dedup window %q: %v", config.DedupWindow, err)
+		}
+	}
+
+	var endpoints []endpoint
+	for _, webhook := range config.Webhooks {
+		endpoints = append(endpoints, &webhookEndpoint{webhook: webhook})
+	}
+	if config.Slack != nil {
+		slack, err := newSlackEndpoint(config.Slack)
+		if err != nil {
+			log.Errorf("Unable to set up Slack notifications: %v", err)
+		} else {
+			endpoints = append(endpoints, slack)
+		}
+	}
+	if config.PagerDuty != nil {
+		pagerDuty, err := newPagerDutyEndpoint(config.PagerDuty)
+		if err != nil {
+			log.Errorf("Unable to set up PagerDuty notifications: %v", err)
+		} else {
+			endpoints = append(endpoints, pagerDuty)
+		}
+	}
+
+	for _, e := range endpoints {
+		s := newSender(e, dedupWindow, newBackOff)
+		safe.Go(s.run)
+		senders = append(senders, s)
+	}
+}
+
+// Stop stops sending the events, the pending ones being discarded.
+func Stop() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	stop()
+}
+
+func stop() {
+	for _, s := range senders {
+		s.cancel()
+	}
+	senders = nil
+}
+
+// Notify sends the event to the senders subscribed to its type, without waiting for the delivery.
+// The disconnections of the providers are recorded in their status as well.
+func Notify(event Event) {
+	if event.Type == ProviderDisconnected && len(event.Provider) > 0 {
+		status.RecordError(event.Provider, event.Error, true)
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if len(senders) == 0 {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	if len(event.Hostname) == 0 {
+		event.Hostname, _ = os.Hostname()
+	}
+	if len(event.Severity) == 0 {
+		event.Severity = severity(event.Type)
+	}
+
+	for _, s := range senders {
+		if !s.subscribed(event.Type) {
+			continue
+		}
+		select {
+		case s.queue <- event:
+		default:
+			log.Warnf("Notification queue of %s is full, dropping %s event", s.endpoint, event.Type)
+		}
+	}
+}
+
+func severity(eventType string) string {
+	if s, ok := severities[eventType]; ok {
+		return s
+	}
+	return SeverityInfo
+}
+
+func newTemplate(text string) (*template.Template, error) {
+	if len(text) == 0 {
+		text = defaultTemplate
+	}
+	return template.New("notification").Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+}
+
+func render(tmpl *template.Template, event Event) (string, error) {
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, event); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+func newJSONRequest(url string, body interface{}) (*http.Request, []byte, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, payload, nil
+}
+
+func newBackOff() backoff.BackOff {
+	ebo := backoff.NewExponentialBackOff()
+	ebo.MaxElapsedTime = 5 * time.Minute
+	return ebo
+}
+
+// endpoint builds the requests delivering the events to a receiver.
+type endpoint interface {
+	fmt.Stringer
+	events() []string
+	newRequest(event Event) (*http.Request, error)
+}
+
+// sender delivers the events to an endpoint one at a time, retrying the failed deliveries
+// and dropping the events already sent during the dedup window.
+type sender struct {
+	endpoint    endpoint
+	client      *http.Client
+	newBackOff  func() backoff.BackOff
+	dedupWindow time.Duration
+	lastSent    map[string]time.Time
+	queue       chan Event
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+func newSender(e endpoint, dedupWindow time.Duration, newBackOff func() backoff.BackOff) *sender {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &sender{
+		endpoint:    e,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		newBackOff:  newBackOff,
+		dedupWindow: dedupWindow,
+		lastSent:    make(map[string]time.Time),
+		queue:       make(chan Event, queueSize),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+func (s *sender) subscribed(eventType string) bool {
+	events := s.endpoint.events()
+	if len(events) == 0 {
+		return true
+	}
+	for _, e := range events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *sender) run() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case event := <-s.queue:
+			if s.duplicate(event) {
+				log.Debugf("Dropping %s event already sent to %s", event.Type, s.endpoint)
+				continue
+			}
+			s.deliver(event)
+		}
+	}
+}
+
+// duplicate reports whether the same event has been sent during the dedup window.
+func (s *sender) duplicate(event Event) bool {
+	if s.dedupWindow <= 0 {
+		return false
+	}
+
+	for key, sent := range s.lastSent {
+		if event.Time.Sub(sent) >= s.dedupWindow {
+			delete(s.lastSent, key)
+		}
+	}
+
+	key := event.key()
+	if _, ok := s.lastSent[key]; ok {
+		return true
+	}
+	s.lastSent[key] = event.Time
+	return false
+}
+
+func (s *sender) deliver(event Event) {
+	operation := func() error {
+		return s.post(event)
+	}
+	notify := func(err error, time time.Duration) {
+		log.Warnf("Error sending %s notification to %s: %v, retrying in %s", event.Type, s.endpoint, err, time)
+	}
+	err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(s.newBackOff(), s.ctx), notify)
+	if err != nil {
+		log.Errorf("Cannot send %s notification to %s: %v", event.Type, s.endpoint, err)
+	}
+}
+
+func (s *sender) post(event Event) error {
+	req, err := s.endpoint.newRequest(event)
+	if err != nil {
+		return backoff.Permanent(err)
+	}
+
+	resp, err := s.client.Do(req.WithContext(s.ctx))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+		if resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+			// the receiver rejects the payload, it would be rejected again
+			return backoff.Permanent(err)
+		}
+		return err
+	}
+	return nil
+}
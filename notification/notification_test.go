@@ -0,0 +1,182 @@
+package notification
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cenk/backoff"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedNotification struct {
+	event     Event
+	header    http.Header
+	signature string
+}
+
+func TestNotify(t *testing.T) {
+	received := make(chan receivedNotification, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		notification := receivedNotification{header: req.Header, signature: Sign("s3cr3t", body)}
+		require.NoError(t, json.Unmarshal(body, &notification.event))
+		received <- notification
+	}))
+	defer server.Close()
+
+	Setup(&types.Notifications{
+		Webhooks: types.Webhooks{
+			{URL: server.URL + "/all", Secret: "s3cr3t"},
+			{URL: server.URL + "/acme", Events: []string{ACMEFailure}},
+		},
+	})
+	defer Stop()
+
+	Notify(Event{Type: BackendDown, Message: "All the servers of the backend failed their health check", Backend: "backend1"})
+	Notify(Event{Type: ACMEFailure, Message: "Cannot obtain ACME certificate", Domains: []string{"foo.localhost"}})
+
+	notifications := map[string][]receivedNotification{}
+	for i := 0; i < 3; i++ {
+		select {
+		case notification := <-received:
+			notifications[notification.event.Type] = append(notifications[notification.event.Type], notification)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the notifications")
+		}
+	}
+
+	require.Len(t, notifications[BackendDown], 1)
+	backendDown := notifications[BackendDown][0]
+	assert.Equal(t, "backend1", backendDown.event.Backend)
+	assert.Equal(t, SeverityCritical, backendDown.event.Severity)
+	assert.NotZero(t, backendDown.event.Time)
+	assert.Equal(t, "application/json", backendDown.header.Get("Content-Type"))
+	assert.Equal(t, BackendDown, backendDown.header.Get("X-Traefik-Event"))
+	assert.Equal(t, backendDown.signature, backendDown.header.Get(SignatureHeader))
+
+	require.Len(t, notifications[ACMEFailure], 2)
+	for _, acmeFailure := range notifications[ACMEFailure] {
+		assert.Equal(t, []string{"foo.localhost"}, acmeFailure.event.Domains)
+	}
+}
+
+func TestNotifyWithoutWebhook(t *testing.T) {
+	Setup(nil)
+	Notify(Event{Type: ConfigurationError, Message: "Error loading new configuration, aborted"})
+}
+
+func TestSenderDeliver(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		statusCodes      []int
+		expectedAttempts int32
+	}{
+		{
+			desc:             "delivered",
+			statusCodes:      []int{http.StatusOK},
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "retried on server errors",
+			statusCodes:      []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusNoContent},
+			expectedAttempts: 3,
+		},
+		{
+			desc:             "not retried on client errors",
+			statusCodes:      []int{http.StatusBadRequest, http.StatusOK},
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "given up after the retries",
+			statusCodes:      []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			expectedAttempts: 3,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				attempt := atomic.AddInt32(&attempts, 1)
+				rw.WriteHeader(test.statusCodes[attempt-1])
+			}))
+			defer server.Close()
+
+			s := newSender(&webhookEndpoint{webhook: &types.Webhook{URL: server.URL}}, 0, func() backoff.BackOff {
+				return backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Millisecond), 2)
+			})
+			defer s.cancel()
+
+			s.deliver(Event{Type: ConfigurationError})
+			assert.Equal(t, test.expectedAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestSenderDuplicate(t *testing.T) {
+	start := time.Date(2018, time.January, 10, 10, 0, 0, 0, time.UTC)
+	backendDown := Event{Type: BackendDown, Backend: "backend1", Time: start}
+
+	testCases := []struct {
+		desc              string
+		dedupWindow       time.Duration
+		event             Event
+		expectedDuplicate bool
+	}{
+		{
+			desc:        "without dedup window",
+			event:       Event{Type: BackendDown, Backend: "backend1", Time: start.Add(time.Minute)},
+			dedupWindow: 0,
+		},
+		{
+			desc:              "same event during the dedup window",
+			event:             Event{Type: BackendDown, Backend: "backend1", Time: start.Add(time.Minute)},
+			dedupWindow:       time.Hour,
+			expectedDuplicate: true,
+		},
+		{
+			desc:        "same event after the dedup window",
+			event:       Event{Type: BackendDown, Backend: "backend1", Time: start.Add(time.Hour)},
+			dedupWindow: time.Hour,
+		},
+		{
+			desc:        "other backend during the dedup window",
+			event:       Event{Type: BackendDown, Backend: "backend2", Time: start.Add(time.Minute)},
+			dedupWindow: time.Hour,
+		},
+		{
+			desc:        "other event type during the dedup window",
+			event:       Event{Type: ConfigurationError, Backend: "backend1", Time: start.Add(time.Minute)},
+			dedupWindow: time.Hour,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			s := newSender(&webhookEndpoint{webhook: &types.Webhook{}}, test.dedupWindow, newBackOff)
+			defer s.cancel()
+
+			require.False(t, s.duplicate(backendDown))
+			assert.Equal(t, test.expectedDuplicate, s.duplicate(test.event))
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", Sign("key", []byte("The quick brown fox jumps over the lazy dog")))
+}
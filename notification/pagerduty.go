@@ -0,0 +1,91 @@
+package notification
+
+import (
+	"errors"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/containous/traefik/types"
+)
+
+var pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// maxPagerDutyDedupKeyLength is the maximum length of a dedup key accepted by the PagerDuty Events API.
+const maxPagerDutyDedupKeyLength = 255
+
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key"`
+	Payload     pagerDutyPayload `json:"payload"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string `json:"summary"`
+	Source        string `json:"source"`
+	Severity      string `json:"severity"`
+	Timestamp     string `json:"timestamp"`
+	Component     string `json:"component,omitempty"`
+	Class         string `json:"class"`
+	CustomDetails Event  `json:"custom_details"`
+}
+
+// pagerDutyEndpoint triggers PagerDuty alerts through the Events API v2,
+// the events about the same issue being grouped in the same alert by their dedup key.
+type pagerDutyEndpoint struct {
+	config   *types.PagerDuty
+	template *template.Template
+}
+
+func newPagerDutyEndpoint(config *types.PagerDuty) (*pagerDutyEndpoint, error) {
+	if len(config.RoutingKey) == 0 {
+		return nil, errors.New("missing PagerDuty routing key")
+	}
+	tmpl, err := newTemplate(config.Template)
+	if err != nil {
+		return nil, err
+	}
+	return &pagerDutyEndpoint{config: config, template: tmpl}, nil
+}
+
+func (p *pagerDutyEndpoint) String() string {
+	return "PagerDuty"
+}
+
+func (p *pagerDutyEndpoint) events() []string {
+	return p.config.Events
+}
+
+func (p *pagerDutyEndpoint) newRequest(event Event) (*http.Request, error) {
+	summary, err := render(p.template, event)
+	if err != nil {
+		return nil, err
+	}
+
+	dedupKey := event.key()
+	if len(dedupKey) > maxPagerDutyDedupKeyLength {
+		dedupKey = dedupKey[:maxPagerDutyDedupKeyLength]
+	}
+
+	component := event.Backend
+	if len(component) == 0 {
+		component = event.Provider
+	}
+
+	req, _, err := newJSONRequest(pagerDutyEventsURL, pagerDutyEvent{
+		RoutingKey:  p.config.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    dedupKey,
+		Payload: pagerDutyPayload{
+			Summary:       summary,
+			Source:        event.Hostname,
+			Severity:      event.Severity,
+			Timestamp:     event.Time.Format(time.RFC3339),
+			Component:     component,
+			Class:         event.Type,
+			CustomDetails: event,
+		},
+	})
+	return req, err
+}
@@ -0,0 +1,112 @@
+package notification
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagerDutyEndpoint(t *testing.T) {
+	eventTime := time.Date(2018, time.January, 10, 10, 22, 13, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		event    Event
+		expected pagerDutyEvent
+	}{
+		{
+			desc: "backend down",
+			event: Event{
+				Type:     BackendDown,
+				Severity: SeverityCritical,
+				Time:     eventTime,
+				Hostname: "traefik-1",
+				Message:  "All the servers of the backend failed their health check",
+				Backend:  "backend1",
+			},
+			expected: pagerDutyEvent{
+				RoutingKey:  "routing-key",
+				EventAction: "trigger",
+				DedupKey:    "traefik/backend.down/traefik-1//backend1/",
+				Payload: pagerDutyPayload{
+					Summary:   "[critical] All the servers of the backend failed their health check on traefik-1, backend: backend1",
+					Source:    "traefik-1",
+					Severity:  SeverityCritical,
+					Timestamp: "2018-01-10T10:22:13Z",
+					Component: "backend1",
+					Class:     BackendDown,
+				},
+			},
+		},
+		{
+			desc: "provider disconnected",
+			event: Event{
+				Type:     ProviderDisconnected,
+				Severity: SeverityError,
+				Time:     eventTime,
+				Hostname: "traefik-1",
+				Message:  "Provider connection error",
+				Provider: "docker",
+				Error:    "connection refused",
+			},
+			expected: pagerDutyEvent{
+				RoutingKey:  "routing-key",
+				EventAction: "trigger",
+				DedupKey:    "traefik/provider.disconnected/traefik-1/docker//",
+				Payload: pagerDutyPayload{
+					Summary:   "[error] Provider connection error on traefik-1, provider: docker: connection refused",
+					Source:    "traefik-1",
+					Severity:  SeverityError,
+					Timestamp: "2018-01-10T10:22:13Z",
+					Component: "docker",
+					Class:     ProviderDisconnected,
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			pagerDuty, err := newPagerDutyEndpoint(&types.PagerDuty{RoutingKey: "routing-key"})
+			require.NoError(t, err)
+
+			req, err := pagerDuty.newRequest(test.event)
+			require.NoError(t, err)
+			assert.Equal(t, pagerDutyEventsURL, req.URL.String())
+
+			body, err := ioutil.ReadAll(req.Body)
+			require.NoError(t, err)
+			event := pagerDutyEvent{}
+			require.NoError(t, json.Unmarshal(body, &event))
+
+			test.expected.Payload.CustomDetails = test.event
+			assert.Equal(t, test.expected, event)
+		})
+	}
+}
+
+func TestPagerDutyEndpointDedupKeyLength(t *testing.T) {
+	pagerDuty, err := newPagerDutyEndpoint(&types.PagerDuty{RoutingKey: "routing-key"})
+	require.NoError(t, err)
+
+	req, err := pagerDuty.newRequest(Event{Type: ACMEFailure, Domains: []string{strings.Repeat("a", 300) + ".localhost"}})
+	require.NoError(t, err)
+
+	event := pagerDutyEvent{}
+	require.NoError(t, json.NewDecoder(req.Body).Decode(&event))
+	assert.Len(t, event.DedupKey, maxPagerDutyDedupKeyLength)
+}
+
+func TestNewPagerDutyEndpointWithoutRoutingKey(t *testing.T) {
+	_, err := newPagerDutyEndpoint(&types.PagerDuty{})
+	assert.EqualError(t, err, "missing PagerDuty routing key")
+}
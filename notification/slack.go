@@ -0,0 +1,76 @@
+package notification
+
+import (
+	"errors"
+	"net/http"
+	"text/template"
+
+	"github.com/containous/traefik/types"
+)
+
+var slackColors = map[string]string{
+	SeverityCritical: "danger",
+	SeverityError:    "danger",
+	SeverityWarning:  "warning",
+	SeverityInfo:     "good",
+}
+
+type slackMessage struct {
+	Channel     string            `json:"channel,omitempty"`
+	Username    string            `json:"username,omitempty"`
+	Text        string            `json:"text,omitempty"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+type slackAttachment struct {
+	Fallback string `json:"fallback"`
+	Color    string `json:"color"`
+	Text     string `json:"text"`
+	Footer   string `json:"footer,omitempty"`
+	Ts       int64  `json:"ts"`
+}
+
+// slackEndpoint posts the events as messages to a Slack incoming webhook, colored according to their severity.
+type slackEndpoint struct {
+	config   *types.Slack
+	template *template.Template
+}
+
+func newSlackEndpoint(config *types.Slack) (*slackEndpoint, error) {
+	if len(config.WebhookURL) == 0 {
+		return nil, errors.New("missing Slack webhook URL")
+	}
+	tmpl, err := newTemplate(config.Template)
+	if err != nil {
+		return nil, err
+	}
+	return &slackEndpoint{config: config, template: tmpl}, nil
+}
+
+func (s *slackEndpoint) String() string {
+	return "Slack"
+}
+
+func (s *slackEndpoint) events() []string {
+	return s.config.Events
+}
+
+func (s *slackEndpoint) newRequest(event Event) (*http.Request, error) {
+	text, err := render(s.template, event)
+	if err != nil {
+		return nil, err
+	}
+
+	req, _, err := newJSONRequest(s.config.WebhookURL, slackMessage{
+		Channel:  s.config.Channel,
+		Username: s.config.Username,
+		Attachments: []slackAttachment{{
+			Fallback: text,
+			Color:    slackColors[event.Severity],
+			Text:     text,
+			Footer:   event.Type,
+			Ts:       event.Time.Unix(),
+		}},
+	})
+	return req, err
+}
@@ -0,0 +1,94 @@
+package notification
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackEndpoint(t *testing.T) {
+	event := Event{
+		Type:     ACMEFailure,
+		Severity: SeverityError,
+		Time:     time.Date(2018, time.January, 10, 10, 22, 13, 0, time.UTC),
+		Hostname: "traefik-1",
+		Message:  "Cannot obtain ACME certificate",
+		Domains:  []string{"foo.localhost", "bar.localhost"},
+		Error:    "rate limited",
+	}
+
+	testCases := []struct {
+		desc          string
+		config        *types.Slack
+		expected      slackMessage
+		expectedError bool
+	}{
+		{
+			desc:   "default template",
+			config: &types.Slack{WebhookURL: "https://hooks.slack.com/services/T/B/X", Channel: "#ops"},
+			expected: slackMessage{
+				Channel: "#ops",
+				Attachments: []slackAttachment{{
+					Fallback: "[error] Cannot obtain ACME certificate on traefik-1, domains: foo.localhost, bar.localhost: rate limited",
+					Color:    "danger",
+					Text:     "[error] Cannot obtain ACME certificate on traefik-1, domains: foo.localhost, bar.localhost: rate limited",
+					Footer:   ACMEFailure,
+					Ts:       1515579733,
+				}},
+			},
+		},
+		{
+			desc:   "custom template",
+			config: &types.Slack{WebhookURL: "https://hooks.slack.com/services/T/B/X", Username: "traefik", Template: "{{.Type}} on {{.Hostname}}"},
+			expected: slackMessage{
+				Username: "traefik",
+				Attachments: []slackAttachment{{
+					Fallback: "acme.failure on traefik-1",
+					Color:    "danger",
+					Text:     "acme.failure on traefik-1",
+					Footer:   ACMEFailure,
+					Ts:       1515579733,
+				}},
+			},
+		},
+		{
+			desc:          "missing webhook URL",
+			config:        &types.Slack{},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid template",
+			config:        &types.Slack{WebhookURL: "https://hooks.slack.com/services/T/B/X", Template: "{{.Type"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			slack, err := newSlackEndpoint(test.config)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			req, err := slack.newRequest(event)
+			require.NoError(t, err)
+			assert.Equal(t, test.config.WebhookURL, req.URL.String())
+
+			body, err := ioutil.ReadAll(req.Body)
+			require.NoError(t, err)
+			message := slackMessage{}
+			require.NoError(t, json.Unmarshal(body, &message))
+			assert.Equal(t, test.expected, message)
+		})
+	}
+}
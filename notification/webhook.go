@@ -0,0 +1,45 @@
+package notification
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/containous/traefik/types"
+)
+
+// SignatureHeader holds the HMAC-SHA256 signature of the payload, computed with the secret of the webhook.
+const SignatureHeader = "X-Traefik-Signature"
+
+// Sign returns the value of the signature header of the payload.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookEndpoint posts the events as they are, signed with the secret of the webhook.
+type webhookEndpoint struct {
+	webhook *types.Webhook
+}
+
+func (w *webhookEndpoint) String() string {
+	return "webhook " + w.webhook.URL
+}
+
+func (w *webhookEndpoint) events() []string {
+	return w.webhook.Events
+}
+
+func (w *webhookEndpoint) newRequest(event Event) (*http.Request, error) {
+	req, payload, err := newJSONRequest(w.webhook.URL, event)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Traefik-Event", event.Type)
+	if len(w.webhook.Secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(w.webhook.Secret, payload))
+	}
+	return req, nil
+}
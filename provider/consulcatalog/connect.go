@@ -0,0 +1,258 @@
+package consulcatalog
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/hashicorp/consul/api"
+)
+
+// suffixConnect is the tag enabling or disabling Consul Connect for a service.
+const suffixConnect = "consulcatalog.connect"
+
+// connectCertificateName is the name of the leaf certificate of Traefik in the client certificates.
+const connectCertificateName = "consulcatalog-connect"
+
+// The vendored Consul client predates Connect,
+// so its agent endpoints are queried with the raw client.
+
+type connectCARoots struct {
+	TrustDomain string
+	Roots       []struct {
+		RootCert string
+	}
+}
+
+type connectLeafCert struct {
+	CertPEM       string
+	PrivateKeyPEM string
+}
+
+// connectServiceEntry is a Connect capable instance: a native service, or the sidecar proxy of a service.
+type connectServiceEntry struct {
+	Node    *api.Node
+	Service *struct {
+		ID      string
+		Address string
+		Port    int
+		Proxy   *struct {
+			DestinationServiceID string
+		}
+	}
+	Checks api.HealthChecks
+}
+
+// connectCerts holds the certificates identifying Traefik in the Connect service mesh,
+// the leaf certificate being kept in the client certificates, out of the configuration.
+type connectCerts struct {
+	lock        sync.RWMutex
+	trustDomain string
+	rootCAs     traefikTls.RootCAs
+	hasLeaf     bool
+}
+
+func (c *connectCerts) setRoots(roots connectCARoots) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.trustDomain = roots.TrustDomain
+	c.rootCAs = nil
+	for _, root := range roots.Roots {
+		c.rootCAs = append(c.rootCAs, traefikTls.FileOrContent(root.RootCert))
+	}
+}
+
+func (c *connectCerts) setLeaf(leaf connectLeafCert) error {
+	certificate, err := tls.X509KeyPair([]byte(leaf.CertPEM), []byte(leaf.PrivateKeyPEM))
+	if err != nil {
+		return fmt.Errorf("invalid Connect leaf certificate: %v", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	traefikTls.GetClientCertificates().Set(connectCertificateName, &certificate)
+	c.hasLeaf = true
+	return nil
+}
+
+// backendTLS returns the TLS configuration reaching the instances of a service in the given datacenters,
+// which certificates hold their SPIFFE ID instead of a host name.
+func (c *connectCerts) backendTLS(service string, datacenters []string) *types.BackendTLS {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if !c.hasLeaf {
+		return nil
+	}
+
+	var peerURIs []string
+	for _, datacenter := range datacenters {
+		spiffeID := url.URL{
+			Scheme: "spiffe",
+			Host:   c.trustDomain,
+			Path:   fmt.Sprintf("/ns/default/dc/%s/svc/%s", datacenter, service),
+		}
+		peerURIs = append(peerURIs, spiffeID.String())
+	}
+
+	return &types.BackendTLS{
+		RootCAs:         c.rootCAs,
+		CertificateName: connectCertificateName,
+		PeerURIs:        peerURIs,
+	}
+}
+
+func (p *Provider) isConnectEnabled(tags []string) bool {
+	return p.ConnectAware && p.getBoolAttribute(suffixConnect, tags, p.ConnectByDefault)
+}
+
+// fetchConnectCerts gets the Connect CA roots and the leaf certificate of Traefik,
+// and returns the indexes from which their changes are watched.
+func (p *Provider) fetchConnectCerts() (uint64, uint64, error) {
+	var roots connectCARoots
+	rootsMeta, err := p.client.Raw().Query("/v1/agent/connect/ca/roots", &roots, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get the Connect CA roots: %v", err)
+	}
+	p.connectCerts.setRoots(roots)
+
+	var leaf connectLeafCert
+	leafMeta, err := p.client.Raw().Query("/v1/agent/connect/ca/leaf/"+p.ServiceName, &leaf, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get the Connect leaf certificate of %s: %v", p.ServiceName, err)
+	}
+	if err := p.connectCerts.setLeaf(leaf); err != nil {
+		return 0, 0, err
+	}
+
+	return rootsMeta.LastIndex, leafMeta.LastIndex, nil
+}
+
+// watchConnectCerts follows the rotations of the Connect CA roots and of the leaf certificate of Traefik.
+func (p *Provider) watchConnectCerts(stopCh <-chan struct{}, watchCh chan<- map[string][]string, errorCh chan<- error, rootsIndex, leafIndex uint64) {
+	p.watchConnectEndpoint(stopCh, watchCh, errorCh, "/v1/agent/connect/ca/roots", rootsIndex, func() (interface{}, func()) {
+		roots := &connectCARoots{}
+		return roots, func() { p.connectCerts.setRoots(*roots) }
+	})
+	p.watchConnectEndpoint(stopCh, watchCh, errorCh, "/v1/agent/connect/ca/leaf/"+p.ServiceName, leafIndex, func() (interface{}, func()) {
+		leaf := &connectLeafCert{}
+		return leaf, func() {
+			if err := p.connectCerts.setLeaf(*leaf); err != nil {
+				log.Errorf("Keeping the previous Connect leaf certificate: %v", err)
+			}
+		}
+	})
+}
+
+// watchConnectEndpoint blocks on an agent endpoint, storing each new response and notifying the services,
+// so that the configuration is built again with the new certificates.
+func (p *Provider) watchConnectEndpoint(stopCh <-chan struct{}, watchCh chan<- map[string][]string, errorCh chan<- error, endpoint string, index uint64, newResponse func() (interface{}, func())) {
+	safe.Go(func() {
+		options := &api.QueryOptions{WaitTime: DefaultWatchWaitTime, WaitIndex: index}
+
+		for {
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+
+			out, store := newResponse()
+			meta, err := p.client.Raw().Query(endpoint, out, options)
+			if err != nil {
+				log.Errorf("Failed to watch %s: %v", endpoint, err)
+				errorCh <- err
+				return
+			}
+
+			if options.WaitIndex == meta.LastIndex {
+				continue
+			}
+			options.WaitIndex = meta.LastIndex
+
+			log.Debugf("Connect certificates changed: %s", endpoint)
+			store()
+
+			data, err := p.getServicesIndex()
+			if err != nil {
+				errorCh <- err
+				return
+			}
+			watchCh <- data
+		}
+	})
+}
+
+// connectNodes returns the nodes reaching the given instances of a service through Connect:
+// a native instance is reached directly, the other ones through their healthy sidecar proxy.
+func (p *Provider) connectNodes(service string, nodes []*api.ServiceEntry) ([]*api.ServiceEntry, error) {
+	endpoints := make(map[string]*connectServiceEntry)
+	for _, datacenter := range p.getDatacenters() {
+		var entries []*connectServiceEntry
+		_, err := p.client.Raw().Query("/v1/health/connect/"+service, &entries, &api.QueryOptions{Datacenter: datacenter})
+		if err != nil {
+			log.WithError(err).Errorf("Failed to fetch Connect details of %s", service)
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.Node == nil || entry.Service == nil || entry.Checks.AggregatedStatus() != api.HealthPassing {
+				continue
+			}
+
+			instanceID := entry.Service.ID
+			if entry.Service.Proxy != nil && len(entry.Service.Proxy.DestinationServiceID) > 0 {
+				instanceID = entry.Service.Proxy.DestinationServiceID
+			}
+			endpoints[connectInstanceKey(entry.Node, instanceID)] = entry
+		}
+	}
+
+	var connectNodes []*api.ServiceEntry
+	for _, node := range nodes {
+		endpoint, ok := endpoints[connectInstanceKey(node.Node, node.Service.ID)]
+		if !ok {
+			log.Debugf("Filtering Consul service %s instance %s without healthy Connect endpoint", service, node.Service.ID)
+			continue
+		}
+
+		// The instance keeps its name and tags, but is reached at the address of its Connect endpoint.
+		connectService := *node.Service
+		connectService.Address = endpoint.Service.Address
+		connectService.Port = endpoint.Service.Port
+
+		connectNode := *node
+		connectNode.Service = &connectService
+		connectNodes = append(connectNodes, &connectNode)
+	}
+	return connectNodes, nil
+}
+
+func connectInstanceKey(node *api.Node, serviceID string) string {
+	if node == nil {
+		return serviceID
+	}
+	return node.Datacenter + "/" + node.Node + "/" + serviceID
+}
+
+// getNodesDatacenters returns the sorted datacenters of the nodes.
+func getNodesDatacenters(nodes []*api.ServiceEntry) []string {
+	visited := make(map[string]bool)
+	var datacenters []string
+	for _, node := range nodes {
+		if node.Node != nil && !visited[node.Node.Datacenter] {
+			visited[node.Node.Datacenter] = true
+			datacenters = append(datacenters, node.Node.Datacenter)
+		}
+	}
+	sort.Strings(datacenters)
+	return datacenters
+}
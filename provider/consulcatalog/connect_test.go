@@ -0,0 +1,224 @@
+package consulcatalog
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderIsConnectEnabled(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		connectAware     bool
+		connectByDefault bool
+		tags             []string
+		expected         bool
+	}{
+		{
+			desc:     "Connect disabled",
+			tags:     []string{"traefik.consulcatalog.connect=true"},
+			expected: false,
+		},
+		{
+			desc:         "not Connect by default",
+			connectAware: true,
+			expected:     false,
+		},
+		{
+			desc:         "Connect enabled by tag",
+			connectAware: true,
+			tags:         []string{"traefik.consulcatalog.connect=true"},
+			expected:     true,
+		},
+		{
+			desc:             "Connect by default",
+			connectAware:     true,
+			connectByDefault: true,
+			expected:         true,
+		},
+		{
+			desc:             "Connect disabled by tag",
+			connectAware:     true,
+			connectByDefault: true,
+			tags:             []string{"traefik.consulcatalog.connect=false"},
+			expected:         false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &Provider{
+				Prefix:           "traefik",
+				ConnectAware:     test.connectAware,
+				ConnectByDefault: test.connectByDefault,
+			}
+
+			assert.Equal(t, test.expected, provider.isConnectEnabled(test.tags))
+		})
+	}
+}
+
+// generateLeafPEM generates a self-signed certificate and its key, PEM encoded as in the Connect leaf certificates.
+func generateLeafPEM(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
+
+func TestProviderFetchConnectCerts(t *testing.T) {
+	certPEM, keyPEM := generateLeafPEM(t)
+	leaf, err := json.Marshal(connectLeafCert{CertPEM: certPEM, PrivateKeyPEM: keyPEM})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/agent/connect/ca/roots":
+			rw.Header().Set("X-Consul-Index", "12")
+			rw.Write([]byte(`{"TrustDomain":"mesh.consul","Roots":[{"RootCert":"root1"},{"RootCert":"root2"}]}`))
+		case "/v1/agent/connect/ca/leaf/traefik":
+			rw.Header().Set("X-Consul-Index", "34")
+			rw.Write(leaf)
+		default:
+			http.NotFound(rw, req)
+		}
+	}))
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: strings.TrimPrefix(server.URL, "http://")})
+	require.NoError(t, err)
+
+	provider := &Provider{
+		ServiceName:  "traefik",
+		client:       client,
+		connectCerts: &connectCerts{},
+	}
+
+	rootsIndex, leafIndex, err := provider.fetchConnectCerts()
+	require.NoError(t, err)
+	assert.EqualValues(t, 12, rootsIndex)
+	assert.EqualValues(t, 34, leafIndex)
+
+	expected := &types.BackendTLS{
+		RootCAs:         traefikTls.RootCAs{"root1", "root2"},
+		CertificateName: connectCertificateName,
+		PeerURIs: []string{
+			"spiffe://mesh.consul/ns/default/dc/dc1/svc/web",
+			"spiffe://mesh.consul/ns/default/dc/dc2/svc/web",
+		},
+	}
+	assert.Equal(t, expected, provider.connectCerts.backendTLS("web", []string{"dc1", "dc2"}))
+
+	// the private key is kept out of the configuration, in the client certificates
+	certificate, ok := traefikTls.GetClientCertificates().Get(connectCertificateName)
+	require.True(t, ok)
+	block, _ := pem.Decode([]byte(certPEM))
+	assert.Equal(t, block.Bytes, certificate.Certificate[0])
+}
+
+func TestConnectCertsSetInvalidLeaf(t *testing.T) {
+	certs := &connectCerts{}
+	assert.Error(t, certs.setLeaf(connectLeafCert{CertPEM: "cert", PrivateKeyPEM: "key"}))
+	assert.Nil(t, certs.backendTLS("web", []string{"dc1"}))
+}
+
+func TestProviderConnectNodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v1/health/connect/web" {
+			http.NotFound(rw, req)
+			return
+		}
+
+		rw.Write([]byte(`[
+  {
+    "Node": {"Node": "node1", "Address": "10.0.0.1", "Datacenter": "dc1"},
+    "Service": {"ID": "web-1-sidecar-proxy", "Kind": "connect-proxy", "Port": 21000, "Proxy": {"DestinationServiceID": "web-1"}},
+    "Checks": [{"Status": "passing"}]
+  },
+  {
+    "Node": {"Node": "node2", "Address": "10.0.0.2", "Datacenter": "dc1"},
+    "Service": {"ID": "web-2", "Address": "10.0.0.20", "Port": 8443, "Connect": {"Native": true}},
+    "Checks": [{"Status": "passing"}]
+  },
+  {
+    "Node": {"Node": "node3", "Address": "10.0.0.3", "Datacenter": "dc1"},
+    "Service": {"ID": "web-3-sidecar-proxy", "Kind": "connect-proxy", "Port": 21000, "Proxy": {"DestinationServiceID": "web-3"}},
+    "Checks": [{"Status": "critical"}]
+  }
+]`))
+	}))
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: strings.TrimPrefix(server.URL, "http://")})
+	require.NoError(t, err)
+
+	provider := &Provider{client: client}
+
+	nodes := []*api.ServiceEntry{
+		{
+			Node:    &api.Node{Node: "node1", Address: "10.0.0.1", Datacenter: "dc1"},
+			Service: &api.AgentService{ID: "web-1", Service: "web", Port: 80, Tags: []string{"traefik.weight=2"}},
+		},
+		{
+			Node:    &api.Node{Node: "node2", Address: "10.0.0.2", Datacenter: "dc1"},
+			Service: &api.AgentService{ID: "web-2", Service: "web", Address: "10.0.0.20", Port: 8443},
+		},
+		{
+			Node:    &api.Node{Node: "node3", Address: "10.0.0.3", Datacenter: "dc1"},
+			Service: &api.AgentService{ID: "web-3", Service: "web", Port: 80},
+		},
+		{
+			Node:    &api.Node{Node: "node4", Address: "10.0.0.4", Datacenter: "dc1"},
+			Service: &api.AgentService{ID: "web-4", Service: "web", Port: 80},
+		},
+	}
+
+	actual, err := provider.connectNodes("web", nodes)
+	require.NoError(t, err)
+
+	expected := []*api.ServiceEntry{
+		{
+			Node:    &api.Node{Node: "node1", Address: "10.0.0.1", Datacenter: "dc1"},
+			Service: &api.AgentService{ID: "web-1", Service: "web", Port: 21000, Tags: []string{"traefik.weight=2"}},
+		},
+		{
+			Node:    &api.Node{Node: "node2", Address: "10.0.0.2", Datacenter: "dc1"},
+			Service: &api.AgentService{ID: "web-2", Service: "web", Address: "10.0.0.20", Port: 8443},
+		},
+	}
+	assert.Equal(t, expected, actual)
+
+	// the instances keep their own entries
+	assert.Equal(t, 80, nodes[0].Service.Port)
+}
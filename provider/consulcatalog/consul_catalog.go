@@ -2,6 +2,7 @@ package consulcatalog
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"text/template"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/cenk/backoff"
 	"github.com/containous/traefik/job"
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
 	"github.com/containous/traefik/provider"
 	"github.com/containous/traefik/provider/label"
 	"github.com/containous/traefik/safe"
@@ -27,13 +29,20 @@ var _ provider.Provider = (*Provider)(nil)
 // Provider holds configurations of the Consul catalog provider.
 type Provider struct {
 	provider.BaseProvider `mapstructure:",squash" export:"true"`
-	Endpoint              string           `description:"Consul server endpoint"`
-	Domain                string           `description:"Default domain used"`
-	ExposedByDefault      bool             `description:"Expose Consul services by default" export:"true"`
-	Prefix                string           `description:"Prefix used for Consul catalog tags" export:"true"`
-	FrontEndRule          string           `description:"Frontend rule used for Consul services" export:"true"`
-	TLS                   *types.ClientTLS `description:"Enable TLS support" export:"true"`
+	Endpoint              string               `description:"Consul server endpoint"`
+	Domain                string               `description:"Default domain used"`
+	ExposedByDefault      bool                 `description:"Expose Consul services by default" export:"true"`
+	Prefix                string               `description:"Prefix used for Consul catalog tags" export:"true"`
+	FrontEndRule          string               `description:"Frontend rule used for Consul services" export:"true"`
+	TLS                   *types.ClientTLS     `description:"Enable TLS support" export:"true"`
+	Proxy                 *types.OutboundProxy `description:"Outbound proxy used to reach Consul" export:"true"`
+	Datacenters           Datacenters          `description:"Datacenters of the services, the one of the Consul agent by default" export:"true"`
+	PreferredDatacenter   string               `description:"Datacenter which instances of a service are used alone while some are healthy" export:"true"`
+	ConnectAware          bool                 `description:"Enable Consul Connect support" export:"true"`
+	ConnectByDefault      bool                 `description:"Consider every service as Connect capable by default" export:"true"`
+	ServiceName           string               `description:"Name of the Traefik service in Consul, identifying it in the Connect intentions" export:"true"`
 	client                *api.Client
+	connectCerts          *connectCerts
 	frontEndRuleTemplate  *template.Template
 }
 
@@ -49,6 +58,9 @@ type Service struct {
 type serviceUpdate struct {
 	ServiceName string
 	Attributes  []string
+	// Connect tells whether the instances are reached through Consul Connect, in the given datacenters.
+	Connect     bool
+	Datacenters []string
 }
 
 type catalogUpdate struct {
@@ -88,6 +100,16 @@ func (a nodeSorter) Less(i int, j int) bool {
 // Provide allows the consul catalog provider to provide configurations to traefik
 // using the given configuration channel.
 func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *safe.Pool, constraints types.Constraints) error {
+	if len(p.PreferredDatacenter) > 0 && len(p.Datacenters) > 0 && !fun.In(p.PreferredDatacenter, []string(p.Datacenters)) {
+		return fmt.Errorf("preferred datacenter %q is not one of the watched datacenters %v", p.PreferredDatacenter, p.Datacenters)
+	}
+	if p.ConnectAware {
+		if len(p.ServiceName) == 0 {
+			return errors.New("a service name is required by Consul Connect")
+		}
+		p.connectCerts = &connectCerts{}
+	}
+
 	client, err := p.createClient()
 	if err != nil {
 		return err
@@ -100,6 +122,7 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 	pool.Go(func(stop chan bool) {
 		notify := func(err error, time time.Duration) {
 			log.Errorf("Consul connection error %+v, retrying in %s", err, time)
+			notification.Notify(notification.Event{Type: notification.ProviderDisconnected, Message: "Provider connection error", Provider: "consul_catalog", Error: err.Error()})
 		}
 		operation := func() error {
 			return p.watch(configurationChan, stop)
@@ -125,6 +148,10 @@ func (p *Provider) createClient() (*api.Client, error) {
 		config.Transport.TLSClientConfig = tlsConfig
 	}
 
+	if err := p.Proxy.ConfigureTransport(config.Transport); err != nil {
+		return nil, err
+	}
+
 	client, err := api.NewClient(config)
 	if err != nil {
 		return nil, err
@@ -138,12 +165,22 @@ func (p *Provider) watch(configurationChan chan<- types.ConfigMessage, stop chan
 	watchCh := make(chan map[string][]string)
 	errorCh := make(chan error)
 
-	p.watchHealthState(stopCh, watchCh, errorCh)
-	p.watchCatalogServices(stopCh, watchCh, errorCh)
+	for _, datacenter := range p.getDatacenters() {
+		p.watchHealthState(stopCh, watchCh, errorCh, datacenter)
+		p.watchCatalogServices(stopCh, watchCh, errorCh, datacenter)
+	}
 
 	defer close(stopCh)
 	defer close(watchCh)
 
+	if p.ConnectAware {
+		rootsIndex, leafIndex, err := p.fetchConnectCerts()
+		if err != nil {
+			return err
+		}
+		p.watchConnectCerts(stopCh, watchCh, errorCh, rootsIndex, leafIndex)
+	}
+
 	for {
 		select {
 		case <-stop:
@@ -153,6 +190,14 @@ func (p *Provider) watch(configurationChan chan<- types.ConfigMessage, stop chan
 				return errors.New("consul service list nil")
 			}
 			log.Debug("List of services changed")
+			if len(p.Datacenters) > 1 {
+				// The index only lists the services of the datacenter which changed.
+				var err error
+				index, err = p.getServicesIndex()
+				if err != nil {
+					return err
+				}
+			}
 			nodes, err := p.getNodes(index)
 			if err != nil {
 				return err
@@ -168,14 +213,38 @@ func (p *Provider) watch(configurationChan chan<- types.ConfigMessage, stop chan
 	}
 }
 
-func (p *Provider) watchCatalogServices(stopCh <-chan struct{}, watchCh chan<- map[string][]string, errorCh chan<- error) {
+// getDatacenters returns the watched datacenters, the empty one being the datacenter of the Consul agent.
+func (p *Provider) getDatacenters() []string {
+	if len(p.Datacenters) == 0 {
+		return []string{""}
+	}
+	return p.Datacenters
+}
+
+// getServicesIndex returns the services of all the watched datacenters with their tags.
+func (p *Provider) getServicesIndex() (map[string][]string, error) {
+	index := make(map[string][]string)
+	for _, datacenter := range p.getDatacenters() {
+		data, _, err := p.client.Catalog().Services(&api.QueryOptions{Datacenter: datacenter})
+		if err != nil {
+			log.Errorf("Failed to list services of datacenter %q: %v", datacenter, err)
+			return nil, err
+		}
+		for name, tags := range data {
+			index[name] = append(index[name], tags...)
+		}
+	}
+	return index, nil
+}
+
+func (p *Provider) watchCatalogServices(stopCh <-chan struct{}, watchCh chan<- map[string][]string, errorCh chan<- error, datacenter string) {
 	catalog := p.client.Catalog()
 
 	safe.Go(func() {
 		// variable to hold previous state
 		var flashback map[string]Service
 
-		options := &api.QueryOptions{WaitTime: DefaultWatchWaitTime}
+		options := &api.QueryOptions{WaitTime: DefaultWatchWaitTime, Datacenter: datacenter}
 
 		for {
 			select {
@@ -200,7 +269,7 @@ func (p *Provider) watchCatalogServices(stopCh <-chan struct{}, watchCh chan<- m
 			if data != nil {
 				current := make(map[string]Service)
 				for key, value := range data {
-					nodes, _, err := catalog.Service(key, "", &api.QueryOptions{})
+					nodes, _, err := catalog.Service(key, "", &api.QueryOptions{Datacenter: datacenter})
 					if err != nil {
 						log.Errorf("Failed to get detail of service %s: %v", key, err)
 						errorCh <- err
@@ -239,7 +308,7 @@ func (p *Provider) watchCatalogServices(stopCh <-chan struct{}, watchCh chan<- m
 	})
 }
 
-func (p *Provider) watchHealthState(stopCh <-chan struct{}, watchCh chan<- map[string][]string, errorCh chan<- error) {
+func (p *Provider) watchHealthState(stopCh <-chan struct{}, watchCh chan<- map[string][]string, errorCh chan<- error, datacenter string) {
 	health := p.client.Health()
 	catalog := p.client.Catalog()
 
@@ -247,7 +316,7 @@ func (p *Provider) watchHealthState(stopCh <-chan struct{}, watchCh chan<- map[s
 		// variable to hold previous state
 		var flashback []string
 
-		options := &api.QueryOptions{WaitTime: DefaultWatchWaitTime}
+		options := &api.QueryOptions{WaitTime: DefaultWatchWaitTime, Datacenter: datacenter}
 
 		for {
 			select {
@@ -281,7 +350,7 @@ func (p *Provider) watchHealthState(stopCh <-chan struct{}, watchCh chan<- map[s
 			options.WaitIndex = meta.LastIndex
 
 			// The response should be unified with watchCatalogServices
-			data, _, err := catalog.Services(&api.QueryOptions{})
+			data, _, err := catalog.Services(&api.QueryOptions{Datacenter: datacenter})
 			if err != nil {
 				log.Errorf("Failed to list services: %v", err)
 				errorCh <- err
@@ -420,33 +489,70 @@ func getServiceAddresses(services []*api.CatalogService) []string {
 
 func (p *Provider) healthyNodes(service string) (catalogUpdate, error) {
 	health := p.client.Health()
-	data, _, err := health.Service(service, "", true, &api.QueryOptions{})
-	if err != nil {
-		log.WithError(err).Errorf("Failed to fetch details of %s", service)
-		return catalogUpdate{}, err
-	}
 
-	nodes := fun.Filter(func(node *api.ServiceEntry) bool {
-		return p.nodeFilter(service, node)
-	}, data).([]*api.ServiceEntry)
+	var nodes []*api.ServiceEntry
+	for _, datacenter := range p.getDatacenters() {
+		data, _, err := health.Service(service, "", true, &api.QueryOptions{Datacenter: datacenter})
+		if err != nil {
+			log.WithError(err).Errorf("Failed to fetch details of %s", service)
+			return catalogUpdate{}, err
+		}
 
-	// Merge tags of nodes matching constraints, in a single slice.
-	tags := fun.Foldl(func(node *api.ServiceEntry, set []string) []string {
-		return fun.Keys(fun.Union(
-			fun.Set(set),
-			fun.Set(node.Service.Tags),
-		).(map[string]bool)).([]string)
-	}, []string{}, nodes).([]string)
+		dcNodes := fun.Filter(func(node *api.ServiceEntry) bool {
+			return p.nodeFilter(service, node)
+		}, data).([]*api.ServiceEntry)
+
+		nodes = append(nodes, dcNodes...)
+	}
+
+	connect := p.isConnectEnabled(mergeTags(nodes))
+	if connect {
+		var err error
+		nodes, err = p.connectNodes(service, nodes)
+		if err != nil {
+			return catalogUpdate{}, err
+		}
+	}
+	nodes = p.preferDatacenter(nodes)
 
 	return catalogUpdate{
 		Service: &serviceUpdate{
 			ServiceName: service,
-			Attributes:  tags,
+			Attributes:  mergeTags(nodes),
+			Connect:     connect,
+			Datacenters: getNodesDatacenters(nodes),
 		},
 		Nodes: nodes,
 	}, nil
 }
 
+// mergeTags merges the tags of nodes matching constraints, in a single slice.
+func mergeTags(nodes []*api.ServiceEntry) []string {
+	return fun.Foldl(func(node *api.ServiceEntry, set []string) []string {
+		return fun.Keys(fun.Union(
+			fun.Set(set),
+			fun.Set(node.Service.Tags),
+		).(map[string]bool)).([]string)
+	}, []string{}, nodes).([]string)
+}
+
+// preferDatacenter keeps only the nodes of the preferred datacenter when it has some,
+// the nodes of the other datacenters being used when the service has no healthy instance in it.
+func (p *Provider) preferDatacenter(nodes []*api.ServiceEntry) []*api.ServiceEntry {
+	if len(p.PreferredDatacenter) == 0 {
+		return nodes
+	}
+
+	preferred := fun.Filter(func(node *api.ServiceEntry) bool {
+		return node.Node != nil && node.Node.Datacenter == p.PreferredDatacenter
+	}, nodes).([]*api.ServiceEntry)
+
+	if len(preferred) == 0 {
+		return nodes
+	}
+	return preferred
+}
+
 func (p *Provider) nodeFilter(service string, node *api.ServiceEntry) bool {
 	// Filter disabled application.
 	if !p.isServiceEnabled(node) {
@@ -41,6 +41,7 @@ func (p *Provider) buildConfiguration(catalog []catalogUpdate) *types.Configurat
 		"getMaxConn":              p.getMaxConn,
 		"getHealthCheck":          p.getHealthCheck,
 		"getBuffering":            p.getBuffering,
+		"getConnectTLS":           p.getConnectTLS,
 
 		// Frontend functions
 		"getFrontendRule":         p.getFrontendRule,
@@ -61,12 +62,17 @@ func (p *Provider) buildConfiguration(catalog []catalogUpdate) *types.Configurat
 
 	var allNodes []*api.ServiceEntry
 	var services []*serviceUpdate
+	connectBackends := make(map[string]bool)
 	for _, info := range catalog {
 		if len(info.Nodes) > 0 {
 			services = append(services, info.Service)
 			allNodes = append(allNodes, info.Nodes...)
+			connectBackends[getServiceBackendName(info.Service)] = info.Service.Connect
 		}
 	}
+	FuncMap["isConnectNode"] = func(node *api.ServiceEntry) bool {
+		return connectBackends[getNodeBackendName(node)]
+	}
 	// Ensure a stable ordering of nodes so that identical configurations may be detected
 	sort.Sort(nodeSorter(allNodes))
 
@@ -92,7 +98,7 @@ func (p *Provider) setupFrontEndRuleTemplate() {
 		"getTag":       getTag,
 		"hasTag":       hasTag,
 	}
-	tmpl := template.New("consul catalog frontend rule").Funcs(FuncMap)
+	tmpl := template.New("consul catalog frontend rule").Funcs(provider.TemplateFuncMap(FuncMap))
 	p.frontEndRuleTemplate = tmpl
 }
 
@@ -131,6 +137,14 @@ func (p *Provider) getFrontendRule(service serviceUpdate) string {
 	return buffer.String()
 }
 
+// getConnectTLS returns the TLS configuration of the backend of a service reached through Consul Connect.
+func (p *Provider) getConnectTLS(service *serviceUpdate) *types.BackendTLS {
+	if !service.Connect || p.connectCerts == nil {
+		return nil
+	}
+	return p.connectCerts.backendTLS(service.ServiceName, service.Datacenters)
+}
+
 // Deprecated
 func (p *Provider) hasMaxConnAttributes(attributes []string) bool {
 	amount := p.getAttribute(label.SuffixBackendMaxConnAmount, attributes, "")
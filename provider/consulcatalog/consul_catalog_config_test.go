@@ -7,6 +7,7 @@ import (
 
 	"github.com/containous/flaeg"
 	"github.com/containous/traefik/provider/label"
+	traefikTls "github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
 	"github.com/hashicorp/consul/api"
 	"github.com/stretchr/testify/assert"
@@ -130,6 +131,68 @@ func TestProviderBuildConfiguration(t *testing.T) {
 	}
 }
 
+func TestProviderBuildConfigurationConnect(t *testing.T) {
+	provider := &Provider{
+		Domain:               "localhost",
+		Prefix:               "traefik",
+		ExposedByDefault:     true,
+		FrontEndRule:         "Host:{{.ServiceName}}.{{.Domain}}",
+		frontEndRuleTemplate: template.New("consul catalog frontend rule"),
+		ConnectAware:         true,
+		connectCerts: &connectCerts{
+			trustDomain: "11111111-2222-3333-4444-555555555555.consul",
+			rootCAs:     traefikTls.RootCAs{"-----BEGIN CERTIFICATE-----\nroot\n-----END CERTIFICATE-----\n"},
+			hasLeaf:     true,
+		},
+	}
+
+	nodes := []catalogUpdate{
+		{
+			Service: &serviceUpdate{
+				ServiceName: "web",
+				Connect:     true,
+				Datacenters: []string{"dc1"},
+			},
+			Nodes: []*api.ServiceEntry{
+				{
+					Service: &api.AgentService{
+						Service: "web",
+						Address: "10.0.0.1",
+						Port:    21000,
+					},
+					Node: &api.Node{
+						Node:       "node1",
+						Address:    "10.0.0.1",
+						Datacenter: "dc1",
+					},
+				},
+			},
+		},
+	}
+
+	actualConfig := provider.buildConfiguration(nodes)
+	assert.NotNil(t, actualConfig)
+
+	expected := map[string]*types.Backend{
+		"backend-web": {
+			Servers: map[string]types.Server{
+				"web-0-l2y6kYxZwG69Jv7WKXUD1o8OeH4": {
+					URL: "https://10.0.0.1:21000",
+				},
+			},
+			LoadBalancer: &types.LoadBalancer{
+				Method: "wrr",
+			},
+			TLS: &types.BackendTLS{
+				RootCAs:         traefikTls.RootCAs{"-----BEGIN CERTIFICATE-----\nroot\n-----END CERTIFICATE-----\n"},
+				CertificateName: connectCertificateName,
+				PeerURIs:        []string{"spiffe://11111111-2222-3333-4444-555555555555.consul/ns/default/dc/dc1/svc/web"},
+			},
+		},
+	}
+	assert.Equal(t, expected, actualConfig.Backends)
+}
+
 func TestGetTag(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -590,6 +653,16 @@ func TestProviderGetFrontendRule(t *testing.T) {
 			},
 			expected: "PathPrefix:/bar",
 		},
+		{
+			desc: "Should return host from sprig functions",
+			service: serviceUpdate{
+				ServiceName: "Foo_Bar",
+				Attributes: []string{
+					"traefik.frontend.rule=Host:{{.ServiceName | lower | replace \"_\" \"-\"}}.{{.Domain}}",
+				},
+			},
+			expected: "Host:foo-bar.localhost",
+		},
 	}
 
 	for _, test := range testCases {
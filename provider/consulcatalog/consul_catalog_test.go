@@ -860,3 +860,53 @@ func TestGetConstraintTags(t *testing.T) {
 		})
 	}
 }
+
+func TestPreferDatacenter(t *testing.T) {
+	dc1Node := &api.ServiceEntry{
+		Node:    &api.Node{Node: "node1", Datacenter: "dc1"},
+		Service: &api.AgentService{Service: "api", Address: "10.0.0.1", Port: 80},
+	}
+	dc2Node := &api.ServiceEntry{
+		Node:    &api.Node{Node: "node2", Datacenter: "dc2"},
+		Service: &api.AgentService{Service: "api", Address: "10.1.0.1", Port: 80},
+	}
+
+	testCases := []struct {
+		desc                string
+		preferredDatacenter string
+		nodes               []*api.ServiceEntry
+		expected            []*api.ServiceEntry
+	}{
+		{
+			desc:     "without preferred datacenter",
+			nodes:    []*api.ServiceEntry{dc1Node, dc2Node},
+			expected: []*api.ServiceEntry{dc1Node, dc2Node},
+		},
+		{
+			desc:                "with healthy nodes in the preferred datacenter",
+			preferredDatacenter: "dc2",
+			nodes:               []*api.ServiceEntry{dc1Node, dc2Node},
+			expected:            []*api.ServiceEntry{dc2Node},
+		},
+		{
+			desc:                "without healthy nodes in the preferred datacenter",
+			preferredDatacenter: "dc2",
+			nodes:               []*api.ServiceEntry{dc1Node},
+			expected:            []*api.ServiceEntry{dc1Node},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &Provider{
+				Datacenters:         Datacenters{"dc1", "dc2"},
+				PreferredDatacenter: test.preferredDatacenter,
+			}
+
+			assert.Equal(t, test.expected, provider.preferDatacenter(test.nodes))
+		})
+	}
+}
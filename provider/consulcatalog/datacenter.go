@@ -0,0 +1,32 @@
+package consulcatalog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Datacenters holds Consul datacenters names
+type Datacenters []string
+
+//Set adds strings elem into the the parser
+//it splits str on , and ;
+func (dcs *Datacenters) Set(str string) error {
+	fargs := func(c rune) bool {
+		return c == ',' || c == ';'
+	}
+	// get function
+	slice := strings.FieldsFunc(str, fargs)
+	*dcs = append(*dcs, slice...)
+	return nil
+}
+
+//Get []string
+func (dcs *Datacenters) Get() interface{} { return *dcs }
+
+//String return slice in a string
+func (dcs *Datacenters) String() string { return fmt.Sprintf("%v", *dcs) }
+
+//SetValue sets []string into the parser
+func (dcs *Datacenters) SetValue(val interface{}) {
+	*dcs = val.(Datacenters)
+}
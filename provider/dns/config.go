@@ -0,0 +1,205 @@
+package dns
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/provider"
+	"github.com/containous/traefik/types"
+	"github.com/miekg/dns"
+)
+
+// loadConfiguration resolves the records of all the services
+// and returns the resulting configuration with the lowest TTL of the records.
+func (p *Provider) loadConfiguration() (*types.Configuration, time.Duration) {
+	if p.lastServers == nil {
+		p.lastServers = make(map[string]map[string]types.Server)
+	}
+
+	var minTTL time.Duration
+	servers := make(map[string]map[string]types.Server)
+	for serviceName, service := range p.Services {
+		serviceServers, ttl, err := p.resolveService(service)
+		if err != nil {
+			// keep the last known servers to survive transient DNS failures
+			log.Errorf("Unable to resolve the servers of the DNS service %s: %v", serviceName, err)
+			if lastServers, ok := p.lastServers[serviceName]; ok {
+				servers[serviceName] = lastServers
+			}
+			continue
+		}
+
+		servers[serviceName] = serviceServers
+		if ttl > 0 && (minTTL == 0 || ttl < minTTL) {
+			minTTL = ttl
+		}
+	}
+	p.lastServers = servers
+
+	configuration, err := p.buildConfiguration(servers)
+	if err != nil {
+		log.Errorf("Unable to build the configuration of the DNS services: %v", err)
+		return nil, minTTL
+	}
+	return configuration, minTTL
+}
+
+// Build the configuration from the servers of the services
+func (p *Provider) buildConfiguration(servers map[string]map[string]types.Server) (*types.Configuration, error) {
+	var dnsFuncMap = template.FuncMap{
+		"getFrontendRule": getFrontendRule,
+	}
+
+	resolved := make(map[string]map[string]types.Server)
+	for serviceName, serviceServers := range servers {
+		if len(serviceServers) == 0 {
+			log.Warnf("No server found for the DNS service %s", serviceName)
+			continue
+		}
+		resolved[serviceName] = serviceServers
+	}
+
+	templateObjects := struct {
+		Services map[string]*Service
+		Servers  map[string]map[string]types.Server
+	}{
+		Services: p.Services,
+		Servers:  resolved,
+	}
+
+	return p.GetConfiguration("templates/dns.tmpl", dnsFuncMap, templateObjects)
+}
+
+func getFrontendRule(serviceName string, service *Service) string {
+	if len(service.FrontendRule) > 0 {
+		return service.FrontendRule
+	}
+	return "Host:" + serviceName
+}
+
+func (p *Provider) resolveService(service *Service) (map[string]types.Server, time.Duration, error) {
+	switch strings.ToUpper(service.Type) {
+	case recordTypeA:
+		return p.resolveA(service)
+	case recordTypeSRV, "":
+		return p.resolveSRV(service)
+	default:
+		return nil, 0, fmt.Errorf("unsupported record type %q", service.Type)
+	}
+}
+
+func (p *Provider) resolveA(service *Service) (map[string]types.Server, time.Duration, error) {
+	var ttl time.Duration
+	servers := make(map[string]types.Server)
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		answer, _, err := p.lookup(service.Record, qtype)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, ip := range extractIPs(answer, &ttl) {
+			addServer(servers, service, ip, service.Port, 1)
+		}
+	}
+	return servers, ttl, nil
+}
+
+func (p *Provider) resolveSRV(service *Service) (map[string]types.Server, time.Duration, error) {
+	answer, extra, err := p.lookup(service.Record, dns.TypeSRV)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var ttl time.Duration
+	var records []*dns.SRV
+	for _, rr := range answer {
+		if srv, ok := rr.(*dns.SRV); ok {
+			records = append(records, srv)
+			updateTTL(&ttl, rr)
+		}
+	}
+
+	// only the targets with the lowest priority must be used
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Priority < records[j].Priority
+	})
+
+	servers := make(map[string]types.Server)
+	for _, srv := range records {
+		if srv.Priority != records[0].Priority {
+			break
+		}
+
+		weight := int(srv.Weight)
+		if weight == 0 {
+			weight = 1
+		}
+
+		target := normalizeTarget(srv.Target)
+		ips := extractIPs(filterByName(extra, srv.Target), &ttl)
+		if len(ips) == 0 {
+			// without additional records, the target is resolved when dialing
+			addServer(servers, service, target, int(srv.Port), weight)
+			continue
+		}
+		for _, ip := range ips {
+			addServer(servers, service, ip, int(srv.Port), weight)
+		}
+	}
+	return servers, ttl, nil
+}
+
+func addServer(servers map[string]types.Server, service *Service, host string, port int, weight int) {
+	protocol := service.Protocol
+	if len(protocol) == 0 {
+		protocol = "http"
+	}
+	if service.Weight > 0 {
+		weight = service.Weight
+	}
+
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	servers["server-"+provider.Normalize(address)] = types.Server{
+		URL:    protocol + "://" + address,
+		Weight: weight,
+	}
+}
+
+func extractIPs(records []dns.RR, ttl *time.Duration) []string {
+	var ips []string
+	for _, rr := range records {
+		switch record := rr.(type) {
+		case *dns.A:
+			ips = append(ips, record.A.String())
+		case *dns.AAAA:
+			ips = append(ips, record.AAAA.String())
+		default:
+			continue
+		}
+		updateTTL(ttl, rr)
+	}
+	return ips
+}
+
+func filterByName(records []dns.RR, name string) []dns.RR {
+	var filtered []dns.RR
+	for _, rr := range records {
+		if strings.EqualFold(rr.Header().Name, name) {
+			filtered = append(filtered, rr)
+		}
+	}
+	return filtered
+}
+
+func updateTTL(ttl *time.Duration, rr dns.RR) {
+	recordTTL := time.Duration(rr.Header().Ttl) * time.Second
+	if recordTTL > 0 && (*ttl == 0 || recordTTL < *ttl) {
+		*ttl = recordTTL
+	}
+}
@@ -0,0 +1,264 @@
+package dns
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/types"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRecords struct {
+	answers map[uint16][]dns.RR
+	extra   []dns.RR
+	err     error
+}
+
+func fakeLookup(records map[string]fakeRecords) func(name string, qtype uint16) ([]dns.RR, []dns.RR, error) {
+	return func(name string, qtype uint16) ([]dns.RR, []dns.RR, error) {
+		record, ok := records[name]
+		if !ok {
+			return nil, nil, nil
+		}
+		if record.err != nil {
+			return nil, nil, record.err
+		}
+		return record.answers[qtype], record.extra, nil
+	}
+}
+
+func srvRecord(name string, ttl uint32, priority, weight, port uint16, target string) *dns.SRV {
+	return &dns.SRV{
+		Hdr:      dns.RR_Header{Name: name, Rrtype: dns.TypeSRV, Ttl: ttl},
+		Priority: priority,
+		Weight:   weight,
+		Port:     port,
+		Target:   target,
+	}
+}
+
+func aRecord(name string, ttl uint32, ip string) *dns.A {
+	return &dns.A{
+		Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeA, Ttl: ttl},
+		A:   net.ParseIP(ip),
+	}
+}
+
+func aaaaRecord(name string, ttl uint32, ip string) *dns.AAAA {
+	return &dns.AAAA{
+		Hdr:  dns.RR_Header{Name: name, Rrtype: dns.TypeAAAA, Ttl: ttl},
+		AAAA: net.ParseIP(ip),
+	}
+}
+
+func TestLoadConfiguration(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		services         map[string]*Service
+		records          map[string]fakeRecords
+		expectedBackends map[string]*types.Backend
+		expectedTTL      time.Duration
+	}{
+		{
+			desc: "SRV records with additional records",
+			services: map[string]*Service{
+				"api": {Record: "_api._tcp.service.consul"},
+			},
+			records: map[string]fakeRecords{
+				"_api._tcp.service.consul": {
+					answers: map[uint16][]dns.RR{
+						dns.TypeSRV: {
+							srvRecord("_api._tcp.service.consul.", 30, 1, 10, 8080, "node1.node.consul."),
+							srvRecord("_api._tcp.service.consul.", 60, 1, 0, 8081, "node2.node.consul."),
+							srvRecord("_api._tcp.service.consul.", 60, 2, 10, 8082, "backup.node.consul."),
+						},
+					},
+					extra: []dns.RR{
+						aRecord("node1.node.consul.", 20, "10.0.0.1"),
+					},
+				},
+			},
+			expectedBackends: map[string]*types.Backend{
+				"backend-api": {
+					Servers: map[string]types.Server{
+						"server-10-0-0-1-8080": {
+							URL:    "http://10.0.0.1:8080",
+							Weight: 10,
+						},
+						"server-node2-node-consul-8081": {
+							URL:    "http://node2.node.consul:8081",
+							Weight: 1,
+						},
+					},
+				},
+			},
+			expectedTTL: 20 * time.Second,
+		},
+		{
+			desc: "A and AAAA records",
+			services: map[string]*Service{
+				"web": {Record: "web.internal", Type: "A", Port: 443, Protocol: "https", Weight: 5},
+			},
+			records: map[string]fakeRecords{
+				"web.internal": {
+					answers: map[uint16][]dns.RR{
+						dns.TypeA:    {aRecord("web.internal.", 300, "10.0.0.2")},
+						dns.TypeAAAA: {aaaaRecord("web.internal.", 120, "fd00::2")},
+					},
+				},
+			},
+			expectedBackends: map[string]*types.Backend{
+				"backend-web": {
+					Servers: map[string]types.Server{
+						"server-10-0-0-2-443": {
+							URL:    "https://10.0.0.2:443",
+							Weight: 5,
+						},
+						"server-fd00-2-443": {
+							URL:    "https://[fd00::2]:443",
+							Weight: 5,
+						},
+					},
+				},
+			},
+			expectedTTL: 120 * time.Second,
+		},
+		{
+			desc: "unresolvable service",
+			services: map[string]*Service{
+				"api": {Record: "_api._tcp.service.consul"},
+			},
+			records: map[string]fakeRecords{
+				"_api._tcp.service.consul": {err: errors.New("timeout")},
+			},
+			expectedBackends: map[string]*types.Backend{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Provider{
+				Services: test.services,
+				lookup:   fakeLookup(test.records),
+			}
+
+			configuration, ttl := p.loadConfiguration()
+
+			assert.Equal(t, test.expectedBackends, configuration.Backends)
+			assert.Equal(t, test.expectedTTL, ttl)
+		})
+	}
+}
+
+func TestLoadConfigurationKeepsLastServersOnError(t *testing.T) {
+	records := map[string]fakeRecords{
+		"web.internal": {
+			answers: map[uint16][]dns.RR{
+				dns.TypeA: {aRecord("web.internal.", 300, "10.0.0.2")},
+			},
+		},
+	}
+
+	p := &Provider{
+		Services: map[string]*Service{
+			"web": {Record: "web.internal", Type: "A", Port: 80},
+		},
+		lookup: fakeLookup(records),
+	}
+
+	configuration, _ := p.loadConfiguration()
+	assert.Len(t, configuration.Backends["backend-web"].Servers, 1)
+
+	records["web.internal"] = fakeRecords{err: errors.New("SERVFAIL")}
+
+	configuration, _ = p.loadConfiguration()
+	assert.Len(t, configuration.Backends["backend-web"].Servers, 1)
+	assert.Equal(t, "Host:web", configuration.Frontends["frontend-web"].Routes["route-web"].Rule)
+}
+
+func TestBuildConfiguration(t *testing.T) {
+	p := &Provider{
+		Services: map[string]*Service{
+			"api": {Record: "_api._tcp.service.consul", FrontendRule: "Host:api.example.com", EntryPoints: []string{"https"}, PassHostHeader: true},
+			"web": {Record: "web.internal", Type: "A", Port: 80},
+			"old": {Record: "old.internal", Type: "A", Port: 80},
+		},
+	}
+
+	configuration, err := p.buildConfiguration(map[string]map[string]types.Server{
+		"api": {"server-10-0-0-1-8080": {URL: "http://10.0.0.1:8080", Weight: 10}},
+		"web": {"server-10-0-0-2-80": {URL: "http://10.0.0.2:80", Weight: 1}},
+		"old": {},
+	})
+	require.NoError(t, err)
+
+	expected := map[string]*types.Frontend{
+		"frontend-api": {
+			Backend:        "backend-api",
+			EntryPoints:    []string{"https"},
+			PassHostHeader: true,
+			Routes: map[string]types.Route{
+				"route-api": {Rule: "Host:api.example.com"},
+			},
+		},
+		"frontend-web": {
+			Backend: "backend-web",
+			Routes: map[string]types.Route{
+				"route-web": {Rule: "Host:web"},
+			},
+		},
+	}
+	assert.Equal(t, expected, configuration.Frontends)
+	assert.Len(t, configuration.Backends, 2)
+}
+
+func TestGetRefreshInterval(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		ttl      time.Duration
+		expected time.Duration
+	}{
+		{
+			desc:     "no TTL",
+			ttl:      0,
+			expected: 30 * time.Second,
+		},
+		{
+			desc:     "TTL lower than the refresh interval",
+			ttl:      10 * time.Second,
+			expected: 10 * time.Second,
+		},
+		{
+			desc:     "TTL greater than the refresh interval",
+			ttl:      time.Hour,
+			expected: 30 * time.Second,
+		},
+		{
+			desc:     "TTL lower than the minimum refresh interval",
+			ttl:      time.Second,
+			expected: 5 * time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Provider{
+				RefreshInterval:    flaeg.Duration(30 * time.Second),
+				MinRefreshInterval: flaeg.Duration(5 * time.Second),
+			}
+
+			assert.Equal(t, test.expected, p.getRefreshInterval(test.ttl))
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package dns
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/provider"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+	"github.com/miekg/dns"
+)
+
+const (
+	recordTypeSRV = "SRV"
+	recordTypeA   = "A"
+
+	defaultResolvConf = "/etc/resolv.conf"
+)
+
+var _ provider.Provider = (*Provider)(nil)
+
+// Provider holds configurations of the provider.
+type Provider struct {
+	provider.BaseProvider `mapstructure:",squash" export:"true"`
+	Nameservers           string              `description:"Comma-separated DNS servers to query (host:port). Defaults to the ones of /etc/resolv.conf" export:"true"`
+	RefreshInterval       flaeg.Duration      `description:"Maximum interval between two resolutions, used as well when the records have no TTL" export:"true"`
+	MinRefreshInterval    flaeg.Duration      `description:"Minimum interval between two resolutions, whatever the TTL of the records" export:"true"`
+	Services              map[string]*Service `export:"true"`
+	lookup                func(name string, qtype uint16) ([]dns.RR, []dns.RR, error)
+	lastServers           map[string]map[string]types.Server
+}
+
+// Service describes a service which servers are discovered through DNS records.
+type Service struct {
+	// Record is the name of the DNS record to resolve.
+	Record string
+	// Type of the DNS record: SRV (default) or A (for A and AAAA records).
+	Type string
+	// Port of the servers, required for A records.
+	Port int
+	// Protocol used to reach the servers (default: http).
+	Protocol string
+	// Weight of the servers, defaults to the SRV record weight.
+	Weight         int
+	FrontendRule   string
+	EntryPoints    []string
+	PassHostHeader bool
+}
+
+// Provide allows the DNS provider to provide configurations to traefik
+// using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *safe.Pool, constraints types.Constraints) error {
+	p.Constraints = append(p.Constraints, constraints...)
+
+	if p.lookup == nil {
+		nameservers, err := p.getNameservers()
+		if err != nil {
+			return err
+		}
+		p.lookup = newLookup(nameservers)
+	}
+
+	pool.Go(func(stop chan bool) {
+		for {
+			configuration, ttl := p.loadConfiguration()
+			configurationChan <- types.ConfigMessage{
+				ProviderName:  "dns",
+				Configuration: configuration,
+			}
+
+			if !p.Watch {
+				return
+			}
+
+			refresh := p.getRefreshInterval(ttl)
+			log.Debugf("Next DNS resolution in %s", refresh)
+			select {
+			case <-stop:
+				return
+			case <-time.After(refresh):
+			}
+		}
+	})
+
+	return nil
+}
+
+func (p *Provider) getNameservers() ([]string, error) {
+	if len(p.Nameservers) > 0 {
+		var nameservers []string
+		for _, nameserver := range strings.Split(p.Nameservers, ",") {
+			nameservers = append(nameservers, strings.TrimSpace(nameserver))
+		}
+		return nameservers, nil
+	}
+
+	config, err := dns.ClientConfigFromFile(defaultResolvConf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the DNS servers from %s: %v", defaultResolvConf, err)
+	}
+
+	var nameservers []string
+	for _, server := range config.Servers {
+		nameservers = append(nameservers, net.JoinHostPort(server, config.Port))
+	}
+	return nameservers, nil
+}
+
+// getRefreshInterval returns the TTL of the records,
+// bounded by the minimum and maximum refresh intervals.
+func (p *Provider) getRefreshInterval(ttl time.Duration) time.Duration {
+	refresh := time.Duration(p.RefreshInterval)
+	if ttl > 0 && (refresh <= 0 || ttl < refresh) {
+		refresh = ttl
+	}
+	if refresh < time.Duration(p.MinRefreshInterval) {
+		refresh = time.Duration(p.MinRefreshInterval)
+	}
+	if refresh <= 0 {
+		refresh = time.Second
+	}
+	return refresh
+}
+
+func newLookup(nameservers []string) func(name string, qtype uint16) ([]dns.RR, []dns.RR, error) {
+	client := &dns.Client{}
+	tcpClient := &dns.Client{Net: "tcp"}
+
+	return func(name string, qtype uint16) ([]dns.RR, []dns.RR, error) {
+		msg := &dns.Msg{}
+		msg.SetQuestion(dns.Fqdn(name), qtype)
+		msg.RecursionDesired = true
+
+		var lastErr error
+		for _, nameserver := range nameservers {
+			response, _, err := client.Exchange(msg, nameserver)
+			if response != nil && response.Truncated {
+				// the answer doesn't fit in a UDP message
+				response, _, err = tcpClient.Exchange(msg, nameserver)
+			}
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if response.Rcode != dns.RcodeSuccess {
+				lastErr = fmt.Errorf("DNS query %s %s failed: %s", dns.TypeToString[qtype], name, dns.RcodeToString[response.Rcode])
+				continue
+			}
+			return response.Answer, response.Extra, nil
+		}
+
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no DNS server to query %s", name)
+		}
+		return nil, nil, lastErr
+	}
+}
+
+func normalizeTarget(target string) string {
+	return strings.TrimSuffix(target, ".")
+}
@@ -0,0 +1,45 @@
+package dns
+
+import (
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupRetriesTruncatedAnswersOverTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	packetConn, err := net.ListenPacket("udp", listener.Addr().String())
+	require.NoError(t, err)
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		response := &dns.Msg{}
+		response.SetReply(r)
+		if _, ok := w.RemoteAddr().(*net.UDPAddr); ok {
+			response.Truncated = true
+		} else {
+			response.Answer = []dns.RR{aRecord("web.internal.", 300, "10.0.0.2")}
+		}
+		w.WriteMsg(response)
+	})
+
+	for _, server := range []*dns.Server{
+		{PacketConn: packetConn, Handler: handler},
+		{Listener: listener, Handler: handler},
+	} {
+		server := server
+		started := make(chan struct{})
+		server.NotifyStartedFunc = func() { close(started) }
+		go server.ActivateAndServe()
+		<-started
+		defer server.Shutdown()
+	}
+
+	answer, _, err := newLookup([]string{listener.Addr().String()})("web.internal", dns.TypeA)
+	require.NoError(t, err)
+	require.Len(t, answer, 1)
+	assert.Equal(t, "10.0.0.2", answer[0].(*dns.A).A.String())
+}
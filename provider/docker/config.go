@@ -25,8 +25,11 @@ func (p *Provider) buildConfiguration(containersInspected []dockerData) *types.C
 		"getMaxConn":        getMaxConn,
 		"getHealthCheck":    getHealthCheck,
 		"getBuffering":      getBuffering,
+		"getAdaptiveLimit":  getAdaptiveLimit,
+		"getBackendTLS":     getBackendTLS,
 		"getCircuitBreaker": getCircuitBreaker,
 		"getLoadBalancer":   getLoadBalancer,
+		"getServerLabels":   getServerLabels,
 
 		// TODO Deprecated [breaking]
 		"hasCircuitBreakerLabel": hasFunc(label.TraefikBackendCircuitBreakerExpression),
@@ -59,11 +62,20 @@ func (p *Provider) buildConfiguration(containersInspected []dockerData) *types.C
 		"getBasicAuth":            getFuncSliceStringLabel(label.TraefikFrontendAuthBasic),
 		"getWhitelistSourceRange": getFuncSliceStringLabel(label.TraefikFrontendWhitelistSourceRange),
 		"getFrontendRule":         p.getFrontendRule,
-
-		"getRedirect":   getRedirect,
-		"getErrorPages": getErrorPages,
-		"getRateLimit":  getRateLimit,
-		"getHeaders":    getHeaders,
+		"getServerSelector":       getFuncStringLabel(label.TraefikFrontendServerSelector, ""),
+		"getTLSPassthrough":       getFuncBoolLabel(label.TraefikFrontendTLSPassthrough, false),
+		"getTenant":               getFuncStringLabel(label.TraefikFrontendTenant, ""),
+		"getPriorityClass":        getFuncStringLabel(label.TraefikFrontendPriorityClass, ""),
+		"getACMEResolver":         getFuncStringLabel(label.TraefikFrontendACMEResolver, ""),
+		"getTLSOptions":           getFuncStringLabel(label.TraefikFrontendTLSOptions, ""),
+
+		"getRedirect":            getRedirect,
+		"getHostHeader":          getHostHeader,
+		"getClientCA":            getClientCA,
+		"getWhitelistIPStrategy": getWhitelistIPStrategy,
+		"getErrorPages":          getErrorPages,
+		"getRateLimit":           getRateLimit,
+		"getHeaders":             getHeaders,
 
 		// Services
 		"hasServices":           hasServices,
@@ -83,10 +95,13 @@ func (p *Provider) buildConfiguration(containersInspected []dockerData) *types.C
 		"getServicePassTLSCert":          getFuncServiceBoolLabel(label.SuffixFrontendPassTLSCert, label.DefaultPassTLSCert),
 		"getServicePriority":             getFuncServiceIntLabel(label.SuffixFrontendPriority, label.DefaultFrontendPriorityInt),
 
-		"getServiceRedirect":   getServiceRedirect,
-		"getServiceErrorPages": getServiceErrorPages,
-		"getServiceRateLimit":  getServiceRateLimit,
-		"getServiceHeaders":    getServiceHeaders,
+		"getServiceRedirect":            getServiceRedirect,
+		"getServiceHostHeader":          getServiceHostHeader,
+		"getServiceClientCA":            getServiceClientCA,
+		"getServiceWhitelistIPStrategy": getServiceWhitelistIPStrategy,
+		"getServiceErrorPages":          getServiceErrorPages,
+		"getServiceRateLimit":           getServiceRateLimit,
+		"getServiceHeaders":             getServiceHeaders,
 	}
 	// filter containers
 	filteredContainers := fun.Filter(func(container dockerData) bool {
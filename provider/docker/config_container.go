@@ -9,6 +9,7 @@ import (
 	"github.com/containous/traefik/log"
 	"github.com/containous/traefik/provider"
 	"github.com/containous/traefik/provider/label"
+	"github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
 	"github.com/docker/go-connections/nat"
 )
@@ -176,6 +177,16 @@ func getLoadBalancer(container dockerData) *types.LoadBalancer {
 		lb.Stickiness = &types.Stickiness{CookieName: cookieName}
 	}
 
+	header := label.GetStringValue(container.Labels, label.TraefikBackendLoadBalancerStickinessHeader, "")
+	query := label.GetStringValue(container.Labels, label.TraefikBackendLoadBalancerStickinessQuery, "")
+	if len(header) > 0 || len(query) > 0 {
+		if lb.Stickiness == nil {
+			lb.Stickiness = &types.Stickiness{}
+		}
+		lb.Stickiness.Header = header
+		lb.Stickiness.Query = query
+	}
+
 	return lb
 }
 
@@ -200,7 +211,8 @@ func getCircuitBreaker(container dockerData) *types.CircuitBreaker {
 
 func getHealthCheck(container dockerData) *types.HealthCheck {
 	path := label.GetStringValue(container.Labels, label.TraefikBackendHealthCheckPath, "")
-	if len(path) == 0 {
+	hcType := label.GetStringValue(container.Labels, label.TraefikBackendHealthCheckType, "")
+	if len(path) == 0 && hcType != types.HealthCheckTypeGRPC && hcType != types.HealthCheckTypeTCP {
 		return nil
 	}
 
@@ -208,9 +220,40 @@ func getHealthCheck(container dockerData) *types.HealthCheck {
 	interval := label.GetStringValue(container.Labels, label.TraefikBackendHealthCheckInterval, "")
 
 	return &types.HealthCheck{
-		Path:     path,
-		Port:     port,
-		Interval: interval,
+		Type:          hcType,
+		Path:          path,
+		Port:          port,
+		Service:       label.GetStringValue(container.Labels, label.TraefikBackendHealthCheckService, ""),
+		Interval:      interval,
+		Timeout:       label.GetStringValue(container.Labels, label.TraefikBackendHealthCheckTimeout, ""),
+		Hostname:      label.GetStringValue(container.Labels, label.TraefikBackendHealthCheckHostname, ""),
+		Headers:       label.GetMapValue(container.Labels, label.TraefikBackendHealthCheckHeaders),
+		ProxyProtocol: label.GetIntValue(container.Labels, label.TraefikBackendHealthCheckProxyProtocol, 0),
+	}
+}
+
+func getBackendTLS(container dockerData) *types.BackendTLS {
+	serverName := label.GetStringValue(container.Labels, label.TraefikBackendTLSServerName, "")
+	verification := label.GetStringValue(container.Labels, label.TraefikBackendTLSVerification, "")
+	if len(serverName) == 0 && len(verification) == 0 {
+		return nil
+	}
+
+	return &types.BackendTLS{
+		ServerName:   serverName,
+		Verification: verification,
+	}
+}
+
+func getAdaptiveLimit(container dockerData) *types.AdaptiveLimit {
+	if !label.HasPrefix(container.Labels, label.TraefikBackendAdaptiveLimit) {
+		return nil
+	}
+
+	return &types.AdaptiveLimit{
+		InitialLimit: label.GetIntValue(container.Labels, label.TraefikBackendAdaptiveLimitInitial, 0),
+		MinLimit:     label.GetIntValue(container.Labels, label.TraefikBackendAdaptiveLimitMin, 0),
+		MaxLimit:     label.GetIntValue(container.Labels, label.TraefikBackendAdaptiveLimitMax, 0),
 	}
 }
 
@@ -250,6 +293,65 @@ func getRedirect(container dockerData) *types.Redirect {
 	return nil
 }
 
+// getServerLabels returns the labels of the server, used by the frontends to select a subset of the servers.
+func getServerLabels(container dockerData) map[string]string {
+	var serverLabels map[string]string
+	for name, value := range container.Labels {
+		if !strings.HasPrefix(name, label.TraefikBackendServerLabel) {
+			continue
+		}
+
+		key := strings.TrimPrefix(name, label.TraefikBackendServerLabel)
+		if len(key) == 0 {
+			continue
+		}
+
+		if serverLabels == nil {
+			serverLabels = make(map[string]string)
+		}
+		serverLabels[key] = value
+	}
+	return serverLabels
+}
+
+func getHostHeader(container dockerData) *types.HostHeader {
+	strategy := label.GetStringValue(container.Labels, label.TraefikFrontendHostHeaderStrategy, "")
+	if len(strategy) == 0 {
+		return nil
+	}
+
+	return &types.HostHeader{
+		Strategy: strategy,
+		Value:    label.GetStringValue(container.Labels, label.TraefikFrontendHostHeaderValue, ""),
+	}
+}
+
+func getClientCA(container dockerData) *types.ClientCA {
+	files := label.GetSliceStringValue(container.Labels, label.TraefikFrontendClientCAFiles)
+	if len(files) == 0 {
+		return nil
+	}
+
+	clientCA := &types.ClientCA{
+		Optional: label.GetBoolValue(container.Labels, label.TraefikFrontendClientCAOptional, false),
+	}
+	for _, file := range files {
+		clientCA.Files = append(clientCA.Files, tls.FileOrContent(file))
+	}
+	return clientCA
+}
+
+func getWhitelistIPStrategy(container dockerData) *types.IPStrategy {
+	if !label.Has(container.Labels, label.TraefikFrontendWhitelistIPStrategyDepth) && !label.Has(container.Labels, label.TraefikFrontendWhitelistIPStrategyExcludedIPs) {
+		return nil
+	}
+
+	return &types.IPStrategy{
+		Depth:       label.GetIntValue(container.Labels, label.TraefikFrontendWhitelistIPStrategyDepth, 0),
+		ExcludedIPs: label.GetSliceStringValue(container.Labels, label.TraefikFrontendWhitelistIPStrategyExcludedIPs),
+	}
+}
+
 func getErrorPages(container dockerData) map[string]*types.ErrorPage {
 	prefix := label.Prefix + label.BaseFrontendErrorPage
 	return label.ParseErrorPages(container.Labels, prefix, label.RegexpFrontendErrorPage)
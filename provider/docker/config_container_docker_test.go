@@ -8,6 +8,7 @@ import (
 
 	"github.com/containous/flaeg"
 	"github.com/containous/traefik/provider/label"
+	"github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
 	docker "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -118,6 +119,7 @@ func TestDockerBuildConfiguration(t *testing.T) {
 						label.TraefikFrontendEntryPoints:          "http,https",
 						label.TraefikFrontendPassHostHeader:       "true",
 						label.TraefikFrontendPassTLSCert:          "true",
+						label.TraefikFrontendTLSPassthrough:       "true",
 						label.TraefikFrontendPriority:             "666",
 						label.TraefikFrontendRedirectEntryPoint:   "https",
 						label.TraefikFrontendRedirectRegex:        "nope",
@@ -183,6 +185,7 @@ func TestDockerBuildConfiguration(t *testing.T) {
 					},
 					PassHostHeader: true,
 					PassTLSCert:    true,
+					TLSPassthrough: true,
 					Priority:       666,
 					BasicAuth: []string{
 						"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/",
@@ -1170,6 +1173,21 @@ func TestDockerGetLoadBalancer(t *testing.T) {
 				Stickiness: nil,
 			},
 		},
+		{
+			desc: "should return a Stickiness on a header when the header is set",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikBackendLoadBalancerMethod:           "wrr",
+					label.TraefikBackendLoadBalancerStickinessHeader: "X-Tenant-ID",
+				})),
+			expected: &types.LoadBalancer{
+				Method: "wrr",
+				Stickiness: &types.Stickiness{
+					Header: "X-Tenant-ID",
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -1285,6 +1303,252 @@ func TestDockerGetRedirect(t *testing.T) {
 	}
 }
 
+func TestDockerGetServerLabels(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  map[string]string
+	}{
+		{
+			desc: "should return nil when no server label",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikWeight: "2",
+				})),
+			expected: nil,
+		},
+		{
+			desc: "should return the server labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikWeight:                         "2",
+					label.TraefikBackendServerLabel + "zone":    "eu-west-1",
+					label.TraefikBackendServerLabel + "version": "v2",
+				})),
+			expected: map[string]string{
+				"zone":    "eu-west-1",
+				"version": "v2",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getServerLabels(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDockerGetBackendTLS(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.BackendTLS
+	}{
+		{
+			desc: "should return nil when no TLS labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when TLS labels are set",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikBackendTLSServerName:   "internal.local",
+					label.TraefikBackendTLSVerification: "skipHostname",
+				})),
+			expected: &types.BackendTLS{
+				ServerName:   "internal.local",
+				Verification: "skipHostname",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getBackendTLS(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDockerGetHostHeader(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.HostHeader
+	}{
+		{
+			desc: "should return nil when no host header labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return nil when only value label",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendHostHeaderValue: "internal.local",
+				}),
+			),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when strategy and value labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendHostHeaderStrategy: "custom",
+					label.TraefikFrontendHostHeaderValue:    "internal.local",
+				}),
+			),
+			expected: &types.HostHeader{
+				Strategy: "custom",
+				Value:    "internal.local",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getHostHeader(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDockerGetClientCA(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.ClientCA
+	}{
+		{
+			desc: "should return nil when no client CA labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return nil when only optional label",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendClientCAOptional: "true",
+				}),
+			),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when files and optional labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendClientCAFiles:    "/certs/tenant1.crt, /certs/tenant2.crt",
+					label.TraefikFrontendClientCAOptional: "true",
+				}),
+			),
+			expected: &types.ClientCA{
+				Files:    []tls.FileOrContent{"/certs/tenant1.crt", "/certs/tenant2.crt"},
+				Optional: true,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getClientCA(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDockerGetWhitelistIPStrategy(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.IPStrategy
+	}{
+		{
+			desc: "should return nil when no IP strategy labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when depth label",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendWhitelistIPStrategyDepth: "2",
+				}),
+			),
+			expected: &types.IPStrategy{
+				Depth: 2,
+			},
+		},
+		{
+			desc: "should return a struct when excluded IPs label",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendWhitelistIPStrategyExcludedIPs: "10.0.0.0/8, 192.168.0.1",
+				}),
+			),
+			expected: &types.IPStrategy{
+				ExcludedIPs: []string{"10.0.0.0/8", "192.168.0.1"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getWhitelistIPStrategy(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestDockerGetRateLimit(t *testing.T) {
 	testCases := []struct {
 		desc      string
@@ -1451,6 +1715,52 @@ func TestDockerGetHealthCheck(t *testing.T) {
 				Interval: "6",
 			},
 		},
+		{
+			desc: "should return a struct when gRPC health check labels are set",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikBackendHealthCheckType:    "grpc",
+					label.TraefikBackendHealthCheckService: "foo.Bar",
+				})),
+			expected: &types.HealthCheck{
+				Type:    "grpc",
+				Service: "foo.Bar",
+			},
+		},
+		{
+			desc: "should return a struct when TCP health check labels are set",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikBackendHealthCheckType:    "tcp",
+					label.TraefikBackendHealthCheckTimeout: "1s",
+				})),
+			expected: &types.HealthCheck{
+				Type:    "tcp",
+				Timeout: "1s",
+			},
+		},
+		{
+			desc: "should return a struct when probe labels are set",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikBackendHealthCheckPath:          "/health",
+					label.TraefikBackendHealthCheckHostname:      "foo.com",
+					label.TraefikBackendHealthCheckHeaders:       "X-Foo:bar||X-Bar:foo",
+					label.TraefikBackendHealthCheckProxyProtocol: "2",
+				})),
+			expected: &types.HealthCheck{
+				Path:     "/health",
+				Hostname: "foo.com",
+				Headers: map[string]string{
+					"X-Foo": "bar",
+					"X-Bar": "foo",
+				},
+				ProxyProtocol: 2,
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -1515,6 +1825,48 @@ func TestDockerGetBuffering(t *testing.T) {
 	}
 }
 
+func TestDockerGetAdaptiveLimit(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.AdaptiveLimit
+	}{
+		{
+			desc: "should return nil when no adaptive limit labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when adaptive limit labels are set",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikBackendAdaptiveLimitInitial: "50",
+					label.TraefikBackendAdaptiveLimitMax:     "200",
+				})),
+			expected: &types.AdaptiveLimit{
+				InitialLimit: 50,
+				MaxLimit:     200,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getAdaptiveLimit(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestDockerGetHeaders(t *testing.T) {
 	testCases := []struct {
 		desc      string
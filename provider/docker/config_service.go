@@ -7,6 +7,7 @@ import (
 
 	"github.com/containous/traefik/provider"
 	"github.com/containous/traefik/provider/label"
+	"github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
 )
 
@@ -121,6 +122,48 @@ func getServiceRedirect(container dockerData, serviceName string) *types.Redirec
 	return getRedirect(container)
 }
 
+func getServiceHostHeader(container dockerData, serviceName string) *types.HostHeader {
+	serviceLabels := getServiceLabels(container, serviceName)
+
+	if hasStrictServiceLabel(serviceLabels, label.SuffixFrontendHostHeaderStrategy) {
+		return &types.HostHeader{
+			Strategy: getStrictServiceStringValue(serviceLabels, label.SuffixFrontendHostHeaderStrategy, ""),
+			Value:    getStrictServiceStringValue(serviceLabels, label.SuffixFrontendHostHeaderValue, ""),
+		}
+	}
+
+	return getHostHeader(container)
+}
+
+func getServiceClientCA(container dockerData, serviceName string) *types.ClientCA {
+	serviceLabels := getServiceLabels(container, serviceName)
+
+	if hasStrictServiceLabel(serviceLabels, label.SuffixFrontendClientCAFiles) {
+		optional, _ := strconv.ParseBool(getStrictServiceStringValue(serviceLabels, label.SuffixFrontendClientCAOptional, "false"))
+		clientCA := &types.ClientCA{Optional: optional}
+		for _, file := range label.SplitAndTrimString(getStrictServiceStringValue(serviceLabels, label.SuffixFrontendClientCAFiles, ""), ",") {
+			clientCA.Files = append(clientCA.Files, tls.FileOrContent(file))
+		}
+		return clientCA
+	}
+
+	return getClientCA(container)
+}
+
+func getServiceWhitelistIPStrategy(container dockerData, serviceName string) *types.IPStrategy {
+	serviceLabels := getServiceLabels(container, serviceName)
+
+	if hasStrictServiceLabel(serviceLabels, label.SuffixFrontendWhitelistIPStrategyDepth) || hasStrictServiceLabel(serviceLabels, label.SuffixFrontendWhitelistIPStrategyExcludedIPs) {
+		depth, _ := strconv.Atoi(getStrictServiceStringValue(serviceLabels, label.SuffixFrontendWhitelistIPStrategyDepth, "0"))
+		return &types.IPStrategy{
+			Depth:       depth,
+			ExcludedIPs: label.SplitAndTrimString(getStrictServiceStringValue(serviceLabels, label.SuffixFrontendWhitelistIPStrategyExcludedIPs, ""), ","),
+		}
+	}
+
+	return getWhitelistIPStrategy(container)
+}
+
 func getServiceErrorPages(container dockerData, serviceName string) map[string]*types.ErrorPage {
 	serviceLabels := getServiceLabels(container, serviceName)
 
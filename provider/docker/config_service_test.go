@@ -8,6 +8,7 @@ import (
 
 	"github.com/containous/flaeg"
 	"github.com/containous/traefik/provider/label"
+	"github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
 	docker "github.com/docker/docker/api/types"
 	"github.com/docker/go-connections/nat"
@@ -1090,6 +1091,178 @@ func TestDockerGetServiceRedirect(t *testing.T) {
 	}
 }
 
+func TestDockerGetServiceHostHeader(t *testing.T) {
+	service := "rubiks"
+
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.HostHeader
+	}{
+		{
+			desc: "should return nil when no host header labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when service strategy label",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.Prefix + service + "." + label.SuffixFrontendHostHeaderStrategy: "backend",
+				}),
+			),
+			expected: &types.HostHeader{
+				Strategy: "backend",
+			},
+		},
+		{
+			desc: "should fallback on container labels when no service strategy label",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendHostHeaderStrategy: "custom",
+					label.TraefikFrontendHostHeaderValue:    "internal.local",
+				}),
+			),
+			expected: &types.HostHeader{
+				Strategy: "custom",
+				Value:    "internal.local",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getServiceHostHeader(dData, service)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDockerGetServiceClientCA(t *testing.T) {
+	service := "rubiks"
+
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.ClientCA
+	}{
+		{
+			desc: "should return nil when no client CA labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when service files label",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.Prefix + service + "." + label.SuffixFrontendClientCAFiles:    "/certs/tenant1.crt",
+					label.Prefix + service + "." + label.SuffixFrontendClientCAOptional: "true",
+				}),
+			),
+			expected: &types.ClientCA{
+				Files:    []tls.FileOrContent{"/certs/tenant1.crt"},
+				Optional: true,
+			},
+		},
+		{
+			desc: "should fallback on container labels when no service files label",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendClientCAFiles: "/certs/tenant2.crt",
+				}),
+			),
+			expected: &types.ClientCA{
+				Files: []tls.FileOrContent{"/certs/tenant2.crt"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getServiceClientCA(dData, service)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDockerGetServiceWhitelistIPStrategy(t *testing.T) {
+	service := "rubiks"
+
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.IPStrategy
+	}{
+		{
+			desc: "should return nil when no IP strategy labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when service labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.Prefix + service + "." + label.SuffixFrontendWhitelistIPStrategyDepth:       "1",
+					label.Prefix + service + "." + label.SuffixFrontendWhitelistIPStrategyExcludedIPs: "10.0.0.0/8",
+					label.TraefikFrontendWhitelistIPStrategyDepth:                                     "2",
+				}),
+			),
+			expected: &types.IPStrategy{
+				Depth:       1,
+				ExcludedIPs: []string{"10.0.0.0/8"},
+			},
+		},
+		{
+			desc: "should fallback on container labels when no service labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendWhitelistIPStrategyDepth: "2",
+				}),
+			),
+			expected: &types.IPStrategy{
+				Depth: 2,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getServiceWhitelistIPStrategy(dData, service)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestDockerGetServiceHeaders(t *testing.T) {
 	service := "rubiks"
 
@@ -12,6 +12,7 @@ import (
 	"github.com/cenk/backoff"
 	"github.com/containous/traefik/job"
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
 	"github.com/containous/traefik/provider"
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/types"
@@ -39,12 +40,13 @@ var _ provider.Provider = (*Provider)(nil)
 // Provider holds configurations of the provider.
 type Provider struct {
 	provider.BaseProvider `mapstructure:",squash" export:"true"`
-	Endpoint              string           `description:"Docker server endpoint. Can be a tcp or a unix socket endpoint"`
-	Domain                string           `description:"Default domain used"`
-	TLS                   *types.ClientTLS `description:"Enable Docker TLS support" export:"true"`
-	ExposedByDefault      bool             `description:"Expose containers by default" export:"true"`
-	UseBindPortIP         bool             `description:"Use the ip address from the bound port, rather than from the inner network" export:"true"`
-	SwarmMode             bool             `description:"Use Docker on Swarm Mode" export:"true"`
+	Endpoint              string               `description:"Docker server endpoint. Can be a tcp, a unix socket or an ssh endpoint"`
+	Domain                string               `description:"Default domain used"`
+	TLS                   *types.ClientTLS     `description:"Enable Docker TLS support" export:"true"`
+	ExposedByDefault      bool                 `description:"Expose containers by default" export:"true"`
+	UseBindPortIP         bool                 `description:"Use the ip address from the bound port, rather than from the inner network" export:"true"`
+	SwarmMode             bool                 `description:"Use Docker on Swarm Mode" export:"true"`
+	Proxy                 *types.OutboundProxy `description:"Outbound proxy used to reach a tcp Docker endpoint" export:"true"`
 }
 
 // dockerData holds the need data to the Provider p
@@ -75,14 +77,26 @@ type networkData struct {
 
 func (p *Provider) createClient() (client.APIClient, error) {
 	var httpClient *http.Client
+	host := p.Endpoint
 
-	if p.TLS != nil {
-		config, err := p.TLS.CreateTLSConfig()
+	if strings.HasPrefix(p.Endpoint, "ssh://") {
+		tr, err := newSSHTransport(p.Endpoint)
 		if err != nil {
 			return nil, err
 		}
-		tr := &http.Transport{
-			TLSClientConfig: config,
+
+		httpClient = &http.Client{
+			Transport: tr,
+		}
+		host = sshDockerHost
+	} else if p.TLS != nil || p.Proxy != nil {
+		tr := &http.Transport{}
+		if p.TLS != nil {
+			config, err := p.TLS.CreateTLSConfig()
+			if err != nil {
+				return nil, err
+			}
+			tr.TLSClientConfig = config
 		}
 		proto, addr, _, err := client.ParseHost(p.Endpoint)
 		if err != nil {
@@ -90,6 +104,9 @@ func (p *Provider) createClient() (client.APIClient, error) {
 		}
 
 		sockets.ConfigureTransport(tr, proto, addr)
+		if err := p.Proxy.ConfigureTransport(tr); err != nil {
+			return nil, err
+		}
 
 		httpClient = &http.Client{
 			Transport: tr,
@@ -107,7 +124,7 @@ func (p *Provider) createClient() (client.APIClient, error) {
 		apiVersion = DockerAPIVersion
 	}
 
-	return client.NewClient(p.Endpoint, apiVersion, httpClient, httpHeaders)
+	return client.NewClient(host, apiVersion, httpClient, httpHeaders)
 }
 
 // Provide allows the docker provider to provide configurations to traefik
@@ -241,6 +258,7 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 		}
 		notify := func(err error, time time.Duration) {
 			log.Errorf("Provider connection error %+v, retrying in %s", err, time)
+			notification.Notify(notification.Event{Type: notification.ProviderDisconnected, Message: "Provider connection error", Provider: "docker", Error: err.Error()})
 		}
 		err := backoff.RetryNotify(safe.OperationWithRecover(operation), job.NewBackOff(backoff.NewExponentialBackOff()), notify)
 		if err != nil {
@@ -0,0 +1,162 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/sirupsen/logrus"
+)
+
+// sshDockerHost is the host of the requests sent to a Docker daemon reached through SSH,
+// the connections being dialed by the ssh command whatever the address.
+const sshDockerHost = "http://docker.ssh"
+
+// sshConnectTimeout bounds the connection of the ssh command to the host, an unreachable host blocking it otherwise.
+const sshConnectTimeout = 30 * time.Second
+
+// newSSHTransport returns a transport reaching the Docker daemon of the host of an ssh://[user@]host[:port] endpoint.
+// As the Docker CLI does, each connection runs `docker system dial-stdio` on the host through the ssh command,
+// so that the SSH configuration, agent and known hosts of the user running Traefik are used.
+func newSSHTransport(endpoint string) (*http.Transport, error) {
+	sshArgs, err := parseSSHEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	args := append(sshArgs, "docker", "system", "dial-stdio")
+	return &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return newCommandConn(ctx, "ssh", args...)
+		},
+	}, nil
+}
+
+// parseSSHEndpoint returns the arguments of the ssh command connecting to the host of the endpoint,
+// failing instead of prompting for a password or a host key confirmation, which nobody would answer.
+func parseSSHEndpoint(endpoint string) ([]string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSH endpoint %q: %v", endpoint, err)
+	}
+	if u.Scheme != "ssh" {
+		return nil, fmt.Errorf("invalid SSH endpoint %q: the scheme must be ssh", endpoint)
+	}
+	if len(u.Hostname()) == 0 {
+		return nil, fmt.Errorf("invalid SSH endpoint %q: no host", endpoint)
+	}
+	if len(u.Path) > 0 && u.Path != "/" {
+		return nil, fmt.Errorf("invalid SSH endpoint %q: unexpected path %s", endpoint, u.Path)
+	}
+
+	args := []string{"-o", fmt.Sprintf("ConnectTimeout=%d", int(sshConnectTimeout.Seconds())), "-o", "BatchMode=yes"}
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			return nil, fmt.Errorf("invalid SSH endpoint %q: passwords are not supported, use SSH keys", endpoint)
+		}
+		args = append(args, "-l", u.User.Username())
+	}
+	if len(u.Port()) > 0 {
+		args = append(args, "-p", u.Port())
+	}
+	return append(args, "--", u.Hostname()), nil
+}
+
+// commandConn is a connection through the standard input and output of a command.
+type commandConn struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	stdout    io.ReadCloser
+	stderr    *io.PipeWriter
+	closeOnce sync.Once
+}
+
+// newCommandConn runs the command, which is killed when the context is done.
+func newCommandConn(ctx context.Context, name string, args ...string) (net.Conn, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	// Forward the errors of the command, such as the SSH authentication failures, to the logs.
+	stderr := log.WriterLevel(logrus.WarnLevel)
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		stderr.Close()
+		return nil, fmt.Errorf("failed to run %s: %v", name, err)
+	}
+
+	return &commandConn{
+		cmd:    cmd,
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}, nil
+}
+
+func (c *commandConn) Read(p []byte) (int, error) {
+	return c.stdout.Read(p)
+}
+
+func (c *commandConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+// Close stops the command, which doesn't exit by itself while its input is open.
+func (c *commandConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.stdin.Close()
+		if c.cmd.Process != nil {
+			c.cmd.Process.Kill()
+		}
+		c.cmd.Wait()
+		c.stderr.Close()
+	})
+	return nil
+}
+
+func (c *commandConn) LocalAddr() net.Addr {
+	return commandAddr{}
+}
+
+func (c *commandConn) RemoteAddr() net.Addr {
+	return commandAddr{}
+}
+
+// The deadlines aren't supported by the pipes of the command: the requests to the Docker daemon rely on their context instead.
+
+func (c *commandConn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *commandConn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *commandConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+type commandAddr struct{}
+
+func (commandAddr) Network() string {
+	return "command"
+}
+
+func (commandAddr) String() string {
+	return "command"
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/cenk/backoff"
 	"github.com/containous/traefik/job"
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
 	"github.com/containous/traefik/provider"
 	"github.com/containous/traefik/provider/label"
 	"github.com/containous/traefik/safe"
@@ -38,6 +39,8 @@ type Provider struct {
 	Clusters             Clusters `description:"ECS Clusters name"`
 	Cluster              string   `description:"deprecated - ECS Cluster name"` // deprecated
 	AutoDiscoverClusters bool     `description:"Auto discover cluster" export:"true"`
+	ClusterTag           string   `description:"Auto discover the clusters with this tag, written as key=value or key" export:"true"`
+	UseServiceTags       bool     `description:"Use the tags of the ECS services in addition to the container labels" export:"true"`
 	Region               string   `description:"The AWS region to use for requests" export:"true"`
 	AccessKeyID          string   `description:"The AWS credentials access key to use for making requests"`
 	SecretAccessKey      string   `description:"The AWS credentials access key to use for making requests"`
@@ -163,6 +166,7 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 
 		notify := func(err error, time time.Duration) {
 			log.Errorf("Provider connection error %+v, retrying in %s", err, time)
+			notification.Notify(notification.Event{Type: notification.ProviderDisconnected, Message: "Provider connection error", Provider: "ecs", Error: err.Error()})
 		}
 		err := backoff.RetryNotify(safe.OperationWithRecover(operation), job.NewBackOff(backoff.NewExponentialBackOff()), notify)
 		if err != nil {
@@ -205,7 +209,7 @@ func (p *Provider) listInstances(ctx context.Context, client *awsClient) ([]ecsI
 	var clustersArn []*string
 	var clusters Clusters
 
-	if p.AutoDiscoverClusters {
+	if p.AutoDiscoverClusters || len(p.ClusterTag) > 0 {
 		input := &ecs.ListClustersInput{}
 		for {
 			result, err := client.ecs.ListClusters(input)
@@ -223,6 +227,15 @@ func (p *Provider) listInstances(ctx context.Context, client *awsClient) ([]ecsI
 			}
 		}
 		for _, cArn := range clustersArn {
+			if len(p.ClusterTag) > 0 {
+				tags, err := listTagsForResource(ctx, client, cArn)
+				if err != nil {
+					return nil, err
+				}
+				if !matchesTag(tags, p.ClusterTag) {
+					continue
+				}
+			}
 			clusters = append(clusters, *cArn)
 		}
 	} else if p.Cluster != "" {
@@ -300,6 +313,14 @@ func (p *Provider) listInstances(ctx context.Context, client *awsClient) ([]ecsI
 			return nil, err
 		}
 
+		var serviceTags map[string]map[string]*string
+		if p.UseServiceTags {
+			serviceTags, err = p.lookupServiceTags(ctx, client, &c, tasks)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		for _, task := range tasks {
 
 			machineIdx := byContainerInstance[*task.ContainerInstanceArn]
@@ -315,6 +336,7 @@ func (p *Provider) listInstances(ctx context.Context, client *awsClient) ([]ecsI
 						break
 					}
 				}
+				containerDefinition = withServiceTags(containerDefinition, serviceTags[aws.StringValue(task.Group)])
 
 				instances = append(instances, ecsInstance{
 					fmt.Sprintf("%s-%s", strings.Replace(*task.Group, ":", "-", 1), *container.Name),
@@ -0,0 +1,129 @@
+package ecs
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// servicePrefix is the prefix of the group of the tasks started by a service.
+const servicePrefix = "service:"
+
+// The vendored ECS client predates the resource tags,
+// so the ListTagsForResource operation is described here with the same JSON protocol as the other operations.
+
+type listTagsForResourceInput struct {
+	_ struct{} `type:"structure"`
+
+	ResourceArn *string `locationName:"resourceArn" type:"string" required:"true"`
+}
+
+type listTagsForResourceOutput struct {
+	_ struct{} `type:"structure"`
+
+	Tags []*resourceTag `locationName:"tags" type:"list"`
+}
+
+type resourceTag struct {
+	_ struct{} `type:"structure"`
+
+	Key   *string `locationName:"key" type:"string"`
+	Value *string `locationName:"value" type:"string"`
+}
+
+// listTagsForResource returns the tags of an ECS resource, which must use the long ARN format.
+func listTagsForResource(ctx context.Context, client *awsClient, arn *string) (map[string]*string, error) {
+	op := &request.Operation{
+		Name:       "ListTagsForResource",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+
+	output := &listTagsForResourceOutput{}
+	req := client.ecs.NewRequest(op, &listTagsForResourceInput{ResourceArn: arn}, output)
+	if err := wrapAws(ctx, req); err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]*string)
+	for _, tag := range output.Tags {
+		if tag.Key != nil {
+			tags[*tag.Key] = tag.Value
+		}
+	}
+	return tags, nil
+}
+
+// matchesTag returns whether the tags hold the tag written as key=value, or the key only when no value is given.
+func matchesTag(tags map[string]*string, tag string) bool {
+	kv := strings.SplitN(tag, "=", 2)
+
+	value, ok := tags[kv[0]]
+	if !ok {
+		return false
+	}
+	return len(kv) == 1 || aws.StringValue(value) == kv[1]
+}
+
+// lookupServiceTags returns the tags of the services of the tasks, by task group.
+func (p *Provider) lookupServiceTags(ctx context.Context, client *awsClient, clusterName *string, tasks []*ecs.Task) (map[string]map[string]*string, error) {
+	var serviceNames []*string
+	visited := make(map[string]bool)
+	for _, task := range tasks {
+		group := aws.StringValue(task.Group)
+		if strings.HasPrefix(group, servicePrefix) && !visited[group] {
+			visited[group] = true
+			serviceNames = append(serviceNames, aws.String(strings.TrimPrefix(group, servicePrefix)))
+		}
+	}
+
+	serviceTags := make(map[string]map[string]*string)
+
+	// DescribeServices accepts up to 10 services per call.
+	for i := 0; i < len(serviceNames); i += 10 {
+		end := i + 10
+		if end > len(serviceNames) {
+			end = len(serviceNames)
+		}
+
+		req, resp := client.ecs.DescribeServicesRequest(&ecs.DescribeServicesInput{
+			Cluster:  clusterName,
+			Services: serviceNames[i:end],
+		})
+		if err := wrapAws(ctx, req); err != nil {
+			return nil, err
+		}
+
+		for _, service := range resp.Services {
+			tags, err := listTagsForResource(ctx, client, service.ServiceArn)
+			if err != nil {
+				return nil, err
+			}
+			serviceTags[servicePrefix+aws.StringValue(service.ServiceName)] = tags
+		}
+	}
+
+	return serviceTags, nil
+}
+
+// withServiceTags returns a copy of the container definition which labels default to the tags of its service.
+func withServiceTags(containerDefinition *ecs.ContainerDefinition, tags map[string]*string) *ecs.ContainerDefinition {
+	if containerDefinition == nil || len(tags) == 0 {
+		return containerDefinition
+	}
+
+	labels := make(map[string]*string, len(tags)+len(containerDefinition.DockerLabels))
+	for key, value := range tags {
+		labels[key] = value
+	}
+	for key, value := range containerDefinition.DockerLabels {
+		labels[key] = value
+	}
+
+	definition := *containerDefinition
+	definition.DockerLabels = labels
+	return &definition
+}
@@ -0,0 +1,119 @@
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchesTag(t *testing.T) {
+	tags := map[string]*string{
+		"traefik": aws.String("public"),
+		"team":    aws.String(""),
+	}
+
+	testCases := []struct {
+		tag      string
+		expected bool
+	}{
+		{tag: "traefik=public", expected: true},
+		{tag: "traefik", expected: true},
+		{tag: "traefik=internal", expected: false},
+		{tag: "team", expected: true},
+		{tag: "team=", expected: true},
+		{tag: "env", expected: false},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.tag, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, matchesTag(tags, test.tag))
+		})
+	}
+}
+
+func TestWithServiceTags(t *testing.T) {
+	containerDefinition := &ecs.ContainerDefinition{
+		Name: aws.String("web"),
+		DockerLabels: map[string]*string{
+			"traefik.frontend.rule": aws.String("Host:container.example.com"),
+		},
+	}
+
+	tags := map[string]*string{
+		"traefik.frontend.rule": aws.String("Host:service.example.com"),
+		"traefik.port":          aws.String("8080"),
+	}
+
+	actual := withServiceTags(containerDefinition, tags)
+
+	expected := map[string]*string{
+		"traefik.frontend.rule": aws.String("Host:container.example.com"),
+		"traefik.port":          aws.String("8080"),
+	}
+	assert.Equal(t, expected, actual.DockerLabels)
+	assert.Equal(t, "web", aws.StringValue(actual.Name))
+
+	// The container definition is shared by the tasks of the task definition.
+	assert.Len(t, containerDefinition.DockerLabels, 1)
+
+	assert.Equal(t, containerDefinition, withServiceTags(containerDefinition, nil))
+}
+
+func TestLookupServiceTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+
+		switch req.Header.Get("X-Amz-Target") {
+		case "AmazonEC2ContainerServiceV20141113.DescribeServices":
+			assert.Equal(t, "cluster", body["cluster"])
+			assert.Equal(t, []interface{}{"web"}, body["services"])
+			rw.Write([]byte(`{"services":[{"serviceName":"web","serviceArn":"arn:aws:ecs:us-east-1:123456789012:service/cluster/web"}]}`))
+		case "AmazonEC2ContainerServiceV20141113.ListTagsForResource":
+			assert.Equal(t, "arn:aws:ecs:us-east-1:123456789012:service/cluster/web", body["resourceArn"])
+			rw.Write([]byte(`{"tags":[{"key":"traefik.port","value":"8080"}]}`))
+		default:
+			http.Error(rw, "unexpected operation", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(server.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	require.NoError(t, err)
+
+	client := &awsClient{ecs: ecs.New(sess)}
+
+	tasks := []*ecs.Task{
+		{Group: aws.String("service:web")},
+		{Group: aws.String("service:web")},
+		{Group: aws.String("family:batch")},
+	}
+
+	provider := &Provider{}
+	serviceTags, err := provider.lookupServiceTags(context.Background(), client, aws.String("cluster"), tasks)
+	require.NoError(t, err)
+
+	expected := map[string]map[string]*string{
+		"service:web": {
+			"traefik.port": aws.String("8080"),
+		},
+	}
+	assert.Equal(t, expected, serviceTags)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/containous/flaeg"
 	"github.com/containous/traefik/job"
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
 	"github.com/containous/traefik/provider"
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/types"
@@ -82,4 +83,5 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 
 func notify(err error, time time.Duration) {
 	log.Errorf("Provider connection error %+v, retrying in %s", err, time)
+	notification.Notify(notification.Event{Type: notification.ProviderDisconnected, Message: "Provider connection error", Provider: "eureka", Error: err.Error()})
 }
@@ -1,12 +1,15 @@
 package file
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/BurntSushi/toml"
 	"github.com/containous/traefik/log"
@@ -14,15 +17,52 @@ import (
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
+	"github.com/ghodss/yaml"
 	"gopkg.in/fsnotify.v1"
 )
 
 var _ provider.Provider = (*Provider)(nil)
 
+// defaultGlobs are the globs of the configuration files loaded from a directory when none is set.
+var defaultGlobs = []string{"*.toml", "*.yaml", "*.yml", "*.json"}
+
+// ParseError is an error of the parsing of a configuration file, which is skipped.
+type ParseError struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+var (
+	parseErrorsLock sync.RWMutex
+	parseErrors     []ParseError
+)
+
+// ParseErrors returns the errors of the configuration files skipped by the last load, sorted by file.
+func ParseErrors() []ParseError {
+	parseErrorsLock.RLock()
+	defer parseErrorsLock.RUnlock()
+
+	errors := make([]ParseError, len(parseErrors))
+	copy(errors, parseErrors)
+	return errors
+}
+
+func setParseErrors(errors []ParseError) {
+	sort.Slice(errors, func(i, j int) bool {
+		return errors[i].File < errors[j].File
+	})
+
+	parseErrorsLock.Lock()
+	defer parseErrorsLock.Unlock()
+
+	parseErrors = errors
+}
+
 // Provider holds configurations of the provider.
 type Provider struct {
 	provider.BaseProvider `mapstructure:",squash" export:"true"`
-	Directory             string `description:"Load configuration from one or more .toml files in a directory" export:"true"`
+	Directory             string `description:"Load configuration from one or more .toml, .yaml or .json files in a directory" export:"true"`
+	Include               Globs  `description:"Globs of the names or the relative paths of the files loaded from the directory (default: *.toml, *.yaml, *.yml and *.json)" export:"true"`
 }
 
 // Provide allows the file provider to provide configurations to traefik
@@ -56,9 +96,19 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 // and returns a 'Configuration' object
 func (p *Provider) BuildConfiguration() (*types.Configuration, error) {
 	if p.Directory != "" {
-		return loadFileConfigFromDirectory(p.Directory, nil)
+		var errors []ParseError
+		configuration, err := p.loadFileConfigFromDirectory(p.Directory, nil, &errors)
+		setParseErrors(errors)
+		return configuration, err
+	}
+
+	configuration, err := loadFileConfig(p.Filename)
+	if err != nil {
+		setParseErrors([]ParseError{{File: p.Filename, Error: err.Error()}})
+		return nil, err
 	}
-	return loadFileConfig(p.Filename)
+	setParseErrors(nil)
+	return configuration, nil
 }
 
 func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationChan chan<- types.ConfigMessage, callback func(chan<- types.ConfigMessage, fsnotify.Event)) error {
@@ -82,6 +132,14 @@ func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationCh
 						callback(configurationChan, evt)
 					}
 				} else {
+					if evt.Op&fsnotify.Create == fsnotify.Create {
+						// the sub-directories created afterwards are watched too
+						if info, err := os.Stat(evt.Name); err == nil && info.IsDir() {
+							if err := watchDirectoryTree(watcher, evt.Name); err != nil {
+								log.Errorf("Unable to watch the directory %s: %v", evt.Name, err)
+							}
+						}
+					}
 					callback(configurationChan, evt)
 				}
 			case err := <-watcher.Errors:
@@ -89,7 +147,12 @@ func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationCh
 			}
 		}
 	})
-	err = watcher.Add(directory)
+
+	if p.Directory != "" {
+		err = watchDirectoryTree(watcher, directory)
+	} else {
+		err = watcher.Add(directory)
+	}
 	if err != nil {
 		return fmt.Errorf("error adding file watcher: %s", err)
 	}
@@ -97,6 +160,19 @@ func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationCh
 	return nil
 }
 
+// watchDirectoryTree adds a directory and all its sub-directories to the watcher.
+func watchDirectoryTree(watcher *fsnotify.Watcher, directory string) error {
+	return filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
 func (p *Provider) watcherCallback(configurationChan chan<- types.ConfigMessage, event fsnotify.Event) {
 	watchItem := p.Filename
 	if p.Directory != "" {
@@ -125,18 +201,62 @@ func sendConfigToChannel(configurationChan chan<- types.ConfigMessage, configura
 	}
 }
 
+// loadFileConfig loads a configuration file, in the YAML or JSON format according to its extension,
+// in the TOML format otherwise.
 func loadFileConfig(filename string) (*types.Configuration, error) {
 	configuration := &types.Configuration{
 		Frontends: make(map[string]*types.Frontend),
 		Backends:  make(map[string]*types.Backend),
 	}
-	if _, err := toml.DecodeFile(filename, configuration); err != nil {
+
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading configuration file: %s", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		// the YAML is converted to JSON to be decoded with the JSON names of the configuration
+		if content, err = yaml.YAMLToJSON(content); err == nil {
+			err = json.Unmarshal(content, configuration)
+		}
+	case ".json":
+		err = json.Unmarshal(content, configuration)
+	default:
+		_, err = toml.DecodeReader(bytes.NewReader(content), configuration)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("error reading configuration file: %s", err)
 	}
 	return configuration, nil
 }
 
-func loadFileConfigFromDirectory(directory string, configuration *types.Configuration) (*types.Configuration, error) {
+// isIncluded returns true if the name or the path relative to the directory of the file matches one of the globs.
+func (p *Provider) isIncluded(filename string) bool {
+	globs := []string(p.Include)
+	if len(globs) == 0 {
+		globs = defaultGlobs
+	}
+
+	relativePath, err := filepath.Rel(p.Directory, filename)
+	if err != nil {
+		relativePath = filename
+	}
+
+	for _, glob := range globs {
+		if matched, _ := filepath.Match(glob, filepath.Base(filename)); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(glob, relativePath); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// loadFileConfigFromDirectory loads the included files of a directory and its sub-directories,
+// skipping the files which cannot be parsed and appending their errors.
+func (p *Provider) loadFileConfigFromDirectory(directory string, configuration *types.Configuration, errors *[]ParseError) (*types.Configuration, error) {
 	fileList, err := ioutil.ReadDir(directory)
 
 	if err != nil {
@@ -152,22 +272,25 @@ func loadFileConfigFromDirectory(directory string, configuration *types.Configur
 
 	configTLSMaps := make(map[*tls.Configuration]struct{})
 	for _, item := range fileList {
+		filename := filepath.Join(directory, item.Name())
 
 		if item.IsDir() {
-			configuration, err = loadFileConfigFromDirectory(filepath.Join(directory, item.Name()), configuration)
+			configuration, err = p.loadFileConfigFromDirectory(filename, configuration, errors)
 			if err != nil {
 				return configuration, fmt.Errorf("unable to load content configuration from subdirectory %s: %v", item, err)
 			}
 			continue
-		} else if !strings.HasSuffix(item.Name(), ".toml") {
+		} else if !p.isIncluded(filename) {
 			continue
 		}
 
 		var c *types.Configuration
-		c, err = loadFileConfig(path.Join(directory, item.Name()))
+		c, err = loadFileConfig(filename)
 
 		if err != nil {
-			return configuration, err
+			log.Errorf("Skipping the configuration file %s: %v", filename, err)
+			*errors = append(*errors, ParseError{File: filename, Error: err.Error()})
+			continue
 		}
 
 		for backendName, backend := range c.Backends {
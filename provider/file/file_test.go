@@ -12,6 +12,7 @@ import (
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProvideSingleFileAndWatch(t *testing.T) {
@@ -167,6 +168,190 @@ func TestProvideDirectoryAndNotWatch(t *testing.T) {
 
 }
 
+func TestProvideDirectoryTreeAndWatch(t *testing.T) {
+	tempDir := createTempDir(t, "testdir")
+	defer os.RemoveAll(tempDir)
+
+	expectedNumFrontends := 2
+	expectedNumBackends := 0
+	expectedNumTLSConf := 0
+
+	createRandomFile(t, tempDir, createFrontendConfiguration(expectedNumFrontends))
+
+	configurationChan, signal := createConfigurationRoutine(t, &expectedNumFrontends, &expectedNumBackends, &expectedNumTLSConf)
+
+	provide(configurationChan, watch, withDirectory(tempDir))
+
+	// Wait for initial config message to be tested
+	err := waitForSignal(signal, 2*time.Second, "initial config")
+	assert.NoError(t, err)
+
+	// Now add a backends file in a new sub-directory
+	expectedNumBackends = 0
+	tempSubDir := createSubDir(t, tempDir, "teams")
+	err = waitForSignal(signal, 2*time.Second, "create the sub-directory")
+	assert.NoError(t, err)
+
+	expectedNumBackends = 2
+	createRandomFile(t, tempSubDir, createBackendConfiguration(expectedNumBackends))
+	err = waitForSignal(signal, 2*time.Second, "add the backends file in the sub-directory")
+	assert.NoError(t, err)
+}
+
+func TestBuildConfigurationWithGlobsAndParseErrors(t *testing.T) {
+	tempDir := createTempDir(t, "testdir")
+	tempSubDir := createSubDir(t, tempDir, "teams")
+	defer os.RemoveAll(tempDir)
+
+	createFile(t, tempDir, "frontends.toml", createFrontendConfiguration(2))
+	createFile(t, tempSubDir, "backends.conf", `
+[backends.backend1.servers.server1]
+  url = "http://172.17.0.1:80"
+`)
+	createFile(t, tempSubDir, "invalid.toml", "[backends")
+	createFile(t, tempDir, "notes.txt", "[backends")
+
+	p := &Provider{Directory: tempDir, Include: Globs{"*.toml", "teams/*.conf"}}
+
+	configuration, err := p.BuildConfiguration()
+	require.NoError(t, err)
+	assert.Len(t, configuration.Frontends, 2)
+	assert.Len(t, configuration.Backends, 1)
+	assert.Equal(t, "http://172.17.0.1:80", configuration.Backends["backend1"].Servers["server1"].URL)
+
+	errors := ParseErrors()
+	require.Len(t, errors, 1)
+	assert.Equal(t, path.Join(tempSubDir, "invalid.toml"), errors[0].File)
+	assert.NotEmpty(t, errors[0].Error)
+
+	// the errors are cleared once the file is fixed
+	createFile(t, tempSubDir, "invalid.toml", "")
+	_, err = p.BuildConfiguration()
+	require.NoError(t, err)
+	assert.Empty(t, ParseErrors())
+}
+
+func TestLoadFileConfig(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		name    string
+		content string
+	}{
+		{
+			desc: "toml",
+			name: "backends.toml",
+			content: `
+[backends.backend1.servers.server1]
+  url = "http://172.17.0.1:80"
+  weight = 10
+`,
+		},
+		{
+			desc: "yaml",
+			name: "backends.yaml",
+			content: `
+backends:
+  backend1:
+    servers:
+      server1:
+        url: http://172.17.0.1:80
+        weight: 10
+`,
+		},
+		{
+			desc: "yml",
+			name: "backends.YML",
+			content: `
+backends:
+  backend1:
+    servers:
+      server1: {url: "http://172.17.0.1:80", weight: 10}
+`,
+		},
+		{
+			desc:    "json",
+			name:    "backends.json",
+			content: `{"backends": {"backend1": {"servers": {"server1": {"url": "http://172.17.0.1:80", "weight": 10}}}}}`,
+		},
+	}
+
+	tempDir := createTempDir(t, "testfile")
+	defer os.RemoveAll(tempDir)
+
+	for _, test := range testCases {
+		test := test
+		tempFile := createFile(t, tempDir, test.name, test.content)
+
+		t.Run(test.desc, func(t *testing.T) {
+			configuration, err := loadFileConfig(tempFile.Name())
+			require.NoError(t, err)
+
+			expected := map[string]*types.Backend{
+				"backend1": {
+					Servers: map[string]types.Server{
+						"server1": {URL: "http://172.17.0.1:80", Weight: 10},
+					},
+				},
+			}
+			assert.Equal(t, expected, configuration.Backends)
+			assert.Empty(t, configuration.Frontends)
+		})
+	}
+}
+
+func TestIsIncluded(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		include  Globs
+		filename string
+		expected bool
+	}{
+		{
+			desc:     "default globs",
+			filename: "/etc/traefik/teams/payments.toml",
+			expected: true,
+		},
+		{
+			desc:     "default globs with yaml file",
+			filename: "/etc/traefik/payments.yaml",
+			expected: true,
+		},
+		{
+			desc:     "default globs without configuration file",
+			filename: "/etc/traefik/README.md",
+			expected: false,
+		},
+		{
+			desc:     "name glob",
+			include:  Globs{"*.toml", "*.yaml"},
+			filename: "/etc/traefik/teams/payments.yaml",
+			expected: true,
+		},
+		{
+			desc:     "relative path glob",
+			include:  Globs{"teams/*.yml"},
+			filename: "/etc/traefik/teams/payments.yml",
+			expected: true,
+		},
+		{
+			desc:     "relative path glob in another directory",
+			include:  Globs{"teams/*.yml"},
+			filename: "/etc/traefik/payments.yml",
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Provider{Directory: "/etc/traefik", Include: test.include}
+			assert.Equal(t, test.expected, p.isIncluded(test.filename))
+		})
+	}
+}
+
 func createConfigurationRoutine(t *testing.T, expectedNumFrontends *int, expectedNumBackends *int, expectedNumTLSes *int) (chan types.ConfigMessage, chan interface{}) {
 	configurationChan := make(chan types.ConfigMessage)
 	signal := make(chan interface{})
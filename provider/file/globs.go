@@ -0,0 +1,32 @@
+package file
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Globs holds the globs of the names of the configuration files.
+type Globs []string
+
+//Set adds strings elem into the the parser
+//it splits str on , and ;
+func (g *Globs) Set(str string) error {
+	fargs := func(c rune) bool {
+		return c == ',' || c == ';'
+	}
+	// get function
+	slice := strings.FieldsFunc(str, fargs)
+	*g = append(*g, slice...)
+	return nil
+}
+
+//Get []string
+func (g *Globs) Get() interface{} { return *g }
+
+//String return slice in a string
+func (g *Globs) String() string { return fmt.Sprintf("%v", *g) }
+
+//SetValue sets []string into the parser
+func (g *Globs) SetValue(val interface{}) {
+	*g = val.(Globs)
+}
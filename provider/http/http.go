@@ -0,0 +1,223 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/provider"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+	"github.com/ghodss/yaml"
+)
+
+const (
+	formatTOML = "toml"
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+var _ provider.Provider = (*Provider)(nil)
+
+// Provider holds configurations of the provider.
+type Provider struct {
+	provider.BaseProvider `mapstructure:",squash" export:"true"`
+	Endpoint              string           `description:"URL of the dynamic configuration to fetch" export:"true"`
+	PollInterval          flaeg.Duration   `description:"Interval between two fetches of the configuration" export:"true"`
+	PollTimeout           flaeg.Duration   `description:"Timeout of a fetch of the configuration" export:"true"`
+	Format                string           `description:"Format of the configuration (toml, json or yaml), detected from the response when empty" export:"true"`
+	TLS                   *types.ClientTLS `description:"Enable TLS support" export:"true"`
+	client                *http.Client
+	etag                  string
+	lastModified          string
+	checksum              [sha256.Size]byte
+}
+
+// Provide allows the HTTP provider to provide configurations to traefik
+// using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *safe.Pool, _ types.Constraints) error {
+	if len(p.Endpoint) == 0 {
+		return errors.New("the endpoint of the HTTP provider is required")
+	}
+
+	if p.client == nil {
+		client, err := p.createClient()
+		if err != nil {
+			return err
+		}
+		p.client = client
+	}
+
+	pool.Go(func(stop chan bool) {
+		for {
+			configuration, err := p.fetchConfiguration()
+			if err != nil {
+				log.Errorf("Unable to fetch the configuration from %s: %v", p.Endpoint, err)
+			} else if configuration != nil {
+				configurationChan <- types.ConfigMessage{
+					ProviderName:  "http",
+					Configuration: configuration,
+				}
+			}
+
+			if !p.Watch {
+				return
+			}
+
+			select {
+			case <-stop:
+				return
+			case <-time.After(p.getPollInterval()):
+			}
+		}
+	})
+
+	return nil
+}
+
+func (p *Provider) createClient() (*http.Client, error) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if p.TLS != nil {
+		tlsConfig, err := p.TLS.CreateTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the TLS configuration of the HTTP provider: %v", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(p.PollTimeout),
+	}, nil
+}
+
+func (p *Provider) getPollInterval() time.Duration {
+	if p.PollInterval <= 0 {
+		return time.Second
+	}
+	return time.Duration(p.PollInterval)
+}
+
+// fetchConfiguration fetches the configuration from the endpoint,
+// and returns nil when it did not change since the previous fetch.
+func (p *Provider) fetchConfiguration() (*types.Configuration, error) {
+	req, err := http.NewRequest(http.MethodGet, p.Endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.etag) > 0 {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	if len(p.lastModified) > 0 {
+		req.Header.Set("If-Modified-Since", p.lastModified)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		log.Debugf("The configuration of %s is not modified", p.Endpoint)
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// the servers without validators, or changing them on each upload, may send the same content again
+	checksum := sha256.Sum256(body)
+	if checksum == p.checksum {
+		log.Debugf("The configuration of %s did not change", p.Endpoint)
+		p.setValidators(resp)
+		return nil, nil
+	}
+
+	configuration, err := decodeConfiguration(body, p.getFormat(resp))
+	if err != nil {
+		return nil, err
+	}
+
+	p.checksum = checksum
+	p.setValidators(resp)
+	return configuration, nil
+}
+
+func (p *Provider) setValidators(resp *http.Response) {
+	p.etag = resp.Header.Get("ETag")
+	p.lastModified = resp.Header.Get("Last-Modified")
+}
+
+// getFormat returns the format of the configuration, detected from the Content-Type of the response,
+// then from the extension of the URL, when it is not set.
+func (p *Provider) getFormat(resp *http.Response) string {
+	if len(p.Format) > 0 {
+		return strings.ToLower(p.Format)
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		switch {
+		case strings.HasSuffix(mediaType, "json"):
+			return formatJSON
+		case strings.HasSuffix(mediaType, "yaml"), strings.HasSuffix(mediaType, "yml"):
+			return formatYAML
+		case strings.HasSuffix(mediaType, "toml"):
+			return formatTOML
+		}
+	}
+
+	if endpoint, err := url.Parse(p.Endpoint); err == nil {
+		switch path.Ext(endpoint.Path) {
+		case ".json":
+			return formatJSON
+		case ".yaml", ".yml":
+			return formatYAML
+		}
+	}
+
+	return formatTOML
+}
+
+func decodeConfiguration(content []byte, format string) (*types.Configuration, error) {
+	configuration := &types.Configuration{
+		Frontends: make(map[string]*types.Frontend),
+		Backends:  make(map[string]*types.Backend),
+	}
+
+	var err error
+	switch format {
+	case formatTOML:
+		_, err = toml.DecodeReader(bytes.NewReader(content), configuration)
+	case formatJSON:
+		err = json.Unmarshal(content, configuration)
+	case formatYAML:
+		var jsonContent []byte
+		if jsonContent, err = yaml.YAMLToJSON(content); err == nil {
+			err = json.Unmarshal(jsonContent, configuration)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported configuration format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the %s configuration: %v", format, err)
+	}
+	return configuration, nil
+}
@@ -0,0 +1,219 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeConfiguration(t *testing.T) {
+	expected := &types.Configuration{
+		Frontends: map[string]*types.Frontend{
+			"frontend1": {Backend: "backend1"},
+		},
+		Backends: map[string]*types.Backend{
+			"backend1": {
+				Servers: map[string]types.Server{
+					"server1": {URL: "http://10.0.0.1:80"},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc          string
+		format        string
+		content       string
+		expected      *types.Configuration
+		expectedError string
+	}{
+		{
+			desc:   "toml",
+			format: formatTOML,
+			content: `
+[frontends.frontend1]
+  backend = "backend1"
+[backends.backend1.servers.server1]
+  url = "http://10.0.0.1:80"
+`,
+			expected: expected,
+		},
+		{
+			desc:     "json",
+			format:   formatJSON,
+			content:  `{"frontends": {"frontend1": {"backend": "backend1"}}, "backends": {"backend1": {"servers": {"server1": {"url": "http://10.0.0.1:80"}}}}}`,
+			expected: expected,
+		},
+		{
+			desc:   "yaml",
+			format: formatYAML,
+			content: `
+frontends:
+  frontend1:
+    backend: backend1
+backends:
+  backend1:
+    servers:
+      server1:
+        url: http://10.0.0.1:80
+`,
+			expected: expected,
+		},
+		{
+			desc:          "invalid json",
+			format:        formatJSON,
+			content:       `{"frontends": `,
+			expectedError: "unable to decode the json configuration: unexpected end of JSON input",
+		},
+		{
+			desc:          "unknown format",
+			format:        "xml",
+			expectedError: `unsupported configuration format "xml"`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := decodeConfiguration([]byte(test.content), test.format)
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestGetFormat(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		format      string
+		endpoint    string
+		contentType string
+		expected    string
+	}{
+		{
+			desc:     "configured format",
+			format:   "JSON",
+			endpoint: "http://127.0.0.1/traefik.yaml",
+			expected: formatJSON,
+		},
+		{
+			desc:        "json content type",
+			endpoint:    "http://127.0.0.1/traefik.toml",
+			contentType: "application/json; charset=utf-8",
+			expected:    formatJSON,
+		},
+		{
+			desc:        "yaml content type",
+			endpoint:    "http://127.0.0.1/traefik",
+			contentType: "application/x-yaml",
+			expected:    formatYAML,
+		},
+		{
+			desc:        "yml extension",
+			endpoint:    "https://bucket.s3.amazonaws.com/traefik.yml?versionId=3",
+			contentType: "binary/octet-stream",
+			expected:    formatYAML,
+		},
+		{
+			desc:     "json extension",
+			endpoint: "http://127.0.0.1/traefik.json",
+			expected: formatJSON,
+		},
+		{
+			desc:        "default",
+			endpoint:    "http://127.0.0.1/traefik",
+			contentType: "text/plain",
+			expected:    formatTOML,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Provider{Endpoint: test.endpoint, Format: test.format}
+			resp := &http.Response{Header: http.Header{}}
+			if len(test.contentType) > 0 {
+				resp.Header.Set("Content-Type", test.contentType)
+			}
+
+			assert.Equal(t, test.expected, p.getFormat(resp))
+		})
+	}
+}
+
+func TestFetchConfiguration(t *testing.T) {
+	content := `{"backends": {"backend1": {"servers": {"server1": {"url": "http://10.0.0.1:80"}}}}}`
+	etag := `"v1"`
+
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req)
+		if req.Header.Get("If-None-Match") == etag {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("ETag", etag)
+		rw.Header().Set("Last-Modified", "Mon, 15 Oct 2018 10:00:00 GMT")
+		rw.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	p := &Provider{Endpoint: server.URL}
+	client, err := p.createClient()
+	require.NoError(t, err)
+	p.client = client
+
+	configuration, err := p.fetchConfiguration()
+	require.NoError(t, err)
+	require.NotNil(t, configuration)
+	assert.Equal(t, "http://10.0.0.1:80", configuration.Backends["backend1"].Servers["server1"].URL)
+	assert.Empty(t, requests[0].Header.Get("If-None-Match"))
+
+	// not modified
+	configuration, err = p.fetchConfiguration()
+	require.NoError(t, err)
+	assert.Nil(t, configuration)
+	assert.Equal(t, etag, requests[1].Header.Get("If-None-Match"))
+	assert.Equal(t, "Mon, 15 Oct 2018 10:00:00 GMT", requests[1].Header.Get("If-Modified-Since"))
+
+	// same content with a new validator
+	etag = `"v2"`
+	configuration, err = p.fetchConfiguration()
+	require.NoError(t, err)
+	assert.Nil(t, configuration)
+	assert.Equal(t, etag, p.etag)
+
+	// new content
+	etag = `"v3"`
+	content = `{"backends": {"backend1": {"servers": {"server1": {"url": "http://10.0.0.2:80"}}}}}`
+	configuration, err = p.fetchConfiguration()
+	require.NoError(t, err)
+	require.NotNil(t, configuration)
+	assert.Equal(t, "http://10.0.0.2:80", configuration.Backends["backend1"].Servers["server1"].URL)
+}
+
+func TestFetchConfigurationUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	p := &Provider{Endpoint: server.URL, client: http.DefaultClient}
+
+	_, err := p.fetchConfiguration()
+	assert.EqualError(t, err, "unexpected status code 403")
+}
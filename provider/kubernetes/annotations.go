@@ -11,6 +11,8 @@ const (
 	annotationKubernetesAuthSecret               = "ingress.kubernetes.io/auth-secret"
 	annotationKubernetesRewriteTarget            = "ingress.kubernetes.io/rewrite-target"
 	annotationKubernetesWhitelistSourceRange     = "ingress.kubernetes.io/whitelist-source-range"
+	annotationKubernetesWhitelistDepth           = "ingress.kubernetes.io/whitelist-ip-strategy-depth"
+	annotationKubernetesWhitelistExcludedIPs     = "ingress.kubernetes.io/whitelist-ip-strategy-excluded-ips"
 	annotationKubernetesPreserveHost             = "ingress.kubernetes.io/preserve-host"
 	annotationKubernetesPassTLSCert              = "ingress.kubernetes.io/pass-tls-cert"
 	annotationKubernetesFrontendEntryPoints      = "ingress.kubernetes.io/frontend-entry-points"
@@ -30,6 +32,12 @@ const (
 	annotationKubernetesErrorPages               = "ingress.kubernetes.io/error-pages"
 	annotationKubernetesBuffering                = "ingress.kubernetes.io/buffering"
 	annotationKubernetesAppRoot                  = "ingress.kubernetes.io/app-root"
+	annotationKubernetesTenant                   = "ingress.kubernetes.io/tenant"
+	annotationKubernetesPriorityClass            = "ingress.kubernetes.io/priority-class"
+	annotationKubernetesACMEResolver             = "ingress.kubernetes.io/acme-resolver"
+	annotationKubernetesClientCASecret           = "ingress.kubernetes.io/client-ca-secret"
+	annotationKubernetesClientCAOptional         = "ingress.kubernetes.io/client-ca-optional"
+	annotationKubernetesTLSOptions               = "ingress.kubernetes.io/tls-options"
 
 	annotationKubernetesSSLRedirect             = "ingress.kubernetes.io/ssl-redirect"
 	annotationKubernetesHSTSMaxAge              = "ingress.kubernetes.io/hsts-max-age"
@@ -14,6 +14,8 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -23,10 +25,11 @@ import (
 const resyncPeriod = 10 * time.Minute
 
 const (
-	kindIngresses = "ingresses"
-	kindServices  = "services"
-	kindEndpoints = "endpoints"
-	kindSecrets   = "secrets"
+	kindIngresses  = "ingresses"
+	kindServices   = "services"
+	kindEndpoints  = "endpoints"
+	kindSecrets    = "secrets"
+	kindNamespaces = "namespaces"
 )
 
 type resourceEventHandler struct {
@@ -61,35 +64,51 @@ func (im *informerManager) extend(informer cache.SharedInformer, withSyncFunc bo
 // WatchAll starts the watch of the Provider resources and updates the stores.
 // The stores can then be accessed via the Get* functions.
 type Client interface {
-	WatchAll(namespaces Namespaces, labelSelector string, stopCh <-chan struct{}) (<-chan interface{}, error)
+	WatchAll(namespaces Namespaces, namespaceSelector, labelSelector string, stopCh <-chan struct{}) (<-chan interface{}, error)
 	GetIngresses() []*extensionsv1beta1.Ingress
 	GetService(namespace, name string) (*corev1.Service, bool, error)
 	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
+	GetSecrets() []*corev1.Secret
 	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
 }
 
 type clientImpl struct {
-	clientset      *kubernetes.Clientset
-	ingStores      []cache.Store
-	svcStores      map[string]cache.Store
-	epStores       map[string]cache.Store
-	secStores      map[string]cache.Store
-	isNamespaceAll bool
+	clientset *kubernetes.Clientset
+	// ingressClient requests the Ingresses along with their ingressClassName.
+	ingressClient rest.Interface
+	// discoveryClient requests the EndpointSlices, loaded instead of the Endpoints when endpointSlices is set.
+	discoveryClient rest.Interface
+	endpointSlices  bool
+	sliceIndexers   map[string]cache.Indexer
+	ingStores       []cache.Store
+	svcStores       map[string]cache.Store
+	epStores        map[string]cache.Store
+	secStores       map[string]cache.Store
+	isNamespaceAll  bool
+	// nsStore holds the namespaces matching the namespace selector, selected along with the static namespaces.
+	// It's nil without namespace selector, all the watched namespaces being selected then.
+	nsStore          cache.Store
+	staticNamespaces Namespaces
 }
 
-func newClientImpl(clientset *kubernetes.Clientset) Client {
+func newClientImpl(clientset *kubernetes.Clientset, ingressClient, discoveryClient rest.Interface, endpointSlices bool) Client {
 	return &clientImpl{
-		clientset: clientset,
-		ingStores: []cache.Store{},
-		svcStores: map[string]cache.Store{},
-		epStores:  map[string]cache.Store{},
-		secStores: map[string]cache.Store{},
+		clientset:       clientset,
+		ingressClient:   ingressClient,
+		discoveryClient: discoveryClient,
+		endpointSlices:  endpointSlices,
+		ingStores:       []cache.Store{},
+		svcStores:       map[string]cache.Store{},
+		epStores:        map[string]cache.Store{},
+		secStores:       map[string]cache.Store{},
+		sliceIndexers:   map[string]cache.Indexer{},
 	}
 }
 
 // NewInClusterClient returns a new Provider client that is expected to run
 // inside the cluster.
-func NewInClusterClient(endpoint string) (Client, error) {
+// With endpointSlices, the servers of the services are loaded from their EndpointSlices instead of their Endpoints.
+func NewInClusterClient(endpoint string, endpointSlices bool) (Client, error) {
 	config, err := rest.InClusterConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create in-cluster configuration: %s", err)
@@ -99,13 +118,13 @@ func NewInClusterClient(endpoint string) (Client, error) {
 		config.Host = endpoint
 	}
 
-	return createClientFromConfig(config)
+	return createClientFromConfig(config, endpointSlices)
 }
 
 // NewExternalClusterClient returns a new Provider client that may run outside
 // of the cluster.
 // The endpoint parameter must not be empty.
-func NewExternalClusterClient(endpoint, token, caFilePath string) (Client, error) {
+func NewExternalClusterClient(endpoint, token, caFilePath string, endpointSlices bool) (Client, error) {
 	if endpoint == "" {
 		return nil, errors.New("endpoint missing for external cluster client")
 	}
@@ -124,20 +143,50 @@ func NewExternalClusterClient(endpoint, token, caFilePath string) (Client, error
 		config.TLSClientConfig = rest.TLSClientConfig{CAData: caData}
 	}
 
-	return createClientFromConfig(config)
+	return createClientFromConfig(config, endpointSlices)
 }
 
-func createClientFromConfig(c *rest.Config) (Client, error) {
+func createClientFromConfig(c *rest.Config, endpointSlices bool) (Client, error) {
 	clientset, err := kubernetes.NewForConfig(c)
 	if err != nil {
 		return nil, err
 	}
 
-	return newClientImpl(clientset), nil
+	scheme := runtime.NewScheme()
+	if err := addExtensionsTypes(scheme); err != nil {
+		return nil, err
+	}
+	if err := addDiscoveryTypes(scheme); err != nil {
+		return nil, err
+	}
+
+	ingressClient, err := newRESTClient(c, scheme, extensionsGroupVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := newRESTClient(c, scheme, discoveryGroupVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClientImpl(clientset, ingressClient, discoveryClient, endpointSlices), nil
+}
+
+// newRESTClient returns a client of the API group and version, decoding the resources into the types of the scheme.
+func newRESTClient(c *rest.Config, scheme *runtime.Scheme, groupVersion schema.GroupVersion) (rest.Interface, error) {
+	config := *c
+	config.GroupVersion = &groupVersion
+	config.APIPath = "/apis"
+	config.ContentType = runtime.ContentTypeJSON
+	config.NegotiatedSerializer = serializer.DirectCodecFactory{CodecFactory: serializer.NewCodecFactory(scheme)}
+	return rest.RESTClientFor(&config)
 }
 
 // WatchAll starts namespace-specific controllers for all relevant kinds.
-func (c *clientImpl) WatchAll(namespaces Namespaces, labelSelector string, stopCh <-chan struct{}) (<-chan interface{}, error) {
+// With a namespace selector, all the namespaces are watched, and the objects are only returned
+// for the namespaces matching the selector or in the static namespaces.
+func (c *clientImpl) WatchAll(namespaces Namespaces, namespaceSelector, labelSelector string, stopCh <-chan struct{}) (<-chan interface{}, error) {
 	eventCh := make(chan interface{}, 1)
 
 	kubeLabelSelector, err := labels.Parse(labelSelector)
@@ -145,17 +194,31 @@ func (c *clientImpl) WatchAll(namespaces Namespaces, labelSelector string, stopC
 		return nil, err
 	}
 
+	var informManager informerManager
+	if len(namespaceSelector) > 0 {
+		kubeNamespaceSelector, err := labels.Parse(namespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+		informManager.extend(c.WatchNamespaces(kubeNamespaceSelector, eventCh), true)
+		c.staticNamespaces = namespaces
+		namespaces = nil
+	}
+
 	if len(namespaces) == 0 {
 		namespaces = Namespaces{metav1.NamespaceAll}
 		c.isNamespaceAll = true
 	}
 
-	var informManager informerManager
 	for _, ns := range namespaces {
 		ns := ns
 		informManager.extend(c.WatchIngresses(ns, kubeLabelSelector, eventCh), true)
 		informManager.extend(c.WatchObjects(ns, kindServices, &corev1.Service{}, c.svcStores, eventCh), true)
-		informManager.extend(c.WatchObjects(ns, kindEndpoints, &corev1.Endpoints{}, c.epStores, eventCh), true)
+		if c.endpointSlices {
+			informManager.extend(c.WatchEndpointSlices(ns, eventCh), true)
+		} else {
+			informManager.extend(c.WatchObjects(ns, kindEndpoints, &corev1.Endpoints{}, c.epStores, eventCh), true)
+		}
 		// Do not wait for the Secrets store to get synced since we cannot rely on
 		// users having granted RBAC permissions for this object.
 		// https://github.com/containous/traefik/issues/1784 should improve the
@@ -194,16 +257,60 @@ func (c *clientImpl) WatchIngresses(namespace string, labelSelector labels.Selec
 	}
 	informer := loadInformer(&cache.ListWatch{
 		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
-			return c.clientset.ExtensionsV1beta1().Ingresses(namespace).List(listOptions)
+			return c.ingressClient.Get().
+				Namespace(namespace).
+				Resource(kindIngresses).
+				VersionedParams(&listOptions, metav1.ParameterCodec).
+				Do().
+				Get()
 		},
 		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-			return c.clientset.ExtensionsV1beta1().Ingresses(namespace).Watch(listOptions)
+			watchOptions := listOptions
+			watchOptions.Watch = true
+			return c.ingressClient.Get().
+				Namespace(namespace).
+				Resource(kindIngresses).
+				VersionedParams(&watchOptions, metav1.ParameterCodec).
+				Watch()
 		},
-	}, &extensionsv1beta1.Ingress{}, watchCh)
+	}, &ingress{}, watchCh)
 	c.ingStores = append(c.ingStores, informer.GetStore())
 	return informer
 }
 
+// WatchNamespaces sets up a watch on the namespaces matching the label selector and returns a corresponding shared informer.
+func (c *clientImpl) WatchNamespaces(labelSelector labels.Selector, watchCh chan<- interface{}) cache.SharedInformer {
+	listOptions := metav1.ListOptions{
+		LabelSelector: labelSelector.String(),
+		FieldSelector: fields.Everything().String(),
+	}
+	informer := loadInformer(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.CoreV1().Namespaces().List(listOptions)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return c.clientset.CoreV1().Namespaces().Watch(listOptions)
+		},
+	}, &corev1.Namespace{}, watchCh)
+	c.nsStore = informer.GetStore()
+	return informer
+}
+
+// WatchEndpointSlices sets up a watch on the EndpointSlices of the services and returns a corresponding shared informer,
+// indexing them by service.
+func (c *clientImpl) WatchEndpointSlices(namespace string, watchCh chan<- interface{}) cache.SharedInformer {
+	listWatch := cache.NewListWatchFromClient(
+		c.discoveryClient,
+		kindEndpointSlices,
+		namespace,
+		fields.Everything())
+
+	informer := cache.NewSharedIndexInformer(listWatch, &endpointSlice{}, resyncPeriod, cache.Indexers{indexServiceName: endpointSliceServiceIndex})
+	informer.AddEventHandler(newResourceEventHandler(watchCh))
+	c.sliceIndexers[namespace] = informer.GetIndexer()
+	return informer
+}
+
 // WatchObjects sets up a watch on objects and returns a corresponding shared informer.
 func (c *clientImpl) WatchObjects(namespace, kind string, object runtime.Object, storeMap map[string]cache.Store, watchCh chan<- interface{}) cache.SharedInformer {
 	listWatch := cache.NewListWatchFromClient(
@@ -234,8 +341,10 @@ func (c *clientImpl) GetIngresses() []*extensionsv1beta1.Ingress {
 
 	for _, store := range c.ingStores {
 		for _, obj := range store.List() {
-			ing := obj.(*extensionsv1beta1.Ingress)
-			result = append(result, ing)
+			ing := obj.(*ingress)
+			if c.isSelectedNamespace(ing.Namespace) {
+				result = append(result, ing.toIngress())
+			}
 		}
 	}
 
@@ -254,7 +363,21 @@ func (c *clientImpl) GetService(namespace, name string) (*corev1.Service, bool,
 }
 
 // GetEndpoints returns the named endpoints from the given namespace.
+// With EndpointSlices, they're merged into the endpoints of the named service.
 func (c *clientImpl) GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error) {
+	if c.endpointSlices {
+		items, err := c.sliceIndexers[c.lookupNamespace(namespace)].ByIndex(indexServiceName, namespace+"/"+name)
+		if err != nil || len(items) == 0 {
+			return nil, false, err
+		}
+
+		slices := make([]*endpointSlice, 0, len(items))
+		for _, item := range items {
+			slices = append(slices, item.(*endpointSlice))
+		}
+		return endpointsFromSlices(namespace, name, slices), true, nil
+	}
+
 	var endpoint *corev1.Endpoints
 	item, exists, err := c.epStores[c.lookupNamespace(namespace)].GetByKey(namespace + "/" + name)
 
@@ -276,6 +399,37 @@ func (c *clientImpl) GetSecret(namespace, name string) (*corev1.Secret, bool, er
 	return secret, exists, err
 }
 
+// GetSecrets returns all Secrets for observed namespaces in the cluster.
+func (c *clientImpl) GetSecrets() []*corev1.Secret {
+	var result []*corev1.Secret
+
+	for _, store := range c.secStores {
+		for _, obj := range store.List() {
+			secret := obj.(*corev1.Secret)
+			if c.isSelectedNamespace(secret.Namespace) {
+				result = append(result, secret)
+			}
+		}
+	}
+
+	return result
+}
+
+// isSelectedNamespace returns whether the namespace matches the namespace selector or is one of the static namespaces,
+// all the watched namespaces being selected without namespace selector.
+func (c *clientImpl) isSelectedNamespace(namespace string) bool {
+	if c.nsStore == nil {
+		return true
+	}
+	for _, ns := range c.staticNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	_, exists, err := c.nsStore.GetByKey(namespace)
+	return err == nil && exists
+}
+
 // lookupNamespace returns the lookup namespace key for the given namespace.
 // When listening on all namespaces, it returns the client-go identifier ("")
 // for all-namespaces. Otherwise, it returns the given namespace.
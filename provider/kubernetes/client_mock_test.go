@@ -61,6 +61,10 @@ func (c clientMock) GetSecret(namespace, name string) (*corev1.Secret, bool, err
 	return nil, false, nil
 }
 
-func (c clientMock) WatchAll(namespaces Namespaces, labelString string, stopCh <-chan struct{}) (<-chan interface{}, error) {
+func (c clientMock) GetSecrets() []*corev1.Secret {
+	return c.secrets
+}
+
+func (c clientMock) WatchAll(namespaces Namespaces, namespaceSelector, labelString string, stopCh <-chan struct{}) (<-chan interface{}, error) {
 	return c.watchChan, nil
 }
@@ -0,0 +1,58 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestGetIngressesSelectedNamespaces(t *testing.T) {
+	ingStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	for _, ns := range []string{"tenant-a", "tenant-b", "kube-system", "static"} {
+		require.NoError(t, ingStore.Add(&ingress{ObjectMeta: metav1.ObjectMeta{Namespace: ns}}))
+	}
+
+	nsStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	for _, ns := range []string{"tenant-a", "tenant-b"} {
+		require.NoError(t, nsStore.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}))
+	}
+
+	testCases := []struct {
+		desc     string
+		client   *clientImpl
+		expected []string
+	}{
+		{
+			desc:     "without namespace selector",
+			client:   &clientImpl{ingStores: []cache.Store{ingStore}},
+			expected: []string{"kube-system", "static", "tenant-a", "tenant-b"},
+		},
+		{
+			desc:     "with namespace selector",
+			client:   &clientImpl{ingStores: []cache.Store{ingStore}, nsStore: nsStore},
+			expected: []string{"tenant-a", "tenant-b"},
+		},
+		{
+			desc:     "with namespace selector and static namespaces",
+			client:   &clientImpl{ingStores: []cache.Store{ingStore}, nsStore: nsStore, staticNamespaces: Namespaces{"static"}},
+			expected: []string{"static", "tenant-a", "tenant-b"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var namespaces []string
+			for _, ingress := range test.client.GetIngresses() {
+				namespaces = append(namespaces, ingress.Namespace)
+			}
+			assert.ElementsMatch(t, test.expected, namespaces)
+		})
+	}
+}
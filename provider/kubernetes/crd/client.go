@@ -0,0 +1,302 @@
+package crd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/provider/kubernetes"
+	"github.com/containous/traefik/safe"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const resyncPeriod = 10 * time.Minute
+
+const (
+	kindServices  = "services"
+	kindEndpoints = "endpoints"
+	kindSecrets   = "secrets"
+)
+
+// Client is a client for the Traefik custom resources and the Kubernetes objects they reference.
+// WatchAll starts the watch of the resources and updates the stores.
+// The stores can then be accessed via the Get* functions.
+type Client interface {
+	WatchAll(namespaces kubernetes.Namespaces, labelSelector string, stopCh <-chan struct{}) (<-chan interface{}, error)
+	GetIngressRoutes() []*IngressRoute
+	GetMiddleware(namespace, name string) (*Middleware, bool, error)
+	GetTLSOption(namespace, name string) (*TLSOption, bool, error)
+	GetService(namespace, name string) (*corev1.Service, bool, error)
+	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
+	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
+}
+
+type clientImpl struct {
+	clientset      *clientset.Clientset
+	crdClient      rest.Interface
+	stores         map[string]map[string]cache.Store
+	isNamespaceAll bool
+}
+
+// NewInClusterClient returns a new client that is expected to run inside the cluster.
+func NewInClusterClient(endpoint string) (Client, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-cluster configuration: %s", err)
+	}
+
+	if endpoint != "" {
+		config.Host = endpoint
+	}
+
+	return createClientFromConfig(config)
+}
+
+// NewExternalClusterClient returns a new client that may run outside of the cluster.
+// The endpoint parameter must not be empty.
+func NewExternalClusterClient(endpoint, token, caFilePath string) (Client, error) {
+	if endpoint == "" {
+		return nil, errors.New("endpoint missing for external cluster client")
+	}
+
+	config := &rest.Config{
+		Host:        endpoint,
+		BearerToken: token,
+	}
+
+	if caFilePath != "" {
+		caData, err := ioutil.ReadFile(caFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %s", caFilePath, err)
+		}
+
+		config.TLSClientConfig = rest.TLSClientConfig{CAData: caData}
+	}
+
+	return createClientFromConfig(config)
+}
+
+func createClientFromConfig(c *rest.Config) (Client, error) {
+	cs, err := clientset.NewForConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	if err := addKnownTypes(scheme); err != nil {
+		return nil, err
+	}
+
+	crdConfig := *c
+	crdConfig.GroupVersion = &SchemeGroupVersion
+	crdConfig.APIPath = "/apis"
+	crdConfig.ContentType = runtime.ContentTypeJSON
+	crdConfig.NegotiatedSerializer = serializer.DirectCodecFactory{CodecFactory: serializer.NewCodecFactory(scheme)}
+	crdClient, err := rest.RESTClientFor(&crdConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientImpl{
+		clientset: cs,
+		crdClient: crdClient,
+		stores:    make(map[string]map[string]cache.Store),
+	}, nil
+}
+
+// WatchAll starts namespace-specific controllers for the custom resources and the objects they reference.
+// The label selector only applies to the IngressRoutes, the other resources being referenced by their names.
+func (c *clientImpl) WatchAll(namespaces kubernetes.Namespaces, labelSelector string, stopCh <-chan struct{}) (<-chan interface{}, error) {
+	eventCh := make(chan interface{}, 1)
+
+	kubeLabelSelector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(namespaces) == 0 {
+		namespaces = kubernetes.Namespaces{metav1.NamespaceAll}
+		c.isNamespaceAll = true
+	}
+
+	var informers []cache.SharedInformer
+	var syncFuncs []cache.InformerSynced
+	for _, ns := range namespaces {
+		watched := []struct {
+			client        rest.Interface
+			kind          string
+			object        runtime.Object
+			labelSelector labels.Selector
+		}{
+			{client: c.crdClient, kind: kindIngressRoutes, object: &IngressRoute{}, labelSelector: kubeLabelSelector},
+			{client: c.crdClient, kind: kindMiddlewares, object: &Middleware{}, labelSelector: labels.Everything()},
+			{client: c.crdClient, kind: kindTLSOptions, object: &TLSOption{}, labelSelector: labels.Everything()},
+			{client: c.clientset.CoreV1().RESTClient(), kind: kindServices, object: &corev1.Service{}, labelSelector: labels.Everything()},
+			{client: c.clientset.CoreV1().RESTClient(), kind: kindEndpoints, object: &corev1.Endpoints{}, labelSelector: labels.Everything()},
+		}
+		for _, w := range watched {
+			informer := c.watchObjects(w.client, ns, w.kind, w.object, w.labelSelector, eventCh)
+			informers = append(informers, informer)
+			syncFuncs = append(syncFuncs, informer.HasSynced)
+		}
+
+		// Do not wait for the Secrets store to get synced since we cannot rely on
+		// users having granted RBAC permissions for this object.
+		informers = append(informers, c.watchObjects(c.clientset.CoreV1().RESTClient(), ns, kindSecrets, &corev1.Secret{}, labels.Everything(), eventCh))
+	}
+
+	var wg sync.WaitGroup
+	for _, informer := range informers {
+		informer := informer
+		wg.Add(1)
+		safe.Go(func() {
+			informer.Run(stopCh)
+			wg.Done()
+		})
+	}
+
+	if !cache.WaitForCacheSync(stopCh, syncFuncs...) {
+		return nil, fmt.Errorf("timed out waiting for controller caches to sync")
+	}
+
+	safe.Go(func() {
+		<-stopCh
+		wg.Wait()
+		close(eventCh)
+	})
+
+	return eventCh, nil
+}
+
+// watchObjects sets up a watch on the objects of a kind and returns a corresponding shared informer.
+func (c *clientImpl) watchObjects(client rest.Interface, namespace, kind string, object runtime.Object, labelSelector labels.Selector, watchCh chan<- interface{}) cache.SharedInformer {
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = labelSelector.String()
+			options.FieldSelector = fields.Everything().String()
+			return client.Get().
+				Namespace(namespace).
+				Resource(kind).
+				VersionedParams(&options, metav1.ParameterCodec).
+				Do().
+				Get()
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.Watch = true
+			options.LabelSelector = labelSelector.String()
+			options.FieldSelector = fields.Everything().String()
+			return client.Get().
+				Namespace(namespace).
+				Resource(kind).
+				VersionedParams(&options, metav1.ParameterCodec).
+				Watch()
+		},
+	}
+
+	informer := cache.NewSharedInformer(listWatch, object, resyncPeriod)
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { eventHandlerFunc(watchCh, obj) },
+		UpdateFunc: func(oldObj, newObj interface{}) { eventHandlerFunc(watchCh, newObj) },
+		DeleteFunc: func(obj interface{}) { eventHandlerFunc(watchCh, obj) },
+	})
+
+	if c.stores[kind] == nil {
+		c.stores[kind] = make(map[string]cache.Store)
+	}
+	c.stores[kind][namespace] = informer.GetStore()
+	return informer
+}
+
+// GetIngressRoutes returns all IngressRoutes for observed namespaces in the cluster.
+func (c *clientImpl) GetIngressRoutes() []*IngressRoute {
+	var result []*IngressRoute
+
+	for _, store := range c.stores[kindIngressRoutes] {
+		for _, obj := range store.List() {
+			result = append(result, obj.(*IngressRoute))
+		}
+	}
+
+	return result
+}
+
+// GetMiddleware returns the named Middleware from the given namespace.
+func (c *clientImpl) GetMiddleware(namespace, name string) (*Middleware, bool, error) {
+	item, exists, err := c.getObject(kindMiddlewares, namespace, name)
+	if err != nil || !exists {
+		return nil, exists, err
+	}
+	return item.(*Middleware), true, nil
+}
+
+// GetTLSOption returns the named TLSOption from the given namespace.
+func (c *clientImpl) GetTLSOption(namespace, name string) (*TLSOption, bool, error) {
+	item, exists, err := c.getObject(kindTLSOptions, namespace, name)
+	if err != nil || !exists {
+		return nil, exists, err
+	}
+	return item.(*TLSOption), true, nil
+}
+
+// GetService returns the named service from the given namespace.
+func (c *clientImpl) GetService(namespace, name string) (*corev1.Service, bool, error) {
+	item, exists, err := c.getObject(kindServices, namespace, name)
+	if err != nil || !exists {
+		return nil, exists, err
+	}
+	return item.(*corev1.Service), true, nil
+}
+
+// GetEndpoints returns the named endpoints from the given namespace.
+func (c *clientImpl) GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error) {
+	item, exists, err := c.getObject(kindEndpoints, namespace, name)
+	if err != nil || !exists {
+		return nil, exists, err
+	}
+	return item.(*corev1.Endpoints), true, nil
+}
+
+// GetSecret returns the named secret from the given namespace.
+func (c *clientImpl) GetSecret(namespace, name string) (*corev1.Secret, bool, error) {
+	item, exists, err := c.getObject(kindSecrets, namespace, name)
+	if err != nil || !exists {
+		return nil, exists, err
+	}
+	return item.(*corev1.Secret), true, nil
+}
+
+// getObject returns the named object of a kind from the given namespace,
+// which doesn't exist when the namespace isn't observed.
+func (c *clientImpl) getObject(kind, namespace, name string) (interface{}, bool, error) {
+	lookupNamespace := namespace
+	if c.isNamespaceAll {
+		lookupNamespace = metav1.NamespaceAll
+	}
+
+	store, ok := c.stores[kind][lookupNamespace]
+	if !ok {
+		return nil, false, nil
+	}
+	return store.GetByKey(namespace + "/" + name)
+}
+
+// eventHandlerFunc will pass the obj on to the events channel or drop it.
+// This is so passing the events along won't block in the case of high volume.
+// The events are only used for signalling anyway so dropping a few is ok.
+func eventHandlerFunc(events chan<- interface{}, obj interface{}) {
+	select {
+	case events <- obj:
+	default:
+	}
+}
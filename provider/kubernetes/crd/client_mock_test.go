@@ -0,0 +1,69 @@
+package crd
+
+import (
+	"github.com/containous/traefik/provider/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type clientMock struct {
+	ingressRoutes []*IngressRoute
+	middlewares   []*Middleware
+	tlsOptions    []*TLSOption
+	services      []*corev1.Service
+	endpoints     []*corev1.Endpoints
+	secrets       []*corev1.Secret
+	watchChan     chan interface{}
+}
+
+func (c clientMock) GetIngressRoutes() []*IngressRoute {
+	return c.ingressRoutes
+}
+
+func (c clientMock) GetMiddleware(namespace, name string) (*Middleware, bool, error) {
+	for _, middleware := range c.middlewares {
+		if middleware.Namespace == namespace && middleware.Name == name {
+			return middleware, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (c clientMock) GetTLSOption(namespace, name string) (*TLSOption, bool, error) {
+	for _, tlsOption := range c.tlsOptions {
+		if tlsOption.Namespace == namespace && tlsOption.Name == name {
+			return tlsOption, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (c clientMock) GetService(namespace, name string) (*corev1.Service, bool, error) {
+	for _, service := range c.services {
+		if service.Namespace == namespace && service.Name == name {
+			return service, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (c clientMock) GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error) {
+	for _, endpoints := range c.endpoints {
+		if endpoints.Namespace == namespace && endpoints.Name == name {
+			return endpoints, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (c clientMock) GetSecret(namespace, name string) (*corev1.Secret, bool, error) {
+	for _, secret := range c.secrets {
+		if secret.Namespace == namespace && secret.Name == name {
+			return secret, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (c clientMock) WatchAll(namespaces kubernetes.Namespaces, labelSelector string, stopCh <-chan struct{}) (<-chan interface{}, error) {
+	return c.watchChan, nil
+}
@@ -0,0 +1,439 @@
+package crd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenk/backoff"
+	"github.com/containous/traefik/job"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
+	"github.com/containous/traefik/provider"
+	"github.com/containous/traefik/provider/kubernetes"
+	"github.com/containous/traefik/provider/label"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var _ provider.Provider = (*Provider)(nil)
+
+const providerName = "kubernetescrd"
+
+// Provider holds configurations of the provider.
+type Provider struct {
+	provider.BaseProvider  `mapstructure:",squash" export:"true"`
+	Endpoint               string                `description:"Kubernetes server endpoint (required for external cluster client)"`
+	Token                  string                `description:"Kubernetes bearer token (not needed for in-cluster client)"`
+	CertAuthFilePath       string                `description:"Kubernetes certificate authority file path (not needed for in-cluster client)"`
+	DisablePassHostHeaders bool                  `description:"Kubernetes disable PassHost Headers" export:"true"`
+	Namespaces             kubernetes.Namespaces `description:"Kubernetes namespaces" export:"true"`
+	LabelSelector          string                `description:"Kubernetes label selector of the IngressRoutes to use" export:"true"`
+	lastConfiguration      safe.Safe
+}
+
+func (p *Provider) newK8sClient() (Client, error) {
+	withEndpoint := ""
+	if p.Endpoint != "" {
+		withEndpoint = fmt.Sprintf(" with endpoint %v", p.Endpoint)
+	}
+
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" && os.Getenv("KUBERNETES_SERVICE_PORT") != "" {
+		log.Infof("Creating in-cluster Provider client%s", withEndpoint)
+		return NewInClusterClient(p.Endpoint)
+	}
+
+	log.Infof("Creating cluster-external Provider client%s", withEndpoint)
+	return NewExternalClusterClient(p.Endpoint, p.Token, p.CertAuthFilePath)
+}
+
+// Provide allows the Kubernetes CRD provider to provide configurations to traefik
+// using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *safe.Pool, constraints types.Constraints) error {
+	// Tell glog (used by client-go) to log into STDERR. Otherwise, we risk
+	// certain kinds of API errors getting logged into a directory not
+	// available in a `FROM scratch` Docker container, causing glog to abort
+	// hard with an exit code > 0.
+	err := flag.Set("logtostderr", "true")
+	if err != nil {
+		return err
+	}
+
+	if _, err := labels.Parse(p.LabelSelector); err != nil {
+		return fmt.Errorf("invalid label selector %q: %v", p.LabelSelector, err)
+	}
+
+	k8sClient, err := p.newK8sClient()
+	if err != nil {
+		return err
+	}
+	p.Constraints = append(p.Constraints, constraints...)
+
+	pool.Go(func(stop chan bool) {
+		operation := func() error {
+			stopWatch := make(chan struct{}, 1)
+			defer close(stopWatch)
+			log.Debugf("Using label selector: '%s'", p.LabelSelector)
+			eventsChan, err := k8sClient.WatchAll(p.Namespaces, p.LabelSelector, stopWatch)
+			if err != nil {
+				log.Errorf("Error watching kubernetes events: %v", err)
+				timer := time.NewTimer(1 * time.Second)
+				select {
+				case <-timer.C:
+					return err
+				case <-stop:
+					return nil
+				}
+			}
+			for {
+				select {
+				case <-stop:
+					return nil
+				case event := <-eventsChan:
+					log.Debugf("Received Kubernetes event kind %T", event)
+					configuration := p.loadConfiguration(k8sClient)
+					if reflect.DeepEqual(p.lastConfiguration.Get(), configuration) {
+						log.Debugf("Skipping Kubernetes event kind %T", event)
+					} else {
+						p.lastConfiguration.Set(configuration)
+						configurationChan <- types.ConfigMessage{
+							ProviderName:  providerName,
+							Configuration: configuration,
+						}
+					}
+				}
+			}
+		}
+
+		notify := func(err error, time time.Duration) {
+			log.Errorf("Provider connection error: %s; retrying in %s", err, time)
+			notification.Notify(notification.Event{Type: notification.ProviderDisconnected, Message: "Provider connection error", Provider: providerName, Error: err.Error()})
+		}
+		err := backoff.RetryNotify(safe.OperationWithRecover(operation), job.NewBackOff(backoff.NewExponentialBackOff()), notify)
+		if err != nil {
+			log.Errorf("Cannot connect to Provider: %s", err)
+		}
+	})
+
+	return nil
+}
+
+// loadConfiguration returns a frontend and a backend for each route of the IngressRoutes,
+// named after the namespace and the name of the IngressRoute and the index of the route.
+// The routes with an invalid service or middleware are skipped.
+func (p *Provider) loadConfiguration(k8sClient Client) *types.Configuration {
+	configuration := &types.Configuration{
+		Backends:   map[string]*types.Backend{},
+		Frontends:  map[string]*types.Frontend{},
+		TLSOptions: map[string]*tls.Options{},
+	}
+
+	ingressRoutes := k8sClient.GetIngressRoutes()
+	sort.Slice(ingressRoutes, func(i, j int) bool {
+		if ingressRoutes[i].Namespace != ingressRoutes[j].Namespace {
+			return ingressRoutes[i].Namespace < ingressRoutes[j].Namespace
+		}
+		return ingressRoutes[i].Name < ingressRoutes[j].Name
+	})
+
+	for _, ingressRoute := range ingressRoutes {
+		var tlsOptions string
+		if ingressRoute.Spec.TLS != nil {
+			if len(ingressRoute.Spec.TLS.SecretName) > 0 {
+				certificate, err := getCertificate(ingressRoute.Namespace, ingressRoute.Spec.TLS.SecretName, k8sClient)
+				if err != nil {
+					log.Errorf("Error configuring TLS for IngressRoute %s/%s: %v", ingressRoute.Namespace, ingressRoute.Name, err)
+					continue
+				}
+				configuration.TLS = append(configuration.TLS, &tls.Configuration{
+					EntryPoints: ingressRoute.Spec.EntryPoints,
+					Certificate: certificate,
+				})
+			}
+
+			name, options, err := getTLSOptions(ingressRoute.Namespace, ingressRoute.Spec.TLS.Options, k8sClient)
+			if err != nil {
+				log.Errorf("Error configuring TLS for IngressRoute %s/%s: %v", ingressRoute.Namespace, ingressRoute.Name, err)
+				continue
+			}
+			if options != nil {
+				configuration.TLSOptions[name] = options
+			}
+			tlsOptions = name
+		}
+
+		for i, route := range ingressRoute.Spec.Routes {
+			name := fmt.Sprintf("%s/%s/%d", ingressRoute.Namespace, ingressRoute.Name, i)
+
+			if len(route.Match) == 0 {
+				log.Errorf("Route %d of IngressRoute %s/%s has no match rule", i, ingressRoute.Namespace, ingressRoute.Name)
+				continue
+			}
+
+			servers, err := getServers(ingressRoute.Namespace, route.Services, k8sClient)
+			if err != nil {
+				log.Errorf("Error configuring the services of route %d of IngressRoute %s/%s: %v", i, ingressRoute.Namespace, ingressRoute.Name, err)
+				continue
+			}
+
+			frontend := &types.Frontend{
+				EntryPoints:    ingressRoute.Spec.EntryPoints,
+				Backend:        name,
+				Routes:         map[string]types.Route{"match": {Rule: route.Match}},
+				PassHostHeader: !p.DisablePassHostHeaders,
+				Priority:       route.Priority,
+				TLSOptions:     tlsOptions,
+			}
+			if ingressRoute.Spec.TLS != nil {
+				frontend.TLSPassthrough = ingressRoute.Spec.TLS.Passthrough
+			}
+
+			if err := applyMiddlewares(frontend, ingressRoute.Namespace, route.Middlewares, k8sClient); err != nil {
+				log.Errorf("Error configuring the middlewares of route %d of IngressRoute %s/%s: %v", i, ingressRoute.Namespace, ingressRoute.Name, err)
+				continue
+			}
+
+			configuration.Frontends[name] = frontend
+			configuration.Backends[name] = &types.Backend{
+				Servers:      servers,
+				LoadBalancer: &types.LoadBalancer{Method: "wrr"},
+			}
+		}
+	}
+
+	return configuration
+}
+
+// getCertificate returns the certificate of the tls.crt and tls.key data entries of a secret.
+func getCertificate(namespace, secretName string, k8sClient Client) (*tls.Certificate, error) {
+	secret, exists, err := k8sClient.GetSecret(namespace, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret %s/%s: %v", namespace, secretName, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("secret %s/%s does not exist", namespace, secretName)
+	}
+
+	tlsCrtData, tlsCrtExists := secret.Data["tls.crt"]
+	tlsKeyData, tlsKeyExists := secret.Data["tls.key"]
+	if !tlsCrtExists || !tlsKeyExists {
+		return nil, fmt.Errorf("secret %s/%s is missing the tls.crt or tls.key entry", namespace, secretName)
+	}
+
+	return &tls.Certificate{
+		CertFile: tls.FileOrContent(tlsCrtData),
+		KeyFile:  tls.FileOrContent(tlsKeyData),
+	}, nil
+}
+
+// getTLSOptions returns the name and the TLS options of a TLSOption of the namespace,
+// named namespace/name in the configuration, or the name alone when the namespace has no such TLSOption:
+// the TLS options are then the ones defined by another provider.
+func getTLSOptions(namespace, name string, k8sClient Client) (string, *tls.Options, error) {
+	if len(name) == 0 {
+		return "", nil, nil
+	}
+
+	tlsOption, exists, err := k8sClient.GetTLSOption(namespace, name)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch TLSOption %s/%s: %v", namespace, name, err)
+	}
+	if !exists {
+		return name, nil, nil
+	}
+
+	options := tlsOption.Spec
+	return namespace + "/" + name, &options, nil
+}
+
+// getServers returns the servers of the endpoints of the services, sharing the weight of their service
+// so that each service receives a share of the requests proportional to its weight, 1 by default.
+func getServers(namespace string, services []Service, k8sClient Client) (map[string]types.Server, error) {
+	if len(services) == 0 {
+		return nil, errors.New("no service")
+	}
+
+	serviceURLs := make([]map[string]string, len(services))
+	commonMultiple := 1
+	for i, svc := range services {
+		urls, err := getServiceURLs(namespace, svc, k8sClient)
+		if err != nil {
+			return nil, err
+		}
+		serviceURLs[i] = urls
+		if len(urls) > 0 {
+			commonMultiple = lcm(commonMultiple, len(urls))
+		}
+	}
+
+	servers := make(map[string]types.Server)
+	for i, svc := range services {
+		weight := svc.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		for name, url := range serviceURLs[i] {
+			servers[svc.Name+"/"+name] = types.Server{
+				URL:    url,
+				Weight: weight * commonMultiple / len(serviceURLs[i]),
+			}
+		}
+	}
+	return servers, nil
+}
+
+// getServiceURLs returns the URLs of the endpoints of a service port, by pod name when known.
+func getServiceURLs(namespace string, svc Service, k8sClient Client) (map[string]string, error) {
+	service, exists, err := k8sClient.GetService(namespace, svc.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch service %s/%s: %v", namespace, svc.Name, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("service %s/%s does not exist", namespace, svc.Name)
+	}
+
+	var servicePort *corev1.ServicePort
+	for i, port := range service.Spec.Ports {
+		if port.Port == svc.Port {
+			servicePort = &service.Spec.Ports[i]
+			break
+		}
+	}
+	if servicePort == nil {
+		return nil, fmt.Errorf("service %s/%s has no port %d", namespace, svc.Name, svc.Port)
+	}
+
+	protocol := label.DefaultProtocol
+	if servicePort.Port == 443 {
+		protocol = "https"
+	}
+
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		url := protocol + "://" + service.Spec.ExternalName + ":" + strconv.Itoa(int(servicePort.Port))
+		return map[string]string{url: url}, nil
+	}
+
+	endpoints, exists, err := k8sClient.GetEndpoints(namespace, svc.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch endpoints %s/%s: %v", namespace, svc.Name, err)
+	}
+	if !exists || len(endpoints.Subsets) == 0 {
+		log.Warnf("Endpoints not available for %s/%s", namespace, svc.Name)
+		return nil, nil
+	}
+
+	urls := make(map[string]string)
+	for _, subset := range endpoints.Subsets {
+		// the name of the endpoint port is optional if there is only one port
+		port := int(servicePort.Port)
+		if len(subset.Ports) > 0 {
+			port = int(subset.Ports[0].Port)
+		}
+		for _, endpointPort := range subset.Ports {
+			if endpointPort.Name == servicePort.Name {
+				port = int(endpointPort.Port)
+			}
+		}
+
+		for _, address := range subset.Addresses {
+			url := protocol + "://" + address.IP + ":" + strconv.Itoa(port)
+			name := url
+			if address.TargetRef != nil && address.TargetRef.Name != "" {
+				name = address.TargetRef.Name
+			}
+			urls[name] = url
+		}
+	}
+	return urls, nil
+}
+
+// applyMiddlewares sets the frontend options of the middlewares, in order: a middleware overrides the options set by the previous ones.
+func applyMiddlewares(frontend *types.Frontend, namespace string, refs []MiddlewareRef, k8sClient Client) error {
+	for _, ref := range refs {
+		middlewareNamespace := ref.Namespace
+		if len(middlewareNamespace) == 0 {
+			middlewareNamespace = namespace
+		}
+
+		middleware, exists, err := k8sClient.GetMiddleware(middlewareNamespace, ref.Name)
+		if err != nil {
+			return fmt.Errorf("failed to fetch middleware %s/%s: %v", middlewareNamespace, ref.Name, err)
+		}
+		if !exists {
+			return fmt.Errorf("middleware %s/%s does not exist", middlewareNamespace, ref.Name)
+		}
+
+		spec := middleware.Spec
+		if spec.Headers != nil {
+			frontend.Headers = spec.Headers
+		}
+		if spec.Redirect != nil {
+			frontend.Redirect = spec.Redirect
+		}
+		if spec.RateLimit != nil {
+			frontend.RateLimit = spec.RateLimit
+		}
+		if spec.Whitelist != nil {
+			frontend.WhitelistSourceRange = spec.Whitelist.SourceRange
+			frontend.WhitelistIPStrategy = spec.Whitelist.IPStrategy
+		}
+		if spec.BasicAuth != nil {
+			users, err := getBasicAuthUsers(middlewareNamespace, spec.BasicAuth.Secret, k8sClient)
+			if err != nil {
+				return fmt.Errorf("invalid basic auth of middleware %s/%s: %v", middlewareNamespace, ref.Name, err)
+			}
+			frontend.BasicAuth = users
+		}
+	}
+	return nil
+}
+
+// getBasicAuthUsers returns the users of the single data entry of a secret, one per line.
+func getBasicAuthUsers(namespace, secretName string, k8sClient Client) ([]string, error) {
+	secret, exists, err := k8sClient.GetSecret(namespace, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret %s/%s: %v", namespace, secretName, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("secret %s/%s does not exist", namespace, secretName)
+	}
+	if len(secret.Data) != 1 {
+		return nil, fmt.Errorf("found %d elements for secret %s/%s, must be single element exactly", len(secret.Data), namespace, secretName)
+	}
+
+	var users []string
+	for _, data := range secret.Data {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			if user := strings.TrimSpace(scanner.Text()); len(user) > 0 {
+				users = append(users, user)
+			}
+		}
+	}
+	if len(users) == 0 {
+		return nil, fmt.Errorf("secret %s/%s does not contain any user", namespace, secretName)
+	}
+	return users, nil
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func lcm(a, b int) int {
+	return a / gcd(a, b) * b
+}
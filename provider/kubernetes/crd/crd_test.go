@@ -0,0 +1,373 @@
+package crd
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+func buildService(name string, port int32, ips ...string) (*corev1.Service, *corev1.Endpoints) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: name},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "http", Port: port}},
+		},
+	}
+
+	subset := corev1.EndpointSubset{Ports: []corev1.EndpointPort{{Name: "http", Port: 8080}}}
+	for _, ip := range ips {
+		subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: ip})
+	}
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: name},
+		Subsets:    []corev1.EndpointSubset{subset},
+	}
+	return service, endpoints
+}
+
+func TestLoadConfiguration(t *testing.T) {
+	whoami, whoamiEndpoints := buildService("whoami", 80, "10.10.0.1", "10.10.0.2")
+	canary, canaryEndpoints := buildService("canary", 80, "10.10.0.3")
+
+	testCases := []struct {
+		desc          string
+		ingressRoutes []*IngressRoute
+		middlewares   []*Middleware
+		tlsOptions    []*TLSOption
+		secrets       []*corev1.Secret
+		expected      *types.Configuration
+	}{
+		{
+			desc: "simple route",
+			ingressRoutes: []*IngressRoute{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "test"},
+				Spec: IngressRouteSpec{
+					EntryPoints: []string{"web"},
+					Routes: []Route{{
+						Match:    "Host:foo.com;PathPrefix:/bar",
+						Priority: 12,
+						Services: []Service{{Name: "whoami", Port: 80}},
+					}},
+				},
+			}},
+			expected: &types.Configuration{
+				Backends: map[string]*types.Backend{
+					"testing/test/0": {
+						Servers: map[string]types.Server{
+							"whoami/http://10.10.0.1:8080": {URL: "http://10.10.0.1:8080", Weight: 1},
+							"whoami/http://10.10.0.2:8080": {URL: "http://10.10.0.2:8080", Weight: 1},
+						},
+						LoadBalancer: &types.LoadBalancer{Method: "wrr"},
+					},
+				},
+				Frontends: map[string]*types.Frontend{
+					"testing/test/0": {
+						EntryPoints:    []string{"web"},
+						Backend:        "testing/test/0",
+						Routes:         map[string]types.Route{"match": {Rule: "Host:foo.com;PathPrefix:/bar"}},
+						PassHostHeader: true,
+						Priority:       12,
+					},
+				},
+				TLSOptions: map[string]*tls.Options{},
+			},
+		},
+		{
+			desc: "weighted services",
+			ingressRoutes: []*IngressRoute{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "test"},
+				Spec: IngressRouteSpec{
+					Routes: []Route{{
+						Match: "Host:foo.com",
+						Services: []Service{
+							{Name: "whoami", Port: 80, Weight: 3},
+							{Name: "canary", Port: 80},
+						},
+					}},
+				},
+			}},
+			expected: &types.Configuration{
+				Backends: map[string]*types.Backend{
+					"testing/test/0": {
+						Servers: map[string]types.Server{
+							"whoami/http://10.10.0.1:8080": {URL: "http://10.10.0.1:8080", Weight: 3},
+							"whoami/http://10.10.0.2:8080": {URL: "http://10.10.0.2:8080", Weight: 3},
+							"canary/http://10.10.0.3:8080": {URL: "http://10.10.0.3:8080", Weight: 2},
+						},
+						LoadBalancer: &types.LoadBalancer{Method: "wrr"},
+					},
+				},
+				Frontends: map[string]*types.Frontend{
+					"testing/test/0": {
+						Backend:        "testing/test/0",
+						Routes:         map[string]types.Route{"match": {Rule: "Host:foo.com"}},
+						PassHostHeader: true,
+					},
+				},
+				TLSOptions: map[string]*tls.Options{},
+			},
+		},
+		{
+			desc: "middlewares and TLS",
+			ingressRoutes: []*IngressRoute{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "test"},
+				Spec: IngressRouteSpec{
+					EntryPoints: []string{"websecure"},
+					Routes: []Route{{
+						Match: "Host:foo.com",
+						Middlewares: []MiddlewareRef{
+							{Name: "headers"},
+							{Name: "auth", Namespace: "shared"},
+						},
+						Services: []Service{{Name: "canary", Port: 80}},
+					}},
+					TLS: &TLS{SecretName: "foo-cert", Options: "modern"},
+				},
+			}},
+			middlewares: []*Middleware{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "headers"},
+					Spec: MiddlewareSpec{
+						Headers:   &types.Headers{CustomResponseHeaders: map[string]string{"X-Foo": "bar"}},
+						Whitelist: &Whitelist{SourceRange: []string{"10.0.0.0/8"}},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "shared", Name: "auth"},
+					Spec:       MiddlewareSpec{BasicAuth: &BasicAuth{Secret: "users"}},
+				},
+			},
+			tlsOptions: []*TLSOption{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "modern"},
+				Spec:       tls.Options{MinVersion: "VersionTLS12"},
+			}},
+			secrets: []*corev1.Secret{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "foo-cert"},
+					Data:       map[string][]byte{"tls.crt": []byte("CERT"), "tls.key": []byte("KEY")},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "shared", Name: "users"},
+					Data:       map[string][]byte{"auth": []byte("foo:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/\n\n")},
+				},
+			},
+			expected: &types.Configuration{
+				Backends: map[string]*types.Backend{
+					"testing/test/0": {
+						Servers: map[string]types.Server{
+							"canary/http://10.10.0.3:8080": {URL: "http://10.10.0.3:8080", Weight: 1},
+						},
+						LoadBalancer: &types.LoadBalancer{Method: "wrr"},
+					},
+				},
+				Frontends: map[string]*types.Frontend{
+					"testing/test/0": {
+						EntryPoints:          []string{"websecure"},
+						Backend:              "testing/test/0",
+						Routes:               map[string]types.Route{"match": {Rule: "Host:foo.com"}},
+						PassHostHeader:       true,
+						Headers:              &types.Headers{CustomResponseHeaders: map[string]string{"X-Foo": "bar"}},
+						WhitelistSourceRange: []string{"10.0.0.0/8"},
+						BasicAuth:            []string{"foo:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"},
+						TLSOptions:           "testing/modern",
+					},
+				},
+				TLS: []*tls.Configuration{{
+					EntryPoints: []string{"websecure"},
+					Certificate: &tls.Certificate{CertFile: tls.FileOrContent("CERT"), KeyFile: tls.FileOrContent("KEY")},
+				}},
+				TLSOptions: map[string]*tls.Options{"testing/modern": {MinVersion: "VersionTLS12"}},
+			},
+		},
+		{
+			desc: "TLS options of another provider",
+			ingressRoutes: []*IngressRoute{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "test"},
+				Spec: IngressRouteSpec{
+					Routes: []Route{{Match: "Host:foo.com", Services: []Service{{Name: "canary", Port: 80}}}},
+					TLS:    &TLS{Options: "modern", Passthrough: true},
+				},
+			}},
+			expected: &types.Configuration{
+				Backends: map[string]*types.Backend{
+					"testing/test/0": {
+						Servers: map[string]types.Server{
+							"canary/http://10.10.0.3:8080": {URL: "http://10.10.0.3:8080", Weight: 1},
+						},
+						LoadBalancer: &types.LoadBalancer{Method: "wrr"},
+					},
+				},
+				Frontends: map[string]*types.Frontend{
+					"testing/test/0": {
+						Backend:        "testing/test/0",
+						Routes:         map[string]types.Route{"match": {Rule: "Host:foo.com"}},
+						PassHostHeader: true,
+						TLSOptions:     "modern",
+						TLSPassthrough: true,
+					},
+				},
+				TLSOptions: map[string]*tls.Options{},
+			},
+		},
+		{
+			desc: "invalid routes are skipped",
+			ingressRoutes: []*IngressRoute{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "test"},
+				Spec: IngressRouteSpec{
+					Routes: []Route{
+						{Match: "Host:foo.com", Services: []Service{{Name: "canary", Port: 80}}, Middlewares: []MiddlewareRef{{Name: "missing"}}},
+						{Match: "Host:bar.com", Services: []Service{{Name: "missing", Port: 80}}},
+						{Match: "Host:baz.com", Services: []Service{{Name: "canary", Port: 443}}},
+						{Services: []Service{{Name: "canary", Port: 80}}},
+					},
+				},
+			}},
+			expected: &types.Configuration{
+				Backends:   map[string]*types.Backend{},
+				Frontends:  map[string]*types.Frontend{},
+				TLSOptions: map[string]*tls.Options{},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			client := clientMock{
+				ingressRoutes: test.ingressRoutes,
+				middlewares:   test.middlewares,
+				tlsOptions:    test.tlsOptions,
+				services:      []*corev1.Service{whoami, canary},
+				endpoints:     []*corev1.Endpoints{whoamiEndpoints, canaryEndpoints},
+				secrets:       test.secrets,
+			}
+
+			provider := &Provider{}
+			assert.Equal(t, test.expected, provider.loadConfiguration(client))
+		})
+	}
+}
+
+func TestDecodeIngressRoute(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, addKnownTypes(scheme))
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	object, _, err := decoder.Decode([]byte(`{
+  "apiVersion": "traefik.containo.us/v1alpha1",
+  "kind": "IngressRoute",
+  "metadata": {"namespace": "testing", "name": "test"},
+  "spec": {
+    "entryPoints": ["web"],
+    "routes": [{
+      "match": "Host:foo.com",
+      "middlewares": [{"name": "headers"}],
+      "services": [{"name": "whoami", "port": 80, "weight": 2}]
+    }]
+  }
+}`), nil, nil)
+	require.NoError(t, err)
+	require.IsType(t, &IngressRoute{}, object)
+
+	ingressRoute := object.(*IngressRoute)
+	assert.Equal(t, "testing", ingressRoute.Namespace)
+	assert.Equal(t, IngressRouteSpec{
+		EntryPoints: []string{"web"},
+		Routes: []Route{{
+			Match:       "Host:foo.com",
+			Middlewares: []MiddlewareRef{{Name: "headers"}},
+			Services:    []Service{{Name: "whoami", Port: 80, Weight: 2}},
+		}},
+	}, ingressRoute.Spec)
+	assert.Equal(t, ingressRoute, ingressRoute.DeepCopyObject())
+}
+
+func TestDeepCopyObject(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		object runtime.Object
+	}{
+		{
+			desc: "IngressRoute",
+			object: &IngressRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "route", Labels: map[string]string{"app": "whoami"}},
+				Spec: IngressRouteSpec{
+					EntryPoints: []string{"web"},
+					Routes: []Route{{
+						Match:       "Host:example.com",
+						Middlewares: []MiddlewareRef{{Name: "headers"}},
+						Services:    []Service{{Name: "whoami", Port: 80}},
+					}},
+					TLS: &TLS{SecretName: "certificate"},
+				},
+			},
+		},
+		{
+			desc: "MiddlewareList",
+			object: &MiddlewareList{
+				Items: []Middleware{{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "middleware"},
+					Spec: MiddlewareSpec{
+						Headers:   &types.Headers{CustomRequestHeaders: map[string]string{"X-Test": "test"}, AllowedHosts: []string{"example.com"}},
+						Redirect:  &types.Redirect{EntryPoint: "websecure"},
+						RateLimit: &types.RateLimit{RateSet: map[string]*types.Rate{"default": {Average: 10, Burst: 20}}},
+						Whitelist: &Whitelist{SourceRange: []string{"10.0.0.0/8"}, IPStrategy: &types.IPStrategy{ExcludedIPs: []string{"10.0.0.1"}}},
+						BasicAuth: &BasicAuth{Secret: "users"},
+					},
+				}},
+			},
+		},
+		{
+			desc: "TLSOptionList",
+			object: &TLSOptionList{
+				Items: []TLSOption{{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "options"},
+					Spec:       tls.Options{MinVersion: "VersionTLS12", CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}},
+				}},
+			},
+		},
+		{
+			desc:   "empty IngressRouteList",
+			object: &IngressRouteList{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			copied := test.object.DeepCopyObject()
+			assert.Equal(t, test.object, copied)
+			assert.True(t, test.object != copied, "object not copied")
+		})
+	}
+}
+
+func TestDeepCopyObjectIsolation(t *testing.T) {
+	middleware := &Middleware{
+		Spec: MiddlewareSpec{
+			Headers:   &types.Headers{CustomRequestHeaders: map[string]string{"X-Test": "test"}},
+			RateLimit: &types.RateLimit{RateSet: map[string]*types.Rate{"default": {Average: 10}}},
+			Whitelist: &Whitelist{SourceRange: []string{"10.0.0.0/8"}},
+		},
+	}
+
+	copied := middleware.DeepCopyObject().(*Middleware)
+	copied.Spec.Headers.CustomRequestHeaders["X-Test"] = "changed"
+	copied.Spec.RateLimit.RateSet["default"].Average = 20
+	copied.Spec.Whitelist.SourceRange[0] = "192.168.0.0/16"
+
+	assert.Equal(t, "test", middleware.Spec.Headers.CustomRequestHeaders["X-Test"])
+	assert.EqualValues(t, 10, middleware.Spec.RateLimit.RateSet["default"].Average)
+	assert.Equal(t, "10.0.0.0/8", middleware.Spec.Whitelist.SourceRange[0])
+}
@@ -0,0 +1,274 @@
+package crd
+
+import (
+	"github.com/containous/traefik/types"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto copies the IngressRoute into out.
+func (in *IngressRoute) DeepCopyInto(out *IngressRoute) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy returns a copy of the IngressRoute.
+func (in *IngressRoute) DeepCopy() *IngressRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *IngressRoute) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies the IngressRouteSpec into out.
+func (in *IngressRouteSpec) DeepCopyInto(out *IngressRouteSpec) {
+	*out = *in
+	out.EntryPoints = copyStrings(in.EntryPoints)
+	if in.Routes != nil {
+		out.Routes = make([]Route, len(in.Routes))
+		for i := range in.Routes {
+			in.Routes[i].DeepCopyInto(&out.Routes[i])
+		}
+	}
+	if in.TLS != nil {
+		out.TLS = new(TLS)
+		*out.TLS = *in.TLS
+	}
+}
+
+// DeepCopyInto copies the Route into out.
+func (in *Route) DeepCopyInto(out *Route) {
+	*out = *in
+	if in.Middlewares != nil {
+		out.Middlewares = make([]MiddlewareRef, len(in.Middlewares))
+		copy(out.Middlewares, in.Middlewares)
+	}
+	if in.Services != nil {
+		out.Services = make([]Service, len(in.Services))
+		copy(out.Services, in.Services)
+	}
+}
+
+// DeepCopyInto copies the IngressRouteList into out.
+func (in *IngressRouteList) DeepCopyInto(out *IngressRouteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]IngressRoute, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a copy of the IngressRouteList.
+func (in *IngressRouteList) DeepCopy() *IngressRouteList {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressRouteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *IngressRouteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies the Middleware into out.
+func (in *Middleware) DeepCopyInto(out *Middleware) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy returns a copy of the Middleware.
+func (in *Middleware) DeepCopy() *Middleware {
+	if in == nil {
+		return nil
+	}
+	out := new(Middleware)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *Middleware) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies the MiddlewareSpec into out.
+func (in *MiddlewareSpec) DeepCopyInto(out *MiddlewareSpec) {
+	*out = *in
+	if in.Headers != nil {
+		out.Headers = new(types.Headers)
+		*out.Headers = *in.Headers
+		out.Headers.CustomRequestHeaders = copyStringMap(in.Headers.CustomRequestHeaders)
+		out.Headers.CustomResponseHeaders = copyStringMap(in.Headers.CustomResponseHeaders)
+		out.Headers.AllowedHosts = copyStrings(in.Headers.AllowedHosts)
+		out.Headers.HostsProxyHeaders = copyStrings(in.Headers.HostsProxyHeaders)
+		out.Headers.SSLProxyHeaders = copyStringMap(in.Headers.SSLProxyHeaders)
+	}
+	if in.Redirect != nil {
+		out.Redirect = new(types.Redirect)
+		*out.Redirect = *in.Redirect
+	}
+	if in.RateLimit != nil {
+		out.RateLimit = new(types.RateLimit)
+		*out.RateLimit = *in.RateLimit
+		if in.RateLimit.RateSet != nil {
+			out.RateLimit.RateSet = make(map[string]*types.Rate, len(in.RateLimit.RateSet))
+			for name, rate := range in.RateLimit.RateSet {
+				if rate == nil {
+					out.RateLimit.RateSet[name] = nil
+					continue
+				}
+				outRate := *rate
+				out.RateLimit.RateSet[name] = &outRate
+			}
+		}
+	}
+	if in.Whitelist != nil {
+		out.Whitelist = new(Whitelist)
+		*out.Whitelist = *in.Whitelist
+		out.Whitelist.SourceRange = copyStrings(in.Whitelist.SourceRange)
+		if in.Whitelist.IPStrategy != nil {
+			out.Whitelist.IPStrategy = new(types.IPStrategy)
+			*out.Whitelist.IPStrategy = *in.Whitelist.IPStrategy
+			out.Whitelist.IPStrategy.ExcludedIPs = copyStrings(in.Whitelist.IPStrategy.ExcludedIPs)
+		}
+	}
+	if in.BasicAuth != nil {
+		out.BasicAuth = new(BasicAuth)
+		*out.BasicAuth = *in.BasicAuth
+	}
+}
+
+// DeepCopyInto copies the MiddlewareList into out.
+func (in *MiddlewareList) DeepCopyInto(out *MiddlewareList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]Middleware, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a copy of the MiddlewareList.
+func (in *MiddlewareList) DeepCopy() *MiddlewareList {
+	if in == nil {
+		return nil
+	}
+	out := new(MiddlewareList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *MiddlewareList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies the TLSOption into out.
+func (in *TLSOption) DeepCopyInto(out *TLSOption) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.CipherSuites = copyStrings(in.Spec.CipherSuites)
+	out.Spec.CurvePreferences = copyStrings(in.Spec.CurvePreferences)
+}
+
+// DeepCopy returns a copy of the TLSOption.
+func (in *TLSOption) DeepCopy() *TLSOption {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSOption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *TLSOption) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies the TLSOptionList into out.
+func (in *TLSOptionList) DeepCopyInto(out *TLSOptionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]TLSOption, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a copy of the TLSOptionList.
+func (in *TLSOptionList) DeepCopy() *TLSOptionList {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSOptionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *TLSOptionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+func copyStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, len(in))
+	copy(out, in)
+	return out
+}
+
+func copyStringMap(in map[string]string) map[string]string {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for key, value := range in {
+		out[key] = value
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package crd
+
+import (
+	"github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName is the API group of the Traefik custom resources.
+const GroupName = "traefik.containo.us"
+
+// SchemeGroupVersion is the API group and version of the Traefik custom resources.
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+
+const (
+	kindIngressRoutes = "ingressroutes"
+	kindMiddlewares   = "middlewares"
+	kindTLSOptions    = "tlsoptions"
+)
+
+// addKnownTypes registers the custom resources in the scheme.
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&IngressRoute{},
+		&IngressRouteList{},
+		&Middleware{},
+		&MiddlewareList{},
+		&TLSOption{},
+		&TLSOptionList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
+
+// IngressRoute routes the requests of its entry points to Kubernetes services,
+// expressing the frontend semantics that don't fit in the Ingress annotations.
+type IngressRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IngressRouteSpec `json:"spec"`
+}
+
+// IngressRouteSpec is the specification of an IngressRoute.
+type IngressRouteSpec struct {
+	EntryPoints []string `json:"entryPoints,omitempty"`
+	Routes      []Route  `json:"routes"`
+	TLS         *TLS     `json:"tls,omitempty"`
+}
+
+// Route matches the requests with a frontend rule, such as Host:example.com;PathPrefix:/api,
+// and forwards them to its services once processed by its middlewares.
+type Route struct {
+	Match       string          `json:"match"`
+	Priority    int             `json:"priority,omitempty"`
+	Middlewares []MiddlewareRef `json:"middlewares,omitempty"`
+	Services    []Service       `json:"services,omitempty"`
+}
+
+// Service is a port of a Kubernetes service, receiving a share of the requests of its route proportional to its weight.
+type Service struct {
+	Name   string `json:"name"`
+	Port   int32  `json:"port"`
+	Weight int    `json:"weight,omitempty"`
+}
+
+// MiddlewareRef references a Middleware, in the namespace of the IngressRoute by default.
+type MiddlewareRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// TLS configures the certificate and the TLS options of the routes of an IngressRoute.
+type TLS struct {
+	// SecretName is the name of the Secret holding the certificate, in its tls.crt and tls.key entries.
+	SecretName string `json:"secretName,omitempty"`
+	// Options is the name of a TLSOption of the namespace, or of the TLS options defined by another provider.
+	Options     string `json:"options,omitempty"`
+	Passthrough bool   `json:"passthrough,omitempty"`
+}
+
+// IngressRouteList is a list of IngressRoutes.
+type IngressRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []IngressRoute `json:"items"`
+}
+
+// Middleware processes the requests of the routes referencing it.
+type Middleware struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MiddlewareSpec `json:"spec"`
+}
+
+// MiddlewareSpec is the specification of a Middleware, holding the frontend options it sets.
+type MiddlewareSpec struct {
+	Headers   *types.Headers   `json:"headers,omitempty"`
+	Redirect  *types.Redirect  `json:"redirect,omitempty"`
+	RateLimit *types.RateLimit `json:"rateLimit,omitempty"`
+	Whitelist *Whitelist       `json:"whitelist,omitempty"`
+	BasicAuth *BasicAuth       `json:"basicAuth,omitempty"`
+}
+
+// Whitelist restricts the requests to the client IPs of the source ranges.
+type Whitelist struct {
+	SourceRange []string          `json:"sourceRange,omitempty"`
+	IPStrategy  *types.IPStrategy `json:"ipStrategy,omitempty"`
+}
+
+// BasicAuth authenticates the requests with the users of a Secret, one user per line of its single entry.
+type BasicAuth struct {
+	Secret string `json:"secret"`
+}
+
+// MiddlewareList is a list of Middlewares.
+type MiddlewareList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Middleware `json:"items"`
+}
+
+// TLSOption holds TLS options, selected by the IngressRoutes of its namespace.
+type TLSOption struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec tls.Options `json:"spec"`
+}
+
+// TLSOptionList is a list of TLSOptions.
+type TLSOptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TLSOption `json:"items"`
+}
@@ -0,0 +1,223 @@
+package kubernetes
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	kindEndpointSlices = "endpointslices"
+
+	// labelServiceName is the label of the EndpointSlices holding the name of their service.
+	labelServiceName = "kubernetes.io/service-name"
+	// indexServiceName indexes the EndpointSlices by the namespace and the name of their service.
+	indexServiceName = "serviceName"
+)
+
+// discoveryGroupVersion is the API group and version of the EndpointSlices, available since Kubernetes 1.21.
+var discoveryGroupVersion = schema.GroupVersion{Group: "discovery.k8s.io", Version: "v1"}
+
+// endpointSlice holds the fields of a discovery.k8s.io/v1 EndpointSlice used to load the servers of a service.
+type endpointSlice struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	AddressType string              `json:"addressType"`
+	Endpoints   []sliceEndpoint     `json:"endpoints"`
+	Ports       []sliceEndpointPort `json:"ports,omitempty"`
+}
+
+type sliceEndpoint struct {
+	Addresses  []string                `json:"addresses"`
+	Conditions sliceEndpointConditions `json:"conditions,omitempty"`
+	Hostname   *string                 `json:"hostname,omitempty"`
+	TargetRef  *corev1.ObjectReference `json:"targetRef,omitempty"`
+	NodeName   *string                 `json:"nodeName,omitempty"`
+}
+
+type sliceEndpointConditions struct {
+	Ready *bool `json:"ready,omitempty"`
+}
+
+type sliceEndpointPort struct {
+	Name     *string          `json:"name,omitempty"`
+	Protocol *corev1.Protocol `json:"protocol,omitempty"`
+	Port     *int32           `json:"port,omitempty"`
+}
+
+type endpointSliceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []endpointSlice `json:"items"`
+}
+
+// DeepCopyInto copies the EndpointSlice into out.
+func (in *endpointSlice) DeepCopyInto(out *endpointSlice) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Endpoints != nil {
+		out.Endpoints = make([]sliceEndpoint, len(in.Endpoints))
+		for i := range in.Endpoints {
+			in.Endpoints[i].DeepCopyInto(&out.Endpoints[i])
+		}
+	}
+	if in.Ports != nil {
+		out.Ports = make([]sliceEndpointPort, len(in.Ports))
+		for i := range in.Ports {
+			in.Ports[i].DeepCopyInto(&out.Ports[i])
+		}
+	}
+}
+
+// DeepCopyInto copies the endpoint into out.
+func (in *sliceEndpoint) DeepCopyInto(out *sliceEndpoint) {
+	*out = *in
+	if in.Addresses != nil {
+		out.Addresses = make([]string, len(in.Addresses))
+		copy(out.Addresses, in.Addresses)
+	}
+	if in.Conditions.Ready != nil {
+		out.Conditions.Ready = new(bool)
+		*out.Conditions.Ready = *in.Conditions.Ready
+	}
+	if in.Hostname != nil {
+		out.Hostname = new(string)
+		*out.Hostname = *in.Hostname
+	}
+	if in.TargetRef != nil {
+		out.TargetRef = in.TargetRef.DeepCopy()
+	}
+	if in.NodeName != nil {
+		out.NodeName = new(string)
+		*out.NodeName = *in.NodeName
+	}
+}
+
+// DeepCopyInto copies the port into out.
+func (in *sliceEndpointPort) DeepCopyInto(out *sliceEndpointPort) {
+	*out = *in
+	if in.Name != nil {
+		out.Name = new(string)
+		*out.Name = *in.Name
+	}
+	if in.Protocol != nil {
+		out.Protocol = new(corev1.Protocol)
+		*out.Protocol = *in.Protocol
+	}
+	if in.Port != nil {
+		out.Port = new(int32)
+		*out.Port = *in.Port
+	}
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *endpointSlice) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := &endpointSlice{}
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *endpointSliceList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := &endpointSliceList{}
+	*out = *in
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]endpointSlice, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+	return out
+}
+
+// addDiscoveryTypes registers the EndpointSlices in the scheme.
+func addDiscoveryTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypeWithName(discoveryGroupVersion.WithKind("EndpointSlice"), &endpointSlice{})
+	scheme.AddKnownTypeWithName(discoveryGroupVersion.WithKind("EndpointSliceList"), &endpointSliceList{})
+	metav1.AddToGroupVersion(scheme, discoveryGroupVersion)
+	return nil
+}
+
+// endpointSliceServiceIndex indexes an EndpointSlice by the namespace and the name of its service.
+func endpointSliceServiceIndex(obj interface{}) ([]string, error) {
+	slice, ok := obj.(*endpointSlice)
+	if !ok {
+		return nil, nil
+	}
+	serviceName, ok := slice.Labels[labelServiceName]
+	if !ok {
+		return nil, nil
+	}
+	return []string{slice.Namespace + "/" + serviceName}, nil
+}
+
+// endpointsFromSlices merges the EndpointSlices of a service into Endpoints, one subset per slice,
+// so that they're loaded as the Endpoints of the service.
+// As kube-proxy does, only the first address of an endpoint is used, the endpoints with an unknown readiness being ready.
+func endpointsFromSlices(namespace, name string, slices []*endpointSlice) *corev1.Endpoints {
+	sort.Slice(slices, func(i, j int) bool {
+		return slices[i].Name < slices[j].Name
+	})
+
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+	}
+	for _, slice := range slices {
+		if slice.AddressType != "IPv4" && slice.AddressType != "IPv6" {
+			continue
+		}
+
+		var subset corev1.EndpointSubset
+		for _, port := range slice.Ports {
+			if port.Port == nil {
+				continue
+			}
+			endpointPort := corev1.EndpointPort{Port: *port.Port}
+			if port.Name != nil {
+				endpointPort.Name = *port.Name
+			}
+			if port.Protocol != nil {
+				endpointPort.Protocol = *port.Protocol
+			}
+			subset.Ports = append(subset.Ports, endpointPort)
+		}
+
+		for _, endpoint := range slice.Endpoints {
+			if len(endpoint.Addresses) == 0 {
+				continue
+			}
+			address := corev1.EndpointAddress{
+				IP:        endpoint.Addresses[0],
+				TargetRef: endpoint.TargetRef,
+				NodeName:  endpoint.NodeName,
+			}
+			if endpoint.Hostname != nil {
+				address.Hostname = *endpoint.Hostname
+			}
+
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				subset.Addresses = append(subset.Addresses, address)
+			} else {
+				subset.NotReadyAddresses = append(subset.NotReadyAddresses, address)
+			}
+		}
+
+		if len(subset.Addresses) > 0 || len(subset.NotReadyAddresses) > 0 {
+			endpoints.Subsets = append(endpoints.Subsets, subset)
+		}
+	}
+	return endpoints
+}
@@ -0,0 +1,127 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/tools/cache"
+)
+
+func decodeEndpointSlice(t *testing.T, data string) *endpointSlice {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, addDiscoveryTypes(scheme))
+	object, _, err := serializer.NewCodecFactory(scheme).UniversalDeserializer().Decode([]byte(data), nil, nil)
+	require.NoError(t, err)
+	require.IsType(t, &endpointSlice{}, object)
+	return object.(*endpointSlice)
+}
+
+func TestGetEndpointsFromSlices(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{indexServiceName: endpointSliceServiceIndex})
+	require.NoError(t, indexer.Add(decodeEndpointSlice(t, `{
+  "apiVersion": "discovery.k8s.io/v1",
+  "kind": "EndpointSlice",
+  "metadata": {"namespace": "testing", "name": "service1-b", "labels": {"kubernetes.io/service-name": "service1"}},
+  "addressType": "IPv4",
+  "endpoints": [
+    {"addresses": ["10.10.0.3"], "conditions": {"ready": false}},
+    {"addresses": ["10.10.0.4", "10.10.0.5"]}
+  ],
+  "ports": [{"name": "http", "port": 8080, "protocol": "TCP"}]
+}`)))
+	require.NoError(t, indexer.Add(decodeEndpointSlice(t, `{
+  "apiVersion": "discovery.k8s.io/v1",
+  "kind": "EndpointSlice",
+  "metadata": {"namespace": "testing", "name": "service1-a", "labels": {"kubernetes.io/service-name": "service1"}},
+  "addressType": "IPv4",
+  "endpoints": [
+    {"addresses": ["10.10.0.1"], "conditions": {"ready": true}, "targetRef": {"kind": "Pod", "name": "pod1"}},
+    {"addresses": ["10.10.0.2"], "conditions": {"ready": true}, "targetRef": {"kind": "Pod", "name": "pod2"}}
+  ],
+  "ports": [{"name": "http", "port": 8080, "protocol": "TCP"}]
+}`)))
+	require.NoError(t, indexer.Add(decodeEndpointSlice(t, `{
+  "apiVersion": "discovery.k8s.io/v1",
+  "kind": "EndpointSlice",
+  "metadata": {"namespace": "testing", "name": "service1-fqdn", "labels": {"kubernetes.io/service-name": "service1"}},
+  "addressType": "FQDN",
+  "endpoints": [{"addresses": ["foo.example.com"]}],
+  "ports": [{"name": "http", "port": 8080}]
+}`)))
+	require.NoError(t, indexer.Add(decodeEndpointSlice(t, `{
+  "apiVersion": "discovery.k8s.io/v1",
+  "kind": "EndpointSlice",
+  "metadata": {"namespace": "testing", "name": "service2-a", "labels": {"kubernetes.io/service-name": "service2"}},
+  "addressType": "IPv4",
+  "endpoints": [{"addresses": ["10.20.0.1"]}],
+  "ports": [{"name": "http", "port": 8080}]
+}`)))
+
+	client := &clientImpl{endpointSlices: true, sliceIndexers: map[string]cache.Indexer{"testing": indexer}}
+
+	endpoints, exists, err := client.GetEndpoints("testing", "service1")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	port := corev1.EndpointPort{Name: "http", Port: 8080, Protocol: corev1.ProtocolTCP}
+	expected := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testing", Name: "service1"},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses: []corev1.EndpointAddress{
+					{IP: "10.10.0.1", TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "pod1"}},
+					{IP: "10.10.0.2", TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "pod2"}},
+				},
+				Ports: []corev1.EndpointPort{port},
+			},
+			{
+				Addresses:         []corev1.EndpointAddress{{IP: "10.10.0.4"}},
+				NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.10.0.3"}},
+				Ports:             []corev1.EndpointPort{port},
+			},
+		},
+	}
+	assert.Equal(t, expected, endpoints)
+
+	_, exists, err = client.GetEndpoints("testing", "service3")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestEndpointSliceDeepCopyObject(t *testing.T) {
+	slice := decodeEndpointSlice(t, `{
+  "apiVersion": "discovery.k8s.io/v1",
+  "kind": "EndpointSlice",
+  "metadata": {"namespace": "testing", "name": "service1-a", "labels": {"kubernetes.io/service-name": "service1"}},
+  "addressType": "IPv4",
+  "endpoints": [
+    {"addresses": ["10.10.0.1"], "conditions": {"ready": true}, "hostname": "pod1", "nodeName": "node1", "targetRef": {"kind": "Pod", "name": "pod1"}}
+  ],
+  "ports": [{"name": "http", "port": 8080, "protocol": "TCP"}]
+}`)
+
+	copied := slice.DeepCopyObject().(*endpointSlice)
+	assert.Equal(t, slice, copied)
+
+	copied.Labels[labelServiceName] = "service2"
+	copied.Endpoints[0].Addresses[0] = "10.10.0.2"
+	*copied.Endpoints[0].Conditions.Ready = false
+	copied.Endpoints[0].TargetRef.Name = "pod2"
+	*copied.Ports[0].Port = 8081
+
+	assert.Equal(t, "service1", slice.Labels[labelServiceName])
+	assert.Equal(t, "10.10.0.1", slice.Endpoints[0].Addresses[0])
+	assert.True(t, *slice.Endpoints[0].Conditions.Ready)
+	assert.Equal(t, "pod1", slice.Endpoints[0].TargetRef.Name)
+	assert.EqualValues(t, 8080, *slice.Ports[0].Port)
+
+	list := &endpointSliceList{Items: []endpointSlice{*slice}}
+	assert.Equal(t, list, list.DeepCopyObject())
+}
@@ -0,0 +1,101 @@
+package kubernetes
+
+import (
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// extensionsGroupVersion is the API group and version of the watched Ingresses.
+var extensionsGroupVersion = schema.GroupVersion{Group: "extensions", Version: "v1beta1"}
+
+// ingress is an extensions/v1beta1 Ingress along with the ingressClassName of its spec,
+// served since Kubernetes 1.18 but unknown to the vendored API types.
+type ingress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ingressSpec                     `json:"spec,omitempty"`
+	Status extensionsv1beta1.IngressStatus `json:"status,omitempty"`
+}
+
+type ingressSpec struct {
+	extensionsv1beta1.IngressSpec `json:",inline"`
+
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+}
+
+type ingressList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ingress `json:"items"`
+}
+
+// DeepCopyInto copies the Ingress into out.
+func (in *ingress) DeepCopyInto(out *ingress) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.IngressSpec.DeepCopyInto(&out.Spec.IngressSpec)
+	if in.Spec.IngressClassName != nil {
+		out.Spec.IngressClassName = new(string)
+		*out.Spec.IngressClassName = *in.Spec.IngressClassName
+	}
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ingress) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := &ingress{}
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ingressList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := &ingressList{}
+	*out = *in
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]ingress, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+	return out
+}
+
+// addExtensionsTypes registers the Ingresses in the scheme.
+func addExtensionsTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypeWithName(extensionsGroupVersion.WithKind("Ingress"), &ingress{})
+	scheme.AddKnownTypeWithName(extensionsGroupVersion.WithKind("IngressList"), &ingressList{})
+	metav1.AddToGroupVersion(scheme, extensionsGroupVersion)
+	return nil
+}
+
+// toIngress returns the Ingress as loaded by the provider, its ingressClassName being its kubernetes.io/ingress.class annotation
+// unless it has one.
+func (in *ingress) toIngress() *extensionsv1beta1.Ingress {
+	out := &extensionsv1beta1.Ingress{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Spec:       in.Spec.IngressSpec,
+		Status:     in.Status,
+	}
+
+	if in.Spec.IngressClassName != nil && len(out.Annotations[annotationKubernetesIngressClass]) == 0 {
+		if out.Annotations == nil {
+			out.Annotations = map[string]string{}
+		}
+		out.Annotations[annotationKubernetesIngressClass] = *in.Spec.IngressClassName
+	}
+	return out
+}
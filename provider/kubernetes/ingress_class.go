@@ -0,0 +1,80 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IngressClass is a value of the kubernetes.io/ingress.class annotation, or spec.ingressClassName, of the ingresses to process,
+// with the entry points of these ingresses.
+type IngressClass struct {
+	Name        string   `description:"Value of the kubernetes.io/ingress.class annotation or spec.ingressClassName, starting with traefik"`
+	EntryPoints []string `description:"Entry points of the ingresses of the class, unless set by their frontend entry points annotation"`
+}
+
+//IngressClasses parse []IngressClass
+type IngressClasses []IngressClass
+
+//Set adds an ingress class written as 'Name:traefik-internal EntryPoints:internal,internal-secure'
+func (ics *IngressClasses) Set(str string) error {
+	class := IngressClass{}
+	for _, field := range strings.Fields(str) {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("bad ingress class format: %s", str)
+		}
+		switch strings.ToLower(kv[0]) {
+		case "name":
+			class.Name = kv[1]
+		case "entrypoints":
+			class.EntryPoints = strings.Split(kv[1], ",")
+		default:
+			return fmt.Errorf("unknown ingress class field %s: %s", kv[0], str)
+		}
+	}
+	if len(class.Name) == 0 {
+		return fmt.Errorf("ingress class without name: %s", str)
+	}
+	*ics = append(*ics, class)
+	return nil
+}
+
+//Get []IngressClass
+func (ics *IngressClasses) Get() interface{} { return []IngressClass(*ics) }
+
+//String returns []IngressClass in string
+func (ics *IngressClasses) String() string { return fmt.Sprintf("%+v", *ics) }
+
+//SetValue sets []IngressClass into the parser
+func (ics *IngressClasses) SetValue(val interface{}) {
+	*ics = val.(IngressClasses)
+}
+
+// validateIngressClasses checks that the ingress classes start with traefik,
+// to reduce chances of conflict with other Ingress Providers.
+func (p *Provider) validateIngressClasses() error {
+	if len(p.IngressClass) > 0 && !strings.HasPrefix(p.IngressClass, traefikDefaultIngressClass) {
+		return fmt.Errorf("value for IngressClass has to be empty or start with the prefix %q, instead found %q", traefikDefaultIngressClass, p.IngressClass)
+	}
+	for _, class := range p.IngressClasses {
+		if !strings.HasPrefix(class.Name, traefikDefaultIngressClass) {
+			return fmt.Errorf("value for IngressClasses has to start with the prefix %q, instead found %q", traefikDefaultIngressClass, class.Name)
+		}
+	}
+	return nil
+}
+
+// ingressClassEntryPoints returns whether the ingresses of a class are processed, and their entry points if the class has any.
+// Without ingress class configured, the ingresses without class or of the traefik class are processed.
+func (p *Provider) ingressClassEntryPoints(annotationIngressClass string) ([]string, bool) {
+	if len(p.IngressClass) == 0 && len(p.IngressClasses) == 0 {
+		return nil, len(annotationIngressClass) == 0 || annotationIngressClass == traefikDefaultIngressClass
+	}
+
+	for _, class := range p.IngressClasses {
+		if annotationIngressClass == class.Name {
+			return class.EntryPoints, true
+		}
+	}
+	return nil, len(p.IngressClass) > 0 && annotationIngressClass == p.IngressClass
+}
@@ -0,0 +1,98 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngressClassesSet(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		values           []string
+		expected         IngressClasses
+		expectedErrorMsg string
+	}{
+		{
+			desc:   "classes with and without entry points",
+			values: []string{"Name:traefik-internal EntryPoints:internal,internal-secure", "name:traefik-public"},
+			expected: IngressClasses{
+				{Name: "traefik-internal", EntryPoints: []string{"internal", "internal-secure"}},
+				{Name: "traefik-public"},
+			},
+		},
+		{
+			desc:             "class without name",
+			values:           []string{"EntryPoints:internal"},
+			expectedErrorMsg: "ingress class without name: EntryPoints:internal",
+		},
+		{
+			desc:             "unknown field",
+			values:           []string{"Name:traefik-internal Priority:1"},
+			expectedErrorMsg: "unknown ingress class field Priority: Name:traefik-internal Priority:1",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var classes IngressClasses
+			var err error
+			for _, value := range test.values {
+				if err = classes.Set(value); err != nil {
+					break
+				}
+			}
+			if len(test.expectedErrorMsg) > 0 {
+				assert.EqualError(t, err, test.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, classes)
+		})
+	}
+}
+
+func TestValidateIngressClasses(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		provider         *Provider
+		expectedErrorMsg string
+	}{
+		{
+			desc:     "no class",
+			provider: &Provider{},
+		},
+		{
+			desc:     "valid classes",
+			provider: &Provider{IngressClass: "traefik-internal", IngressClasses: IngressClasses{{Name: "traefik-public"}}},
+		},
+		{
+			desc:             "invalid class",
+			provider:         &Provider{IngressClass: "nginx"},
+			expectedErrorMsg: `value for IngressClass has to be empty or start with the prefix "traefik", instead found "nginx"`,
+		},
+		{
+			desc:             "invalid class of the list",
+			provider:         &Provider{IngressClasses: IngressClasses{{Name: "traefik-public"}, {Name: "nginx"}}},
+			expectedErrorMsg: `value for IngressClasses has to start with the prefix "traefik", instead found "nginx"`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.provider.validateIngressClasses()
+			if len(test.expectedErrorMsg) > 0 {
+				assert.EqualError(t, err, test.expectedErrorMsg)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
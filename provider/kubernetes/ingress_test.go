@@ -0,0 +1,79 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+func decodeIngress(t *testing.T, data string) *ingress {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, addExtensionsTypes(scheme))
+	object, _, err := serializer.NewCodecFactory(scheme).UniversalDeserializer().Decode([]byte(data), nil, nil)
+	require.NoError(t, err)
+	require.IsType(t, &ingress{}, object)
+	return object.(*ingress)
+}
+
+func TestIngressToIngress(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		data          string
+		expectedClass string
+	}{
+		{
+			desc: "without class",
+			data: `{
+  "apiVersion": "extensions/v1beta1",
+  "kind": "Ingress",
+  "metadata": {"name": "whoami", "namespace": "default"},
+  "spec": {"backend": {"serviceName": "whoami", "servicePort": 80}}
+}`,
+		},
+		{
+			desc: "with ingressClassName",
+			data: `{
+  "apiVersion": "extensions/v1beta1",
+  "kind": "Ingress",
+  "metadata": {"name": "whoami", "namespace": "default"},
+  "spec": {"ingressClassName": "traefik-internal", "backend": {"serviceName": "whoami", "servicePort": 80}}
+}`,
+			expectedClass: "traefik-internal",
+		},
+		{
+			desc: "with ingressClassName and class annotation",
+			data: `{
+  "apiVersion": "extensions/v1beta1",
+  "kind": "Ingress",
+  "metadata": {"name": "whoami", "namespace": "default", "annotations": {"kubernetes.io/ingress.class": "traefik-public"}},
+  "spec": {"ingressClassName": "traefik-internal", "backend": {"serviceName": "whoami", "servicePort": 80}}
+}`,
+			expectedClass: "traefik-public",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ing := decodeIngress(t, test.data)
+			annotations := len(ing.Annotations)
+			actual := ing.toIngress()
+
+			assert.Equal(t, "whoami", actual.Name)
+			assert.Equal(t, "default", actual.Namespace)
+			require.NotNil(t, actual.Spec.Backend)
+			assert.Equal(t, "whoami", actual.Spec.Backend.ServiceName)
+			assert.Equal(t, test.expectedClass, actual.Annotations[annotationKubernetesIngressClass])
+
+			// The Ingress of the store is left as is.
+			assert.Len(t, ing.Annotations, annotations)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -16,6 +17,7 @@ import (
 	"github.com/cenk/backoff"
 	"github.com/containous/traefik/job"
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
 	"github.com/containous/traefik/provider"
 	"github.com/containous/traefik/provider/label"
 	"github.com/containous/traefik/safe"
@@ -24,6 +26,7 @@ import (
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -39,14 +42,19 @@ const (
 // Provider holds configurations of the provider.
 type Provider struct {
 	provider.BaseProvider  `mapstructure:",squash" export:"true"`
-	Endpoint               string     `description:"Kubernetes server endpoint (required for external cluster client)"`
-	Token                  string     `description:"Kubernetes bearer token (not needed for in-cluster client)"`
-	CertAuthFilePath       string     `description:"Kubernetes certificate authority file path (not needed for in-cluster client)"`
-	DisablePassHostHeaders bool       `description:"Kubernetes disable PassHost Headers" export:"true"`
-	EnablePassTLSCert      bool       `description:"Kubernetes enable Pass TLS Client Certs" export:"true"`
-	Namespaces             Namespaces `description:"Kubernetes namespaces" export:"true"`
-	LabelSelector          string     `description:"Kubernetes api label selector to use" export:"true"`
-	IngressClass           string     `description:"Value of kubernetes.io/ingress.class annotation to watch for" export:"true"`
+	Endpoint               string         `description:"Kubernetes server endpoint (required for external cluster client)"`
+	Token                  string         `description:"Kubernetes bearer token (not needed for in-cluster client)"`
+	CertAuthFilePath       string         `description:"Kubernetes certificate authority file path (not needed for in-cluster client)"`
+	DisablePassHostHeaders bool           `description:"Kubernetes disable PassHost Headers" export:"true"`
+	EnablePassTLSCert      bool           `description:"Kubernetes enable Pass TLS Client Certs" export:"true"`
+	Namespaces             Namespaces     `description:"Kubernetes namespaces" export:"true"`
+	NamespaceSelector      string         `description:"Kubernetes label selector of the namespaces to watch, along with the listed namespaces" export:"true"`
+	LabelSelector          string         `description:"Kubernetes api label selector to use" export:"true"`
+	IngressClass           string         `description:"Value of kubernetes.io/ingress.class annotation to watch for" export:"true"`
+	IngressClasses         IngressClasses `description:"Values of kubernetes.io/ingress.class annotation to watch for, with the entry points of their ingresses, e.g. 'Name:traefik-internal EntryPoints:internal'" export:"true"`
+	TenantFromNamespace    bool           `description:"Use the namespace of the ingresses as their tenant" export:"true"`
+	TLSSecretsSelector     string         `description:"Load the TLS certificates of the Secrets matching the label selector, independently of the ingresses" export:"true"`
+	EndpointSlices         bool           `description:"Load the servers of the services from their EndpointSlices instead of their Endpoints (requires Kubernetes 1.21)" export:"true"`
 	lastConfiguration      safe.Safe
 }
 
@@ -58,11 +66,11 @@ func (p *Provider) newK8sClient() (Client, error) {
 
 	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" && os.Getenv("KUBERNETES_SERVICE_PORT") != "" {
 		log.Infof("Creating in-cluster Provider client%s", withEndpoint)
-		return NewInClusterClient(p.Endpoint)
+		return NewInClusterClient(p.Endpoint, p.EndpointSlices)
 	}
 
 	log.Infof("Creating cluster-external Provider client%s", withEndpoint)
-	return NewExternalClusterClient(p.Endpoint, p.Token, p.CertAuthFilePath)
+	return NewExternalClusterClient(p.Endpoint, p.Token, p.CertAuthFilePath, p.EndpointSlices)
 }
 
 // Provide allows the k8s provider to provide configurations to traefik
@@ -77,10 +85,20 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 		return err
 	}
 
-	// We require that IngressClasses start with `traefik` to reduce chances of
-	// conflict with other Ingress Providers
-	if len(p.IngressClass) > 0 && !strings.HasPrefix(p.IngressClass, traefikDefaultIngressClass) {
-		return fmt.Errorf("value for IngressClass has to be empty or start with the prefix %q, instead found %q", traefikDefaultIngressClass, p.IngressClass)
+	if err := p.validateIngressClasses(); err != nil {
+		return err
+	}
+
+	if len(p.NamespaceSelector) > 0 {
+		if _, err := labels.Parse(p.NamespaceSelector); err != nil {
+			return fmt.Errorf("invalid namespace selector %q: %v", p.NamespaceSelector, err)
+		}
+	}
+
+	if len(p.TLSSecretsSelector) > 0 {
+		if _, err := labels.Parse(p.TLSSecretsSelector); err != nil {
+			return fmt.Errorf("invalid TLS secrets selector %q: %v", p.TLSSecretsSelector, err)
+		}
 	}
 
 	k8sClient, err := p.newK8sClient()
@@ -95,7 +113,7 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 				stopWatch := make(chan struct{}, 1)
 				defer close(stopWatch)
 				log.Debugf("Using label selector: '%s'", p.LabelSelector)
-				eventsChan, err := k8sClient.WatchAll(p.Namespaces, p.LabelSelector, stopWatch)
+				eventsChan, err := k8sClient.WatchAll(p.Namespaces, p.NamespaceSelector, p.LabelSelector, stopWatch)
 				if err != nil {
 					log.Errorf("Error watching kubernetes events: %v", err)
 					timer := time.NewTimer(1 * time.Second)
@@ -132,6 +150,7 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 
 		notify := func(err error, time time.Duration) {
 			log.Errorf("Provider connection error: %s; retrying in %s", err, time)
+			notification.Notify(notification.Event{Type: notification.ProviderDisconnected, Message: "Provider connection error", Provider: "kubernetes", Error: err.Error()})
 		}
 		err := backoff.RetryNotify(safe.OperationWithRecover(operation), job.NewBackOff(backoff.NewExponentialBackOff()), notify)
 		if err != nil {
@@ -154,11 +173,12 @@ func (p *Provider) loadIngresses(k8sClient Client) (*types.Configuration, error)
 		annotationIngressClass := getAnnotationName(i.Annotations, annotationKubernetesIngressClass)
 		ingressClass := i.Annotations[annotationIngressClass]
 
-		if !p.shouldProcessIngress(ingressClass) {
+		classEntryPoints, ok := p.ingressClassEntryPoints(ingressClass)
+		if !ok {
 			continue
 		}
 
-		tlsSection, err := getTLS(i, k8sClient)
+		tlsSection, err := getTLS(i, k8sClient, classEntryPoints)
 		if err != nil {
 			log.Errorf("Error configuring TLS for ingress %s/%s: %v", i.Namespace, i.Name, err)
 			continue
@@ -196,11 +216,24 @@ func (p *Provider) loadIngresses(k8sClient Client) (*types.Configuration, error)
 						continue
 					}
 
+					clientCA, err := getClientCA(i, k8sClient)
+					if err != nil {
+						log.Errorf("Failed to retrieve client CA configuration for ingress %s/%s: %s", i.Namespace, i.Name, err)
+						continue
+					}
+
 					passHostHeader := getBoolValue(i.Annotations, annotationKubernetesPreserveHost, !p.DisablePassHostHeaders)
 					passTLSCert := getBoolValue(i.Annotations, annotationKubernetesPassTLSCert, p.EnablePassTLSCert)
 					priority := getIntValue(i.Annotations, annotationKubernetesPriority, 0)
 					entryPoints := getSliceStringValue(i.Annotations, annotationKubernetesFrontendEntryPoints)
+					if len(entryPoints) == 0 {
+						entryPoints = classEntryPoints
+					}
 					whitelistSourceRange := getSliceStringValue(i.Annotations, annotationKubernetesWhitelistSourceRange)
+					tenant := getStringValue(i.Annotations, annotationKubernetesTenant, "")
+					if len(tenant) == 0 && p.TenantFromNamespace {
+						tenant = i.Namespace
+					}
 
 					templateObjects.Frontends[baseName] = &types.Frontend{
 						Backend:              baseName,
@@ -210,11 +243,17 @@ func (p *Provider) loadIngresses(k8sClient Client) (*types.Configuration, error)
 						Priority:             priority,
 						BasicAuth:            basicAuthCreds,
 						WhitelistSourceRange: whitelistSourceRange,
+						WhitelistIPStrategy:  getWhitelistIPStrategy(i),
 						Redirect:             getFrontendRedirect(i),
 						EntryPoints:          entryPoints,
 						Headers:              getHeader(i),
 						Errors:               getErrorPages(i),
 						RateLimit:            getRateLimit(i),
+						Tenant:               tenant,
+						PriorityClass:        getStringValue(i.Annotations, annotationKubernetesPriorityClass, ""),
+						ACMEResolver:         getStringValue(i.Annotations, annotationKubernetesACMEResolver, ""),
+						ClientCA:             clientCA,
+						TLSOptions:           getStringValue(i.Annotations, annotationKubernetesTLSOptions, ""),
 					}
 				}
 
@@ -263,7 +302,7 @@ func (p *Provider) loadIngresses(k8sClient Client) (*types.Configuration, error)
 						}
 
 						if service.Spec.Type == "ExternalName" {
-							url := protocol + "://" + service.Spec.ExternalName
+							url := getExternalNameURL(protocol, service.Spec.ExternalName, port.Port)
 							name := url
 
 							templateObjects.Backends[baseName].Servers[name] = types.Server{
@@ -307,6 +346,14 @@ func (p *Provider) loadIngresses(k8sClient Client) (*types.Configuration, error)
 			}
 		}
 	}
+
+	if len(p.TLSSecretsSelector) > 0 {
+		tlsSection, err := getSecretsTLS(p.TLSSecretsSelector, k8sClient)
+		if err != nil {
+			log.Errorf("Error loading the TLS secrets matching %q: %v", p.TLSSecretsSelector, err)
+		}
+		templateObjects.TLS = append(templateObjects.TLS, tlsSection...)
+	}
 	return &templateObjects, nil
 }
 
@@ -412,7 +459,34 @@ func loadAuthCredentials(namespace, secretName string, k8sClient Client) ([]stri
 	return creds, nil
 }
 
-func getTLS(ingress *extensionsv1beta1.Ingress, k8sClient Client) ([]*tls.Configuration, error) {
+// getClientCA returns the client CA of the ingress, read from the ca.crt entry of its client CA secret.
+func getClientCA(i *extensionsv1beta1.Ingress, k8sClient Client) (*types.ClientCA, error) {
+	secretName := getStringValue(i.Annotations, annotationKubernetesClientCASecret, "")
+	if len(secretName) == 0 {
+		return nil, nil
+	}
+
+	secret, exists, err := k8sClient.GetSecret(i.Namespace, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret %s/%s: %v", i.Namespace, secretName, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("secret %s/%s does not exist", i.Namespace, secretName)
+	}
+	caData, ok := secret.Data["ca.crt"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s is missing the ca.crt entry", i.Namespace, secretName)
+	}
+
+	return &types.ClientCA{
+		Files:    []tls.FileOrContent{tls.FileOrContent(caData)},
+		Optional: getBoolValue(i.Annotations, annotationKubernetesClientCAOptional, false),
+	}, nil
+}
+
+// getTLS returns the certificates of the TLS secrets of the ingress, served on the entry points of its frontend entry points annotation,
+// or on the entry points of its ingress class without annotation.
+func getTLS(ingress *extensionsv1beta1.Ingress, k8sClient Client, classEntryPoints []string) ([]*tls.Configuration, error) {
 	var tlsConfigs []*tls.Configuration
 
 	for _, t := range ingress.Spec.TLS {
@@ -424,29 +498,19 @@ func getTLS(ingress *extensionsv1beta1.Ingress, k8sClient Client) ([]*tls.Config
 			return nil, fmt.Errorf("secret %s/%s does not exist", ingress.Namespace, t.SecretName)
 		}
 
-		tlsCrtData, tlsCrtExists := tlsSecret.Data["tls.crt"]
-		tlsKeyData, tlsKeyExists := tlsSecret.Data["tls.key"]
-
-		var missingEntries []string
-		if !tlsCrtExists {
-			missingEntries = append(missingEntries, "tls.crt")
-		}
-		if !tlsKeyExists {
-			missingEntries = append(missingEntries, "tls.key")
-		}
-		if len(missingEntries) > 0 {
-			return nil, fmt.Errorf("secret %s/%s is missing the following TLS data entries: %s",
-				ingress.Namespace, t.SecretName, strings.Join(missingEntries, ", "))
+		certificate, err := getSecretCertificate(tlsSecret)
+		if err != nil {
+			return nil, err
 		}
 
 		entryPoints := getSliceStringValue(ingress.Annotations, annotationKubernetesFrontendEntryPoints)
+		if len(entryPoints) == 0 {
+			entryPoints = classEntryPoints
+		}
 
 		tlsConfig := &tls.Configuration{
 			EntryPoints: entryPoints,
-			Certificate: &tls.Certificate{
-				CertFile: tls.FileOrContent(tlsCrtData),
-				KeyFile:  tls.FileOrContent(tlsKeyData),
-			},
+			Certificate: certificate,
 		}
 
 		tlsConfigs = append(tlsConfigs, tlsConfig)
@@ -455,6 +519,77 @@ func getTLS(ingress *extensionsv1beta1.Ingress, k8sClient Client) ([]*tls.Config
 	return tlsConfigs, nil
 }
 
+// getSecretsTLS returns the TLS certificates of the secrets matching the label selector, sorted by namespace and name.
+// The entry points of a certificate are read from the frontend entry points annotation of its secret.
+// The secrets with missing TLS data entries are skipped.
+func getSecretsTLS(labelSelector string, k8sClient Client) ([]*tls.Configuration, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []*corev1.Secret
+	for _, secret := range k8sClient.GetSecrets() {
+		if selector.Matches(labels.Set(secret.Labels)) {
+			secrets = append(secrets, secret)
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		if secrets[i].Namespace != secrets[j].Namespace {
+			return secrets[i].Namespace < secrets[j].Namespace
+		}
+		return secrets[i].Name < secrets[j].Name
+	})
+
+	var tlsConfigs []*tls.Configuration
+	for _, secret := range secrets {
+		certificate, err := getSecretCertificate(secret)
+		if err != nil {
+			log.Errorf("Error configuring TLS for secret %s/%s: %v", secret.Namespace, secret.Name, err)
+			continue
+		}
+
+		tlsConfigs = append(tlsConfigs, &tls.Configuration{
+			EntryPoints: getSliceStringValue(secret.Annotations, annotationKubernetesFrontendEntryPoints),
+			Certificate: certificate,
+		})
+	}
+
+	return tlsConfigs, nil
+}
+
+// getSecretCertificate returns the certificate of the tls.crt and tls.key data entries of a secret.
+func getSecretCertificate(secret *corev1.Secret) (*tls.Certificate, error) {
+	tlsCrtData, tlsCrtExists := secret.Data["tls.crt"]
+	tlsKeyData, tlsKeyExists := secret.Data["tls.key"]
+
+	var missingEntries []string
+	if !tlsCrtExists {
+		missingEntries = append(missingEntries, "tls.crt")
+	}
+	if !tlsKeyExists {
+		missingEntries = append(missingEntries, "tls.key")
+	}
+	if len(missingEntries) > 0 {
+		return nil, fmt.Errorf("secret %s/%s is missing the following TLS data entries: %s",
+			secret.Namespace, secret.Name, strings.Join(missingEntries, ", "))
+	}
+
+	return &tls.Certificate{
+		CertFile: tls.FileOrContent(tlsCrtData),
+		KeyFile:  tls.FileOrContent(tlsKeyData),
+	}, nil
+}
+
+// getExternalNameURL returns the URL of the external host of an ExternalName service,
+// with the port of the service unless it's the default port of the protocol.
+func getExternalNameURL(protocol, externalName string, port int32) string {
+	if port == 80 || port == 443 {
+		return protocol + "://" + externalName
+	}
+	return protocol + "://" + externalName + ":" + strconv.Itoa(int(port))
+}
+
 func endpointPortNumber(servicePort corev1.ServicePort, endpointPorts []corev1.EndpointPort) int {
 	if len(endpointPorts) > 0 {
 		//name is optional if there is only one port
@@ -479,13 +614,6 @@ func equalPorts(servicePort corev1.ServicePort, ingressPort intstr.IntOrString)
 	return false
 }
 
-func (p *Provider) shouldProcessIngress(annotationIngressClass string) bool {
-	if len(p.IngressClass) == 0 {
-		return len(annotationIngressClass) == 0 || annotationIngressClass == traefikDefaultIngressClass
-	}
-	return annotationIngressClass == p.IngressClass
-}
-
 func getFrontendRedirect(i *extensionsv1beta1.Ingress) *types.Redirect {
 	permanent := getBoolValue(i.Annotations, annotationKubernetesRedirectPermanent, false)
 
@@ -612,6 +740,19 @@ func getCircuitBreaker(service *corev1.Service) *types.CircuitBreaker {
 	return nil
 }
 
+func getWhitelistIPStrategy(i *extensionsv1beta1.Ingress) *types.IPStrategy {
+	depth := getIntValue(i.Annotations, annotationKubernetesWhitelistDepth, 0)
+	excludedIPs := getSliceStringValue(i.Annotations, annotationKubernetesWhitelistExcludedIPs)
+	if depth == 0 && len(excludedIPs) == 0 {
+		return nil
+	}
+
+	return &types.IPStrategy{
+		Depth:       depth,
+		ExcludedIPs: excludedIPs,
+	}
+}
+
 func getErrorPages(i *extensionsv1beta1.Ingress) map[string]*types.ErrorPage {
 	var errorPages map[string]*types.ErrorPage
 
@@ -416,6 +416,61 @@ func TestHostlessIngress(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestExternalNameService(t *testing.T) {
+	ingresses := []*extensionsv1beta1.Ingress{
+		buildIngress(iNamespace("awesome"),
+			iRules(iRule(
+				iHost("foo"),
+				iPaths(
+					onePath(iPath("/bar"), iBackend("external", intstr.FromString("http"))),
+					onePath(iPath("/baz"), iBackend("external", intstr.FromInt(443)))),
+			)),
+		),
+	}
+
+	services := []*corev1.Service{
+		buildService(
+			sName("external"),
+			sNamespace("awesome"),
+			sUID("1"),
+			sSpec(
+				sType("ExternalName"),
+				sExternalName("api.example.com"),
+				sPorts(sPort(8080, "http"), sPort(443, "https"))),
+		),
+	}
+
+	watchChan := make(chan interface{})
+	client := clientMock{
+		ingresses: ingresses,
+		services:  services,
+		watchChan: watchChan,
+	}
+	provider := Provider{DisablePassHostHeaders: true}
+
+	actual, err := provider.loadIngresses(client)
+	require.NoError(t, err, "error loading ingresses")
+
+	expected := buildConfiguration(
+		backends(
+			backend("foo/bar", lbMethod("wrr"), servers(server("http://api.example.com:8080", weight(1)))),
+			backend("foo/baz", lbMethod("wrr"), servers(server("https://api.example.com", weight(1)))),
+		),
+		frontends(
+			frontend("foo/bar",
+				routes(
+					route("/bar", "PathPrefix:/bar"),
+					route("foo", "Host:foo"))),
+			frontend("foo/baz",
+				routes(
+					route("/baz", "PathPrefix:/baz"),
+					route("foo", "Host:foo"))),
+		),
+	)
+
+	assert.Equal(t, expected, actual)
+}
+
 func TestServiceAnnotations(t *testing.T) {
 	ingresses := []*extensionsv1beta1.Ingress{
 		buildIngress(iNamespace("testing"),
@@ -1201,6 +1256,44 @@ func TestIngressClassAnnotation(t *testing.T) {
 				),
 			),
 		},
+		{
+			desc: "Provided IngressClasses",
+			provider: Provider{IngressClasses: IngressClasses{
+				{Name: traefikDefaultIngressClass},
+				{Name: traefikDefaultIngressClass + "-other", EntryPoints: []string{"internal"}},
+			}},
+			expected: buildConfiguration(
+				backends(
+					backend("other/stuff",
+						servers(
+							server("http://example.com", weight(1)),
+							server("http://example.com", weight(1))),
+						lbMethod("wrr"),
+					),
+					backend("herp/derp",
+						servers(
+							server("http://example.com", weight(1)),
+							server("http://example.com", weight(1))),
+						lbMethod("wrr"),
+					),
+				),
+				frontends(
+					frontend("other/stuff",
+						passHostHeader(),
+						routes(
+							route("/stuff", "PathPrefix:/stuff"),
+							route("other", "Host:other")),
+					),
+					frontend("herp/derp",
+						passHostHeader(),
+						entryPoints("internal"),
+						routes(
+							route("/derp", "PathPrefix:/derp"),
+							route("herp", "Host:herp")),
+					),
+				),
+			),
+		},
 	}
 
 	for _, test := range testCases {
@@ -1914,7 +2007,115 @@ func TestGetTLS(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			tlsConfigs, err := getTLS(test.ingress, test.client)
+			tlsConfigs, err := getTLS(test.ingress, test.client, nil)
+
+			if test.errResult != "" {
+				assert.EqualError(t, err, test.errResult)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.result, tlsConfigs)
+			}
+		})
+	}
+}
+
+func TestGetSecretsTLS(t *testing.T) {
+	secret := func(namespace, name string, secretLabels map[string]string, entryPoints string, data map[string][]byte) *corev1.Secret {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    secretLabels,
+			},
+			Data: data,
+		}
+		if len(entryPoints) > 0 {
+			s.Annotations = map[string]string{annotationKubernetesFrontendEntryPoints: entryPoints}
+		}
+		return s
+	}
+	tlsData := func(crt string) map[string][]byte {
+		return map[string][]byte{
+			"tls.crt": []byte(crt),
+			"tls.key": []byte(crt + "-key"),
+		}
+	}
+
+	tests := []struct {
+		desc          string
+		labelSelector string
+		secrets       []*corev1.Secret
+		result        []*tls.Configuration
+		errResult     string
+	}{
+		{
+			desc:          "invalid label selector",
+			labelSelector: "traefik in",
+			errResult:     "unable to parse requirement: found '' expected: '('",
+		},
+		{
+			desc:          "no matching secret",
+			labelSelector: "traefik.io/certificate=true",
+			secrets: []*corev1.Secret{
+				secret("testing", "other", map[string]string{"app": "web"}, "", tlsData("other")),
+			},
+		},
+		{
+			desc:          "matching secrets sorted by namespace and name",
+			labelSelector: "traefik.io/certificate=true",
+			secrets: []*corev1.Secret{
+				secret("testing", "web", map[string]string{"traefik.io/certificate": "true"}, "https", tlsData("web")),
+				secret("testing", "api", map[string]string{"traefik.io/certificate": "true"}, "https,api-secure", tlsData("api")),
+				secret("default", "wildcard", map[string]string{"traefik.io/certificate": "true"}, "", tlsData("wildcard")),
+				secret("testing", "other", map[string]string{"traefik.io/certificate": "false"}, "", tlsData("other")),
+			},
+			result: []*tls.Configuration{
+				{
+					Certificate: &tls.Certificate{
+						CertFile: tls.FileOrContent("wildcard"),
+						KeyFile:  tls.FileOrContent("wildcard-key"),
+					},
+				},
+				{
+					EntryPoints: []string{"https", "api-secure"},
+					Certificate: &tls.Certificate{
+						CertFile: tls.FileOrContent("api"),
+						KeyFile:  tls.FileOrContent("api-key"),
+					},
+				},
+				{
+					EntryPoints: []string{"https"},
+					Certificate: &tls.Certificate{
+						CertFile: tls.FileOrContent("web"),
+						KeyFile:  tls.FileOrContent("web-key"),
+					},
+				},
+			},
+		},
+		{
+			desc:          "matching secret with missing entries skipped",
+			labelSelector: "traefik.io/certificate",
+			secrets: []*corev1.Secret{
+				secret("testing", "incomplete", map[string]string{"traefik.io/certificate": "true"}, "", map[string][]byte{"tls.crt": []byte("incomplete")}),
+				secret("testing", "web", map[string]string{"traefik.io/certificate": "true"}, "", tlsData("web")),
+			},
+			result: []*tls.Configuration{
+				{
+					Certificate: &tls.Certificate{
+						CertFile: tls.FileOrContent("web"),
+						KeyFile:  tls.FileOrContent("web-key"),
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tlsConfigs, err := getSecretsTLS(test.labelSelector, clientMock{secrets: test.secrets})
 
 			if test.errResult != "" {
 				assert.EqualError(t, err, test.errResult)
@@ -1925,3 +2126,93 @@ func TestGetTLS(t *testing.T) {
 		})
 	}
 }
+
+func TestGetClientCA(t *testing.T) {
+	testIngress := buildIngress(
+		iNamespace("testing"),
+		iAnnotation(annotationKubernetesClientCASecret, "ca-secret"),
+		iAnnotation(annotationKubernetesClientCAOptional, "true"),
+		iRules(iRule(iHost("tenant1.example.com"))),
+	)
+
+	tests := []struct {
+		desc      string
+		ingress   *extensionsv1beta1.Ingress
+		client    Client
+		result    *types.ClientCA
+		errResult string
+	}{
+		{
+			desc:    "no client CA secret",
+			ingress: buildIngress(iNamespace("testing"), iRules(iRule(iHost("tenant1.example.com")))),
+			client:  clientMock{},
+		},
+		{
+			desc:    "api client returns error",
+			ingress: testIngress,
+			client: clientMock{
+				apiSecretError: errors.New("api secret error"),
+			},
+			errResult: "failed to fetch secret testing/ca-secret: api secret error",
+		},
+		{
+			desc:      "api client doesn't find secret",
+			ingress:   testIngress,
+			client:    clientMock{},
+			errResult: "secret testing/ca-secret does not exist",
+		},
+		{
+			desc:    "entry 'ca.crt' in secret missing",
+			ingress: testIngress,
+			client: clientMock{
+				secrets: []*corev1.Secret{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "ca-secret",
+							Namespace: "testing",
+						},
+						Data: map[string][]byte{},
+					},
+				},
+			},
+			errResult: "secret testing/ca-secret is missing the ca.crt entry",
+		},
+		{
+			desc:    "client CA from the secret",
+			ingress: testIngress,
+			client: clientMock{
+				secrets: []*corev1.Secret{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "ca-secret",
+							Namespace: "testing",
+						},
+						Data: map[string][]byte{
+							"ca.crt": []byte("ca-crt"),
+						},
+					},
+				},
+			},
+			result: &types.ClientCA{
+				Files:    []tls.FileOrContent{tls.FileOrContent("ca-crt")},
+				Optional: true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			clientCA, err := getClientCA(test.ingress, test.client)
+
+			if test.errResult != "" {
+				assert.EqualError(t, err, test.errResult)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.result, clientCA)
+			}
+		})
+	}
+}
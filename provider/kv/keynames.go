@@ -6,15 +6,31 @@ const (
 	pathBackendHealthCheckPath                  = "/healthcheck/path"
 	pathBackendHealthCheckPort                  = "/healthcheck/port"
 	pathBackendHealthCheckInterval              = "/healthcheck/interval"
+	pathBackendHealthCheckType                  = "/healthcheck/type"
+	pathBackendHealthCheckService               = "/healthcheck/service"
+	pathBackendHealthCheckTimeout               = "/healthcheck/timeout"
+	pathBackendHealthCheckHostname              = "/healthcheck/hostname"
+	pathBackendHealthCheckHeaders               = "/healthcheck/headers/"
+	pathBackendHealthCheckProxyProtocol         = "/healthcheck/proxyprotocol"
 	pathBackendLoadBalancerMethod               = "/loadbalancer/method"
 	pathBackendLoadBalancerSticky               = "/loadbalancer/sticky"
 	pathBackendLoadBalancerStickiness           = "/loadbalancer/stickiness"
 	pathBackendLoadBalancerStickinessCookieName = "/loadbalancer/stickiness/cookiename"
+	pathBackendLoadBalancerStickinessHeader     = "/loadbalancer/stickiness/header"
+	pathBackendLoadBalancerStickinessQuery      = "/loadbalancer/stickiness/query"
 	pathBackendMaxConnAmount                    = "/maxconn/amount"
 	pathBackendMaxConnExtractorFunc             = "/maxconn/extractorfunc"
 	pathBackendServers                          = "/servers/"
 	pathBackendServerURL                        = "/url"
 	pathBackendServerWeight                     = "/weight"
+	pathBackendServerLabels                     = "/labels/"
+	pathBackendTLSServerName                    = "/tls/servername"
+	pathBackendTLSRootCAs                       = "/tls/rootcas"
+	pathBackendTLSVerification                  = "/tls/verification"
+	pathBackendAdaptiveLimit                    = "/adaptivelimit/"
+	pathBackendAdaptiveLimitInitial             = pathBackendAdaptiveLimit + "initial"
+	pathBackendAdaptiveLimitMin                 = pathBackendAdaptiveLimit + "min"
+	pathBackendAdaptiveLimitMax                 = pathBackendAdaptiveLimit + "max"
 	pathBackendBuffering                        = "/buffering/"
 	pathBackendBufferingMaxResponseBodyBytes    = pathBackendBuffering + "maxresponsebodybytes"
 	pathBackendBufferingMemResponseBodyBytes    = pathBackendBuffering + "memresponsebodybytes"
@@ -27,7 +43,20 @@ const (
 	pathFrontendPriority               = "/priority"
 	pathFrontendPassHostHeader         = "/passHostHeader"
 	pathFrontendPassTLSCert            = "/passtlscert"
+	pathFrontendHostHeaderStrategy     = "/hostheader/strategy"
+	pathFrontendHostHeaderValue        = "/hostheader/value"
+	pathFrontendServerSelector         = "/serverselector"
+	pathFrontendTLSPassthrough         = "/tlspassthrough"
+	pathFrontendTenant                 = "/tenant"
+	pathFrontendPriorityClass          = "/priorityclass"
+	pathFrontendACMEResolver           = "/acmeresolver"
+	pathFrontendTLSOptions             = "/tlsoptions"
+	pathFrontendClientCAFiles          = "/clientca/files"
+	pathFrontendClientCAOptional       = "/clientca/optional"
 	pathFrontendWhiteListSourceRange   = "/whitelistsourcerange"
+	pathFrontendIPStrategy             = "/whitelistipstrategy/"
+	pathFrontendIPStrategyDepth        = "/whitelistipstrategy/depth"
+	pathFrontendIPStrategyExcludedIPs  = "/whitelistipstrategy/excludedips"
 	pathFrontendBasicAuth              = "/basicauth"
 	pathFrontendEntryPoints            = "/entrypoints"
 	pathFrontendRedirectEntryPoint     = "/redirect/entrypoint"
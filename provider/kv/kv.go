@@ -11,6 +11,7 @@ import (
 	"github.com/cenk/backoff"
 	"github.com/containous/traefik/job"
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
 	"github.com/containous/traefik/provider"
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/types"
@@ -88,6 +89,7 @@ func (p *Provider) watchKv(configurationChan chan<- types.ConfigMessage, prefix
 
 	notify := func(err error, time time.Duration) {
 		log.Errorf("KV connection error: %+v, retrying in %s", err, time)
+		notification.Notify(notification.Event{Type: notification.ProviderDisconnected, Message: "Provider connection error", Provider: string(p.storeType), Error: err.Error()})
 	}
 	err := backoff.RetryNotify(safe.OperationWithRecover(operation), job.NewBackOff(backoff.NewExponentialBackOff()), notify)
 	if err != nil {
@@ -120,6 +122,7 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 	}
 	notify := func(err error, time time.Duration) {
 		log.Errorf("KV connection error: %+v, retrying in %s", err, time)
+		notification.Notify(notification.Event{Type: notification.ProviderDisconnected, Message: "Provider connection error", Provider: string(p.storeType), Error: err.Error()})
 	}
 	err := backoff.RetryNotify(safe.OperationWithRecover(operation), job.NewBackOff(backoff.NewExponentialBackOff()), notify)
 	if err != nil {
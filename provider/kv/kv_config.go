@@ -50,6 +50,15 @@ func (p *Provider) buildConfiguration() *types.Configuration {
 		"getBasicAuth":            p.getFuncList(pathFrontendBasicAuth),
 		"getRoutes":               p.getRoutes,
 		"getRedirect":             p.getRedirect,
+		"getHostHeader":           p.getHostHeader,
+		"getClientCA":             p.getClientCA,
+		"getWhitelistIPStrategy":  p.getWhitelistIPStrategy,
+		"getServerSelector":       p.getFuncString(pathFrontendServerSelector, ""),
+		"getTLSPassthrough":       p.getFuncBool(pathFrontendTLSPassthrough, false),
+		"getTenant":               p.getFuncString(pathFrontendTenant, ""),
+		"getPriorityClass":        p.getFuncString(pathFrontendPriorityClass, ""),
+		"getACMEResolver":         p.getFuncString(pathFrontendACMEResolver, ""),
+		"getTLSOptions":           p.getFuncString(pathFrontendTLSOptions, ""),
 		"getErrorPages":           p.getErrorPages,
 		"getRateLimit":            p.getRateLimit,
 		"getHeaders":              p.getHeaders,
@@ -61,6 +70,8 @@ func (p *Provider) buildConfiguration() *types.Configuration {
 		"getMaxConn":              p.getMaxConn,
 		"getHealthCheck":          p.getHealthCheck,
 		"getBuffering":            p.getBuffering,
+		"getAdaptiveLimit":        p.getAdaptiveLimit,
+		"getBackendTLS":           p.getBackendTLS,
 		"getSticky":               p.getSticky,               // Deprecated [breaking]
 		"hasStickinessLabel":      p.hasStickinessLabel,      // Deprecated [breaking]
 		"getStickinessCookieName": p.getStickinessCookieName, // Deprecated [breaking]
@@ -128,6 +139,43 @@ func (p *Provider) getRedirect(rootPath string) *types.Redirect {
 	return nil
 }
 
+func (p *Provider) getHostHeader(rootPath string) *types.HostHeader {
+	if !p.has(rootPath, pathFrontendHostHeaderStrategy) {
+		return nil
+	}
+
+	return &types.HostHeader{
+		Strategy: p.get("", rootPath, pathFrontendHostHeaderStrategy),
+		Value:    p.get("", rootPath, pathFrontendHostHeaderValue),
+	}
+}
+
+func (p *Provider) getClientCA(rootPath string) *types.ClientCA {
+	files := p.getList(rootPath, pathFrontendClientCAFiles)
+	if len(files) == 0 {
+		return nil
+	}
+
+	clientCA := &types.ClientCA{
+		Optional: p.getBool(false, rootPath, pathFrontendClientCAOptional),
+	}
+	for _, file := range files {
+		clientCA.Files = append(clientCA.Files, tls.FileOrContent(file))
+	}
+	return clientCA
+}
+
+func (p *Provider) getWhitelistIPStrategy(rootPath string) *types.IPStrategy {
+	if len(p.list(rootPath, pathFrontendIPStrategy)) == 0 {
+		return nil
+	}
+
+	return &types.IPStrategy{
+		Depth:       p.getInt(0, rootPath, pathFrontendIPStrategyDepth),
+		ExcludedIPs: p.getList(rootPath, pathFrontendIPStrategyExcludedIPs),
+	}
+}
+
 func (p *Provider) getErrorPages(rootPath string) map[string]*types.ErrorPage {
 	var errorPages map[string]*types.ErrorPage
 
@@ -232,6 +280,16 @@ func (p *Provider) getLoadBalancer(rootPath string) *types.LoadBalancer {
 		}
 	}
 
+	header := p.get("", rootPath, pathBackendLoadBalancerStickinessHeader)
+	query := p.get("", rootPath, pathBackendLoadBalancerStickinessQuery)
+	if len(header) > 0 || len(query) > 0 {
+		if lb.Stickiness == nil {
+			lb.Stickiness = &types.Stickiness{}
+		}
+		lb.Stickiness.Header = header
+		lb.Stickiness.Query = query
+	}
+
 	return lb
 }
 
@@ -264,8 +322,9 @@ func (p *Provider) getMaxConn(rootPath string) *types.MaxConn {
 
 func (p *Provider) getHealthCheck(rootPath string) *types.HealthCheck {
 	path := p.get("", rootPath, pathBackendHealthCheckPath)
+	hcType := p.get("", rootPath, pathBackendHealthCheckType)
 
-	if len(path) == 0 {
+	if len(path) == 0 && hcType != types.HealthCheckTypeGRPC && hcType != types.HealthCheckTypeTCP {
 		return nil
 	}
 
@@ -273,9 +332,32 @@ func (p *Provider) getHealthCheck(rootPath string) *types.HealthCheck {
 	interval := p.get("30s", rootPath, pathBackendHealthCheckInterval)
 
 	return &types.HealthCheck{
-		Path:     path,
-		Port:     port,
-		Interval: interval,
+		Type:          hcType,
+		Path:          path,
+		Port:          port,
+		Service:       p.get("", rootPath, pathBackendHealthCheckService),
+		Interval:      interval,
+		Timeout:       p.get("", rootPath, pathBackendHealthCheckTimeout),
+		Hostname:      p.get("", rootPath, pathBackendHealthCheckHostname),
+		Headers:       p.getMap(rootPath, pathBackendHealthCheckHeaders),
+		ProxyProtocol: p.getInt(0, rootPath, pathBackendHealthCheckProxyProtocol),
+	}
+}
+
+func (p *Provider) getBackendTLS(rootPath string) *types.BackendTLS {
+	if !p.has(rootPath, pathBackendTLSServerName) && !p.has(rootPath, pathBackendTLSRootCAs) && !p.has(rootPath, pathBackendTLSVerification) {
+		return nil
+	}
+
+	var rootCAs tls.RootCAs
+	for _, rootCA := range p.getList(rootPath, pathBackendTLSRootCAs) {
+		rootCAs = append(rootCAs, tls.FileOrContent(rootCA))
+	}
+
+	return &types.BackendTLS{
+		ServerName:   p.get("", rootPath, pathBackendTLSServerName),
+		RootCAs:      rootCAs,
+		Verification: p.get("", rootPath, pathBackendTLSVerification),
 	}
 }
 
@@ -298,6 +380,18 @@ func (p *Provider) getBuffering(rootPath string) *types.Buffering {
 	return buffering
 }
 
+func (p *Provider) getAdaptiveLimit(rootPath string) *types.AdaptiveLimit {
+	if len(p.list(rootPath, pathBackendAdaptiveLimit)) == 0 {
+		return nil
+	}
+
+	return &types.AdaptiveLimit{
+		InitialLimit: p.getInt(0, rootPath, pathBackendAdaptiveLimitInitial),
+		MinLimit:     p.getInt(0, rootPath, pathBackendAdaptiveLimitMin),
+		MaxLimit:     p.getInt(0, rootPath, pathBackendAdaptiveLimitMax),
+	}
+}
+
 func (p *Provider) getTLSSection(prefix string) []*tls.Configuration {
 	var tlsSection []*tls.Configuration
 
@@ -372,12 +466,27 @@ func (p *Provider) getServers(rootPath string) map[string]types.Server {
 		servers[serverName] = types.Server{
 			URL:    serverURL,
 			Weight: p.getInt(0, serverKey, pathBackendServerWeight),
+			Labels: p.getServerLabels(serverKey),
 		}
 	}
 
 	return servers
 }
 
+func (p *Provider) getServerLabels(serverKey string) map[string]string {
+	var labels map[string]string
+
+	for _, name := range p.list(serverKey, pathBackendServerLabels) {
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+
+		labels[p.last(name)] = p.get("", name)
+	}
+
+	return labels
+}
+
 func (p *Provider) listServers(backend string) []string {
 	serverNames := p.list(backend, pathBackendServers)
 	return fun.Filter(p.serverFilter, serverNames).([]string)
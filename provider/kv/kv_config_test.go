@@ -89,6 +89,7 @@ func TestProviderBuildConfiguration(t *testing.T) {
 					withPair(pathFrontendPriority, "6"),
 					withPair(pathFrontendPassHostHeader, "false"),
 					withPair(pathFrontendPassTLSCert, "true"),
+					withPair(pathFrontendTLSPassthrough, "true"),
 					withPair(pathFrontendEntryPoints, "http,https"),
 					withPair(pathFrontendWhiteListSourceRange, "1.1.1.1/24, 1234:abcd::42/32"),
 					withPair(pathFrontendBasicAuth, "test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/, test2:$apr1$d9hr9HBB$4HxwgUir3HP4EsggP/QNo0"),
@@ -184,6 +185,7 @@ func TestProviderBuildConfiguration(t *testing.T) {
 						EntryPoints:          []string{"http", "https"},
 						Backend:              "backend1",
 						PassTLSCert:          true,
+						TLSPassthrough:       true,
 						WhitelistSourceRange: []string{"1.1.1.1/24", "1234:abcd::42/32"},
 						BasicAuth:            []string{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/", "test2:$apr1$d9hr9HBB$4HxwgUir3HP4EsggP/QNo0"},
 						Redirect: &types.Redirect{
@@ -1120,6 +1122,179 @@ func TestProviderGetRedirect(t *testing.T) {
 	}
 }
 
+func TestProviderGetHostHeader(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.HostHeader
+	}{
+		{
+			desc:     "should use strategy and value when they are valued in the store",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendHostHeaderStrategy, "custom"),
+					withPair(pathFrontendHostHeaderValue, "internal.local"))),
+			expected: &types.HostHeader{
+				Strategy: "custom",
+				Value:    "internal.local",
+			},
+		},
+		{
+			desc:     "should return nil when strategy key is not valued in the store",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendHostHeaderValue, "internal.local"))),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			actual := p.getHostHeader(test.rootPath)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestProviderGetClientCA(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.ClientCA
+	}{
+		{
+			desc:     "should use files and optional when they are valued in the store",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendClientCAFiles+"/0", "/certs/tenant1.crt"),
+					withPair(pathFrontendClientCAFiles+"/1", "/certs/tenant2.crt"),
+					withPair(pathFrontendClientCAOptional, "true"))),
+			expected: &types.ClientCA{
+				Files:    []tls.FileOrContent{"/certs/tenant1.crt", "/certs/tenant2.crt"},
+				Optional: true,
+			},
+		},
+		{
+			desc:     "should return nil when files key is not valued in the store",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendClientCAOptional, "true"))),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			actual := p.getClientCA(test.rootPath)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestProviderGetWhitelistIPStrategy(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.IPStrategy
+	}{
+		{
+			desc:     "should use depth and excluded IPs when they are valued in the store",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendIPStrategyDepth, "1"),
+					withPair(pathFrontendIPStrategyExcludedIPs, "10.0.0.0/8,192.168.0.1"))),
+			expected: &types.IPStrategy{
+				Depth:       1,
+				ExcludedIPs: []string{"10.0.0.0/8", "192.168.0.1"},
+			},
+		},
+		{
+			desc:     "should return nil when the IP strategy keys are not valued in the store",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendHostHeaderValue, "internal.local"))),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			actual := p.getWhitelistIPStrategy(test.rootPath)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestProviderGetBackendTLS(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.BackendTLS
+	}{
+		{
+			desc:     "when no TLS keys",
+			rootPath: "traefik/backends/foo",
+			kvPairs:  filler("traefik", backend("foo")),
+			expected: nil,
+		},
+		{
+			desc:     "when all TLS keys",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendTLSServerName, "internal.local"),
+					withPair(pathBackendTLSRootCAs, "/ca1.pem,/ca2.pem"),
+					withPair(pathBackendTLSVerification, "skipHostname"))),
+			expected: &types.BackendTLS{
+				ServerName:   "internal.local",
+				RootCAs:      tls.RootCAs{"/ca1.pem", "/ca2.pem"},
+				Verification: "skipHostname",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			actual := p.getBackendTLS(test.rootPath)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestProviderGetErrorPages(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -1529,6 +1704,19 @@ func TestProviderGetLoadBalancer(t *testing.T) {
 				Method: "wrr",
 			},
 		},
+		{
+			desc:     "when stickiness on a query parameter is set",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendLoadBalancerStickinessQuery, "tenant"))),
+			expected: &types.LoadBalancer{
+				Method: "wrr",
+				Stickiness: &types.Stickiness{
+					Query: "tenant",
+				},
+			},
+		},
 		{
 			desc:     "when method is set",
 			rootPath: "traefik/backends/foo",
@@ -1694,6 +1882,51 @@ func TestProviderGetMaxConn(t *testing.T) {
 	}
 }
 
+func TestProviderGetAdaptiveLimit(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.AdaptiveLimit
+	}{
+		{
+			desc:     "when adaptive limit keys are defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendAdaptiveLimitInitial, "50"),
+					withPair(pathBackendAdaptiveLimitMin, "5"),
+					withPair(pathBackendAdaptiveLimitMax, "200"))),
+			expected: &types.AdaptiveLimit{
+				InitialLimit: 50,
+				MinLimit:     5,
+				MaxLimit:     200,
+			},
+		},
+		{
+			desc:     "should return nil when no adaptive limit keys are defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendMaxConnAmount, "5"))),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			result := p.getAdaptiveLimit(test.rootPath)
+
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
 func TestProviderGetHealthCheck(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -1715,6 +1948,51 @@ func TestProviderGetHealthCheck(t *testing.T) {
 				Port:     80,
 			},
 		},
+		{
+			desc:     "when gRPC type defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendHealthCheckType, "grpc"),
+					withPair(pathBackendHealthCheckService, "foo.Bar"))),
+			expected: &types.HealthCheck{
+				Type:     "grpc",
+				Service:  "foo.Bar",
+				Interval: "30s",
+			},
+		},
+		{
+			desc:     "when TCP type defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendHealthCheckType, "tcp"),
+					withPair(pathBackendHealthCheckTimeout, "1s"))),
+			expected: &types.HealthCheck{
+				Type:     "tcp",
+				Interval: "30s",
+				Timeout:  "1s",
+			},
+		},
+		{
+			desc:     "when probe keys defined",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendHealthCheckPath, "/health"),
+					withPair(pathBackendHealthCheckHostname, "foo.com"),
+					withPair(pathBackendHealthCheckHeaders+"X-Foo", "bar"),
+					withPair(pathBackendHealthCheckProxyProtocol, "1"))),
+			expected: &types.HealthCheck{
+				Interval: "30s",
+				Path:     "/health",
+				Hostname: "foo.com",
+				Headers: map[string]string{
+					"X-Foo": "bar",
+				},
+				ProxyProtocol: 1,
+			},
+		},
 		{
 			desc:     "when only path defined",
 			rootPath: "traefik/backends/foo",
@@ -1969,6 +2247,25 @@ func TestProviderGetServers(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:     "should return the labels of the servers",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendServers+"server1/url", "http://172.17.0.2:80"),
+					withPair(pathBackendServers+"server1/labels/zone", "eu-west-1"),
+					withPair(pathBackendServers+"server1/labels/version", "v2"))),
+			expected: map[string]types.Server{
+				"server1": {
+					URL:    "http://172.17.0.2:80",
+					Weight: 0,
+					Labels: map[string]string{
+						"zone":    "eu-west-1",
+						"version": "v2",
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
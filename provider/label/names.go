@@ -17,13 +17,28 @@ const (
 	SuffixBackendHealthCheckPath                   = "backend.healthcheck.path"
 	SuffixBackendHealthCheckPort                   = "backend.healthcheck.port"
 	SuffixBackendHealthCheckInterval               = "backend.healthcheck.interval"
+	SuffixBackendHealthCheckType                   = "backend.healthcheck.type"
+	SuffixBackendHealthCheckService                = "backend.healthcheck.service"
+	SuffixBackendHealthCheckTimeout                = "backend.healthcheck.timeout"
+	SuffixBackendHealthCheckHostname               = "backend.healthcheck.hostname"
+	SuffixBackendHealthCheckHeaders                = "backend.healthcheck.headers"
+	SuffixBackendHealthCheckProxyProtocol          = "backend.healthcheck.proxyProtocol"
 	SuffixBackendLoadBalancer                      = "backend.loadbalancer"
 	SuffixBackendLoadBalancerMethod                = SuffixBackendLoadBalancer + ".method"
 	SuffixBackendLoadBalancerSticky                = SuffixBackendLoadBalancer + ".sticky"
 	SuffixBackendLoadBalancerStickiness            = SuffixBackendLoadBalancer + ".stickiness"
 	SuffixBackendLoadBalancerStickinessCookieName  = SuffixBackendLoadBalancer + ".stickiness.cookieName"
+	SuffixBackendLoadBalancerStickinessHeader      = SuffixBackendLoadBalancer + ".stickiness.header"
+	SuffixBackendLoadBalancerStickinessQuery       = SuffixBackendLoadBalancer + ".stickiness.query"
 	SuffixBackendMaxConnAmount                     = "backend.maxconn.amount"
+	SuffixBackendServerLabel                       = "backend.server.label."
+	SuffixBackendTLSServerName                     = "backend.tls.serverName"
+	SuffixBackendTLSVerification                   = "backend.tls.verification"
 	SuffixBackendMaxConnExtractorFunc              = "backend.maxconn.extractorfunc"
+	SuffixBackendAdaptiveLimit                     = "backend.adaptiveLimit"
+	SuffixBackendAdaptiveLimitInitial              = SuffixBackendAdaptiveLimit + ".initial"
+	SuffixBackendAdaptiveLimitMin                  = SuffixBackendAdaptiveLimit + ".min"
+	SuffixBackendAdaptiveLimitMax                  = SuffixBackendAdaptiveLimit + ".max"
 	SuffixBackendBuffering                         = "backend.buffering"
 	SuffixBackendBufferingMaxRequestBodyBytes      = SuffixBackendBuffering + ".maxRequestBodyBytes"
 	SuffixBackendBufferingMemRequestBodyBytes      = SuffixBackendBuffering + ".memRequestBodyBytes"
@@ -56,6 +71,8 @@ const (
 	SuffixFrontendHeadersPublicKey                 = SuffixFrontendHeaders + "publicKey"
 	SuffixFrontendHeadersReferrerPolicy            = SuffixFrontendHeaders + "referrerPolicy"
 	SuffixFrontendHeadersIsDevelopment             = SuffixFrontendHeaders + "isDevelopment"
+	SuffixFrontendHostHeaderStrategy               = "frontend.hostHeader.strategy"
+	SuffixFrontendHostHeaderValue                  = "frontend.hostHeader.value"
 	SuffixFrontendPassHostHeader                   = "frontend.passHostHeader"
 	SuffixFrontendPassTLSCert                      = "frontend.passTLSCert"
 	SuffixFrontendPriority                         = "frontend.priority"
@@ -65,8 +82,18 @@ const (
 	SuffixFrontendRedirectReplacement              = "frontend.redirect.replacement"
 	SuffixFrontendRedirectPermanent                = "frontend.redirect.permanent"
 	SuffixFrontendRule                             = "frontend.rule"
+	SuffixFrontendServerSelector                   = "frontend.serverSelector"
+	SuffixFrontendTLSPassthrough                   = "frontend.tlsPassthrough"
+	SuffixFrontendTenant                           = "frontend.tenant"
+	SuffixFrontendPriorityClass                    = "frontend.priorityClass"
+	SuffixFrontendACMEResolver                     = "frontend.acmeResolver"
+	SuffixFrontendTLSOptions                       = "frontend.tlsOptions"
+	SuffixFrontendClientCAFiles                    = "frontend.clientCA.files"
+	SuffixFrontendClientCAOptional                 = "frontend.clientCA.optional"
 	SuffixFrontendRuleType                         = "frontend.rule.type"
 	SuffixFrontendWhitelistSourceRange             = "frontend.whitelistSourceRange"
+	SuffixFrontendWhitelistIPStrategyDepth         = "frontend.whitelistIPStrategy.depth"
+	SuffixFrontendWhitelistIPStrategyExcludedIPs   = "frontend.whitelistIPStrategy.excludedIPs"
 	TraefikDomain                                  = Prefix + SuffixDomain
 	TraefikEnable                                  = Prefix + SuffixEnable
 	TraefikPort                                    = Prefix + SuffixPort
@@ -81,13 +108,28 @@ const (
 	TraefikBackendHealthCheckPath                  = Prefix + SuffixBackendHealthCheckPath
 	TraefikBackendHealthCheckPort                  = Prefix + SuffixBackendHealthCheckPort
 	TraefikBackendHealthCheckInterval              = Prefix + SuffixBackendHealthCheckInterval
+	TraefikBackendHealthCheckType                  = Prefix + SuffixBackendHealthCheckType
+	TraefikBackendHealthCheckService               = Prefix + SuffixBackendHealthCheckService
+	TraefikBackendHealthCheckTimeout               = Prefix + SuffixBackendHealthCheckTimeout
+	TraefikBackendHealthCheckHostname              = Prefix + SuffixBackendHealthCheckHostname
+	TraefikBackendHealthCheckHeaders               = Prefix + SuffixBackendHealthCheckHeaders
+	TraefikBackendHealthCheckProxyProtocol         = Prefix + SuffixBackendHealthCheckProxyProtocol
 	TraefikBackendLoadBalancer                     = Prefix + SuffixBackendLoadBalancer
 	TraefikBackendLoadBalancerMethod               = Prefix + SuffixBackendLoadBalancerMethod
 	TraefikBackendLoadBalancerSticky               = Prefix + SuffixBackendLoadBalancerSticky
 	TraefikBackendLoadBalancerStickiness           = Prefix + SuffixBackendLoadBalancerStickiness
 	TraefikBackendLoadBalancerStickinessCookieName = Prefix + SuffixBackendLoadBalancerStickinessCookieName
+	TraefikBackendLoadBalancerStickinessHeader     = Prefix + SuffixBackendLoadBalancerStickinessHeader
+	TraefikBackendLoadBalancerStickinessQuery      = Prefix + SuffixBackendLoadBalancerStickinessQuery
 	TraefikBackendMaxConnAmount                    = Prefix + SuffixBackendMaxConnAmount
+	TraefikBackendServerLabel                      = Prefix + SuffixBackendServerLabel
+	TraefikBackendTLSServerName                    = Prefix + SuffixBackendTLSServerName
+	TraefikBackendTLSVerification                  = Prefix + SuffixBackendTLSVerification
 	TraefikBackendMaxConnExtractorFunc             = Prefix + SuffixBackendMaxConnExtractorFunc
+	TraefikBackendAdaptiveLimit                    = Prefix + SuffixBackendAdaptiveLimit
+	TraefikBackendAdaptiveLimitInitial             = Prefix + SuffixBackendAdaptiveLimitInitial
+	TraefikBackendAdaptiveLimitMin                 = Prefix + SuffixBackendAdaptiveLimitMin
+	TraefikBackendAdaptiveLimitMax                 = Prefix + SuffixBackendAdaptiveLimitMax
 	TraefikBackendBuffering                        = Prefix + SuffixBackendBuffering
 	TraefikBackendBufferingMaxRequestBodyBytes     = Prefix + SuffixBackendBufferingMaxRequestBodyBytes
 	TraefikBackendBufferingMemRequestBodyBytes     = Prefix + SuffixBackendBufferingMemRequestBodyBytes
@@ -97,6 +139,8 @@ const (
 	TraefikFrontend                                = Prefix + SuffixFrontend
 	TraefikFrontendAuthBasic                       = Prefix + SuffixFrontendAuthBasic
 	TraefikFrontendEntryPoints                     = Prefix + SuffixFrontendEntryPoints
+	TraefikFrontendHostHeaderStrategy              = Prefix + SuffixFrontendHostHeaderStrategy
+	TraefikFrontendHostHeaderValue                 = Prefix + SuffixFrontendHostHeaderValue
 	TraefikFrontendPassHostHeader                  = Prefix + SuffixFrontendPassHostHeader
 	TraefikFrontendPassTLSCert                     = Prefix + SuffixFrontendPassTLSCert
 	TraefikFrontendPriority                        = Prefix + SuffixFrontendPriority
@@ -107,7 +151,17 @@ const (
 	TraefikFrontendRedirectPermanent               = Prefix + SuffixFrontendRedirectPermanent
 	TraefikFrontendRule                            = Prefix + SuffixFrontendRule
 	TraefikFrontendRuleType                        = Prefix + SuffixFrontendRuleType // k8s only
+	TraefikFrontendServerSelector                  = Prefix + SuffixFrontendServerSelector
+	TraefikFrontendTLSPassthrough                  = Prefix + SuffixFrontendTLSPassthrough
+	TraefikFrontendTenant                          = Prefix + SuffixFrontendTenant
+	TraefikFrontendPriorityClass                   = Prefix + SuffixFrontendPriorityClass
+	TraefikFrontendACMEResolver                    = Prefix + SuffixFrontendACMEResolver
+	TraefikFrontendTLSOptions                      = Prefix + SuffixFrontendTLSOptions
+	TraefikFrontendClientCAFiles                   = Prefix + SuffixFrontendClientCAFiles
+	TraefikFrontendClientCAOptional                = Prefix + SuffixFrontendClientCAOptional
 	TraefikFrontendWhitelistSourceRange            = Prefix + SuffixFrontendWhitelistSourceRange
+	TraefikFrontendWhitelistIPStrategyDepth        = Prefix + SuffixFrontendWhitelistIPStrategyDepth
+	TraefikFrontendWhitelistIPStrategyExcludedIPs  = Prefix + SuffixFrontendWhitelistIPStrategyExcludedIPs
 	TraefikFrontendHeaders                         = Prefix + SuffixFrontendHeaders
 	TraefikFrontendRequestHeaders                  = Prefix + SuffixFrontendRequestHeaders
 	TraefikFrontendResponseHeaders                 = Prefix + SuffixFrontendResponseHeaders
@@ -93,6 +93,13 @@ func (p *Provider) buildConfiguration() *types.Configuration {
 		return nil
 	}
 
+	// the applications are still configured when the pods can't be retrieved
+	pods, err := p.getPods()
+	if err != nil {
+		log.Errorf("Failed to retrieve Marathon pods: %v", err)
+	}
+	applications.Apps = append(applications.Apps, podApplications(pods)...)
+
 	filteredApps := fun.Filter(p.applicationFilter, applications.Apps).([]marathon.Application)
 	for i, app := range filteredApps {
 		filteredApps[i].Tasks = fun.Filter(func(task *marathon.Task) bool {
@@ -9,6 +9,7 @@ import (
 	"github.com/containous/flaeg"
 	"github.com/containous/traefik/job"
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
 	"github.com/containous/traefik/provider"
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/types"
@@ -46,21 +47,23 @@ var _ provider.Provider = (*Provider)(nil)
 // Provider holds configuration of the provider.
 type Provider struct {
 	provider.BaseProvider
-	Endpoint                  string           `description:"Marathon server endpoint. You can also specify multiple endpoint for Marathon" export:"true"`
-	Domain                    string           `description:"Default domain used" export:"true"`
-	ExposedByDefault          bool             `description:"Expose Marathon apps by default" export:"true"`
-	GroupsAsSubDomains        bool             `description:"Convert Marathon groups to subdomains" export:"true"`
-	DCOSToken                 string           `description:"DCOSToken for DCOS environment, This will override the Authorization header" export:"true"`
-	MarathonLBCompatibility   bool             `description:"Add compatibility with marathon-lb labels" export:"true"`
-	FilterMarathonConstraints bool             `description:"Enable use of Marathon constraints in constraint filtering" export:"true"`
-	TLS                       *types.ClientTLS `description:"Enable TLS support" export:"true"`
-	DialerTimeout             flaeg.Duration   `description:"Set a non-default connection timeout for Marathon" export:"true"`
-	KeepAlive                 flaeg.Duration   `description:"Set a non-default TCP Keep Alive time in seconds" export:"true"`
-	ForceTaskHostname         bool             `description:"Force to use the task's hostname." export:"true"`
-	Basic                     *Basic           `description:"Enable basic authentication" export:"true"`
-	RespectReadinessChecks    bool             `description:"Filter out tasks with non-successful readiness checks during deployments" export:"true"`
+	Endpoint                  string               `description:"Marathon server endpoint. You can also specify multiple endpoint for Marathon" export:"true"`
+	Domain                    string               `description:"Default domain used" export:"true"`
+	ExposedByDefault          bool                 `description:"Expose Marathon apps by default" export:"true"`
+	GroupsAsSubDomains        bool                 `description:"Convert Marathon groups to subdomains" export:"true"`
+	DCOSToken                 string               `description:"DCOSToken for DCOS environment, This will override the Authorization header" export:"true"`
+	MarathonLBCompatibility   bool                 `description:"Add compatibility with marathon-lb labels" export:"true"`
+	FilterMarathonConstraints bool                 `description:"Enable use of Marathon constraints in constraint filtering" export:"true"`
+	TLS                       *types.ClientTLS     `description:"Enable TLS support" export:"true"`
+	DialerTimeout             flaeg.Duration       `description:"Set a non-default connection timeout for Marathon" export:"true"`
+	KeepAlive                 flaeg.Duration       `description:"Set a non-default TCP Keep Alive time in seconds" export:"true"`
+	ForceTaskHostname         bool                 `description:"Force to use the task's hostname." export:"true"`
+	Basic                     *Basic               `description:"Enable basic authentication" export:"true"`
+	RespectReadinessChecks    bool                 `description:"Filter out tasks with non-successful readiness checks during deployments" export:"true"`
+	Proxy                     *types.OutboundProxy `description:"Outbound proxy used to reach Marathon" export:"true"`
 	readyChecker              *readinessChecker
 	marathonClient            marathon.Marathon
+	podsClient                *http.Client
 }
 
 // Basic holds basic authentication specific configurations
@@ -98,14 +101,18 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 		if err != nil {
 			return err
 		}
+		transport := &http.Transport{
+			DialContext: (&net.Dialer{
+				KeepAlive: time.Duration(p.KeepAlive),
+				Timeout:   time.Duration(p.DialerTimeout),
+			}).DialContext,
+			TLSClientConfig: TLSConfig,
+		}
+		if err := p.Proxy.ConfigureTransport(transport); err != nil {
+			return err
+		}
 		config.HTTPClient = &http.Client{
-			Transport: &http.Transport{
-				DialContext: (&net.Dialer{
-					KeepAlive: time.Duration(p.KeepAlive),
-					Timeout:   time.Duration(p.DialerTimeout),
-				}).DialContext,
-				TLSClientConfig: TLSConfig,
-			},
+			Transport: transport,
 		}
 		client, err := marathon.NewClient(config)
 		if err != nil {
@@ -113,6 +120,7 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 			return err
 		}
 		p.marathonClient = client
+		p.podsClient = config.HTTPClient
 
 		if p.Watch {
 			update, err := client.AddEventsListener(marathonEventIDs)
@@ -149,6 +157,7 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 
 	notify := func(err error, time time.Duration) {
 		log.Errorf("Provider connection error %+v, retrying in %s", err, time)
+		notification.Notify(notification.Event{Type: notification.ProviderDisconnected, Message: "Provider connection error", Provider: "marathon", Error: err.Error()})
 	}
 	err := backoff.RetryNotify(safe.OperationWithRecover(operation), job.NewBackOff(backoff.NewExponentialBackOff()), notify)
 	if err != nil {
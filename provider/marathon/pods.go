@@ -0,0 +1,218 @@
+package marathon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/containous/traefik/log"
+	"github.com/gambol99/go-marathon"
+)
+
+const (
+	podsStatusPath      = "/v2/pods/::status"
+	podNetworkContainer = "container"
+)
+
+// podStatus is the status of a Marathon pod, as returned by the pods API.
+type podStatus struct {
+	ID        string        `json:"id"`
+	Spec      podSpec       `json:"spec"`
+	Instances []podInstance `json:"instances"`
+}
+
+type podSpec struct {
+	Labels     map[string]string `json:"labels"`
+	Containers []podContainer    `json:"containers"`
+	Networks   []podNetwork      `json:"networks"`
+}
+
+type podContainer struct {
+	Name      string            `json:"name"`
+	Labels    map[string]string `json:"labels"`
+	Endpoints []podEndpoint     `json:"endpoints"`
+}
+
+type podEndpoint struct {
+	Name          string `json:"name"`
+	ContainerPort int    `json:"containerPort"`
+	HostPort      int    `json:"hostPort"`
+}
+
+type podNetwork struct {
+	Name string `json:"name"`
+	Mode string `json:"mode"`
+}
+
+type podInstance struct {
+	ID            string               `json:"id"`
+	AgentHostname string               `json:"agentHostname"`
+	Networks      []podInstanceNetwork `json:"networks"`
+	Containers    []podContainerStatus `json:"containers"`
+}
+
+type podInstanceNetwork struct {
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+}
+
+type podContainerStatus struct {
+	Name        string                       `json:"name"`
+	Status      string                       `json:"status"`
+	ContainerID string                       `json:"containerId"`
+	Endpoints   []podContainerEndpointStatus `json:"endpoints"`
+}
+
+type podContainerEndpointStatus struct {
+	Name              string `json:"name"`
+	AllocatedHostPort int    `json:"allocatedHostPort"`
+}
+
+// getPods retrieves the status of the pods from the first Marathon endpoint answering.
+// The pods are not supported by the versions of Marathon prior to 1.4, which answer with a 404.
+func (p *Provider) getPods() ([]podStatus, error) {
+	if p.podsClient == nil {
+		// the client is created by Provide
+		return nil, nil
+	}
+
+	var err error
+	for _, endpoint := range strings.Split(p.Endpoint, ",") {
+		var pods []podStatus
+		pods, err = p.getPodsFromEndpoint(strings.TrimRight(strings.TrimSpace(endpoint), "/"))
+		if err == nil {
+			return pods, nil
+		}
+		log.Debugf("Failed to retrieve Marathon pods from %s: %v", endpoint, err)
+	}
+	return nil, err
+}
+
+func (p *Provider) getPodsFromEndpoint(endpoint string) ([]podStatus, error) {
+	req, err := http.NewRequest(http.MethodGet, endpoint+podsStatusPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if len(p.DCOSToken) > 0 {
+		req.Header.Set("Authorization", "token="+p.DCOSToken)
+	} else if p.Basic != nil {
+		req.SetBasicAuth(p.Basic.HTTPBasicAuthUser, p.Basic.HTTPBasicPassword)
+	}
+
+	resp, err := p.podsClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		log.Debugf("Marathon pods are not supported by %s", endpoint)
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var pods []podStatus
+	if err := json.NewDecoder(resp.Body).Decode(&pods); err != nil {
+		return nil, fmt.Errorf("unable to decode the pods: %v", err)
+	}
+	return pods, nil
+}
+
+// podApplications converts each container of the pods exposing endpoints into an application,
+// identified by the pod ID followed by the container name, so that it is configured by the labels
+// of the pod overridden by the ones of the container.
+// Each instance of the pod gives a task of the application, using the ports allocated on the host
+// to the endpoints of the container, or their container ports and the instance addresses
+// when the pod is attached to a container network.
+func podApplications(pods []podStatus) []marathon.Application {
+	var applications []marathon.Application
+	for _, pod := range pods {
+		containerNetwork := false
+		for _, network := range pod.Spec.Networks {
+			if network.Mode == podNetworkContainer {
+				containerNetwork = true
+			}
+		}
+
+		for _, container := range pod.Spec.Containers {
+			if len(container.Endpoints) == 0 {
+				log.Debugf("Filtering Marathon pod %s container %s without endpoints", pod.ID, container.Name)
+				continue
+			}
+
+			labels := make(map[string]string)
+			for key, value := range pod.Spec.Labels {
+				labels[key] = value
+			}
+			for key, value := range container.Labels {
+				labels[key] = value
+			}
+
+			app := marathon.Application{
+				ID:     pod.ID + "/" + container.Name,
+				Labels: &labels,
+			}
+
+			if containerNetwork {
+				var ports []marathon.Port
+				for _, endpoint := range container.Endpoints {
+					ports = append(ports, marathon.Port{Number: endpoint.ContainerPort, Name: endpoint.Name})
+				}
+				app.IPAddressPerTask = &marathon.IPAddressPerTask{Discovery: &marathon.Discovery{Ports: &ports}}
+			}
+
+			for _, instance := range pod.Instances {
+				if task := podTask(app.ID, instance, container, containerNetwork); task != nil {
+					app.Tasks = append(app.Tasks, task)
+				}
+			}
+
+			applications = append(applications, app)
+		}
+	}
+	return applications
+}
+
+// podTask returns the task of a container of a pod instance, or nil if the instance doesn't run the container.
+func podTask(appID string, instance podInstance, container podContainer, containerNetwork bool) *marathon.Task {
+	for _, status := range instance.Containers {
+		if status.Name != container.Name {
+			continue
+		}
+
+		task := &marathon.Task{
+			ID:    status.ContainerID,
+			AppID: appID,
+			Host:  instance.AgentHostname,
+			State: status.Status,
+		}
+		if len(task.ID) == 0 {
+			task.ID = instance.ID + "." + container.Name
+		}
+
+		if containerNetwork {
+			for _, network := range instance.Networks {
+				for _, address := range network.Addresses {
+					task.IPAddresses = append(task.IPAddresses, &marathon.IPAddress{IPAddress: address})
+				}
+			}
+			return task
+		}
+
+		// the ports are in the order of the endpoints of the container
+		for _, endpoint := range container.Endpoints {
+			for _, endpointStatus := range status.Endpoints {
+				if endpointStatus.Name == endpoint.Name && endpointStatus.AllocatedHostPort > 0 {
+					task.Ports = append(task.Ports, endpointStatus.AllocatedHostPort)
+				}
+			}
+		}
+		return task
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package marathon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/types"
+	"github.com/gambol99/go-marathon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const podsContent = `[{
+  "id": "/shop",
+  "spec": {
+    "id": "/shop",
+    "labels": {"traefik.frontend.entryPoints": "http"},
+    "containers": [
+      {
+        "name": "web",
+        "labels": {"traefik.frontend.rule": "Host:shop.docker.localhost"},
+        "endpoints": [{"name": "http", "containerPort": 80, "hostPort": 0, "protocol": ["tcp"]}]
+      },
+      {
+        "name": "metrics-exporter"
+      }
+    ],
+    "networks": [{"mode": "host"}]
+  },
+  "instances": [
+    {
+      "id": "shop.instance-1",
+      "agentHostname": "10.0.0.1",
+      "containers": [
+        {"name": "web", "status": "TASK_RUNNING", "containerId": "shop.instance-1.web", "endpoints": [{"name": "http", "allocatedHostPort": 31000}]},
+        {"name": "metrics-exporter", "status": "TASK_RUNNING", "containerId": "shop.instance-1.metrics-exporter"}
+      ]
+    }
+  ]
+}]`
+
+func TestPodApplications(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		pods     []podStatus
+		expected []marathon.Application
+	}{
+		{
+			desc: "host network",
+			pods: []podStatus{
+				{
+					ID: "/shop",
+					Spec: podSpec{
+						Labels: map[string]string{"traefik.frontend.entryPoints": "http", "traefik.weight": "1"},
+						Containers: []podContainer{
+							{
+								Name:   "web",
+								Labels: map[string]string{"traefik.weight": "2"},
+								Endpoints: []podEndpoint{
+									{Name: "http", ContainerPort: 80},
+									{Name: "admin", ContainerPort: 8080},
+								},
+							},
+							{Name: "sidecar"},
+						},
+						Networks: []podNetwork{{Mode: "host"}},
+					},
+					Instances: []podInstance{
+						{
+							ID:            "shop.instance-1",
+							AgentHostname: "10.0.0.1",
+							Containers: []podContainerStatus{
+								{
+									Name:   "web",
+									Status: "TASK_RUNNING",
+									Endpoints: []podContainerEndpointStatus{
+										{Name: "admin", AllocatedHostPort: 31001},
+										{Name: "http", AllocatedHostPort: 31000},
+									},
+								},
+								{Name: "sidecar", Status: "TASK_RUNNING"},
+							},
+						},
+						{
+							ID:            "shop.instance-2",
+							AgentHostname: "10.0.0.2",
+						},
+					},
+				},
+			},
+			expected: []marathon.Application{
+				{
+					ID:     "/shop/web",
+					Labels: &map[string]string{"traefik.frontend.entryPoints": "http", "traefik.weight": "2"},
+					Tasks: []*marathon.Task{
+						{
+							ID:    "shop.instance-1.web",
+							AppID: "/shop/web",
+							Host:  "10.0.0.1",
+							State: "TASK_RUNNING",
+							Ports: []int{31000, 31001},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "container network",
+			pods: []podStatus{
+				{
+					ID: "/shop",
+					Spec: podSpec{
+						Containers: []podContainer{
+							{
+								Name:      "web",
+								Endpoints: []podEndpoint{{Name: "http", ContainerPort: 80}},
+							},
+						},
+						Networks: []podNetwork{{Name: "dcos", Mode: "container"}},
+					},
+					Instances: []podInstance{
+						{
+							ID:            "shop.instance-1",
+							AgentHostname: "10.0.0.1",
+							Networks:      []podInstanceNetwork{{Name: "dcos", Addresses: []string{"9.0.0.1"}}},
+							Containers: []podContainerStatus{
+								{Name: "web", Status: "TASK_STAGING", ContainerID: "shop.instance-1.web"},
+							},
+						},
+					},
+				},
+			},
+			expected: []marathon.Application{
+				{
+					ID:     "/shop/web",
+					Labels: &map[string]string{},
+					IPAddressPerTask: &marathon.IPAddressPerTask{
+						Discovery: &marathon.Discovery{Ports: &[]marathon.Port{{Number: 80, Name: "http"}}},
+					},
+					Tasks: []*marathon.Task{
+						{
+							ID:          "shop.instance-1.web",
+							AppID:       "/shop/web",
+							Host:        "10.0.0.1",
+							State:       "TASK_STAGING",
+							IPAddresses: []*marathon.IPAddress{{IPAddress: "9.0.0.1"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual := podApplications(test.pods)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestGetPods(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		provider      Provider
+		status        int
+		expectedAuth  string
+		expectedPods  int
+		expectedError string
+	}{
+		{
+			desc:         "pods",
+			status:       http.StatusOK,
+			expectedPods: 1,
+		},
+		{
+			desc:         "dcos token",
+			provider:     Provider{DCOSToken: "secret"},
+			status:       http.StatusOK,
+			expectedAuth: "token=secret",
+			expectedPods: 1,
+		},
+		{
+			desc:         "basic authentication",
+			provider:     Provider{Basic: &Basic{HTTPBasicAuthUser: "user", HTTPBasicPassword: "password"}},
+			status:       http.StatusOK,
+			expectedAuth: "Basic dXNlcjpwYXNzd29yZA==",
+			expectedPods: 1,
+		},
+		{
+			desc:   "pods not supported",
+			status: http.StatusNotFound,
+		},
+		{
+			desc:          "unexpected status",
+			status:        http.StatusForbidden,
+			expectedError: "unexpected status code 403",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var authorization string
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != podsStatusPath {
+					rw.WriteHeader(http.StatusNotFound)
+					return
+				}
+				authorization = req.Header.Get("Authorization")
+				rw.WriteHeader(test.status)
+				rw.Write([]byte(podsContent))
+			}))
+			defer server.Close()
+
+			p := test.provider
+			p.Endpoint = "http://127.0.0.1:1," + server.URL
+			p.podsClient = server.Client()
+
+			pods, err := p.getPods()
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, pods, test.expectedPods)
+			assert.Equal(t, test.expectedAuth, authorization)
+		})
+	}
+}
+
+func TestBuildConfigurationWithPods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(podsContent))
+	}))
+	defer server.Close()
+
+	p := &Provider{
+		Endpoint:         server.URL,
+		Domain:           "docker.localhost",
+		ExposedByDefault: true,
+		marathonClient:   newFakeClient(false, marathon.Applications{}),
+		podsClient:       server.Client(),
+	}
+
+	configuration := p.buildConfiguration()
+	require.NotNil(t, configuration)
+
+	assert.Equal(t, map[string]types.Server{
+		"server-shop-instance-1-web": {URL: "http://10.0.0.1:31000"},
+	}, configuration.Backends["backend-shop-web"].Servers)
+
+	require.Contains(t, configuration.Frontends, "frontend-shop-web")
+	frontend := configuration.Frontends["frontend-shop-web"]
+	assert.Equal(t, "backend-shop-web", frontend.Backend)
+	assert.Equal(t, []string{"http"}, frontend.EntryPoints)
+	assert.Equal(t, "Host:shop.docker.localhost", frontend.Routes["route-host-shop-web"].Rule)
+}
+
+func TestBuildConfigurationWithPodsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	fakeClient := newFakeClient(false, marathon.Applications{Apps: []marathon.Application{
+		application(
+			appID("/app"),
+			appPorts(80),
+			withTasks(localhostTask(withTaskID("task"), taskPorts(80))),
+		),
+	}})
+
+	p := &Provider{
+		Endpoint:         server.URL,
+		Domain:           "docker.localhost",
+		ExposedByDefault: true,
+		marathonClient:   fakeClient,
+		podsClient:       server.Client(),
+	}
+
+	configuration := p.buildConfiguration()
+	fakeClient.AssertExpectations(t)
+	require.NotNil(t, configuration)
+
+	assert.Contains(t, configuration.Frontends, "frontend-app")
+	require.Contains(t, configuration.Backends, "backend-app")
+	assert.Equal(t, map[string]types.Server{
+		"server-task": {URL: "http://localhost:80"},
+	}, configuration.Backends["backend-app"].Servers)
+}
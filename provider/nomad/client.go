@@ -0,0 +1,121 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	headerToken = "X-Nomad-Token"
+	headerIndex = "X-Nomad-Index"
+)
+
+// serviceListStub lists the services of a namespace, as returned by the /v1/services endpoint.
+type serviceListStub struct {
+	Namespace string
+	Services  []serviceStub
+}
+
+type serviceStub struct {
+	ServiceName string
+	Tags        []string
+}
+
+// registration is an instance of a service, as returned by the /v1/service/<name> endpoint.
+type registration struct {
+	ID          string
+	ServiceName string
+	Namespace   string
+	NodeID      string
+	Datacenter  string
+	JobID       string
+	AllocID     string
+	Tags        []string
+	Address     string
+	Port        int
+}
+
+type client interface {
+	listServices(waitIndex uint64) ([]serviceListStub, uint64, error)
+	getRegistrations(namespace, serviceName string) ([]registration, error)
+}
+
+// apiClient queries the service registrations of the Nomad HTTP API.
+type apiClient struct {
+	endpoint   string
+	token      string
+	namespace  string
+	waitTime   time.Duration
+	httpClient *http.Client
+}
+
+// listServices returns the services of the watched namespace,
+// blocking until their index exceeds waitIndex or the wait time elapses.
+func (c *apiClient) listServices(waitIndex uint64) ([]serviceListStub, uint64, error) {
+	query := url.Values{}
+	query.Set("namespace", c.namespace)
+	if waitIndex > 0 {
+		query.Set("index", strconv.FormatUint(waitIndex, 10))
+		query.Set("wait", c.waitTime.String())
+	}
+
+	var stubs []serviceListStub
+	index, err := c.get("/v1/services", query, &stubs)
+	if err != nil {
+		return nil, 0, err
+	}
+	return stubs, index, nil
+}
+
+// getRegistrations returns the registrations of a service.
+func (c *apiClient) getRegistrations(namespace, serviceName string) ([]registration, error) {
+	query := url.Values{}
+	query.Set("namespace", namespace)
+
+	var registrations []registration
+	_, err := c.get("/v1/service/"+url.PathEscape(serviceName), query, &registrations)
+	if err != nil {
+		return nil, err
+	}
+	return registrations, nil
+}
+
+func (c *apiClient) get(path string, query url.Values, result interface{}) (uint64, error) {
+	req, err := http.NewRequest(http.MethodGet, c.endpoint+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(c.token) > 0 {
+		req.Header.Set(headerToken, c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return 0, fmt.Errorf("unexpected response from Nomad %s: %d %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return 0, fmt.Errorf("failed to decode response from Nomad %s: %v", path, err)
+	}
+
+	var index uint64
+	if rawIndex := resp.Header.Get(headerIndex); len(rawIndex) > 0 {
+		index, err = strconv.ParseUint(rawIndex, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s header from Nomad %s: %s", headerIndex, path, rawIndex)
+		}
+	}
+	return index, nil
+}
@@ -0,0 +1,88 @@
+package nomad
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIClientListServices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v1/services" || req.Header.Get(headerToken) != "secret" {
+			http.NotFound(rw, req)
+			return
+		}
+
+		assert.Equal(t, "*", req.URL.Query().Get("namespace"))
+		assert.Equal(t, "42", req.URL.Query().Get("index"))
+		assert.Equal(t, "1s", req.URL.Query().Get("wait"))
+
+		rw.Header().Set(headerIndex, "43")
+		rw.Write([]byte(`[{"Namespace":"default","Services":[{"ServiceName":"whoami","Tags":["traefik.enable=true"]}]}]`))
+	}))
+	defer server.Close()
+
+	client := &apiClient{
+		endpoint:   server.URL,
+		token:      "secret",
+		namespace:  "*",
+		waitTime:   time.Second,
+		httpClient: server.Client(),
+	}
+
+	stubs, index, err := client.listServices(42)
+	require.NoError(t, err)
+
+	expected := []serviceListStub{
+		{
+			Namespace: "default",
+			Services: []serviceStub{
+				{ServiceName: "whoami", Tags: []string{"traefik.enable=true"}},
+			},
+		},
+	}
+	assert.Equal(t, expected, stubs)
+	assert.EqualValues(t, 43, index)
+}
+
+func TestAPIClientGetRegistrations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/service/whoami":
+			assert.Equal(t, "prod", req.URL.Query().Get("namespace"))
+			rw.Write([]byte(`[{"ID":"_nomad-task-1","ServiceName":"whoami","Namespace":"prod","Tags":["traefik.enable=true"],"Address":"10.0.0.1","Port":8080}]`))
+		case "/v1/service/forbidden":
+			http.Error(rw, "Permission denied", http.StatusForbidden)
+		default:
+			http.NotFound(rw, req)
+		}
+	}))
+	defer server.Close()
+
+	client := &apiClient{
+		endpoint:   server.URL,
+		httpClient: server.Client(),
+	}
+
+	registrations, err := client.getRegistrations("prod", "whoami")
+	require.NoError(t, err)
+
+	expected := []registration{
+		{
+			ID:          "_nomad-task-1",
+			ServiceName: "whoami",
+			Namespace:   "prod",
+			Tags:        []string{"traefik.enable=true"},
+			Address:     "10.0.0.1",
+			Port:        8080,
+		},
+	}
+	assert.Equal(t, expected, registrations)
+
+	_, err = client.getRegistrations("prod", "forbidden")
+	assert.EqualError(t, err, "unexpected response from Nomad /v1/service/forbidden: 403 Permission denied")
+}
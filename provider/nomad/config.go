@@ -0,0 +1,399 @@
+package nomad
+
+import (
+	"bytes"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/BurntSushi/ty/fun"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/provider"
+	"github.com/containous/traefik/provider/label"
+	"github.com/containous/traefik/types"
+)
+
+func (p *Provider) buildConfiguration(services []nomadService) *types.Configuration {
+	var nomadFuncMap = template.FuncMap{
+		// Backend functions
+		"getCircuitBreaker": getCircuitBreaker,
+		"getLoadBalancer":   getLoadBalancer,
+		"getMaxConn":        getMaxConn,
+		"getHealthCheck":    getHealthCheck,
+		"getBuffering":      getBuffering,
+		"getServers":        getServers,
+
+		// Frontend functions
+		"getBackendName":          getBackendName,
+		"getFrontendRule":         p.getFrontendRule,
+		"getPriority":             getFuncInt(label.TraefikFrontendPriority, label.DefaultFrontendPriorityInt),
+		"getPassHostHeader":       getFuncBool(label.TraefikFrontendPassHostHeader, label.DefaultPassHostHeaderBool),
+		"getPassTLSCert":          getFuncBool(label.TraefikFrontendPassTLSCert, label.DefaultPassTLSCert),
+		"getEntryPoints":          getFuncSliceString(label.TraefikFrontendEntryPoints),
+		"getBasicAuth":            getFuncSliceString(label.TraefikFrontendAuthBasic),
+		"getWhitelistSourceRange": getFuncSliceString(label.TraefikFrontendWhitelistSourceRange),
+
+		"getErrorPages": getErrorPages,
+		"getRateLimit":  getRateLimit,
+		"getRedirect":   getRedirect,
+		"getHeaders":    getHeaders,
+	}
+
+	filteredServices := fun.Filter(p.serviceFilter, services).([]nomadService)
+
+	frontends := map[string]nomadService{}
+	backends := map[string]nomadService{}
+
+	for _, service := range filteredServices {
+		frontends[getFrontendName(service)] = service
+		backends[getBackendName(service)] = service
+	}
+
+	templateObjects := struct {
+		Frontends map[string]nomadService
+		Backends  map[string]nomadService
+	}{
+		Frontends: frontends,
+		Backends:  backends,
+	}
+
+	configuration, err := p.GetConfiguration("templates/nomad.tmpl", nomadFuncMap, templateObjects)
+	if err != nil {
+		log.WithError(err).Error("Failed to create config")
+	}
+
+	return configuration
+}
+
+func (p *Provider) setupFrontEndRuleTemplate() {
+	var FuncMap = template.FuncMap{
+		"getAttribute": p.getAttribute,
+		"getTag":       getTag,
+		"hasTag":       hasTag,
+	}
+	tmpl := template.New("nomad frontend rule").Funcs(provider.TemplateFuncMap(FuncMap))
+	p.frontEndRuleTemplate = tmpl
+}
+
+func (p *Provider) serviceFilter(service nomadService) bool {
+	if !label.IsEnabled(service.Labels, p.ExposedByDefault) {
+		log.Debugf("Filtering disabled Nomad service %s", service.Name)
+		return false
+	}
+
+	constraintTags := label.GetSliceStringValue(service.Labels, label.TraefikTags)
+	if ok, failingConstraint := p.MatchConstraints(constraintTags); !ok {
+		if failingConstraint != nil {
+			log.Debugf("Service %s pruned by '%v' constraint", service.Name, failingConstraint.String())
+		}
+		return false
+	}
+
+	return true
+}
+
+// Specific functions
+
+func (p *Provider) getFrontendRule(service nomadService) string {
+	customFrontendRule := label.GetStringValue(service.Labels, label.TraefikFrontendRule, p.FrontEndRule)
+
+	tmpl, err := p.frontEndRuleTemplate.Parse(customFrontendRule)
+	if err != nil {
+		log.Errorf("Failed to parse Nomad custom frontend rule: %v", err)
+		return ""
+	}
+
+	templateObjects := struct {
+		ServiceName string
+		Namespace   string
+		Domain      string
+		Attributes  []string
+	}{
+		ServiceName: service.Name,
+		Namespace:   service.Namespace,
+		Domain:      p.Domain,
+		Attributes:  service.Tags,
+	}
+
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, templateObjects)
+	if err != nil {
+		log.Errorf("Failed to execute Nomad custom frontend rule template: %v", err)
+		return ""
+	}
+
+	return buffer.String()
+}
+
+// getServiceName returns the name of the service, suffixed by its namespace outside of the default one.
+func getServiceName(service nomadService) string {
+	if len(service.Namespace) == 0 || service.Namespace == "default" {
+		return service.Name
+	}
+	return service.Name + "-" + service.Namespace
+}
+
+func getFrontendName(service nomadService) string {
+	return provider.Normalize(getServiceName(service))
+}
+
+func getBackendName(service nomadService) string {
+	backend := label.GetStringValue(service.Labels, label.TraefikBackend, getServiceName(service))
+	return provider.Normalize(backend)
+}
+
+func getCircuitBreaker(service nomadService) *types.CircuitBreaker {
+	circuitBreaker := label.GetStringValue(service.Labels, label.TraefikBackendCircuitBreakerExpression, "")
+	if len(circuitBreaker) == 0 {
+		return nil
+	}
+	return &types.CircuitBreaker{Expression: circuitBreaker}
+}
+
+func getLoadBalancer(service nomadService) *types.LoadBalancer {
+	if !label.HasPrefix(service.Labels, label.TraefikBackendLoadBalancer) {
+		return nil
+	}
+
+	method := label.GetStringValue(service.Labels, label.TraefikBackendLoadBalancerMethod, label.DefaultBackendLoadBalancerMethod)
+
+	lb := &types.LoadBalancer{
+		Method: method,
+	}
+
+	if label.GetBoolValue(service.Labels, label.TraefikBackendLoadBalancerStickiness, false) {
+		cookieName := label.GetStringValue(service.Labels, label.TraefikBackendLoadBalancerStickinessCookieName, label.DefaultBackendLoadbalancerStickinessCookieName)
+		lb.Stickiness = &types.Stickiness{CookieName: cookieName}
+	}
+
+	return lb
+}
+
+func getMaxConn(service nomadService) *types.MaxConn {
+	amount := label.GetInt64Value(service.Labels, label.TraefikBackendMaxConnAmount, math.MinInt64)
+	extractorFunc := label.GetStringValue(service.Labels, label.TraefikBackendMaxConnExtractorFunc, label.DefaultBackendMaxconnExtractorFunc)
+
+	if amount == math.MinInt64 || len(extractorFunc) == 0 {
+		return nil
+	}
+
+	return &types.MaxConn{
+		Amount:        amount,
+		ExtractorFunc: extractorFunc,
+	}
+}
+
+func getHealthCheck(service nomadService) *types.HealthCheck {
+	path := label.GetStringValue(service.Labels, label.TraefikBackendHealthCheckPath, "")
+	if len(path) == 0 {
+		return nil
+	}
+
+	port := label.GetIntValue(service.Labels, label.TraefikBackendHealthCheckPort, label.DefaultBackendHealthCheckPort)
+	interval := label.GetStringValue(service.Labels, label.TraefikBackendHealthCheckInterval, "")
+
+	return &types.HealthCheck{
+		Path:     path,
+		Port:     port,
+		Interval: interval,
+	}
+}
+
+func getBuffering(service nomadService) *types.Buffering {
+	if !label.HasPrefix(service.Labels, label.TraefikBackendBuffering) {
+		return nil
+	}
+
+	return &types.Buffering{
+		MaxRequestBodyBytes:  label.GetInt64Value(service.Labels, label.TraefikBackendBufferingMaxRequestBodyBytes, 0),
+		MaxResponseBodyBytes: label.GetInt64Value(service.Labels, label.TraefikBackendBufferingMaxResponseBodyBytes, 0),
+		MemRequestBodyBytes:  label.GetInt64Value(service.Labels, label.TraefikBackendBufferingMemRequestBodyBytes, 0),
+		MemResponseBodyBytes: label.GetInt64Value(service.Labels, label.TraefikBackendBufferingMemResponseBodyBytes, 0),
+		RetryExpression:      label.GetStringValue(service.Labels, label.TraefikBackendBufferingRetryExpression, ""),
+	}
+}
+
+func getServers(service nomadService) map[string]types.Server {
+	var servers map[string]types.Server
+
+	protocol := label.GetStringValue(service.Labels, label.TraefikProtocol, label.DefaultProtocol)
+	weight := label.GetIntValue(service.Labels, label.TraefikWeight, label.DefaultWeightInt)
+
+	for index, r := range service.Registrations {
+		if servers == nil {
+			servers = make(map[string]types.Server)
+		}
+
+		serverName := "server-" + strconv.Itoa(index)
+		servers[serverName] = types.Server{
+			URL:    protocol + "://" + net.JoinHostPort(r.Address, strconv.Itoa(r.Port)),
+			Weight: weight,
+		}
+	}
+
+	return servers
+}
+
+func getRedirect(service nomadService) *types.Redirect {
+	permanent := label.GetBoolValue(service.Labels, label.TraefikFrontendRedirectPermanent, false)
+
+	if label.Has(service.Labels, label.TraefikFrontendRedirectEntryPoint) {
+		return &types.Redirect{
+			EntryPoint: label.GetStringValue(service.Labels, label.TraefikFrontendRedirectEntryPoint, ""),
+			Permanent:  permanent,
+		}
+	}
+
+	if label.Has(service.Labels, label.TraefikFrontendRedirectRegex) &&
+		label.Has(service.Labels, label.TraefikFrontendRedirectReplacement) {
+		return &types.Redirect{
+			Regex:       label.GetStringValue(service.Labels, label.TraefikFrontendRedirectRegex, ""),
+			Replacement: label.GetStringValue(service.Labels, label.TraefikFrontendRedirectReplacement, ""),
+			Permanent:   permanent,
+		}
+	}
+
+	return nil
+}
+
+func getErrorPages(service nomadService) map[string]*types.ErrorPage {
+	prefix := label.Prefix + label.BaseFrontendErrorPage
+	return label.ParseErrorPages(service.Labels, prefix, label.RegexpFrontendErrorPage)
+}
+
+func getRateLimit(service nomadService) *types.RateLimit {
+	extractorFunc := label.GetStringValue(service.Labels, label.TraefikFrontendRateLimitExtractorFunc, "")
+	if len(extractorFunc) == 0 {
+		return nil
+	}
+
+	prefix := label.Prefix + label.BaseFrontendRateLimit
+	limits := label.ParseRateSets(service.Labels, prefix, label.RegexpFrontendRateLimit)
+
+	return &types.RateLimit{
+		ExtractorFunc: extractorFunc,
+		RateSet:       limits,
+	}
+}
+
+func getHeaders(service nomadService) *types.Headers {
+	headers := &types.Headers{
+		CustomRequestHeaders:    label.GetMapValue(service.Labels, label.TraefikFrontendRequestHeaders),
+		CustomResponseHeaders:   label.GetMapValue(service.Labels, label.TraefikFrontendResponseHeaders),
+		SSLProxyHeaders:         label.GetMapValue(service.Labels, label.TraefikFrontendSSLProxyHeaders),
+		AllowedHosts:            label.GetSliceStringValue(service.Labels, label.TraefikFrontendAllowedHosts),
+		HostsProxyHeaders:       label.GetSliceStringValue(service.Labels, label.TraefikFrontendHostsProxyHeaders),
+		STSSeconds:              label.GetInt64Value(service.Labels, label.TraefikFrontendSTSSeconds, 0),
+		SSLRedirect:             label.GetBoolValue(service.Labels, label.TraefikFrontendSSLRedirect, false),
+		SSLTemporaryRedirect:    label.GetBoolValue(service.Labels, label.TraefikFrontendSSLTemporaryRedirect, false),
+		STSIncludeSubdomains:    label.GetBoolValue(service.Labels, label.TraefikFrontendSTSIncludeSubdomains, false),
+		STSPreload:              label.GetBoolValue(service.Labels, label.TraefikFrontendSTSPreload, false),
+		ForceSTSHeader:          label.GetBoolValue(service.Labels, label.TraefikFrontendForceSTSHeader, false),
+		FrameDeny:               label.GetBoolValue(service.Labels, label.TraefikFrontendFrameDeny, false),
+		ContentTypeNosniff:      label.GetBoolValue(service.Labels, label.TraefikFrontendContentTypeNosniff, false),
+		BrowserXSSFilter:        label.GetBoolValue(service.Labels, label.TraefikFrontendBrowserXSSFilter, false),
+		IsDevelopment:           label.GetBoolValue(service.Labels, label.TraefikFrontendIsDevelopment, false),
+		SSLHost:                 label.GetStringValue(service.Labels, label.TraefikFrontendSSLHost, ""),
+		CustomFrameOptionsValue: label.GetStringValue(service.Labels, label.TraefikFrontendCustomFrameOptionsValue, ""),
+		ContentSecurityPolicy:   label.GetStringValue(service.Labels, label.TraefikFrontendContentSecurityPolicy, ""),
+		PublicKey:               label.GetStringValue(service.Labels, label.TraefikFrontendPublicKey, ""),
+		ReferrerPolicy:          label.GetStringValue(service.Labels, label.TraefikFrontendReferrerPolicy, ""),
+		CustomBrowserXSSValue:   label.GetStringValue(service.Labels, label.TraefikFrontendCustomBrowserXSSValue, ""),
+	}
+
+	if !headers.HasSecureHeadersDefined() && !headers.HasCustomHeadersDefined() {
+		return nil
+	}
+
+	return headers
+}
+
+// Tag functions
+
+// parseTagsToNeutralLabels converts the prefixed key=value tags into labels with the generic prefix.
+func (p *Provider) parseTagsToNeutralLabels(tags []string) map[string]string {
+	var labels map[string]string
+
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, p.Prefix) {
+
+			parts := strings.SplitN(tag, "=", 2)
+			if len(parts) == 2 {
+				if labels == nil {
+					labels = make(map[string]string)
+				}
+
+				// replace custom prefix by the generic prefix
+				key := label.Prefix + strings.TrimPrefix(parts[0], p.Prefix+".")
+				labels[key] = parts[1]
+			}
+		}
+	}
+
+	return labels
+}
+
+func (p *Provider) getAttribute(name string, tags []string, defaultValue string) string {
+	return getTag(p.getPrefixedName(name), tags, defaultValue)
+}
+
+func (p *Provider) getPrefixedName(name string) string {
+	if len(p.Prefix) > 0 && len(name) > 0 {
+		return p.Prefix + "." + name
+	}
+	return name
+}
+
+func hasTag(name string, tags []string) bool {
+	lowerName := strings.ToLower(name)
+
+	for _, tag := range tags {
+		lowerTag := strings.ToLower(tag)
+
+		// Tags are either singular markers, or key=value pairs
+		if strings.HasPrefix(lowerTag, lowerName+"=") || lowerTag == lowerName {
+			return true
+		}
+	}
+	return false
+}
+
+func getTag(name string, tags []string, defaultValue string) string {
+	lowerName := strings.ToLower(name)
+
+	for _, tag := range tags {
+		lowerTag := strings.ToLower(tag)
+
+		// Tags are either singular markers, or key=value pairs
+		if strings.HasPrefix(lowerTag, lowerName+"=") || lowerTag == lowerName {
+			kv := strings.SplitN(tag, "=", 2)
+			if len(kv) == 2 {
+				return kv[1]
+			}
+			return kv[0]
+		}
+	}
+	return defaultValue
+}
+
+// Label functions
+
+func getFuncBool(labelName string, defaultValue bool) func(service nomadService) bool {
+	return func(service nomadService) bool {
+		return label.GetBoolValue(service.Labels, labelName, defaultValue)
+	}
+}
+
+func getFuncInt(labelName string, defaultValue int) func(service nomadService) int {
+	return func(service nomadService) int {
+		return label.GetIntValue(service.Labels, labelName, defaultValue)
+	}
+}
+
+func getFuncSliceString(labelName string) func(service nomadService) []string {
+	return func(service nomadService) []string {
+		return label.GetSliceStringValue(service.Labels, labelName)
+	}
+}
@@ -0,0 +1,227 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderBuildConfiguration(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		services          []nomadService
+		expectedFrontends map[string]*types.Frontend
+		expectedBackends  map[string]*types.Backend
+	}{
+		{
+			desc:              "without services",
+			services:          []nomadService{},
+			expectedFrontends: map[string]*types.Frontend{},
+			expectedBackends:  map[string]*types.Backend{},
+		},
+		{
+			desc: "with a service without tags",
+			services: []nomadService{
+				{
+					Name:      "whoami",
+					Namespace: "default",
+					Registrations: []registration{
+						{ID: "a", Address: "10.0.0.1", Port: 8080},
+						{ID: "b", Address: "10.0.0.2", Port: 8081},
+					},
+				},
+			},
+			expectedFrontends: map[string]*types.Frontend{
+				"frontend-whoami": {
+					Backend:        "backend-whoami",
+					PassHostHeader: true,
+					EntryPoints:    []string{},
+					BasicAuth:      []string{},
+					Routes: map[string]types.Route{
+						"route-frontend-whoami": {
+							Rule: "Host:whoami.nomad.localhost",
+						},
+					},
+				},
+			},
+			expectedBackends: map[string]*types.Backend{
+				"backend-whoami": {
+					Servers: map[string]types.Server{
+						"server-0": {URL: "http://10.0.0.1:8080", Weight: 0},
+						"server-1": {URL: "http://10.0.0.2:8081", Weight: 0},
+					},
+				},
+			},
+		},
+		{
+			desc: "with tags",
+			services: []nomadService{
+				{
+					Name:      "api",
+					Namespace: "prod",
+					Tags: []string{
+						"traefik.protocol=https",
+						"traefik.weight=10",
+						"traefik.backend.loadbalancer.method=drr",
+						"traefik.backend.healthcheck.path=/health",
+						"traefik.frontend.rule=Host:api.{{.Namespace}}.example.com",
+						"traefik.frontend.entryPoints=http,https",
+						"traefik.frontend.passHostHeader=false",
+						"traefik.frontend.priority=12",
+						"traefik.frontend.redirect.entryPoint=https",
+					},
+					Registrations: []registration{
+						{ID: "a", Address: "fd00::1", Port: 443},
+					},
+				},
+			},
+			expectedFrontends: map[string]*types.Frontend{
+				"frontend-api-prod": {
+					Backend:     "backend-api-prod",
+					Priority:    12,
+					EntryPoints: []string{"http", "https"},
+					BasicAuth:   []string{},
+					Redirect: &types.Redirect{
+						EntryPoint: "https",
+					},
+					Routes: map[string]types.Route{
+						"route-frontend-api-prod": {
+							Rule: "Host:api.prod.example.com",
+						},
+					},
+				},
+			},
+			expectedBackends: map[string]*types.Backend{
+				"backend-api-prod": {
+					Servers: map[string]types.Server{
+						"server-0": {URL: "https://[fd00::1]:443", Weight: 10},
+					},
+					LoadBalancer: &types.LoadBalancer{
+						Method: "drr",
+					},
+					HealthCheck: &types.HealthCheck{
+						Path: "/health",
+					},
+				},
+			},
+		},
+		{
+			desc: "with a disabled service",
+			services: []nomadService{
+				{
+					Name: "whoami",
+					Tags: []string{"traefik.enable=false"},
+					Registrations: []registration{
+						{ID: "a", Address: "10.0.0.1", Port: 8080},
+					},
+				},
+			},
+			expectedFrontends: map[string]*types.Frontend{},
+			expectedBackends:  map[string]*types.Backend{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &Provider{
+				Domain:           "nomad.localhost",
+				ExposedByDefault: true,
+				Prefix:           "traefik",
+				FrontEndRule:     "Host:{{.ServiceName}}.{{.Domain}}",
+			}
+			provider.setupFrontEndRuleTemplate()
+
+			for i := range test.services {
+				test.services[i].Labels = provider.parseTagsToNeutralLabels(test.services[i].Tags)
+			}
+
+			actualConfig := provider.buildConfiguration(test.services)
+			require.NotNil(t, actualConfig)
+
+			assert.EqualValues(t, test.expectedBackends, actualConfig.Backends)
+			assert.EqualValues(t, test.expectedFrontends, actualConfig.Frontends)
+		})
+	}
+}
+
+func TestProviderServiceFilter(t *testing.T) {
+	constraint, err := types.NewConstraint("tag==ch*se")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc             string
+		exposedByDefault bool
+		prefix           string
+		tags             []string
+		expected         bool
+	}{
+		{
+			desc:             "exposed by default",
+			exposedByDefault: true,
+			tags:             []string{"traefik.tags=cheese"},
+			expected:         true,
+		},
+		{
+			desc:     "not exposed by default",
+			tags:     []string{"traefik.tags=cheese"},
+			expected: false,
+		},
+		{
+			desc:     "enabled",
+			tags:     []string{"traefik.enable=true", "traefik.tags=cheese"},
+			expected: true,
+		},
+		{
+			desc:             "disabled",
+			exposedByDefault: true,
+			tags:             []string{"traefik.enable=false", "traefik.tags=cheese"},
+			expected:         false,
+		},
+		{
+			desc:             "not matching the constraints",
+			exposedByDefault: true,
+			tags:             []string{"traefik.tags=bread"},
+			expected:         false,
+		},
+		{
+			desc:             "with a custom prefix",
+			exposedByDefault: true,
+			prefix:           "custom",
+			tags:             []string{"traefik.enable=false", "custom.tags=cheese"},
+			expected:         true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			prefix := "traefik"
+			if len(test.prefix) > 0 {
+				prefix = test.prefix
+			}
+
+			provider := &Provider{
+				ExposedByDefault: test.exposedByDefault,
+				Prefix:           prefix,
+			}
+			provider.Constraints = types.Constraints{constraint}
+
+			service := nomadService{
+				Name:   "whoami",
+				Tags:   test.tags,
+				Labels: provider.parseTagsToNeutralLabels(test.tags),
+			}
+
+			assert.Equal(t, test.expected, provider.serviceFilter(service))
+		})
+	}
+}
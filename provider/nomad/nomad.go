@@ -0,0 +1,196 @@
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cenk/backoff"
+	"github.com/containous/traefik/job"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
+	"github.com/containous/traefik/provider"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+)
+
+const (
+	// DefaultWatchWaitTime is the duration to wait when polling Nomad
+	DefaultWatchWaitTime = 15 * time.Second
+)
+
+var _ provider.Provider = (*Provider)(nil)
+
+// Provider holds configurations of the Nomad provider.
+type Provider struct {
+	provider.BaseProvider `mapstructure:",squash" export:"true"`
+	Endpoint              string           `description:"Nomad server endpoint"`
+	Token                 string           `description:"Nomad ACL token"`
+	Namespace             string           `description:"Nomad namespace of the services, * for all the namespaces" export:"true"`
+	Domain                string           `description:"Default domain used"`
+	ExposedByDefault      bool             `description:"Expose Nomad services by default" export:"true"`
+	Prefix                string           `description:"Prefix used for Nomad service tags" export:"true"`
+	FrontEndRule          string           `description:"Frontend rule used for Nomad services" export:"true"`
+	TLS                   *types.ClientTLS `description:"Enable TLS support" export:"true"`
+	client                client
+	frontEndRuleTemplate  *template.Template
+}
+
+// nomadService is a Nomad service with its registrations.
+type nomadService struct {
+	Name          string
+	Namespace     string
+	Tags          []string
+	Labels        map[string]string
+	Registrations []registration
+}
+
+// Provide allows the Nomad provider to provide configurations to traefik
+// using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *safe.Pool, constraints types.Constraints) error {
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	p.client = client
+	p.Constraints = append(p.Constraints, constraints...)
+	p.setupFrontEndRuleTemplate()
+
+	pool.Go(func(stop chan bool) {
+		notify := func(err error, time time.Duration) {
+			log.Errorf("Nomad connection error %+v, retrying in %s", err, time)
+			notification.Notify(notification.Event{Type: notification.ProviderDisconnected, Message: "Provider connection error", Provider: "nomad", Error: err.Error()})
+		}
+		operation := func() error {
+			return p.watch(configurationChan, stop)
+		}
+		errRetry := backoff.RetryNotify(safe.OperationWithRecover(operation), job.NewBackOff(backoff.NewExponentialBackOff()), notify)
+		if errRetry != nil {
+			log.Errorf("Cannot connect to Nomad server %+v", errRetry)
+		}
+	})
+	return nil
+}
+
+func (p *Provider) createClient() (*apiClient, error) {
+	endpoint := p.Endpoint
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+
+	if p.TLS != nil {
+		tlsConfig, err := p.TLS.CreateTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if !strings.Contains(endpoint, "://") {
+		if p.TLS != nil {
+			endpoint = "https://" + endpoint
+		} else {
+			endpoint = "http://" + endpoint
+		}
+	}
+
+	namespace := p.Namespace
+	if len(namespace) == 0 {
+		namespace = "default"
+	}
+
+	return &apiClient{
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		token:     p.Token,
+		namespace: namespace,
+		waitTime:  DefaultWatchWaitTime,
+		httpClient: &http.Client{
+			Transport: transport,
+			// Nomad adds up to 1/16 of the wait time to the blocking queries.
+			Timeout: 2 * DefaultWatchWaitTime,
+		},
+	}, nil
+}
+
+func (p *Provider) watch(configurationChan chan<- types.ConfigMessage, stop chan bool) error {
+	var index uint64
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		stubs, lastIndex, err := p.client.listServices(index)
+		if err != nil {
+			log.Errorf("Failed to list Nomad services: %v", err)
+			return err
+		}
+
+		// The blocking query returned because of the wait time.
+		if index > 0 && lastIndex == index {
+			continue
+		}
+		index = lastIndex
+
+		log.Debug("Nomad services changed")
+		services, err := p.getServices(stubs)
+		if err != nil {
+			return err
+		}
+
+		configurationChan <- types.ConfigMessage{
+			ProviderName:  "nomad",
+			Configuration: p.buildConfiguration(services),
+		}
+
+		if !p.Watch {
+			return nil
+		}
+	}
+}
+
+// getServices fetches the registrations of the services,
+// merging their tags as the Consul catalog provider does for the tags of the nodes of a service.
+func (p *Provider) getServices(stubs []serviceListStub) ([]nomadService, error) {
+	var services []nomadService
+	for _, stub := range stubs {
+		for _, s := range stub.Services {
+			registrations, err := p.client.getRegistrations(stub.Namespace, s.ServiceName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the registrations of Nomad service %s: %v", s.ServiceName, err)
+			}
+			if len(registrations) == 0 {
+				continue
+			}
+
+			// Ensure a stable ordering of registrations so that identical configurations may be detected
+			sort.Slice(registrations, func(i, j int) bool {
+				return registrations[i].ID < registrations[j].ID
+			})
+
+			service := nomadService{
+				Name:          s.ServiceName,
+				Namespace:     stub.Namespace,
+				Registrations: registrations,
+			}
+
+			visited := make(map[string]bool)
+			for _, r := range registrations {
+				for _, tag := range r.Tags {
+					if !visited[tag] {
+						visited[tag] = true
+						service.Tags = append(service.Tags, tag)
+					}
+				}
+			}
+			service.Labels = p.parseTagsToNeutralLabels(service.Tags)
+
+			services = append(services, service)
+		}
+	}
+	return services, nil
+}
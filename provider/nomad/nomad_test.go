@@ -0,0 +1,65 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/provider/label"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clientMock struct {
+	registrations map[string][]registration
+}
+
+func (c *clientMock) listServices(waitIndex uint64) ([]serviceListStub, uint64, error) {
+	return nil, waitIndex, nil
+}
+
+func (c *clientMock) getRegistrations(namespace, serviceName string) ([]registration, error) {
+	return c.registrations[namespace+"/"+serviceName], nil
+}
+
+func TestProviderGetServices(t *testing.T) {
+	provider := &Provider{
+		Prefix: "traefik",
+		client: &clientMock{
+			registrations: map[string][]registration{
+				"default/whoami": {
+					{ID: "b", Address: "10.0.0.2", Port: 80, Tags: []string{"traefik.weight=2", "traefik.frontend.priority=5"}},
+					{ID: "a", Address: "10.0.0.1", Port: 80, Tags: []string{"traefik.weight=2", "web"}},
+				},
+			},
+		},
+	}
+
+	stubs := []serviceListStub{
+		{
+			Namespace: "default",
+			Services: []serviceStub{
+				{ServiceName: "whoami"},
+				{ServiceName: "deregistered"},
+			},
+		},
+	}
+
+	services, err := provider.getServices(stubs)
+	require.NoError(t, err)
+
+	expected := []nomadService{
+		{
+			Name:      "whoami",
+			Namespace: "default",
+			Tags:      []string{"traefik.weight=2", "web", "traefik.frontend.priority=5"},
+			Labels: map[string]string{
+				label.TraefikWeight:           "2",
+				label.TraefikFrontendPriority: "5",
+			},
+			Registrations: []registration{
+				{ID: "a", Address: "10.0.0.1", Port: 80, Tags: []string{"traefik.weight=2", "web"}},
+				{ID: "b", Address: "10.0.0.2", Port: 80, Tags: []string{"traefik.weight=2", "traefik.frontend.priority=5"}},
+			},
+		},
+	}
+	assert.Equal(t, expected, services)
+}
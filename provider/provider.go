@@ -2,7 +2,12 @@ package provider
 
 import (
 	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"hash/adler32"
 	"io/ioutil"
+	"strconv"
 	"strings"
 	"text/template"
 	"unicode"
@@ -54,16 +59,7 @@ func (p *BaseProvider) MatchConstraints(tags []string) (bool, *types.Constraint)
 func (p *BaseProvider) GetConfiguration(defaultTemplateFile string, funcMap template.FuncMap, templateObjects interface{}) (*types.Configuration, error) {
 	configuration := new(types.Configuration)
 
-	var defaultFuncMap = sprig.TxtFuncMap()
-	// tolower is deprecated in favor of sprig's lower function
-	defaultFuncMap["tolower"] = strings.ToLower
-	defaultFuncMap["normalize"] = Normalize
-	defaultFuncMap["split"] = split
-	for funcID, funcElement := range funcMap {
-		defaultFuncMap[funcID] = funcElement
-	}
-
-	tmpl := template.New(p.Filename).Funcs(defaultFuncMap)
+	tmpl := template.New(p.Filename).Funcs(TemplateFuncMap(funcMap))
 
 	tmplContent, err := p.getTemplateContent(defaultTemplateFile)
 	if err != nil {
@@ -111,6 +107,37 @@ func (p *BaseProvider) getTemplateContent(defaultTemplateFile string) (string, e
 	return defaultTemplateFile, nil
 }
 
+// TemplateFuncMap returns the functions available to the provider templates:
+// the sprig library, the traefik helpers, and the given provider specific functions.
+func TemplateFuncMap(funcMap template.FuncMap) template.FuncMap {
+	var defaultFuncMap = sprig.TxtFuncMap()
+	// tolower is deprecated in favor of sprig's lower function
+	defaultFuncMap["tolower"] = strings.ToLower
+	defaultFuncMap["normalize"] = Normalize
+	defaultFuncMap["split"] = split
+	defaultFuncMap["sha1sum"] = sha1sum
+	defaultFuncMap["md5sum"] = md5sum
+	defaultFuncMap["adler32sum"] = adler32sum
+	for funcID, funcElement := range funcMap {
+		defaultFuncMap[funcID] = funcElement
+	}
+	return defaultFuncMap
+}
+
+func sha1sum(input string) string {
+	hash := sha1.Sum([]byte(input))
+	return hex.EncodeToString(hash[:])
+}
+
+func md5sum(input string) string {
+	hash := md5.Sum([]byte(input))
+	return hex.EncodeToString(hash[:])
+}
+
+func adler32sum(input string) string {
+	return strconv.FormatUint(uint64(adler32.Checksum([]byte(input))), 10)
+}
+
 func split(sep, s string) []string {
 	return strings.Split(s, sep)
 }
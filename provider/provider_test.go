@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"strings"
@@ -434,6 +435,80 @@ func TestSprigFunctions(t *testing.T) {
 	}
 }
 
+func TestTemplateFuncMap(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		template string
+		funcMap  template.FuncMap
+		expected string
+	}{
+		{
+			desc:     "string functions",
+			template: `{{ "Foo.Bar" | lower | replace "." "-" | trunc 5 }}`,
+			expected: "foo-b",
+		},
+		{
+			desc:     "list functions",
+			template: `{{ list "a" "b" "c" | last }}`,
+			expected: "c",
+		},
+		{
+			desc:     "dict functions",
+			template: `{{ $d := dict "foo" "bar" }}{{ if hasKey $d "foo" }}{{ index $d "foo" }}{{ end }}`,
+			expected: "bar",
+		},
+		{
+			desc:     "sha1sum",
+			template: `{{ sha1sum "foo" }}`,
+			expected: "0beec7b5ea3f0fdbc95d0dd47f3c5bc275da8a33",
+		},
+		{
+			desc:     "sha256sum",
+			template: `{{ sha256sum "foo" }}`,
+			expected: "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+		},
+		{
+			desc:     "md5sum",
+			template: `{{ md5sum "foo" }}`,
+			expected: "acbd18db4cc2f85cedef654fccc4a4d8",
+		},
+		{
+			desc:     "adler32sum",
+			template: `{{ adler32sum "foo" }}`,
+			expected: "42074437",
+		},
+		{
+			desc:     "traefik helpers",
+			template: `{{ normalize "foo/bar" }}`,
+			expected: "foo-bar",
+		},
+		{
+			desc:     "provider functions override the default ones",
+			template: `{{ normalize "foo/bar" }}`,
+			funcMap: template.FuncMap{
+				"normalize": strings.ToUpper,
+			},
+			expected: "FOO/BAR",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl, err := template.New("test").Funcs(TemplateFuncMap(test.funcMap)).Parse(test.template)
+			require.NoError(t, err)
+
+			var buffer bytes.Buffer
+			err = tmpl.Execute(&buffer, nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, buffer.String())
+		})
+	}
+}
+
 func TestBaseProvider_GetConfiguration(t *testing.T) {
 	baseProvider := BaseProvider{}
 
@@ -0,0 +1,229 @@
+package rancher
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cenk/backoff"
+	"github.com/containous/traefik/job"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
+	"github.com/containous/traefik/provider/kubernetes"
+	"github.com/containous/traefik/provider/label"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	unhealthy = "unhealthy"
+
+	// labelPodTemplateHash is the label suffixing the names of the ReplicaSets of a Deployment.
+	labelPodTemplateHash = "pod-template-hash"
+)
+
+// ClusterConfiguration contains configuration properties specific to the Rancher 2.x provider,
+// which reads the workloads of a Kubernetes cluster managed by Rancher.
+type ClusterConfiguration struct {
+	Endpoint         string                `description:"Kubernetes server endpoint (required for external cluster client)"`
+	Token            string                `description:"Kubernetes bearer token (not needed for in-cluster client)"`
+	CertAuthFilePath string                `description:"Kubernetes certificate authority file path (not needed for in-cluster client)"`
+	Namespaces       kubernetes.Namespaces `description:"Kubernetes namespaces of the workloads, all of them by default" export:"true"`
+	LabelSelector    string                `description:"Kubernetes label selector of the pods of the workloads" export:"true"`
+}
+
+func (p *Provider) createClusterClient() (k8s.Interface, error) {
+	var config *rest.Config
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" && os.Getenv("KUBERNETES_SERVICE_PORT") != "" {
+		var err error
+		config, err = rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create in-cluster configuration: %s", err)
+		}
+		if p.Cluster.Endpoint != "" {
+			config.Host = p.Cluster.Endpoint
+		}
+	} else {
+		if p.Cluster.Endpoint == "" {
+			return nil, errors.New("endpoint missing for external cluster client")
+		}
+
+		config = &rest.Config{
+			Host:        p.Cluster.Endpoint,
+			BearerToken: p.Cluster.Token,
+		}
+		if p.Cluster.CertAuthFilePath != "" {
+			caData, err := ioutil.ReadFile(p.Cluster.CertAuthFilePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file %s: %s", p.Cluster.CertAuthFilePath, err)
+			}
+			config.TLSClientConfig = rest.TLSClientConfig{CAData: caData}
+		}
+	}
+
+	return k8s.NewForConfig(config)
+}
+
+func (p *Provider) clusterProvide(configurationChan chan<- types.ConfigMessage, pool *safe.Pool, constraints types.Constraints) error {
+	p.Constraints = append(p.Constraints, constraints...)
+
+	client, err := p.createClusterClient()
+	if err != nil {
+		return err
+	}
+
+	updateConfiguration := func() error {
+		pods, err := p.listClusterPods(client)
+		if err != nil {
+			return err
+		}
+
+		rancherData := parseClusterSourcedRancherData(pods)
+		configuration := p.buildConfiguration(rancherData)
+		configurationChan <- types.ConfigMessage{
+			ProviderName:  "rancher",
+			Configuration: configuration,
+		}
+		return nil
+	}
+
+	safe.Go(func() {
+		operation := func() error {
+			if err := updateConfiguration(); err != nil {
+				log.Errorf("Failed to list the pods of the Rancher cluster: %v", err)
+				return err
+			}
+
+			if p.Watch {
+				pool.Go(func(stop chan bool) {
+					ticker := time.NewTicker(time.Second * time.Duration(p.RefreshSeconds))
+					defer ticker.Stop()
+
+					for {
+						select {
+						case <-ticker.C:
+							log.Debugf("Refreshing new Data from the Rancher cluster")
+							if err := updateConfiguration(); err != nil {
+								log.Errorf("Failed to list the pods of the Rancher cluster: %v; Skipping refresh Data from the Rancher cluster.", err)
+							}
+						case <-stop:
+							return
+						}
+					}
+				})
+			}
+			return nil
+		}
+
+		notify := func(err error, time time.Duration) {
+			log.Errorf("Rancher cluster connection error %+v, retrying in %s", err, time)
+			notification.Notify(notification.Event{Type: notification.ProviderDisconnected, Message: "Provider connection error", Provider: "rancher", Error: err.Error()})
+		}
+
+		if err := backoff.RetryNotify(safe.OperationWithRecover(operation), job.NewBackOff(backoff.NewExponentialBackOff()), notify); err != nil {
+			log.WithField("endpoint", p.Cluster.Endpoint).Errorln("Cannot connect to the Rancher cluster")
+		}
+	})
+
+	return nil
+}
+
+func (p *Provider) listClusterPods(client k8s.Interface) ([]corev1.Pod, error) {
+	namespaces := []string(p.Cluster.Namespaces)
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	var pods []corev1.Pod
+	for _, namespace := range namespaces {
+		podList, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: p.Cluster.LabelSelector})
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, podList.Items...)
+	}
+	return pods, nil
+}
+
+// parseClusterSourcedRancherData groups the pods by workload, the namespace playing the role of the Rancher 1.x stack.
+// The labels of a workload are the Traefik labels and annotations of its pods, the annotations winning,
+// as Kubernetes label values can't hold most frontend rules.
+func parseClusterSourcedRancherData(pods []corev1.Pod) []rancherData {
+	workloads := make(map[string]*rancherData)
+	for _, pod := range pods {
+		name := getWorkloadName(pod) + "/" + pod.Namespace
+
+		workload, ok := workloads[name]
+		if !ok {
+			workload = &rancherData{
+				Name:   name,
+				Labels: make(map[string]string),
+				Health: unhealthy,
+				State:  active,
+			}
+			workloads[name] = workload
+		}
+
+		for _, values := range []map[string]string{pod.Labels, pod.Annotations} {
+			for key, value := range values {
+				if strings.HasPrefix(key, label.Prefix) {
+					workload.Labels[key] = value
+				}
+			}
+		}
+
+		if isPodReady(pod) && len(pod.Status.PodIP) > 0 {
+			workload.Containers = append(workload.Containers, pod.Status.PodIP)
+			workload.Health = healthy
+		}
+	}
+
+	var names []string
+	for name := range workloads {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rancherDataList []rancherData
+	for _, name := range names {
+		sort.Strings(workloads[name].Containers)
+		rancherDataList = append(rancherDataList, *workloads[name])
+	}
+	return rancherDataList
+}
+
+// getWorkloadName returns the name of the workload controlling a pod, a Deployment being named after its ReplicaSets.
+func getWorkloadName(pod corev1.Pod) string {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller == nil || !*owner.Controller {
+			continue
+		}
+
+		if hash, ok := pod.Labels[labelPodTemplateHash]; ok && owner.Kind == "ReplicaSet" {
+			return strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+		return owner.Name
+	}
+	return pod.Name
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package rancher
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/provider/label"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseClusterSourcedRancherData(t *testing.T) {
+	controller := true
+
+	pod := func(name, namespace, ip string, ready bool, owner *metav1.OwnerReference, labels, annotations map[string]string) corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+
+		p := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Labels:      labels,
+				Annotations: annotations,
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				PodIP: ip,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: status},
+				},
+			},
+		}
+		if owner != nil {
+			p.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		return p
+	}
+
+	testCases := []struct {
+		desc     string
+		pods     []corev1.Pod
+		expected []rancherData
+	}{
+		{
+			desc: "without pods",
+		},
+		{
+			desc: "pods of a deployment",
+			pods: []corev1.Pod{
+				pod("web-6d4cf56db6-abcde", "shop", "10.42.0.2", true,
+					&metav1.OwnerReference{Kind: "ReplicaSet", Name: "web-6d4cf56db6", Controller: &controller},
+					map[string]string{labelPodTemplateHash: "6d4cf56db6", label.TraefikPort: "80", "app": "web"},
+					map[string]string{label.TraefikFrontendRule: "Host:shop.example.com", "field.cattle.io/ports": "[]"}),
+				pod("web-6d4cf56db6-fghij", "shop", "10.42.0.1", true,
+					&metav1.OwnerReference{Kind: "ReplicaSet", Name: "web-6d4cf56db6", Controller: &controller},
+					map[string]string{labelPodTemplateHash: "6d4cf56db6", label.TraefikPort: "80", "app": "web"},
+					nil),
+				pod("web-6d4cf56db6-klmno", "shop", "10.42.0.3", false,
+					&metav1.OwnerReference{Kind: "ReplicaSet", Name: "web-6d4cf56db6", Controller: &controller},
+					map[string]string{labelPodTemplateHash: "6d4cf56db6", label.TraefikPort: "80", "app": "web"},
+					nil),
+			},
+			expected: []rancherData{
+				{
+					Name: "web/shop",
+					Labels: map[string]string{
+						label.TraefikPort:         "80",
+						label.TraefikFrontendRule: "Host:shop.example.com",
+					},
+					Containers: []string{"10.42.0.1", "10.42.0.2"},
+					Health:     healthy,
+					State:      active,
+				},
+			},
+		},
+		{
+			desc: "annotations override labels",
+			pods: []corev1.Pod{
+				pod("db-0", "shop", "10.42.1.1", true,
+					&metav1.OwnerReference{Kind: "StatefulSet", Name: "db", Controller: &controller},
+					map[string]string{label.TraefikPort: "5432"},
+					map[string]string{label.TraefikPort: "8080"}),
+			},
+			expected: []rancherData{
+				{
+					Name:       "db/shop",
+					Labels:     map[string]string{label.TraefikPort: "8080"},
+					Containers: []string{"10.42.1.1"},
+					Health:     healthy,
+					State:      active,
+				},
+			},
+		},
+		{
+			desc: "unready standalone pod",
+			pods: []corev1.Pod{
+				pod("debug", "default", "10.42.2.1", false, nil, nil, nil),
+			},
+			expected: []rancherData{
+				{
+					Name:   "debug/default",
+					Labels: map[string]string{},
+					Health: unhealthy,
+					State:  active,
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual := parseClusterSourcedRancherData(test.pods)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
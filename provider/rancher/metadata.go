@@ -8,6 +8,7 @@ import (
 	"github.com/cenk/backoff"
 	"github.com/containous/traefik/job"
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/notification"
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/types"
 	"github.com/sirupsen/logrus"
@@ -71,6 +72,7 @@ func (p *Provider) metadataProvide(configurationChan chan<- types.ConfigMessage,
 				"error":    err,
 				"retry_in": time,
 			}).Errorln("Rancher metadata service connection error")
+			notification.Notify(notification.Event{Type: notification.ProviderDisconnected, Message: "Provider connection error", Provider: "rancher", Error: err.Error()})
 		}
 
 		if err := backoff.RetryNotify(operation, job.NewBackOff(backoff.NewExponentialBackOff()), notify); err != nil {
@@ -30,6 +30,7 @@ type Provider struct {
 	APIConfiguration          `mapstructure:",squash" export:"true"` // Provide backwards compatibility
 	API                       *APIConfiguration                      `description:"Enable the Rancher API provider" export:"true"`
 	Metadata                  *MetadataConfiguration                 `description:"Enable the Rancher metadata service provider" export:"true"`
+	Cluster                   *ClusterConfiguration                  `description:"Enable the Rancher 2.x provider, reading the workloads of its Kubernetes cluster" export:"true"`
 	Domain                    string                                 `description:"Default domain used"`
 	RefreshSeconds            int                                    `description:"Polling interval (in seconds)" export:"true"`
 	ExposedByDefault          bool                                   `description:"Expose services by default" export:"true"`
@@ -48,9 +49,12 @@ func (r rancherData) String() string {
 	return fmt.Sprintf("{name:%s, labels:%v, containers: %v, health: %s, state: %s}", r.Name, r.Labels, r.Containers, r.Health, r.State)
 }
 
-// Provide allows either the Rancher API or metadata service provider to
+// Provide allows either the Rancher API, metadata service or Rancher 2.x cluster provider to
 // seed configuration into Traefik using the given configuration channel.
 func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *safe.Pool, constraints types.Constraints) error {
+	if p.Cluster != nil {
+		return p.clusterProvide(configurationChan, pool, constraints)
+	}
 	if p.Metadata == nil {
 		return p.apiProvide(configurationChan, pool, constraints)
 	}
@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"fmt"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/containous/traefik/provider"
+	"github.com/containous/traefik/provider/kv"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+)
+
+var _ provider.Provider = (*Provider)(nil)
+
+// Provider holds configurations of the provider.
+type Provider struct {
+	kv.Provider `mapstructure:",squash" export:"true"`
+}
+
+// Provide allows the redis provider to Provide configurations to traefik
+// using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *safe.Pool, constraints types.Constraints) error {
+	store, err := p.CreateStore()
+	if err != nil {
+		return fmt.Errorf("failed to Connect to KV store: %v", err)
+	}
+	p.SetKVClient(store)
+	return p.Provider.Provide(configurationChan, pool, constraints)
+}
+
+// CreateStore creates the KV store
+func (p *Provider) CreateStore() (store.Store, error) {
+	p.SetStoreType(store.REDIS)
+	Register()
+	return p.Provider.CreateStore()
+}
@@ -0,0 +1,204 @@
+package redis
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+)
+
+// redisError is an error replied by the Redis server, which leaves the connection usable.
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// client is a minimal client of the Redis serialization protocol (RESP),
+// sending the commands over a single connection to the first reachable endpoint.
+type client struct {
+	endpoints []string
+	config    *store.Config
+
+	lock   sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newClient(endpoints []string, config *store.Config) *client {
+	if config == nil {
+		config = &store.Config{}
+	}
+	return &client{endpoints: endpoints, config: config}
+}
+
+// do sends a command and returns its reply, which is a string, an int64, a []byte, an []interface{} or nil.
+func (c *client) do(args ...string) (interface{}, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := c.roundTrip(args)
+	if err != nil {
+		if _, ok := err.(redisError); !ok {
+			// the connection is in an unknown state after a network or protocol error
+			c.closeConn()
+		}
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *client) close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.closeConn()
+}
+
+func (c *client) closeConn() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+		c.reader = nil
+	}
+}
+
+func (c *client) connect() error {
+	if len(c.endpoints) == 0 {
+		return errors.New("no Redis endpoint")
+	}
+
+	var err error
+	for _, endpoint := range c.endpoints {
+		if err = c.dial(endpoint); err == nil {
+			return nil
+		}
+		c.closeConn()
+	}
+	return fmt.Errorf("failed to connect to Redis: %v", err)
+}
+
+func (c *client) dial(endpoint string) error {
+	dialer := &net.Dialer{Timeout: c.config.ConnectionTimeout}
+
+	var err error
+	if c.config.TLS != nil {
+		c.conn, err = tls.DialWithDialer(dialer, "tcp", endpoint, c.config.TLS)
+	} else {
+		c.conn, err = dialer.Dial("tcp", endpoint)
+	}
+	if err != nil {
+		return err
+	}
+	c.reader = bufio.NewReader(c.conn)
+
+	if len(c.config.Password) > 0 {
+		auth := []string{"AUTH", c.config.Password}
+		if len(c.config.Username) > 0 {
+			auth = []string{"AUTH", c.config.Username, c.config.Password}
+		}
+		if _, err := c.roundTrip(auth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *client) roundTrip(args []string) (interface{}, error) {
+	if c.config.ConnectionTimeout > 0 {
+		if err := c.conn.SetDeadline(time.Now().Add(c.config.ConnectionTimeout)); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := c.conn.Write(encodeCommand(args)); err != nil {
+		return nil, err
+	}
+	return readReply(c.reader)
+}
+
+// encodeCommand encodes a command as an array of bulk strings.
+func encodeCommand(args []string) []byte {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	return buf
+}
+
+func readReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := readLine(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("empty Redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		return buf[:size], nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+
+		array := make([]interface{}, size)
+		for i := range array {
+			if array[i], err = readReply(reader); err != nil {
+				return nil, err
+			}
+		}
+		return array, nil
+	default:
+		return nil, fmt.Errorf("unexpected Redis reply %q", line)
+	}
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("malformed Redis reply %q", line)
+	}
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeCommand(t *testing.T) {
+	actual := encodeCommand([]string{"SET", "traefik/key", "multi\r\nline"})
+
+	assert.Equal(t, "*3\r\n$3\r\nSET\r\n$11\r\ntraefik/key\r\n$11\r\nmulti\r\nline\r\n", string(actual))
+}
+
+func TestReadReply(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		reply         string
+		expected      interface{}
+		expectedError string
+	}{
+		{
+			desc:     "simple string",
+			reply:    "+OK\r\n",
+			expected: "OK",
+		},
+		{
+			desc:          "error",
+			reply:         "-ERR unknown command\r\n",
+			expectedError: "ERR unknown command",
+		},
+		{
+			desc:     "integer",
+			reply:    ":42\r\n",
+			expected: int64(42),
+		},
+		{
+			desc:     "bulk string",
+			reply:    "$11\r\nmulti\r\nline\r\n",
+			expected: []byte("multi\r\nline"),
+		},
+		{
+			desc:  "nil bulk string",
+			reply: "$-1\r\n",
+		},
+		{
+			desc:     "array",
+			reply:    "*2\r\n$1\r\n0\r\n*2\r\n$1\r\na\r\n$-1\r\n",
+			expected: []interface{}{[]byte("0"), []interface{}{[]byte("a"), nil}},
+		},
+		{
+			desc:          "truncated bulk string",
+			reply:         "$11\r\nmulti",
+			expectedError: "unexpected EOF",
+		},
+		{
+			desc:          "unknown type",
+			reply:         "?foo\r\n",
+			expectedError: `unexpected Redis reply "?foo"`,
+		},
+		{
+			desc:          "malformed line",
+			reply:         "+OK\n",
+			expectedError: `malformed Redis reply "+OK\n"`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := readReply(bufio.NewReader(strings.NewReader(test.reply)))
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
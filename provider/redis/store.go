@@ -0,0 +1,463 @@
+package redis
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abronan/valkeyrie"
+	"github.com/abronan/valkeyrie/store"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+)
+
+const (
+	// watchPeriod is the polling interval of the watched keys,
+	// as the keyspace notifications are disabled by default on the Redis servers.
+	watchPeriod = 2 * time.Second
+
+	// defaultLockTTL is the expiration of the locks created without TTL, which is renewed while they are held.
+	defaultLockTTL = 20 * time.Second
+
+	// scanCount is the number of keys hinted to each SCAN call.
+	scanCount = "1000"
+)
+
+// The atomic operations compare the current value of the key to the previous one in a script,
+// the values being stored as is so that the configuration can be written with any Redis client.
+const (
+	compareAndSetScript = `if redis.call('GET', KEYS[1]) ~= ARGV[1] then return 0 end
+if ARGV[3] ~= '0' then redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3]) else redis.call('SET', KEYS[1], ARGV[2]) end
+return 1`
+	compareAndDeleteScript = `if redis.call('GET', KEYS[1]) ~= ARGV[1] then return 0 end
+return redis.call('DEL', KEYS[1])`
+	compareAndExpireScript = `if redis.call('GET', KEYS[1]) ~= ARGV[1] then return 0 end
+return redis.call('PEXPIRE', KEYS[1], ARGV[2])`
+)
+
+// Register registers Redis to valkeyrie.
+func Register() {
+	valkeyrie.AddStore(store.REDIS, newStore)
+}
+
+// redisStore is a valkeyrie store storing each key of the tree as a Redis string.
+// Redis keeps no modification index, so the LastIndex of the pairs is always 0.
+type redisStore struct {
+	client *client
+}
+
+func newStore(endpoints []string, options *store.Config) (store.Store, error) {
+	return &redisStore{client: newClient(endpoints, options)}, nil
+}
+
+// normalize returns the Redis key of a path, without leading and trailing slashes.
+func normalize(key string) string {
+	return strings.Trim(key, "/")
+}
+
+// Put a value at the specified key.
+func (s *redisStore) Put(key string, value []byte, options *store.WriteOptions) error {
+	args := []string{"SET", normalize(key), string(value)}
+	if options != nil && options.TTL > 0 {
+		args = append(args, "PX", formatMilliseconds(options.TTL))
+	}
+
+	_, err := s.client.do(args...)
+	return err
+}
+
+// Get a value given its key.
+func (s *redisStore) Get(key string, options *store.ReadOptions) (*store.KVPair, error) {
+	key = normalize(key)
+
+	reply, err := s.client.do("GET", key)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+	return &store.KVPair{Key: key, Value: value}, nil
+}
+
+// Delete the value at the specified key.
+func (s *redisStore) Delete(key string) error {
+	reply, err := s.client.do("DEL", normalize(key))
+	if err != nil {
+		return err
+	}
+
+	if deleted, _ := reply.(int64); deleted == 0 {
+		return store.ErrKeyNotFound
+	}
+	return nil
+}
+
+// Exists verifies if a key exists in the store.
+func (s *redisStore) Exists(key string, options *store.ReadOptions) (bool, error) {
+	reply, err := s.client.do("EXISTS", normalize(key))
+	if err != nil {
+		return false, err
+	}
+
+	count, _ := reply.(int64)
+	return count > 0, nil
+}
+
+// List the content of a given directory.
+func (s *redisStore) List(directory string, options *store.ReadOptions) ([]*store.KVPair, error) {
+	keys, err := s.scan(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []*store.KVPair
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > 100 {
+			batch = batch[:100]
+		}
+		keys = keys[len(batch):]
+
+		reply, err := s.client.do(append([]string{"MGET"}, batch...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		values, _ := reply.([]interface{})
+		for i, value := range values {
+			// the keys deleted since the scan are skipped
+			if data, ok := value.([]byte); ok && i < len(batch) {
+				pairs = append(pairs, &store.KVPair{Key: batch[i], Value: data})
+			}
+		}
+	}
+
+	if len(pairs) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+	return pairs, nil
+}
+
+// DeleteTree deletes the keys under a given directory.
+func (s *redisStore) DeleteTree(directory string) error {
+	keys, err := s.scan(directory)
+	if err != nil {
+		return err
+	}
+
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > 100 {
+			batch = batch[:100]
+		}
+		keys = keys[len(batch):]
+
+		if _, err := s.client.do(append([]string{"DEL"}, batch...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scan returns the sorted keys under a directory.
+func (s *redisStore) scan(directory string) ([]string, error) {
+	pattern := "*"
+	if prefix := normalize(directory); len(prefix) > 0 {
+		pattern = escapePattern(prefix) + "/*"
+	}
+
+	var keys []string
+	cursor := "0"
+	for {
+		reply, err := s.client.do("SCAN", cursor, "MATCH", pattern, "COUNT", scanCount)
+		if err != nil {
+			return nil, err
+		}
+
+		page, ok := reply.([]interface{})
+		if !ok || len(page) != 2 {
+			return nil, fmt.Errorf("unexpected SCAN reply %v", reply)
+		}
+		next, _ := page[0].([]byte)
+		batch, _ := page[1].([]interface{})
+		for _, key := range batch {
+			if data, ok := key.([]byte); ok {
+				keys = append(keys, string(data))
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" || len(cursor) == 0 {
+			break
+		}
+	}
+
+	// a key may be returned several times by SCAN
+	sort.Strings(keys)
+	var uniqueKeys []string
+	for i, key := range keys {
+		if i == 0 || keys[i-1] != key {
+			uniqueKeys = append(uniqueKeys, key)
+		}
+	}
+	return uniqueKeys, nil
+}
+
+// Watch for changes on a key, polled every watchPeriod.
+// The current value is sent first, then each new value.
+func (s *redisStore) Watch(key string, stopCh <-chan struct{}, options *store.ReadOptions) (<-chan *store.KVPair, error) {
+	watchCh := make(chan *store.KVPair)
+
+	safe.Go(func() {
+		defer close(watchCh)
+
+		var previous *store.KVPair
+		for {
+			pair, err := s.Get(key, options)
+			if err != nil && err != store.ErrKeyNotFound {
+				log.Errorf("Failed to watch Redis key %s: %v", key, err)
+				return
+			}
+
+			if pair != nil && (previous == nil || string(pair.Value) != string(previous.Value)) {
+				select {
+				case watchCh <- pair:
+				case <-stopCh:
+					return
+				}
+			}
+			previous = pair
+
+			select {
+			case <-time.After(watchPeriod):
+			case <-stopCh:
+				return
+			}
+		}
+	})
+
+	return watchCh, nil
+}
+
+// WatchTree watches for changes on the keys under a directory, polled every watchPeriod.
+// The current pairs are sent first, then the pairs after each change.
+func (s *redisStore) WatchTree(directory string, stopCh <-chan struct{}, options *store.ReadOptions) (<-chan []*store.KVPair, error) {
+	watchCh := make(chan []*store.KVPair)
+
+	safe.Go(func() {
+		defer close(watchCh)
+
+		var previous map[string]string
+		for {
+			pairs, err := s.List(directory, options)
+			if err != nil && err != store.ErrKeyNotFound {
+				log.Errorf("Failed to watch Redis directory %s: %v", directory, err)
+				return
+			}
+
+			current := make(map[string]string)
+			for _, pair := range pairs {
+				current[pair.Key] = string(pair.Value)
+			}
+
+			if previous == nil || !reflect.DeepEqual(current, previous) {
+				select {
+				case watchCh <- pairs:
+				case <-stopCh:
+					return
+				}
+			}
+			previous = current
+
+			select {
+			case <-time.After(watchPeriod):
+			case <-stopCh:
+				return
+			}
+		}
+	})
+
+	return watchCh, nil
+}
+
+// AtomicPut sets a value if the key still holds the previous value, or if the key doesn't exist without previous value.
+func (s *redisStore) AtomicPut(key string, value []byte, previous *store.KVPair, options *store.WriteOptions) (bool, *store.KVPair, error) {
+	key = normalize(key)
+
+	var ttl time.Duration
+	if options != nil {
+		ttl = options.TTL
+	}
+
+	if previous == nil {
+		args := []string{"SET", key, string(value), "NX"}
+		if ttl > 0 {
+			args = append(args, "PX", formatMilliseconds(ttl))
+		}
+
+		reply, err := s.client.do(args...)
+		if err != nil {
+			return false, nil, err
+		}
+		if reply == nil {
+			return false, nil, store.ErrKeyExists
+		}
+		return true, &store.KVPair{Key: key, Value: value}, nil
+	}
+
+	reply, err := s.client.do("EVAL", compareAndSetScript, "1", key, string(previous.Value), string(value), formatMilliseconds(ttl))
+	if err != nil {
+		return false, nil, err
+	}
+	if set, _ := reply.(int64); set == 0 {
+		return false, nil, store.ErrKeyModified
+	}
+	return true, &store.KVPair{Key: key, Value: value}, nil
+}
+
+// AtomicDelete deletes a key if it still holds the previous value.
+func (s *redisStore) AtomicDelete(key string, previous *store.KVPair) (bool, error) {
+	if previous == nil {
+		return false, store.ErrPreviousNotSpecified
+	}
+
+	reply, err := s.client.do("EVAL", compareAndDeleteScript, "1", normalize(key), string(previous.Value))
+	if err != nil {
+		return false, err
+	}
+	if deleted, _ := reply.(int64); deleted == 0 {
+		return false, store.ErrKeyModified
+	}
+	return true, nil
+}
+
+// NewLock creates a lock for a given key, held while the key holds the value of the lock.
+func (s *redisStore) NewLock(key string, options *store.LockOptions) (store.Locker, error) {
+	lock := &redisLock{
+		store: s,
+		key:   normalize(key),
+		ttl:   defaultLockTTL,
+	}
+
+	if options != nil {
+		lock.value = options.Value
+		lock.renewCh = options.RenewLock
+		if options.TTL > 0 {
+			lock.ttl = options.TTL
+		}
+	}
+
+	if len(lock.value) == 0 {
+		// the value identifies the holder of the lock
+		token := make([]byte, 16)
+		if _, err := rand.Read(token); err != nil {
+			return nil, err
+		}
+		lock.value = []byte(hex.EncodeToString(token))
+	}
+	return lock, nil
+}
+
+// Close the store connection.
+func (s *redisStore) Close() {
+	s.client.close()
+}
+
+type redisLock struct {
+	store    *redisStore
+	key      string
+	value    []byte
+	ttl      time.Duration
+	renewCh  chan struct{}
+	unlockCh chan struct{}
+}
+
+// Lock blocks until the lock is acquired or stopChan receives,
+// and returns a channel closed when the lock is lost.
+func (l *redisLock) Lock(stopChan chan struct{}) (<-chan struct{}, error) {
+	for {
+		reply, err := l.store.client.do("SET", l.key, string(l.value), "NX", "PX", formatMilliseconds(l.ttl))
+		if err != nil {
+			return nil, err
+		}
+		if reply != nil {
+			break
+		}
+
+		select {
+		case <-time.After(l.ttl / 10):
+		case <-stopChan:
+			return nil, store.ErrCannotLock
+		}
+	}
+
+	l.unlockCh = make(chan struct{})
+	lostCh := make(chan struct{})
+	unlockCh := l.unlockCh
+	safe.Go(func() {
+		l.renew(lostCh, unlockCh)
+	})
+	return lostCh, nil
+}
+
+// renew extends the expiration of the lock until it is unlocked, the renewal is stopped, or the lock is lost.
+func (l *redisLock) renew(lostCh chan<- struct{}, unlockCh <-chan struct{}) {
+	defer close(lostCh)
+
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reply, err := l.store.client.do("EVAL", compareAndExpireScript, "1", l.key, string(l.value), formatMilliseconds(l.ttl))
+			if err != nil {
+				log.Errorf("Failed to renew the Redis lock %s: %v", l.key, err)
+				return
+			}
+			if renewed, _ := reply.(int64); renewed == 0 {
+				return
+			}
+		case <-l.renewCh:
+			return
+		case <-unlockCh:
+			return
+		}
+	}
+}
+
+// Unlock releases the lock if it is still held.
+func (l *redisLock) Unlock() error {
+	if l.unlockCh != nil {
+		close(l.unlockCh)
+		l.unlockCh = nil
+	}
+
+	_, err := l.store.client.do("EVAL", compareAndDeleteScript, "1", l.key, string(l.value))
+	return err
+}
+
+func formatMilliseconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Millisecond), 10)
+}
+
+// escapePattern escapes the special characters of the glob-style patterns of SCAN.
+func escapePattern(key string) string {
+	var escaped strings.Builder
+	for _, r := range key {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
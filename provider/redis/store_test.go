@@ -0,0 +1,265 @@
+package redis
+
+import (
+	"bufio"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is a Redis server implementing the commands of the store without scripting.
+type fakeServer struct {
+	listener net.Listener
+	password string
+
+	lock   sync.Mutex
+	values map[string]string
+}
+
+func newFakeServer(t *testing.T, password string) *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeServer{
+		listener: listener,
+		password: password,
+		values:   make(map[string]string),
+	}
+	go server.serve()
+	return server
+}
+
+func (s *fakeServer) close() {
+	s.listener.Close()
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	authenticated := len(s.password) == 0
+	for {
+		request, err := readReply(reader)
+		if err != nil {
+			return
+		}
+
+		var args []string
+		for _, arg := range request.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+
+		if strings.ToUpper(args[0]) == "AUTH" {
+			if args[len(args)-1] != s.password {
+				conn.Write([]byte("-WRONGPASS invalid password\r\n"))
+				continue
+			}
+			authenticated = true
+			conn.Write([]byte("+OK\r\n"))
+			continue
+		}
+		if !authenticated {
+			conn.Write([]byte("-NOAUTH Authentication required.\r\n"))
+			continue
+		}
+
+		conn.Write(s.execute(args))
+	}
+}
+
+func (s *fakeServer) execute(args []string) []byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "GET":
+		value, ok := s.values[args[1]]
+		if !ok {
+			return []byte("$-1\r\n")
+		}
+		return bulk(value)
+	case "SET":
+		if len(args) > 3 && args[3] == "NX" {
+			if _, ok := s.values[args[1]]; ok {
+				return []byte("$-1\r\n")
+			}
+		}
+		s.values[args[1]] = args[2]
+		return []byte("+OK\r\n")
+	case "DEL":
+		var deleted int
+		for _, key := range args[1:] {
+			if _, ok := s.values[key]; ok {
+				delete(s.values, key)
+				deleted++
+			}
+		}
+		return []byte(":" + strconv.Itoa(deleted) + "\r\n")
+	case "EXISTS":
+		if _, ok := s.values[args[1]]; ok {
+			return []byte(":1\r\n")
+		}
+		return []byte(":0\r\n")
+	case "MGET":
+		reply := "*" + strconv.Itoa(len(args)-1) + "\r\n"
+		for _, key := range args[1:] {
+			if value, ok := s.values[key]; ok {
+				reply += string(bulk(value))
+			} else {
+				reply += "$-1\r\n"
+			}
+		}
+		return []byte(reply)
+	case "SCAN":
+		// a single page, the pattern being a prefix followed by *
+		prefix := strings.Replace(strings.TrimSuffix(args[3], "*"), `\`, "", -1)
+		var keys []string
+		for key := range s.values {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		reply := "*2\r\n" + string(bulk("0")) + "*" + strconv.Itoa(len(keys)) + "\r\n"
+		for _, key := range keys {
+			reply += string(bulk(key))
+		}
+		return []byte(reply)
+	default:
+		return []byte("-ERR unknown command '" + args[0] + "'\r\n")
+	}
+}
+
+func bulk(value string) []byte {
+	return []byte("$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n")
+}
+
+func TestRedisStore(t *testing.T) {
+	server := newFakeServer(t, "secret")
+	defer server.close()
+
+	kv, err := newStore([]string{"127.0.0.1:1", server.listener.Addr().String()}, &store.Config{
+		ConnectionTimeout: time.Second,
+		Password:          "secret",
+	})
+	require.NoError(t, err)
+	defer kv.Close()
+
+	require.NoError(t, kv.Put("/traefik/backends/backend1/servers/server1/url", []byte("http://10.0.0.1:80"), nil))
+	require.NoError(t, kv.Put("traefik/backends/backend1/servers/server1/weight", []byte("10"), nil))
+	require.NoError(t, kv.Put("traefik/frontends/frontend1/backend", []byte("backend1"), nil))
+	require.NoError(t, kv.Put("traefik_other/key", []byte("value"), nil))
+
+	pair, err := kv.Get("traefik/frontends/frontend1/backend", nil)
+	require.NoError(t, err)
+	assert.Equal(t, &store.KVPair{Key: "traefik/frontends/frontend1/backend", Value: []byte("backend1")}, pair)
+
+	_, err = kv.Get("traefik/frontends/frontend2/backend", nil)
+	assert.Equal(t, store.ErrKeyNotFound, err)
+
+	exists, err := kv.Exists("traefik/frontends/frontend1/backend", nil)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	pairs, err := kv.List("traefik/backends", nil)
+	require.NoError(t, err)
+	expected := []*store.KVPair{
+		{Key: "traefik/backends/backend1/servers/server1/url", Value: []byte("http://10.0.0.1:80")},
+		{Key: "traefik/backends/backend1/servers/server1/weight", Value: []byte("10")},
+	}
+	assert.Equal(t, expected, pairs)
+
+	_, err = kv.List("traefik/tls", nil)
+	assert.Equal(t, store.ErrKeyNotFound, err)
+
+	ok, _, err := kv.AtomicPut("traefik/frontends/frontend1/backend", []byte("backend2"), nil, nil)
+	assert.False(t, ok)
+	assert.Equal(t, store.ErrKeyExists, err)
+
+	ok, _, err = kv.AtomicPut("traefik/frontends/frontend2/backend", []byte("backend2"), nil, nil)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, kv.DeleteTree("traefik"))
+	_, err = kv.List("traefik", nil)
+	assert.Equal(t, store.ErrKeyNotFound, err)
+
+	// the keys sharing the prefix of the directory aren't in the directory
+	exists, err = kv.Exists("traefik_other/key", nil)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, kv.Delete("traefik_other/key"))
+	assert.Equal(t, store.ErrKeyNotFound, kv.Delete("traefik_other/key"))
+}
+
+func TestRedisStoreAuthenticationFailure(t *testing.T) {
+	server := newFakeServer(t, "secret")
+	defer server.close()
+
+	kv, err := newStore([]string{server.listener.Addr().String()}, &store.Config{
+		ConnectionTimeout: time.Second,
+		Password:          "wrong",
+	})
+	require.NoError(t, err)
+	defer kv.Close()
+
+	_, err = kv.Get("traefik/key", nil)
+	assert.Error(t, err)
+}
+
+func TestRedisStoreWatchTree(t *testing.T) {
+	server := newFakeServer(t, "")
+	defer server.close()
+
+	kv, err := newStore([]string{server.listener.Addr().String()}, &store.Config{ConnectionTimeout: time.Second})
+	require.NoError(t, err)
+	defer kv.Close()
+
+	require.NoError(t, kv.Put("traefik/frontends/frontend1/backend", []byte("backend1"), nil))
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	events, err := kv.WatchTree("traefik", stopCh, nil)
+	require.NoError(t, err)
+
+	select {
+	case pairs := <-events:
+		assert.Equal(t, []*store.KVPair{{Key: "traefik/frontends/frontend1/backend", Value: []byte("backend1")}}, pairs)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no initial pairs")
+	}
+
+	require.NoError(t, kv.Put("traefik/frontends/frontend1/backend", []byte("backend2"), nil))
+
+	select {
+	case pairs := <-events:
+		assert.Equal(t, []*store.KVPair{{Key: "traefik/frontends/frontend1/backend", Value: []byte("backend2")}}, pairs)
+	case <-time.After(3 * watchPeriod):
+		t.Fatal("no change notified")
+	}
+}
+
+func TestEscapePattern(t *testing.T) {
+	assert.Equal(t, `traefik/\[a\]\*\?\\`, escapePattern(`traefik/[a]*?\`))
+}
@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
+	"sync"
 
 	"github.com/containous/mux"
+	"github.com/containous/traefik/audit"
 	"github.com/containous/traefik/log"
 	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
 	"github.com/unrolled/render"
 )
@@ -17,6 +21,8 @@ import (
 type Provider struct {
 	configurationChan chan<- types.ConfigMessage
 	EntryPoint        string `description:"EntryPoint" export:"true"`
+	lock              sync.Mutex
+	sources           map[string]*types.Configuration
 }
 
 var templatesRenderer = render.New(render.Options{Directory: "nowhere"})
@@ -26,33 +32,17 @@ func (p *Provider) AddRoutes(systemRouter *mux.Router) {
 	systemRouter.
 		Methods(http.MethodPut).
 		Path("/api/providers/{provider}").
-		HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
-
-			vars := mux.Vars(request)
-			// TODO: Deprecated configuration - Need to be removed in the future
-			if vars["provider"] != "web" && vars["provider"] != "rest" {
-				response.WriteHeader(http.StatusBadRequest)
-				fmt.Fprint(response, "Only 'rest' provider can be updated through the REST API")
-				return
-			} else if vars["provider"] == "web" {
-				log.Warn("The provider web is deprecated. Please use /rest instead")
-			}
+		HandlerFunc(p.updateConfiguration)
 
-			configuration := new(types.Configuration)
-			body, _ := ioutil.ReadAll(request.Body)
-			err := json.Unmarshal(body, configuration)
-			if err == nil {
-				// TODO: Deprecated configuration - Change to `rest` in the future
-				p.configurationChan <- types.ConfigMessage{ProviderName: "web", Configuration: configuration}
-				err := templatesRenderer.JSON(response, http.StatusOK, configuration)
-				if err != nil {
-					log.Error(err)
-				}
-			} else {
-				log.Errorf("Error parsing configuration %+v", err)
-				http.Error(response, fmt.Sprintf("%+v", err), http.StatusBadRequest)
-			}
-		})
+	systemRouter.
+		Methods(http.MethodPut).
+		Path("/api/providers/{provider}/{source:.+}").
+		HandlerFunc(p.updateConfiguration)
+
+	systemRouter.
+		Methods(http.MethodDelete).
+		Path("/api/providers/{provider}/{source:.+}").
+		HandlerFunc(p.deleteConfiguration)
 }
 
 // Provide allows the provider to provide configurations to traefik
@@ -61,3 +51,149 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 	p.configurationChan = configurationChan
 	return nil
 }
+
+// updateConfiguration replaces the configuration of a source,
+// the configuration put without source being the one of the default source.
+func (p *Provider) updateConfiguration(response http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+	if !checkProvider(response, vars["provider"]) {
+		return
+	}
+
+	configuration := new(types.Configuration)
+	body, _ := ioutil.ReadAll(request.Body)
+	err := json.Unmarshal(body, configuration)
+
+	event := newEvent(request, vars["provider"])
+	if err == nil {
+		event.Digest = audit.Digest(configuration)
+	} else {
+		event.Error = err.Error()
+	}
+	audit.Record(event)
+
+	if err != nil {
+		log.Errorf("Error parsing configuration %+v", err)
+		http.Error(response, fmt.Sprintf("%+v", err), http.StatusBadRequest)
+		return
+	}
+
+	p.setSource(vars["source"], configuration)
+
+	err = templatesRenderer.JSON(response, http.StatusOK, configuration)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// deleteConfiguration removes the configuration of a source.
+func (p *Provider) deleteConfiguration(response http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+	if !checkProvider(response, vars["provider"]) {
+		return
+	}
+
+	audit.Record(newEvent(request, vars["provider"]))
+
+	if !p.setSource(vars["source"], nil) {
+		http.Error(response, fmt.Sprintf("Source %s not found", vars["source"]), http.StatusNotFound)
+		return
+	}
+	response.WriteHeader(http.StatusNoContent)
+}
+
+func checkProvider(response http.ResponseWriter, provider string) bool {
+	// TODO: Deprecated configuration - Need to be removed in the future
+	if provider != "web" && provider != "rest" {
+		response.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(response, "Only 'rest' provider can be updated through the REST API")
+		return false
+	} else if provider == "web" {
+		log.Warn("The provider web is deprecated. Please use /rest instead")
+	}
+	return true
+}
+
+func newEvent(request *http.Request, provider string) audit.Event {
+	return audit.Event{
+		Type:       audit.APIMutation,
+		Provider:   provider,
+		RemoteAddr: request.RemoteAddr,
+		Method:     request.Method,
+		Path:       request.URL.Path,
+	}
+}
+
+// setSource sets the configuration of a source, or removes it if the configuration is nil,
+// and sends the merged configuration of all the sources.
+// It returns false if there is no source to remove.
+func (p *Provider) setSource(source string, configuration *types.Configuration) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if configuration == nil {
+		if _, ok := p.sources[source]; !ok {
+			return false
+		}
+		delete(p.sources, source)
+	} else {
+		if p.sources == nil {
+			p.sources = make(map[string]*types.Configuration)
+		}
+		p.sources[source] = configuration
+	}
+
+	// TODO: Deprecated configuration - Change to `rest` in the future
+	p.configurationChan <- types.ConfigMessage{ProviderName: "web", Configuration: mergeConfigurations(p.sources)}
+	return true
+}
+
+// mergeConfigurations merges the configurations of the sources, in the order of their names.
+// A frontend, a backend or TLS options already defined by a previous source are skipped.
+func mergeConfigurations(sources map[string]*types.Configuration) *types.Configuration {
+	var names []string
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	merged := &types.Configuration{
+		Frontends: make(map[string]*types.Frontend),
+		Backends:  make(map[string]*types.Backend),
+	}
+
+	for _, name := range names {
+		configuration := sources[name]
+
+		for frontendName, frontend := range configuration.Frontends {
+			if _, exists := merged.Frontends[frontendName]; exists {
+				log.Warnf("Frontend %s of the source %q already configured, skipping", frontendName, name)
+				continue
+			}
+			merged.Frontends[frontendName] = frontend
+		}
+
+		for backendName, backend := range configuration.Backends {
+			if _, exists := merged.Backends[backendName]; exists {
+				log.Warnf("Backend %s of the source %q already configured, skipping", backendName, name)
+				continue
+			}
+			merged.Backends[backendName] = backend
+		}
+
+		merged.TLS = append(merged.TLS, configuration.TLS...)
+
+		for optionsName, options := range configuration.TLSOptions {
+			if merged.TLSOptions == nil {
+				merged.TLSOptions = make(map[string]*tls.Options)
+			}
+			if _, exists := merged.TLSOptions[optionsName]; exists {
+				log.Warnf("TLS options %s of the source %q already configured, skipping", optionsName, name)
+				continue
+			}
+			merged.TLSOptions[optionsName] = options
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/containous/mux"
+	"github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeConfigurations(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		sources  map[string]*types.Configuration
+		expected *types.Configuration
+	}{
+		{
+			desc: "without sources",
+			expected: &types.Configuration{
+				Frontends: map[string]*types.Frontend{},
+				Backends:  map[string]*types.Backend{},
+			},
+		},
+		{
+			desc: "distinct sources",
+			sources: map[string]*types.Configuration{
+				"teams/payments": {
+					Frontends: map[string]*types.Frontend{"payments": {Backend: "payments"}},
+					Backends:  map[string]*types.Backend{"payments": {}},
+					TLS:       []*tls.Configuration{{EntryPoints: []string{"https"}}},
+				},
+				"teams/search": {
+					Frontends:  map[string]*types.Frontend{"search": {Backend: "search"}},
+					Backends:   map[string]*types.Backend{"search": {}},
+					TLSOptions: map[string]*tls.Options{"strict": {MinVersion: "VersionTLS12"}},
+				},
+			},
+			expected: &types.Configuration{
+				Frontends: map[string]*types.Frontend{
+					"payments": {Backend: "payments"},
+					"search":   {Backend: "search"},
+				},
+				Backends: map[string]*types.Backend{
+					"payments": {},
+					"search":   {},
+				},
+				TLS:        []*tls.Configuration{{EntryPoints: []string{"https"}}},
+				TLSOptions: map[string]*tls.Options{"strict": {MinVersion: "VersionTLS12"}},
+			},
+		},
+		{
+			desc: "conflicting sources",
+			sources: map[string]*types.Configuration{
+				"teams/search": {
+					Frontends: map[string]*types.Frontend{"shared": {Backend: "search"}},
+				},
+				"": {
+					Frontends: map[string]*types.Frontend{"shared": {Backend: "default"}},
+				},
+			},
+			expected: &types.Configuration{
+				Frontends: map[string]*types.Frontend{"shared": {Backend: "default"}},
+				Backends:  map[string]*types.Backend{},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual := mergeConfigurations(test.sources)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestProviderSources(t *testing.T) {
+	configurationChan := make(chan types.ConfigMessage, 10)
+	p := &Provider{}
+	require.NoError(t, p.Provide(configurationChan, nil, nil))
+
+	router := mux.NewRouter()
+	p.AddRoutes(router)
+
+	do := func(method, path, body string) int {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "/api/providers/rest/teams/payments", `{"backends": {"payments": {}}}`))
+	message := <-configurationChan
+	assert.Equal(t, "web", message.ProviderName)
+	assert.Contains(t, message.Configuration.Backends, "payments")
+
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "/api/providers/rest/teams/search", `{"backends": {"search": {}}}`))
+	message = <-configurationChan
+	assert.Len(t, message.Configuration.Backends, 2)
+
+	// the default source doesn't replace the other ones
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "/api/providers/rest", `{"backends": {"default": {}}}`))
+	message = <-configurationChan
+	assert.Len(t, message.Configuration.Backends, 3)
+
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/providers/rest/teams/payments", ""))
+	message = <-configurationChan
+	assert.Len(t, message.Configuration.Backends, 2)
+	assert.NotContains(t, message.Configuration.Backends, "payments")
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/api/providers/rest/teams/payments", ""))
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/api/providers/rest/teams/search", `{"backends": `))
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/api/providers/docker/teams/search", `{}`))
+	assert.Empty(t, configurationChan)
+}
@@ -0,0 +1,77 @@
+package status
+
+import (
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/types"
+)
+
+// Status is the synchronization state of a provider.
+type Status struct {
+	Provider string `json:"provider"`
+	// Connected is false since an error of the connection of the provider to its source, until its next synchronization.
+	Connected     bool       `json:"connected"`
+	LastSync      *time.Time `json:"lastSync,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+	Frontends     int        `json:"frontends"`
+	Backends      int        `json:"backends"`
+}
+
+var (
+	mu       sync.RWMutex
+	statuses = make(map[string]*Status)
+)
+
+// RecordSync records a configuration of a provider which is applied, or is the same as the applied one.
+func RecordSync(providerName string, configuration *types.Configuration) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	now := time.Now().UTC()
+	status := getOrCreate(providerName)
+	status.Connected = true
+	status.LastSync = &now
+	status.Frontends = 0
+	status.Backends = 0
+	if configuration != nil {
+		status.Frontends = len(configuration.Frontends)
+		status.Backends = len(configuration.Backends)
+	}
+}
+
+// RecordError records an error of a provider, which is disconnected from its source if the error is a connection error.
+func RecordError(providerName string, message string, disconnected bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	now := time.Now().UTC()
+	status := getOrCreate(providerName)
+	status.LastError = message
+	status.LastErrorTime = &now
+	if disconnected {
+		status.Connected = false
+	}
+}
+
+// Get returns the status of a provider, if it has been synchronized or has failed.
+func Get(providerName string) (Status, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	status, ok := statuses[providerName]
+	if !ok {
+		return Status{}, false
+	}
+	return *status, true
+}
+
+func getOrCreate(providerName string) *Status {
+	status, ok := statuses[providerName]
+	if !ok {
+		status = &Status{Provider: providerName}
+		statuses[providerName] = status
+	}
+	return status
+}
@@ -0,0 +1,46 @@
+package status
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus(t *testing.T) {
+	_, ok := Get("docker")
+	assert.False(t, ok)
+
+	RecordError("docker", "connection refused", true)
+
+	status, ok := Get("docker")
+	require.True(t, ok)
+	assert.Equal(t, "docker", status.Provider)
+	assert.False(t, status.Connected)
+	assert.Nil(t, status.LastSync)
+	assert.Equal(t, "connection refused", status.LastError)
+	require.NotNil(t, status.LastErrorTime)
+
+	RecordSync("docker", &types.Configuration{
+		Frontends: map[string]*types.Frontend{"frontend1": {}, "frontend2": {}},
+		Backends:  map[string]*types.Backend{"backend1": {}},
+	})
+
+	status, ok = Get("docker")
+	require.True(t, ok)
+	assert.True(t, status.Connected)
+	require.NotNil(t, status.LastSync)
+	assert.Equal(t, 2, status.Frontends)
+	assert.Equal(t, 1, status.Backends)
+	// the last error is kept
+	assert.Equal(t, "connection refused", status.LastError)
+
+	// a rejected configuration doesn't disconnect the provider
+	RecordError("docker", "invalid configuration", false)
+
+	status, ok = Get("docker")
+	require.True(t, ok)
+	assert.True(t, status.Connected)
+	assert.Equal(t, "invalid configuration", status.LastError)
+}
@@ -0,0 +1,108 @@
+package proxyprotocol
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+// v2Signature starts the binary header of the version 2 of the PROXY protocol.
+var v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+type addrsKey struct{}
+
+type addrs struct {
+	src net.Addr
+	dst net.Addr
+}
+
+// DialFunc dials a connection, like net.Dialer.DialContext.
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// WithAddrs returns a copy of the context announcing the connection from src to dst
+// in the headers sent by the dial functions of this package, instead of the dialed connection itself.
+func WithAddrs(ctx context.Context, src, dst net.Addr) context.Context {
+	return context.WithValue(ctx, addrsKey{}, addrs{src: src, dst: dst})
+}
+
+// Dialer returns a dial function sending the PROXY protocol header of the given version on the new connections,
+// for the servers requiring it.
+// The header announces the addresses set on the context with WithAddrs, or the dialed connection itself.
+func Dialer(dial DialFunc, version int) DialFunc {
+	return dialer(dial, version, false)
+}
+
+// ForwardingDialer returns a dial function sending the PROXY protocol header of the given version
+// only on the connections dialed with a context set with WithAddrs, the other connections being left untouched.
+func ForwardingDialer(dial DialFunc, version int) DialFunc {
+	return dialer(dial, version, true)
+}
+
+func dialer(dial DialFunc, version int, announcedOnly bool) DialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		announced, ok := ctx.Value(addrsKey{}).(addrs)
+		if !ok && announcedOnly {
+			return dial(ctx, network, address)
+		}
+
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		src, dst := conn.LocalAddr(), conn.RemoteAddr()
+		if ok {
+			src, dst = announced.src, announced.dst
+		}
+
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetWriteDeadline(deadline)
+			defer conn.SetWriteDeadline(time.Time{})
+		}
+		if _, err := conn.Write(Header(version, src, dst)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to send PROXY protocol header: %s", err)
+		}
+		return conn, nil
+	}
+}
+
+// Header returns the PROXY protocol header of a TCP connection from src to dst.
+func Header(version int, src, dst net.Addr) []byte {
+	srcAddr, srcOk := src.(*net.TCPAddr)
+	dstAddr, dstOk := dst.(*net.TCPAddr)
+
+	if version == 2 {
+		header := bytes.NewBuffer(append([]byte{}, v2Signature...))
+		switch {
+		case !srcOk || !dstOk:
+			// LOCAL command: the receiver uses the real connection endpoints
+			header.Write([]byte{0x20, 0x00, 0x00, 0x00})
+		case srcAddr.IP.To4() != nil && dstAddr.IP.To4() != nil:
+			header.Write([]byte{0x21, 0x11, 0x00, 12})
+			header.Write(srcAddr.IP.To4())
+			header.Write(dstAddr.IP.To4())
+		default:
+			header.Write([]byte{0x21, 0x21, 0x00, 36})
+			header.Write(srcAddr.IP.To16())
+			header.Write(dstAddr.IP.To16())
+		}
+		if srcOk && dstOk {
+			binary.Write(header, binary.BigEndian, uint16(srcAddr.Port))
+			binary.Write(header, binary.BigEndian, uint16(dstAddr.Port))
+		}
+		return header.Bytes()
+	}
+
+	if !srcOk || !dstOk {
+		return []byte("PROXY UNKNOWN\r\n")
+	}
+	family := "TCP6"
+	if srcAddr.IP.To4() != nil && dstAddr.IP.To4() != nil {
+		family = "TCP4"
+	}
+	return []byte(fmt.Sprintf("PROXY %s %s %s %d %d\r\n", family, srcAddr.IP, dstAddr.IP, srcAddr.Port, dstAddr.Port))
+}
@@ -0,0 +1,132 @@
+package proxyprotocol
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeader(t *testing.T) {
+	ipv4Src := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 40000}
+	ipv4Dst := &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 80}
+	ipv6Src := &net.TCPAddr{IP: net.ParseIP("fd00::1"), Port: 40000}
+	ipv6Dst := &net.TCPAddr{IP: net.ParseIP("fd00::2"), Port: 80}
+	unixAddr := &net.UnixAddr{Name: "/tmp/backend.sock", Net: "unix"}
+
+	testCases := []struct {
+		desc     string
+		version  int
+		src      net.Addr
+		dst      net.Addr
+		expected []byte
+	}{
+		{
+			desc:     "version 1 over IPv4",
+			version:  1,
+			src:      ipv4Src,
+			dst:      ipv4Dst,
+			expected: []byte("PROXY TCP4 10.0.0.1 10.0.0.2 40000 80\r\n"),
+		},
+		{
+			desc:     "version 1 over IPv6",
+			version:  1,
+			src:      ipv6Src,
+			dst:      ipv6Dst,
+			expected: []byte("PROXY TCP6 fd00::1 fd00::2 40000 80\r\n"),
+		},
+		{
+			desc:     "version 1 over an unknown protocol",
+			version:  1,
+			src:      unixAddr,
+			dst:      unixAddr,
+			expected: []byte("PROXY UNKNOWN\r\n"),
+		},
+		{
+			desc:    "version 2 over IPv4",
+			version: 2,
+			src:     ipv4Src,
+			dst:     ipv4Dst,
+			expected: append(append([]byte{}, v2Signature...),
+				0x21, 0x11, 0x00, 12,
+				10, 0, 0, 1,
+				10, 0, 0, 2,
+				0x9c, 0x40,
+				0x00, 0x50),
+		},
+		{
+			desc:     "version 2 over an unknown protocol",
+			version:  2,
+			src:      unixAddr,
+			dst:      unixAddr,
+			expected: append(append([]byte{}, v2Signature...), 0x20, 0x00, 0x00, 0x00),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, Header(test.version, test.src, test.dst))
+		})
+	}
+
+	t.Run("version 2 over IPv6", func(t *testing.T) {
+		header := Header(2, ipv6Src, ipv6Dst)
+		require.Len(t, header, 16+36)
+		assert.Equal(t, []byte{0x21, 0x21, 0x00, 36}, header[12:16])
+		assert.Equal(t, []byte(ipv6Src.IP.To16()), header[16:32])
+	})
+}
+
+func TestDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	headers := make(chan string, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			header, _ := bufio.NewReader(conn).ReadString('\n')
+			headers <- header
+			conn.Close()
+		}
+	}()
+
+	dial := Dialer((&net.Dialer{}).DialContext, 1)
+
+	conn, err := dial(context.Background(), "tcp", listener.Addr().String())
+	require.NoError(t, err)
+	conn.Close()
+	// the dialed connection itself is announced by default
+	assert.Regexp(t, `^PROXY TCP4 127\.0\.0\.1 127\.0\.0\.1 \d+ \d+\r\n$`, <-headers)
+
+	ctx := WithAddrs(context.Background(), &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 40000}, &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 443})
+	conn, err = dial(ctx, "tcp", listener.Addr().String())
+	require.NoError(t, err)
+	conn.Close()
+	assert.Equal(t, "PROXY TCP4 10.0.0.1 10.0.0.2 40000 443\r\n", <-headers)
+
+	dial = ForwardingDialer((&net.Dialer{}).DialContext, 1)
+
+	conn, err = dial(ctx, "tcp", listener.Addr().String())
+	require.NoError(t, err)
+	conn.Close()
+	assert.Equal(t, "PROXY TCP4 10.0.0.1 10.0.0.2 40000 443\r\n", <-headers)
+
+	conn, err = dial(context.Background(), "tcp", listener.Addr().String())
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("GET / HTTP/1.0\r\n"))
+	require.NoError(t, err)
+	conn.Close()
+	// the connections dialed without announced addresses are left untouched
+	assert.Equal(t, "GET / HTTP/1.0\r\n", <-headers)
+}
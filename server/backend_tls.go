@@ -0,0 +1,154 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+)
+
+// backendTLSKey identifies the TLS configuration of a backend among the shared transports.
+func backendTLSKey(backendTLS *types.BackendTLS) string {
+	if backendTLS == nil {
+		return ""
+	}
+
+	var rootCAs []string
+	for _, rootCA := range backendTLS.RootCAs {
+		rootCAs = append(rootCAs, rootCA.String())
+	}
+	var certificate string
+	if backendTLS.Certificate != nil {
+		certificate = backendTLS.Certificate.CertFile.String() + "," + backendTLS.Certificate.KeyFile.String()
+	}
+	return strings.Join([]string{backendTLS.ServerName, backendTLS.Verification, strings.Join(rootCAs, ","), certificate, backendTLS.CertificateName, strings.Join(backendTLS.PeerURIs, ",")}, "|")
+}
+
+// configureBackendTLS applies the server name, the root CAs, the client certificate and the verification policy of a backend
+// to the TLS configuration used to reach its servers.
+func configureBackendTLS(tlsConfig *tls.Config, backendTLS *types.BackendTLS) error {
+	if backendTLS == nil {
+		return nil
+	}
+
+	if len(backendTLS.ServerName) > 0 {
+		tlsConfig.ServerName = backendTLS.ServerName
+	}
+	if len(backendTLS.RootCAs) > 0 {
+		tlsConfig.RootCAs = createRootCACertPool(backendTLS.RootCAs)
+	}
+	if backendTLS.Certificate != nil {
+		certificate, err := loadClientCertificate(backendTLS.Certificate)
+		if err != nil {
+			return err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	} else if len(backendTLS.CertificateName) > 0 {
+		tlsConfig.GetClientCertificate = getClientCertificate(backendTLS.CertificateName)
+	}
+
+	switch backendTLS.Verification {
+	case "", types.TLSVerificationVerify:
+		tlsConfig.InsecureSkipVerify = false
+		if len(backendTLS.PeerURIs) > 0 {
+			// the servers are identified by the URIs of their certificates instead of their host names
+			tlsConfig.InsecureSkipVerify = true
+			tlsConfig.VerifyPeerCertificate = verifyPeerURIs(tlsConfig.RootCAs, backendTLS.PeerURIs)
+		}
+	case types.TLSVerificationSkipHostname:
+		// the certificate chain is still verified, against the root CAs, but not the host name
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyCertificateChain(tlsConfig.RootCAs)
+	case types.TLSVerificationInsecure:
+		tlsConfig.InsecureSkipVerify = true
+	default:
+		return fmt.Errorf("unknown TLS verification policy %q", backendTLS.Verification)
+	}
+	return nil
+}
+
+func loadClientCertificate(certificate *traefikTls.Certificate) (tls.Certificate, error) {
+	certContent, err := certificate.CertFile.Read()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyContent, err := certificate.KeyFile.Read()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	tlsCert, err := tls.X509KeyPair(certContent, keyContent)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid client certificate: %v", err)
+	}
+	return tlsCert, nil
+}
+
+// getClientCertificate returns the function presenting the client certificate of the name to the servers,
+// as it is when the connection is established for the rotations of the certificate to be followed.
+func getClientCertificate(name string) func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		certificate, ok := traefikTls.GetClientCertificates().Get(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown client certificate %q", name)
+		}
+		return certificate, nil
+	}
+}
+
+// verifyPeerURIs returns a certificate verification function checking the chain, and that the server certificate holds one of the URIs.
+func verifyPeerURIs(roots *x509.CertPool, peerURIs []string) func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	verifyChain := verifyCertificateChain(roots)
+
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if err := verifyChain(rawCerts, verifiedChains); err != nil {
+			return err
+		}
+
+		cert, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return err
+		}
+		for _, uri := range cert.URIs {
+			for _, peerURI := range peerURIs {
+				if uri.String() == peerURI {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("the server certificate doesn't hold any of the URIs %v", peerURIs)
+	}
+}
+
+// verifyCertificateChain returns a certificate verification function ignoring the host name.
+// A nil pool means the system root CAs.
+func verifyCertificateChain(roots *x509.CertPool) func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("no certificate presented by the server")
+		}
+
+		var certs []*x509.Certificate
+		for _, rawCert := range rawCerts {
+			cert, err := x509.ParseCertificate(rawCert)
+			if err != nil {
+				return err
+			}
+			certs = append(certs, cert)
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+		return err
+	}
+}
@@ -0,0 +1,267 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/configuration"
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureBackendTLS(t *testing.T) {
+	backendServer := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer backendServer.Close()
+
+	// the certificate of the test server is valid for example.com and 127.0.0.1
+	rootCA := traefikTls.FileOrContent(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: backendServer.Certificate().Raw}))
+
+	testCases := []struct {
+		desc        string
+		backendTLS  *types.BackendTLS
+		expectedErr bool
+	}{
+		{
+			desc:        "no backend TLS configuration",
+			expectedErr: true,
+		},
+		{
+			desc: "root CA",
+			backendTLS: &types.BackendTLS{
+				RootCAs: traefikTls.RootCAs{rootCA},
+			},
+		},
+		{
+			desc: "root CA and matching server name",
+			backendTLS: &types.BackendTLS{
+				ServerName: "example.com",
+				RootCAs:    traefikTls.RootCAs{rootCA},
+			},
+		},
+		{
+			desc: "root CA and other server name",
+			backendTLS: &types.BackendTLS{
+				ServerName: "other.com",
+				RootCAs:    traefikTls.RootCAs{rootCA},
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "root CA, other server name and host name not verified",
+			backendTLS: &types.BackendTLS{
+				ServerName:   "other.com",
+				RootCAs:      traefikTls.RootCAs{rootCA},
+				Verification: types.TLSVerificationSkipHostname,
+			},
+		},
+		{
+			desc: "host name not verified without root CA",
+			backendTLS: &types.BackendTLS{
+				Verification: types.TLSVerificationSkipHostname,
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "insecure",
+			backendTLS: &types.BackendTLS{
+				Verification: types.TLSVerificationInsecure,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			transport := createHTTPTransport(configuration.GlobalConfiguration{})
+			err := configureTransport(transport, configuration.GlobalConfiguration{}, "", 0, test.backendTLS, nil)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, backendServer.URL, nil)
+			req.RequestURI = ""
+			resp, err := transport.RoundTrip(req)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestConfigureBackendTLSUnknownVerification(t *testing.T) {
+	transport := createHTTPTransport(configuration.GlobalConfiguration{})
+	err := configureTransport(transport, configuration.GlobalConfiguration{}, "", 0, &types.BackendTLS{Verification: "foo"}, nil)
+	assert.Error(t, err)
+}
+
+func TestConfigureBackendTLSPeerURIs(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "mesh CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	// the mesh certificates hold a URI rather than the host names of the servers
+	issue := func(serial int64, uri string) (certPEM []byte, keyPEM []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			URIs:         []*url.URL{u},
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	serverCertPEM, serverKeyPEM := issue(2, "spiffe://mesh.local/svc/web")
+	serverCert, err := tls.X509KeyPair(serverCertPEM, serverKeyPEM)
+	require.NoError(t, err)
+	clientCertPEM, clientKeyPEM := issue(3, "spiffe://mesh.local/svc/traefik")
+
+	caPool := x509.NewCertPool()
+	caPool.AddCert(caCert)
+
+	backendServer := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	backendServer.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	backendServer.StartTLS()
+	defer backendServer.Close()
+
+	rootCA := traefikTls.FileOrContent(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}))
+	clientCert := &traefikTls.Certificate{
+		CertFile: traefikTls.FileOrContent(clientCertPEM),
+		KeyFile:  traefikTls.FileOrContent(clientKeyPEM),
+	}
+	namedClientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+	traefikTls.GetClientCertificates().Set("test-peer-uris", &namedClientCert)
+
+	testCases := []struct {
+		desc        string
+		backendTLS  *types.BackendTLS
+		expectedErr bool
+	}{
+		{
+			desc: "matching peer URI and client certificate",
+			backendTLS: &types.BackendTLS{
+				RootCAs:     traefikTls.RootCAs{rootCA},
+				Certificate: clientCert,
+				PeerURIs:    []string{"spiffe://mesh.local/svc/api", "spiffe://mesh.local/svc/web"},
+			},
+		},
+		{
+			desc: "matching peer URI and named client certificate",
+			backendTLS: &types.BackendTLS{
+				RootCAs:         traefikTls.RootCAs{rootCA},
+				CertificateName: "test-peer-uris",
+				PeerURIs:        []string{"spiffe://mesh.local/svc/web"},
+			},
+		},
+		{
+			desc: "unknown named client certificate",
+			backendTLS: &types.BackendTLS{
+				RootCAs:         traefikTls.RootCAs{rootCA},
+				CertificateName: "test-unknown",
+				PeerURIs:        []string{"spiffe://mesh.local/svc/web"},
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "other peer URI",
+			backendTLS: &types.BackendTLS{
+				RootCAs:     traefikTls.RootCAs{rootCA},
+				Certificate: clientCert,
+				PeerURIs:    []string{"spiffe://mesh.local/svc/api"},
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "without client certificate",
+			backendTLS: &types.BackendTLS{
+				RootCAs:  traefikTls.RootCAs{rootCA},
+				PeerURIs: []string{"spiffe://mesh.local/svc/web"},
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "without peer URI",
+			backendTLS: &types.BackendTLS{
+				RootCAs:     traefikTls.RootCAs{rootCA},
+				Certificate: clientCert,
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			transport := createHTTPTransport(configuration.GlobalConfiguration{})
+			err := configureTransport(transport, configuration.GlobalConfiguration{}, "", 0, test.backendTLS, nil)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, backendServer.URL, nil)
+			req.RequestURI = ""
+			resp, err := transport.RoundTrip(req)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestConfigureBackendTLSInvalidCertificate(t *testing.T) {
+	transport := createHTTPTransport(configuration.GlobalConfiguration{})
+	backendTLS := &types.BackendTLS{
+		Certificate: &traefikTls.Certificate{CertFile: "foo", KeyFile: "bar"},
+	}
+	err := configureTransport(transport, configuration.GlobalConfiguration{}, "", 0, backendTLS, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/containous/traefik/canary"
+	"github.com/containous/traefik/types"
+)
+
+// buildCanaryTarget creates the canary release of the frontend if needed, and the target holding its stable
+// and canary servers, along with the recorder of the requests forwarded to the canary servers by the forwarder.
+func buildCanaryTarget(releases map[string]*canary.Release, frontendName string, config *types.Configuration, frontend *types.Frontend, fwd http.Handler) (*canary.Release, *canary.Target, error) {
+	release, ok := releases[frontendName]
+	if !ok {
+		var err error
+		release, err = canary.NewRelease(frontendName, *frontend.Canary)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	canaryBackend, ok := config.Backends[frontend.Canary.Backend]
+	if !ok {
+		return nil, nil, fmt.Errorf("undefined canary backend '%s'", frontend.Canary.Backend)
+	}
+
+	selector, err := parseServerSelector(frontend.ServerSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target := &canary.Target{}
+	for _, srv := range config.Backends[frontend.Backend].Servers {
+		if !selector.matches(srv.Labels) {
+			continue
+		}
+		server, err := buildCanaryServer(srv)
+		if err != nil {
+			return nil, nil, err
+		}
+		target.Stable = append(target.Stable, server)
+	}
+
+	var canaryURLs []*url.URL
+	for _, srv := range canaryBackend.Servers {
+		server, err := buildCanaryServer(srv)
+		if err != nil {
+			return nil, nil, err
+		}
+		target.Canary = append(target.Canary, server)
+		canaryURLs = append(canaryURLs, server.URL)
+	}
+	target.Recorder = canary.NewRecorder(fwd, canaryURLs)
+
+	return release, target, nil
+}
+
+func buildCanaryServer(srv types.Server) (canary.Server, error) {
+	u, err := parseServerURL(srv.URL)
+	if err != nil {
+		return canary.Server{}, fmt.Errorf("error parsing server URL %s: %v", srv.URL, err)
+	}
+	normalizeServerURL(u)
+	return canary.Server{URL: u, Weight: srv.Weight}, nil
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/containous/traefik/canary"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCanaryTarget(t *testing.T) {
+	config := &types.Configuration{
+		Backends: map[string]*types.Backend{
+			"stable": {
+				Servers: map[string]types.Server{
+					"server1": {URL: "http://STABLE1:80", Weight: 2, Labels: map[string]string{"zone": "a"}},
+					"server2": {URL: "http://stable2:80", Weight: 1, Labels: map[string]string{"zone": "b"}},
+				},
+			},
+			"canary": {
+				Servers: map[string]types.Server{
+					"server1": {URL: "http://canary1:80", Weight: 1},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc           string
+		frontend       *types.Frontend
+		expectedStable []string
+		expectedError  bool
+	}{
+		{
+			desc:           "all the stable servers",
+			frontend:       &types.Frontend{Backend: "stable", Canary: &types.Canary{Backend: "canary"}},
+			expectedStable: []string{"http://stable1:80", "http://stable2:80"},
+		},
+		{
+			desc:           "stable servers matching the selector",
+			frontend:       &types.Frontend{Backend: "stable", ServerSelector: "zone==a", Canary: &types.Canary{Backend: "canary"}},
+			expectedStable: []string{"http://stable1:80"},
+		},
+		{
+			desc:          "undefined canary backend",
+			frontend:      &types.Frontend{Backend: "stable", Canary: &types.Canary{Backend: "missing"}},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid canary configuration",
+			frontend:      &types.Frontend{Backend: "stable", Canary: &types.Canary{Backend: "canary", Steps: []int{50, 10}}},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			releases := map[string]*canary.Release{}
+			release, target, err := buildCanaryTarget(releases, "frontend", config, test.frontend, http.NotFoundHandler())
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "frontend", release.Frontend)
+
+			var stable []string
+			for _, server := range target.Stable {
+				stable = append(stable, server.URL.String())
+			}
+			assert.ElementsMatch(t, test.expectedStable, stable)
+
+			require.Len(t, target.Canary, 1)
+			assert.Equal(t, "http://canary1:80", target.Canary[0].URL.String())
+			assert.NotNil(t, target.Recorder)
+		})
+	}
+}
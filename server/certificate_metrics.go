@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	traefikTls "github.com/containous/traefik/tls"
+)
+
+const certificatesMetricsInterval = time.Minute
+
+// startCertificatesMetrics reports the expiration of the served certificates every minute,
+// the certificates being renewed or reloaded in the background.
+func (s *Server) startCertificatesMetrics() {
+	if !s.metricsRegistry.IsEnabled() {
+		return
+	}
+
+	s.routinesPool.GoCtx(func(ctx context.Context) {
+		ticker := time.NewTicker(certificatesMetricsInterval)
+		defer ticker.Stop()
+
+		for {
+			traefikTls.ReportCertificatesNotAfter(s.metricsRegistry.TLSCertsNotAfterGauge(), s.servedCertificates())
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// servedCertificates returns the certificates of the TLS entry points, whether they are configured statically,
+// by the providers, or obtained from ACME or Vault.
+func (s *Server) servedCertificates() []*tls.Certificate {
+	var certs []*tls.Certificate
+
+	s.serverEntryPointsLock.RLock()
+	for _, sep := range s.serverEntryPoints {
+		// the reloaded certificates replace the ones of the config
+		var staticCerts []*tls.Certificate
+		if reloader, ok := sep.certificatesReloader.Get().(*staticCertificatesReloader); ok {
+			staticCerts = reloader.reloadedCertificates()
+		}
+		if staticCerts == nil && sep.httpServer != nil && sep.httpServer.TLSConfig != nil {
+			for i := range sep.httpServer.TLSConfig.Certificates {
+				staticCerts = append(staticCerts, &sep.httpServer.TLSConfig.Certificates[i])
+			}
+		}
+		certs = append(certs, staticCerts...)
+		if domainsCertificates, ok := sep.certs.Get().(*traefikTls.DomainsCertificates); ok && domainsCertificates != nil {
+			for _, cert := range *domainsCertificates {
+				certs = append(certs, cert)
+			}
+		}
+	}
+	s.serverEntryPointsLock.RUnlock()
+
+	for _, acmeConfig := range s.acmeConfigurations() {
+		certs = append(certs, acmeConfig.Certificates()...)
+	}
+	if s.vaultPKI != nil {
+		certs = append(certs, s.vaultPKI.Certificates()...)
+	}
+	return certs
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadFixtureCertificate(t *testing.T, domain string) *tls.Certificate {
+	t.Helper()
+
+	fixtures := filepath.Join("..", "integration", "fixtures", "https")
+	cert, err := tls.LoadX509KeyPair(filepath.Join(fixtures, domain+".cert"), filepath.Join(fixtures, domain+".key"))
+	require.NoError(t, err)
+	return &cert
+}
+
+func TestServedCertificates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certificates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	staticCert := loadFixtureCertificate(t, "snitest.com")
+	dynamicCert := loadFixtureCertificate(t, "snitest.org")
+
+	https := &serverEntryPoint{
+		httpServer: &http.Server{TLSConfig: &tls.Config{Certificates: []tls.Certificate{*staticCert}}},
+	}
+	https.certs.Set(&traefikTls.DomainsCertificates{"snitest.org": dynamicCert})
+
+	// the certificates of the config are replaced by the reloaded ones
+	writeCertificate(t, dir, "snitest.org")
+	reloader := newStaticCertificatesReloader("reloaded", traefikTls.Certificates{{
+		CertFile: traefikTls.FileOrContent(filepath.Join(dir, "server.cert")),
+		KeyFile:  traefikTls.FileOrContent(filepath.Join(dir, "server.key")),
+	}}, nil)
+	require.NoError(t, reloader.reload())
+	reloaded := &serverEntryPoint{
+		httpServer: &http.Server{TLSConfig: &tls.Config{Certificates: []tls.Certificate{*staticCert}}},
+	}
+	reloaded.certificatesReloader.Set(reloader)
+
+	s := &Server{
+		serverEntryPoints: serverEntryPoints{
+			"http":     &serverEntryPoint{httpServer: &http.Server{}},
+			"https":    https,
+			"reloaded": reloaded,
+		},
+	}
+
+	var commonNames []string
+	for _, cert := range s.servedCertificates() {
+		commonNames = append(commonNames, certificateCommonName(t, cert))
+	}
+	assert.ElementsMatch(t, []string{"snitest.com", "snitest.org", "snitest.org"}, commonNames)
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+)
+
+// loadClientCAPool reads the CA files verifying the client certificates.
+func loadClientCAPool(files []string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, caFile := range files {
+		data, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.New("invalid certificate(s) in " + caFile)
+		}
+	}
+	return pool, nil
+}
+
+// clientCAReloader reloads the client CA files of a TLS entrypoint when they change:
+// the new handshakes verify the client certificates against the last valid CA files,
+// without restarting the entrypoint and dropping its connections.
+type clientCAReloader struct {
+	entryPointName string
+	files          []string
+	base           *tls.Config
+	// config is the base config along with the reloaded CA pool, nil until the first reload.
+	config safe.Safe
+}
+
+func newClientCAReloader(entryPointName string, files []string, base *tls.Config) *clientCAReloader {
+	return &clientCAReloader{
+		entryPointName: entryPointName,
+		files:          files,
+		base:           base,
+	}
+}
+
+// getConfigForClient returns the config with the reloaded CA pool, or nil to use the base config.
+func (r *clientCAReloader) getConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	config, _ := r.config.Get().(*tls.Config)
+	return config, nil
+}
+
+// reload reads the CA files, keeping the previous ones when they are invalid.
+func (r *clientCAReloader) reload() error {
+	pool, err := loadClientCAPool(r.files)
+	if err != nil {
+		return err
+	}
+
+	config := r.base.Clone()
+	config.ClientCAs = pool
+	config.GetConfigForClient = nil
+	r.config.Set(config)
+	return nil
+}
+
+// watch reloads the CA files when they change.
+func (r *clientCAReloader) watch(pool *safe.Pool) error {
+	return watchFiles(pool, r.files, func() {
+		if err := r.reload(); err != nil {
+			log.Errorf("Error reloading the client CA files of entrypoint %s, keeping the previous ones: %v", r.entryPointName, err)
+			return
+		}
+		log.Infof("Reloaded the client CA files of entrypoint %s", r.entryPointName)
+	})
+}
+
+// checkClientCertificatesRevocation rejects the client certificates revoked by the CRLs or OCSP responders of the client CA,
+// the CRL files being reloaded when they change.
+func (s *Server) checkClientCertificatesRevocation(entryPointName string, clientCA traefikTls.ClientCA, config *tls.Config, pool *safe.Pool) error {
+	checker, err := traefikTls.NewRevocationChecker(clientCA)
+	if err != nil {
+		return err
+	}
+	checker.Apply(config)
+
+	if len(checker.CRLFiles()) == 0 {
+		return nil
+	}
+	err = watchFiles(pool, checker.CRLFiles(), func() {
+		if err := checker.ReloadCRLs(); err != nil {
+			log.Errorf("Error reloading the CRL files of entrypoint %s, keeping the previous ones: %v", entryPointName, err)
+			return
+		}
+		log.Infof("Reloaded the CRL files of entrypoint %s", entryPointName)
+	})
+	if err != nil {
+		log.Errorf("Error watching the CRL files of entrypoint %s, they won't be reloaded: %v", entryPointName, err)
+	}
+	return nil
+}
+
+// frontendClientCA is the client CA of a frontend, requested in the handshakes of its server names
+// instead of the client CA of the entry point.
+type frontendClientCA struct {
+	frontendName string
+	pool         *x509.CertPool
+	optional     bool
+}
+
+// frontendClientCAs maps the SNI server names to the client CAs of the frontends of a TLS entry point.
+type frontendClientCAs map[string]*frontendClientCA
+
+// loadFrontendClientCA reads the CA files, or contents, of the client CA of a frontend.
+func loadFrontendClientCA(frontendName string, clientCA *types.ClientCA) (*frontendClientCA, error) {
+	if len(clientCA.Files) == 0 {
+		return nil, errors.New("no client CA files")
+	}
+
+	pool := x509.NewCertPool()
+	for i, caFile := range clientCA.Files {
+		data, err := caFile.Read()
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("invalid certificate(s) in client CA file %d", i)
+		}
+	}
+	return &frontendClientCA{
+		frontendName: frontendName,
+		pool:         pool,
+		optional:     clientCA.Optional,
+	}, nil
+}
+
+// wireFrontendClientCA registers the client CA of a frontend for the server names of its Host rules on a TLS entry point.
+func wireFrontendClientCA(serverEntryPoint *serverEntryPoint, clientCA *frontendClientCA, frontend *types.Frontend) error {
+	serverNames, err := parseServerNames(frontend)
+	if err != nil {
+		return err
+	}
+	if len(serverNames) == 0 {
+		return errors.New("a frontend with a client CA requires a Host rule")
+	}
+
+	clientCAs, _ := serverEntryPoint.clientCAs.Get().(frontendClientCAs)
+	if clientCAs == nil {
+		clientCAs = frontendClientCAs{}
+		serverEntryPoint.clientCAs.Set(clientCAs)
+	}
+	for _, serverName := range serverNames {
+		serverName = types.CanonicalDomain(serverName)
+		if existing, ok := clientCAs[serverName]; ok && existing.frontendName != clientCA.frontendName {
+			log.Warnf("The client CA of frontend %s for %s replaces the one of frontend %s", clientCA.frontendName, serverName, existing.frontendName)
+		}
+		clientCAs[serverName] = clientCA
+	}
+	return nil
+}
+
+func (c frontendClientCAs) match(serverName string) *frontendClientCA {
+	for _, name := range serverNameCandidates(serverName) {
+		if clientCA, ok := c[name]; ok {
+			return clientCA
+		}
+	}
+	return nil
+}
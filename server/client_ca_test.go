@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/safe"
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeClientCA(t *testing.T, dest string, fixture string) {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(filepath.Join("..", "integration", "fixtures", "https", "clientca", fixture))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(dest, data, 0600))
+}
+
+func TestClientCAReloaderReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clientca")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	caFile := filepath.Join(dir, "ca.crt")
+	writeClientCA(t, caFile, "ca1.crt")
+
+	base := &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert}
+	base.ClientCAs, err = loadClientCAPool([]string{caFile})
+	require.NoError(t, err)
+
+	reloader := newClientCAReloader("https", []string{caFile}, base)
+
+	config, err := reloader.getConfigForClient(nil)
+	require.NoError(t, err)
+	assert.Nil(t, config, "the base config is used until the first reload")
+
+	writeClientCA(t, caFile, "ca1and2.crt")
+	require.NoError(t, reloader.reload())
+
+	config, err = reloader.getConfigForClient(nil)
+	require.NoError(t, err)
+	require.NotNil(t, config)
+	assert.Len(t, config.ClientCAs.Subjects(), 2)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+	assert.Nil(t, config.GetConfigForClient)
+	assert.Len(t, base.ClientCAs.Subjects(), 1, "the base config is left unchanged")
+
+	require.NoError(t, ioutil.WriteFile(caFile, []byte("not a certificate"), 0600))
+	assert.Error(t, reloader.reload())
+
+	config, err = reloader.getConfigForClient(nil)
+	require.NoError(t, err)
+	assert.Len(t, config.ClientCAs.Subjects(), 2, "the previous CA files are kept when invalid")
+}
+
+func TestClientCAReloaderWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clientca")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	caFile := filepath.Join(dir, "ca.crt")
+	writeClientCA(t, caFile, "ca1.crt")
+
+	pool := safe.NewPool(context.Background())
+	defer pool.Stop()
+
+	reloader := newClientCAReloader("https", []string{caFile}, &tls.Config{})
+	require.NoError(t, reloader.watch(pool))
+
+	// a file replaced by a rename
+	tmpFile := filepath.Join(dir, "ca.crt.tmp")
+	writeClientCA(t, tmpFile, "ca1and2.crt")
+	require.NoError(t, os.Rename(tmpFile, caFile))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		config, _ := reloader.getConfigForClient(nil)
+		if config != nil && len(config.ClientCAs.Subjects()) == 2 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the client CA files have not been reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServerEntryPointGetConfigForClient(t *testing.T) {
+	ca1, err := ioutil.ReadFile(filepath.Join("..", "integration", "fixtures", "https", "clientca", "ca1.crt"))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc               string
+		serverName         string
+		optional           bool
+		next               *tls.Config
+		expectedClientAuth tls.ClientAuthType
+		expectedNext       bool
+	}{
+		{
+			desc:               "exact server name",
+			serverName:         "tenant1.example.com",
+			expectedClientAuth: tls.RequireAndVerifyClientCert,
+		},
+		{
+			desc:               "wildcard server name, optional",
+			serverName:         "api.tenant2.example.com",
+			optional:           true,
+			expectedClientAuth: tls.VerifyClientCertIfGiven,
+		},
+		{
+			desc:               "config of the next callback",
+			serverName:         "TENANT1.example.com",
+			next:               &tls.Config{ServerName: "reloaded"},
+			expectedClientAuth: tls.RequireAndVerifyClientCert,
+			expectedNext:       true,
+		},
+		{
+			desc:       "frontend without client CA",
+			serverName: "other.example.com",
+		},
+		{
+			desc: "no server name",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			sep := &serverEntryPoint{}
+			for _, rule := range []string{"Host:tenant1.example.com", "Host:*.tenant2.example.com"} {
+				clientCA, err := loadFrontendClientCA(rule, &types.ClientCA{
+					Files:    []traefikTls.FileOrContent{traefikTls.FileOrContent(ca1)},
+					Optional: test.optional,
+				})
+				require.NoError(t, err)
+				frontend := &types.Frontend{Routes: map[string]types.Route{"route": {Rule: rule}}}
+				require.NoError(t, wireFrontendClientCA(sep, clientCA, frontend))
+			}
+
+			base := &tls.Config{ServerName: "base"}
+			getConfigForClient := sep.getConfigForClient(base, func(*tls.ClientHelloInfo) (*tls.Config, error) {
+				return test.next, nil
+			})
+
+			config, err := getConfigForClient(&tls.ClientHelloInfo{ServerName: test.serverName})
+			require.NoError(t, err)
+			if test.expectedClientAuth == tls.NoClientCert {
+				assert.Nil(t, config)
+				return
+			}
+			require.NotNil(t, config)
+			assert.Equal(t, test.expectedClientAuth, config.ClientAuth)
+			assert.Len(t, config.ClientCAs.Subjects(), 1)
+			assert.Nil(t, config.GetConfigForClient)
+			if test.expectedNext {
+				assert.Equal(t, "reloaded", config.ServerName)
+			} else {
+				assert.Equal(t, "base", config.ServerName)
+			}
+			assert.Equal(t, tls.NoClientCert, base.ClientAuth, "the base config is left unchanged")
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+// connectionLimitListener counts the client connections of an entry point, and limits their number if requested:
+// the listener doesn't accept any connection while the limit is reached,
+// the excess connections waiting in the listen backlog until a connection is closed.
+type connectionLimitListener struct {
+	net.Listener
+	entryPointName string
+	gauge          gokitmetrics.Gauge
+	// slots is nil when the number of connections isn't limited
+	slots chan struct{}
+	conns int64
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func newConnectionLimitListener(listener net.Listener, entryPointName string, maxConnections int, gauge gokitmetrics.Gauge) *connectionLimitListener {
+	l := &connectionLimitListener{
+		Listener:       listener,
+		entryPointName: entryPointName,
+		gauge:          gauge,
+		done:           make(chan struct{}),
+	}
+	if maxConnections > 0 {
+		l.slots = make(chan struct{}, maxConnections)
+	}
+	return l
+}
+
+func (l *connectionLimitListener) Accept() (net.Conn, error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-l.done:
+			// the listener is closed, its Accept returning the error expected by the HTTP server
+			return l.Listener.Accept()
+		}
+	}
+
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		l.release()
+		return nil, err
+	}
+
+	l.gauge.With("entrypoint", l.entryPointName).Set(float64(atomic.AddInt64(&l.conns, 1)))
+	return &limitedConn{Conn: conn, listener: l}, nil
+}
+
+// Close closes the listener, unblocking the pending Accept call.
+func (l *connectionLimitListener) Close() error {
+	l.closeOnce.Do(func() { close(l.done) })
+	return l.Listener.Close()
+}
+
+func (l *connectionLimitListener) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// limitedConn gives its slot back to the listener when it is closed.
+type limitedConn struct {
+	net.Conn
+	listener  *connectionLimitListener
+	closeOnce sync.Once
+}
+
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.closeOnce.Do(func() {
+		c.listener.gauge.With("entrypoint", c.listener.entryPointName).Set(float64(atomic.AddInt64(&c.listener.conns, -1)))
+		c.listener.release()
+	})
+	return err
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionLimitListener(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	gauge := &testhelpers.CollectingGauge{}
+	limitListener := newConnectionLimitListener(listener, "http", 1, gauge)
+	defer limitListener.Close()
+
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := limitListener.Accept()
+			if err != nil {
+				close(accepted)
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	client1, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer client1.Close()
+	conn1 := <-accepted
+	assert.Equal(t, float64(1), gauge.GaugeValue)
+	assert.Equal(t, []string{"entrypoint", "http"}, gauge.LastLabelValues)
+
+	// the second connection waits in the listen backlog while the first one is open
+	client2, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer client2.Close()
+	select {
+	case <-accepted:
+		t.Fatal("connection accepted beyond the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, conn1.Close())
+	// closing a connection twice doesn't release its slot twice
+	conn1.Close()
+	conn2 := <-accepted
+	require.NotNil(t, conn2)
+	assert.Equal(t, float64(1), gauge.GaugeValue)
+
+	conn2.Close()
+	assert.Equal(t, float64(0), gauge.GaugeValue)
+}
+
+func TestConnectionLimitListenerClose(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	limitListener := newConnectionLimitListener(listener, "http", 1, &testhelpers.CollectingGauge{})
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	conn, err := limitListener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	accepted := make(chan error)
+	go func() {
+		_, err := limitListener.Accept()
+		accepted <- err
+	}()
+
+	// the pending Accept call, waiting for a slot, returns once the listener is closed
+	require.NoError(t, limitListener.Close())
+	select {
+	case err := <-accepted:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Accept not unblocked by Close")
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/middlewares"
+	"github.com/urfave/negroni"
+)
+
+// defaultMiddlewares are the middlewares applied to the frontends of all the providers, before their own middlewares.
+type defaultMiddlewares struct {
+	handlers []negroni.Handler
+	names    []string
+	// compress is skipped on the entrypoints already compressing the responses.
+	compress negroni.Handler
+}
+
+func (s *Server) buildDefaultMiddlewares(config *configuration.DefaultMiddlewares) (*defaultMiddlewares, error) {
+	d := &defaultMiddlewares{}
+
+	if len(config.WhitelistSourceRange) > 0 {
+		ipWhitelistMiddleware, err := middlewares.NewIPWhitelister(config.WhitelistSourceRange, config.WhitelistIPStrategy)
+		if err != nil {
+			return nil, fmt.Errorf("error creating IP Whitelister: %v", err)
+		}
+		d.handlers = append(d.handlers, s.tracingMiddleware.NewNegroniHandlerWrapper("IP whitelist", s.wrapNegroniHandlerWithAccessLog(ipWhitelistMiddleware, "default ipwhitelister"), false))
+		d.names = append(d.names, "default-whitelist")
+	}
+
+	if len(config.RequestIDHeader) > 0 {
+		d.handlers = append(d.handlers, middlewares.NewRequestID(config.RequestIDHeader))
+		d.names = append(d.names, "default-requestid")
+	}
+
+	if headersMiddleware := middlewares.NewDefaultHeaders(config.Headers); headersMiddleware != nil {
+		d.handlers = append(d.handlers, s.tracingMiddleware.NewNegroniHandlerWrapper("Header", headersMiddleware, false))
+		d.names = append(d.names, "default-headers")
+	}
+
+	if config.Compress {
+		compressMiddleware, err := middlewares.NewCompress(config.Compression)
+		if err != nil {
+			return nil, fmt.Errorf("error creating compression: %v", err)
+		}
+		d.compress = compressMiddleware
+	}
+
+	return d, nil
+}
+
+// middlewareNames returns the names of the default middlewares applied on the entrypoint.
+func (d *defaultMiddlewares) middlewareNames(entryPoint *configuration.EntryPoint) []string {
+	names := append([]string{}, d.names...)
+	if d.compress != nil && !entryPoint.Compress {
+		names = append(names, "default-compress")
+	}
+	return names
+}
+
+// wrap applies the default middlewares to the handler of a frontend on the entrypoint.
+func (d *defaultMiddlewares) wrap(handler http.Handler, entryPoint *configuration.EntryPoint) http.Handler {
+	n := negroni.New(d.handlers...)
+	if d.compress != nil && !entryPoint.Compress {
+		n.Use(d.compress)
+	}
+	n.UseHandler(handler)
+	return n
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/ping"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerLoadConfigDefaultMiddlewares(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		defaultMiddlewares *configuration.DefaultMiddlewares
+		remoteAddr         string
+		expectedStatusCode int
+		expectedHeaders    map[string]string
+	}{
+		{
+			desc:               "whitelisted",
+			defaultMiddlewares: &configuration.DefaultMiddlewares{WhitelistSourceRange: []string{"10.0.0.0/8"}},
+			remoteAddr:         "10.0.0.1:1234",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "not whitelisted",
+			defaultMiddlewares: &configuration.DefaultMiddlewares{WhitelistSourceRange: []string{"10.0.0.0/8"}},
+			remoteAddr:         "192.168.1.1:1234",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			desc: "headers",
+			defaultMiddlewares: &configuration.DefaultMiddlewares{
+				Headers: &types.Headers{CustomResponseHeaders: map[string]string{"X-Platform": "traefik"}},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders:    map[string]string{"X-Platform": "traefik"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			globalConfig := configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{
+					"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+				},
+				Ping:               &ping.Handler{},
+				DefaultMiddlewares: test.defaultMiddlewares,
+			}
+			frontend := buildFrontend(withRoute("ping", "Path:/ping"), withInternalBackend("ping@internal"))
+			dynamicConfigs := types.Configurations{"config": buildDynamicConfig(withFrontend("frontend", frontend))}
+
+			srv := NewServer(globalConfig, nil)
+			entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "http://localhost/ping", nil)
+			if len(test.remoteAddr) > 0 {
+				request.RemoteAddr = test.remoteAddr
+			}
+			entryPoints["http"].httpRouter.ServeHTTP(recorder, request)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, recorder.Header().Get(name))
+			}
+		})
+	}
+}
+
+func TestDefaultMiddlewaresNames(t *testing.T) {
+	srv := NewServer(configuration.GlobalConfiguration{}, nil)
+	d, err := srv.buildDefaultMiddlewares(&configuration.DefaultMiddlewares{
+		RequestIDHeader: "X-Request-Id",
+		Compress:        true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"default-requestid", "default-compress"}, d.middlewareNames(&configuration.EntryPoint{}))
+	assert.Equal(t, []string{"default-requestid"}, d.middlewareNames(&configuration.EntryPoint{Compress: true}))
+}
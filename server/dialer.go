@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/types"
+)
+
+// defaultFallbackDelay is the delay before racing the fallback address family, as recommended by RFC 6555.
+const defaultFallbackDelay = 300 * time.Millisecond
+
+// policyDialer dials the backend servers preferring an address family,
+// trying all the addresses of the preferred family before the other ones.
+type policyDialer struct {
+	preferIPv6   bool
+	lookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+	dial         func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// newPolicyDialer returns the dial function of the given types.DialPolicy.
+func newPolicyDialer(dialer *net.Dialer, policy string) (func(ctx context.Context, network, address string) (net.Conn, error), error) {
+	d := &policyDialer{
+		lookupIPAddr: net.DefaultResolver.LookupIPAddr,
+		dial:         dialer.DialContext,
+	}
+
+	switch policy {
+	case types.DialPolicyPreferIPv6:
+		d.preferIPv6 = true
+	case types.DialPolicyPreferIPv4:
+	case types.DialPolicyHappyEyeballs:
+		// the standard dialer races the address families as described by RFC 6555
+		happyEyeballs := *dialer
+		happyEyeballs.DualStack = true
+		happyEyeballs.FallbackDelay = defaultFallbackDelay
+		return happyEyeballs.DialContext, nil
+	default:
+		return nil, fmt.Errorf("unknown dial policy %q", policy)
+	}
+
+	return d.DialContext, nil
+}
+
+// DialContext dials the given address, trying all the addresses of the preferred family first.
+func (d *policyDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	// IP literals, zoned ones included, don't have to be resolved
+	if net.ParseIP(strings.SplitN(host, "%", 2)[0]) != nil {
+		return d.dial(ctx, network, address)
+	}
+
+	addrs, err := d.lookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var primaries, fallbacks []net.IPAddr
+	for _, addr := range addrs {
+		if (addr.IP.To4() == nil) == d.preferIPv6 {
+			primaries = append(primaries, addr)
+		} else {
+			fallbacks = append(fallbacks, addr)
+		}
+	}
+
+	lastErr := fmt.Errorf("no address to dial")
+	for _, addr := range append(primaries, fallbacks...) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		conn, err := d.dial(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+var ipv6ZoneRegexp = regexp.MustCompile(`\[([0-9a-fA-F:.]+)%([^\]]+)\]`)
+
+// parseServerURL parses the URL of a backend server, accepting IPv6 literals
+// with an unescaped zone (e.g. http://[fe80::1%eth0]:8080) as built by most providers.
+func parseServerURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err == nil {
+		return u, nil
+	}
+
+	// an escaped zone is already understood by url.Parse
+	if strings.Contains(rawURL, "%25") {
+		return nil, err
+	}
+
+	escaped := ipv6ZoneRegexp.ReplaceAllString(rawURL, "[$1%25$2]")
+	if escaped == rawURL {
+		return nil, err
+	}
+	return url.Parse(escaped)
+}
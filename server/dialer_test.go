@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConn struct {
+	net.Conn
+	address string
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func TestPolicyDialer(t *testing.T) {
+	addrs := []net.IPAddr{
+		{IP: net.ParseIP("10.0.0.1")},
+		{IP: net.ParseIP("fd00::1")},
+		{IP: net.ParseIP("10.0.0.2")},
+		{IP: net.ParseIP("fe80::1"), Zone: "eth0"},
+	}
+
+	testCases := []struct {
+		desc             string
+		policy           string
+		address          string
+		failing          map[string]bool
+		expectedAddress  string
+		expectedAttempts []string
+		expectedError    bool
+	}{
+		{
+			desc:             "prefer IPv6",
+			policy:           types.DialPolicyPreferIPv6,
+			address:          "backend:80",
+			failing:          map[string]bool{"[fd00::1]:80": true},
+			expectedAddress:  "[fe80::1%eth0]:80",
+			expectedAttempts: []string{"[fd00::1]:80", "[fe80::1%eth0]:80"},
+		},
+		{
+			desc:             "prefer IPv4",
+			policy:           types.DialPolicyPreferIPv4,
+			address:          "backend:80",
+			failing:          map[string]bool{"10.0.0.1:80": true, "10.0.0.2:80": true},
+			expectedAddress:  "[fd00::1]:80",
+			expectedAttempts: []string{"10.0.0.1:80", "10.0.0.2:80", "[fd00::1]:80"},
+		},
+		{
+			desc:             "IP literal with zone",
+			policy:           types.DialPolicyPreferIPv4,
+			address:          "[fe80::2%eth0]:80",
+			expectedAddress:  "[fe80::2%eth0]:80",
+			expectedAttempts: []string{"[fe80::2%eth0]:80"},
+		},
+		{
+			desc:          "all addresses failing",
+			policy:        types.DialPolicyPreferIPv6,
+			address:       "backend:80",
+			failing:       map[string]bool{"[fd00::1]:80": true, "[fe80::1%eth0]:80": true, "10.0.0.1:80": true, "10.0.0.2:80": true},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts []string
+			dialer := &policyDialer{
+				preferIPv6: test.policy == types.DialPolicyPreferIPv6,
+				lookupIPAddr: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+					return addrs, nil
+				},
+				dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+					attempts = append(attempts, address)
+					if test.failing[address] {
+						return nil, errors.New("connection refused")
+					}
+					return &fakeConn{address: address}, nil
+				},
+			}
+
+			conn, err := dialer.DialContext(context.Background(), "tcp", test.address)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedAddress, conn.(*fakeConn).address)
+			assert.Equal(t, test.expectedAttempts, attempts)
+		})
+	}
+}
+
+func TestNewPolicyDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	for _, policy := range []string{types.DialPolicyPreferIPv6, types.DialPolicyPreferIPv4, types.DialPolicyHappyEyeballs} {
+		dial, err := newPolicyDialer(&net.Dialer{Timeout: time.Second}, policy)
+		require.NoError(t, err)
+
+		conn, err := dial(context.Background(), "tcp", listener.Addr().String())
+		require.NoError(t, err, policy)
+		conn.Close()
+	}
+}
+
+func TestNewPolicyDialerUnknownPolicy(t *testing.T) {
+	_, err := newPolicyDialer(&net.Dialer{}, "foo")
+	assert.Error(t, err)
+}
+
+func TestParseServerURL(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		rawURL       string
+		expectedHost string
+		expectedErr  bool
+	}{
+		{
+			desc:         "IPv4",
+			rawURL:       "http://10.0.0.1:80",
+			expectedHost: "10.0.0.1:80",
+		},
+		{
+			desc:         "IPv6",
+			rawURL:       "http://[fd00::1]:80",
+			expectedHost: "[fd00::1]:80",
+		},
+		{
+			desc:         "IPv6 with escaped zone",
+			rawURL:       "http://[fe80::1%25eth0]:80",
+			expectedHost: "[fe80::1%eth0]:80",
+		},
+		{
+			desc:         "IPv6 with unescaped zone",
+			rawURL:       "http://[fe80::1%eth0]:80",
+			expectedHost: "[fe80::1%eth0]:80",
+		},
+		{
+			desc:        "IPv6 with escaped zone and invalid path",
+			rawURL:      "http://[fe80::1%25eth0]:80/%zz",
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid URL",
+			rawURL:      "http://[fe80::1:80",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			u, err := parseServerURL(test.rawURL)
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedHost, u.Host)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+
+	"github.com/containous/traefik/configuration"
+)
+
+// entryPointSockets are the sockets an entry point listens on, handed over to the entry point replacing it on reload
+// for the new one to listen before the previous one is stopped.
+type entryPointSockets struct {
+	listeners []net.Listener
+	// addresses are the addresses of the listeners, in the same order, unless the sockets were passed by systemd
+	addresses  []configuration.ListenAddress
+	packetConn net.PacketConn
+}
+
+// handOver duplicates the sockets listening on the addresses, the sockets passed by systemd being all duplicated.
+func (s *entryPointSockets) handOver(addresses []configuration.ListenAddress) (*entryPointSockets, error) {
+	handedOver := &entryPointSockets{}
+	if s == nil {
+		return handedOver, nil
+	}
+
+	if s.packetConn != nil {
+		if len(s.addresses) > 0 && !reflect.DeepEqual(s.addresses, addresses) {
+			return handedOver, nil
+		}
+		conn, err := duplicatePacketConn(s.packetConn)
+		if err != nil {
+			return nil, err
+		}
+		handedOver.packetConn = conn
+		handedOver.addresses = s.addresses
+		return handedOver, nil
+	}
+
+	for i, listener := range s.listeners {
+		if len(s.addresses) > 0 && !containsListenAddress(addresses, s.addresses[i]) {
+			continue
+		}
+		duplicated, err := duplicateListener(listener)
+		if err != nil {
+			handedOver.close()
+			return nil, err
+		}
+		handedOver.listeners = append(handedOver.listeners, duplicated)
+		if len(s.addresses) > 0 {
+			handedOver.addresses = append(handedOver.addresses, s.addresses[i])
+		}
+	}
+	return handedOver, nil
+}
+
+// listener returns the socket listening on the address, if any.
+func (s *entryPointSockets) listener(address configuration.ListenAddress) net.Listener {
+	if s == nil {
+		return nil
+	}
+	for i, listenAddress := range s.addresses {
+		if listenAddress == address && i < len(s.listeners) {
+			return s.listeners[i]
+		}
+	}
+	return nil
+}
+
+// activated returns whether the sockets were passed by systemd.
+func (s *entryPointSockets) activated() bool {
+	return s != nil && len(s.addresses) == 0 && (len(s.listeners) > 0 || s.packetConn != nil)
+}
+
+// unlinkOnClose removes the Unix domain sockets when they are closed, the entry point owning them from then on.
+func (s *entryPointSockets) unlinkOnClose() {
+	if s == nil {
+		return
+	}
+	for _, listener := range s.listeners {
+		if unix, ok := listener.(*unixListener); ok {
+			if unixListener, ok := unix.Listener.(*net.UnixListener); ok {
+				unixListener.SetUnlinkOnClose(true)
+			}
+		}
+	}
+}
+
+func (s *entryPointSockets) close() {
+	if s == nil {
+		return
+	}
+	for _, listener := range s.listeners {
+		listener.Close()
+	}
+	if s.packetConn != nil {
+		s.packetConn.Close()
+	}
+}
+
+func containsListenAddress(addresses []configuration.ListenAddress, address configuration.ListenAddress) bool {
+	for _, listenAddress := range addresses {
+		if listenAddress == address {
+			return true
+		}
+	}
+	return false
+}
+
+// filer is implemented by the TCP and Unix listeners, and the UDP connections.
+type filer interface {
+	File() (*os.File, error)
+}
+
+// duplicateListener duplicates the socket of the listener.
+// The Unix domain socket isn't removed anymore when the listener is closed, for the duplicated one to keep listening on it.
+func duplicateListener(listener net.Listener) (net.Listener, error) {
+	if unix, ok := listener.(*unixListener); ok {
+		duplicated, err := duplicateListener(unix.Listener)
+		if err != nil {
+			return nil, err
+		}
+		return &unixListener{Listener: duplicated}, nil
+	}
+
+	f, ok := listener.(filer)
+	if !ok {
+		return nil, fmt.Errorf("unable to hand over the socket %s", listener.Addr())
+	}
+	file, err := f.File()
+	if err != nil {
+		return nil, fmt.Errorf("unable to hand over the socket %s: %v", listener.Addr(), err)
+	}
+	defer file.Close()
+
+	if unixListener, ok := listener.(*net.UnixListener); ok {
+		unixListener.SetUnlinkOnClose(false)
+	}
+	return net.FileListener(file)
+}
+
+// duplicatePacketConn duplicates the socket of the connection.
+func duplicatePacketConn(conn net.PacketConn) (net.PacketConn, error) {
+	f, ok := conn.(filer)
+	if !ok {
+		return nil, fmt.Errorf("unable to hand over the socket %s", conn.LocalAddr())
+	}
+	file, err := f.File()
+	if err != nil {
+		return nil, fmt.Errorf("unable to hand over the socket %s: %v", conn.LocalAddr(), err)
+	}
+	defer file.Close()
+
+	return net.FilePacketConn(file)
+}
@@ -23,7 +23,10 @@ func NewRecordingErrorHandler(recorder middlewares.NetErrorRecorder) *RecordingE
 func (eh *RecordingErrorHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, err error) {
 	statusCode := http.StatusInternalServerError
 
-	if e, ok := err.(net.Error); ok {
+	if middlewares.IsBodyTooLarge(err) {
+		// the request body exceeded the limit of the entry point while being forwarded
+		statusCode = http.StatusRequestEntityTooLarge
+	} else if e, ok := err.(net.Error); ok {
 		eh.netErrorRecorder.Record(req.Context())
 		if e.Timeout() {
 			statusCode = http.StatusGatewayTimeout
@@ -41,6 +41,12 @@ func TestServeHTTP(t *testing.T) {
 			wantHTTPStatus:     http.StatusBadGateway,
 			wantNetErrRecorded: true,
 		},
+		{
+			name:               "request body too large",
+			err:                errors.New("http: request body too large"),
+			wantHTTPStatus:     http.StatusRequestEntityTooLarge,
+			wantNetErrRecorded: false,
+		},
 		{
 			name:               "custom error",
 			err:                errors.New("any error"),
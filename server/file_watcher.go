@@ -0,0 +1,49 @@
+package server
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	"gopkg.in/fsnotify.v1"
+)
+
+// watchFiles calls onChange on the events of the given files until the pool is stopped.
+// The directories of the files are watched, so that the files replaced by a rename are caught.
+func watchFiles(pool *safe.Pool, paths []string, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creating file watcher: %s", err)
+	}
+
+	files := make(map[string]bool)
+	directories := make(map[string]bool)
+	for _, file := range paths {
+		files[filepath.Clean(file)] = true
+		directories[filepath.Dir(filepath.Clean(file))] = true
+	}
+	for directory := range directories {
+		if err := watcher.Add(directory); err != nil {
+			watcher.Close()
+			return fmt.Errorf("error adding file watcher: %s", err)
+		}
+	}
+
+	pool.Go(func(stop chan bool) {
+		defer watcher.Close()
+		for {
+			select {
+			case <-stop:
+				return
+			case evt := <-watcher.Events:
+				if files[filepath.Clean(evt.Name)] {
+					onChange()
+				}
+			case err := <-watcher.Errors:
+				log.Errorf("Watcher event error: %s", err)
+			}
+		}
+	})
+	return nil
+}
@@ -1,11 +1,13 @@
 package server
 
 import (
+	"fmt"
 	"net"
 	"net/http"
 	"os"
 
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
 	"github.com/containous/traefik/whitelist"
 	"github.com/vulcand/oxy/forward"
 )
@@ -58,3 +60,46 @@ func (h *headerRewriter) Rewrite(req *http.Request) {
 		h.insecureRewriter.Rewrite(req)
 	}
 }
+
+// newHostHeaderRewriter wraps a header rewriter to set the Host header of the forwarded requests
+// according to the given strategy, WebSocket upgrades included.
+func newHostHeaderRewriter(next forward.ReqRewriter, hostHeader *types.HostHeader) (forward.ReqRewriter, error) {
+	switch hostHeader.Strategy {
+	case types.HostHeaderPass, types.HostHeaderBackend:
+	case types.HostHeaderCustom:
+		if len(hostHeader.Value) == 0 {
+			return nil, fmt.Errorf("the %s Host header strategy requires a value", types.HostHeaderCustom)
+		}
+	default:
+		return nil, fmt.Errorf("unknown Host header strategy %q", hostHeader.Strategy)
+	}
+
+	return &hostHeaderRewriter{next: next, strategy: hostHeader.Strategy, value: hostHeader.Value}, nil
+}
+
+type hostHeaderRewriter struct {
+	next     forward.ReqRewriter
+	strategy string
+	value    string
+}
+
+func (h *hostHeaderRewriter) Rewrite(req *http.Request) {
+	// the forwarded headers are computed from the requested host
+	h.next.Rewrite(req)
+
+	var host string
+	switch h.strategy {
+	case types.HostHeaderBackend:
+		host = req.URL.Host
+	case types.HostHeaderCustom:
+		host = h.value
+	default:
+		return
+	}
+
+	req.Host = host
+	// WebSocket requests are dialed with their headers, Host included
+	if _, ok := req.Header["Host"]; ok {
+		req.Header.Set("Host", host)
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noopRewriter struct{}
+
+func (noopRewriter) Rewrite(req *http.Request) {}
+
+func TestHostHeaderRewriter(t *testing.T) {
+	tests := []struct {
+		desc          string
+		hostHeader    *types.HostHeader
+		webSocket     bool
+		expectedHost  string
+		expectedError bool
+	}{
+		{
+			desc:         "pass",
+			hostHeader:   &types.HostHeader{Strategy: types.HostHeaderPass},
+			expectedHost: "frontend.example.com",
+		},
+		{
+			desc:         "backend",
+			hostHeader:   &types.HostHeader{Strategy: types.HostHeaderBackend},
+			expectedHost: "10.0.0.1:8080",
+		},
+		{
+			desc:         "custom",
+			hostHeader:   &types.HostHeader{Strategy: types.HostHeaderCustom, Value: "internal.local"},
+			expectedHost: "internal.local",
+		},
+		{
+			desc:         "custom with WebSocket",
+			hostHeader:   &types.HostHeader{Strategy: types.HostHeaderCustom, Value: "internal.local"},
+			webSocket:    true,
+			expectedHost: "internal.local",
+		},
+		{
+			desc:          "custom without value",
+			hostHeader:    &types.HostHeader{Strategy: types.HostHeaderCustom},
+			expectedError: true,
+		},
+		{
+			desc:          "unknown strategy",
+			hostHeader:    &types.HostHeader{Strategy: "foo"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rewriter, err := newHostHeaderRewriter(noopRewriter{}, test.hostHeader)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://10.0.0.1:8080/", nil)
+			req.Host = "frontend.example.com"
+			if test.webSocket {
+				req.Header.Set("Host", req.Host)
+			}
+
+			rewriter.Rewrite(req)
+
+			assert.Equal(t, test.expectedHost, req.Host)
+			if test.webSocket {
+				assert.Equal(t, test.expectedHost, req.Header.Get("Host"))
+			}
+		})
+	}
+}
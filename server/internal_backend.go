@@ -0,0 +1,136 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/containous/mux"
+	"github.com/containous/traefik/api"
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/metrics"
+	"github.com/containous/traefik/middlewares"
+	mauth "github.com/containous/traefik/middlewares/auth"
+	"github.com/containous/traefik/types"
+	"github.com/urfave/negroni"
+)
+
+// internalBackendSuffix marks the backends served by Traefik itself, e.g. api@internal.
+const internalBackendSuffix = "@internal"
+
+// Internal backends, routable by the frontends like the other backends.
+const (
+	internalBackendAPI        = "api" + internalBackendSuffix
+	internalBackendDashboard  = "dashboard" + internalBackendSuffix
+	internalBackendPing       = "ping" + internalBackendSuffix
+	internalBackendPrometheus = "prometheus" + internalBackendSuffix
+	internalBackendRest       = "rest" + internalBackendSuffix
+)
+
+func isInternalBackend(backendName string) bool {
+	return strings.HasSuffix(backendName, internalBackendSuffix)
+}
+
+// buildInternalBackendHandler returns the handler serving the routes of an internal backend.
+func (s *Server) buildInternalBackendHandler(backendName string) (http.Handler, error) {
+	router := mux.NewRouter()
+	router.StrictSlash(true)
+	router.SkipClean(true)
+
+	switch backendName {
+	case internalBackendAPI, internalBackendDashboard:
+		if s.globalConfiguration.API == nil {
+			return nil, errors.New("the API is not enabled")
+		}
+		if backendName == internalBackendDashboard {
+			api.DashboardHandler{}.AddRoutes(router)
+			return router, nil
+		}
+		s.globalConfiguration.API.AddRoutes(router)
+		if s.globalConfiguration.API.RBAC != nil {
+			router.Walk(wrapRoute([]negroni.Handler{api.NewRBACMiddleware(s.globalConfiguration.API.RBAC, "")}))
+		}
+	case internalBackendPing:
+		if s.globalConfiguration.Ping == nil {
+			return nil, errors.New("the ping is not enabled")
+		}
+		s.globalConfiguration.Ping.AddRoutes(router)
+	case internalBackendPrometheus:
+		if s.globalConfiguration.Metrics == nil || s.globalConfiguration.Metrics.Prometheus == nil {
+			return nil, errors.New("the Prometheus metrics are not enabled")
+		}
+		metrics.PrometheusHandler{}.AddRoutes(router)
+	case internalBackendRest:
+		if s.globalConfiguration.Rest == nil {
+			return nil, errors.New("the Rest provider is not enabled")
+		}
+		s.globalConfiguration.Rest.AddRoutes(router)
+	default:
+		return nil, fmt.Errorf("unknown internal backend '%s'", backendName)
+	}
+	return router, nil
+}
+
+// buildInternalFrontendHandler appends the handler of the internal backend of the frontend to its middlewares,
+// along with the frontend middlewares applying to the internal services.
+func (s *Server) buildInternalFrontendHandler(n *negroni.Negroni, middlewareNames []string, entryPointName, frontendName string, frontend *types.Frontend, entryPoints configuration.EntryPoints) (http.Handler, []string, error) {
+	handler, err := s.buildInternalBackendHandler(frontend.Backend)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var handlerMiddlewareNames []string
+	if frontend.RateLimit != nil && len(frontend.RateLimit.RateSet) > 0 {
+		handler, err = s.buildRateLimiter(handler, frontend.RateLimit)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating rate limiter: %v", err)
+		}
+		handler = s.wrapHTTPHandlerWithAccessLog(handler, fmt.Sprintf("rate limit for %s", frontendName))
+		handlerMiddlewareNames = append(handlerMiddlewareNames, "ratelimit")
+	}
+
+	ipWhitelistMiddleware, err := configureIPWhitelistMiddleware(frontend.WhitelistSourceRange, frontend.WhitelistIPStrategy)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating IP Whitelister: %v", err)
+	} else if ipWhitelistMiddleware != nil {
+		ipWhitelistMiddleware = s.wrapNegroniHandlerWithAccessLog(ipWhitelistMiddleware, fmt.Sprintf("ipwhitelister for %s", frontendName))
+		n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("IP whitelist", ipWhitelistMiddleware, false))
+		middlewareNames = append(middlewareNames, "whitelist")
+	}
+
+	if frontend.Redirect != nil && entryPointName != frontend.Redirect.EntryPoint {
+		rewrite, err := s.buildRedirectHandler(entryPointName, frontend.Redirect, entryPoints)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating Frontend Redirect: %v", err)
+		}
+		n.Use(s.wrapNegroniHandlerWithAccessLog(rewrite, fmt.Sprintf("frontend redirect for %s", frontendName)))
+		middlewareNames = append(middlewareNames, "redirect")
+	}
+
+	if len(frontend.BasicAuth) > 0 {
+		auth := &types.Auth{Basic: &types.Basic{Users: frontend.BasicAuth}}
+		authMiddleware, err := mauth.NewAuthenticator(auth, s.tracingMiddleware)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating Auth: %v", err)
+		}
+		n.Use(s.wrapNegroniHandlerWithAccessLog(authMiddleware, fmt.Sprintf("Auth for %s", frontendName)))
+		middlewareNames = append(middlewareNames, "basicauth")
+	}
+
+	if headerMiddleware := middlewares.NewHeaderFromStruct(frontend.Headers); headerMiddleware != nil {
+		log.Debugf("Adding header middleware for frontend %s", frontendName)
+		n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("Header", headerMiddleware, false))
+		middlewareNames = append(middlewareNames, "headers")
+	}
+
+	if secureMiddleware := middlewares.NewSecure(frontend.Headers); secureMiddleware != nil {
+		log.Debugf("Adding secure middleware for frontend %s", frontendName)
+		n.UseFunc(secureMiddleware.HandlerFuncWithNext)
+		middlewareNames = append(middlewareNames, "secure")
+	}
+
+	n.UseHandler(handler)
+	return n, append(middlewareNames, handlerMiddlewareNames...), nil
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/ping"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withInternalBackend(backendName string) func(*types.Frontend) {
+	return func(fe *types.Frontend) {
+		fe.Backend = backendName
+	}
+}
+
+func TestServerLoadConfigInternalBackends(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		frontend           *types.Frontend
+		path               string
+		remoteAddr         string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			desc:               "ping",
+			frontend:           buildFrontend(withRoute("ping", "Path:/ping"), withInternalBackend("ping@internal")),
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "OK",
+		},
+		{
+			desc: "ping behind basic auth",
+			frontend: buildFrontend(withRoute("ping", "Path:/ping"), withInternalBackend("ping@internal"), func(fe *types.Frontend) {
+				fe.BasicAuth = []string{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"}
+			}),
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc: "ping behind whitelist",
+			frontend: buildFrontend(withRoute("ping", "Path:/ping"), withInternalBackend("ping@internal"), func(fe *types.Frontend) {
+				fe.WhitelistSourceRange = []string{"10.0.0.0/8"}
+			}),
+			remoteAddr:         "192.168.1.1:1234",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			desc:               "disabled internal backend",
+			frontend:           buildFrontend(withRoute("api", "PathPrefix:/api"), withInternalBackend("api@internal")),
+			path:               "/api/providers",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "unknown internal backend",
+			frontend:           buildFrontend(withRoute("ping", "Path:/ping"), withInternalBackend("foo@internal")),
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			globalConfig := configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{
+					"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+				},
+				Ping: &ping.Handler{},
+			}
+			dynamicConfigs := types.Configurations{"config": buildDynamicConfig(withFrontend("frontend", test.frontend))}
+
+			srv := NewServer(globalConfig, nil)
+			entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			path := "/ping"
+			if len(test.path) > 0 {
+				path = test.path
+			}
+			request := httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil)
+			if len(test.remoteAddr) > 0 {
+				request.RemoteAddr = test.remoteAddr
+			}
+			entryPoints["http"].httpRouter.ServeHTTP(recorder, request)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+			if len(test.expectedBody) > 0 {
+				assert.Equal(t, test.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"sync"
+)
+
+// errListenerClosed is the error of the accepts on a closed listener, worded as the one of the net package.
+var errListenerClosed = errors.New("use of closed network connection")
+
+// multiListener merges the listeners of the addresses of an entry point,
+// for its HTTP server to serve all of them.
+type multiListener struct {
+	listeners []net.Listener
+	conns     chan net.Conn
+	errs      chan error
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func newMultiListener(listeners []net.Listener) *multiListener {
+	l := &multiListener{
+		listeners: listeners,
+		conns:     make(chan net.Conn),
+		errs:      make(chan error),
+		done:      make(chan struct{}),
+	}
+	for _, listener := range listeners {
+		go l.accept(listener)
+	}
+	return l
+}
+
+func (l *multiListener) accept(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case l.errs <- err:
+			case <-l.done:
+				return
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+
+		select {
+		case l.conns <- conn:
+		case <-l.done:
+			conn.Close()
+			return
+		}
+	}
+}
+
+// Accept returns the next connection accepted on any of the listeners,
+// or the first error of a listener, which stops the server as with a single listener.
+func (l *multiListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case err := <-l.errs:
+		return nil, err
+	case <-l.done:
+		return nil, &net.OpError{Op: "accept", Net: l.Addr().Network(), Addr: l.Addr(), Err: errListenerClosed}
+	}
+}
+
+// Close closes all the listeners.
+func (l *multiListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.done)
+		for _, listener := range l.listeners {
+			if closeErr := listener.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
+}
+
+// Addr returns the address of the first listener, the main address of the entry point.
+func (l *multiListener) Addr() net.Addr {
+	return l.listeners[0].Addr()
+}
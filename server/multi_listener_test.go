@@ -0,0 +1,63 @@
+package server
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiListener(t *testing.T) {
+	sockets, err := listen("http", &configuration.EntryPoint{
+		Address:   "127.0.0.1:0",
+		Addresses: []string{"127.0.0.1:0"},
+	}, nil)
+	require.NoError(t, err)
+	listeners := sockets.listeners
+	require.Len(t, listeners, 2)
+
+	listener := newMultiListener(listeners)
+	assert.Equal(t, listeners[0].Addr(), listener.Addr())
+
+	server := &http.Server{Handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(req.Context().Value(http.LocalAddrContextKey).(net.Addr).String()))
+	})}
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+
+	for _, l := range listeners {
+		resp, err := http.Get("http://" + l.Addr().String())
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, l.Addr().String(), string(body))
+	}
+
+	require.NoError(t, server.Close())
+	assert.Equal(t, http.ErrServerClosed, <-served)
+
+	// all the listeners are closed
+	for _, l := range listeners {
+		_, err := net.Dial("tcp", l.Addr().String())
+		assert.Error(t, err)
+	}
+}
+
+func TestListenAddressInUse(t *testing.T) {
+	used, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer used.Close()
+
+	_, err = listen("http", &configuration.EntryPoint{
+		Address:   "127.0.0.1:0",
+		Addresses: []string{used.Addr().String()},
+	}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+)
+
+// resolveProviderConflicts resolves the frontends defined by several providers according to the conflict policy,
+// and returns the resolved configurations with the conflicts.
+// The backends are scoped to the providers defining them, and never conflict.
+// Without conflict policy, the configurations are returned as is.
+// The configurations of the providers are left unchanged, the resolved ones being copied.
+func resolveProviderConflicts(configurations types.Configurations, providersMerge *types.ProvidersMerge) (types.Configurations, []types.ProviderConflict, error) {
+	if providersMerge == nil || len(providersMerge.ConflictPolicy) == 0 {
+		return configurations, nil, nil
+	}
+	policy := providersMerge.ConflictPolicy
+
+	providerNames := sortedProviderNames(configurations)
+	sort.SliceStable(providerNames, func(i, j int) bool {
+		return providersMerge.Priority(providerNames[i]) > providersMerge.Priority(providerNames[j])
+	})
+
+	frontendProviders := make(map[string][]string)
+	for _, providerName := range providerNames {
+		for frontendName := range configurations[providerName].Frontends {
+			frontendProviders[frontendName] = append(frontendProviders[frontendName], providerName)
+		}
+	}
+
+	conflicts := findConflicts("frontend", frontendProviders, policy)
+	if len(conflicts) == 0 {
+		return configurations, nil, nil
+	}
+
+	var descriptions []string
+	for _, conflict := range conflicts {
+		descriptions = append(descriptions, fmt.Sprintf("%s %s (%s)", conflict.Kind, conflict.Name, strings.Join(conflict.Providers, ", ")))
+	}
+	if policy == types.ConflictPolicyError {
+		return nil, conflicts, fmt.Errorf("defined by several providers: %s", strings.Join(descriptions, ", "))
+	}
+	log.Warnf("Defined by several providers, resolved with the %s policy: %s", policy, strings.Join(descriptions, ", "))
+
+	resolved := make(types.Configurations, len(configurations))
+	for providerName, configuration := range configurations {
+		resolvedConfiguration := *configuration
+		resolvedConfiguration.Frontends = make(map[string]*types.Frontend, len(configuration.Frontends))
+		for name, frontend := range configuration.Frontends {
+			resolvedConfiguration.Frontends[name] = frontend
+		}
+		resolvedConfiguration.Backends = make(map[string]*types.Backend, len(configuration.Backends))
+		for name, backend := range configuration.Backends {
+			resolvedConfiguration.Backends[name] = backend
+		}
+		resolved[providerName] = &resolvedConfiguration
+	}
+
+	for _, conflict := range conflicts {
+		owner := conflict.Providers[0]
+		frontend := configurations[owner].Frontends[conflict.Name]
+		if policy == types.ConflictPolicyMerge {
+			var frontends []*types.Frontend
+			for _, providerName := range conflict.Providers {
+				frontends = append(frontends, configurations[providerName].Frontends[conflict.Name])
+			}
+			frontend = mergeFrontends(frontends)
+
+			// the merged routes are forwarded to the servers of the backends of all the frontends, through the backend of the owner
+			if backend, ok := resolved[owner].Backends[frontend.Backend]; ok {
+				backends := []*types.Backend{backend}
+				for _, providerName := range conflict.Providers[1:] {
+					otherFrontend := configurations[providerName].Frontends[conflict.Name]
+					if otherBackend, ok := configurations[providerName].Backends[otherFrontend.Backend]; ok {
+						backends = append(backends, otherBackend)
+					}
+				}
+				resolved[owner].Backends[frontend.Backend] = mergeBackends(backends)
+			}
+		}
+
+		// the frontend is only wired once, by the provider with the highest priority
+		resolved[owner].Frontends[conflict.Name] = frontend
+		for _, providerName := range conflict.Providers[1:] {
+			delete(resolved[providerName].Frontends, conflict.Name)
+		}
+	}
+
+	return resolved, conflicts, nil
+}
+
+// findConflicts returns the conflicts of the frontends defined by several providers, sorted by name.
+func findConflicts(kind string, providersByName map[string][]string, policy string) []types.ProviderConflict {
+	var conflicts []types.ProviderConflict
+	for name, providerNames := range providersByName {
+		if len(providerNames) > 1 {
+			conflicts = append(conflicts, types.ProviderConflict{
+				Kind:      kind,
+				Name:      name,
+				Providers: providerNames,
+				Policy:    policy,
+			})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Name < conflicts[j].Name
+	})
+	return conflicts
+}
+
+// mergeFrontends returns the first frontend with the routes of the next ones it doesn't define.
+func mergeFrontends(frontends []*types.Frontend) *types.Frontend {
+	merged := *frontends[0]
+	merged.Routes = make(map[string]types.Route)
+	for _, frontend := range frontends {
+		for name, route := range frontend.Routes {
+			if _, ok := merged.Routes[name]; !ok {
+				merged.Routes[name] = route
+			}
+		}
+	}
+	return &merged
+}
+
+// mergeBackends returns the first backend with the servers of the next ones it doesn't define.
+func mergeBackends(backends []*types.Backend) *types.Backend {
+	merged := *backends[0]
+	merged.Servers = make(map[string]types.Server)
+	for _, backend := range backends {
+		for name, server := range backend.Servers {
+			if _, ok := merged.Servers[name]; !ok {
+				merged.Servers[name] = server
+			}
+		}
+	}
+	return &merged
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveProviderConflicts(t *testing.T) {
+	newConfigurations := func() types.Configurations {
+		return types.Configurations{
+			"docker": {
+				Frontends: map[string]*types.Frontend{
+					"web": {Backend: "web", Routes: map[string]types.Route{"docker": {Rule: "Host:docker.localhost"}}},
+					"api": {Backend: "api"},
+				},
+				Backends: map[string]*types.Backend{
+					"web": {Servers: map[string]types.Server{"docker": {URL: "http://10.0.0.1:80"}}},
+					"api": {Servers: map[string]types.Server{"api": {URL: "http://10.0.0.3:80"}}},
+				},
+			},
+			"file": {
+				Frontends: map[string]*types.Frontend{
+					"web": {Backend: "web", Routes: map[string]types.Route{"file": {Rule: "Host:file.localhost"}}},
+				},
+				Backends: map[string]*types.Backend{
+					"web": {Servers: map[string]types.Server{"file": {URL: "http://10.0.0.2:80"}}},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		desc              string
+		providersMerge    *types.ProvidersMerge
+		expectedConflicts []types.ProviderConflict
+		expectedError     string
+		// expected frontend of the owner, and backends of the providers, by provider name
+		expectedFrontend *types.Frontend
+		expectedBackends map[string]*types.Backend
+		expectedOwner    string
+	}{
+		{
+			desc: "first wins by provider name",
+			providersMerge: &types.ProvidersMerge{
+				ConflictPolicy: types.ConflictPolicyFirstWins,
+			},
+			expectedConflicts: []types.ProviderConflict{
+				{Kind: "frontend", Name: "web", Providers: []string{"docker", "file"}, Policy: types.ConflictPolicyFirstWins},
+			},
+			expectedOwner:    "docker",
+			expectedFrontend: &types.Frontend{Backend: "web", Routes: map[string]types.Route{"docker": {Rule: "Host:docker.localhost"}}},
+			expectedBackends: map[string]*types.Backend{
+				"docker": {Servers: map[string]types.Server{"docker": {URL: "http://10.0.0.1:80"}}},
+				"file":   {Servers: map[string]types.Server{"file": {URL: "http://10.0.0.2:80"}}},
+			},
+		},
+		{
+			desc: "first wins by priority",
+			providersMerge: &types.ProvidersMerge{
+				ConflictPolicy: types.ConflictPolicyFirstWins,
+				Priorities:     types.ProviderPriorities{{Provider: "file", Priority: 10}},
+			},
+			expectedConflicts: []types.ProviderConflict{
+				{Kind: "frontend", Name: "web", Providers: []string{"file", "docker"}, Policy: types.ConflictPolicyFirstWins},
+			},
+			expectedOwner:    "file",
+			expectedFrontend: &types.Frontend{Backend: "web", Routes: map[string]types.Route{"file": {Rule: "Host:file.localhost"}}},
+			expectedBackends: map[string]*types.Backend{
+				"docker": {Servers: map[string]types.Server{"docker": {URL: "http://10.0.0.1:80"}}},
+				"file":   {Servers: map[string]types.Server{"file": {URL: "http://10.0.0.2:80"}}},
+			},
+		},
+		{
+			desc: "merge",
+			providersMerge: &types.ProvidersMerge{
+				ConflictPolicy: types.ConflictPolicyMerge,
+				Priorities:     types.ProviderPriorities{{Provider: "file", Priority: 10}},
+			},
+			expectedConflicts: []types.ProviderConflict{
+				{Kind: "frontend", Name: "web", Providers: []string{"file", "docker"}, Policy: types.ConflictPolicyMerge},
+			},
+			expectedOwner: "file",
+			expectedFrontend: &types.Frontend{Backend: "web", Routes: map[string]types.Route{
+				"file":   {Rule: "Host:file.localhost"},
+				"docker": {Rule: "Host:docker.localhost"},
+			}},
+			expectedBackends: map[string]*types.Backend{
+				"docker": {Servers: map[string]types.Server{"docker": {URL: "http://10.0.0.1:80"}}},
+				"file": {Servers: map[string]types.Server{
+					"file":   {URL: "http://10.0.0.2:80"},
+					"docker": {URL: "http://10.0.0.1:80"},
+				}},
+			},
+		},
+		{
+			desc:           "error",
+			providersMerge: &types.ProvidersMerge{ConflictPolicy: types.ConflictPolicyError},
+			expectedConflicts: []types.ProviderConflict{
+				{Kind: "frontend", Name: "web", Providers: []string{"docker", "file"}, Policy: types.ConflictPolicyError},
+			},
+			expectedError: "defined by several providers: frontend web (docker, file)",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			configurations := newConfigurations()
+
+			resolved, conflicts, err := resolveProviderConflicts(configurations, test.providersMerge)
+			assert.Equal(t, test.expectedConflicts, conflicts)
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			// the frontend is only wired by its owner, and each provider keeps its backend
+			for providerName, configuration := range resolved {
+				if providerName == test.expectedOwner {
+					assert.Equal(t, test.expectedFrontend, configuration.Frontends["web"])
+				} else {
+					assert.NotContains(t, configuration.Frontends, "web")
+				}
+				assert.Equal(t, test.expectedBackends[providerName], configuration.Backends["web"])
+			}
+			assert.Contains(t, resolved["docker"].Frontends, "api")
+
+			// the configurations of the providers are unchanged
+			assert.Equal(t, newConfigurations(), configurations)
+		})
+	}
+}
+
+func TestResolveProviderConflictsWithoutConflict(t *testing.T) {
+	configurations := types.Configurations{
+		"docker": {Frontends: map[string]*types.Frontend{"web": {Backend: "web"}}},
+		"file":   {Frontends: map[string]*types.Frontend{"api": {Backend: "api"}}},
+	}
+
+	resolved, conflicts, err := resolveProviderConflicts(configurations, &types.ProvidersMerge{ConflictPolicy: types.ConflictPolicyError})
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, configurations, resolved)
+}
+
+func TestResolveProviderConflictsWithoutPolicy(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		providersMerge *types.ProvidersMerge
+	}{
+		{
+			desc: "without providers merge",
+		},
+		{
+			desc: "with priorities only",
+			providersMerge: &types.ProvidersMerge{
+				Priorities: types.ProviderPriorities{{Provider: "file", Priority: 10}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			configurations := types.Configurations{
+				"docker": {
+					Frontends: map[string]*types.Frontend{"web": {Backend: "web"}},
+					Backends:  map[string]*types.Backend{"web": {Servers: map[string]types.Server{"docker": {URL: "http://10.0.0.1:80"}}}},
+				},
+				"file": {
+					Frontends: map[string]*types.Frontend{"web": {Backend: "web"}},
+					Backends:  map[string]*types.Backend{"web": {Servers: map[string]types.Server{"file": {URL: "http://10.0.0.2:80"}}}},
+				},
+			}
+
+			resolved, conflicts, err := resolveProviderConflicts(configurations, test.providersMerge)
+			require.NoError(t, err)
+			assert.Empty(t, conflicts)
+			assert.Equal(t, configurations, resolved)
+		})
+	}
+}
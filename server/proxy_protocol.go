@@ -0,0 +1,30 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/containous/traefik/proxyprotocol"
+)
+
+// announceClient sets the client connection of the requests on their context,
+// for the PROXY protocol header sent to the backend servers to announce it.
+// A client connection which can't be parsed is announced as unknown.
+func announceClient(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var src, dst net.Addr
+		if host, port, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+			ip := net.ParseIP(host)
+			portNumber, err := strconv.Atoi(port)
+			if ip != nil && err == nil {
+				src = &net.TCPAddr{IP: ip, Port: portNumber}
+			}
+		}
+		if localAddr, ok := req.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+			dst = localAddr
+		}
+
+		next.ServeHTTP(rw, req.WithContext(proxyprotocol.WithAddrs(req.Context(), src, dst)))
+	})
+}
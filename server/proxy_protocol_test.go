@@ -0,0 +1,153 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyProtocolRoundTripper(t *testing.T) {
+	listener, headers := startProxyProtocolBackend(t)
+	defer listener.Close()
+
+	srv := NewServer(configuration.GlobalConfiguration{}, nil)
+	roundTripper, err := srv.getRoundTripper("http", configuration.GlobalConfiguration{}, false, nil, nil, &types.Backend{ProxyProtocol: 1}, map[roundTripperKey]http.RoundTripper{})
+	require.NoError(t, err)
+
+	handler := announceClient(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		outReq := req.WithContext(req.Context())
+		outReq.URL = testhelpers.MustParseURL("http://" + listener.Addr().String())
+		outReq.RequestURI = ""
+		resp, err := roundTripper.RoundTrip(outReq)
+		require.NoError(t, err)
+		resp.Body.Close()
+		rw.WriteHeader(resp.StatusCode)
+	}))
+
+	testCases := []struct {
+		desc       string
+		remoteAddr string
+		expected   string
+	}{
+		{
+			desc:       "IPv4 client",
+			remoteAddr: "10.0.0.1:40000",
+			expected:   "PROXY TCP4 10.0.0.1 10.0.0.2 40000 80\r\n",
+		},
+		{
+			// the connections are not reused by the next clients
+			desc:       "IPv6 client",
+			remoteAddr: "[fd00::1]:40000",
+			expected:   "PROXY TCP6 fd00::1 10.0.0.2 40000 80\r\n",
+		},
+		{
+			desc:       "unknown client",
+			remoteAddr: "pipe",
+			expected:   "PROXY UNKNOWN\r\n",
+		},
+	}
+
+	for _, test := range testCases {
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost/", nil)
+		req.RemoteAddr = test.remoteAddr
+		req = req.WithContext(context.WithValue(req.Context(), http.LocalAddrContextKey, &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 80}))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code, test.desc)
+		assert.Equal(t, test.expected, <-headers, test.desc)
+	}
+}
+
+func TestProxyProtocolInvalidVersion(t *testing.T) {
+	srv := NewServer(configuration.GlobalConfiguration{}, nil)
+	_, err := srv.getRoundTripper("http", configuration.GlobalConfiguration{}, false, nil, nil, &types.Backend{ProxyProtocol: 3}, map[roundTripperKey]http.RoundTripper{})
+	assert.Error(t, err)
+
+	_, err = newPassthroughRoute("frontend", &types.Frontend{Backend: "backend"}, &types.Backend{
+		ProxyProtocol: 3,
+		Servers:       map[string]types.Server{"server": {URL: "tcp://10.0.0.1:5432"}},
+	}, time.Second)
+	assert.Error(t, err)
+}
+
+func TestPassthroughRouteProxyProtocol(t *testing.T) {
+	backend, headers := startProxyProtocolBackend(t)
+	defer backend.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	route := &passthroughRoute{
+		frontendName:  "frontend",
+		addresses:     []string{backend.Addr().String()},
+		dialTimeout:   time.Second,
+		proxyProtocol: 1,
+	}
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		route.forward(conn)
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = io.WriteString(conn, "GET / HTTP/1.1\r\nHost: localhost\r\n\r\n")
+	require.NoError(t, err)
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	clientAddr := conn.LocalAddr().(*net.TCPAddr)
+	assert.Equal(t, "PROXY TCP4 127.0.0.1 127.0.0.1 "+strconv.Itoa(clientAddr.Port)+" "+strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)+"\r\n", <-headers)
+}
+
+// startProxyProtocolBackend starts a server answering the HTTP requests sent after a PROXY protocol header,
+// recording the header line of each connection.
+func startProxyProtocolBackend(t *testing.T) (net.Listener, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	headers := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				header, _ := reader.ReadString('\n')
+				headers <- header
+				for {
+					req, err := http.ReadRequest(reader)
+					if err != nil {
+						return
+					}
+					req.Body.Close()
+					io.WriteString(conn, "HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n")
+				}
+			}()
+		}
+	}()
+	return listener, headers
+}
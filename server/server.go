@@ -7,7 +7,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	stdlog "log"
 	"net"
 	"net/http"
@@ -22,9 +21,16 @@ import (
 	"time"
 
 	"github.com/armon/go-proxyproto"
+	"github.com/containous/flaeg"
 	"github.com/containous/mux"
+	"github.com/containous/traefik/acme"
+	"github.com/containous/traefik/api"
+	"github.com/containous/traefik/audit"
+	"github.com/containous/traefik/canary"
 	"github.com/containous/traefik/cluster"
 	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/ctmonitor"
+	"github.com/containous/traefik/drain"
 	"github.com/containous/traefik/healthcheck"
 	"github.com/containous/traefik/log"
 	"github.com/containous/traefik/metrics"
@@ -33,12 +39,16 @@ import (
 	mauth "github.com/containous/traefik/middlewares/auth"
 	"github.com/containous/traefik/middlewares/redirect"
 	"github.com/containous/traefik/middlewares/tracing"
+	"github.com/containous/traefik/notification"
 	"github.com/containous/traefik/provider"
+	"github.com/containous/traefik/provider/status"
+	"github.com/containous/traefik/proxyprotocol"
 	"github.com/containous/traefik/rules"
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/server/cookie"
 	traefikTls "github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
+	"github.com/containous/traefik/vault"
 	"github.com/containous/traefik/whitelist"
 	"github.com/eapache/channels"
 	"github.com/sirupsen/logrus"
@@ -52,6 +62,7 @@ import (
 	"github.com/vulcand/oxy/roundrobin"
 	"github.com/vulcand/oxy/utils"
 	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 var (
@@ -61,29 +72,81 @@ var (
 // Server is the reverse-proxy/load-balancer engine
 type Server struct {
 	serverEntryPoints             serverEntryPoints
+	serverEntryPointsLock         sync.RWMutex
 	configurationChan             chan types.ConfigMessage
 	configurationValidatedChan    chan types.ConfigMessage
 	signals                       chan os.Signal
 	stopChan                      chan bool
 	currentConfigurations         safe.Safe
+	providerConflicts             safe.Safe
 	providerConfigUpdateMap       map[string]chan types.ConfigMessage
 	globalConfiguration           configuration.GlobalConfiguration
 	accessLoggerMiddleware        *accesslog.LogHandler
 	tracingMiddleware             *tracing.Tracing
 	routinesPool                  *safe.Pool
+	providersPool                 *safe.Pool
 	leadership                    *cluster.Leadership
 	defaultForwardingRoundTripper http.RoundTripper
+	forwardingRoundTrippers       map[roundTripperKey]http.RoundTripper
+	adaptiveLimiters              map[adaptiveLimiterKey]*middlewares.AdaptiveLimiter
+	adaptiveLimitersLock          sync.Mutex
 	metricsRegistry               metrics.Registry
 	provider                      provider.Provider
+	providerConfiguration         string
+	staticConfigurationLoader     func() (*configuration.GlobalConfiguration, error)
+	staticConfigurationChan       chan configuration.GlobalConfiguration
+	overloadLimiter               *middlewares.OverloadLimiter
+	defaultMiddlewares            *defaultMiddlewares
+	ocspStapler                   *traefikTls.OCSPStapler
+	sessionTicketKeys             *sessionTicketKeys
+	vaultPKI                      *vault.PKI
 }
 
 type serverEntryPoints map[string]*serverEntryPoint
 
+// roundTripperKey identifies the settings of a round tripper which differ from the default one.
+type roundTripperKey struct {
+	timeouts      configuration.ForwardingTimeouts
+	proxyURL      string
+	dialPolicy    string
+	proxyProtocol int
+	tls           string
+}
+
+// adaptiveLimiterKey identifies the adaptive limiter of a backend, recreated when its limits change.
+type adaptiveLimiterKey struct {
+	backend string
+	config  types.AdaptiveLimit
+}
+
 type serverEntryPoint struct {
 	httpServer *http.Server
+	// handler is the handler of the HTTP server, before HTTP/2 cleartext is set up on it
+	handler http.Handler
+	// tlsConfig is the TLS configuration of the HTTP server, which sets its own one when serving plain HTTP
+	tlsConfig  *tls.Config
 	listener   net.Listener
 	httpRouter *middlewares.HandlerSwitcher
 	certs      safe.Safe
+	// passthrough holds the passthroughRoutes of the TLS frontends which are not terminated
+	passthrough safe.Safe
+	// clientCAs holds the frontendClientCAs of the TLS frontends with their own client CA
+	clientCAs safe.Safe
+	// tlsOptions holds the frontendTLSOptions of the TLS frontends selecting named TLS options
+	tlsOptions safe.Safe
+	// certificatesReloader holds the staticCertificatesReloader of a TLS entry point watching its certificate files
+	certificatesReloader safe.Safe
+	// tcp holds the tcpRoutes of the frontends of a TCP entry point
+	tcp         safe.Safe
+	tcpListener *tcpListener
+	// udp holds the passthroughRoute of the frontend of a UDP entry point
+	udp          safe.Safe
+	udpForwarder *udpForwarder
+	drain        *drain.EntryPoint
+	// sockets are the sockets the entry point listens on, or the ones handed over to it before it is set up
+	sockets *entryPointSockets
+	// routinesPool runs the routines of the entry point, e.g. watching its certificates, stopped with it
+	routinesPool *safe.Pool
 }
 
 // NewServer returns an initialized Server.
@@ -96,6 +159,7 @@ func NewServer(globalConfiguration configuration.GlobalConfiguration, provider p
 	server.configurationValidatedChan = make(chan types.ConfigMessage, 100)
 	server.signals = make(chan os.Signal, 1)
 	server.stopChan = make(chan bool, 1)
+	server.staticConfigurationChan = make(chan configuration.GlobalConfiguration, 1)
 	server.configureSignals()
 	currentConfigurations := make(types.Configurations)
 	server.currentConfigurations.Set(currentConfigurations)
@@ -103,10 +167,24 @@ func NewServer(globalConfiguration configuration.GlobalConfiguration, provider p
 	server.globalConfiguration = globalConfiguration
 	if server.globalConfiguration.API != nil {
 		server.globalConfiguration.API.CurrentConfigurations = &server.currentConfigurations
+		server.globalConfiguration.API.ProviderConflicts = &server.providerConflicts
+
+		if entryPoint, ok := globalConfiguration.EntryPoints[globalConfiguration.API.EntryPoint]; ok &&
+			entryPoint.ForwardedHeaders != nil && len(entryPoint.ForwardedHeaders.TrustedIPs) > 0 {
+			trustedIPs, err := whitelist.NewIP(entryPoint.ForwardedHeaders.TrustedIPs, false)
+			if err != nil {
+				log.Errorf("Invalid trusted IPs of the entrypoint %s: %v", globalConfiguration.API.EntryPoint, err)
+			} else {
+				server.globalConfiguration.API.TrustedIPs = trustedIPs
+			}
+		}
 	}
 
 	server.routinesPool = safe.NewPool(context.Background())
+	server.providersPool = safe.NewPool(context.Background())
 	server.defaultForwardingRoundTripper = createHTTPTransport(globalConfiguration)
+	server.forwardingRoundTrippers = make(map[roundTripperKey]http.RoundTripper)
+	server.adaptiveLimiters = make(map[adaptiveLimiterKey]*middlewares.AdaptiveLimiter)
 
 	server.tracingMiddleware = globalConfiguration.Tracing
 	if globalConfiguration.Tracing != nil && globalConfiguration.Tracing.Backend != "" {
@@ -115,11 +193,35 @@ func NewServer(globalConfiguration configuration.GlobalConfiguration, provider p
 
 	server.metricsRegistry = registerMetricClients(globalConfiguration.Metrics)
 
+	if globalConfiguration.OCSP != nil {
+		server.ocspStapler = traefikTls.NewOCSPStapler(time.Duration(globalConfiguration.OCSP.RefreshInterval), server.metricsRegistry.OCSPStapleAgeGauge())
+	}
+
+	if globalConfiguration.VaultPKI != nil {
+		var err error
+		server.vaultPKI, err = vault.NewPKI(globalConfiguration.VaultPKI)
+		if err != nil {
+			log.Errorf("Unable to create the Vault PKI, its certificates won't be served: %v", err)
+		}
+	}
+
 	if globalConfiguration.Cluster != nil {
 		// leadership creation if cluster mode
 		server.leadership = cluster.NewLeadership(server.routinesPool.Ctx(), globalConfiguration.Cluster)
 	}
 
+	if globalConfiguration.SessionTickets != nil {
+		var err error
+		server.sessionTicketKeys, err = newSessionTicketKeys(time.Duration(globalConfiguration.SessionTickets.RotationInterval), globalConfiguration.SessionTickets.EncryptionKey)
+		if err != nil {
+			log.Errorf("Unable to create the session ticket keys, they won't be rotated: %v", err)
+		} else if server.leadership != nil {
+			if err := server.sessionTicketKeys.share(server.leadership); err != nil {
+				log.Errorf("Unable to share the session ticket keys through the KV store: %v", err)
+			}
+		}
+	}
+
 	if globalConfiguration.AccessLogsFile != "" {
 		globalConfiguration.AccessLog = &types.AccessLog{FilePath: globalConfiguration.AccessLogsFile, Format: accesslog.CommonFormat}
 	}
@@ -131,6 +233,30 @@ func NewServer(globalConfiguration configuration.GlobalConfiguration, provider p
 			log.Warnf("Unable to create log handler: %s", err)
 		}
 	}
+
+	if globalConfiguration.AuditLog != nil && len(globalConfiguration.AuditLog.FilePath) > 0 {
+		if err := audit.OpenFile(globalConfiguration.AuditLog.FilePath); err != nil {
+			log.Errorf("Unable to open audit log file: %s", err)
+		}
+	}
+
+	if err := traefikTls.SetDefaultCertificate(globalConfiguration.DefaultCertificate); err != nil {
+		log.Errorf("Unable to configure the default certificate, falling back to a self-signed one: %s", err)
+	}
+
+	if globalConfiguration.Overload != nil {
+		server.overloadLimiter = middlewares.NewOverloadLimiter(globalConfiguration.Overload)
+	}
+
+	if globalConfiguration.DefaultMiddlewares != nil {
+		var err error
+		server.defaultMiddlewares, err = server.buildDefaultMiddlewares(globalConfiguration.DefaultMiddlewares)
+		if err != nil {
+			log.Fatalf("Error creating the default middlewares: %v", err)
+		}
+	}
+
+	notification.Setup(globalConfiguration.Notifications)
 	return server
 }
 
@@ -139,7 +265,7 @@ func NewServer(globalConfiguration configuration.GlobalConfiguration, provider p
 // An exception to this is the MaxIdleConns setting as we only provide the option MaxIdleConnsPerHost
 // in Traefik at this point in time. Setting this value to the default of 100 could lead to confusing
 // behaviour and backwards compatibility issues.
-func createHTTPTransport(globalConfiguration configuration.GlobalConfiguration) *http.Transport {
+func createDialer(globalConfiguration configuration.GlobalConfiguration) *net.Dialer {
 	dialer := &net.Dialer{
 		Timeout:   configuration.DefaultDialTimeout,
 		KeepAlive: 30 * time.Second,
@@ -148,10 +274,13 @@ func createHTTPTransport(globalConfiguration configuration.GlobalConfiguration)
 	if globalConfiguration.ForwardingTimeouts != nil {
 		dialer.Timeout = time.Duration(globalConfiguration.ForwardingTimeouts.DialTimeout)
 	}
+	return dialer
+}
 
+func createHTTPTransport(globalConfiguration configuration.GlobalConfiguration) *http.Transport {
 	transport := &http.Transport{
 		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer.DialContext,
+		DialContext:           createDialer(globalConfiguration).DialContext,
 		MaxIdleConnsPerHost:   globalConfiguration.MaxIdleConnsPerHost,
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
@@ -159,6 +288,7 @@ func createHTTPTransport(globalConfiguration configuration.GlobalConfiguration)
 	}
 	if globalConfiguration.ForwardingTimeouts != nil {
 		transport.ResponseHeaderTimeout = time.Duration(globalConfiguration.ForwardingTimeouts.ResponseHeaderTimeout)
+		transport.IdleConnTimeout = time.Duration(globalConfiguration.ForwardingTimeouts.IdleConnTimeout)
 	}
 	if globalConfiguration.InsecureSkipVerify {
 		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
@@ -199,37 +329,189 @@ func (s *Server) Start() {
 		s.listenConfigurations(stop)
 	})
 	s.startProvider()
+	s.startCTMonitor()
+	s.startOCSPStapler()
+	s.startSessionTicketKeys()
+	s.startVaultPKI()
+	s.startCertificatesMetrics()
+	s.startACMEChallengeServer()
 	go s.listenSignals()
 }
 
+// startACMEChallengeServer serves the ACME HTTP challenge on the dedicated addresses of the ACME configurations, if any.
+func (s *Server) startACMEChallengeServer() {
+	var addresses []string
+	configurationsByAddress := make(map[string][]*acme.ACME)
+	for _, acmeConfig := range s.acmeConfigurations() {
+		if acmeConfig.HTTPChallenge == nil || len(acmeConfig.HTTPChallenge.Address) == 0 {
+			continue
+		}
+		address := acmeConfig.HTTPChallenge.Address
+		if _, ok := configurationsByAddress[address]; !ok {
+			addresses = append(addresses, address)
+		}
+		configurationsByAddress[address] = append(configurationsByAddress[address], acmeConfig)
+	}
+
+	for _, address := range addresses {
+		s.serveACMEChallenge(address, configurationsByAddress[address])
+	}
+}
+
+func (s *Server) serveACMEChallenge(address string, acmeConfigs []*acme.ACME) {
+	router := mux.NewRouter()
+	acme.AddChallengeRoutes(router, acmeConfigs...)
+	challengeServer := &http.Server{Addr: address, Handler: router}
+
+	listener, err := net.Listen("tcp", challengeServer.Addr)
+	if err != nil {
+		log.Errorf("Unable to listen on %s for the ACME HTTP challenge: %v", challengeServer.Addr, err)
+		return
+	}
+
+	log.Infof("Serving the ACME HTTP challenge on %s", challengeServer.Addr)
+	s.routinesPool.GoCtx(func(ctx context.Context) {
+		safe.Go(func() {
+			<-ctx.Done()
+			challengeServer.Close()
+		})
+		if err := challengeServer.Serve(listener); err != http.ErrServerClosed {
+			log.Errorf("Error serving the ACME HTTP challenge: %v", err)
+		}
+	})
+}
+
+func (s *Server) startCTMonitor() {
+	if s.globalConfiguration.CTMonitor == nil {
+		return
+	}
+
+	monitor, err := ctmonitor.New(s.globalConfiguration.CTMonitor, acme.IssuedSerialNumbers)
+	if err != nil {
+		log.Errorf("Unable to start the Certificate Transparency monitoring: %v", err)
+		return
+	}
+	s.routinesPool.GoCtx(monitor.Run)
+}
+
+func (s *Server) startOCSPStapler() {
+	if s.ocspStapler != nil {
+		s.routinesPool.GoCtx(s.ocspStapler.Run)
+	}
+}
+
+func (s *Server) startSessionTicketKeys() {
+	// in cluster mode, the leader rotates the shared keys
+	if s.sessionTicketKeys != nil && s.leadership == nil {
+		s.routinesPool.GoCtx(s.sessionTicketKeys.run)
+	}
+}
+
+func (s *Server) startVaultPKI() {
+	if s.vaultPKI != nil {
+		s.vaultPKI.AddDomains(s.globalConfiguration.VaultPKI.Domains)
+		s.routinesPool.GoCtx(s.vaultPKI.Run)
+	}
+}
+
 // Wait blocks until server is shutted down.
 func (s *Server) Wait() {
 	<-s.stopChan
 }
 
-// Stop stops the server
+// Stop stops the server, the entry point serving the API being stopped once the others are,
+// for the API to report their drain.
 func (s *Server) Stop() {
 	defer log.Info("Server stopped")
+	s.startDrain()
+
+	s.serverEntryPointsLock.RLock()
+	defer s.serverEntryPointsLock.RUnlock()
+
+	var apiEntryPoint *serverEntryPoint
 	var wg sync.WaitGroup
 	for sepn, sep := range s.serverEntryPoints {
+		if s.globalConfiguration.API != nil && sepn == s.globalConfiguration.API.EntryPoint {
+			apiEntryPoint = sep
+			continue
+		}
 		wg.Add(1)
 		go func(serverEntryPointName string, serverEntryPoint *serverEntryPoint) {
 			defer wg.Done()
-			graceTimeOut := time.Duration(s.globalConfiguration.LifeCycle.GraceTimeOut)
-			ctx, cancel := context.WithTimeout(context.Background(), graceTimeOut)
-			log.Debugf("Waiting %s seconds before killing connections on entrypoint %s...", graceTimeOut, serverEntryPointName)
-			if err := serverEntryPoint.httpServer.Shutdown(ctx); err != nil {
-				log.Debugf("Wait is over due to: %s", err)
-				serverEntryPoint.httpServer.Close()
-			}
-			cancel()
-			log.Debugf("Entrypoint %s closed", serverEntryPointName)
+			serverEntryPoint.stop(serverEntryPointName)
 		}(sepn, sep)
 	}
 	wg.Wait()
+	if apiEntryPoint != nil {
+		apiEntryPoint.stop(s.globalConfiguration.API.EntryPoint)
+	}
 	s.stopChan <- true
 }
 
+// stop closes the entry point once its in-flight requests and connections are finished, or its drain timeout is over.
+func (sep *serverEntryPoint) stop(serverEntryPointName string) {
+	graceTimeOut := sep.drain.Timeout()
+	ctx, cancel := context.WithTimeout(context.Background(), graceTimeOut)
+	defer cancel()
+
+	log.Debugf("Waiting %s seconds before killing connections on entrypoint %s...", graceTimeOut, serverEntryPointName)
+	if sep.udpForwarder != nil {
+		sep.udpForwarder.shutdown(ctx)
+	} else if err := sep.httpServer.Shutdown(ctx); err != nil {
+		log.Debugf("Wait is over due to: %s", err)
+		sep.httpServer.Close()
+	}
+	if sep.tcpListener != nil {
+		sep.tcpListener.drain(ctx)
+	}
+	if sep.routinesPool != nil {
+		sep.routinesPool.Cleanup()
+	}
+	log.Debugf("Entrypoint %s closed", serverEntryPointName)
+}
+
+// newEntryPointDrain registers the drain of an entry point, with the grace timeout of the life cycle
+// unless the entry point has its own drain timeout.
+func (s *Server) newEntryPointDrain(entryPointName string) *drain.EntryPoint {
+	var timeout time.Duration
+	if s.globalConfiguration.LifeCycle != nil {
+		timeout = time.Duration(s.globalConfiguration.LifeCycle.GraceTimeOut)
+	}
+
+	entryPoint := s.globalConfiguration.EntryPoints[entryPointName]
+	if entryPoint.Drain == nil {
+		return drain.GetRegistry().Add(entryPointName, timeout, false, false)
+	}
+	if entryPoint.Drain.Timeout > 0 {
+		timeout = time.Duration(entryPoint.Drain.Timeout)
+	}
+	return drain.GetRegistry().Add(entryPointName, timeout, entryPoint.Drain.CloseConnections, entryPoint.Drain.RejectRequests)
+}
+
+// startDrain starts the drain of all the entry points.
+func (s *Server) startDrain() {
+	s.serverEntryPointsLock.RLock()
+	defer s.serverEntryPointsLock.RUnlock()
+
+	for _, serverEntryPoint := range s.serverEntryPoints {
+		serverEntryPoint.drain.Start()
+	}
+}
+
+// StopGracefully starts the drain of the entry points, waits for the incoming requests to cease
+// during the request accept grace timeout, then stops the server gracefully.
+func (s *Server) StopGracefully() {
+	s.startDrain()
+
+	reqAcceptGraceTimeOut := time.Duration(s.globalConfiguration.LifeCycle.RequestAcceptGraceTimeout)
+	if reqAcceptGraceTimeOut > 0 {
+		log.Infof("Waiting %s for incoming requests to cease", reqAcceptGraceTimeOut)
+		time.Sleep(reqAcceptGraceTimeOut)
+	}
+	log.Info("Stopping server gracefully")
+	s.Stop()
+}
+
 // Close destroys the server
 func (s *Server) Close() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.globalConfiguration.LifeCycle.GraceTimeOut))
@@ -244,6 +526,7 @@ func (s *Server) Close() {
 	}(ctx)
 	stopMetricsClients()
 	s.stopLeadership()
+	s.providersPool.Cleanup()
 	s.routinesPool.Cleanup()
 	close(s.configurationChan)
 	close(s.configurationValidatedChan)
@@ -255,6 +538,10 @@ func (s *Server) Close() {
 			log.Errorf("Error closing access log file: %s", err)
 		}
 	}
+	if err := audit.CloseFile(); err != nil {
+		log.Errorf("Error closing audit log file: %s", err)
+	}
+	notification.Stop()
 	cancel()
 }
 
@@ -274,12 +561,32 @@ func (s *Server) startHTTPServers() {
 	s.serverEntryPoints = s.buildEntryPoints(s.globalConfiguration)
 
 	for newServerEntryPointName, newServerEntryPoint := range s.serverEntryPoints {
-		serverEntryPoint := s.setupServerEntryPoint(newServerEntryPointName, newServerEntryPoint)
-		go s.startServer(serverEntryPoint, s.globalConfiguration)
+		serverEntryPoint, err := s.setupServerEntryPoint(newServerEntryPointName, newServerEntryPoint)
+		if err != nil {
+			log.Fatal("Error preparing server: ", err)
+		}
+		s.startServer(serverEntryPoint, s.globalConfiguration)
 	}
 }
 
-func (s *Server) setupServerEntryPoint(newServerEntryPointName string, newServerEntryPoint *serverEntryPoint) *serverEntryPoint {
+func (s *Server) setupServerEntryPoint(newServerEntryPointName string, newServerEntryPoint *serverEntryPoint) (*serverEntryPoint, error) {
+	newServerEntryPoint.drain = s.newEntryPointDrain(newServerEntryPointName)
+	newServerEntryPoint.routinesPool = safe.NewPool(s.routinesPool.Ctx())
+
+	if entryPoint := s.globalConfiguration.EntryPoints[newServerEntryPointName]; entryPoint.Protocol == configuration.EntryPointProtocolUDP {
+		sessionTimeout := configuration.DefaultUDPSessionTimeout
+		if entryPoint.RespondingTimeouts != nil && entryPoint.RespondingTimeouts.IdleTimeout > 0 {
+			sessionTimeout = time.Duration(entryPoint.RespondingTimeouts.IdleTimeout)
+		}
+		sockets, err := listenPacket(newServerEntryPointName, entryPoint, newServerEntryPoint.sockets)
+		if err != nil {
+			return nil, err
+		}
+		newServerEntryPoint.sockets = sockets
+		newServerEntryPoint.udpForwarder = newUDPForwarder(sockets.packetConn, &newServerEntryPoint.udp, sessionTimeout)
+		return newServerEntryPoint, nil
+	}
+
 	serverMiddlewares := []negroni.Handler{middlewares.NegroniRecoverHandler()}
 	serverInternalMiddlewares := []negroni.Handler{middlewares.NegroniRecoverHandler()}
 
@@ -306,34 +613,91 @@ func (s *Server) setupServerEntryPoint(newServerEntryPointName string, newServer
 		}
 
 	}
+	serverMiddlewares = append(serverMiddlewares, newServerEntryPoint.drain)
+	serverInternalMiddlewares = append(serverInternalMiddlewares, newServerEntryPoint.drain)
+	if headersMiddleware := middlewares.NewDefaultHeaders(s.globalConfiguration.EntryPoints[newServerEntryPointName].Headers); headersMiddleware != nil {
+		// the headers are applied before the other middlewares, for their responses to get them too
+		serverMiddlewares = append(serverMiddlewares, headersMiddleware)
+		serverInternalMiddlewares = append(serverInternalMiddlewares, headersMiddleware)
+	}
+	if tlsOption := s.globalConfiguration.EntryPoints[newServerEntryPointName].TLS; tlsOption != nil && tlsOption.ClientCA.Headers != nil {
+		serverMiddlewares = append(serverMiddlewares, middlewares.NewClientCertHeaders(tlsOption.ClientCA.Headers))
+	}
+	if requestLimits := s.globalConfiguration.EntryPoints[newServerEntryPointName].RequestLimits; requestLimits != nil && (requestLimits.MaxHeaderCount > 0 || requestLimits.MaxURILength > 0) {
+		requestLimiter := middlewares.NewRequestLimiter(requestLimits.MaxHeaderCount, requestLimits.MaxURILength)
+		serverMiddlewares = append(serverMiddlewares, requestLimiter)
+		serverInternalMiddlewares = append(serverInternalMiddlewares, requestLimiter)
+	}
+	if maxConcurrentRequests := s.globalConfiguration.EntryPoints[newServerEntryPointName].MaxConcurrentRequests; maxConcurrentRequests > 0 {
+		concurrencyLimiter := middlewares.NewConcurrencyLimiter(newServerEntryPointName, maxConcurrentRequests, s.metricsRegistry.EntrypointRejectedReqsCounter())
+		serverMiddlewares = append(serverMiddlewares, concurrencyLimiter)
+		serverInternalMiddlewares = append(serverInternalMiddlewares, concurrencyLimiter)
+	}
+	if maxRequestBodyBytes := s.globalConfiguration.EntryPoints[newServerEntryPointName].MaxRequestBodyBytes; maxRequestBodyBytes > 0 {
+		bodyLimiter := middlewares.NewBodyLimiter(maxRequestBodyBytes)
+		serverMiddlewares = append(serverMiddlewares, bodyLimiter)
+		serverInternalMiddlewares = append(serverInternalMiddlewares, bodyLimiter)
+	}
+	if s.globalConfiguration.EntryPoints[newServerEntryPointName].HeaderNormalization != nil {
+		headerNormalizer := middlewares.NewHeaderNormalizer(s.globalConfiguration.EntryPoints[newServerEntryPointName].HeaderNormalization)
+		serverMiddlewares = append(serverMiddlewares, headerNormalizer)
+		serverInternalMiddlewares = append(serverInternalMiddlewares, headerNormalizer)
+	}
+	var connectionRecycler *middlewares.ConnectionRecycler
+	if keepAlive := s.globalConfiguration.EntryPoints[newServerEntryPointName].KeepAlive; keepAlive != nil && (keepAlive.MaxRequests > 0 || keepAlive.MaxAge > 0) {
+		connectionRecycler = middlewares.NewConnectionRecycler(keepAlive.MaxRequests, time.Duration(keepAlive.MaxAge))
+		serverMiddlewares = append(serverMiddlewares, connectionRecycler)
+		serverInternalMiddlewares = append(serverInternalMiddlewares, connectionRecycler)
+	}
 	if s.globalConfiguration.EntryPoints[newServerEntryPointName].Auth != nil {
 		authMiddleware, err := mauth.NewAuthenticator(s.globalConfiguration.EntryPoints[newServerEntryPointName].Auth, s.tracingMiddleware)
 		if err != nil {
-			log.Fatal("Error starting server: ", err)
+			return nil, err
 		}
 		serverMiddlewares = append(serverMiddlewares, s.wrapNegroniHandlerWithAccessLog(authMiddleware, fmt.Sprintf("Auth for entrypoint %s", newServerEntryPointName)))
 		serverInternalMiddlewares = append(serverInternalMiddlewares, authMiddleware)
 	}
 	if s.globalConfiguration.EntryPoints[newServerEntryPointName].Compress {
-		serverMiddlewares = append(serverMiddlewares, &middlewares.Compress{})
+		compressMiddleware, err := middlewares.NewCompress(s.globalConfiguration.EntryPoints[newServerEntryPointName].Compression)
+		if err != nil {
+			return nil, err
+		}
+		serverMiddlewares = append(serverMiddlewares, compressMiddleware)
 	}
 	if len(s.globalConfiguration.EntryPoints[newServerEntryPointName].WhitelistSourceRange) > 0 {
-		ipWhitelistMiddleware, err := middlewares.NewIPWhitelister(s.globalConfiguration.EntryPoints[newServerEntryPointName].WhitelistSourceRange)
+		ipWhitelistMiddleware, err := middlewares.NewIPWhitelister(s.globalConfiguration.EntryPoints[newServerEntryPointName].WhitelistSourceRange, s.globalConfiguration.EntryPoints[newServerEntryPointName].WhitelistIPStrategy)
 		if err != nil {
-			log.Fatal("Error starting server: ", err)
+			return nil, err
 		}
 		serverMiddlewares = append(serverMiddlewares, s.wrapNegroniHandlerWithAccessLog(ipWhitelistMiddleware, fmt.Sprintf("ipwhitelister for entrypoint %s", newServerEntryPointName)))
 		serverInternalMiddlewares = append(serverInternalMiddlewares, ipWhitelistMiddleware)
 	}
-	newSrv, listener, err := s.prepareServer(newServerEntryPointName, s.globalConfiguration.EntryPoints[newServerEntryPointName], newServerEntryPoint.httpRouter, serverMiddlewares, serverInternalMiddlewares)
+	newSrv, listener, err := s.prepareServer(newServerEntryPointName, s.globalConfiguration.EntryPoints[newServerEntryPointName], newServerEntryPoint, serverMiddlewares, serverInternalMiddlewares)
 	if err != nil {
-		log.Fatal("Error preparing server: ", err)
+		return nil, err
+	}
+	if connectionRecycler != nil {
+		newSrv.ConnState = connectionRecycler.ConnState
 	}
-	serverEntryPoint := s.serverEntryPoints[newServerEntryPointName]
-	serverEntryPoint.httpServer = newSrv
-	serverEntryPoint.listener = listener
+	newServerEntryPoint.serve(newSrv, listener, s.globalConfiguration.EntryPoints[newServerEntryPointName].Protocol == configuration.EntryPointProtocolTCP)
 
-	return serverEntryPoint
+	return newServerEntryPoint, nil
+}
+
+// serve sets the HTTP server of the entry point, serving the connections of the listener
+// unless they are forwarded to the TCP or passthrough frontends.
+func (sep *serverEntryPoint) serve(httpServer *http.Server, listener net.Listener, tcp bool) {
+	if tcp {
+		// the connections are forwarded as is to the frontends, the HTTP server only manages the listener
+		sep.tcpListener = newTCPListener(listener, &sep.tcp, httpServer.TLSConfig, httpServer.ReadTimeout)
+		listener = sep.tcpListener
+	} else if httpServer.TLSConfig != nil {
+		// TLS connections are routed on their ClientHello, either to the HTTP server or to a passthrough frontend
+		listener = newSNIListener(listener, &sep.passthrough, httpServer.ReadTimeout)
+	}
+	sep.httpServer = httpServer
+	sep.tlsConfig = httpServer.TLSConfig
+	sep.listener = listener
 }
 
 func (s *Server) listenProviders(stop chan bool) {
@@ -360,6 +724,7 @@ func (s *Server) preLoadConfiguration(configMsg types.ConfigMessage) {
 		log.Infof("Skipping empty Configuration for provider %s", configMsg.ProviderName)
 	} else if reflect.DeepEqual(currentConfigurations[configMsg.ProviderName], configMsg.Configuration) {
 		log.Infof("Skipping same configuration for provider %s", configMsg.ProviderName)
+		status.RecordSync(configMsg.ProviderName, configMsg.Configuration)
 	} else {
 		providerConfigUpdateCh, ok := s.providerConfigUpdateMap[configMsg.ProviderName]
 		if !ok {
@@ -421,6 +786,8 @@ func (s *Server) listenConfigurations(stop chan bool) {
 				return
 			}
 			s.loadConfiguration(configMsg)
+		case globalConfiguration := <-s.staticConfigurationChan:
+			s.applyStaticConfiguration(globalConfiguration)
 		}
 	}
 }
@@ -437,26 +804,65 @@ func (s *Server) loadConfiguration(configMsg types.ConfigMessage) {
 	newConfigurations[configMsg.ProviderName] = configMsg.Configuration
 
 	s.metricsRegistry.ConfigReloadsCounter().Add(1)
-	newServerEntryPoints, err := s.loadConfig(newConfigurations, s.globalConfiguration)
+	resolvedConfigurations, conflicts, err := resolveProviderConflicts(newConfigurations, s.globalConfiguration.ProvidersMerge)
+	s.providerConflicts.Set(conflicts)
+	var newServerEntryPoints map[string]*serverEntryPoint
+	if err == nil {
+		newServerEntryPoints, err = s.loadConfig(resolvedConfigurations, s.globalConfiguration)
+	}
 	if err == nil {
 		s.metricsRegistry.LastConfigReloadSuccessGauge().Set(float64(time.Now().Unix()))
-		for newServerEntryPointName, newServerEntryPoint := range newServerEntryPoints {
-			s.serverEntryPoints[newServerEntryPointName].httpRouter.UpdateHandler(newServerEntryPoint.httpRouter.GetHandler())
-			if s.globalConfiguration.EntryPoints[newServerEntryPointName].TLS == nil {
-				if newServerEntryPoint.certs.Get() != nil {
-					log.Debugf("Certificates not added to non-TLS entryPoint %s.", newServerEntryPointName)
-				}
-			} else {
-				s.serverEntryPoints[newServerEntryPointName].certs.Set(newServerEntryPoint.certs.Get())
-			}
-			log.Infof("Server configuration reloaded on %s", s.serverEntryPoints[newServerEntryPointName].httpServer.Addr)
-		}
+		s.updateServerEntryPoints(newServerEntryPoints)
 		s.currentConfigurations.Set(newConfigurations)
+		status.RecordSync(configMsg.ProviderName, configMsg.Configuration)
 		s.postLoadConfiguration()
+		audit.Record(audit.Event{
+			Type:     audit.ConfigurationApplied,
+			Provider: configMsg.ProviderName,
+			Digest:   audit.Digest(configMsg.Configuration),
+		})
 	} else {
 		s.metricsRegistry.ConfigReloadsFailureCounter().Add(1)
 		s.metricsRegistry.LastConfigReloadFailureGauge().Set(float64(time.Now().Unix()))
 		log.Error("Error loading new configuration, aborted ", err)
+		status.RecordError(configMsg.ProviderName, err.Error(), false)
+		audit.Record(audit.Event{
+			Type:     audit.ConfigurationRejected,
+			Provider: configMsg.ProviderName,
+			Digest:   audit.Digest(configMsg.Configuration),
+			Error:    err.Error(),
+		})
+		notification.Notify(notification.Event{
+			Type:     notification.ConfigurationError,
+			Message:  "Error loading new configuration, aborted",
+			Provider: configMsg.ProviderName,
+			Error:    err.Error(),
+		})
+	}
+}
+
+// updateServerEntryPoints switches the routing of the running entry points to the loaded one.
+func (s *Server) updateServerEntryPoints(newServerEntryPoints map[string]*serverEntryPoint) {
+	for newServerEntryPointName, newServerEntryPoint := range newServerEntryPoints {
+		serverEntryPoint, ok := s.serverEntryPoints[newServerEntryPointName]
+		if !ok {
+			// the entry point couldn't be restarted on the static configuration reload
+			continue
+		}
+		serverEntryPoint.httpRouter.UpdateHandler(newServerEntryPoint.httpRouter.GetHandler())
+		if s.globalConfiguration.EntryPoints[newServerEntryPointName].TLS == nil {
+			if newServerEntryPoint.certs.Get() != nil {
+				log.Debugf("Certificates not added to non-TLS entryPoint %s.", newServerEntryPointName)
+			}
+		} else {
+			serverEntryPoint.certs.Set(newServerEntryPoint.certs.Get())
+			serverEntryPoint.passthrough.Set(newServerEntryPoint.passthrough.Get())
+			serverEntryPoint.clientCAs.Set(newServerEntryPoint.clientCAs.Get())
+			serverEntryPoint.tlsOptions.Set(newServerEntryPoint.tlsOptions.Get())
+		}
+		serverEntryPoint.tcp.Set(newServerEntryPoint.tcp.Get())
+		serverEntryPoint.udp.Set(newServerEntryPoint.udp.Get())
+		log.Infof("Server configuration reloaded on %s", s.globalConfiguration.EntryPoints[newServerEntryPointName].Address)
 	}
 }
 
@@ -495,36 +901,46 @@ func (s *serverEntryPoint) getCertificate(clientHello *tls.ClientHelloInfo) (*tl
 func (s *Server) postLoadConfiguration() {
 	metrics.OnConfigurationUpdate()
 
-	if s.globalConfiguration.ACME == nil {
+	s.loadVaultPKIDomains()
+
+	if len(s.acmeConfigurations()) == 0 {
 		return
 	}
 	if s.leadership != nil && !s.leadership.IsLeader() {
 		return
 	}
-	if s.globalConfiguration.ACME.OnHostRule {
-		currentConfigurations := s.currentConfigurations.Get().(types.Configurations)
-		for _, config := range currentConfigurations {
-			for _, frontend := range config.Frontends {
-
-				// check if one of the frontend entrypoints is configured with TLS
-				// and is configured with ACME
-				acmeEnabled := false
-				for _, entryPoint := range frontend.EntryPoints {
-					if s.globalConfiguration.ACME.EntryPoint == entryPoint && s.globalConfiguration.EntryPoints[entryPoint].TLS != nil {
-						acmeEnabled = true
-						break
-					}
+	currentConfigurations := s.currentConfigurations.Get().(types.Configurations)
+	for _, config := range currentConfigurations {
+		for frontendName, frontend := range config.Frontends {
+			acmeConfig := s.acmeResolver(frontend.ACMEResolver)
+			if acmeConfig == nil {
+				if len(frontend.ACMEResolver) > 0 {
+					log.Errorf("Unknown ACME resolver %q for frontend %s", frontend.ACMEResolver, frontendName)
 				}
+				continue
+			}
+			if !acmeConfig.OnHostRule {
+				continue
+			}
 
-				if acmeEnabled {
-					for _, route := range frontend.Routes {
-						rules := rules.Rules{}
-						domains, err := rules.ParseDomains(route.Rule)
-						if err != nil {
-							log.Errorf("Error parsing domains: %v", err)
-						} else {
-							s.globalConfiguration.ACME.LoadCertificateForDomains(domains)
-						}
+			// check if one of the frontend entrypoints is configured with TLS
+			// and is configured with the ACME configuration of the frontend
+			acmeEnabled := false
+			for _, entryPoint := range frontend.EntryPoints {
+				if acmeConfig.EntryPoint == entryPoint && s.globalConfiguration.EntryPoints[entryPoint].TLS != nil {
+					acmeEnabled = true
+					break
+				}
+			}
+
+			if acmeEnabled {
+				for _, route := range frontend.Routes {
+					rules := rules.Rules{}
+					domains, err := rules.ParseDomains(route.Rule)
+					if err != nil {
+						log.Errorf("Error parsing domains: %v", err)
+					} else {
+						acmeConfig.LoadCertificateForDomains(domains)
 					}
 				}
 			}
@@ -532,6 +948,63 @@ func (s *Server) postLoadConfiguration() {
 	}
 }
 
+// loadVaultPKIDomains issues the certificates of the Host rules of the frontends of the Vault PKI entry point.
+func (s *Server) loadVaultPKIDomains() {
+	if s.vaultPKI == nil || !s.globalConfiguration.VaultPKI.OnHostRule {
+		return
+	}
+
+	currentConfigurations := s.currentConfigurations.Get().(types.Configurations)
+	for _, config := range currentConfigurations {
+		for _, frontend := range config.Frontends {
+			vaultEnabled := false
+			for _, entryPoint := range frontend.EntryPoints {
+				if entryPoint == s.globalConfiguration.VaultPKI.EntryPoint {
+					vaultEnabled = !frontend.TLSPassthrough
+					break
+				}
+			}
+			if !vaultEnabled {
+				continue
+			}
+
+			for _, route := range frontend.Routes {
+				domains, err := (&rules.Rules{}).ParseDomains(route.Rule)
+				if err != nil {
+					log.Errorf("Error parsing domains: %v", err)
+					continue
+				}
+				s.vaultPKI.AddDomains(domains)
+			}
+		}
+	}
+}
+
+// acmeConfigurations returns the default ACME configuration, if any, followed by the ones of the ACME resolvers.
+func (s *Server) acmeConfigurations() []*acme.ACME {
+	var acmeConfigs []*acme.ACME
+	if s.globalConfiguration.ACME != nil {
+		acmeConfigs = append(acmeConfigs, s.globalConfiguration.ACME)
+	}
+	for _, resolver := range s.globalConfiguration.ACMEResolvers {
+		acmeConfigs = append(acmeConfigs, &resolver.ACME)
+	}
+	return acmeConfigs
+}
+
+// acmeResolver returns the ACME configuration of the named resolver, or the default one when the name is empty.
+func (s *Server) acmeResolver(name string) *acme.ACME {
+	if len(name) == 0 {
+		return s.globalConfiguration.ACME
+	}
+	for _, resolver := range s.globalConfiguration.ACMEResolvers {
+		if resolver.Name == name {
+			return &resolver.ACME
+		}
+	}
+	return nil
+}
+
 func (s *Server) startProvider() {
 	// start providers
 	providerType := reflect.TypeOf(s.provider)
@@ -540,9 +1013,12 @@ func (s *Server) startProvider() {
 		log.Debugf("Unable to marshal provider conf %v with error: %v", providerType, err)
 	}
 	log.Infof("Starting provider %v %s", providerType, jsonConf)
+	s.providerConfiguration = string(jsonConf)
 	currentProvider := s.provider
+	providersPool := s.providersPool
+	constraints := s.globalConfiguration.Constraints
 	safe.Go(func() {
-		err := currentProvider.Provide(s.configurationChan, s.routinesPool, s.globalConfiguration.Constraints)
+		err := currentProvider.Provide(s.configurationChan, providersPool, constraints)
 		if err != nil {
 			log.Errorf("Error starting provider %v: %s", providerType, err)
 		}
@@ -565,15 +1041,9 @@ func createClientTLSConfig(entryPointName string, tlsOption *traefikTls.TLS) (*t
 		tlsOption.ClientCA.Optional = false
 	}
 	if len(tlsOption.ClientCA.Files) > 0 {
-		pool := x509.NewCertPool()
-		for _, caFile := range tlsOption.ClientCA.Files {
-			data, err := ioutil.ReadFile(caFile)
-			if err != nil {
-				return nil, err
-			}
-			if !pool.AppendCertsFromPEM(data) {
-				return nil, errors.New("invalid certificate(s) in " + caFile)
-			}
+		pool, err := loadClientCAPool(tlsOption.ClientCA.Files)
+		if err != nil {
+			return nil, err
 		}
 		config.RootCAs = pool
 	}
@@ -582,7 +1052,8 @@ func createClientTLSConfig(entryPointName string, tlsOption *traefikTls.TLS) (*t
 }
 
 // creates a TLS config that allows terminating HTTPS for multiple domains using SNI
-func (s *Server) createTLSConfig(entryPointName string, tlsOption *traefikTls.TLS, router *middlewares.HandlerSwitcher) (*tls.Config, error) {
+// The routines watching the certificates and renewing them are run by the pool of the entry point.
+func (s *Server) createTLSConfig(entryPointName string, tlsOption *traefikTls.TLS, serverEntryPoint *serverEntryPoint) (*tls.Config, error) {
 	if tlsOption == nil {
 		return nil, nil
 	}
@@ -591,15 +1062,21 @@ func (s *Server) createTLSConfig(entryPointName string, tlsOption *traefikTls.TL
 	if err != nil {
 		return nil, err
 	}
+	if err := tlsOption.ApplyDefaultCertificate(config); err != nil {
+		return nil, err
+	}
 	epDomainsCertificatesTmp := new(traefikTls.DomainsCertificates)
 	if epDomainsCertificates[entryPointName] != nil {
 		epDomainsCertificatesTmp = epDomainsCertificates[entryPointName]
 	} else {
 		*epDomainsCertificatesTmp = make(map[string]*tls.Certificate)
 	}
-	s.serverEntryPoints[entryPointName].certs.Set(epDomainsCertificatesTmp)
-	// ensure http2 enabled
+	serverEntryPoint.certs.Set(epDomainsCertificatesTmp)
+	// ensure http2 enabled, unless disabled on the entrypoint
 	config.NextProtos = []string{"h2", "http/1.1"}
+	if http2Options := s.globalConfiguration.EntryPoints[entryPointName].HTTP2; http2Options != nil && http2Options.Disabled {
+		config.NextProtos = []string{"http/1.1"}
+	}
 
 	if len(tlsOption.ClientCAFiles) > 0 {
 		log.Warnf("Deprecated configuration found during TLS configuration creation: %s. Please use %s (which allows to make the CA Files optional).", "tls.ClientCAFiles", "tls.ClientCA.files")
@@ -607,16 +1084,9 @@ func (s *Server) createTLSConfig(entryPointName string, tlsOption *traefikTls.TL
 		tlsOption.ClientCA.Optional = false
 	}
 	if len(tlsOption.ClientCA.Files) > 0 {
-		pool := x509.NewCertPool()
-		for _, caFile := range tlsOption.ClientCA.Files {
-			data, err := ioutil.ReadFile(caFile)
-			if err != nil {
-				return nil, err
-			}
-			ok := pool.AppendCertsFromPEM(data)
-			if !ok {
-				return nil, errors.New("invalid certificate(s) in " + caFile)
-			}
+		pool, err := loadClientCAPool(tlsOption.ClientCA.Files)
+		if err != nil {
+			return nil, err
 		}
 		config.ClientCAs = pool
 		if tlsOption.ClientCA.Optional {
@@ -624,33 +1094,68 @@ func (s *Server) createTLSConfig(entryPointName string, tlsOption *traefikTls.TL
 		} else {
 			config.ClientAuth = tls.RequireAndVerifyClientCert
 		}
+		if len(tlsOption.ClientCA.CRLFiles) > 0 || tlsOption.ClientCA.OCSP {
+			if err := s.checkClientCertificatesRevocation(entryPointName, tlsOption.ClientCA, config, serverEntryPoint.routinesPool); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	if s.globalConfiguration.ACME != nil {
-		if entryPointName == s.globalConfiguration.ACME.EntryPoint {
-			checkOnDemandDomain := func(domain string) bool {
-				routeMatch := &mux.RouteMatch{}
-				router := router.GetHandler()
-				match := router.Match(&http.Request{URL: &url.URL{}, Host: domain}, routeMatch)
-				if match && routeMatch.Route != nil {
-					return true
-				}
-				return false
+	// Set the TLS versions, cipher suites and curves if set in the config TOML
+	if err := tlsOption.Options().Apply(config); err != nil {
+		return nil, err
+	}
+
+	if acmeConfigs := s.acmeConfigurations(); len(acmeConfigs) > 0 {
+		checkOnDemandDomain := func(domain string) bool {
+			routeMatch := &mux.RouteMatch{}
+			router := serverEntryPoint.httpRouter.GetHandler()
+			match := router.Match(&http.Request{URL: &url.URL{}, Host: domain}, routeMatch)
+			if match && routeMatch.Route != nil {
+				return true
+			}
+			return false
+		}
+		// the ACME configurations sharing the entry point chain their certificates, the last one being tried first
+		for _, acmeConfig := range acmeConfigs {
+			if entryPointName != acmeConfig.EntryPoint {
+				continue
 			}
 			if s.leadership == nil {
-				err := s.globalConfiguration.ACME.CreateLocalConfig(config, &s.serverEntryPoints[entryPointName].certs, checkOnDemandDomain)
+				err := acmeConfig.CreateLocalConfig(serverEntryPoint.routinesPool.Ctx(), config, &serverEntryPoint.certs, checkOnDemandDomain)
 				if err != nil {
 					return nil, err
 				}
 			} else {
-				err := s.globalConfiguration.ACME.CreateClusterConfig(s.leadership, config, &s.serverEntryPoints[entryPointName].certs, checkOnDemandDomain)
+				err := acmeConfig.CreateClusterConfig(serverEntryPoint.routinesPool.Ctx(), s.leadership, config, &serverEntryPoint.certs, checkOnDemandDomain)
 				if err != nil {
 					return nil, err
 				}
 			}
 		}
 	} else {
-		config.GetCertificate = s.serverEntryPoints[entryPointName].getCertificate
+		config.GetCertificate = serverEntryPoint.getCertificate
+	}
+	if s.vaultPKI != nil && s.globalConfiguration.VaultPKI.EntryPoint == entryPointName {
+		config.GetCertificate = s.vaultPKI.GetCertificate(config.GetCertificate)
+	}
+	var certificatesReloader *staticCertificatesReloader
+	if reloader := newStaticCertificatesReloader(entryPointName, tlsOption.StaticCertificates(), config.GetCertificate); len(reloader.files()) > 0 {
+		if err := reloader.watch(serverEntryPoint.routinesPool); err != nil {
+			log.Errorf("Error watching the certificates of entrypoint %s, they won't be reloaded: %v", entryPointName, err)
+		} else {
+			certificatesReloader = reloader
+			config.GetCertificate = certificatesReloader.getCertificate
+			serverEntryPoint.certificatesReloader.Set(certificatesReloader)
+		}
+	}
+	if tlsOption.SNIStrict {
+		config.GetCertificate = traefikTls.StrictSNI(config, config.GetCertificate)
+	} else if certificatesReloader != nil {
+		config.GetCertificate = certificatesReloader.getDefaultCertificate(config.GetCertificate)
+	}
+	if s.ocspStapler != nil {
+		config.GetCertificate = s.ocspStapler.GetCertificate(config, config.GetCertificate)
 	}
 	if len(config.Certificates) == 0 {
 		return nil, errors.New("No certificates found for TLS entrypoint " + entryPointName)
@@ -658,38 +1163,50 @@ func (s *Server) createTLSConfig(entryPointName string, tlsOption *traefikTls.TL
 	// BuildNameToCertificate parses the CommonName and SubjectAlternateName fields
 	// in each certificate and populates the config.NameToCertificate map.
 	config.BuildNameToCertificate()
-	//Set the minimum TLS version if set in the config TOML
-	if minConst, exists := traefikTls.MinVersion[s.globalConfiguration.EntryPoints[entryPointName].TLS.MinVersion]; exists {
-		config.PreferServerCipherSuites = true
-		config.MinVersion = minConst
-	}
-	//Set the list of CipherSuites if set in the config TOML
-	if s.globalConfiguration.EntryPoints[entryPointName].TLS.CipherSuites != nil {
-		//if our list of CipherSuites is defined in the entrypoint config, we can re-initilize the suites list as empty
-		config.CipherSuites = make([]uint16, 0)
-		for _, cipher := range s.globalConfiguration.EntryPoints[entryPointName].TLS.CipherSuites {
-			if cipherConst, exists := traefikTls.CipherSuites[cipher]; exists {
-				config.CipherSuites = append(config.CipherSuites, cipherConst)
-			} else {
-				//CipherSuite listed in the toml does not exist in our listed
-				return nil, errors.New("Invalid CipherSuite: " + cipher)
-			}
+
+	if config.ClientCAs != nil {
+		reloader := newClientCAReloader(entryPointName, tlsOption.ClientCA.Files, config)
+		if err := reloader.watch(serverEntryPoint.routinesPool); err != nil {
+			log.Errorf("Error watching the client CA files of entrypoint %s, they won't be reloaded: %v", entryPointName, err)
+		} else {
+			config.GetConfigForClient = reloader.getConfigForClient
 		}
 	}
+	// the frontends with their own TLS options or client CA apply them to the handshakes of their server names
+	config.GetConfigForClient = serverEntryPoint.getConfigForClient(config, config.GetConfigForClient)
+	if s.sessionTicketKeys != nil {
+		s.sessionTicketKeys.apply(config)
+	}
 	return config, nil
 }
 
+// startServer serves the entry point in the background with the server and listener it has when called,
+// the HTTP server of the entry point being replaced when its timeouts are reloaded.
 func (s *Server) startServer(serverEntryPoint *serverEntryPoint, globalConfiguration configuration.GlobalConfiguration) {
-	log.Infof("Starting server on %s", serverEntryPoint.httpServer.Addr)
-	var err error
-	if serverEntryPoint.httpServer.TLSConfig != nil {
-		err = serverEntryPoint.httpServer.ServeTLS(serverEntryPoint.listener, "", "")
-	} else {
-		err = serverEntryPoint.httpServer.Serve(serverEntryPoint.listener)
-	}
-	if err != http.ErrServerClosed {
-		log.Error("Error creating server: ", err)
+	if udpForwarder := serverEntryPoint.udpForwarder; udpForwarder != nil {
+		go func() {
+			log.Infof("Starting UDP server on %s", udpForwarder.conn.LocalAddr())
+			if err := udpForwarder.serve(); err != nil {
+				log.Error("Error creating server: ", err)
+			}
+		}()
+		return
 	}
+
+	httpServer, listener := serverEntryPoint.httpServer, serverEntryPoint.listener
+	serveTLS := httpServer.TLSConfig != nil && serverEntryPoint.tcpListener == nil
+	go func() {
+		log.Infof("Starting server on %s", httpServer.Addr)
+		var err error
+		if serveTLS {
+			err = httpServer.ServeTLS(listener, "", "")
+		} else {
+			err = httpServer.Serve(listener)
+		}
+		if err != http.ErrServerClosed {
+			log.Error("Error creating server: ", err)
+		}
+	}()
 }
 
 func (s *Server) addInternalRoutes(entryPointName string, router *mux.Router) {
@@ -713,13 +1230,21 @@ func (s *Server) addInternalPublicRoutes(entryPointName string, router *mux.Rout
 }
 
 func (s *Server) addACMERoutes(entryPointName string, router *mux.Router) {
-	if s.globalConfiguration.ACME != nil && s.globalConfiguration.ACME.HTTPChallenge != nil && s.globalConfiguration.ACME.HTTPChallenge.EntryPoint == entryPointName {
-		s.globalConfiguration.ACME.AddRoutes(router)
+	var acmeConfigs []*acme.ACME
+	for _, acmeConfig := range s.acmeConfigurations() {
+		if acmeConfig.HTTPChallenge != nil && acmeConfig.HTTPChallenge.EntryPoint == entryPointName {
+			acmeConfigs = append(acmeConfigs, acmeConfig)
+		}
+	}
+	if len(acmeConfigs) > 0 {
+		acme.AddChallengeRoutes(router, acmeConfigs...)
 	}
 }
 
-func (s *Server) prepareServer(entryPointName string, entryPoint *configuration.EntryPoint, router *middlewares.HandlerSwitcher, middlewares []negroni.Handler, internalMiddlewares []negroni.Handler) (*http.Server, net.Listener, error) {
-	readTimeout, writeTimeout, idleTimeout := buildServerTimeouts(s.globalConfiguration)
+// prepareServer builds the HTTP server of the entry point, opening its listeners once its options are validated
+// and closing them when its TLS configuration can't be created.
+func (s *Server) prepareServer(entryPointName string, entryPoint *configuration.EntryPoint, serverEntryPoint *serverEntryPoint, middlewares []negroni.Handler, internalMiddlewares []negroni.Handler) (*http.Server, net.Listener, error) {
+	readTimeout, writeTimeout, idleTimeout := buildServerTimeouts(s.globalConfiguration, entryPoint)
 	log.Infof("Preparing server %s %+v with readTimeout=%s writeTimeout=%s idleTimeout=%s", entryPointName, entryPoint, readTimeout, writeTimeout, idleTimeout)
 
 	// middlewares
@@ -727,7 +1252,7 @@ func (s *Server) prepareServer(entryPointName string, entryPoint *configuration.
 	for _, middleware := range middlewares {
 		n.Use(middleware)
 	}
-	n.UseHandler(router)
+	n.UseHandler(serverEntryPoint.httpRouter)
 
 	path := "/"
 	if s.globalConfiguration.Web != nil && s.globalConfiguration.Web.Path != "" {
@@ -737,27 +1262,111 @@ func (s *Server) prepareServer(entryPointName string, entryPoint *configuration.
 	internalMuxRouter := s.buildInternalRouter(entryPointName, path, internalMiddlewares)
 	internalMuxRouter.NotFoundHandler = n
 
-	tlsConfig, err := s.createTLSConfig(entryPointName, entryPoint.TLS, router)
+	http2Server, err := buildHTTP2Config(entryPoint.HTTP2)
 	if err != nil {
-		log.Errorf("Error creating TLS config: %s", err)
-		return nil, nil, err
+		return nil, nil, fmt.Errorf("invalid HTTP/2 configuration: %v", err)
 	}
 
-	listener, err := net.Listen("tcp", entryPoint.Address)
+	sockets, err := listen(entryPointName, entryPoint, serverEntryPoint.sockets)
 	if err != nil {
 		log.Error("Error opening listener ", err)
 		return nil, nil, err
 	}
+	serverEntryPoint.sockets = nil
+
+	listener, err := s.wrapListeners(entryPointName, entryPoint, sockets.listeners)
+	if err != nil {
+		sockets.close()
+		return nil, nil, err
+	}
+
+	tlsConfig, err := s.createTLSConfig(entryPointName, entryPoint.TLS, serverEntryPoint)
+	if err != nil {
+		log.Errorf("Error creating TLS config: %s", err)
+		sockets.close()
+		return nil, nil, err
+	}
+	serverEntryPoint.sockets = sockets
+
+	var maxHeaderBytes int
+	if entryPoint.RequestLimits != nil {
+		maxHeaderBytes = entryPoint.RequestLimits.MaxHeaderBytes
+	}
+
+	httpServer := &http.Server{
+		Addr:           entryPoint.Address,
+		Handler:        internalMuxRouter,
+		TLSConfig:      tlsConfig,
+		ReadTimeout:    readTimeout,
+		WriteTimeout:   writeTimeout,
+		IdleTimeout:    idleTimeout,
+		MaxHeaderBytes: maxHeaderBytes,
+		ErrorLog:       httpServerLogger,
+	}
+
+	if err := configureHTTP2(httpServer, entryPoint, http2Server); err != nil {
+		sockets.close()
+		return nil, nil, fmt.Errorf("invalid HTTP/2 configuration: %v", err)
+	}
+	serverEntryPoint.handler = internalMuxRouter
+
+	return httpServer, listener, nil
+}
+
+// configureHTTP2 sets up HTTP/2 on the HTTP server of an entry point, according to its HTTP/2 options:
+// disabled, served in cleartext, or negotiated on the TLS connections.
+func configureHTTP2(httpServer *http.Server, entryPoint *configuration.EntryPoint, http2Server *http2.Server) error {
+	switch {
+	case entryPoint.HTTP2 != nil && entryPoint.HTTP2.Disabled:
+		// a non-nil empty map keeps the server from negotiating HTTP/2 on its TLS connections
+		httpServer.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	case entryPoint.HTTP2 != nil && entryPoint.HTTP2.Cleartext && httpServer.TLSConfig == nil:
+		if http2Server.IdleTimeout == 0 {
+			http2Server.IdleTimeout = httpServer.IdleTimeout
+		}
+		httpServer.Handler = newH2CHandler(httpServer.Handler, http2Server)
+	case httpServer.TLSConfig != nil:
+		return http2.ConfigureServer(httpServer, http2Server)
+	}
+	return nil
+}
+
+// wrapListeners returns the listener accepting the connections of the listeners of an entry point,
+// with their keep-alive period, limit and PROXY protocol header.
+func (s *Server) wrapListeners(entryPointName string, entryPoint *configuration.EntryPoint, sockets []net.Listener) (net.Listener, error) {
+	listeners := make([]net.Listener, len(sockets))
+	for i, listener := range sockets {
+		listeners[i] = listener
+		if tcpListener, ok := listener.(*net.TCPListener); ok && entryPoint.KeepAlive != nil && entryPoint.KeepAlive.TCPPeriod != 0 {
+			listeners[i] = &tcpKeepAliveListener{
+				TCPListener: tcpListener,
+				period:      time.Duration(entryPoint.KeepAlive.TCPPeriod),
+			}
+		}
+	}
+
+	listener := listeners[0]
+	if len(listeners) > 1 {
+		listener = newMultiListener(listeners)
+	}
+
+	if entryPoint.MaxConnections > 0 || s.metricsRegistry.IsEnabled() {
+		listener = newConnectionLimitListener(listener, entryPointName, entryPoint.MaxConnections, s.metricsRegistry.EntrypointClientConnsGauge())
+	}
 
 	if entryPoint.ProxyProtocol != nil {
 		IPs, err := whitelist.NewIP(entryPoint.ProxyProtocol.TrustedIPs, entryPoint.ProxyProtocol.Insecure)
 		if err != nil {
-			return nil, nil, fmt.Errorf("error creating whitelist: %s", err)
+			return nil, fmt.Errorf("error creating whitelist: %s", err)
 		}
 		log.Infof("Enabling ProxyProtocol for trusted IPs %v", entryPoint.ProxyProtocol.TrustedIPs)
 		listener = &proxyproto.Listener{
 			Listener: listener,
 			SourceCheck: func(addr net.Addr) (bool, error) {
+				if _, ok := addr.(unixClientAddr); ok {
+					allowed, _, err := IPs.Contains(whitelist.UnixSocket)
+					return allowed, err
+				}
 				ip, ok := addr.(*net.TCPAddr)
 				if !ok {
 					return false, fmt.Errorf("type error %v", addr)
@@ -767,17 +1376,62 @@ func (s *Server) prepareServer(entryPointName string, entryPoint *configuration.
 		}
 	}
 
-	return &http.Server{
-			Addr:         entryPoint.Address,
-			Handler:      internalMuxRouter,
-			TLSConfig:    tlsConfig,
-			ReadTimeout:  readTimeout,
-			WriteTimeout: writeTimeout,
-			IdleTimeout:  idleTimeout,
-			ErrorLog:     httpServerLogger,
-		},
-		listener,
-		nil
+	return listener, nil
+}
+
+// newH2CHandler returns the handler serving HTTP/2 cleartext to the connections starting with the HTTP/2 connection preface.
+// The requests to upgrade to h2c are answered over HTTP/1.1.
+func newH2CHandler(handler http.Handler, http2Server *http2.Server) http.Handler {
+	n := negroni.New(middlewares.NewH2CUpgradeIgnorer())
+	n.UseHandler(h2c.NewHandler(handler, http2Server))
+	return n
+}
+
+// buildHTTP2Config validates the HTTP/2 settings of an entry point, and returns the HTTP/2 server applying them.
+func buildHTTP2Config(config *configuration.HTTP2) (*http2.Server, error) {
+	if config == nil {
+		return &http2.Server{}, nil
+	}
+
+	if config.MaxConcurrentStreams < 0 {
+		return nil, fmt.Errorf("negative max concurrent streams %d", config.MaxConcurrentStreams)
+	}
+	for _, windowSize := range []int{config.InitialConnectionWindowSize, config.InitialStreamWindowSize} {
+		if windowSize != 0 && (windowSize < 1<<16-1 || windowSize > 1<<31-1) {
+			return nil, fmt.Errorf("initial window size %d out of the [65535, 2147483647] range", windowSize)
+		}
+	}
+	if config.MaxFrameSize != 0 && (config.MaxFrameSize < 1<<14 || config.MaxFrameSize > 1<<24-1) {
+		return nil, fmt.Errorf("max frame size %d out of the [16384, 16777215] range", config.MaxFrameSize)
+	}
+
+	return &http2.Server{
+		MaxConcurrentStreams:         uint32(config.MaxConcurrentStreams),
+		MaxUploadBufferPerConnection: int32(config.InitialConnectionWindowSize),
+		MaxUploadBufferPerStream:     int32(config.InitialStreamWindowSize),
+		MaxReadFrameSize:             uint32(config.MaxFrameSize),
+	}, nil
+}
+
+// tcpKeepAliveListener sets the TCP keep-alive period of the accepted connections,
+// a negative period disabling the keep-alive probes.
+type tcpKeepAliveListener struct {
+	*net.TCPListener
+	period time.Duration
+}
+
+func (l *tcpKeepAliveListener) Accept() (net.Conn, error) {
+	conn, err := l.AcceptTCP()
+	if err != nil {
+		return nil, err
+	}
+	if l.period < 0 {
+		conn.SetKeepAlive(false)
+		return conn, nil
+	}
+	conn.SetKeepAlive(true)
+	conn.SetKeepAlivePeriod(l.period)
+	return conn, nil
 }
 
 func (s *Server) buildInternalRouter(entryPointName, path string, internalMiddlewares []negroni.Handler) *mux.Router {
@@ -790,6 +1444,14 @@ func (s *Server) buildInternalRouter(entryPointName, path string, internalMiddle
 	internalMuxSubrouter.SkipClean(true)
 
 	s.addInternalRoutes(entryPointName, internalMuxSubrouter)
+
+	if s.globalConfiguration.API != nil && s.globalConfiguration.API.RBAC != nil {
+		if entryPoint, ok := s.globalConfiguration.EntryPoints[entryPointName]; ok && entryPoint.Auth == nil && (entryPoint.TLS == nil || len(entryPoint.TLS.ClientCA.Files) == 0) {
+			log.Warnf("RBAC identities are not authenticated on entry point %s: enable its authentication or client certificates", entryPointName)
+		}
+		rbacMiddleware := api.NewRBACMiddleware(s.globalConfiguration.API.RBAC, path)
+		internalMiddlewares = append(append([]negroni.Handler{}, internalMiddlewares...), rbacMiddleware)
+	}
 	internalMuxRouter.Walk(wrapRoute(internalMiddlewares))
 
 	s.addInternalPublicRoutes(entryPointName, internalMuxSubrouter)
@@ -808,7 +1470,9 @@ func wrapRoute(middlewares []negroni.Handler) func(*mux.Route, *mux.Router, []*m
 	}
 }
 
-func buildServerTimeouts(globalConfig configuration.GlobalConfiguration) (readTimeout, writeTimeout, idleTimeout time.Duration) {
+// buildServerTimeouts returns the timeouts of an entry point,
+// its own responding timeouts taking precedence over the global ones.
+func buildServerTimeouts(globalConfig configuration.GlobalConfiguration, entryPoint *configuration.EntryPoint) (readTimeout, writeTimeout, idleTimeout time.Duration) {
 	readTimeout = time.Duration(0)
 	writeTimeout = time.Duration(0)
 	if globalConfig.RespondingTimeouts != nil {
@@ -826,6 +1490,18 @@ func buildServerTimeouts(globalConfig configuration.GlobalConfiguration) (readTi
 		idleTimeout = configuration.DefaultIdleTimeout
 	}
 
+	if entryPoint != nil && entryPoint.RespondingTimeouts != nil {
+		if entryPoint.RespondingTimeouts.ReadTimeout != 0 {
+			readTimeout = time.Duration(entryPoint.RespondingTimeouts.ReadTimeout)
+		}
+		if entryPoint.RespondingTimeouts.WriteTimeout != 0 {
+			writeTimeout = time.Duration(entryPoint.RespondingTimeouts.WriteTimeout)
+		}
+		if entryPoint.RespondingTimeouts.IdleTimeout != 0 {
+			idleTimeout = time.Duration(entryPoint.RespondingTimeouts.IdleTimeout)
+		}
+	}
+
 	return readTimeout, writeTimeout, idleTimeout
 }
 
@@ -841,8 +1517,41 @@ func (s *Server) buildEntryPoints(globalConfiguration configuration.GlobalConfig
 }
 
 // getRoundTripper will either use server.defaultForwardingRoundTripper or create a new one
-// given a custom TLS configuration is passed and the passTLSCert option is set to true.
-func (s *Server) getRoundTripper(entryPointName string, globalConfiguration configuration.GlobalConfiguration, passTLSCert bool, tls *traefikTls.TLS) (http.RoundTripper, error) {
+// given a custom TLS configuration is passed and the passTLSCert option is set to true,
+// or given the forwarding timeouts or the outbound proxy are overridden by the backend or the frontend.
+// The overridden round trippers are added to the round trippers of the configuration being loaded.
+func (s *Server) getRoundTripper(entryPointName string, globalConfiguration configuration.GlobalConfiguration, passTLSCert bool, tls *traefikTls.TLS, timeouts *configuration.ForwardingTimeouts, backend *types.Backend, roundTrippers map[roundTripperKey]http.RoundTripper) (http.RoundTripper, error) {
+	key := roundTripperKey{}
+	overridden := false
+	if timeouts != nil && !reflect.DeepEqual(timeouts, globalConfiguration.ForwardingTimeouts) {
+		globalConfiguration.ForwardingTimeouts = timeouts
+		key.timeouts = *timeouts
+		overridden = true
+	}
+	var outboundProxy *types.OutboundProxy
+	if backend != nil && backend.Proxy != nil && len(backend.Proxy.URL) > 0 {
+		outboundProxy = backend.Proxy
+		key.proxyURL = outboundProxy.URL
+		overridden = true
+	}
+	if backend != nil && len(backend.DialPolicy) > 0 {
+		key.dialPolicy = backend.DialPolicy
+		overridden = true
+	}
+	if backend != nil && backend.ProxyProtocol != 0 {
+		if backend.ProxyProtocol < 0 || backend.ProxyProtocol > 2 {
+			return nil, fmt.Errorf("invalid PROXY protocol version %d", backend.ProxyProtocol)
+		}
+		key.proxyProtocol = backend.ProxyProtocol
+		overridden = true
+	}
+	var backendTLS *types.BackendTLS
+	if backend != nil && backend.TLS != nil {
+		backendTLS = backend.TLS
+		key.tls = backendTLSKey(backendTLS)
+		overridden = true
+	}
+
 	if passTLSCert {
 		tlsConfig, err := createClientTLSConfig(entryPointName, tls)
 		if err != nil {
@@ -852,20 +1561,207 @@ func (s *Server) getRoundTripper(entryPointName string, globalConfiguration conf
 
 		transport := createHTTPTransport(globalConfiguration)
 		transport.TLSClientConfig = tlsConfig
+		if err := configureTransport(transport, globalConfiguration, key.dialPolicy, key.proxyProtocol, backendTLS, outboundProxy); err != nil {
+			return nil, err
+		}
+		return transport, nil
+	}
+
+	if overridden {
+		if roundTripper, ok := roundTrippers[key]; ok {
+			return roundTripper, nil
+		}
+		// transports are shared between configuration reloads to keep their idle connections
+		if roundTripper, ok := s.forwardingRoundTrippers[key]; ok {
+			roundTrippers[key] = roundTripper
+			return roundTripper, nil
+		}
+		transport := createHTTPTransport(globalConfiguration)
+		if err := configureTransport(transport, globalConfiguration, key.dialPolicy, key.proxyProtocol, backendTLS, outboundProxy); err != nil {
+			return nil, err
+		}
+		roundTrippers[key] = transport
 		return transport, nil
 	}
 
 	return s.defaultForwardingRoundTripper, nil
 }
 
+// keepForwardingRoundTrippers replaces the round trippers of the previous configuration by the ones of the loaded configuration,
+// closing the idle connections of the round trippers which aren't used anymore.
+func (s *Server) keepForwardingRoundTrippers(roundTrippers map[roundTripperKey]http.RoundTripper) {
+	for key, roundTripper := range s.forwardingRoundTrippers {
+		if _, ok := roundTrippers[key]; !ok {
+			closeIdleConnections(roundTripper)
+		}
+	}
+	s.forwardingRoundTrippers = roundTrippers
+}
+
+// closeIdleConnections closes the idle connections of the round tripper, if it keeps any.
+func closeIdleConnections(roundTripper http.RoundTripper) {
+	if closer, ok := roundTripper.(interface {
+		CloseIdleConnections()
+	}); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// adaptiveLimiter returns the adaptive limiter of the backend of the previous configuration,
+// for the learnt limit to be kept across the configuration reloads, or a new one.
+func (s *Server) adaptiveLimiter(key adaptiveLimiterKey, backendName string) *middlewares.AdaptiveLimiter {
+	s.adaptiveLimitersLock.Lock()
+	defer s.adaptiveLimitersLock.Unlock()
+
+	if limiter, ok := s.adaptiveLimiters[key]; ok {
+		return limiter
+	}
+	config := key.config
+	return middlewares.NewAdaptiveLimiter(&config, backendName, s.metricsRegistry.BackendConcurrencyLimitGauge())
+}
+
+// configureTransport applies the dial policy, the PROXY protocol, the TLS configuration and the outbound proxy of a backend to its transport.
+func configureTransport(transport *http.Transport, globalConfiguration configuration.GlobalConfiguration, dialPolicy string, proxyProtocol int, backendTLS *types.BackendTLS, outboundProxy *types.OutboundProxy) error {
+	if backendTLS != nil {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		if err := configureBackendTLS(transport.TLSClientConfig, backendTLS); err != nil {
+			return err
+		}
+	}
+
+	if len(dialPolicy) > 0 {
+		dial, err := newPolicyDialer(createDialer(globalConfiguration), dialPolicy)
+		if err != nil {
+			return err
+		}
+		transport.DialContext = dial
+	}
+
+	if proxyProtocol > 0 {
+		if outboundProxy != nil {
+			return errors.New("the PROXY protocol header can't be sent through an outbound proxy")
+		}
+		transport.DialContext = proxyprotocol.ForwardingDialer(transport.DialContext, proxyProtocol)
+		// a connection announces a single client, so it is neither reused by nor multiplexed with other clients
+		transport.DisableKeepAlives = true
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+		if transport.TLSClientConfig != nil {
+			transport.TLSClientConfig = transport.TLSClientConfig.Clone()
+			transport.TLSClientConfig.NextProtos = nil
+		}
+	}
+
+	// the outbound proxy must be configured last, as a SOCKS proxy wraps the dialer
+	return outboundProxy.ConfigureTransport(transport)
+}
+
+// buildForwardingTimeouts computes the forwarding timeouts of a frontend:
+// each timeout set on the frontend overrides the one of the backend,
+// which itself overrides the global one.
+func buildForwardingTimeouts(globalTimeouts *configuration.ForwardingTimeouts, backend *types.Backend, frontend *types.Frontend) *configuration.ForwardingTimeouts {
+	var backendTimeouts, frontendTimeouts *types.ForwardingTimeouts
+	if backend != nil {
+		backendTimeouts = backend.ForwardingTimeouts
+	}
+	if frontend != nil {
+		frontendTimeouts = frontend.ForwardingTimeouts
+	}
+
+	if backendTimeouts == nil && frontendTimeouts == nil {
+		return globalTimeouts
+	}
+
+	timeouts := &configuration.ForwardingTimeouts{}
+	if globalTimeouts != nil {
+		*timeouts = *globalTimeouts
+	} else {
+		timeouts.DialTimeout = flaeg.Duration(configuration.DefaultDialTimeout)
+		timeouts.IdleConnTimeout = flaeg.Duration(configuration.DefaultIdleConnTimeout)
+	}
+
+	for _, override := range []*types.ForwardingTimeouts{backendTimeouts, frontendTimeouts} {
+		if override == nil {
+			continue
+		}
+		if override.DialTimeout > 0 {
+			timeouts.DialTimeout = override.DialTimeout
+		}
+		if override.ResponseHeaderTimeout > 0 {
+			timeouts.ResponseHeaderTimeout = override.ResponseHeaderTimeout
+		}
+		if override.IdleConnTimeout > 0 {
+			timeouts.IdleConnTimeout = override.IdleConnTimeout
+		}
+	}
+
+	return timeouts
+}
+
+// wirePassthroughFrontend adds the route of a TLS passthrough frontend to a TLS entry point.
+func (s *Server) wirePassthroughFrontend(serverEntryPoint *serverEntryPoint, entryPointName, frontendName string, frontend *types.Frontend, backend *types.Backend, globalConfiguration configuration.GlobalConfiguration) error {
+	if globalConfiguration.EntryPoints[entryPointName].TLS == nil {
+		return fmt.Errorf("entrypoint %s doesn't use TLS", entryPointName)
+	}
+
+	dialTimeout := configuration.DefaultDialTimeout
+	if timeouts := buildForwardingTimeouts(globalConfiguration.ForwardingTimeouts, backend, frontend); timeouts != nil {
+		dialTimeout = time.Duration(timeouts.DialTimeout)
+	}
+
+	route, serverNames, err := buildPassthroughRoute(frontendName, frontend, backend, dialTimeout)
+	if err != nil {
+		return err
+	}
+
+	routes, _ := serverEntryPoint.passthrough.Get().(passthroughRoutes)
+	if routes == nil {
+		routes = passthroughRoutes{}
+		serverEntryPoint.passthrough.Set(routes)
+	}
+	for _, serverName := range serverNames {
+		if existing, ok := routes[serverName]; ok {
+			log.Warnf("Server name %s of frontend %s is already passed through by frontend %s", serverName, frontendName, existing.frontendName)
+			continue
+		}
+		log.Debugf("Passing TLS connections for %s through to backend %s on entryPoint %s", serverName, frontend.Backend, entryPointName)
+		routes[serverName] = route
+	}
+	return nil
+}
+
+// buildHostHeader computes the Host header strategy of a frontend:
+// the strategy of the frontend overrides the one of the backend,
+// which itself overrides the passHostHeader option of the frontend.
+func buildHostHeader(backend *types.Backend, frontend *types.Frontend) *types.HostHeader {
+	if frontend.HostHeader != nil && len(frontend.HostHeader.Strategy) > 0 {
+		return frontend.HostHeader
+	}
+	if backend != nil && backend.HostHeader != nil && len(backend.HostHeader.Strategy) > 0 {
+		return backend.HostHeader
+	}
+	if frontend.PassHostHeader {
+		return &types.HostHeader{Strategy: types.HostHeaderPass}
+	}
+	return &types.HostHeader{Strategy: types.HostHeaderBackend}
+}
+
 // loadConfig returns a new gorilla.mux Route from the specified global configuration and the dynamic
 // provider configurations.
 func (s *Server) loadConfig(configurations types.Configurations, globalConfiguration configuration.GlobalConfiguration) (map[string]*serverEntryPoint, error) {
 	serverEntryPoints := s.buildEntryPoints(globalConfiguration)
 	redirectHandlers := make(map[string]negroni.Handler)
 	backends := map[string]http.Handler{}
+	backendsMiddlewares := map[string][]string{}
 	backendsHealthCheck := map[string]*healthcheck.BackendHealthCheck{}
+	canaryReleases := map[string]*canary.Release{}
+	adaptiveLimiters := map[adaptiveLimiterKey]*middlewares.AdaptiveLimiter{}
+	roundTrippers := map[roundTripperKey]http.RoundTripper{}
 	errorHandler := NewRecordingErrorHandler(middlewares.DefaultNetErrorRecorder{})
+	tenants := newTenants(globalConfiguration.Tenancy, s.metricsRegistry)
+	frontendsOverQuota := tenants.frontendsOverQuota(configurations)
+	tlsOptions := mergeTLSOptions(configurations)
 
 	for providerName, config := range configurations {
 		frontendNames := sortedFrontendNamesForConfig(config)
@@ -875,6 +1771,11 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 
 			log.Debugf("Creating frontend %s", frontendName)
 
+			if frontendsOverQuota[providerName+frontendName] {
+				log.Errorf("Skipping frontend %s...", frontendName)
+				continue frontend
+			}
+
 			var frontendEntryPoints []string
 			for _, entryPointName := range frontend.EntryPoints {
 				if _, ok := serverEntryPoints[entryPointName]; !ok {
@@ -893,6 +1794,33 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 			for _, entryPointName := range frontend.EntryPoints {
 				log.Debugf("Wiring frontend %s to entryPoint %s", frontendName, entryPointName)
 
+				if globalConfiguration.EntryPoints[entryPointName].Protocol == configuration.EntryPointProtocolUDP {
+					if err := s.wireUDPFrontend(serverEntryPoints[entryPointName], entryPointName, frontendName, frontend, config.Backends[frontend.Backend], globalConfiguration); err != nil {
+						log.Errorf("Error creating UDP forwarding for frontend %s: %v", frontendName, err)
+						log.Errorf("Skipping frontend %s...", frontendName)
+						continue frontend
+					}
+					continue
+				}
+
+				if globalConfiguration.EntryPoints[entryPointName].Protocol == configuration.EntryPointProtocolTCP {
+					if err := s.wireTCPFrontend(serverEntryPoints[entryPointName], entryPointName, frontendName, frontend, config.Backends[frontend.Backend], globalConfiguration); err != nil {
+						log.Errorf("Error creating TCP route for frontend %s: %v", frontendName, err)
+						log.Errorf("Skipping frontend %s...", frontendName)
+						continue frontend
+					}
+					continue
+				}
+
+				if frontend.TLSPassthrough {
+					if err := s.wirePassthroughFrontend(serverEntryPoints[entryPointName], entryPointName, frontendName, frontend, config.Backends[frontend.Backend], globalConfiguration); err != nil {
+						log.Errorf("Error creating TLS passthrough for frontend %s: %v", frontendName, err)
+						log.Errorf("Skipping frontend %s...", frontendName)
+						continue frontend
+					}
+					continue
+				}
+
 				newServerRoute := &types.ServerRoute{Route: serverEntryPoints[entryPointName].httpRouter.GetHandler().NewRoute().Name(frontendName)}
 				for routeName, route := range frontend.Routes {
 					err := getRoute(newServerRoute, &route)
@@ -906,10 +1834,12 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 
 				entryPoint := globalConfiguration.EntryPoints[entryPointName]
 				n := negroni.New()
+				var middlewareNames, lbMiddlewareNames []string
 				if entryPoint.Redirect != nil && entryPointName != entryPoint.Redirect.EntryPoint {
 					if redirectHandlers[entryPointName] != nil {
 						n.Use(redirectHandlers[entryPointName])
-					} else if handler, err := s.buildRedirectHandler(entryPointName, entryPoint.Redirect); err != nil {
+						middlewareNames = append(middlewareNames, "entrypoint-redirect")
+					} else if handler, err := s.buildRedirectHandler(entryPointName, entryPoint.Redirect, globalConfiguration.EntryPoints); err != nil {
 						log.Errorf("Error loading entrypoint configuration for frontend %s: %v", frontendName, err)
 						log.Errorf("Skipping frontend %s...", frontendName)
 						continue frontend
@@ -917,12 +1847,53 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 						handlerToUse := s.wrapNegroniHandlerWithAccessLog(handler, fmt.Sprintf("entrypoint redirect for %s", frontendName))
 						n.Use(handlerToUse)
 						redirectHandlers[entryPointName] = handlerToUse
+						middlewareNames = append(middlewareNames, "entrypoint-redirect")
 					}
 				}
-				if backends[entryPointName+providerName+frontend.Backend] == nil {
+				var clientCA *frontendClientCA
+				if frontend.ClientCA != nil && entryPoint.TLS != nil {
+					var err error
+					clientCA, err = loadFrontendClientCA(frontendName, frontend.ClientCA)
+					if err == nil {
+						err = wireFrontendClientCA(serverEntryPoints[entryPointName], clientCA, frontend)
+					}
+					if err != nil {
+						log.Errorf("Error creating client CA for frontend %s: %v", frontendName, err)
+						log.Errorf("Skipping frontend %s...", frontendName)
+						continue frontend
+					}
+				}
+				if len(frontend.TLSOptions) > 0 && entryPoint.TLS != nil {
+					if err := wireFrontendTLSOptions(serverEntryPoints[entryPointName], tlsOptions, frontendName, frontend); err != nil {
+						log.Errorf("Error applying TLS options for frontend %s: %v", frontendName, err)
+						log.Errorf("Skipping frontend %s...", frontendName)
+						continue frontend
+					}
+				}
+
+				backendCacheKey := entryPointName + providerName + frontend.Backend
+				if frontend.ForwardingTimeouts != nil || frontend.HostHeader != nil || len(frontend.ServerSelector) > 0 || frontend.Canary != nil || frontend.TrafficSplit != nil || clientCA != nil || isInternalBackend(frontend.Backend) {
+					// a frontend overriding the forwarding timeouts or the Host header, selecting a subset of the servers,
+					// releasing a canary, splitting its traffic, verifying its client certificates, or routing to an internal backend,
+					// can't share its backend handler
+					backendCacheKey += frontendName
+				}
+				if backends[backendCacheKey] == nil && isInternalBackend(frontend.Backend) {
+					log.Debugf("Creating internal backend %s", frontend.Backend)
+
+					handler, handlerMiddlewareNames, err := s.buildInternalFrontendHandler(n, middlewareNames, entryPointName, frontendName, frontend, globalConfiguration.EntryPoints)
+					if err != nil {
+						log.Errorf("Error creating internal backend %s for frontend %s: %v", frontend.Backend, frontendName, err)
+						log.Errorf("Skipping frontend %s...", frontendName)
+						continue frontend
+					}
+					backends[backendCacheKey] = handler
+					backendsMiddlewares[backendCacheKey] = handlerMiddlewareNames
+				} else if backends[backendCacheKey] == nil {
 					log.Debugf("Creating backend %s", frontend.Backend)
 
-					roundTripper, err := s.getRoundTripper(entryPointName, globalConfiguration, frontend.PassTLSCert, entryPoint.TLS)
+					timeouts := buildForwardingTimeouts(globalConfiguration.ForwardingTimeouts, config.Backends[frontend.Backend], frontend)
+					roundTripper, err := s.getRoundTripper(entryPointName, globalConfiguration, frontend.PassTLSCert, entryPoint.TLS, timeouts, config.Backends[frontend.Backend], roundTrippers)
 					if err != nil {
 						log.Errorf("Failed to create RoundTripper for frontend %s: %v", frontendName, err)
 						log.Errorf("Skipping frontend %s...", frontendName)
@@ -936,6 +1907,15 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 						continue frontend
 					}
 
+					hostHeader := buildHostHeader(config.Backends[frontend.Backend], frontend)
+					rewriter, err = newHostHeaderRewriter(rewriter, hostHeader)
+					if err != nil {
+						log.Errorf("Error creating Host header rewriter for frontend %s: %v", frontendName, err)
+						log.Errorf("Skipping frontend %s...", frontendName)
+						continue frontend
+					}
+					rewriter = middlewares.NewTrailersRewriter(rewriter)
+
 					headerMiddleware := middlewares.NewHeaderFromStruct(frontend.Headers)
 					secureMiddleware := middlewares.NewSecure(frontend.Headers)
 
@@ -944,7 +1924,7 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 
 					fwd, err = forward.New(
 						forward.Stream(true),
-						forward.PassHostHeader(frontend.PassHostHeader),
+						forward.PassHostHeader(hostHeader.Strategy != types.HostHeaderBackend),
 						forward.RoundTripper(roundTripper),
 						forward.ErrorHandler(errorHandler),
 						forward.Rewriter(rewriter),
@@ -957,6 +1937,12 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 						continue frontend
 					}
 
+					fwd = middlewares.NewTrailers(fwd)
+
+					if backend := config.Backends[frontend.Backend]; backend != nil && backend.ProxyProtocol > 0 {
+						fwd = announceClient(fwd)
+					}
+
 					if s.tracingMiddleware.IsEnabled() {
 						tm := s.tracingMiddleware.NewForwarderMiddleware(frontendName, frontend.Backend)
 
@@ -966,6 +1952,32 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 						})
 					}
 
+					if globalConfiguration.RoutingDebug != nil {
+						fwd = middlewares.NewRoutingDebugServer(fwd)
+					}
+
+					var canaryRelease *canary.Release
+					var canaryTarget *canary.Target
+					if frontend.Canary != nil {
+						canaryRelease, canaryTarget, err = buildCanaryTarget(canaryReleases, frontendName, config, frontend, fwd)
+						if err != nil {
+							log.Errorf("Error creating canary release for frontend %s: %v", frontendName, err)
+							log.Errorf("Skipping frontend %s...", frontendName)
+							continue frontend
+						}
+						fwd = canaryTarget.Recorder
+					}
+
+					var splitServers []*url.URL
+					if frontend.TrafficSplit != nil {
+						splitServers, err = buildTrafficSplitServers(config, frontend.TrafficSplit)
+						if err != nil {
+							log.Errorf("Error creating traffic split for frontend %s: %v", frontendName, err)
+							log.Errorf("Skipping frontend %s...", frontendName)
+							continue frontend
+						}
+					}
+
 					var rr *roundrobin.RoundRobin
 					var saveFrontend http.Handler
 					if s.accessLoggerMiddleware != nil {
@@ -991,7 +2003,9 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 
 					var sticky *roundrobin.StickySession
 					var cookieName string
-					if stickiness := config.Backends[frontend.Backend].LoadBalancer.Stickiness; stickiness != nil {
+					stickiness := config.Backends[frontend.Backend].LoadBalancer.Stickiness
+					hashAffinity := stickiness != nil && (len(stickiness.Header) > 0 || len(stickiness.Query) > 0)
+					if stickiness != nil && !hashAffinity {
 						cookieName = cookie.GetName(stickiness.CookieName, frontend.Backend)
 						sticky = roundrobin.NewStickySession(cookieName)
 					}
@@ -1006,15 +2020,28 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 							rebalancer, _ = roundrobin.NewRebalancer(rr, roundrobin.RebalancerStickySession(sticky))
 						}
 						lb = rebalancer
-						if err := s.configureLBServers(rebalancer, config, frontend); err != nil {
+						// the health check goes through the canary weights of the servers
+						var serversLB healthcheck.LoadBalancer = rebalancer
+						if canaryTarget != nil {
+							canaryTarget.LB = canary.NewWeightedLoadBalancer(rebalancer)
+							serversLB = canaryTarget.LB
+						}
+						if err := s.configureLBServers(serversLB, config, frontend); err != nil {
 							log.Errorf("Skipping frontend %s...", frontendName)
 							continue frontend
 						}
-						hcOpts := parseHealthCheckOptions(rebalancer, frontend.Backend, config.Backends[frontend.Backend].HealthCheck, globalConfiguration.HealthCheck)
+						hcOpts := parseHealthCheckOptions(serversLB, frontend.Backend, config.Backends[frontend.Backend].HealthCheck, globalConfiguration.HealthCheck)
 						if hcOpts != nil {
 							log.Debugf("Setting up backend health check %s", *hcOpts)
-							hcOpts.Transport = s.defaultForwardingRoundTripper
-							backendsHealthCheck[entryPointName+frontend.Backend] = healthcheck.NewBackendHealthCheck(*hcOpts, frontend.Backend)
+							hcOpts.Transport = roundTripper
+							if hcOpts.ProxyProtocol == 0 {
+								hcOpts.ProxyProtocol = config.Backends[frontend.Backend].ProxyProtocol
+							}
+							backendsHealthCheck[backendCacheKey] = healthcheck.NewBackendHealthCheck(*hcOpts, frontend.Backend)
+						}
+						if hashAffinity {
+							log.Debugf("Session affinity on header %q or query parameter %q", stickiness.Header, stickiness.Query)
+							lb = middlewares.NewHashAffinity(rebalancer, rr.Next(), lb, stickiness.Header, stickiness.Query)
 						}
 						lb = middlewares.NewEmptyBackendHandler(rebalancer, lb)
 					case types.Wrr:
@@ -1028,19 +2055,39 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 							}
 						}
 						lb = rr
-						if err := s.configureLBServers(rr, config, frontend); err != nil {
+						// the health check goes through the canary weights of the servers
+						var serversLB healthcheck.LoadBalancer = rr
+						if canaryTarget != nil {
+							canaryTarget.LB = canary.NewWeightedLoadBalancer(rr)
+							serversLB = canaryTarget.LB
+						}
+						if err := s.configureLBServers(serversLB, config, frontend); err != nil {
 							log.Errorf("Skipping frontend %s...", frontendName)
 							continue frontend
 						}
-						hcOpts := parseHealthCheckOptions(rr, frontend.Backend, config.Backends[frontend.Backend].HealthCheck, globalConfiguration.HealthCheck)
+						hcOpts := parseHealthCheckOptions(serversLB, frontend.Backend, config.Backends[frontend.Backend].HealthCheck, globalConfiguration.HealthCheck)
 						if hcOpts != nil {
 							log.Debugf("Setting up backend health check %s", *hcOpts)
-							hcOpts.Transport = s.defaultForwardingRoundTripper
-							backendsHealthCheck[entryPointName+frontend.Backend] = healthcheck.NewBackendHealthCheck(*hcOpts, frontend.Backend)
+							hcOpts.Transport = roundTripper
+							if hcOpts.ProxyProtocol == 0 {
+								hcOpts.ProxyProtocol = config.Backends[frontend.Backend].ProxyProtocol
+							}
+							backendsHealthCheck[backendCacheKey] = healthcheck.NewBackendHealthCheck(*hcOpts, frontend.Backend)
+						}
+						if hashAffinity {
+							log.Debugf("Session affinity on header %q or query parameter %q", stickiness.Header, stickiness.Query)
+							lb = middlewares.NewHashAffinity(rr, rr.Next(), lb, stickiness.Header, stickiness.Query)
 						}
 						lb = middlewares.NewEmptyBackendHandler(rr, lb)
 					}
 
+					if frontend.TrafficSplit != nil {
+						split := frontend.TrafficSplit
+						log.Debugf("Splitting %d%% of the users on header %q or cookie %q to backend %s", split.Percentage, split.Header, split.Cookie, split.Backend)
+						lb = middlewares.NewTrafficSplit(splitServers, rr.Next(), lb, split.Percentage, split.Header, split.Cookie)
+						lbMiddlewareNames = append([]string{"trafficsplit"}, lbMiddlewareNames...)
+					}
+
 					if len(frontend.Errors) > 0 {
 						for _, errorPage := range frontend.Errors {
 							if config.Backends[errorPage.Backend] != nil && config.Backends[errorPage.Backend].Servers["error"].URL != "" {
@@ -1049,6 +2096,7 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 									log.Errorf("Error creating custom error page middleware, %v", err)
 								} else {
 									n.Use(errorPageHandler)
+									middlewareNames = append(middlewareNames, "errors")
 								}
 							} else {
 								log.Errorf("Error Page is configured for Frontend %s, but either Backend %s is not set or Backend URL is missing", frontendName, errorPage.Backend)
@@ -1059,6 +2107,7 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 					if frontend.RateLimit != nil && len(frontend.RateLimit.RateSet) > 0 {
 						lb, err = s.buildRateLimiter(lb, frontend.RateLimit)
 						lb = s.wrapHTTPHandlerWithAccessLog(lb, fmt.Sprintf("rate limit for %s", frontendName))
+						lbMiddlewareNames = append([]string{"ratelimit"}, lbMiddlewareNames...)
 						if err != nil {
 							log.Errorf("Error creating rate limiter: %v", err)
 							log.Errorf("Skipping frontend %s...", frontendName)
@@ -1066,6 +2115,19 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 						}
 					}
 
+					if adaptiveLimit := config.Backends[frontend.Backend].AdaptiveLimit; adaptiveLimit != nil {
+						// the limit applies to the backend as a whole, across its frontends and entrypoints
+						limiterKey := adaptiveLimiterKey{backend: providerName + frontend.Backend, config: *adaptiveLimit}
+						if adaptiveLimiters[limiterKey] == nil {
+							adaptiveLimiters[limiterKey] = s.adaptiveLimiter(limiterKey, frontend.Backend)
+						}
+						log.Debugf("Creating load-balancer adaptive limit")
+						limitHandler := negroni.New(adaptiveLimiters[limiterKey])
+						limitHandler.UseHandler(lb)
+						lb = s.wrapHTTPHandlerWithAccessLog(limitHandler, fmt.Sprintf("adaptive limit for %s", frontendName))
+						lbMiddlewareNames = append([]string{"adaptivelimit"}, lbMiddlewareNames...)
+					}
+
 					maxConns := config.Backends[frontend.Backend].MaxConn
 					if maxConns != nil && maxConns.Amount != 0 {
 						extractFunc, err := utils.NewExtractor(maxConns.ExtractorFunc)
@@ -1077,6 +2139,7 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 						log.Debugf("Creating load-balancer connlimit")
 						lb, err = connlimit.New(lb, extractFunc, maxConns.Amount)
 						lb = s.wrapHTTPHandlerWithAccessLog(lb, fmt.Sprintf("connection limit for %s", frontendName))
+						lbMiddlewareNames = append([]string{"maxconn"}, lbMiddlewareNames...)
 						if err != nil {
 							log.Errorf("Error creating connlimit: %v", err)
 							log.Errorf("Skipping frontend %s...", frontendName)
@@ -1087,27 +2150,37 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 					if globalConfiguration.Retry != nil {
 						countServers := len(config.Backends[frontend.Backend].Servers)
 						lb = s.buildRetryMiddleware(lb, globalConfiguration, countServers, frontend.Backend)
+						lbMiddlewareNames = append([]string{"retry"}, lbMiddlewareNames...)
 					}
 
 					if s.metricsRegistry.IsEnabled() {
 						n.Use(middlewares.NewBackendMetricsMiddleware(s.metricsRegistry, frontend.Backend))
+						middlewareNames = append(middlewareNames, "metrics")
 					}
 
-					ipWhitelistMiddleware, err := configureIPWhitelistMiddleware(frontend.WhitelistSourceRange)
+					ipWhitelistMiddleware, err := configureIPWhitelistMiddleware(frontend.WhitelistSourceRange, frontend.WhitelistIPStrategy)
 					if err != nil {
 						log.Errorf("Error creating IP Whitelister: %s", err)
 					} else if ipWhitelistMiddleware != nil {
 						ipWhitelistMiddleware = s.wrapNegroniHandlerWithAccessLog(ipWhitelistMiddleware, fmt.Sprintf("ipwhitelister for %s", frontendName))
 						n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("IP whitelist", ipWhitelistMiddleware, false))
+						middlewareNames = append(middlewareNames, "whitelist")
 						log.Infof("Configured IP Whitelists: %s", frontend.WhitelistSourceRange)
 					}
 
+					if clientCA != nil {
+						clientCAVerifier := s.wrapNegroniHandlerWithAccessLog(middlewares.NewClientCAVerifier(clientCA.pool, clientCA.optional), fmt.Sprintf("client CA verifier for %s", frontendName))
+						n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("Client CA", clientCAVerifier, false))
+						middlewareNames = append(middlewareNames, "client-ca")
+					}
+
 					if frontend.Redirect != nil && entryPointName != frontend.Redirect.EntryPoint {
-						rewrite, err := s.buildRedirectHandler(entryPointName, frontend.Redirect)
+						rewrite, err := s.buildRedirectHandler(entryPointName, frontend.Redirect, globalConfiguration.EntryPoints)
 						if err != nil {
 							log.Errorf("Error creating Frontend Redirect: %v", err)
 						} else {
 							n.Use(s.wrapNegroniHandlerWithAccessLog(rewrite, fmt.Sprintf("frontend redirect for %s", frontendName)))
+							middlewareNames = append(middlewareNames, "redirect")
 							log.Debugf("Frontend %s redirect created", frontendName)
 						}
 					}
@@ -1127,17 +2200,20 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 							log.Errorf("Error creating Auth: %s", err)
 						} else {
 							n.Use(s.wrapNegroniHandlerWithAccessLog(authMiddleware, fmt.Sprintf("Auth for %s", frontendName)))
+							middlewareNames = append(middlewareNames, "basicauth")
 						}
 					}
 
 					if headerMiddleware != nil {
 						log.Debugf("Adding header middleware for frontend %s", frontendName)
 						n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("Header", headerMiddleware, false))
+						middlewareNames = append(middlewareNames, "headers")
 					}
 
 					if secureMiddleware != nil {
 						log.Debugf("Adding secure middleware for frontend %s", frontendName)
 						n.UseFunc(secureMiddleware.HandlerFuncWithNextForRequestOnly)
+						middlewareNames = append(middlewareNames, "secure")
 					}
 
 					if config.Backends[frontend.Backend].Buffering != nil {
@@ -1147,6 +2223,7 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 							log.Errorf("Error setting up buffering middleware: %s", err)
 						} else {
 							lb = bufferedLb
+							lbMiddlewareNames = append([]string{"buffering"}, lbMiddlewareNames...)
 						}
 					}
 
@@ -1160,17 +2237,40 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 							continue frontend
 						}
 						n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("Circuit breaker", circuitBreaker, false))
+						middlewareNames = append(middlewareNames, "circuitbreaker")
 					} else {
 						n.UseHandler(lb)
 					}
-					backends[entryPointName+providerName+frontend.Backend] = n
+					if canaryRelease != nil {
+						canaryRelease.Targets = append(canaryRelease.Targets, canaryTarget)
+						canaryReleases[frontendName] = canaryRelease
+					}
+					backends[backendCacheKey] = n
+					backendsMiddlewares[backendCacheKey] = append(middlewareNames, lbMiddlewareNames...)
 				} else {
 					log.Debugf("Reusing backend %s", frontend.Backend)
 				}
 				if frontend.Priority > 0 {
 					newServerRoute.Route.Priority(frontend.Priority)
 				}
-				s.wireFrontendBackend(newServerRoute, backends[entryPointName+providerName+frontend.Backend])
+				handler := backends[backendCacheKey]
+				if globalConfiguration.RoutingDebug != nil {
+					middlewareNames := backendsMiddlewares[backendCacheKey]
+					if s.defaultMiddlewares != nil {
+						middlewareNames = append(s.defaultMiddlewares.middlewareNames(entryPoint), middlewareNames...)
+					}
+					handler = s.buildRoutingDebugHandler(handler, globalConfiguration.RoutingDebug, entryPointName, frontendName, frontend, middlewareNames)
+				}
+				if len(frontend.Tenant) > 0 {
+					handler = tenants.wrap(frontend.Tenant, handler)
+				}
+				if s.overloadLimiter != nil {
+					handler = s.wrapOverloadProtection(handler, globalConfiguration.Overload, frontend.PriorityClass)
+				}
+				if s.defaultMiddlewares != nil {
+					handler = s.defaultMiddlewares.wrap(handler, entryPoint)
+				}
+				s.wireFrontendBackend(newServerRoute, handler)
 
 				err := newServerRoute.Route.GetError()
 				if err != nil {
@@ -1180,9 +2280,23 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 		}
 	}
 	healthcheck.GetHealthCheck(s.metricsRegistry).SetBackendsConfiguration(s.routinesPool.Ctx(), backendsHealthCheck)
+	canary.GetController().SetReleases(s.routinesPool.Ctx(), canaryReleases)
+	s.adaptiveLimitersLock.Lock()
+	s.adaptiveLimiters = adaptiveLimiters
+	s.adaptiveLimitersLock.Unlock()
 	// Get new certificates list sorted per entrypoints
 	// Update certificates
 	entryPointsCertificates, err := s.loadHTTPSConfiguration(configurations, globalConfiguration.DefaultEntryPoints)
+	if err != nil {
+		// the round trippers created for the configuration are discarded with it
+		for key, roundTripper := range roundTrippers {
+			if s.forwardingRoundTrippers[key] != roundTripper {
+				closeIdleConnections(roundTripper)
+			}
+		}
+	} else {
+		s.keepForwardingRoundTrippers(roundTrippers)
+	}
 	//sort routes and update certificates
 	for serverEntryPointName, serverEntryPoint := range serverEntryPoints {
 		serverEntryPoint.httpRouter.GetHandler().SortRoutes()
@@ -1195,13 +2309,34 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 	return serverEntryPoints, err
 }
 
+// wrapOverloadProtection queues the requests of a frontend by priority when the server is overloaded.
+func (s *Server) wrapOverloadProtection(handler http.Handler, config *types.Overload, class string) http.Handler {
+	n := negroni.New(middlewares.NewOverloadProtection(s.overloadLimiter, config, class))
+	n.UseHandler(handler)
+	return n
+}
+
 func (s *Server) configureLBServers(lb healthcheck.LoadBalancer, config *types.Configuration, frontend *types.Frontend) error {
+	selector, err := parseServerSelector(frontend.ServerSelector)
+	if err != nil {
+		log.Errorf("Error parsing server selector %q: %v", frontend.ServerSelector, err)
+		return err
+	}
+
+	var selected int
 	for name, srv := range config.Backends[frontend.Backend].Servers {
-		u, err := url.Parse(srv.URL)
+		if !selector.matches(srv.Labels) {
+			log.Debugf("Skipping server %s not matching the selector %q", name, frontend.ServerSelector)
+			continue
+		}
+		selected++
+
+		u, err := parseServerURL(srv.URL)
 		if err != nil {
 			log.Errorf("Error parsing server URL %s: %v", srv.URL, err)
 			return err
 		}
+		normalizeServerURL(u)
 		log.Debugf("Creating server %s at %s with weight %d", name, u, srv.Weight)
 		if err := lb.UpsertServer(u, roundrobin.Weight(srv.Weight)); err != nil {
 			log.Errorf("Error adding server %s to load balancer: %v", srv.URL, err)
@@ -1209,13 +2344,30 @@ func (s *Server) configureLBServers(lb healthcheck.LoadBalancer, config *types.C
 		}
 		s.metricsRegistry.BackendServerUpGauge().With("backend", frontend.Backend, "url", srv.URL).Set(1)
 	}
+
+	if selected == 0 && len(selector) > 0 {
+		log.Warnf("No server of backend %s matches the selector %q", frontend.Backend, frontend.ServerSelector)
+	}
 	return nil
 }
 
-func configureIPWhitelistMiddleware(whitelistSourceRanges []string) (negroni.Handler, error) {
+// normalizeServerURL makes the URL of a server independent of the way it is written by the provider.
+// The sticky cookies hold the URL of the servers: they must stay valid across the configuration reloads
+// as long as the server is unchanged.
+func normalizeServerURL(u *url.URL) {
+	u.Scheme = strings.ToLower(u.Scheme)
+	if i := strings.Index(u.Host, "%"); i >= 0 {
+		// the zone of IPv6 addresses is a case-sensitive interface name
+		u.Host = strings.ToLower(u.Host[:i]) + u.Host[i:]
+	} else {
+		u.Host = strings.ToLower(u.Host)
+	}
+}
+
+func configureIPWhitelistMiddleware(whitelistSourceRanges []string, ipStrategy *types.IPStrategy) (negroni.Handler, error) {
 	if len(whitelistSourceRanges) > 0 {
 		ipSourceRanges := whitelistSourceRanges
-		ipWhitelistMiddleware, err := middlewares.NewIPWhitelister(ipSourceRanges)
+		ipWhitelistMiddleware, err := middlewares.NewIPWhitelister(ipSourceRanges, ipStrategy)
 
 		if err != nil {
 			return nil, err
@@ -1271,10 +2423,22 @@ func (s *Server) wireFrontendBackend(serverRoute *types.ServerRoute, handler htt
 	serverRoute.Route.Handler(handler)
 }
 
-func (s *Server) buildRedirectHandler(srcEntryPointName string, opt *types.Redirect) (negroni.Handler, error) {
+func (s *Server) buildRoutingDebugHandler(handler http.Handler, routingDebug *configuration.RoutingDebug, entryPointName, frontendName string, frontend *types.Frontend, middlewareNames []string) http.Handler {
+	var rules []string
+	for _, routeName := range sortedRouteNames(frontend.Routes) {
+		rules = append(rules, frontend.Routes[routeName].Rule)
+	}
+
+	debugMiddleware := middlewares.NewRoutingDebug(routingDebug.Header, routingDebug.Secret, entryPointName, frontendName, rules)
+	debugMiddleware.Middlewares = middlewareNames
+
+	return negroni.New(debugMiddleware, negroni.Wrap(handler))
+}
+
+func (s *Server) buildRedirectHandler(srcEntryPointName string, opt *types.Redirect, entryPoints configuration.EntryPoints) (negroni.Handler, error) {
 	// entry point redirect
 	if len(opt.EntryPoint) > 0 {
-		entryPoint := s.globalConfiguration.EntryPoints[opt.EntryPoint]
+		entryPoint := entryPoints[opt.EntryPoint]
 		if entryPoint == nil {
 			return nil, fmt.Errorf("unknown target entrypoint %q", srcEntryPointName)
 		}
@@ -1301,7 +2465,7 @@ func (s *Server) buildDefaultHTTPRouter() *mux.Router {
 }
 
 func parseHealthCheckOptions(lb healthcheck.LoadBalancer, backend string, hc *types.HealthCheck, hcConfig *configuration.HealthCheckConfig) *healthcheck.Options {
-	if hc == nil || hc.Path == "" || hcConfig == nil {
+	if hc == nil || (hc.Path == "" && hc.Type != types.HealthCheckTypeGRPC && hc.Type != types.HealthCheckTypeTCP) || hcConfig == nil {
 		return nil
 	}
 
@@ -1318,11 +2482,36 @@ func parseHealthCheckOptions(lb healthcheck.LoadBalancer, backend string, hc *ty
 		}
 	}
 
+	var timeout time.Duration
+	if hc.Timeout != "" {
+		timeoutOverride, err := time.ParseDuration(hc.Timeout)
+		switch {
+		case err != nil:
+			log.Errorf("Illegal healthcheck timeout for backend '%s': %s", backend, err)
+		case timeoutOverride <= 0:
+			log.Errorf("Healthcheck timeout smaller than zero for backend '%s'", backend)
+		default:
+			timeout = timeoutOverride
+		}
+	}
+
+	proxyProtocol := hc.ProxyProtocol
+	if proxyProtocol < 0 || proxyProtocol > 2 {
+		log.Errorf("Illegal healthcheck PROXY protocol version for backend '%s': %d", backend, proxyProtocol)
+		proxyProtocol = 0
+	}
+
 	return &healthcheck.Options{
-		Path:     hc.Path,
-		Port:     hc.Port,
-		Interval: interval,
-		LB:       lb,
+		Type:          hc.Type,
+		Path:          hc.Path,
+		Port:          hc.Port,
+		Service:       hc.Service,
+		Hostname:      hc.Hostname,
+		Headers:       hc.Headers,
+		ProxyProtocol: proxyProtocol,
+		Interval:      interval,
+		Timeout:       timeout,
+		LB:            lb,
 	}
 }
 
@@ -1337,6 +2526,15 @@ func getRoute(serverRoute *types.ServerRoute, route *types.Route) error {
 	return nil
 }
 
+func sortedProviderNames(configurations types.Configurations) []string {
+	var keys []string
+	for key := range configurations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func sortedFrontendNamesForConfig(configuration *types.Configuration) []string {
 	var keys []string
 	for key := range configuration.Frontends {
@@ -1346,6 +2544,15 @@ func sortedFrontendNamesForConfig(configuration *types.Configuration) []string {
 	return keys
 }
 
+func sortedRouteNames(routes map[string]types.Route) []string {
+	var keys []string
+	for key := range routes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func configureFrontends(frontends map[string]*types.Frontend, defaultEntrypoints []string) {
 	for _, frontend := range frontends {
 		// default endpoints if not defined in frontends
@@ -1401,6 +2608,9 @@ func registerMetricClients(metricsConfig *types.Metrics) metrics.Registry {
 	if metricsConfig.Prometheus != nil {
 		registries = append(registries, metrics.RegisterPrometheus(metricsConfig.Prometheus))
 		log.Debug("Configured Prometheus metrics")
+		if metricsConfig.Prometheus.Push != nil {
+			log.Debugf("Configured Prometheus metrics pushing to %s once every %s", metricsConfig.Prometheus.Push.Address, metricsConfig.Prometheus.Push.PushInterval)
+		}
 	}
 	if metricsConfig.Datadog != nil {
 		registries = append(registries, metrics.RegisterDatadog(metricsConfig.Datadog))
@@ -1422,6 +2632,7 @@ func stopMetricsClients() {
 	metrics.StopDatadog()
 	metrics.StopStatsd()
 	metrics.StopInfluxDB()
+	metrics.StopPrometheusPush()
 }
 
 func (s *Server) buildRateLimiter(handler http.Handler, rlConfig *types.RateLimit) (http.Handler, error) {
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+)
+
+// labelRequirement is a condition on a label of the servers:
+// a value to match (or not), or only the presence of the label.
+type labelRequirement struct {
+	key      string
+	value    string
+	negate   bool
+	presence bool
+}
+
+// serverSelector selects the servers of a backend matching all its requirements.
+type serverSelector []labelRequirement
+
+// parseServerSelector parses a comma-separated list of requirements,
+// i.e. `key==value`, `key!=value`, `key` (the label is set) or `!key` (the label is not set).
+func parseServerSelector(expression string) (serverSelector, error) {
+	var selector serverSelector
+	for _, part := range strings.Split(expression, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			continue
+		}
+
+		var requirement labelRequirement
+		switch {
+		case strings.Contains(part, "!="):
+			kv := strings.SplitN(part, "!=", 2)
+			requirement = labelRequirement{key: kv[0], value: kv[1], negate: true}
+		case strings.Contains(part, "=="):
+			kv := strings.SplitN(part, "==", 2)
+			requirement = labelRequirement{key: kv[0], value: kv[1]}
+		case strings.HasPrefix(part, "!"):
+			requirement = labelRequirement{key: part[1:], presence: true, negate: true}
+		default:
+			requirement = labelRequirement{key: part, presence: true}
+		}
+
+		requirement.key = strings.TrimSpace(requirement.key)
+		requirement.value = strings.TrimSpace(requirement.value)
+		if len(requirement.key) == 0 || strings.ContainsAny(requirement.key, "=!") {
+			return nil, fmt.Errorf("invalid server selector requirement %q", part)
+		}
+		selector = append(selector, requirement)
+	}
+	return selector, nil
+}
+
+// matches returns true if the labels meet all the requirements of the selector.
+func (s serverSelector) matches(labels map[string]string) bool {
+	for _, requirement := range s {
+		value, ok := labels[requirement.key]
+		match := ok
+		if !requirement.presence {
+			match = ok && value == requirement.value
+		}
+		if match == requirement.negate {
+			return false
+		}
+	}
+	return true
+}
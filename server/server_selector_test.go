@@ -0,0 +1,91 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerSelector(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		expression string
+		labels     map[string]string
+		expected   bool
+	}{
+		{
+			desc:       "empty selector",
+			expression: "",
+			labels:     nil,
+			expected:   true,
+		},
+		{
+			desc:       "equality",
+			expression: "zone==eu-west-1",
+			labels:     map[string]string{"zone": "eu-west-1"},
+			expected:   true,
+		},
+		{
+			desc:       "equality on another value",
+			expression: "zone==eu-west-1",
+			labels:     map[string]string{"zone": "us-east-1"},
+			expected:   false,
+		},
+		{
+			desc:       "equality without label",
+			expression: "zone==eu-west-1",
+			labels:     nil,
+			expected:   false,
+		},
+		{
+			desc:       "inequality",
+			expression: "version!=v2",
+			labels:     map[string]string{"version": "v1"},
+			expected:   true,
+		},
+		{
+			desc:       "inequality without label",
+			expression: "version!=v2",
+			labels:     nil,
+			expected:   true,
+		},
+		{
+			desc:       "presence",
+			expression: "canary",
+			labels:     map[string]string{"canary": ""},
+			expected:   true,
+		},
+		{
+			desc:       "absence",
+			expression: "!canary",
+			labels:     map[string]string{"canary": "true"},
+			expected:   false,
+		},
+		{
+			desc:       "all requirements must match",
+			expression: "zone == eu-west-1, tier!=batch",
+			labels:     map[string]string{"zone": "eu-west-1", "tier": "batch"},
+			expected:   false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			selector, err := parseServerSelector(test.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, selector.matches(test.labels))
+		})
+	}
+}
+
+func TestParseServerSelectorInvalid(t *testing.T) {
+	for _, expression := range []string{"==eu-west-1", "zone=eu-west-1", "!"} {
+		_, err := parseServerSelector(expression)
+		assert.Error(t, err, expression)
+	}
+}
@@ -5,13 +5,13 @@ package server
 import (
 	"os/signal"
 	"syscall"
-	"time"
 
+	"github.com/containous/traefik/audit"
 	"github.com/containous/traefik/log"
 )
 
 func (s *Server) configureSignals() {
-	signal.Notify(s.signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1)
+	signal.Notify(s.signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1, syscall.SIGHUP)
 }
 
 func (s *Server) listenSignals() {
@@ -30,15 +30,16 @@ func (s *Server) listenSignals() {
 			if err := log.RotateFile(); err != nil {
 				log.Errorf("Error rotating traefik log: %s", err)
 			}
+
+			if err := audit.RotateFile(); err != nil {
+				log.Errorf("Error rotating audit log: %s", err)
+			}
+		case syscall.SIGHUP:
+			log.Infof("Reloading the static configuration: %+v", sig)
+			s.reloadStaticConfiguration()
 		default:
 			log.Infof("I have to go... %+v", sig)
-			reqAcceptGraceTimeOut := time.Duration(s.globalConfiguration.LifeCycle.RequestAcceptGraceTimeout)
-			if reqAcceptGraceTimeOut > 0 {
-				log.Infof("Waiting %s for incoming requests to cease", reqAcceptGraceTimeOut)
-				time.Sleep(reqAcceptGraceTimeOut)
-			}
-			log.Info("Stopping server gracefully")
-			s.Stop()
+			s.StopGracefully()
 		}
 	}
 }
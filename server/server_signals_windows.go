@@ -19,8 +19,7 @@ func (s *Server) listenSignals() {
 		switch sig {
 		default:
 			log.Infof("I have to go... %+v", sig)
-			log.Info("Stopping server")
-			s.Stop()
+			s.StopGracefully()
 		}
 	}
 }
@@ -2,10 +2,13 @@ package server
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,6 +21,7 @@ import (
 	"github.com/containous/traefik/metrics"
 	"github.com/containous/traefik/middlewares"
 	"github.com/containous/traefik/rules"
+	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/testhelpers"
 	"github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
@@ -27,6 +31,7 @@ import (
 	"github.com/unrolled/secure"
 	"github.com/urfave/negroni"
 	"github.com/vulcand/oxy/roundrobin"
+	"golang.org/x/net/http2"
 )
 
 // LocalhostCert is a PEM-encoded TLS cert with SAN IPs
@@ -85,6 +90,7 @@ func TestPrepareServerTimeouts(t *testing.T) {
 	tests := []struct {
 		desc             string
 		globalConfig     configuration.GlobalConfiguration
+		entryPoint       *configuration.RespondingTimeouts
 		wantIdleTimeout  time.Duration
 		wantReadTimeout  time.Duration
 		wantWriteTimeout time.Duration
@@ -130,6 +136,50 @@ func TestPrepareServerTimeouts(t *testing.T) {
 			wantReadTimeout:  time.Duration(0 * time.Second),
 			wantWriteTimeout: time.Duration(0 * time.Second),
 		},
+		{
+			desc: "entry point timeouts override the global ones",
+			globalConfig: configuration.GlobalConfiguration{
+				RespondingTimeouts: &configuration.RespondingTimeouts{
+					IdleTimeout:  flaeg.Duration(10 * time.Second),
+					ReadTimeout:  flaeg.Duration(12 * time.Second),
+					WriteTimeout: flaeg.Duration(14 * time.Second),
+				},
+			},
+			entryPoint: &configuration.RespondingTimeouts{
+				IdleTimeout:  flaeg.Duration(20 * time.Minute),
+				ReadTimeout:  flaeg.Duration(5 * time.Second),
+				WriteTimeout: flaeg.Duration(10 * time.Minute),
+			},
+			wantIdleTimeout:  time.Duration(20 * time.Minute),
+			wantReadTimeout:  time.Duration(5 * time.Second),
+			wantWriteTimeout: time.Duration(10 * time.Minute),
+		},
+		{
+			desc: "partial entry point timeouts",
+			globalConfig: configuration.GlobalConfiguration{
+				RespondingTimeouts: &configuration.RespondingTimeouts{
+					IdleTimeout:  flaeg.Duration(10 * time.Second),
+					ReadTimeout:  flaeg.Duration(12 * time.Second),
+					WriteTimeout: flaeg.Duration(14 * time.Second),
+				},
+			},
+			entryPoint: &configuration.RespondingTimeouts{
+				WriteTimeout: flaeg.Duration(10 * time.Minute),
+			},
+			wantIdleTimeout:  time.Duration(10 * time.Second),
+			wantReadTimeout:  time.Duration(12 * time.Second),
+			wantWriteTimeout: time.Duration(10 * time.Minute),
+		},
+		{
+			desc:         "entry point timeouts with global defaults",
+			globalConfig: configuration.GlobalConfiguration{},
+			entryPoint: &configuration.RespondingTimeouts{
+				ReadTimeout: flaeg.Duration(30 * time.Second),
+			},
+			wantIdleTimeout:  time.Duration(180 * time.Second),
+			wantReadTimeout:  time.Duration(30 * time.Second),
+			wantWriteTimeout: time.Duration(0 * time.Second),
+		},
 	}
 
 	for _, test := range tests {
@@ -140,13 +190,14 @@ func TestPrepareServerTimeouts(t *testing.T) {
 
 			entryPointName := "http"
 			entryPoint := &configuration.EntryPoint{
-				Address:          "localhost:0",
-				ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true},
+				Address:            "localhost:0",
+				ForwardedHeaders:   &configuration.ForwardedHeaders{Insecure: true},
+				RespondingTimeouts: test.entryPoint,
 			}
 			router := middlewares.NewHandlerSwitcher(mux.NewRouter())
 
 			srv := NewServer(test.globalConfig, nil)
-			httpServer, _, err := srv.prepareServer(entryPointName, entryPoint, router, nil, nil)
+			httpServer, _, err := srv.prepareServer(entryPointName, entryPoint, &serverEntryPoint{httpRouter: router}, nil, nil)
 			if err != nil {
 				t.Fatalf("Unexpected error when preparing srv: %s", err)
 			}
@@ -164,6 +215,165 @@ func TestPrepareServerTimeouts(t *testing.T) {
 	}
 }
 
+func TestBuildHTTP2Config(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      *configuration.HTTP2
+		expected    *http2.Server
+		expectedErr bool
+	}{
+		{
+			desc:     "no configuration",
+			expected: &http2.Server{},
+		},
+		{
+			desc: "full configuration",
+			config: &configuration.HTTP2{
+				MaxConcurrentStreams:        100,
+				InitialConnectionWindowSize: 4 << 20,
+				InitialStreamWindowSize:     1 << 20,
+				MaxFrameSize:                1 << 16,
+			},
+			expected: &http2.Server{
+				MaxConcurrentStreams:         100,
+				MaxUploadBufferPerConnection: 4 << 20,
+				MaxUploadBufferPerStream:     1 << 20,
+				MaxReadFrameSize:             1 << 16,
+			},
+		},
+		{
+			desc:        "negative max concurrent streams",
+			config:      &configuration.HTTP2{MaxConcurrentStreams: -1},
+			expectedErr: true,
+		},
+		{
+			desc:        "too small window size",
+			config:      &configuration.HTTP2{InitialStreamWindowSize: 1024},
+			expectedErr: true,
+		},
+		{
+			desc:        "too large frame size",
+			config:      &configuration.HTTP2{MaxFrameSize: 1 << 24},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := buildHTTP2Config(test.config)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, config)
+		})
+	}
+}
+
+func TestPrepareServerHTTP2Disabled(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		http2             *configuration.HTTP2
+		expectedProtocols []string
+		expectedHTTP2     bool
+	}{
+		{
+			desc:              "enabled by default",
+			expectedProtocols: []string{"h2", "http/1.1"},
+			expectedHTTP2:     true,
+		},
+		{
+			desc:              "disabled",
+			http2:             &configuration.HTTP2{Disabled: true},
+			expectedProtocols: []string{"http/1.1"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			entryPoint := &configuration.EntryPoint{
+				Address:          "localhost:0",
+				TLS:              &tls.TLS{},
+				ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true},
+				HTTP2:            test.http2,
+			}
+			globalConfig := configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{"https": entryPoint},
+			}
+
+			srv := NewServer(globalConfig, nil)
+			srv.serverEntryPoints = srv.buildEntryPoints(globalConfig)
+			httpServer, listener, err := srv.prepareServer("https", entryPoint, srv.serverEntryPoints["https"], nil, nil)
+			require.NoError(t, err)
+			defer listener.Close()
+
+			assert.Equal(t, test.expectedProtocols, httpServer.TLSConfig.NextProtos)
+			_, ok := httpServer.TLSNextProto[http2.NextProtoTLS]
+			assert.Equal(t, test.expectedHTTP2, ok)
+		})
+	}
+}
+
+func TestPrepareServerHTTP2Cleartext(t *testing.T) {
+	entryPoint := &configuration.EntryPoint{
+		Address:          "127.0.0.1:0",
+		ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true},
+		HTTP2:            &configuration.HTTP2{Cleartext: true},
+	}
+	router := mux.NewRouter()
+	router.NewRoute().Path("/").Handler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(r.Proto))
+	}))
+
+	srv := NewServer(configuration.GlobalConfiguration{}, nil)
+	httpServer, listener, err := srv.prepareServer("http", entryPoint, &serverEntryPoint{httpRouter: middlewares.NewHandlerSwitcher(router)}, nil, nil)
+	require.NoError(t, err)
+	go httpServer.Serve(listener)
+	defer httpServer.Close()
+
+	testCases := []struct {
+		desc          string
+		unencrypted   bool
+		expectedProto string
+	}{
+		{
+			desc:          "prior knowledge",
+			unencrypted:   true,
+			expectedProto: "HTTP/2.0",
+		},
+		{
+			desc:          "HTTP/1.1",
+			expectedProto: "HTTP/1.1",
+		},
+	}
+
+	for _, test := range testCases {
+		var transport http.RoundTripper = &http.Transport{}
+		if test.unencrypted {
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err, test.desc)
+			defer conn.Close()
+			transport, err = (&http2.Transport{}).NewClientConn(conn)
+			require.NoError(t, err, test.desc)
+		}
+		client := &http.Client{Transport: transport}
+
+		resp, err := client.Get("http://" + listener.Addr().String() + "/")
+		require.NoError(t, err, test.desc)
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err, test.desc)
+		assert.Equal(t, test.expectedProto, string(body), test.desc)
+	}
+}
+
 func TestListenProvidersSkipsEmptyConfigs(t *testing.T) {
 	server, stop, invokeStopChan := setupListenProvider(10 * time.Millisecond)
 	defer invokeStopChan()
@@ -554,6 +764,44 @@ func TestServerParseHealthCheckOptions(t *testing.T) {
 				LB:       lb,
 			},
 		},
+		{
+			desc: "TCP with timeout",
+			hc: &types.HealthCheck{
+				Type:    types.HealthCheckTypeTCP,
+				Timeout: "2s",
+			},
+			wantOpts: &healthcheck.Options{
+				Type:     types.HealthCheckTypeTCP,
+				Interval: globalInterval,
+				Timeout:  2 * time.Second,
+				LB:       lb,
+			},
+		},
+		{
+			desc: "unparseable timeout",
+			hc: &types.HealthCheck{
+				Path:    "/path",
+				Timeout: "unparseable",
+			},
+			wantOpts: &healthcheck.Options{
+				Path:     "/path",
+				Interval: globalInterval,
+				LB:       lb,
+			},
+		},
+		{
+			desc: "gRPC without path",
+			hc: &types.HealthCheck{
+				Type:    types.HealthCheckTypeGRPC,
+				Service: "foo.Bar",
+			},
+			wantOpts: &healthcheck.Options{
+				Type:     types.HealthCheckTypeGRPC,
+				Service:  "foo.Bar",
+				Interval: globalInterval,
+				LB:       lb,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -569,10 +817,146 @@ func TestServerParseHealthCheckOptions(t *testing.T) {
 	}
 }
 
+func TestBuildForwardingTimeouts(t *testing.T) {
+	globalTimeouts := &configuration.ForwardingTimeouts{
+		DialTimeout:           flaeg.Duration(30 * time.Second),
+		ResponseHeaderTimeout: flaeg.Duration(10 * time.Second),
+		IdleConnTimeout:       flaeg.Duration(90 * time.Second),
+	}
+
+	tests := []struct {
+		desc     string
+		global   *configuration.ForwardingTimeouts
+		backend  *types.Backend
+		frontend *types.Frontend
+		expected *configuration.ForwardingTimeouts
+	}{
+		{
+			desc:     "no override",
+			global:   globalTimeouts,
+			backend:  &types.Backend{},
+			frontend: &types.Frontend{},
+			expected: globalTimeouts,
+		},
+		{
+			desc:   "backend override",
+			global: globalTimeouts,
+			backend: &types.Backend{
+				ForwardingTimeouts: &types.ForwardingTimeouts{
+					ResponseHeaderTimeout: flaeg.Duration(5 * time.Minute),
+				},
+			},
+			frontend: &types.Frontend{},
+			expected: &configuration.ForwardingTimeouts{
+				DialTimeout:           flaeg.Duration(30 * time.Second),
+				ResponseHeaderTimeout: flaeg.Duration(5 * time.Minute),
+				IdleConnTimeout:       flaeg.Duration(90 * time.Second),
+			},
+		},
+		{
+			desc:   "frontend override takes precedence over backend override",
+			global: globalTimeouts,
+			backend: &types.Backend{
+				ForwardingTimeouts: &types.ForwardingTimeouts{
+					DialTimeout:           flaeg.Duration(5 * time.Second),
+					ResponseHeaderTimeout: flaeg.Duration(5 * time.Minute),
+				},
+			},
+			frontend: &types.Frontend{
+				ForwardingTimeouts: &types.ForwardingTimeouts{
+					ResponseHeaderTimeout: flaeg.Duration(1 * time.Minute),
+					IdleConnTimeout:       flaeg.Duration(10 * time.Second),
+				},
+			},
+			expected: &configuration.ForwardingTimeouts{
+				DialTimeout:           flaeg.Duration(5 * time.Second),
+				ResponseHeaderTimeout: flaeg.Duration(1 * time.Minute),
+				IdleConnTimeout:       flaeg.Duration(10 * time.Second),
+			},
+		},
+		{
+			desc:    "override without global timeouts",
+			global:  nil,
+			backend: nil,
+			frontend: &types.Frontend{
+				ForwardingTimeouts: &types.ForwardingTimeouts{
+					ResponseHeaderTimeout: flaeg.Duration(1 * time.Minute),
+				},
+			},
+			expected: &configuration.ForwardingTimeouts{
+				DialTimeout:           flaeg.Duration(configuration.DefaultDialTimeout),
+				ResponseHeaderTimeout: flaeg.Duration(1 * time.Minute),
+				IdleConnTimeout:       flaeg.Duration(configuration.DefaultIdleConnTimeout),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			timeouts := buildForwardingTimeouts(test.global, test.backend, test.frontend)
+			assert.Equal(t, test.expected, timeouts)
+		})
+	}
+}
+
+func TestBuildHostHeader(t *testing.T) {
+	tests := []struct {
+		desc     string
+		backend  *types.Backend
+		frontend *types.Frontend
+		expected *types.HostHeader
+	}{
+		{
+			desc:     "pass host header",
+			backend:  &types.Backend{},
+			frontend: &types.Frontend{PassHostHeader: true},
+			expected: &types.HostHeader{Strategy: types.HostHeaderPass},
+		},
+		{
+			desc:     "don't pass host header",
+			backend:  nil,
+			frontend: &types.Frontend{},
+			expected: &types.HostHeader{Strategy: types.HostHeaderBackend},
+		},
+		{
+			desc: "backend strategy overrides pass host header",
+			backend: &types.Backend{
+				HostHeader: &types.HostHeader{Strategy: types.HostHeaderCustom, Value: "internal.local"},
+			},
+			frontend: &types.Frontend{PassHostHeader: true},
+			expected: &types.HostHeader{Strategy: types.HostHeaderCustom, Value: "internal.local"},
+		},
+		{
+			desc: "frontend strategy overrides backend strategy",
+			backend: &types.Backend{
+				HostHeader: &types.HostHeader{Strategy: types.HostHeaderCustom, Value: "internal.local"},
+			},
+			frontend: &types.Frontend{
+				HostHeader: &types.HostHeader{Strategy: types.HostHeaderBackend},
+			},
+			expected: &types.HostHeader{Strategy: types.HostHeaderBackend},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			hostHeader := buildHostHeader(test.backend, test.frontend)
+			assert.Equal(t, test.expected, hostHeader)
+		})
+	}
+}
+
 func TestNewServerWithWhitelistSourceRange(t *testing.T) {
 	cases := []struct {
 		desc                 string
 		whitelistStrings     []string
+		ipStrategy           *types.IPStrategy
 		middlewareConfigured bool
 		errMessage           string
 	}{
@@ -596,6 +980,22 @@ func TestNewServerWithWhitelistSourceRange(t *testing.T) {
 			},
 			middlewareConfigured: false,
 			errMessage:           "parsing CIDR whitelist [foo]: parsing CIDR whitelist <nil>: invalid CIDR address: foo",
+		}, {
+			desc: "whitelists configued with an IP strategy",
+			whitelistStrings: []string{
+				"1.2.3.4/24",
+			},
+			ipStrategy:           &types.IPStrategy{Depth: 1},
+			middlewareConfigured: true,
+			errMessage:           "",
+		}, {
+			desc: "invalid IP strategy configued",
+			whitelistStrings: []string{
+				"1.2.3.4/24",
+			},
+			ipStrategy:           &types.IPStrategy{ExcludedIPs: []string{"foo"}},
+			middlewareConfigured: false,
+			errMessage:           "creating IP strategy: parsing CIDR whitelist <nil>: invalid CIDR address: foo",
 		},
 	}
 
@@ -603,7 +1003,7 @@ func TestNewServerWithWhitelistSourceRange(t *testing.T) {
 		tc := tc
 		t.Run(tc.desc, func(t *testing.T) {
 			t.Parallel()
-			middleware, err := configureIPWhitelistMiddleware(tc.whitelistStrings)
+			middleware, err := configureIPWhitelistMiddleware(tc.whitelistStrings, tc.ipStrategy)
 
 			if tc.errMessage != "" {
 				require.EqualError(t, err, tc.errMessage)
@@ -800,10 +1200,12 @@ func TestServerEntryPointWhitelistConfig(t *testing.T) {
 					},
 				},
 				metricsRegistry: metrics.NewVoidRegistry(),
+				routinesPool:    safe.NewPool(context.Background()),
 			}
 
 			srv.serverEntryPoints = srv.buildEntryPoints(srv.globalConfiguration)
-			srvEntryPoint := srv.setupServerEntryPoint("test", srv.serverEntryPoints["test"])
+			srvEntryPoint, err := srv.setupServerEntryPoint("test", srv.serverEntryPoints["test"])
+			require.NoError(t, err)
 			handler := srvEntryPoint.httpServer.Handler.(*mux.Router).NotFoundHandler.(*negroni.Negroni)
 			found := false
 			for _, handler := range handler.Handlers() {
@@ -823,6 +1225,44 @@ func TestServerEntryPointWhitelistConfig(t *testing.T) {
 	}
 }
 
+func TestServerEntryPointHeaders(t *testing.T) {
+	srv := Server{
+		globalConfiguration: configuration.GlobalConfiguration{
+			EntryPoints: map[string]*configuration.EntryPoint{
+				"test": {
+					Address:          ":0",
+					ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true},
+					Headers: &types.Headers{
+						CustomResponseHeaders: map[string]string{"Server": ""},
+						STSSeconds:            31536000,
+						ForceSTSHeader:        true,
+					},
+				},
+			},
+		},
+		metricsRegistry: metrics.NewVoidRegistry(),
+		routinesPool:    safe.NewPool(context.Background()),
+	}
+
+	srv.serverEntryPoints = srv.buildEntryPoints(srv.globalConfiguration)
+	router := mux.NewRouter()
+	router.NewRoute().Path("/app").Handler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Server", "backend")
+		rw.WriteHeader(http.StatusOK)
+	}))
+	srv.serverEntryPoints["test"].httpRouter.UpdateHandler(router)
+	srvEntryPoint, err := srv.setupServerEntryPoint("test", srv.serverEntryPoints["test"])
+	require.NoError(t, err)
+
+	for _, path := range []string{"/app", "/unknown"} {
+		recorder := httptest.NewRecorder()
+		srvEntryPoint.httpServer.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+
+		assert.Equal(t, "max-age=31536000", recorder.Header().Get("Strict-Transport-Security"), path)
+		assert.Empty(t, recorder.Header().Get("Server"), path)
+	}
+}
+
 func TestServerResponseEmptyBackend(t *testing.T) {
 	const requestPath = "/path"
 	const routeRule = "Path:" + requestPath
@@ -1003,7 +1443,7 @@ func TestBuildRedirectHandler(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			rewrite, err := srv.buildRedirectHandler(test.srcEntryPointName, test.redirect)
+			rewrite, err := srv.buildRedirectHandler(test.srcEntryPointName, test.redirect, srv.globalConfiguration.EntryPoints)
 			require.NoError(t, err)
 
 			req := testhelpers.MustNewRequest(http.MethodGet, test.url, nil)
@@ -1204,3 +1644,263 @@ func withLoadBalancer(method string, sticky bool) func(*types.Backend) {
 		}
 	}
 }
+
+func TestServerStickinessAcrossReloads(t *testing.T) {
+	const requestPath = "/path"
+	const routeRule = "Path:" + requestPath
+
+	servers := make(map[string]string)
+	for _, name := range []string{"server1", "server2", "server3"} {
+		name := name
+		testServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("X-Server", name)
+			rw.WriteHeader(http.StatusOK)
+		}))
+		defer testServer.Close()
+		servers[name] = testServer.URL
+	}
+
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+		},
+	}
+	srv := NewServer(globalConfig, nil)
+
+	serve := func(backend *types.Backend, cookie *http.Cookie) (string, *http.Cookie) {
+		dynamicConfigs := types.Configurations{"config": buildDynamicConfig(
+			withFrontend("frontend", buildFrontend(withRoute(requestPath, routeRule))),
+			withBackend("backend", backend),
+		)}
+		entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://localhost"+requestPath, nil)
+		if cookie != nil {
+			request.AddCookie(cookie)
+		}
+		entryPoints["http"].httpRouter.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		for _, c := range recorder.Result().Cookies() {
+			if c.Name == "test" {
+				cookie = c
+			}
+		}
+		return recorder.Header().Get("X-Server"), cookie
+	}
+
+	backend := func(names ...string) *types.Backend {
+		be := buildBackend(withLoadBalancer("Wrr", true))
+		for _, name := range names {
+			// the servers names and URLs are written differently by the providers across reloads
+			be.Servers["renamed-"+name] = types.Server{URL: strings.ToUpper(servers[name]), Weight: 1}
+		}
+		return be
+	}
+
+	initial := buildBackend(withLoadBalancer("Wrr", true))
+	for name, serverURL := range servers {
+		initial.Servers[name] = types.Server{URL: serverURL, Weight: 1}
+	}
+
+	stuck, cookie := serve(initial, nil)
+	require.NotNil(t, cookie)
+
+	for i := 0; i < 5; i++ {
+		server, newCookie := serve(backend("server1", "server2", "server3"), cookie)
+		assert.Equal(t, stuck, server)
+		assert.Equal(t, cookie.Value, newCookie.Value)
+	}
+
+	var remaining []string
+	for name := range servers {
+		if name != stuck {
+			remaining = append(remaining, name)
+		}
+	}
+
+	// only the clients of the removed servers are re-balanced
+	server, newCookie := serve(backend(stuck, remaining[0]), cookie)
+	assert.Equal(t, stuck, server)
+	assert.Equal(t, cookie.Value, newCookie.Value)
+
+	server, newCookie = serve(backend(remaining...), cookie)
+	assert.NotEqual(t, stuck, server)
+	assert.NotEqual(t, cookie.Value, newCookie.Value)
+}
+
+func TestServerAdaptiveLimitAcrossReloads(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+		},
+	}
+	srv := NewServer(globalConfig, nil)
+
+	load := func(adaptiveLimit *types.AdaptiveLimit) map[adaptiveLimiterKey]*middlewares.AdaptiveLimiter {
+		backend := buildBackend(withServer("server", "http://127.0.0.1:80"))
+		backend.AdaptiveLimit = adaptiveLimit
+		dynamicConfigs := types.Configurations{"config": buildDynamicConfig(
+			withFrontend("frontend", buildFrontend(withRoute("/path", "Path:/path"))),
+			withBackend("backend", backend),
+		)}
+		_, err := srv.loadConfig(dynamicConfigs, globalConfig)
+		require.NoError(t, err)
+		return srv.adaptiveLimiters
+	}
+
+	limiters := load(&types.AdaptiveLimit{InitialLimit: 5})
+	require.Len(t, limiters, 1)
+
+	reloaded := load(&types.AdaptiveLimit{InitialLimit: 5})
+	require.Len(t, reloaded, 1)
+	for key, limiter := range limiters {
+		assert.True(t, limiter == reloaded[key], "the limiter of the backend is recreated")
+	}
+
+	changed := load(&types.AdaptiveLimit{InitialLimit: 10})
+	require.Len(t, changed, 1)
+	for key := range limiters {
+		assert.NotContains(t, changed, key)
+	}
+
+	assert.Empty(t, load(nil))
+}
+
+type closeCountingRoundTripper struct {
+	http.RoundTripper
+	closed int
+}
+
+func (c *closeCountingRoundTripper) CloseIdleConnections() {
+	c.closed++
+}
+
+func TestServerRoundTrippersAcrossReloads(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+		},
+	}
+	srv := NewServer(globalConfig, nil)
+
+	load := func(proxyProtocol int) map[roundTripperKey]http.RoundTripper {
+		backend := buildBackend(withServer("server", "http://127.0.0.1:80"))
+		backend.ProxyProtocol = proxyProtocol
+		dynamicConfigs := types.Configurations{"config": buildDynamicConfig(
+			withFrontend("frontend", buildFrontend(withRoute("/path", "Path:/path"))),
+			withBackend("backend", backend),
+		)}
+		_, err := srv.loadConfig(dynamicConfigs, globalConfig)
+		require.NoError(t, err)
+		return srv.forwardingRoundTrippers
+	}
+
+	roundTrippers := load(1)
+	require.Len(t, roundTrippers, 1)
+
+	reloaded := load(1)
+	require.Len(t, reloaded, 1)
+	for key, roundTripper := range roundTrippers {
+		assert.True(t, roundTripper == reloaded[key], "the round tripper of the backend is recreated")
+	}
+
+	unused := &closeCountingRoundTripper{RoundTripper: http.DefaultTransport}
+	for key := range reloaded {
+		srv.forwardingRoundTrippers[key] = unused
+	}
+
+	changed := load(2)
+	require.Len(t, changed, 1)
+	for key := range roundTrippers {
+		assert.NotContains(t, changed, key)
+	}
+	assert.Equal(t, 1, unused.closed)
+
+	assert.Empty(t, load(0))
+}
+
+func TestNormalizeServerURL(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rawURL   string
+		expected string
+	}{
+		{
+			desc:     "already normalized",
+			rawURL:   "http://10.0.0.1:80",
+			expected: "http://10.0.0.1:80",
+		},
+		{
+			desc:     "upper case scheme and host",
+			rawURL:   "HTTP://Backend.Example.COM:8080",
+			expected: "http://backend.example.com:8080",
+		},
+		{
+			desc:     "root path",
+			rawURL:   "http://10.0.0.1:80/",
+			expected: "http://10.0.0.1:80/",
+		},
+		{
+			desc:     "zoned IPv6 address",
+			rawURL:   "http://[FE80::1%25Ethernet0]:80",
+			expected: "http://[fe80::1%25Ethernet0]:80",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			u, err := parseServerURL(test.rawURL)
+			require.NoError(t, err)
+
+			normalizeServerURL(u)
+
+			assert.Equal(t, test.expected, u.String())
+		})
+	}
+}
+
+func TestNewEntryPointDrain(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		drain           *configuration.Drain
+		expectedTimeout time.Duration
+	}{
+		{
+			desc:            "grace timeout of the life cycle",
+			expectedTimeout: 10 * time.Second,
+		},
+		{
+			desc:            "drain without timeout",
+			drain:           &configuration.Drain{RejectRequests: true},
+			expectedTimeout: 10 * time.Second,
+		},
+		{
+			desc:            "drain timeout",
+			drain:           &configuration.Drain{Timeout: flaeg.Duration(time.Minute)},
+			expectedTimeout: time.Minute,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			srv := NewServer(configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{
+					"http": &configuration.EntryPoint{Drain: test.drain},
+				},
+				LifeCycle: &configuration.LifeCycle{GraceTimeOut: flaeg.Duration(10 * time.Second)},
+			}, nil)
+
+			entryPointDrain := srv.newEntryPointDrain("http")
+			assert.Equal(t, test.expectedTimeout, entryPointDrain.Timeout())
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package server
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/containous/staert"
+	"github.com/containous/traefik/cluster"
+	"github.com/containous/traefik/log"
+)
+
+const (
+	defaultsessionTicketKeysRotationInterval = 24 * time.Hour
+	// sessionTicketKeysKept is the number of keys decrypting the tickets, the current one and the previous ones.
+	sessionTicketKeysKept   = 3
+	sessionTicketKeysPrefix = "/sessiontickets"
+)
+
+// sessionTicketKeys rotates the keys of the TLS session tickets, the tickets encrypted with the previous keys
+// being still accepted for the clients to resume their sessions across a rotation.
+// In cluster mode, the leader rotates the keys and shares them through the KV store, encrypted with the configured key,
+// for the sessions to be resumed by any node behind a L4 load balancer.
+type sessionTicketKeys struct {
+	rotationInterval time.Duration
+	lock             sync.RWMutex
+	keys             [][32]byte
+	rotatedAt        time.Time
+	// aead encrypts the keys stored in the KV store, nil without encryption key.
+	aead  cipher.AEAD
+	store cluster.Store
+}
+
+// sessionTicketKeysState holds the keys shared through the KV store, the first one encrypting the new tickets.
+// Each key is encrypted with the encryption key of the configuration, preceded by its nonce.
+type sessionTicketKeysState struct {
+	Keys      [][]byte
+	RotatedAt time.Time
+}
+
+// newSessionTicketKeys creates the keys rotated at the interval, one day if zero,
+// and shared encrypted with the encryption key in cluster mode.
+func newSessionTicketKeys(rotationInterval time.Duration, encryptionKey string) (*sessionTicketKeys, error) {
+	if rotationInterval <= 0 {
+		rotationInterval = defaultsessionTicketKeysRotationInterval
+	}
+	k := &sessionTicketKeys{
+		rotationInterval: rotationInterval,
+	}
+	if len(encryptionKey) > 0 {
+		key := sha256.Sum256([]byte(encryptionKey))
+		block, err := aes.NewCipher(key[:])
+		if err != nil {
+			return nil, err
+		}
+		k.aead, err = cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := newSessionTicketKey()
+	if err != nil {
+		return nil, err
+	}
+	k.rotate(key, time.Now())
+	return k, nil
+}
+
+// apply makes the config encrypt and decrypt its session tickets with the keys.
+// Each handshake is made with a clone of the config, or of the one returned by its GetConfigForClient,
+// given the keys as they are when the handshake starts: a config keeps the keys it is given,
+// including in its clones, while the keys are rotated.
+// It is applied once the GetConfigForClient of the config is set.
+func (k *sessionTicketKeys) apply(config *tls.Config) {
+	getConfigForClient := config.GetConfigForClient
+	config.GetConfigForClient = func(clientHello *tls.ClientHelloInfo) (*tls.Config, error) {
+		handshakeConfig := config
+		if getConfigForClient != nil {
+			clientConfig, err := getConfigForClient(clientHello)
+			if err != nil {
+				return nil, err
+			}
+			if clientConfig != nil {
+				handshakeConfig = clientConfig
+			}
+		}
+
+		handshakeConfig = handshakeConfig.Clone()
+		handshakeConfig.GetConfigForClient = nil
+		k.lock.RLock()
+		handshakeConfig.SetSessionTicketKeys(k.keys)
+		k.lock.RUnlock()
+		return handshakeConfig, nil
+	}
+}
+
+// run rotates the keys at every interval until the context is done.
+func (k *sessionTicketKeys) run(ctx context.Context) {
+	ticker := time.NewTicker(k.rotationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			key, err := newSessionTicketKey()
+			if err != nil {
+				log.Errorf("Unable to rotate the session ticket keys: %v", err)
+				continue
+			}
+			k.rotate(key, time.Now())
+		}
+	}
+}
+
+// share shares the keys through the KV store of the cluster: the leader rotates them at every interval,
+// and the other nodes use the keys it stores.
+func (k *sessionTicketKeys) share(leadership *cluster.Leadership) error {
+	if k.aead == nil {
+		return errors.New("an encryption key is required to store the session ticket keys")
+	}
+
+	listener := func(object cluster.Object) error {
+		return k.setState(object.(*sessionTicketKeysState))
+	}
+
+	datastore, err := cluster.NewDataStore(
+		leadership.Pool.Ctx(),
+		staert.KvSource{
+			Store:  leadership.Store,
+			Prefix: leadership.Store.Prefix + sessionTicketKeysPrefix,
+		},
+		&sessionTicketKeysState{},
+		listener)
+	if err != nil {
+		return err
+	}
+	k.store = datastore
+
+	if object, err := datastore.Load(); err != nil {
+		log.Debugf("No session ticket keys loaded from the KV store: %v", err)
+	} else if err := listener(object); err != nil {
+		return err
+	}
+
+	leadership.AddListener(k.leadershipListener)
+	leadership.Pool.AddGoCtx(k.runLeader)
+	return nil
+}
+
+// leadershipListener rotates the keys of the KV store when the elected node finds them outdated or unreadable.
+// Nothing fails between the beginning of the transaction and its commit, which releases the lock of the KV store.
+func (k *sessionTicketKeys) leadershipListener(elected bool) error {
+	if !elected {
+		return nil
+	}
+	if _, err := k.store.Load(); err != nil {
+		return err
+	}
+	key, err := newSessionTicketKey()
+	if err != nil {
+		return err
+	}
+
+	transaction, object, err := k.store.Begin()
+	if err != nil {
+		return err
+	}
+
+	state := object.(*sessionTicketKeysState)
+	rotate := len(state.Keys) == 0 || time.Since(state.RotatedAt) >= k.rotationInterval
+	if err := k.setState(state); err != nil {
+		log.Warnf("Replacing the session ticket keys of the KV store: %v", err)
+		rotate = true
+	}
+	if rotate {
+		k.rotate(key, time.Now())
+	}
+	return transaction.Commit(k.state())
+}
+
+// runLeader rotates the keys of the KV store at every interval, while the node is the leader.
+func (k *sessionTicketKeys) runLeader(ctx context.Context) {
+	ticker := time.NewTicker(k.rotationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.rotateShared(); err != nil {
+				log.Errorf("Unable to rotate the session ticket keys of the cluster: %v", err)
+			}
+		}
+	}
+}
+
+func (k *sessionTicketKeys) rotateShared() error {
+	key, err := newSessionTicketKey()
+	if err != nil {
+		return err
+	}
+
+	transaction, _, err := k.store.Begin()
+	if err != nil {
+		return err
+	}
+	k.rotate(key, time.Now())
+	return transaction.Commit(k.state())
+}
+
+// newSessionTicketKey generates a session ticket key.
+func newSessionTicketKey() ([32]byte, error) {
+	var key [32]byte
+	_, err := rand.Read(key[:])
+	return key, err
+}
+
+// rotate makes the key encrypt the new tickets, the oldest one being dropped.
+func (k *sessionTicketKeys) rotate(key [32]byte, now time.Time) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	keys := append([][32]byte{key}, k.keys...)
+	if len(keys) > sessionTicketKeysKept {
+		keys = keys[:sessionTicketKeysKept]
+	}
+	k.setKeys(keys, now)
+}
+
+// state returns the keys encrypted for the KV store.
+func (k *sessionTicketKeys) state() *sessionTicketKeysState {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+
+	state := &sessionTicketKeysState{RotatedAt: k.rotatedAt}
+	for _, key := range k.keys {
+		nonce := make([]byte, k.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			// the keys are not stored rather than stored in plaintext
+			log.Errorf("Unable to encrypt the session ticket keys: %v", err)
+			return &sessionTicketKeysState{}
+		}
+		state.Keys = append(state.Keys, k.aead.Seal(nonce, nonce, key[:], nil))
+	}
+	return state
+}
+
+// setState uses the keys of the KV store, ignoring an empty store.
+func (k *sessionTicketKeys) setState(state *sessionTicketKeysState) error {
+	if state == nil || len(state.Keys) == 0 {
+		return nil
+	}
+
+	keys := make([][32]byte, len(state.Keys))
+	for i, sealed := range state.Keys {
+		if len(sealed) < k.aead.NonceSize() {
+			return errors.New("invalid session ticket key length")
+		}
+		key, err := k.aead.Open(nil, sealed[:k.aead.NonceSize()], sealed[k.aead.NonceSize():], nil)
+		if err != nil {
+			return errors.New("unable to decrypt the session ticket keys, the encryption keys of the nodes may differ")
+		}
+		if len(key) != len(keys[i]) {
+			return errors.New("invalid session ticket key length")
+		}
+		copy(keys[i][:], key)
+	}
+
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	k.setKeys(keys, state.RotatedAt)
+	return nil
+}
+
+func (k *sessionTicketKeys) setKeys(keys [][32]byte, rotatedAt time.Time) {
+	k.keys = keys
+	k.rotatedAt = rotatedAt
+}
@@ -0,0 +1,269 @@
+package server
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/cluster"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTicketsServerConfig returns a TLS 1.2 server config whose tickets are encrypted with the keys.
+func newTicketsServerConfig(t *testing.T, keys *sessionTicketKeys) *tls.Config {
+	t.Helper()
+
+	config := newTicketsCertificateConfig(t)
+	keys.apply(config)
+	return config
+}
+
+// newTicketsCertificateConfig returns a TLS 1.2 server config,
+// the certificate being generated as the session of an expired certificate isn't resumed.
+func newTicketsCertificateConfig(t *testing.T) *tls.Config {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "snitest.com"},
+		DNSNames:     []string{"snitest.com"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MaxVersion:   tls.VersionTLS12,
+	}
+}
+
+// resumedHandshake makes a TLS 1.2 handshake with the server config, and returns whether the session was resumed.
+func resumedHandshake(t *testing.T, serverConfig *tls.Config, clientConfig *tls.Config) bool {
+	t.Helper()
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- tls.Server(serverConn, serverConfig).Handshake()
+	}()
+
+	client := tls.Client(clientConn, clientConfig)
+	require.NoError(t, client.Handshake())
+	require.NoError(t, <-errCh)
+	return client.ConnectionState().DidResume
+}
+
+func TestSessionTicketKeysResumption(t *testing.T) {
+	keys, err := newSessionTicketKeys(0, "secret")
+	require.NoError(t, err)
+	otherKeys, err := newSessionTicketKeys(0, "secret")
+	require.NoError(t, err)
+	sharedKeys, err := newSessionTicketKeys(0, "secret")
+	require.NoError(t, err)
+	require.NoError(t, sharedKeys.setState(keys.state()))
+
+	clientConfig := &tls.Config{
+		ServerName:         "snitest.com",
+		InsecureSkipVerify: true,
+		ClientSessionCache: tls.NewLRUClientSessionCache(1),
+	}
+	assert.False(t, resumedHandshake(t, newTicketsServerConfig(t, keys), clientConfig))
+
+	testCases := []struct {
+		desc     string
+		keys     *sessionTicketKeys
+		expected bool
+	}{
+		{
+			desc:     "same keys",
+			keys:     keys,
+			expected: true,
+		},
+		{
+			desc:     "keys shared by another node",
+			keys:     sharedKeys,
+			expected: true,
+		},
+		{
+			desc: "other keys",
+			keys: otherKeys,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			// the client keeps the ticket of the first handshake while its sessions are resumed
+			assert.Equal(t, test.expected, resumedHandshake(t, newTicketsServerConfig(t, test.keys), clientConfig))
+		})
+	}
+}
+
+func TestSessionTicketKeysConfigForClient(t *testing.T) {
+	keys, err := newSessionTicketKeys(0, "secret")
+	require.NoError(t, err)
+
+	// the config returned for the client, e.g. for the TLS options of a frontend, encrypts its tickets with the keys
+	clientHelloConfig := newTicketsCertificateConfig(t)
+	serverConfig := &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return clientHelloConfig, nil
+		},
+	}
+	keys.apply(serverConfig)
+
+	clientConfig := &tls.Config{
+		ServerName:         "snitest.com",
+		InsecureSkipVerify: true,
+		ClientSessionCache: tls.NewLRUClientSessionCache(1),
+	}
+	assert.False(t, resumedHandshake(t, serverConfig, clientConfig))
+	rotateSessionTicketKeys(t, keys)
+	assert.True(t, resumedHandshake(t, newTicketsServerConfig(t, keys), clientConfig))
+}
+
+func rotateSessionTicketKeys(t *testing.T, keys *sessionTicketKeys) {
+	t.Helper()
+
+	key, err := newSessionTicketKey()
+	require.NoError(t, err)
+	keys.rotate(key, time.Now())
+}
+
+func TestSessionTicketKeysRotate(t *testing.T) {
+	keys, err := newSessionTicketKeys(time.Hour, "secret")
+	require.NoError(t, err)
+	serverConfig := newTicketsServerConfig(t, keys)
+
+	clientConfig := &tls.Config{
+		ServerName:         "snitest.com",
+		InsecureSkipVerify: true,
+		ClientSessionCache: tls.NewLRUClientSessionCache(1),
+	}
+	assert.False(t, resumedHandshake(t, serverConfig, clientConfig))
+
+	// the tickets are accepted until their key is dropped
+	first := keys.keys[0]
+	for i := 1; i < sessionTicketKeysKept; i++ {
+		rotateSessionTicketKeys(t, keys)
+		require.Len(t, keys.keys, i+1)
+		assert.Equal(t, first, keys.keys[i])
+	}
+	assert.True(t, resumedHandshake(t, serverConfig, clientConfig))
+
+	clientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(1)
+	assert.False(t, resumedHandshake(t, serverConfig, clientConfig))
+	for i := 0; i < sessionTicketKeysKept; i++ {
+		rotateSessionTicketKeys(t, keys)
+	}
+	assert.Len(t, keys.keys, sessionTicketKeysKept)
+	assert.False(t, resumedHandshake(t, serverConfig, clientConfig))
+}
+
+// sessionTicketKeysStore is a KV store holding the shared keys in memory.
+type sessionTicketKeysStore struct {
+	state     *sessionTicketKeysState
+	committed *sessionTicketKeysState
+}
+
+func (s *sessionTicketKeysStore) Load() (cluster.Object, error) { return s.state, nil }
+
+func (s *sessionTicketKeysStore) Get() cluster.Object { return s.state }
+
+func (s *sessionTicketKeysStore) Begin() (cluster.Transaction, cluster.Object, error) {
+	return s, s.state, nil
+}
+
+func (s *sessionTicketKeysStore) Commit(object cluster.Object) error {
+	s.committed = object.(*sessionTicketKeysState)
+	return nil
+}
+
+func TestSessionTicketKeysLeadershipListener(t *testing.T) {
+	stored, err := newSessionTicketKeys(time.Hour, "secret")
+	require.NoError(t, err)
+
+	otherNode, err := newSessionTicketKeys(time.Hour, "other secret")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc         string
+		state        *sessionTicketKeysState
+		expectedKeys int
+		expectedKept bool
+	}{
+		{
+			desc:         "empty store",
+			state:        &sessionTicketKeysState{},
+			expectedKeys: 2,
+		},
+		{
+			desc:         "keys encrypted with another key",
+			state:        &sessionTicketKeysState{Keys: otherNode.state().Keys, RotatedAt: time.Now().Add(-time.Minute)},
+			expectedKeys: 2,
+		},
+		{
+			desc:         "keys rotated within the interval",
+			state:        &sessionTicketKeysState{Keys: stored.state().Keys, RotatedAt: time.Now().Add(-time.Minute)},
+			expectedKeys: 1,
+			expectedKept: true,
+		},
+		{
+			desc:         "keys rotated before the interval",
+			state:        &sessionTicketKeysState{Keys: stored.state().Keys, RotatedAt: time.Now().Add(-2 * time.Hour)},
+			expectedKeys: 2,
+			expectedKept: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			keys, err := newSessionTicketKeys(time.Hour, "secret")
+			require.NoError(t, err)
+			store := &sessionTicketKeysStore{state: test.state}
+			keys.store = store
+
+			require.NoError(t, keys.leadershipListener(true))
+
+			require.NotNil(t, store.committed)
+			assert.Len(t, store.committed.Keys, test.expectedKeys)
+			for _, sealed := range store.committed.Keys {
+				for _, key := range keys.keys {
+					assert.False(t, bytes.Contains(sealed, key[:]), "the keys are stored in plaintext")
+				}
+			}
+
+			committed, err := newSessionTicketKeys(time.Hour, "secret")
+			require.NoError(t, err)
+			require.NoError(t, committed.setState(store.committed))
+			if test.expectedKept {
+				assert.Equal(t, stored.keys[0], committed.keys[len(committed.keys)-1], "the stored keys are kept")
+			}
+		})
+	}
+}
+
+func TestSessionTicketKeysShareWithoutEncryptionKey(t *testing.T) {
+	keys, err := newSessionTicketKeys(0, "")
+	require.NoError(t, err)
+
+	assert.EqualError(t, keys.share(nil), "an encryption key is required to store the session ticket keys")
+}
@@ -0,0 +1,288 @@
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/proxyprotocol"
+	"github.com/containous/traefik/rules"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+)
+
+// errClientHelloPeeked aborts the handshake used to read the ClientHello.
+var errClientHelloPeeked = errors.New("client hello peeked")
+
+// passthroughRoute forwards the raw connections of a frontend to its backend servers.
+type passthroughRoute struct {
+	frontendName string
+	addresses    []string
+	dialTimeout  time.Duration
+	// proxyProtocol is the version of the PROXY protocol header sent to the servers, 0 to disable it
+	proxyProtocol int
+	next          uint32
+}
+
+// passthroughRoutes maps the SNI server names to the passthrough routes of an entry point.
+type passthroughRoutes map[string]*passthroughRoute
+
+// buildPassthroughRoute creates the passthrough route of a frontend, returning the server names it matches.
+func buildPassthroughRoute(frontendName string, frontend *types.Frontend, backend *types.Backend, dialTimeout time.Duration) (*passthroughRoute, []string, error) {
+	serverNames, err := parseServerNames(frontend)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(serverNames) == 0 {
+		return nil, nil, errors.New("a TLS passthrough frontend requires a Host rule")
+	}
+
+	route, err := newPassthroughRoute(frontendName, frontend, backend, dialTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	return route, serverNames, nil
+}
+
+// parseServerNames returns the server names of the Host rules of a frontend.
+func parseServerNames(frontend *types.Frontend) ([]string, error) {
+	var serverNames []string
+	for _, route := range frontend.Routes {
+		domains, err := (&rules.Rules{}).ParseDomains(route.Rule)
+		if err != nil {
+			return nil, err
+		}
+		serverNames = append(serverNames, domains...)
+	}
+	return serverNames, nil
+}
+
+// newPassthroughRoute creates a route forwarding the connections to the servers of the backend of a frontend.
+func newPassthroughRoute(frontendName string, frontend *types.Frontend, backend *types.Backend, dialTimeout time.Duration) (*passthroughRoute, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("undefined backend '%s'", frontend.Backend)
+	}
+	if backend.ProxyProtocol < 0 || backend.ProxyProtocol > 2 {
+		return nil, fmt.Errorf("invalid PROXY protocol version %d for backend '%s'", backend.ProxyProtocol, frontend.Backend)
+	}
+
+	route := &passthroughRoute{
+		frontendName:  frontendName,
+		dialTimeout:   dialTimeout,
+		proxyProtocol: backend.ProxyProtocol,
+	}
+	for name, srv := range backend.Servers {
+		address, err := passthroughAddress(srv.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL for server %s: %v", name, err)
+		}
+		route.addresses = append(route.addresses, address)
+	}
+	sort.Strings(route.addresses)
+	if len(route.addresses) == 0 {
+		return nil, fmt.Errorf("no server defined for backend '%s'", frontend.Backend)
+	}
+
+	return route, nil
+}
+
+// passthroughAddress returns the TCP address of a server URL, defaulting the port from the scheme.
+func passthroughAddress(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if len(u.Hostname()) == 0 {
+		return "", errors.New("missing host")
+	}
+
+	port := u.Port()
+	if len(port) == 0 {
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		default:
+			return "", fmt.Errorf("missing port for scheme %q", u.Scheme)
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// dial connects to the first backend server accepting the connection, starting in round robin order.
+func (r *passthroughRoute) dial(network string) (net.Conn, error) {
+	start := int(atomic.AddUint32(&r.next, 1))
+	var backendConn net.Conn
+	var err error
+	for i := range r.addresses {
+		address := r.addresses[(start+i)%len(r.addresses)]
+		backendConn, err = net.DialTimeout(network, address, r.dialTimeout)
+		if err == nil {
+			return backendConn, nil
+		}
+		log.Debugf("Error dialing %s for frontend %s: %v", address, r.frontendName, err)
+	}
+	return nil, err
+}
+
+// forward pipes the client connection to a backend server.
+func (r *passthroughRoute) forward(conn net.Conn) {
+	defer conn.Close()
+
+	backendConn, err := r.dial("tcp")
+	if err != nil {
+		log.Errorf("No server available for frontend %s: %v", r.frontendName, err)
+		return
+	}
+	defer backendConn.Close()
+
+	if r.proxyProtocol > 0 {
+		if _, err := backendConn.Write(proxyprotocol.Header(r.proxyProtocol, conn.RemoteAddr(), conn.LocalAddr())); err != nil {
+			log.Debugf("Error sending PROXY protocol header for frontend %s: %v", r.frontendName, err)
+			return
+		}
+	}
+
+	errc := make(chan error, 2)
+	pipe := func(dst, src net.Conn) {
+		_, err := io.Copy(dst, src)
+		if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+			cw.CloseWrite()
+		} else {
+			dst.Close()
+		}
+		errc <- err
+	}
+	go pipe(backendConn, conn)
+	go pipe(conn, backendConn)
+	for i := 0; i < 2; i++ {
+		if err := <-errc; err != nil {
+			log.Debugf("Error forwarding connection for frontend %s: %v", r.frontendName, err)
+			return
+		}
+	}
+}
+
+// sniListener reads the ClientHello of the accepted connections, and forwards them to a passthrough route
+// when their server name matches one, instead of handing them over to the TLS-terminating HTTP server.
+type sniListener struct {
+	net.Listener
+	routes  *safe.Safe
+	timeout time.Duration
+
+	conns chan net.Conn
+	errs  chan error
+	done  chan struct{}
+	err   error
+}
+
+func newSNIListener(listener net.Listener, routes *safe.Safe, timeout time.Duration) *sniListener {
+	l := &sniListener{
+		Listener: listener,
+		routes:   routes,
+		timeout:  timeout,
+		conns:    make(chan net.Conn),
+		errs:     make(chan error),
+		done:     make(chan struct{}),
+	}
+	go l.serve()
+	return l
+}
+
+// Accept returns the next connection which is not passed through.
+func (l *sniListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case err := <-l.errs:
+		return nil, err
+	case <-l.done:
+		return nil, l.err
+	}
+}
+
+func (l *sniListener) serve() {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				l.errs <- err
+				continue
+			}
+			l.err = err
+			close(l.done)
+			return
+		}
+		go l.dispatch(conn)
+	}
+}
+
+func (l *sniListener) dispatch(conn net.Conn) {
+	if l.timeout > 0 {
+		conn.SetReadDeadline(time.Now().Add(l.timeout))
+	}
+	serverName, peeked := peekServerName(conn)
+	conn.SetReadDeadline(time.Time{})
+	conn = &peekedConn{Conn: conn, reader: io.MultiReader(peeked, conn)}
+
+	if routes, ok := l.routes.Get().(passthroughRoutes); ok && len(serverName) > 0 {
+		if route, ok := routes[types.CanonicalDomain(serverName)]; ok {
+			route.forward(conn)
+			return
+		}
+	}
+
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		conn.Close()
+	}
+}
+
+// peekServerName reads the ClientHello from the connection, returning its server name along with the bytes read.
+func peekServerName(conn net.Conn) (string, io.Reader) {
+	peeked := &bytes.Buffer{}
+	var serverName string
+	tls.Server(&peekedConn{Conn: conn, reader: io.TeeReader(conn, peeked), readOnly: true}, &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName = hello.ServerName
+			return nil, errClientHelloPeeked
+		},
+	}).Handshake()
+	return serverName, peeked
+}
+
+// peekedConn reads from a reader replaying the bytes already read from the connection.
+type peekedConn struct {
+	net.Conn
+	reader   io.Reader
+	readOnly bool
+}
+
+func (c *peekedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+func (c *peekedConn) Write(b []byte) (int, error) {
+	if c.readOnly {
+		return 0, io.ErrClosedPipe
+	}
+	return c.Conn.Write(b)
+}
+
+// CloseWrite shuts down the writing side of the underlying connection when it supports it.
+func (c *peekedConn) CloseWrite() error {
+	if cw, ok := c.Conn.(interface{ CloseWrite() error }); ok {
+		return cw.CloseWrite()
+	}
+	return c.Conn.Close()
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/safe"
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassthroughAddress(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		url         string
+		expected    string
+		expectedErr bool
+	}{
+		{
+			desc:     "explicit port",
+			url:      "https://10.0.0.1:8443",
+			expected: "10.0.0.1:8443",
+		},
+		{
+			desc:     "https default port",
+			url:      "https://db.internal",
+			expected: "db.internal:443",
+		},
+		{
+			desc:     "http default port",
+			url:      "http://db.internal",
+			expected: "db.internal:80",
+		},
+		{
+			desc:     "IPv6 address",
+			url:      "https://[::1]:8443",
+			expected: "[::1]:8443",
+		},
+		{
+			desc:     "TCP address",
+			url:      "tcp://mysql.internal:3306",
+			expected: "mysql.internal:3306",
+		},
+		{
+			desc:        "missing host",
+			url:         "/path",
+			expectedErr: true,
+		},
+		{
+			desc:        "missing TCP port",
+			url:         "tcp://mysql.internal",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			address, err := passthroughAddress(test.url)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, address)
+		})
+	}
+}
+
+func TestServerLoadConfigTLSPassthrough(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"https": &configuration.EntryPoint{TLS: &traefikTls.TLS{}, ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+			"http":  &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+		},
+	}
+
+	dynamicConfigs := types.Configurations{
+		"config": &types.Configuration{
+			Frontends: map[string]*types.Frontend{
+				"passthrough": {
+					EntryPoints:    []string{"https", "http"},
+					Backend:        "database",
+					TLSPassthrough: true,
+					Routes: map[string]types.Route{
+						"host": {Rule: "Host:DB.localhost,mq.localhost"},
+					},
+				},
+				"terminated": {
+					EntryPoints: []string{"https"},
+					Backend:     "web",
+					Routes: map[string]types.Route{
+						"host": {Rule: "Host:web.localhost"},
+					},
+				},
+				"no-host": {
+					EntryPoints:    []string{"https"},
+					Backend:        "database",
+					TLSPassthrough: true,
+					Routes: map[string]types.Route{
+						"path": {Rule: "PathPrefix:/db"},
+					},
+				},
+			},
+			Backends: map[string]*types.Backend{
+				"database": {
+					Servers: map[string]types.Server{
+						"server": {URL: "https://10.0.0.1:5432"},
+					},
+				},
+				"web": {
+					Servers: map[string]types.Server{
+						"server": {URL: "http://10.0.0.2"},
+					},
+				},
+			},
+		},
+	}
+
+	srv := NewServer(globalConfig, nil)
+	entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+	require.NoError(t, err)
+
+	routes, ok := entryPoints["https"].passthrough.Get().(passthroughRoutes)
+	require.True(t, ok)
+	require.Len(t, routes, 2)
+	for _, serverName := range []string{"db.localhost", "mq.localhost"} {
+		require.Contains(t, routes, serverName)
+		assert.Equal(t, "passthrough", routes[serverName].frontendName)
+		assert.Equal(t, []string{"10.0.0.1:5432"}, routes[serverName].addresses)
+	}
+
+	assert.Nil(t, entryPoints["http"].passthrough.Get())
+}
+
+func TestSNIListener(t *testing.T) {
+	backendServer := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte("passed through"))
+	}))
+	defer backendServer.Close()
+
+	terminatingServer := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte("terminated"))
+	}))
+	terminatingServer.TLS = &tls.Config{Certificates: backendServer.TLS.Certificates}
+
+	routes := &safe.Safe{}
+	routes.Set(passthroughRoutes{
+		"passthrough.localhost": {
+			frontendName: "passthrough",
+			addresses:    []string{backendServer.Listener.Addr().String()},
+			dialTimeout:  time.Second,
+		},
+	})
+	terminatingServer.Listener = newSNIListener(terminatingServer.Listener, routes, time.Second)
+	terminatingServer.StartTLS()
+	defer terminatingServer.Close()
+
+	testCases := []struct {
+		desc       string
+		serverName string
+		expected   string
+	}{
+		{
+			desc:       "matching server name",
+			serverName: "passthrough.localhost",
+			expected:   "passed through",
+		},
+		{
+			desc:       "matching server name with another case",
+			serverName: "Passthrough.Localhost",
+			expected:   "passed through",
+		},
+		{
+			desc:       "other server name",
+			serverName: "web.localhost",
+			expected:   "terminated",
+		},
+		{
+			desc:     "no server name",
+			expected: "terminated",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			client := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{ServerName: test.serverName, InsecureSkipVerify: true},
+				},
+			}
+
+			resp, err := client.Get(terminatingServer.URL)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(body))
+		})
+	}
+}
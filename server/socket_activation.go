@@ -0,0 +1,133 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/containous/traefik/log"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd to an activated process.
+const listenFDsStart = 3
+
+// activatedSockets holds the sockets passed by systemd, by the name of the entry point they are bound to,
+// as set with the FileDescriptorName option of the socket units.
+var activatedSockets struct {
+	once  sync.Once
+	lock  sync.Mutex
+	files map[string][]*os.File
+}
+
+// activatedSocketFiles returns the sockets passed by systemd for an entry point, if any.
+// The sockets are only returned once, the entry point owning them from then on.
+func activatedSocketFiles(entryPointName string) []*os.File {
+	activatedSockets.once.Do(func() {
+		fds, err := parseListenFDs(os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS"), os.Getenv("LISTEN_FDNAMES"), os.Getpid())
+		if err != nil {
+			log.Errorf("Ignoring the sockets passed by systemd: %v", err)
+		}
+
+		// the sockets are not passed on to the child processes
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+
+		activatedSockets.files = make(map[string][]*os.File, len(fds))
+		for name, nameFDs := range fds {
+			for _, fd := range nameFDs {
+				activatedSockets.files[name] = append(activatedSockets.files[name], os.NewFile(fd, name))
+			}
+		}
+	})
+
+	activatedSockets.lock.Lock()
+	defer activatedSockets.lock.Unlock()
+
+	files := activatedSockets.files[entryPointName]
+	delete(activatedSockets.files, entryPointName)
+	return files
+}
+
+// parseListenFDs returns the file descriptors of the sockets passed by systemd to the process, by name.
+// The sockets are ignored if they are meant for another process, or if they are not named.
+func parseListenFDs(listenPID, listenFDs, listenFDNames string, pid int) (map[string][]uintptr, error) {
+	if len(listenPID) == 0 || len(listenFDs) == 0 {
+		return nil, nil
+	}
+
+	if targetPID, err := strconv.Atoi(listenPID); err != nil {
+		return nil, fmt.Errorf("invalid LISTEN_PID %q: %v", listenPID, err)
+	} else if targetPID != pid {
+		return nil, nil
+	}
+
+	count, err := strconv.Atoi(listenFDs)
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS %q", listenFDs)
+	}
+
+	var names []string
+	if len(listenFDNames) > 0 {
+		names = strings.Split(listenFDNames, ":")
+	}
+
+	fds := make(map[string][]uintptr, count)
+	for i := 0; i < count; i++ {
+		fd := uintptr(listenFDsStart + i)
+		if i >= len(names) || len(names[i]) == 0 || names[i] == "unknown" {
+			log.Warnf("Ignoring the socket %d passed by systemd without FileDescriptorName", fd)
+			continue
+		}
+		fds[names[i]] = append(fds[names[i]], fd)
+	}
+	return fds, nil
+}
+
+// activatedListeners returns the listeners of the stream sockets passed by systemd for an entry point, if any.
+func activatedListeners(entryPointName string) ([]net.Listener, error) {
+	files := activatedSocketFiles(entryPointName)
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	var listeners []net.Listener
+	for _, file := range files {
+		listener, err := net.FileListener(file)
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("invalid socket passed by systemd for entrypoint %s: %v", entryPointName, err)
+		}
+		log.Infof("Using the socket %s passed by systemd for entrypoint %s", listener.Addr(), entryPointName)
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// activatedPacketConn returns the connection of the datagram socket passed by systemd for an entry point, if any.
+func activatedPacketConn(entryPointName string) (net.PacketConn, error) {
+	files := activatedSocketFiles(entryPointName)
+	if len(files) == 0 {
+		return nil, nil
+	}
+	for _, extra := range files[1:] {
+		log.Warnf("Ignoring the additional socket passed by systemd for UDP entrypoint %s", entryPointName)
+		extra.Close()
+	}
+	file := files[0]
+	defer file.Close()
+
+	conn, err := net.FilePacketConn(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid socket passed by systemd for entrypoint %s: %v", entryPointName, err)
+	}
+	log.Infof("Using the socket %s passed by systemd for entrypoint %s", conn.LocalAddr(), entryPointName)
+	return conn, nil
+}
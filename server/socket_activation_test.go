@@ -0,0 +1,80 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListenFDs(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		listenPID     string
+		listenFDs     string
+		listenFDNames string
+		expected      map[string][]uintptr
+		expectedError bool
+	}{
+		{
+			desc: "not activated",
+		},
+		{
+			desc:      "sockets of another process",
+			listenPID: "41",
+			listenFDs: "2",
+		},
+		{
+			desc:          "named sockets",
+			listenPID:     "42",
+			listenFDs:     "2",
+			listenFDNames: "http:dns",
+			expected:      map[string][]uintptr{"http": {3}, "dns": {4}},
+		},
+		{
+			desc:          "unnamed sockets",
+			listenPID:     "42",
+			listenFDs:     "3",
+			listenFDNames: "http:unknown",
+			expected:      map[string][]uintptr{"http": {3}},
+		},
+		{
+			desc:          "sockets sharing a name",
+			listenPID:     "42",
+			listenFDs:     "2",
+			listenFDNames: "http:http",
+			expected:      map[string][]uintptr{"http": {3, 4}},
+		},
+		{
+			desc:          "invalid PID",
+			listenPID:     "me",
+			listenFDs:     "1",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid socket count",
+			listenPID:     "42",
+			listenFDs:     "-1",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			fds, err := parseListenFDs(test.listenPID, test.listenFDs, test.listenFDNames, 42)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if len(test.expected) == 0 {
+				assert.Empty(t, fds)
+				return
+			}
+			assert.Equal(t, test.expected, fds)
+		})
+	}
+}
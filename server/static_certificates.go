@@ -0,0 +1,137 @@
+package server
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	traefikTls "github.com/containous/traefik/tls"
+)
+
+// staticCertificatesReloader reloads the certificates of a TLS entrypoint configuration when their files change:
+// the new handshakes use the last valid certificates, without restarting the entrypoint and dropping its connections.
+type staticCertificatesReloader struct {
+	entryPointName string
+	certificates   traefikTls.Certificates
+	// getDynamicCertificate is the previous callback of the config, returning the dynamic certificates.
+	getDynamicCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	// config holds the reloaded certificates, nil until the first reload.
+	config safe.Safe
+}
+
+func newStaticCertificatesReloader(entryPointName string, certificates traefikTls.Certificates, getDynamicCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) *staticCertificatesReloader {
+	return &staticCertificatesReloader{
+		entryPointName:        entryPointName,
+		certificates:          certificates,
+		getDynamicCertificate: getDynamicCertificate,
+	}
+}
+
+// files returns the certificate and key files, the certificates given as content not being reloaded.
+func (r *staticCertificatesReloader) files() []string {
+	var files []string
+	for _, certificate := range r.certificates {
+		if certificate.CertFile.IsPath() && certificate.KeyFile.IsPath() {
+			files = append(files, certificate.CertFile.String(), certificate.KeyFile.String())
+		}
+	}
+	return files
+}
+
+// getCertificate returns the dynamic certificate matching the client hello, or else the matching reloaded certificate,
+// or nil when none matches, the certificates of the config being used until the first reload.
+func (r *staticCertificatesReloader) getCertificate(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if r.getDynamicCertificate != nil {
+		cert, err := r.getDynamicCertificate(clientHello)
+		if cert != nil || err != nil {
+			return cert, err
+		}
+	}
+
+	config, _ := r.config.Get().(*tls.Config)
+	if config == nil {
+		return nil, nil
+	}
+
+	name := strings.TrimRight(strings.ToLower(clientHello.ServerName), ".")
+	if cert, ok := config.NameToCertificate[name]; ok {
+		return cert, nil
+	}
+	if labels := strings.Split(name, "."); len(labels) > 1 {
+		labels[0] = "*"
+		if cert, ok := config.NameToCertificate[strings.Join(labels, ".")]; ok {
+			return cert, nil
+		}
+	}
+	return nil, nil
+}
+
+// getDefaultCertificate wraps the callback, returning the first reloaded certificate when it returns none,
+// as the default certificate of the config is outdated after a reload.
+func (r *staticCertificatesReloader) getDefaultCertificate(getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		cert, err := getCertificate(clientHello)
+		if cert != nil || err != nil {
+			return cert, err
+		}
+
+		if config, _ := r.config.Get().(*tls.Config); config != nil {
+			return &config.Certificates[0], nil
+		}
+		return nil, nil
+	}
+}
+
+// reloadedCertificates returns the reloaded certificates, nil until the first reload.
+func (r *staticCertificatesReloader) reloadedCertificates() []*tls.Certificate {
+	config, _ := r.config.Get().(*tls.Config)
+	if config == nil {
+		return nil
+	}
+
+	var certs []*tls.Certificate
+	for i := range config.Certificates {
+		certs = append(certs, &config.Certificates[i])
+	}
+	return certs
+}
+
+// reload reads the certificates, keeping the previous ones when one of them is invalid,
+// e.g. while a certificate and its key are being replaced.
+func (r *staticCertificatesReloader) reload() error {
+	config := &tls.Config{}
+	for _, certificate := range r.certificates {
+		certContent, err := certificate.CertFile.Read()
+		if err != nil {
+			return err
+		}
+		keyContent, err := certificate.KeyFile.Read()
+		if err != nil {
+			return err
+		}
+		cert, err := tls.X509KeyPair(certContent, keyContent)
+		if err != nil {
+			return err
+		}
+		config.Certificates = append(config.Certificates, cert)
+	}
+	if len(config.Certificates) == 0 {
+		return nil
+	}
+
+	config.BuildNameToCertificate()
+	r.config.Set(config)
+	return nil
+}
+
+// watch reloads the certificates when their files change.
+func (r *staticCertificatesReloader) watch(pool *safe.Pool) error {
+	return watchFiles(pool, r.files(), func() {
+		if err := r.reload(); err != nil {
+			log.Errorf("Error reloading the certificates of entrypoint %s, keeping the previous ones: %v", r.entryPointName, err)
+			return
+		}
+		log.Infof("Reloaded the certificates of entrypoint %s", r.entryPointName)
+	})
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/safe"
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCertificate(t *testing.T, dir string, domain string) {
+	t.Helper()
+
+	for _, ext := range []string{"cert", "key"} {
+		data, err := ioutil.ReadFile(filepath.Join("..", "integration", "fixtures", "https", domain+"."+ext))
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "server."+ext), data, 0600))
+	}
+}
+
+func certificateCommonName(t *testing.T, cert *tls.Certificate) string {
+	t.Helper()
+
+	require.NotNil(t, cert)
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return parsed.Subject.CommonName
+}
+
+func TestStaticCertificatesReloaderReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certificates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeCertificate(t, dir, "snitest.com")
+
+	reloader := newStaticCertificatesReloader("https", traefikTls.Certificates{{
+		CertFile: traefikTls.FileOrContent(filepath.Join(dir, "server.cert")),
+		KeyFile:  traefikTls.FileOrContent(filepath.Join(dir, "server.key")),
+	}}, nil)
+	assert.Len(t, reloader.files(), 2)
+
+	cert, err := reloader.getCertificate(&tls.ClientHelloInfo{ServerName: "snitest.com"})
+	require.NoError(t, err)
+	assert.Nil(t, cert, "the certificates of the config are used until the first reload")
+
+	writeCertificate(t, dir, "snitest.org")
+	require.NoError(t, reloader.reload())
+
+	cert, err = reloader.getCertificate(&tls.ClientHelloInfo{ServerName: "snitest.org"})
+	require.NoError(t, err)
+	assert.Equal(t, "snitest.org", certificateCommonName(t, cert))
+
+	cert, err = reloader.getCertificate(&tls.ClientHelloInfo{ServerName: "unknown.com"})
+	require.NoError(t, err)
+	assert.Nil(t, cert)
+
+	cert, err = reloader.getDefaultCertificate(reloader.getCertificate)(&tls.ClientHelloInfo{ServerName: "unknown.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "snitest.org", certificateCommonName(t, cert), "the first certificate is the default one")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "server.key"), []byte("not a key"), 0600))
+	assert.Error(t, reloader.reload())
+
+	cert, err = reloader.getCertificate(&tls.ClientHelloInfo{ServerName: "snitest.org"})
+	require.NoError(t, err)
+	assert.Equal(t, "snitest.org", certificateCommonName(t, cert), "the previous certificates are kept when invalid")
+}
+
+func TestStaticCertificatesReloaderStrictSNI(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certificates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeCertificate(t, dir, "snitest.com")
+	cert, err := tls.LoadX509KeyPair(filepath.Join(dir, "server.cert"), filepath.Join(dir, "server.key"))
+	require.NoError(t, err)
+	config := &tls.Config{Certificates: []tls.Certificate{cert}}
+	config.BuildNameToCertificate()
+
+	reloader := newStaticCertificatesReloader("https", traefikTls.Certificates{{
+		CertFile: traefikTls.FileOrContent(filepath.Join(dir, "server.cert")),
+		KeyFile:  traefikTls.FileOrContent(filepath.Join(dir, "server.key")),
+	}}, nil)
+	getCertificate := traefikTls.StrictSNI(config, reloader.getCertificate)
+
+	writeCertificate(t, dir, "snitest.org")
+	require.NoError(t, reloader.reload())
+
+	served, err := getCertificate(&tls.ClientHelloInfo{ServerName: "snitest.org"})
+	require.NoError(t, err)
+	assert.Equal(t, "snitest.org", certificateCommonName(t, served))
+
+	_, err = getCertificate(&tls.ClientHelloInfo{ServerName: "unknown.com"})
+	assert.EqualError(t, err, `strict SNI enabled - no certificate for server name "unknown.com"`)
+}
+
+func TestStaticCertificatesReloaderDynamicCertificates(t *testing.T) {
+	dynamic := &tls.Certificate{}
+	reloader := newStaticCertificatesReloader("https", nil, func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if clientHello.ServerName == "dynamic.com" {
+			return dynamic, nil
+		}
+		return nil, nil
+	})
+	assert.Empty(t, reloader.files())
+
+	cert, err := reloader.getCertificate(&tls.ClientHelloInfo{ServerName: "dynamic.com"})
+	require.NoError(t, err)
+	assert.Equal(t, dynamic, cert)
+
+	cert, err = reloader.getCertificate(&tls.ClientHelloInfo{ServerName: "static.com"})
+	require.NoError(t, err)
+	assert.Nil(t, cert)
+}
+
+func TestStaticCertificatesReloaderWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certificates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeCertificate(t, dir, "snitest.com")
+
+	pool := safe.NewPool(context.Background())
+	defer pool.Stop()
+
+	reloader := newStaticCertificatesReloader("https", traefikTls.Certificates{{
+		CertFile: traefikTls.FileOrContent(filepath.Join(dir, "server.cert")),
+		KeyFile:  traefikTls.FileOrContent(filepath.Join(dir, "server.key")),
+	}}, nil)
+	require.NoError(t, reloader.watch(pool))
+
+	writeCertificate(t, dir, "snitest.org")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		cert, _ := reloader.getCertificate(&tls.ClientHelloInfo{ServerName: "snitest.org"})
+		if cert != nil && certificateCommonName(t, cert) == "snitest.org" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the certificates have not been reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
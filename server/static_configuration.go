@@ -0,0 +1,308 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/drain"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/middlewares"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+	"github.com/sirupsen/logrus"
+)
+
+// SetStaticConfigurationLoader sets the function reading the static configuration again on SIGHUP.
+func (s *Server) SetStaticConfigurationLoader(loader func() (*configuration.GlobalConfiguration, error)) {
+	s.staticConfigurationLoader = loader
+}
+
+// reloadStaticConfiguration reads the static configuration again, for the configuration routine to apply it.
+func (s *Server) reloadStaticConfiguration() {
+	if s.staticConfigurationLoader == nil {
+		log.Warn("The static configuration can't be reloaded")
+		return
+	}
+
+	globalConfiguration, err := s.staticConfigurationLoader()
+	if err != nil {
+		log.Errorf("Error reloading the static configuration, keeping the current one: %v", err)
+		return
+	}
+	s.staticConfigurationChan <- *globalConfiguration
+}
+
+// applyStaticConfiguration applies the log level, the timeouts, the entry points and the providers
+// of a reloaded static configuration, the other options being applied on restart.
+// Only the entry points whose configuration changed are restarted, the others keeping their connections,
+// and the entry points whose server timeouts changed only replace their HTTP server.
+func (s *Server) applyStaticConfiguration(globalConfiguration configuration.GlobalConfiguration) {
+	previous := s.globalConfiguration
+	previousRoundTripper := s.defaultForwardingRoundTripper
+	previousRoundTrippers := s.forwardingRoundTrippers
+
+	next := s.globalConfiguration
+	next.LogLevel = globalConfiguration.LogLevel
+	next.RespondingTimeouts = globalConfiguration.RespondingTimeouts
+	next.ForwardingTimeouts = globalConfiguration.ForwardingTimeouts
+	next.EntryPoints = globalConfiguration.EntryPoints
+	next.EntryPointTemplates = globalConfiguration.EntryPointTemplates
+	next.Constraints = globalConfiguration.Constraints
+
+	if !reflect.DeepEqual(previous.ForwardingTimeouts, next.ForwardingTimeouts) {
+		s.defaultForwardingRoundTripper = createHTTPTransport(next)
+		s.forwardingRoundTrippers = make(map[roundTripperKey]http.RoundTripper)
+	}
+
+	// the current dynamic configuration is loaded with the new entry points and timeouts before applying them
+	newServerEntryPoints, err := s.loadConfig(s.currentConfigurations.Get().(types.Configurations), next)
+	if err != nil {
+		s.defaultForwardingRoundTripper = previousRoundTripper
+		s.forwardingRoundTrippers = previousRoundTrippers
+		log.Errorf("Error applying the reloaded static configuration, keeping the current one: %v", err)
+		return
+	}
+	if previousRoundTripper != s.defaultForwardingRoundTripper {
+		closeIdleConnections(previousRoundTripper)
+		for _, roundTripper := range previousRoundTrippers {
+			closeIdleConnections(roundTripper)
+		}
+	}
+
+	// only the reloaded options are set, the other ones being read by the running routines
+	s.globalConfiguration.LogLevel = next.LogLevel
+	s.globalConfiguration.RespondingTimeouts = next.RespondingTimeouts
+	s.globalConfiguration.ForwardingTimeouts = next.ForwardingTimeouts
+	s.globalConfiguration.EntryPoints = next.EntryPoints
+	s.globalConfiguration.EntryPointTemplates = next.EntryPointTemplates
+	s.globalConfiguration.Constraints = next.Constraints
+
+	if previous.LogLevel != next.LogLevel {
+		if level, err := logrus.ParseLevel(strings.ToLower(next.LogLevel)); err != nil {
+			log.Errorf("Error getting level, keeping the current one: %v", err)
+		} else {
+			log.SetLevel(level)
+		}
+	}
+
+	changed, retimed := changedEntryPoints(previous, next)
+	var restartedEntryPoints []*serverEntryPoint
+	for _, entryPointName := range changed {
+		serverEntryPoint, err := s.restartServerEntryPoint(entryPointName, previous.EntryPoints[entryPointName])
+		if err != nil {
+			log.Errorf("Error restarting entrypoint %s, keeping the current one: %v", entryPointName, err)
+			continue
+		}
+		if serverEntryPoint != nil {
+			restartedEntryPoints = append(restartedEntryPoints, serverEntryPoint)
+		}
+	}
+	for _, entryPointName := range retimed {
+		if err := s.reloadServerTimeouts(entryPointName); err != nil {
+			log.Errorf("Error reloading the timeouts of entrypoint %s, keeping the current ones: %v", entryPointName, err)
+		}
+	}
+	// the restarted entry points are served once their routing is set
+	s.updateServerEntryPoints(newServerEntryPoints)
+	for _, serverEntryPoint := range restartedEntryPoints {
+		s.startServer(serverEntryPoint, s.globalConfiguration)
+	}
+
+	newProvider := configuration.NewProviderAggregator(&globalConfiguration)
+	if jsonConf, err := json.Marshal(newProvider); err != nil || string(jsonConf) != s.providerConfiguration || !reflect.DeepEqual(previous.Constraints, next.Constraints) {
+		log.Info("Restarting the providers")
+		s.providersPool.Cleanup()
+		s.providersPool = safe.NewPool(context.Background())
+		s.provider = newProvider
+		s.startProvider()
+	}
+
+	log.Info("Static configuration reloaded")
+}
+
+// changedEntryPoints returns the sorted names of the entry points added, removed, or whose configuration changed,
+// and the sorted names of the other entry points whose server timeouts changed.
+func changedEntryPoints(previous, globalConfiguration configuration.GlobalConfiguration) (changed, retimed []string) {
+	for entryPointName, entryPoint := range globalConfiguration.EntryPoints {
+		previousEntryPoint, ok := previous.EntryPoints[entryPointName]
+		if !ok || !reflect.DeepEqual(previousEntryPoint, entryPoint) {
+			changed = append(changed, entryPointName)
+			continue
+		}
+
+		previousReadTimeout, previousWriteTimeout, previousIdleTimeout := buildServerTimeouts(previous, previousEntryPoint)
+		readTimeout, writeTimeout, idleTimeout := buildServerTimeouts(globalConfiguration, entryPoint)
+		if previousReadTimeout != readTimeout || previousWriteTimeout != writeTimeout || previousIdleTimeout != idleTimeout {
+			retimed = append(retimed, entryPointName)
+		}
+	}
+	for entryPointName := range previous.EntryPoints {
+		if _, ok := globalConfiguration.EntryPoints[entryPointName]; !ok {
+			changed = append(changed, entryPointName)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(retimed)
+	return changed, retimed
+}
+
+// restartServerEntryPoint replaces the running entry point by a new one built from the reloaded configuration,
+// returning it to be served, or stops the entry point when it was removed.
+// The previous entry point hands over its sockets to the new one, which listens on them before the previous one
+// is stopped, and keeps running when the new one can't be set up.
+// The in-flight requests of the previous entry point are drained meanwhile.
+func (s *Server) restartServerEntryPoint(entryPointName string, previousEntryPoint *configuration.EntryPoint) (*serverEntryPoint, error) {
+	previous, running := s.serverEntryPoints[entryPointName]
+	entryPoint, ok := s.globalConfiguration.EntryPoints[entryPointName]
+	if !ok {
+		log.Infof("Stopping entrypoint %s", entryPointName)
+		s.removeServerEntryPoint(entryPointName)
+		if running {
+			previous.drain.Start()
+			go previous.stop(entryPointName)
+		}
+		return nil, nil
+	}
+
+	log.Infof("Restarting entrypoint %s", entryPointName)
+	newServerEntryPoint := &serverEntryPoint{
+		httpRouter: middlewares.NewHandlerSwitcher(s.buildDefaultHTTPRouter()),
+	}
+	if running {
+		sockets, err := previous.sockets.handOver(entryPoint.ListenAddresses())
+		if err != nil {
+			s.rollbackEntryPoint(entryPointName, previousEntryPoint)
+			return nil, err
+		}
+		newServerEntryPoint.sockets = sockets
+	}
+
+	if _, err := s.setupServerEntryPoint(entryPointName, newServerEntryPoint); err != nil {
+		newServerEntryPoint.sockets.close()
+		if newServerEntryPoint.routinesPool != nil {
+			newServerEntryPoint.routinesPool.Cleanup()
+		}
+		s.rollbackEntryPoint(entryPointName, previousEntryPoint)
+		if running {
+			drain.GetRegistry().Restore(previous.drain)
+			previous.sockets.unlinkOnClose()
+		} else {
+			drain.GetRegistry().Remove(entryPointName)
+		}
+		return nil, err
+	}
+
+	s.serverEntryPointsLock.Lock()
+	s.serverEntryPoints[entryPointName] = newServerEntryPoint
+	s.serverEntryPointsLock.Unlock()
+	newServerEntryPoint.sockets.unlinkOnClose()
+
+	if running {
+		previous.drain.Start()
+		previous.shutdown(entryPointName)
+	}
+	return newServerEntryPoint, nil
+}
+
+// rollbackEntryPoint sets back the configuration of the entry point which couldn't be restarted,
+// removing it when it was added.
+func (s *Server) rollbackEntryPoint(entryPointName string, previousEntryPoint *configuration.EntryPoint) {
+	// the entry points are replaced, not updated, the loaded configuration sharing them
+	entryPoints := make(configuration.EntryPoints, len(s.globalConfiguration.EntryPoints))
+	for name, entryPoint := range s.globalConfiguration.EntryPoints {
+		entryPoints[name] = entryPoint
+	}
+	if previousEntryPoint != nil {
+		entryPoints[entryPointName] = previousEntryPoint
+	} else {
+		delete(entryPoints, entryPointName)
+	}
+	s.globalConfiguration.EntryPoints = entryPoints
+}
+
+// reloadServerTimeouts replaces the HTTP server of the entry point by one with the reloaded timeouts,
+// listening on the sockets handed over by the previous one, which is stopped once its connections are finished.
+func (s *Server) reloadServerTimeouts(entryPointName string) error {
+	sep, ok := s.serverEntryPoints[entryPointName]
+	if !ok || sep.udpForwarder != nil {
+		return nil
+	}
+	entryPoint := s.globalConfiguration.EntryPoints[entryPointName]
+	http2Server, err := buildHTTP2Config(entryPoint.HTTP2)
+	if err != nil {
+		return fmt.Errorf("invalid HTTP/2 configuration: %v", err)
+	}
+
+	sockets, err := sep.sockets.handOver(entryPoint.ListenAddresses())
+	if err != nil {
+		return err
+	}
+	listener, err := s.wrapListeners(entryPointName, entryPoint, sockets.listeners)
+	if err != nil {
+		sockets.close()
+		return err
+	}
+
+	readTimeout, writeTimeout, idleTimeout := buildServerTimeouts(s.globalConfiguration, entryPoint)
+	log.Infof("Reloading the timeouts of entrypoint %s with readTimeout=%s writeTimeout=%s idleTimeout=%s", entryPointName, readTimeout, writeTimeout, idleTimeout)
+	httpServer := &http.Server{
+		Addr:           sep.httpServer.Addr,
+		Handler:        sep.handler,
+		TLSConfig:      sep.tlsConfig,
+		ReadTimeout:    readTimeout,
+		WriteTimeout:   writeTimeout,
+		IdleTimeout:    idleTimeout,
+		MaxHeaderBytes: sep.httpServer.MaxHeaderBytes,
+		ErrorLog:       sep.httpServer.ErrorLog,
+		ConnState:      sep.httpServer.ConnState,
+	}
+	if err := configureHTTP2(httpServer, entryPoint, http2Server); err != nil {
+		sockets.close()
+		return fmt.Errorf("invalid HTTP/2 configuration: %v", err)
+	}
+
+	s.serverEntryPointsLock.Lock()
+	// the previous server is stopped with its own listeners, the routines and the drain going on with the entry point
+	previous := &serverEntryPoint{
+		httpServer:  sep.httpServer,
+		tcpListener: sep.tcpListener,
+		drain:       sep.drain,
+	}
+	sep.serve(httpServer, listener, sep.tcpListener != nil)
+	sep.sockets = sockets
+	s.serverEntryPointsLock.Unlock()
+	sockets.unlinkOnClose()
+
+	previous.shutdown(entryPointName)
+	s.startServer(sep, s.globalConfiguration)
+	return nil
+}
+
+func (s *Server) removeServerEntryPoint(entryPointName string) {
+	s.serverEntryPointsLock.Lock()
+	delete(s.serverEntryPoints, entryPointName)
+	s.serverEntryPointsLock.Unlock()
+	drain.GetRegistry().Remove(entryPointName)
+}
+
+// shutdown stops the entry point in the background, returning once it doesn't accept connections anymore,
+// for the entry point listening on the sockets it handed over to accept all their connections.
+func (sep *serverEntryPoint) shutdown(serverEntryPointName string) {
+	if sep.httpServer == nil {
+		go sep.stop(serverEntryPointName)
+		return
+	}
+
+	closed := make(chan struct{})
+	sep.httpServer.RegisterOnShutdown(func() {
+		close(closed)
+	})
+	go sep.stop(serverEntryPointName)
+	<-closed
+}
@@ -0,0 +1,243 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/drain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+)
+
+func TestChangedEntryPoints(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		entryPoints        configuration.EntryPoints
+		respondingTimeouts *configuration.RespondingTimeouts
+		expected           []string
+		expectedRetimed    []string
+	}{
+		{
+			desc: "same entry points",
+			entryPoints: configuration.EntryPoints{
+				"http":  {Address: ":80"},
+				"https": {Address: ":443", Compress: true},
+			},
+		},
+		{
+			desc: "entry point added and removed",
+			entryPoints: configuration.EntryPoints{
+				"http":    {Address: ":80"},
+				"traefik": {Address: ":8080"},
+			},
+			expected: []string{"https", "traefik"},
+		},
+		{
+			desc: "address changed",
+			entryPoints: configuration.EntryPoints{
+				"http":  {Address: ":8000"},
+				"https": {Address: ":443", Compress: true},
+			},
+			expected: []string{"http"},
+		},
+		{
+			desc: "option changed",
+			entryPoints: configuration.EntryPoints{
+				"http":  {Address: ":80"},
+				"https": {Address: ":443"},
+			},
+			expected: []string{"https"},
+		},
+		{
+			desc: "global responding timeouts changed",
+			entryPoints: configuration.EntryPoints{
+				"http": {Address: ":80"},
+				"https": {
+					Address:            ":443",
+					Compress:           true,
+					RespondingTimeouts: &configuration.RespondingTimeouts{ReadTimeout: flaeg.Duration(time.Second)},
+				},
+			},
+			respondingTimeouts: &configuration.RespondingTimeouts{ReadTimeout: flaeg.Duration(time.Minute)},
+			expected:           []string{"https"},
+			expectedRetimed:    []string{"http"},
+		},
+		{
+			desc: "only global responding timeouts changed",
+			entryPoints: configuration.EntryPoints{
+				"http":  {Address: ":80"},
+				"https": {Address: ":443", Compress: true},
+			},
+			respondingTimeouts: &configuration.RespondingTimeouts{IdleTimeout: flaeg.Duration(time.Second)},
+			expectedRetimed:    []string{"http", "https"},
+		},
+	}
+
+	previous := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"http":  {Address: ":80"},
+			"https": {Address: ":443", Compress: true},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			globalConfiguration := configuration.GlobalConfiguration{
+				EntryPoints:        test.entryPoints,
+				RespondingTimeouts: test.respondingTimeouts,
+			}
+			changed, retimed := changedEntryPoints(previous, globalConfiguration)
+			assert.Equal(t, test.expected, changed)
+			assert.Equal(t, test.expectedRetimed, retimed)
+		})
+	}
+}
+
+func TestApplyStaticConfiguration(t *testing.T) {
+	forwardedHeaders := &configuration.ForwardedHeaders{Insecure: true}
+	globalConfiguration := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"reload-web":    {Address: "127.0.0.1:0", ForwardedHeaders: forwardedHeaders},
+			"reload-admin":  {Address: "127.0.0.1:0", ForwardedHeaders: forwardedHeaders},
+			"reload-legacy": {Address: "127.0.0.1:0", ForwardedHeaders: forwardedHeaders},
+		},
+		LifeCycle: &configuration.LifeCycle{GraceTimeOut: flaeg.Duration(time.Second)},
+	}
+	srv := NewServer(globalConfiguration, nil)
+	srv.startHTTPServers()
+	defer srv.Stop()
+
+	previous := make(serverEntryPoints)
+	for entryPointName, serverEntryPoint := range srv.serverEntryPoints {
+		previous[entryPointName] = serverEntryPoint
+	}
+	previousRoundTripper := srv.defaultForwardingRoundTripper
+
+	reloaded := globalConfiguration
+	reloaded.EntryPoints = configuration.EntryPoints{
+		"reload-web":   {Address: "127.0.0.1:0", ForwardedHeaders: forwardedHeaders, Compress: true},
+		"reload-admin": {Address: "127.0.0.1:0", ForwardedHeaders: forwardedHeaders},
+		"reload-other": {Address: "127.0.0.1:0", ForwardedHeaders: forwardedHeaders},
+	}
+	reloaded.ForwardingTimeouts = &configuration.ForwardingTimeouts{DialTimeout: flaeg.Duration(time.Second)}
+	srv.applyStaticConfiguration(reloaded)
+
+	require.Len(t, srv.serverEntryPoints, 3)
+	assert.True(t, previous["reload-admin"] == srv.serverEntryPoints["reload-admin"], "unchanged entry point restarted")
+	assert.True(t, previous["reload-web"] != srv.serverEntryPoints["reload-web"], "changed entry point not restarted")
+	assert.NotContains(t, srv.serverEntryPoints, "reload-legacy")
+	assert.True(t, previousRoundTripper != srv.defaultForwardingRoundTripper, "forwarding round tripper not replaced")
+
+	var registered []string
+	for _, status := range drain.GetRegistry().Statuses() {
+		registered = append(registered, status.EntryPoint)
+	}
+	assert.Contains(t, registered, "reload-other")
+	assert.NotContains(t, registered, "reload-legacy")
+
+	// the restarted entry point listens on the socket handed over by the previous one
+	assert.Equal(t, previous["reload-web"].listener.Addr().String(), srv.serverEntryPoints["reload-web"].listener.Addr().String())
+	for _, entryPointName := range []string{"reload-web", "reload-other"} {
+		resp, err := http.Get("http://" + srv.serverEntryPoints[entryPointName].listener.Addr().String())
+		require.NoError(t, err, entryPointName)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, entryPointName)
+	}
+
+	// the routines of the replaced and removed entry points are stopped with them
+	for _, entryPointName := range []string{"reload-web", "reload-legacy"} {
+		select {
+		case <-previous[entryPointName].routinesPool.Ctx().Done():
+		case <-time.After(5 * time.Second):
+			t.Errorf("routines of entrypoint %s not stopped", entryPointName)
+		}
+	}
+}
+
+func TestApplyStaticConfigurationTimeouts(t *testing.T) {
+	globalConfiguration := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"timeouts-web": {Address: "127.0.0.1:0", ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+			"timeouts-h2c": {
+				Address:          "127.0.0.1:0",
+				ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true},
+				HTTP2:            &configuration.HTTP2{Cleartext: true},
+			},
+		},
+		LifeCycle: &configuration.LifeCycle{GraceTimeOut: flaeg.Duration(time.Second)},
+	}
+	srv := NewServer(globalConfiguration, nil)
+	srv.startHTTPServers()
+	defer srv.Stop()
+
+	previous := srv.serverEntryPoints["timeouts-web"]
+	previousHTTPServer := previous.httpServer
+
+	reloaded := globalConfiguration
+	reloaded.RespondingTimeouts = &configuration.RespondingTimeouts{ReadTimeout: flaeg.Duration(time.Minute)}
+	srv.applyStaticConfiguration(reloaded)
+
+	serverEntryPoint := srv.serverEntryPoints["timeouts-web"]
+	assert.True(t, previous == serverEntryPoint, "entry point restarted")
+	assert.True(t, previousHTTPServer != serverEntryPoint.httpServer, "HTTP server not replaced")
+	assert.Equal(t, time.Minute, serverEntryPoint.httpServer.ReadTimeout)
+
+	resp, err := http.Get("http://" + serverEntryPoint.listener.Addr().String())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// the reloaded server still serves HTTP/2 cleartext
+	h2cServerEntryPoint := srv.serverEntryPoints["timeouts-h2c"]
+	assert.Equal(t, time.Minute, h2cServerEntryPoint.httpServer.ReadTimeout)
+	conn, err := net.Dial("tcp", h2cServerEntryPoint.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	clientConn, err := (&http2.Transport{}).NewClientConn(conn)
+	require.NoError(t, err)
+	resp, err = (&http.Client{Transport: clientConn}).Get("http://" + h2cServerEntryPoint.listener.Addr().String())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 2, resp.ProtoMajor)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestApplyStaticConfigurationRollback(t *testing.T) {
+	entryPoint := &configuration.EntryPoint{Address: "127.0.0.1:0", ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}}
+	globalConfiguration := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{"rollback-web": entryPoint},
+		LifeCycle:   &configuration.LifeCycle{GraceTimeOut: flaeg.Duration(time.Second)},
+	}
+	srv := NewServer(globalConfiguration, nil)
+	srv.startHTTPServers()
+	defer srv.Stop()
+
+	previous := srv.serverEntryPoints["rollback-web"]
+
+	reloaded := globalConfiguration
+	reloaded.EntryPoints = configuration.EntryPoints{
+		"rollback-web": {
+			Address:          "127.0.0.1:0",
+			ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true},
+			ProxyProtocol:    &configuration.ProxyProtocol{TrustedIPs: []string{"invalid"}},
+		},
+	}
+	srv.applyStaticConfiguration(reloaded)
+
+	assert.True(t, previous == srv.serverEntryPoints["rollback-web"], "entry point not kept")
+	assert.True(t, entryPoint == srv.globalConfiguration.EntryPoints["rollback-web"], "configuration not rolled back")
+	assert.NoError(t, previous.routinesPool.Ctx().Err())
+
+	resp, err := http.Get("http://" + previous.listener.Addr().String())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
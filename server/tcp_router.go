@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+)
+
+// tcpPeekTimeout bounds the wait for the ClientHello of the connections to a TCP entry point with server name routes,
+// the connections of the clients waiting for the server to speak first going to the default route afterwards.
+const tcpPeekTimeout = time.Second
+
+// tcpRoute forwards the connections of a frontend of a TCP entry point to its backend servers,
+// terminating their TLS first when the entry point uses TLS and the frontend doesn't pass it through.
+type tcpRoute struct {
+	*passthroughRoute
+	terminate bool
+}
+
+// tcpRoutes holds the routes of the frontends of a TCP entry point.
+type tcpRoutes struct {
+	// sni maps the SNI server names to the routes of the frontends with a Host rule
+	sni map[string]*tcpRoute
+	// defaultRoute receives the connections matching no server name, the entry point port being its only rule
+	defaultRoute *tcpRoute
+}
+
+// wireTCPFrontend adds the route of a frontend to a TCP entry point.
+func (s *Server) wireTCPFrontend(serverEntryPoint *serverEntryPoint, entryPointName, frontendName string, frontend *types.Frontend, backend *types.Backend, globalConfiguration configuration.GlobalConfiguration) error {
+	entryPoint := globalConfiguration.EntryPoints[entryPointName]
+
+	dialTimeout := configuration.DefaultDialTimeout
+	if timeouts := buildForwardingTimeouts(globalConfiguration.ForwardingTimeouts, backend, frontend); timeouts != nil {
+		dialTimeout = time.Duration(timeouts.DialTimeout)
+	}
+
+	route, serverNames, err := buildTCPRoute(frontendName, frontend, backend, dialTimeout)
+	if err != nil {
+		return err
+	}
+	route.terminate = entryPoint.TLS != nil && !frontend.TLSPassthrough
+
+	routes, _ := serverEntryPoint.tcp.Get().(*tcpRoutes)
+	if routes == nil {
+		routes = &tcpRoutes{sni: map[string]*tcpRoute{}}
+		serverEntryPoint.tcp.Set(routes)
+	}
+
+	if len(serverNames) == 0 {
+		if routes.defaultRoute != nil {
+			return fmt.Errorf("entrypoint %s already forwards its connections to frontend %s", entryPointName, routes.defaultRoute.frontendName)
+		}
+		log.Debugf("Forwarding TCP connections to backend %s on entryPoint %s", frontend.Backend, entryPointName)
+		routes.defaultRoute = route
+		return nil
+	}
+
+	for _, serverName := range serverNames {
+		if existing, ok := routes.sni[serverName]; ok {
+			log.Warnf("Server name %s of frontend %s is already routed to frontend %s", serverName, frontendName, existing.frontendName)
+			continue
+		}
+		log.Debugf("Forwarding TCP connections for %s to backend %s on entryPoint %s", serverName, frontend.Backend, entryPointName)
+		routes.sni[serverName] = route
+	}
+	return nil
+}
+
+// buildTCPRoute creates the route of a frontend of a TCP entry point, returning the server names it matches, if any.
+func buildTCPRoute(frontendName string, frontend *types.Frontend, backend *types.Backend, dialTimeout time.Duration) (*tcpRoute, []string, error) {
+	serverNames, err := parseServerNames(frontend)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	route, err := newPassthroughRoute(frontendName, frontend, backend, dialTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &tcpRoute{passthroughRoute: route}, serverNames, nil
+}
+
+// tcpListener dispatches the connections accepted on a TCP entry point to the routes of its frontends.
+// It never hands a connection over to the HTTP server of the entry point,
+// which only manages the lifecycle of the listener.
+type tcpListener struct {
+	net.Listener
+	routes      *safe.Safe
+	tlsConfig   *tls.Config
+	timeout     time.Duration
+	peekTimeout time.Duration
+
+	lock  sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+func newTCPListener(listener net.Listener, routes *safe.Safe, tlsConfig *tls.Config, timeout time.Duration) *tcpListener {
+	if tlsConfig != nil {
+		tlsConfig = tlsConfig.Clone()
+		// the ALPN protocols of the HTTP server don't apply to the forwarded connections
+		tlsConfig.NextProtos = nil
+		if getConfigForClient := tlsConfig.GetConfigForClient; getConfigForClient != nil {
+			tlsConfig.GetConfigForClient = func(clientHello *tls.ClientHelloInfo) (*tls.Config, error) {
+				config, err := getConfigForClient(clientHello)
+				if err != nil || config == nil {
+					return config, err
+				}
+				config = config.Clone()
+				config.NextProtos = nil
+				return config, nil
+			}
+		}
+	}
+	return &tcpListener{
+		Listener:    listener,
+		routes:      routes,
+		tlsConfig:   tlsConfig,
+		timeout:     timeout,
+		peekTimeout: tcpPeekTimeout,
+		conns:       make(map[net.Conn]struct{}),
+	}
+}
+
+// Accept forwards the accepted connections until the listener fails.
+func (l *tcpListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		l.track(conn, true)
+		go func() {
+			defer l.track(conn, false)
+			l.dispatch(conn)
+		}()
+	}
+}
+
+func (l *tcpListener) track(conn net.Conn, active bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if active {
+		l.conns[conn] = struct{}{}
+		l.wg.Add(1)
+		return
+	}
+	delete(l.conns, conn)
+	l.wg.Done()
+}
+
+// drain waits for the forwarded connections to be closed, closing the remaining ones when the context is done.
+func (l *tcpListener) drain(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		l.lock.Lock()
+		for conn := range l.conns {
+			conn.Close()
+		}
+		l.lock.Unlock()
+	}
+}
+
+func (l *tcpListener) dispatch(conn net.Conn) {
+	routes, _ := l.routes.Get().(*tcpRoutes)
+	if routes == nil {
+		log.Debugf("No frontend for TCP connection from %s", conn.RemoteAddr())
+		conn.Close()
+		return
+	}
+
+	route := routes.defaultRoute
+	// the ClientHello is only read when needed, and not waited for long, as the server speaks first with some protocols
+	if len(routes.sni) > 0 {
+		peekTimeout := l.peekTimeout
+		if l.timeout > 0 && l.timeout < peekTimeout {
+			peekTimeout = l.timeout
+		}
+		conn.SetReadDeadline(time.Now().Add(peekTimeout))
+		serverName, peeked := peekServerName(conn)
+		conn.SetReadDeadline(time.Time{})
+		conn = &peekedConn{Conn: conn, reader: io.MultiReader(peeked, conn)}
+
+		if sniRoute, ok := routes.sni[types.CanonicalDomain(serverName)]; ok && len(serverName) > 0 {
+			route = sniRoute
+		}
+	}
+	if route == nil {
+		log.Debugf("No frontend for TCP connection from %s", conn.RemoteAddr())
+		conn.Close()
+		return
+	}
+
+	if route.terminate {
+		tlsConn := tls.Server(conn, l.tlsConfig)
+		if l.timeout > 0 {
+			tlsConn.SetDeadline(time.Now().Add(l.timeout))
+		}
+		if err := tlsConn.Handshake(); err != nil {
+			log.Debugf("Error during TLS handshake for frontend %s: %v", route.frontendName, err)
+			conn.Close()
+			return
+		}
+		tlsConn.SetDeadline(time.Time{})
+		conn = tlsConn
+	}
+
+	route.forward(conn)
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"bufio"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/safe"
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerLoadConfigTCP(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"mysql": &configuration.EntryPoint{Protocol: "tcp", ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+			"tls":   &configuration.EntryPoint{Protocol: "tcp", TLS: &traefikTls.TLS{}, ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+			"http":  &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+		},
+	}
+
+	dynamicConfigs := types.Configurations{
+		"config": &types.Configuration{
+			Frontends: map[string]*types.Frontend{
+				"mysql": {
+					EntryPoints: []string{"mysql"},
+					Backend:     "mysql",
+				},
+				"mysql-replica": {
+					EntryPoints: []string{"mysql"},
+					Backend:     "mysql",
+				},
+				"mqtt": {
+					EntryPoints: []string{"tls"},
+					Backend:     "mqtt",
+					Routes: map[string]types.Route{
+						"host": {Rule: "Host:MQTT.localhost"},
+					},
+				},
+				"postgres": {
+					EntryPoints:    []string{"tls"},
+					Backend:        "postgres",
+					TLSPassthrough: true,
+					Routes: map[string]types.Route{
+						"host": {Rule: "Host:db.localhost"},
+					},
+				},
+				"no-port": {
+					EntryPoints: []string{"tls"},
+					Backend:     "no-port",
+				},
+			},
+			Backends: map[string]*types.Backend{
+				"mysql": {
+					Servers: map[string]types.Server{
+						"server": {URL: "tcp://10.0.0.1:3306"},
+					},
+				},
+				"mqtt": {
+					Servers: map[string]types.Server{
+						"server": {URL: "tcp://10.0.0.2:1883"},
+					},
+				},
+				"postgres": {
+					Servers: map[string]types.Server{
+						"server": {URL: "tcp://10.0.0.3:5432"},
+					},
+				},
+				"no-port": {
+					Servers: map[string]types.Server{
+						"server": {URL: "tcp://10.0.0.4"},
+					},
+				},
+			},
+		},
+	}
+
+	srv := NewServer(globalConfig, nil)
+	entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+	require.NoError(t, err)
+
+	mysqlRoutes, ok := entryPoints["mysql"].tcp.Get().(*tcpRoutes)
+	require.True(t, ok)
+	assert.Empty(t, mysqlRoutes.sni)
+	require.NotNil(t, mysqlRoutes.defaultRoute)
+	// the frontends are wired in the alphabetical order, the second default route being rejected
+	assert.Equal(t, "mysql", mysqlRoutes.defaultRoute.frontendName)
+	assert.Equal(t, []string{"10.0.0.1:3306"}, mysqlRoutes.defaultRoute.addresses)
+	assert.False(t, mysqlRoutes.defaultRoute.terminate)
+
+	tlsRoutes, ok := entryPoints["tls"].tcp.Get().(*tcpRoutes)
+	require.True(t, ok)
+	assert.Nil(t, tlsRoutes.defaultRoute)
+	require.Len(t, tlsRoutes.sni, 2)
+	require.Contains(t, tlsRoutes.sni, "mqtt.localhost")
+	assert.Equal(t, []string{"10.0.0.2:1883"}, tlsRoutes.sni["mqtt.localhost"].addresses)
+	assert.True(t, tlsRoutes.sni["mqtt.localhost"].terminate)
+	require.Contains(t, tlsRoutes.sni, "db.localhost")
+	assert.Equal(t, []string{"10.0.0.3:5432"}, tlsRoutes.sni["db.localhost"].addresses)
+	assert.False(t, tlsRoutes.sni["db.localhost"].terminate)
+
+	assert.Nil(t, entryPoints["http"].tcp.Get())
+}
+
+func TestTCPListener(t *testing.T) {
+	certServer := httptest.NewTLSServer(nil)
+	certServer.Close()
+	tlsConfig := &tls.Config{Certificates: certServer.TLS.Certificates}
+
+	plainBackend := startTCPBackend(t, "plain", nil, false)
+	defer plainBackend.Close()
+	greetingBackend := startTCPBackend(t, "greeting", nil, true)
+	defer greetingBackend.Close()
+	tlsBackend := startTCPBackend(t, "tls", tlsConfig, false)
+	defer tlsBackend.Close()
+
+	newRoute := func(backend net.Listener, terminate bool) *tcpRoute {
+		return &tcpRoute{
+			passthroughRoute: &passthroughRoute{
+				frontendName: "frontend",
+				addresses:    []string{backend.Addr().String()},
+				dialTimeout:  time.Second,
+			},
+			terminate: terminate,
+		}
+	}
+
+	testCases := []struct {
+		desc       string
+		routes     *tcpRoutes
+		tls        bool
+		serverName string
+		greeting   bool
+		expected   string
+	}{
+		{
+			desc:     "default route",
+			routes:   &tcpRoutes{defaultRoute: newRoute(plainBackend, false)},
+			expected: "plain: ping",
+		},
+		{
+			desc:     "default route of a server speaking first",
+			routes:   &tcpRoutes{defaultRoute: newRoute(greetingBackend, false)},
+			greeting: true,
+			expected: "greeting: ping",
+		},
+		{
+			desc: "terminated TLS",
+			routes: &tcpRoutes{sni: map[string]*tcpRoute{
+				"mqtt.localhost": newRoute(plainBackend, true),
+				"db.localhost":   newRoute(tlsBackend, false),
+			}},
+			tls:        true,
+			serverName: "mqtt.localhost",
+			expected:   "plain: ping",
+		},
+		{
+			desc: "passed through TLS",
+			routes: &tcpRoutes{sni: map[string]*tcpRoute{
+				"mqtt.localhost": newRoute(plainBackend, true),
+				"db.localhost":   newRoute(tlsBackend, false),
+			}},
+			tls:        true,
+			serverName: "DB.localhost",
+			expected:   "tls: ping",
+		},
+		{
+			desc: "unknown server name",
+			routes: &tcpRoutes{
+				sni:          map[string]*tcpRoute{"db.localhost": newRoute(tlsBackend, false)},
+				defaultRoute: newRoute(plainBackend, true),
+			},
+			tls:        true,
+			serverName: "other.localhost",
+			expected:   "plain: ping",
+		},
+		{
+			desc: "no TLS with server name routes",
+			routes: &tcpRoutes{
+				sni:          map[string]*tcpRoute{"db.localhost": newRoute(tlsBackend, false)},
+				defaultRoute: newRoute(plainBackend, false),
+			},
+			expected: "plain: ping",
+		},
+		{
+			desc: "server speaking first with server name routes",
+			routes: &tcpRoutes{
+				sni:          map[string]*tcpRoute{"db.localhost": newRoute(tlsBackend, false)},
+				defaultRoute: newRoute(greetingBackend, false),
+			},
+			greeting: true,
+			expected: "greeting: ping",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			routes := &safe.Safe{}
+			routes.Set(test.routes)
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			tcpListener := newTCPListener(listener, routes, tlsConfig, 0)
+			tcpListener.peekTimeout = 100 * time.Millisecond
+			go tcpListener.Accept()
+			defer tcpListener.Close()
+
+			var conn net.Conn
+			if test.tls {
+				conn, err = tls.Dial("tcp", listener.Addr().String(), &tls.Config{ServerName: test.serverName, InsecureSkipVerify: true})
+			} else {
+				conn, err = net.Dial("tcp", listener.Addr().String())
+			}
+			require.NoError(t, err)
+			defer conn.Close()
+
+			reader := bufio.NewReader(conn)
+			if test.greeting {
+				greeting, err := reader.ReadString('\n')
+				require.NoError(t, err)
+				assert.Equal(t, "hello\n", greeting)
+			}
+
+			_, err = conn.Write([]byte("ping\n"))
+			require.NoError(t, err)
+
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			assert.Equal(t, test.expected+"\n", line)
+		})
+	}
+}
+
+func TestTCPListenerNoRoute(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	tcpListener := newTCPListener(listener, &safe.Safe{}, nil, time.Second)
+	go tcpListener.Accept()
+	defer tcpListener.Close()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+}
+
+// startTCPBackend starts a server answering each line with its name, greeting its clients first if requested.
+func startTCPBackend(t *testing.T, name string, tlsConfig *tls.Config, greeting bool) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if greeting {
+					conn.Write([]byte("hello\n"))
+				}
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil {
+					return
+				}
+				conn.Write([]byte(name + ": " + line))
+			}()
+		}
+	}()
+	return listener
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/metrics"
+	"github.com/containous/traefik/middlewares"
+	"github.com/containous/traefik/types"
+	"github.com/urfave/negroni"
+)
+
+// tenants holds the middlewares of the tenants, shared by all their frontends
+// so that their quotas and metrics apply to the tenants as a whole.
+type tenants struct {
+	tenancy         *types.Tenancy
+	metricsRegistry metrics.Registry
+	middlewares     map[string][]negroni.Handler
+}
+
+func newTenants(tenancy *types.Tenancy, metricsRegistry metrics.Registry) *tenants {
+	return &tenants{
+		tenancy:         tenancy,
+		metricsRegistry: metricsRegistry,
+		middlewares:     make(map[string][]negroni.Handler),
+	}
+}
+
+// wrap applies the quota and the metrics of the tenant to the handler of one of its frontends.
+func (t *tenants) wrap(tenant string, handler http.Handler) http.Handler {
+	handlers, ok := t.middlewares[tenant]
+	if !ok {
+		if t.metricsRegistry.IsEnabled() {
+			handlers = append(handlers, middlewares.NewTenantMetricsMiddleware(t.metricsRegistry, tenant))
+		}
+		if quota := t.tenancy.Quota(tenant); quota != nil && (quota.MaxRPS > 0 || quota.MaxConnections > 0) {
+			handlers = append(handlers, middlewares.NewTenantQuota(quota, tenant, t.metricsRegistry))
+		}
+		t.middlewares[tenant] = handlers
+	}
+
+	if len(handlers) == 0 {
+		return handler
+	}
+	n := negroni.New(handlers...)
+	n.UseHandler(handler)
+	return n
+}
+
+// frontendsOverQuota returns the frontends exceeding the maximum number of frontends of their tenant,
+// keyed by provider and frontend names.
+// The frontends are counted in the order of their providers and names, for the result to be stable across reloads.
+func (t *tenants) frontendsOverQuota(configurations types.Configurations) map[string]bool {
+	overQuota := make(map[string]bool)
+	if t.tenancy == nil {
+		return overQuota
+	}
+
+	var providerNames []string
+	for providerName := range configurations {
+		providerNames = append(providerNames, providerName)
+	}
+	sort.Strings(providerNames)
+
+	counts := make(map[string]int)
+	for _, providerName := range providerNames {
+		config := configurations[providerName]
+		for _, frontendName := range sortedFrontendNamesForConfig(config) {
+			tenant := config.Frontends[frontendName].Tenant
+			if len(tenant) == 0 {
+				continue
+			}
+
+			quota := t.tenancy.Quota(tenant)
+			counts[tenant]++
+			if quota != nil && quota.MaxFrontends > 0 && counts[tenant] > quota.MaxFrontends {
+				log.Errorf("Frontend %s exceeding the quota of %d frontends of tenant %s", frontendName, quota.MaxFrontends, tenant)
+				overQuota[providerName+frontendName] = true
+			}
+		}
+	}
+	return overQuota
+}
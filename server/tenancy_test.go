@@ -0,0 +1,64 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/metrics"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantsFrontendsOverQuota(t *testing.T) {
+	configurations := types.Configurations{
+		"docker": &types.Configuration{
+			Frontends: map[string]*types.Frontend{
+				"frontend-a": {Tenant: "team-a"},
+				"frontend-b": {Tenant: "team-a"},
+				"frontend-c": {Tenant: "team-b"},
+				"frontend-d": {},
+			},
+		},
+		"file": &types.Configuration{
+			Frontends: map[string]*types.Frontend{
+				"frontend-a": {Tenant: "team-a"},
+				"frontend-e": {Tenant: "team-c"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		tenancy  *types.Tenancy
+		expected map[string]bool
+	}{
+		{
+			desc:     "no tenancy",
+			expected: map[string]bool{},
+		},
+		{
+			desc: "specific quota",
+			tenancy: &types.Tenancy{
+				Quotas: types.TenantQuotas{{Tenant: "team-a", MaxFrontends: 2}},
+			},
+			expected: map[string]bool{"filefrontend-a": true},
+		},
+		{
+			desc: "default quota",
+			tenancy: &types.Tenancy{
+				DefaultQuota: &types.TenantQuota{MaxFrontends: 1},
+				Quotas:       types.TenantQuotas{{Tenant: "team-c", MaxFrontends: 0}},
+			},
+			expected: map[string]bool{"dockerfrontend-b": true, "filefrontend-a": true},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tenants := newTenants(test.tenancy, metrics.NewVoidRegistry())
+			assert.Equal(t, test.expected, tenants.frontendsOverQuota(configurations))
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/containous/traefik/log"
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+)
+
+// frontendTLSOption is the named TLS options selected by a frontend, applied to the handshakes of its server names
+// instead of the TLS options of the entry point.
+type frontendTLSOption struct {
+	frontendName string
+	name         string
+	options      *traefikTls.Options
+}
+
+// frontendTLSOptions maps the SNI server names to the TLS options of the frontends of a TLS entry point.
+type frontendTLSOptions map[string]*frontendTLSOption
+
+// mergeTLSOptions returns the named TLS options of the configurations of all the providers,
+// the first provider defining a name in alphabetical order winning.
+func mergeTLSOptions(configurations types.Configurations) map[string]*traefikTls.Options {
+	tlsOptions := make(map[string]*traefikTls.Options)
+	for _, providerName := range sortedProviderNames(configurations) {
+		for name, options := range configurations[providerName].TLSOptions {
+			if _, ok := tlsOptions[name]; ok {
+				log.Warnf("TLS options %s of provider %s already defined by another provider", name, providerName)
+				continue
+			}
+			tlsOptions[name] = options
+		}
+	}
+	return tlsOptions
+}
+
+// wireFrontendTLSOptions registers the named TLS options selected by a frontend for the server names of its Host rules on a TLS entry point.
+func wireFrontendTLSOptions(serverEntryPoint *serverEntryPoint, tlsOptions map[string]*traefikTls.Options, frontendName string, frontend *types.Frontend) error {
+	options, ok := tlsOptions[frontend.TLSOptions]
+	if !ok || options == nil {
+		return fmt.Errorf("unknown TLS options %s", frontend.TLSOptions)
+	}
+	if err := options.Apply(&tls.Config{}); err != nil {
+		return fmt.Errorf("invalid TLS options %s: %v", frontend.TLSOptions, err)
+	}
+
+	serverNames, err := parseServerNames(frontend)
+	if err != nil {
+		return err
+	}
+	if len(serverNames) == 0 {
+		return errors.New("a frontend with TLS options requires a Host rule")
+	}
+
+	frontendOptions, _ := serverEntryPoint.tlsOptions.Get().(frontendTLSOptions)
+	if frontendOptions == nil {
+		frontendOptions = frontendTLSOptions{}
+		serverEntryPoint.tlsOptions.Set(frontendOptions)
+	}
+	for _, serverName := range serverNames {
+		serverName = types.CanonicalDomain(serverName)
+		if existing, ok := frontendOptions[serverName]; ok && existing.name != frontend.TLSOptions {
+			log.Warnf("The TLS options %s of frontend %s for %s replace the TLS options %s of frontend %s", frontend.TLSOptions, frontendName, serverName, existing.name, existing.frontendName)
+		}
+		frontendOptions[serverName] = &frontendTLSOption{
+			frontendName: frontendName,
+			name:         frontend.TLSOptions,
+			options:      options,
+		}
+	}
+	return nil
+}
+
+func (o frontendTLSOptions) match(serverName string) *frontendTLSOption {
+	for _, name := range serverNameCandidates(serverName) {
+		if option, ok := o[name]; ok {
+			return option
+		}
+	}
+	return nil
+}
+
+// getConfigForClient returns the config applying the TLS options and requesting the client certificates of the frontends
+// matching the server name, or else the config of the next callback, nil for the base config.
+func (sep *serverEntryPoint) getConfigForClient(base *tls.Config, next func(*tls.ClientHelloInfo) (*tls.Config, error)) func(*tls.ClientHelloInfo) (*tls.Config, error) {
+	return func(clientHello *tls.ClientHelloInfo) (*tls.Config, error) {
+		var config *tls.Config
+		if next != nil {
+			var err error
+			config, err = next(clientHello)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		tlsOptions, _ := sep.tlsOptions.Get().(frontendTLSOptions)
+		tlsOption := tlsOptions.match(clientHello.ServerName)
+		clientCAs, _ := sep.clientCAs.Get().(frontendClientCAs)
+		clientCA := clientCAs.match(clientHello.ServerName)
+		if tlsOption == nil && clientCA == nil {
+			return config, nil
+		}
+
+		if config == nil {
+			config = base
+		}
+		config = config.Clone()
+		if tlsOption != nil {
+			if err := tlsOption.options.Apply(config); err != nil {
+				return nil, err
+			}
+		}
+		if clientCA != nil {
+			config.ClientCAs = clientCA.pool
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+			if clientCA.optional {
+				config.ClientAuth = tls.VerifyClientCertIfGiven
+			}
+		}
+		config.GetConfigForClient = nil
+		return config, nil
+	}
+}
+
+// serverNameCandidates returns the names matching a SNI server name, the exact one first, then its wildcard.
+func serverNameCandidates(serverName string) []string {
+	name := strings.TrimRight(types.CanonicalDomain(serverName), ".")
+	if len(name) == 0 {
+		return nil
+	}
+
+	candidates := []string{name}
+	if labels := strings.Split(name, "."); len(labels) > 1 {
+		labels[0] = "*"
+		candidates = append(candidates, strings.Join(labels, "."))
+	}
+	return candidates
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"crypto/tls"
+	"testing"
+
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeTLSOptions(t *testing.T) {
+	modern := &traefikTls.Options{MinVersion: "VersionTLS12"}
+	legacy := &traefikTls.Options{MinVersion: "VersionTLS10"}
+
+	tlsOptions := mergeTLSOptions(types.Configurations{
+		"file":   {TLSOptions: map[string]*traefikTls.Options{"modern": modern}},
+		"docker": {TLSOptions: map[string]*traefikTls.Options{"modern": legacy, "legacy": legacy}},
+		"rest":   {},
+	})
+
+	assert.Equal(t, map[string]*traefikTls.Options{"modern": legacy, "legacy": legacy}, tlsOptions)
+}
+
+func TestWireFrontendTLSOptions(t *testing.T) {
+	tlsOptions := map[string]*traefikTls.Options{
+		"modern":  {MinVersion: "VersionTLS12", CurvePreferences: []string{"X25519"}},
+		"invalid": {MinVersion: "VersionSSL30"},
+	}
+
+	testCases := []struct {
+		desc          string
+		frontend      *types.Frontend
+		expectedError string
+	}{
+		{
+			desc: "known TLS options",
+			frontend: &types.Frontend{
+				TLSOptions: "modern",
+				Routes:     map[string]types.Route{"route": {Rule: "Host:secure.example.com"}},
+			},
+		},
+		{
+			desc: "unknown TLS options",
+			frontend: &types.Frontend{
+				TLSOptions: "unknown",
+				Routes:     map[string]types.Route{"route": {Rule: "Host:secure.example.com"}},
+			},
+			expectedError: "unknown TLS options unknown",
+		},
+		{
+			desc: "invalid TLS options",
+			frontend: &types.Frontend{
+				TLSOptions: "invalid",
+				Routes:     map[string]types.Route{"route": {Rule: "Host:secure.example.com"}},
+			},
+			expectedError: "invalid TLS options invalid: invalid TLS minimum version: VersionSSL30",
+		},
+		{
+			desc: "no Host rule",
+			frontend: &types.Frontend{
+				TLSOptions: "modern",
+				Routes:     map[string]types.Route{"route": {Rule: "PathPrefix:/api"}},
+			},
+			expectedError: "a frontend with TLS options requires a Host rule",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			sep := &serverEntryPoint{}
+			err := wireFrontendTLSOptions(sep, tlsOptions, "frontend", test.frontend)
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+				assert.Nil(t, sep.tlsOptions.Get())
+				return
+			}
+			require.NoError(t, err)
+
+			base := &tls.Config{MinVersion: tls.VersionTLS10}
+			config, err := sep.getConfigForClient(base, nil)(&tls.ClientHelloInfo{ServerName: "secure.example.com"})
+			require.NoError(t, err)
+			require.NotNil(t, config)
+			assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+			assert.Equal(t, []tls.CurveID{tls.X25519}, config.CurvePreferences)
+			assert.Equal(t, uint16(tls.VersionTLS10), base.MinVersion, "the base config is left unchanged")
+
+			config, err = sep.getConfigForClient(base, nil)(&tls.ClientHelloInfo{ServerName: "other.example.com"})
+			require.NoError(t, err)
+			assert.Nil(t, config)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/containous/traefik/types"
+)
+
+// buildTrafficSplitServers returns the servers of the alternate backend of the traffic split of a frontend.
+func buildTrafficSplitServers(config *types.Configuration, split *types.TrafficSplit) ([]*url.URL, error) {
+	if split.Percentage < 0 || split.Percentage > 100 {
+		return nil, fmt.Errorf("invalid traffic split percentage %d, must be between 0 and 100", split.Percentage)
+	}
+	if len(split.Header) == 0 && len(split.Cookie) == 0 {
+		return nil, errors.New("traffic split requires a header or a cookie identifying the users")
+	}
+
+	backend, ok := config.Backends[split.Backend]
+	if !ok {
+		return nil, fmt.Errorf("undefined traffic split backend '%s'", split.Backend)
+	}
+
+	var servers []*url.URL
+	for _, srv := range backend.Servers {
+		u, err := parseServerURL(srv.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing server URL %s: %v", srv.URL, err)
+		}
+		normalizeServerURL(u)
+		servers = append(servers, u)
+	}
+	return servers, nil
+}
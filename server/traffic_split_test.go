@@ -0,0 +1,80 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTrafficSplitServers(t *testing.T) {
+	config := &types.Configuration{
+		Backends: map[string]*types.Backend{
+			"alternate": {
+				Servers: map[string]types.Server{
+					"server1": {URL: "http://ALTERNATE1:80"},
+					"server2": {URL: "http://alternate2:80"},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc          string
+		split         *types.TrafficSplit
+		expected      []string
+		expectedError bool
+	}{
+		{
+			desc:     "servers of the alternate backend",
+			split:    &types.TrafficSplit{Backend: "alternate", Percentage: 10, Header: "X-User-ID"},
+			expected: []string{"http://alternate1:80", "http://alternate2:80"},
+		},
+		{
+			desc:     "cookie identifying the users",
+			split:    &types.TrafficSplit{Backend: "alternate", Percentage: 10, Cookie: "user"},
+			expected: []string{"http://alternate1:80", "http://alternate2:80"},
+		},
+		{
+			desc:          "undefined alternate backend",
+			split:         &types.TrafficSplit{Backend: "missing", Percentage: 10, Header: "X-User-ID"},
+			expectedError: true,
+		},
+		{
+			desc:          "percentage over 100",
+			split:         &types.TrafficSplit{Backend: "alternate", Percentage: 110, Header: "X-User-ID"},
+			expectedError: true,
+		},
+		{
+			desc:          "negative percentage",
+			split:         &types.TrafficSplit{Backend: "alternate", Percentage: -1, Header: "X-User-ID"},
+			expectedError: true,
+		},
+		{
+			desc:          "no header nor cookie",
+			split:         &types.TrafficSplit{Backend: "alternate", Percentage: 10},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			servers, err := buildTrafficSplitServers(config, test.split)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var actual []string
+			for _, server := range servers {
+				actual = append(actual, server.String())
+			}
+			assert.ElementsMatch(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+)
+
+// udpBufferSize is the size of the largest UDP datagram.
+const udpBufferSize = 65535
+
+// errNoUDPFrontend is returned when a datagram is received on a UDP entry point without frontend.
+var errNoUDPFrontend = errors.New("no frontend")
+
+// wireUDPFrontend sets the frontend of a UDP entry point, which receives all its datagrams.
+func (s *Server) wireUDPFrontend(serverEntryPoint *serverEntryPoint, entryPointName, frontendName string, frontend *types.Frontend, backend *types.Backend, globalConfiguration configuration.GlobalConfiguration) error {
+	if existing, ok := serverEntryPoint.udp.Get().(*passthroughRoute); ok {
+		return fmt.Errorf("entrypoint %s already forwards its datagrams to frontend %s", entryPointName, existing.frontendName)
+	}
+
+	dialTimeout := configuration.DefaultDialTimeout
+	if timeouts := buildForwardingTimeouts(globalConfiguration.ForwardingTimeouts, backend, frontend); timeouts != nil {
+		dialTimeout = time.Duration(timeouts.DialTimeout)
+	}
+
+	route, err := newPassthroughRoute(frontendName, frontend, backend, dialTimeout)
+	if err != nil {
+		return err
+	}
+	if len(frontend.Routes) > 0 {
+		log.Warnf("The routes of frontend %s are ignored on UDP entrypoint %s", frontendName, entryPointName)
+	}
+	if route.proxyProtocol > 0 {
+		log.Warnf("The PROXY protocol of backend %s is ignored on UDP entrypoint %s", frontend.Backend, entryPointName)
+	}
+
+	log.Debugf("Forwarding UDP datagrams to backend %s on entryPoint %s", frontend.Backend, entryPointName)
+	serverEntryPoint.udp.Set(route)
+	return nil
+}
+
+// udpForwarder forwards the datagrams received on a UDP entry point to the servers of its frontend.
+// The datagrams of a client are sent to the same server as long as its session is active,
+// the responses of the server being sent back to the client.
+type udpForwarder struct {
+	conn           net.PacketConn
+	route          *safe.Safe
+	sessionTimeout time.Duration
+
+	lock     sync.Mutex
+	sessions map[string]net.Conn
+	wg       sync.WaitGroup
+
+	done   chan struct{}
+	served chan struct{}
+}
+
+func newUDPForwarder(conn net.PacketConn, route *safe.Safe, sessionTimeout time.Duration) *udpForwarder {
+	return &udpForwarder{
+		conn:           conn,
+		route:          route,
+		sessionTimeout: sessionTimeout,
+		sessions:       make(map[string]net.Conn),
+		done:           make(chan struct{}),
+		served:         make(chan struct{}),
+	}
+}
+
+// serve forwards the received datagrams until the forwarder is shut down.
+func (f *udpForwarder) serve() error {
+	defer close(f.served)
+
+	buffer := make([]byte, udpBufferSize)
+	for {
+		n, clientAddr, err := f.conn.ReadFrom(buffer)
+		if err != nil {
+			select {
+			case <-f.done:
+				return nil
+			default:
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return err
+		}
+
+		backendConn, err := f.session(clientAddr)
+		if err != nil {
+			log.Debugf("Dropping UDP datagram from %s: %v", clientAddr, err)
+			continue
+		}
+
+		backendConn.SetReadDeadline(time.Now().Add(f.sessionTimeout))
+		if _, err := backendConn.Write(buffer[:n]); err != nil {
+			log.Debugf("Error forwarding UDP datagram from %s: %v", clientAddr, err)
+		}
+	}
+}
+
+// session returns the connection to the server of a client, creating it if the client has no active session.
+func (f *udpForwarder) session(clientAddr net.Addr) (net.Conn, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if backendConn, ok := f.sessions[clientAddr.String()]; ok {
+		return backendConn, nil
+	}
+
+	route, ok := f.route.Get().(*passthroughRoute)
+	if !ok {
+		return nil, errNoUDPFrontend
+	}
+	backendConn, err := route.dial("udp")
+	if err != nil {
+		return nil, err
+	}
+
+	f.sessions[clientAddr.String()] = backendConn
+	f.wg.Add(1)
+	go f.reply(clientAddr, backendConn)
+	return backendConn, nil
+}
+
+// reply sends the responses of the server back to the client, until the session times out.
+func (f *udpForwarder) reply(clientAddr net.Addr, backendConn net.Conn) {
+	defer f.wg.Done()
+	defer func() {
+		f.lock.Lock()
+		delete(f.sessions, clientAddr.String())
+		f.lock.Unlock()
+		backendConn.Close()
+	}()
+
+	buffer := make([]byte, udpBufferSize)
+	for {
+		n, err := backendConn.Read(buffer)
+		if err != nil {
+			return
+		}
+		backendConn.SetReadDeadline(time.Now().Add(f.sessionTimeout))
+		if _, err := f.conn.WriteTo(buffer[:n], clientAddr); err != nil {
+			log.Debugf("Error sending UDP datagram to %s: %v", clientAddr, err)
+			return
+		}
+	}
+}
+
+// shutdown stops receiving datagrams, and waits for the active sessions to time out
+// before closing the entry point, the remaining sessions being closed when the context is done.
+func (f *udpForwarder) shutdown(ctx context.Context) {
+	close(f.done)
+	f.conn.SetReadDeadline(time.Now())
+	<-f.served
+
+	done := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		f.lock.Lock()
+		for _, backendConn := range f.sessions {
+			backendConn.Close()
+		}
+		f.lock.Unlock()
+		<-done
+	}
+	f.conn.Close()
+}
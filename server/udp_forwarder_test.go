@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerLoadConfigUDP(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"dns":  &configuration.EntryPoint{Protocol: "udp", ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+			"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+		},
+	}
+
+	dynamicConfigs := types.Configurations{
+		"config": &types.Configuration{
+			Frontends: map[string]*types.Frontend{
+				"dns": {
+					EntryPoints: []string{"dns"},
+					Backend:     "dns",
+				},
+				"dns-secondary": {
+					EntryPoints: []string{"dns"},
+					Backend:     "dns",
+				},
+			},
+			Backends: map[string]*types.Backend{
+				"dns": {
+					Servers: map[string]types.Server{
+						"server1": {URL: "udp://10.0.0.2:53"},
+						"server2": {URL: "udp://10.0.0.1:53"},
+					},
+				},
+			},
+		},
+	}
+
+	srv := NewServer(globalConfig, nil)
+	entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+	require.NoError(t, err)
+
+	route, ok := entryPoints["dns"].udp.Get().(*passthroughRoute)
+	require.True(t, ok)
+	// the frontends are wired in the alphabetical order, the second one being rejected
+	assert.Equal(t, "dns", route.frontendName)
+	assert.Equal(t, []string{"10.0.0.1:53", "10.0.0.2:53"}, route.addresses)
+
+	assert.Nil(t, entryPoints["http"].udp.Get())
+}
+
+func TestUDPForwarder(t *testing.T) {
+	backend1 := startUDPBackend(t, "backend1")
+	defer backend1.Close()
+	backend2 := startUDPBackend(t, "backend2")
+	defer backend2.Close()
+
+	route := &safe.Safe{}
+	route.Set(&passthroughRoute{
+		frontendName: "dns",
+		addresses:    []string{backend1.LocalAddr().String(), backend2.LocalAddr().String()},
+		dialTimeout:  time.Second,
+	})
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	forwarder := newUDPForwarder(conn, route, time.Second)
+	served := make(chan error, 1)
+	go func() {
+		served <- forwarder.serve()
+	}()
+
+	client1, err := net.Dial("udp", forwarder.conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer client1.Close()
+	client2, err := net.Dial("udp", forwarder.conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer client2.Close()
+
+	first := exchangeUDP(t, client1, "query1")
+	// the datagrams of a client are sent to the server of its session
+	assert.Equal(t, first, exchangeUDP(t, client1, "query2"))
+	// the sessions of the clients are balanced across the servers
+	assert.NotEqual(t, first, exchangeUDP(t, client2, "query1"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	forwarder.shutdown(ctx)
+	assert.NoError(t, <-served)
+	assert.Empty(t, forwarder.sessions)
+}
+
+func TestUDPForwarderNoFrontend(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	forwarder := newUDPForwarder(conn, &safe.Safe{}, time.Second)
+	go forwarder.serve()
+	defer forwarder.shutdown(context.Background())
+
+	client, err := net.Dial("udp", forwarder.conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Write([]byte("query"))
+	require.NoError(t, err)
+
+	client.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, err = client.Read(make([]byte, udpBufferSize))
+	assert.Error(t, err)
+	assert.Empty(t, forwarder.sessions)
+}
+
+// exchangeUDP sends a datagram and returns the name of the server which answered it.
+func exchangeUDP(t *testing.T, conn net.Conn, query string) string {
+	_, err := conn.Write([]byte(query))
+	require.NoError(t, err)
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	buffer := make([]byte, udpBufferSize)
+	n, err := conn.Read(buffer)
+	require.NoError(t, err)
+
+	fields := strings.Fields(string(buffer[:n]))
+	require.Len(t, fields, 2)
+	assert.Equal(t, query, fields[1])
+	return fields[0]
+}
+
+// startUDPBackend starts a server answering each datagram with its name followed by the datagram.
+func startUDPBackend(t *testing.T, name string) net.PacketConn {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		buffer := make([]byte, udpBufferSize)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			conn.WriteTo([]byte(name+" "+string(buffer[:n])), addr)
+		}
+	}()
+	return conn
+}
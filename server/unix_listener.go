@@ -0,0 +1,134 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/whitelist"
+)
+
+// unixClientAddr is the address of the clients of the entry points listening on a Unix domain socket.
+// It is not an IP address, for the clients to only be trusted or whitelisted when the socket is listed explicitly.
+type unixClientAddr struct{}
+
+func (unixClientAddr) Network() string {
+	return "unix"
+}
+
+func (unixClientAddr) String() string {
+	return net.JoinHostPort(whitelist.UnixSocket, "0")
+}
+
+// listen opens the listeners of an entry point, one per address, on TCP addresses or on Unix domain sockets,
+// unless systemd passed sockets for the entry point.
+// The sockets handed over by the entry point it replaces are used instead, the handed over sockets being closed on error.
+func listen(entryPointName string, entryPoint *configuration.EntryPoint, handedOver *entryPointSockets) (*entryPointSockets, error) {
+	if handedOver != nil && handedOver.packetConn != nil {
+		// the entry point replaced was a UDP one
+		handedOver.close()
+		handedOver = nil
+	}
+	if handedOver.activated() {
+		return handedOver, nil
+	}
+
+	listeners, err := activatedListeners(entryPointName)
+	if err != nil {
+		handedOver.close()
+		return nil, err
+	}
+	if len(listeners) > 0 {
+		handedOver.close()
+		for i, listener := range listeners {
+			if _, ok := listener.(*net.UnixListener); ok {
+				listeners[i] = &unixListener{Listener: listener}
+			}
+		}
+		return &entryPointSockets{listeners: listeners}, nil
+	}
+
+	sockets := &entryPointSockets{addresses: entryPoint.ListenAddresses()}
+	for _, listenAddress := range sockets.addresses {
+		listener := handedOver.listener(listenAddress)
+		if listener == nil {
+			listener, err = listenOn(listenAddress)
+		}
+		if err != nil {
+			sockets.close()
+			handedOver.close()
+			return nil, err
+		}
+		sockets.listeners = append(sockets.listeners, listener)
+	}
+	return sockets, nil
+}
+
+func listenOn(listenAddress configuration.ListenAddress) (net.Listener, error) {
+	if listenAddress.Network != "unix" {
+		return net.Listen(listenAddress.Network, listenAddress.Address)
+	}
+
+	// the socket left over by a previous process which wasn't stopped gracefully is removed
+	if info, err := os.Lstat(listenAddress.Address); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s already exists and is not a socket", listenAddress.Address)
+		}
+		if err := os.Remove(listenAddress.Address); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := net.Listen(listenAddress.Network, listenAddress.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &unixListener{Listener: listener}, nil
+}
+
+// listenPacket opens the connection of a UDP entry point, unless systemd passed a socket for the entry point
+// or the entry point it replaces handed over its socket.
+func listenPacket(entryPointName string, entryPoint *configuration.EntryPoint, handedOver *entryPointSockets) (*entryPointSockets, error) {
+	if handedOver != nil && handedOver.packetConn != nil {
+		return handedOver, nil
+	}
+	// the entry point replaced wasn't a UDP one
+	handedOver.close()
+
+	conn, err := activatedPacketConn(entryPointName)
+	if err != nil {
+		return nil, err
+	}
+	if conn != nil {
+		return &entryPointSockets{packetConn: conn}, nil
+	}
+	conn, err = net.ListenPacket("udp", entryPoint.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &entryPointSockets{packetConn: conn, addresses: entryPoint.ListenAddresses()}, nil
+}
+
+// unixListener gives its clients the unixClientAddr address,
+// the address of a Unix domain socket peer being usually empty.
+type unixListener struct {
+	net.Listener
+}
+
+func (l *unixListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &unixConn{Conn: conn}, nil
+}
+
+type unixConn struct {
+	net.Conn
+}
+
+// RemoteAddr returns the address of the clients of the Unix domain sockets.
+func (c *unixConn) RemoteAddr() net.Addr {
+	return unixClientAddr{}
+}
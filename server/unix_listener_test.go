@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-unix")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "traefik.sock")
+
+	// a socket left over by a previous process
+	stale, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	sockets, err := listen("unix", &configuration.EntryPoint{Address: "unix://" + socket}, nil)
+	require.NoError(t, err)
+	require.Len(t, sockets.listeners, 1)
+	listener := sockets.listeners[0]
+
+	server := &http.Server{Handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(req.RemoteAddr))
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err := client.Get("http://localhost/")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "unix:0", string(body))
+}
+
+func TestListenUnixSocketNotASocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-unix")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "traefik.sock")
+	require.NoError(t, ioutil.WriteFile(file, []byte("data"), 0600))
+
+	_, err = listen("unix", &configuration.EntryPoint{Address: "unix://" + file}, nil)
+	assert.EqualError(t, err, file+" already exists and is not a socket")
+
+	_, err = os.Stat(file)
+	assert.NoError(t, err)
+}
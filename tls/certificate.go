@@ -10,11 +10,10 @@ import (
 	"strings"
 
 	"github.com/containous/traefik/log"
-	"github.com/containous/traefik/tls/generate"
 )
 
 var (
-	// MinVersion Map of allowed TLS minimum versions
+	// MinVersion Map of allowed TLS minimum and maximum versions
 	MinVersion = map[string]uint16{
 		`VersionTLS10`: tls.VersionTLS10,
 		`VersionTLS11`: tls.VersionTLS11,
@@ -92,7 +91,7 @@ func (c *Certificates) CreateTLSConfig(entryPointName string) (*tls.Config, map[
 	domainsCertificates := make(map[string]*DomainsCertificates)
 	if c.isEmpty() {
 		config.Certificates = make([]tls.Certificate, 0)
-		cert, err := generate.DefaultCertificate()
+		cert, err := GenerateDefaultCertificate()
 		if err != nil {
 			return nil, nil, err
 		}
@@ -129,21 +128,29 @@ func (c *Certificates) isEmpty() bool {
 	return key == len(*c)
 }
 
-// AppendCertificates appends a Certificate to a certificates map sorted by entrypoints
-func (c *Certificate) AppendCertificates(certs map[string]*DomainsCertificates, ep string) error {
-
+// keyPair reads the certificate and its key.
+func (c *Certificate) keyPair() (tls.Certificate, error) {
 	certContent, err := c.CertFile.Read()
 	if err != nil {
-		return fmt.Errorf("unable to read CertFile : %v", err)
+		return tls.Certificate{}, fmt.Errorf("unable to read CertFile : %v", err)
 	}
 
 	keyContent, err := c.KeyFile.Read()
 	if err != nil {
-		return fmt.Errorf("uUnable to read KeyFile : %v", err)
+		return tls.Certificate{}, fmt.Errorf("uUnable to read KeyFile : %v", err)
 	}
 	tlsCert, err := tls.X509KeyPair(certContent, keyContent)
 	if err != nil {
-		return fmt.Errorf("unable to generate TLS certificate : %v", err)
+		return tls.Certificate{}, fmt.Errorf("unable to generate TLS certificate : %v", err)
+	}
+	return tlsCert, nil
+}
+
+// AppendCertificates appends a Certificate to a certificates map sorted by entrypoints
+func (c *Certificate) AppendCertificates(certs map[string]*DomainsCertificates, ep string) error {
+	tlsCert, err := c.keyPair()
+	if err != nil {
+		return err
 	}
 
 	parsedCert, _ := x509.ParseCertificate(tlsCert.Certificate[0])
@@ -0,0 +1,42 @@
+package tls
+
+import (
+	"crypto/tls"
+	"sync"
+)
+
+var clientCertificates *ClientCertificates
+var clientCertificatesOnce sync.Once
+
+// GetClientCertificates returns the client certificates store which is guaranteed to be a singleton.
+func GetClientCertificates() *ClientCertificates {
+	clientCertificatesOnce.Do(func() {
+		clientCertificates = &ClientCertificates{certificates: make(map[string]*tls.Certificate)}
+	})
+	return clientCertificates
+}
+
+// ClientCertificates holds the client certificates presented to the backends which are managed by the providers in memory,
+// such as the Consul Connect leaf certificate, referenced by name from the backend TLS configurations
+// for their private keys not to be part of the configurations.
+type ClientCertificates struct {
+	lock         sync.RWMutex
+	certificates map[string]*tls.Certificate
+}
+
+// Set stores the certificate of the name, replacing the previous one.
+func (c *ClientCertificates) Set(name string, certificate *tls.Certificate) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.certificates[name] = certificate
+}
+
+// Get returns the certificate of the name, if any.
+func (c *ClientCertificates) Get(name string) (*tls.Certificate, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	certificate, ok := c.certificates[name]
+	return certificate, ok
+}
@@ -0,0 +1,268 @@
+package tls
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/tls/generate"
+)
+
+const (
+	defaultCertificateCommonName = "TRAEFIK DEFAULT CERT"
+	defaultCertificateValidity   = 365 * 24 * time.Hour
+)
+
+var (
+	defaultCertificateOptions *generate.Options
+	defaultCertificateStorage string
+	defaultCertificateLock    sync.Mutex
+)
+
+// DefaultCertificate configures the certificate generated for the entry points without certificates,
+// which is also served on the TLS handshakes whose SNI matches no certificate.
+type DefaultCertificate struct {
+	CommonName string         `description:"Common name of the certificate" export:"true"`
+	SANs       []string       `description:"Domains and IP addresses of the certificate" export:"true"`
+	Validity   flaeg.Duration `description:"Validity of the certificate" export:"true"`
+	KeyType    string         `description:"Key type of the certificate: RSA2048, RSA4096, EC256 or EC384" export:"true"`
+	CACertFile FileOrContent  `description:"Certificate of the CA signing the certificate, which is self-signed otherwise"`
+	CAKeyFile  FileOrContent  `description:"Private key of the CA signing the certificate"`
+	Storage    string         `description:"File storing the certificate and its key, to serve the same certificate after a restart" export:"true"`
+}
+
+// SetDefaultCertificate configures the generated default certificate, which is self-signed with a random domain when nil.
+func SetDefaultCertificate(config *DefaultCertificate) error {
+	defaultCertificateLock.Lock()
+	defer defaultCertificateLock.Unlock()
+
+	if config == nil {
+		defaultCertificateOptions = nil
+		defaultCertificateStorage = ""
+		return nil
+	}
+
+	opts, err := config.options()
+	if err != nil {
+		return err
+	}
+	defaultCertificateOptions = opts
+	defaultCertificateStorage = config.Storage
+	return nil
+}
+
+// GenerateDefaultCertificate generates the default certificate.
+// With a storage, the stored certificate is returned instead while it matches the configuration and isn't expired,
+// so that it stays the same across the restarts.
+func GenerateDefaultCertificate() (*tls.Certificate, error) {
+	defaultCertificateLock.Lock()
+	defer defaultCertificateLock.Unlock()
+
+	if defaultCertificateOptions == nil {
+		return generate.DefaultCertificate()
+	}
+	if len(defaultCertificateStorage) == 0 {
+		return generate.Certificate(*defaultCertificateOptions)
+	}
+
+	cert, err := loadStoredDefaultCertificate(defaultCertificateStorage, defaultCertificateOptions, time.Now())
+	if err != nil {
+		log.Warnf("Unable to load the default certificate from %s, generating a new one: %v", defaultCertificateStorage, err)
+	}
+	if cert != nil {
+		return cert, nil
+	}
+
+	certPEM, keyPEM, err := generate.CertificatePEM(*defaultCertificateOptions)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(defaultCertificateStorage, append(certPEM, keyPEM...), 0600); err != nil {
+		log.Errorf("Unable to store the default certificate in %s, it will change on restart: %v", defaultCertificateStorage, err)
+	} else {
+		log.Infof("Stored the generated default certificate in %s", defaultCertificateStorage)
+	}
+
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+// loadStoredDefaultCertificate returns the stored default certificate, or nil when nothing is stored,
+// or when it doesn't match the options or reached the last third of its validity: it's generated again then.
+func loadStoredDefaultCertificate(storage string, opts *generate.Options, now time.Time) (*tls.Certificate, error) {
+	data, err := ioutil.ReadFile(storage)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.X509KeyPair(data, data)
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	cert.Leaf = leaf
+
+	if renewAt := leaf.NotAfter.Add(-leaf.NotAfter.Sub(leaf.NotBefore) / 3); !now.Before(renewAt) {
+		log.Infof("The stored default certificate expires at %s, generating a new one", leaf.NotAfter)
+		return nil, nil
+	}
+	if reason := defaultCertificateMismatch(leaf, opts); len(reason) > 0 {
+		log.Infof("The stored default certificate doesn't match the configuration (%s), generating a new one", reason)
+		return nil, nil
+	}
+	return &cert, nil
+}
+
+// defaultCertificateMismatch returns how the certificate doesn't match the options, or an empty string if it does.
+func defaultCertificateMismatch(leaf *x509.Certificate, opts *generate.Options) string {
+	if leaf.Subject.CommonName != opts.CommonName {
+		return "common name"
+	}
+
+	var sans []string
+	sans = append(sans, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	expectedSANs := make([]string, 0, len(opts.SANs))
+	for _, san := range opts.SANs {
+		if ip := net.ParseIP(san); ip != nil {
+			san = ip.String()
+		}
+		expectedSANs = append(expectedSANs, san)
+	}
+	sort.Strings(sans)
+	sort.Strings(expectedSANs)
+	if strings.Join(sans, ",") != strings.Join(expectedSANs, ",") {
+		return "SANs"
+	}
+
+	keyType := opts.KeyType
+	if len(keyType) == 0 {
+		keyType = generate.KeyTypeRSA2048
+	}
+	if publicKeyType(leaf.PublicKey) != keyType {
+		return "key type"
+	}
+
+	if opts.CACert != nil {
+		if err := leaf.CheckSignatureFrom(opts.CACert); err != nil {
+			return "CA"
+		}
+	} else if !bytes.Equal(leaf.RawIssuer, leaf.RawSubject) {
+		return "CA"
+	}
+	return ""
+}
+
+// publicKeyType returns the key type of a public key, as configured.
+func publicKeyType(publicKey crypto.PublicKey) string {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA%d", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("EC%d", key.Curve.Params().BitSize)
+	}
+	return ""
+}
+
+// ApplyDefaultCertificate makes the configured default certificate of the entry point the first certificate of the config,
+// served to the handshakes without SNI server name or whose server name matches no certificate.
+// It replaces the certificate generated for the entry points without certificates.
+func (t *TLS) ApplyDefaultCertificate(config *tls.Config) error {
+	if t.DefaultCertificate == nil {
+		return nil
+	}
+
+	cert, err := t.DefaultCertificate.keyPair()
+	if err != nil {
+		return fmt.Errorf("invalid default certificate: %v", err)
+	}
+	if t.Certificates.isEmpty() {
+		config.Certificates = []tls.Certificate{cert}
+		return nil
+	}
+	config.Certificates = append([]tls.Certificate{cert}, config.Certificates...)
+	return nil
+}
+
+// StaticCertificates returns the certificates of the entry point, its configured default certificate first.
+func (t *TLS) StaticCertificates() Certificates {
+	if t.DefaultCertificate == nil {
+		return t.Certificates
+	}
+	return append(Certificates{*t.DefaultCertificate}, t.Certificates...)
+}
+
+func (d *DefaultCertificate) options() (*generate.Options, error) {
+	opts := &generate.Options{
+		CommonName: d.CommonName,
+		SANs:       d.SANs,
+		Validity:   time.Duration(d.Validity),
+		KeyType:    d.KeyType,
+	}
+	if len(opts.CommonName) == 0 {
+		opts.CommonName = defaultCertificateCommonName
+	}
+	if opts.Validity <= 0 {
+		opts.Validity = defaultCertificateValidity
+	}
+
+	switch opts.KeyType {
+	case "", generate.KeyTypeRSA2048, generate.KeyTypeRSA4096, generate.KeyTypeEC256, generate.KeyTypeEC384:
+	default:
+		return nil, fmt.Errorf("unknown default certificate key type %q", opts.KeyType)
+	}
+
+	if len(d.CACertFile) == 0 && len(d.CAKeyFile) == 0 {
+		return opts, nil
+	}
+	if len(d.CACertFile) == 0 || len(d.CAKeyFile) == 0 {
+		return nil, fmt.Errorf("both the CA certificate and key are required to sign the default certificate")
+	}
+
+	certPEM, err := d.CACertFile.Read()
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := d.CAKeyFile.Read()
+	if err != nil {
+		return nil, err
+	}
+	ca, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default certificate CA: %v", err)
+	}
+
+	opts.CACert, err = x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid default certificate CA: %v", err)
+	}
+	if !opts.CACert.IsCA {
+		return nil, fmt.Errorf("invalid default certificate CA: %s is not a CA certificate", opts.CACert.Subject.CommonName)
+	}
+	opts.CAKey = ca.PrivateKey.(crypto.Signer)
+	return opts, nil
+}
@@ -0,0 +1,271 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/tls/generate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultCertificateOptions(t *testing.T) {
+	caCert, caKey := newTestCA(t, "Internal CA").pem(t)
+
+	testCases := []struct {
+		desc          string
+		config        DefaultCertificate
+		expectedError bool
+	}{
+		{
+			desc:   "defaults",
+			config: DefaultCertificate{},
+		},
+		{
+			desc:   "signed by the CA",
+			config: DefaultCertificate{CACertFile: caCert, CAKeyFile: caKey},
+		},
+		{
+			desc:          "unknown key type",
+			config:        DefaultCertificate{KeyType: "DSA"},
+			expectedError: true,
+		},
+		{
+			desc:          "missing CA key",
+			config:        DefaultCertificate{CACertFile: caCert},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid CA",
+			config:        DefaultCertificate{CACertFile: "foo", CAKeyFile: "bar"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := test.config.options()
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, defaultCertificateCommonName, opts.CommonName)
+			assert.Equal(t, defaultCertificateValidity, opts.Validity)
+			assert.Equal(t, len(test.config.CACertFile) > 0, opts.CACert != nil)
+		})
+	}
+}
+
+func TestGenerateDefaultCertificate(t *testing.T) {
+	caCert, caKey := newTestCA(t, "Internal CA").pem(t)
+
+	err := SetDefaultCertificate(&DefaultCertificate{
+		CommonName: "internal",
+		SANs:       []string{"default.internal", "10.0.0.1"},
+		Validity:   flaeg.Duration(24 * time.Hour),
+		KeyType:    "EC256",
+		CACertFile: caCert,
+		CAKeyFile:  caKey,
+	})
+	require.NoError(t, err)
+	defer SetDefaultCertificate(nil)
+
+	cert, err := GenerateDefaultCertificate()
+	require.NoError(t, err)
+	require.Len(t, cert.Certificate, 2)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "internal", leaf.Subject.CommonName)
+	assert.Equal(t, []string{"default.internal"}, leaf.DNSNames)
+	require.Len(t, leaf.IPAddresses, 1)
+	assert.True(t, leaf.IPAddresses[0].Equal(net.ParseIP("10.0.0.1")))
+	assert.IsType(t, &ecdsa.PublicKey{}, leaf.PublicKey)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), leaf.NotAfter, time.Minute)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM([]byte(caCert)))
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "default.internal", Roots: pool})
+	assert.NoError(t, err)
+}
+
+func TestApplyDefaultCertificate(t *testing.T) {
+	comCertificate := Certificate{
+		CertFile: "../integration/fixtures/https/snitest.com.cert",
+		KeyFile:  "../integration/fixtures/https/snitest.com.key",
+	}
+	orgCertificate := Certificate{
+		CertFile: "../integration/fixtures/https/snitest.org.cert",
+		KeyFile:  "../integration/fixtures/https/snitest.org.key",
+	}
+
+	testCases := []struct {
+		desc             string
+		tlsOption        TLS
+		expectedDefault  string
+		expectedCount    int
+		expectedErrorMsg string
+	}{
+		{
+			desc:            "no default certificate",
+			tlsOption:       TLS{Certificates: Certificates{comCertificate}},
+			expectedDefault: "snitest.com",
+			expectedCount:   1,
+		},
+		{
+			desc:            "dedicated default certificate",
+			tlsOption:       TLS{Certificates: Certificates{comCertificate}, DefaultCertificate: &orgCertificate},
+			expectedDefault: "snitest.org",
+			expectedCount:   2,
+		},
+		{
+			desc:            "default certificate replacing the generated one",
+			tlsOption:       TLS{Certificates: Certificates{}, DefaultCertificate: &orgCertificate},
+			expectedDefault: "snitest.org",
+			expectedCount:   1,
+		},
+		{
+			desc:             "invalid default certificate",
+			tlsOption:        TLS{Certificates: Certificates{comCertificate}, DefaultCertificate: &Certificate{CertFile: "foo", KeyFile: "bar"}},
+			expectedErrorMsg: "invalid default certificate: unable to generate TLS certificate : tls: failed to find any PEM data in certificate input",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config, _, err := test.tlsOption.Certificates.CreateTLSConfig("https")
+			require.NoError(t, err)
+
+			err = test.tlsOption.ApplyDefaultCertificate(config)
+			if len(test.expectedErrorMsg) > 0 {
+				assert.EqualError(t, err, test.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, config.Certificates, test.expectedCount)
+
+			leaf, err := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedDefault, leaf.Subject.CommonName)
+		})
+	}
+}
+
+func TestGenerateDefaultCertificateStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "default-certificate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config := &DefaultCertificate{
+		CommonName: "internal",
+		SANs:       []string{"default.internal", "10.0.0.1"},
+		KeyType:    "EC256",
+		Storage:    filepath.Join(dir, "default.pem"),
+	}
+	require.NoError(t, SetDefaultCertificate(config))
+	defer SetDefaultCertificate(nil)
+
+	cert, err := GenerateDefaultCertificate()
+	require.NoError(t, err)
+	fileInfo, err := os.Stat(config.Storage)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fileInfo.Mode().Perm())
+
+	// the stored certificate is served again, e.g. after a restart
+	stored, err := GenerateDefaultCertificate()
+	require.NoError(t, err)
+	assert.Equal(t, cert.Certificate, stored.Certificate)
+
+	// the certificate is generated again when the configuration changes
+	config.SANs = []string{"default.internal"}
+	require.NoError(t, SetDefaultCertificate(config))
+	regenerated, err := GenerateDefaultCertificate()
+	require.NoError(t, err)
+	assert.NotEqual(t, cert.Certificate, regenerated.Certificate)
+}
+
+func TestLoadStoredDefaultCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "default-certificate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := &generate.Options{
+		CommonName: "internal",
+		SANs:       []string{"default.internal", "10.0.0.1"},
+		Validity:   3 * time.Hour,
+		KeyType:    "EC256",
+	}
+	certPEM, keyPEM, err := generate.CertificatePEM(*opts)
+	require.NoError(t, err)
+	storage := filepath.Join(dir, "default.pem")
+	require.NoError(t, ioutil.WriteFile(storage, append(certPEM, keyPEM...), 0600))
+
+	testCases := []struct {
+		desc     string
+		storage  string
+		opts     generate.Options
+		now      time.Time
+		expected bool
+	}{
+		{
+			desc:     "matching certificate",
+			storage:  storage,
+			opts:     *opts,
+			now:      time.Now(),
+			expected: true,
+		},
+		{
+			desc:    "nothing stored",
+			storage: filepath.Join(dir, "missing.pem"),
+			opts:    *opts,
+			now:     time.Now(),
+		},
+		{
+			desc:    "last third of the validity",
+			storage: storage,
+			opts:    *opts,
+			now:     time.Now().Add(2*time.Hour + time.Minute),
+		},
+		{
+			desc:    "other common name",
+			storage: storage,
+			opts:    generate.Options{CommonName: "other", SANs: opts.SANs, KeyType: opts.KeyType},
+			now:     time.Now(),
+		},
+		{
+			desc:    "other SANs",
+			storage: storage,
+			opts:    generate.Options{CommonName: opts.CommonName, SANs: []string{"10.0.0.1"}, KeyType: opts.KeyType},
+			now:     time.Now(),
+		},
+		{
+			desc:    "other key type",
+			storage: storage,
+			opts:    generate.Options{CommonName: opts.CommonName, SANs: opts.SANs, KeyType: "RSA2048"},
+			now:     time.Now(),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			cert, err := loadStoredDefaultCertificate(test.storage, &test.opts, test.now)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, cert != nil)
+		})
+	}
+}
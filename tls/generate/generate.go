@@ -1,6 +1,9 @@
 package generate
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -11,6 +14,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"math/big"
+	"net"
 	"time"
 )
 
@@ -89,3 +93,124 @@ func derCert(privKey *rsa.PrivateKey, expiration time.Time, domain string) ([]by
 
 	return x509.CreateCertificate(rand.Reader, &template, &template, &privKey.PublicKey, privKey)
 }
+
+// Key types of the generated certificates.
+const (
+	KeyTypeRSA2048 = "RSA2048"
+	KeyTypeRSA4096 = "RSA4096"
+	KeyTypeEC256   = "EC256"
+	KeyTypeEC384   = "EC384"
+)
+
+// Options configures a generated certificate.
+type Options struct {
+	CommonName string
+	// SANs are the domains and IP addresses of the certificate.
+	SANs     []string
+	Validity time.Duration
+	KeyType  string
+	// CACert and CAKey sign the certificate when set, otherwise it is self-signed.
+	CACert *x509.Certificate
+	CAKey  crypto.Signer
+}
+
+// Certificate generates a TLS certificate, along with the CA certificate signing it if any.
+func Certificate(opts Options) (*tls.Certificate, error) {
+	certPEM, keyPEM, err := CertificatePEM(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+// CertificatePEM generates a PEM encoded certificate, followed by the CA certificate signing it if any, and its PEM encoded key.
+func CertificatePEM(opts Options) ([]byte, []byte, error) {
+	privKey, err := privateKey(opts.KeyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyPEM, err := pemKey(privKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName: opts.CommonName,
+		},
+		NotBefore: time.Now(),
+		NotAfter:  time.Now().Add(opts.Validity),
+
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, san := range opts.SANs {
+		if ip := net.ParseIP(san); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, san)
+		}
+	}
+
+	parent, signer := &template, privKey
+	if opts.CACert != nil {
+		parent, signer = opts.CACert, opts.CAKey
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, parent, privKey.Public(), signer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
+	if opts.CACert != nil {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: opts.CACert.Raw})...)
+	}
+
+	return certPEM, keyPEM, nil
+}
+
+func privateKey(keyType string) (crypto.Signer, error) {
+	switch keyType {
+	case "", KeyTypeRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case KeyTypeRSA4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case KeyTypeEC256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyTypeEC384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unknown key type %q", keyType)
+	}
+}
+
+func pemKey(privKey crypto.Signer) ([]byte, error) {
+	switch key := privKey.(type) {
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), nil
+	case *ecdsa.PrivateKey:
+		derBytes, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: derBytes}), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", privKey)
+	}
+}
@@ -0,0 +1,146 @@
+package tls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// testCA is a CA issuing the certificates of the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+func newTestCA(t *testing.T, commonName string) *testCA {
+	t.Helper()
+
+	cert := generateCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return &testCA{cert: leaf, key: cert.PrivateKey.(crypto.Signer)}
+}
+
+// generateCertificate generates a certificate of the template, valid for an hour by default, with a new key.
+// The certificate is signed by the CA and followed by it in the chain, or self-signed when the CA is nil.
+func generateCertificate(t *testing.T, template *x509.Certificate, ca *testCA) *tls.Certificate {
+	t.Helper()
+
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now()
+	}
+	if template.NotAfter.IsZero() {
+		template.NotAfter = time.Now().Add(time.Hour)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	parent, parentKey := template, crypto.Signer(key)
+	if ca != nil {
+		parent, parentKey = ca.cert, ca.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert := &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	if ca != nil {
+		cert.Certificate = append(cert.Certificate, ca.cert.Raw)
+	}
+	return cert
+}
+
+// pem returns the PEM encoded certificate and key of the CA.
+func (ca *testCA) pem(t *testing.T) (FileOrContent, FileOrContent) {
+	t.Helper()
+
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(ca.key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})
+	return FileOrContent(certPEM), FileOrContent(keyPEM)
+}
+
+// issue issues a client certificate of the serial number, with the OCSP responder if any.
+func (ca *testCA) issue(t *testing.T, serial int64, ocspServer string) *tls.Certificate {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "client"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if len(ocspServer) > 0 {
+		template.OCSPServer = []string{ocspServer}
+	}
+
+	cert := generateCertificate(t, template, ca)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	cert.Leaf = leaf
+	return cert
+}
+
+// writeCRL writes the PEM encoded CRL of the CA revoking the serial numbers.
+func (ca *testCA) writeCRL(t *testing.T, path string, revoked ...int64) {
+	t.Helper()
+
+	var revokedCertificates []pkix.RevokedCertificate
+	for _, serial := range revoked {
+		revokedCertificates = append(revokedCertificates, pkix.RevokedCertificate{
+			SerialNumber:   big.NewInt(serial),
+			RevocationTime: time.Now(),
+		})
+	}
+	der, err := ca.cert.CreateCRL(rand.Reader, ca.key, revokedCertificates, time.Now(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), 0600))
+}
+
+// newOCSPResponder starts an OCSP responder of the CA, reporting the status of the serial numbers, unknown by default,
+// in responses valid for the next update duration.
+func (ca *testCA) newOCSPResponder(t *testing.T, statuses map[int64]int, nextUpdate time.Duration) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		request, err := ocsp.ParseRequest(body)
+		require.NoError(t, err)
+
+		status, ok := statuses[request.SerialNumber.Int64()]
+		if !ok {
+			status = ocsp.Unknown
+		}
+		response, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+			Status:       status,
+			SerialNumber: request.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(nextUpdate),
+			RevokedAt:    time.Now().Add(-time.Minute),
+		}, ca.key)
+		require.NoError(t, err)
+		rw.Write(response)
+	}))
+}
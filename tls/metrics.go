@@ -0,0 +1,59 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"sort"
+	"strings"
+
+	"github.com/go-kit/kit/metrics"
+)
+
+// ReportCertificatesNotAfter sets the expiration timestamp of each certificate, by common name, serial number and SANs,
+// the certificates served several times being reported once.
+func ReportCertificatesNotAfter(gauge metrics.Gauge, certs []*tls.Certificate) {
+	if gauge == nil {
+		return
+	}
+
+	reported := make(map[string]bool)
+	for _, cert := range certs {
+		leaf := certificateLeaf(cert)
+		if leaf == nil {
+			continue
+		}
+
+		key := string(leaf.Raw)
+		if reported[key] {
+			continue
+		}
+		reported[key] = true
+
+		sans := append([]string{}, leaf.DNSNames...)
+		for _, ip := range leaf.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+		sort.Strings(sans)
+
+		gauge.With("cn", leaf.Subject.CommonName, "serial", leaf.SerialNumber.String(), "sans", strings.Join(sans, ",")).
+			Set(float64(leaf.NotAfter.Unix()))
+	}
+}
+
+// certificateLeaf returns the parsed leaf of the certificate, or nil if it can't be parsed.
+func certificateLeaf(cert *tls.Certificate) *x509.Certificate {
+	if cert == nil {
+		return nil
+	}
+	if cert.Leaf != nil {
+		return cert.Leaf
+	}
+	if len(cert.Certificate) == 0 {
+		return nil
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil
+	}
+	return leaf
+}
@@ -0,0 +1,92 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingGauge records the values set by label values.
+type recordingGauge struct {
+	values      map[string]float64
+	labelValues []string
+}
+
+func (g *recordingGauge) With(labelValues ...string) metrics.Gauge {
+	return &recordingGauge{values: g.values, labelValues: append(g.labelValues, labelValues...)}
+}
+
+func (g *recordingGauge) Set(value float64) {
+	g.values[strings.Join(g.labelValues, " ")] = value
+}
+
+func (g *recordingGauge) Add(delta float64) {
+	g.values[strings.Join(g.labelValues, " ")] += delta
+}
+
+func TestReportCertificatesNotAfter(t *testing.T) {
+	notAfter := time.Unix(1893456000, 0)
+	cert := generateCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotAfter:     notAfter,
+		DNSNames:     []string{"www.example.com", "example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("10.0.0.1")},
+	}, nil)
+	parsed := generateCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(43),
+		NotAfter:     notAfter.Add(time.Hour),
+	}, nil)
+	parsed.Leaf, _ = x509.ParseCertificate(parsed.Certificate[0])
+
+	testCases := []struct {
+		desc     string
+		certs    []*tls.Certificate
+		expected map[string]float64
+	}{
+		{
+			desc:     "no certificate",
+			expected: map[string]float64{},
+		},
+		{
+			desc:  "certificate with SANs",
+			certs: []*tls.Certificate{cert},
+			expected: map[string]float64{
+				"cn example.com serial 42 sans 10.0.0.1,example.com,www.example.com": float64(notAfter.Unix()),
+			},
+		},
+		{
+			desc:  "certificates served several times",
+			certs: []*tls.Certificate{cert, parsed, cert},
+			expected: map[string]float64{
+				"cn example.com serial 42 sans 10.0.0.1,example.com,www.example.com": float64(notAfter.Unix()),
+				"cn  serial 43 sans ": float64(notAfter.Add(time.Hour).Unix()),
+			},
+		},
+		{
+			desc:     "invalid certificates",
+			certs:    []*tls.Certificate{nil, {}, {Certificate: [][]byte{[]byte("invalid")}}},
+			expected: map[string]float64{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			gauge := &recordingGauge{values: make(map[string]float64)}
+			ReportCertificatesNotAfter(gauge, test.certs)
+
+			assert.Equal(t, test.expected, gauge.values)
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	"github.com/go-kit/kit/metrics"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	defaultOCSPRefreshInterval = time.Hour
+	ocspStapleAgeInterval      = time.Minute
+)
+
+// OCSPStapler fetches and caches the OCSP responses of the served certificates, and staples them during the handshakes,
+// sparing the clients a request to the OCSP responder of the CA.
+// The responses are fetched in the background the first time a certificate is served, then refreshed periodically
+// as long as the certificate is served.
+type OCSPStapler struct {
+	refreshInterval time.Duration
+	client          *http.Client
+	stapleAgeGauge  metrics.Gauge
+	lock            sync.RWMutex
+	// staples are the OCSP responses by leaf certificate fingerprint.
+	staples map[string]*ocspStaple
+}
+
+// ocspStaple is the OCSP response of a certificate, without leaf when the certificate can't be stapled.
+type ocspStaple struct {
+	// served is set when the certificate is served, and reset by each refresh.
+	served     int32
+	domain     string
+	leaf       *x509.Certificate
+	issuer     *x509.Certificate
+	response   []byte
+	thisUpdate time.Time
+	nextUpdate time.Time
+}
+
+// NewOCSPStapler creates a stapler refreshing the OCSP responses at the interval, one hour if zero,
+// and reporting their age to the gauge.
+func NewOCSPStapler(refreshInterval time.Duration, stapleAgeGauge metrics.Gauge) *OCSPStapler {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultOCSPRefreshInterval
+	}
+	return &OCSPStapler{
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 30 * time.Second},
+		stapleAgeGauge:  stapleAgeGauge,
+		staples:         make(map[string]*ocspStaple),
+	}
+}
+
+// GetCertificate wraps the GetCertificate callback of the config, stapling the OCSP response to the certificate it returns,
+// or to the certificate of the config matching the client hello when it returns none.
+func (s *OCSPStapler) GetCertificate(config *tls.Config, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		var cert *tls.Certificate
+		if getCertificate != nil {
+			var err error
+			cert, err = getCertificate(clientHello)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if cert == nil {
+			cert = configCertificate(config, clientHello.ServerName)
+		}
+		return s.Staple(cert), nil
+	}
+}
+
+// Staple returns a copy of the certificate with its OCSP response, or the certificate itself until the response is fetched.
+func (s *OCSPStapler) Staple(cert *tls.Certificate) *tls.Certificate {
+	if cert == nil || len(cert.Certificate) == 0 || len(cert.OCSPStaple) > 0 {
+		return cert
+	}
+
+	key := fingerprint(cert.Certificate[0])
+	s.lock.RLock()
+	staple, ok := s.staples[key]
+	var response []byte
+	if ok {
+		atomic.StoreInt32(&staple.served, 1)
+		// the expired responses are not stapled anymore
+		if staple.nextUpdate.IsZero() || time.Now().Before(staple.nextUpdate) {
+			response = staple.response
+		}
+	}
+	s.lock.RUnlock()
+
+	if !ok {
+		s.register(key, cert)
+		return cert
+	}
+	if len(response) == 0 {
+		return cert
+	}
+
+	stapled := *cert
+	stapled.OCSPStaple = response
+	return &stapled
+}
+
+// register adds the certificate to the cache and fetches its OCSP response in the background.
+// The certificates without OCSP responder or issuer in their chain are cached as such, and never stapled.
+func (s *OCSPStapler) register(key string, cert *tls.Certificate) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.staples[key]; ok {
+		return
+	}
+
+	staple, err := newOCSPStaple(cert)
+	if err != nil {
+		log.Debugf("The OCSP response of the certificate can't be stapled: %v", err)
+		s.staples[key] = &ocspStaple{served: 1}
+		return
+	}
+	staple.served = 1
+	s.staples[key] = staple
+
+	safe.Go(func() {
+		s.refresh(staple)
+	})
+}
+
+// Run refreshes the OCSP responses at every interval, and reports their age every minute, until the context is done.
+// The certificates not served since the previous refresh are forgotten, and registered again if they are served later on.
+func (s *OCSPStapler) Run(ctx context.Context) {
+	refreshTicker := time.NewTicker(s.refreshInterval)
+	defer refreshTicker.Stop()
+	stapleAgeTicker := time.NewTicker(ocspStapleAgeInterval)
+	defer stapleAgeTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-refreshTicker.C:
+			s.prune()
+			for _, staple := range s.registeredStaples() {
+				s.refresh(staple)
+			}
+		case <-stapleAgeTicker.C:
+			for _, staple := range s.registeredStaples() {
+				s.reportStapleAge(staple)
+			}
+		}
+	}
+}
+
+func (s *OCSPStapler) registeredStaples() []*ocspStaple {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var staples []*ocspStaple
+	for _, staple := range s.staples {
+		if staple.leaf != nil {
+			staples = append(staples, staple)
+		}
+	}
+	return staples
+}
+
+// prune removes the certificates which were not served since the previous refresh,
+// e.g. the certificates replaced by a renewal or removed from the configuration.
+func (s *OCSPStapler) prune() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for key, staple := range s.staples {
+		if atomic.SwapInt32(&staple.served, 0) == 0 {
+			if staple.leaf != nil {
+				log.Debugf("Forgetting the OCSP response of the certificate of %s, which is not served anymore", staple.domain)
+			}
+			delete(s.staples, key)
+		}
+	}
+}
+
+// refresh fetches the OCSP response of the certificate, keeping the previous one on error.
+func (s *OCSPStapler) refresh(staple *ocspStaple) {
+	response, parsedResponse, err := s.fetch(staple)
+	if err != nil {
+		log.Errorf("Error fetching the OCSP response of the certificate of %s: %v", staple.domain, err)
+	} else if parsedResponse.Status != ocsp.Good {
+		log.Warnf("The OCSP responder reports the certificate of %s as not valid, its response is not stapled", staple.domain)
+		s.lock.Lock()
+		staple.response = nil
+		s.lock.Unlock()
+	} else {
+		log.Debugf("Fetched the OCSP response of the certificate of %s", staple.domain)
+		s.lock.Lock()
+		staple.response = response
+		staple.thisUpdate = parsedResponse.ThisUpdate
+		staple.nextUpdate = parsedResponse.NextUpdate
+		s.lock.Unlock()
+	}
+	s.reportStapleAge(staple)
+}
+
+// reportStapleAge sets the gauge to the time elapsed since the OCSP response of the certificate was produced.
+func (s *OCSPStapler) reportStapleAge(staple *ocspStaple) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.stapleAgeGauge != nil && len(staple.response) > 0 {
+		s.stapleAgeGauge.With("domain", staple.domain).Set(time.Since(staple.thisUpdate).Seconds())
+	}
+}
+
+func (s *OCSPStapler) fetch(staple *ocspStaple) ([]byte, *ocsp.Response, error) {
+	return requestOCSPResponse(s.client, staple.leaf, staple.issuer)
+}
+
+// requestOCSPResponse requests the OCSP response of the certificate to the first OCSP responder of the certificate.
+func requestOCSPResponse(client *http.Client, leaf *x509.Certificate, issuer *x509.Certificate) ([]byte, *ocsp.Response, error) {
+	request, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := client.Post(leaf.OCSPServer[0], "application/ocsp-request", bytes.NewReader(request))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status code from %s: %d", leaf.OCSPServer[0], resp.StatusCode)
+	}
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parsedResponse, err := ocsp.ParseResponseForCert(response, leaf, issuer)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, parsedResponse, nil
+}
+
+func newOCSPStaple(cert *tls.Certificate) (*ocspStaple, error) {
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	domain := leaf.Subject.CommonName
+	if len(domain) == 0 && len(leaf.DNSNames) > 0 {
+		domain = leaf.DNSNames[0]
+	}
+	if len(leaf.OCSPServer) == 0 {
+		return nil, fmt.Errorf("no OCSP responder in the certificate of %s", domain)
+	}
+	if len(cert.Certificate) < 2 {
+		return nil, fmt.Errorf("no issuer in the chain of the certificate of %s", domain)
+	}
+	issuer, err := x509.ParseCertificate(cert.Certificate[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &ocspStaple{
+		domain: domain,
+		leaf:   leaf,
+		issuer: issuer,
+	}, nil
+}
+
+// configCertificate returns the certificate of the config matching the server name, or else its first certificate,
+// as the handshake does without GetCertificate callback.
+func configCertificate(config *tls.Config, serverName string) *tls.Certificate {
+	if len(config.Certificates) == 0 {
+		return nil
+	}
+	if cert := nameCertificate(config, serverName); cert != nil {
+		return cert
+	}
+	return &config.Certificates[0]
+}
+
+// nameCertificate returns the certificate of the config matching the server name, exactly or by wildcard.
+func nameCertificate(config *tls.Config, serverName string) *tls.Certificate {
+	name := strings.TrimRight(strings.ToLower(serverName), ".")
+	if cert, ok := config.NameToCertificate[name]; ok {
+		return cert
+	}
+	if labels := strings.Split(name, "."); len(labels) > 1 {
+		labels[0] = "*"
+		if cert, ok := config.NameToCertificate[strings.Join(labels, ".")]; ok {
+			return cert
+		}
+	}
+	return nil
+}
+
+func fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return string(sum[:])
+}
@@ -0,0 +1,141 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// generateOCSPCertificate generates a certificate signed by a CA, whose OCSP responder answers with the status.
+func generateOCSPCertificate(t *testing.T, status int, nextUpdate time.Duration) (*tls.Certificate, *httptest.Server) {
+	t.Helper()
+
+	ca := newTestCA(t, "Internal CA")
+	responder := ca.newOCSPResponder(t, map[int64]int{2: status}, nextUpdate)
+	cert := generateCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		OCSPServer:   []string{responder.URL},
+	}, ca)
+	return cert, responder
+}
+
+func TestOCSPStapler_Staple(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		status     int
+		nextUpdate time.Duration
+		expected   bool
+	}{
+		{
+			desc:       "good certificate",
+			status:     ocsp.Good,
+			nextUpdate: time.Hour,
+			expected:   true,
+		},
+		{
+			desc:       "revoked certificate",
+			status:     ocsp.Revoked,
+			nextUpdate: time.Hour,
+		},
+		{
+			desc:       "expired response",
+			status:     ocsp.Good,
+			nextUpdate: -time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cert, responder := generateOCSPCertificate(t, test.status, test.nextUpdate)
+			defer responder.Close()
+
+			stapler := NewOCSPStapler(0, nil)
+			staple, err := newOCSPStaple(cert)
+			require.NoError(t, err)
+			stapler.staples[fingerprint(cert.Certificate[0])] = staple
+			stapler.refresh(staple)
+
+			stapled := stapler.Staple(cert)
+			assert.Empty(t, cert.OCSPStaple)
+			if !test.expected {
+				assert.Empty(t, stapled.OCSPStaple)
+				return
+			}
+			require.NotEmpty(t, stapled.OCSPStaple)
+			response, err := ocsp.ParseResponse(stapled.OCSPStaple, nil)
+			require.NoError(t, err)
+			assert.Equal(t, ocsp.Good, response.Status)
+		})
+	}
+}
+
+func TestOCSPStapler_GetCertificate(t *testing.T) {
+	cert, responder := generateOCSPCertificate(t, ocsp.Good, time.Hour)
+	defer responder.Close()
+
+	stapler := NewOCSPStapler(0, nil)
+	config := &tls.Config{Certificates: []tls.Certificate{*cert}}
+	getCertificate := stapler.GetCertificate(config, func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return nil, nil
+	})
+
+	// the certificate of the config is registered by the first handshake, and stapled once its response is fetched
+	served, err := getCertificate(&tls.ClientHelloInfo{ServerName: "example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, cert.Certificate, served.Certificate)
+
+	staples := stapler.registeredStaples()
+	require.Len(t, staples, 1)
+	stapler.refresh(staples[0])
+
+	served, err = getCertificate(&tls.ClientHelloInfo{ServerName: "example.com"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, served.OCSPStaple)
+}
+
+func TestOCSPStapler_StapleWithoutResponder(t *testing.T) {
+	cert, responder := generateOCSPCertificate(t, ocsp.Good, time.Hour)
+	responder.Close()
+	// without issuer in the chain, the response can't be requested
+	cert.Certificate = cert.Certificate[:1]
+
+	stapler := NewOCSPStapler(0, nil)
+	assert.True(t, stapler.Staple(cert) == cert, "certificate without issuer stapled")
+	assert.Empty(t, stapler.registeredStaples())
+}
+
+func TestOCSPStapler_Prune(t *testing.T) {
+	served, responder := generateOCSPCertificate(t, ocsp.Good, time.Hour)
+	defer responder.Close()
+	replaced, otherResponder := generateOCSPCertificate(t, ocsp.Good, time.Hour)
+	defer otherResponder.Close()
+
+	stapler := NewOCSPStapler(0, nil)
+	stapler.Staple(served)
+	stapler.Staple(replaced)
+
+	// the certificates are kept until a refresh during which they were not served
+	stapler.prune()
+	assert.Len(t, stapler.registeredStaples(), 2)
+
+	stapler.Staple(served)
+	stapler.prune()
+	staples := stapler.registeredStaples()
+	require.Len(t, staples, 1)
+	assert.Equal(t, served.Certificate[0], staples[0].leaf.Raw)
+
+	stapler.prune()
+	assert.Empty(t, stapler.registeredStaples())
+}
@@ -0,0 +1,66 @@
+package tls
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// CurveIDs Map of the elliptic curves from crypto/tls
+var CurveIDs = map[string]tls.CurveID{
+	`CurveP256`: tls.CurveP256,
+	`CurveP384`: tls.CurveP384,
+	`CurveP521`: tls.CurveP521,
+	`X25519`:    tls.X25519,
+}
+
+// Options holds the TLS versions, cipher suites and curves of an entry point,
+// or the ones of the named TLS options selected by the frontends in the dynamic configuration.
+type Options struct {
+	MinVersion       string   `json:"minVersion,omitempty"`
+	MaxVersion       string   `json:"maxVersion,omitempty"`
+	CipherSuites     []string `json:"cipherSuites,omitempty"`
+	CurvePreferences []string `json:"curvePreferences,omitempty"`
+}
+
+// Apply sets the options to the TLS config, the unset ones leaving it unchanged.
+func (o *Options) Apply(config *tls.Config) error {
+	if len(o.MinVersion) > 0 {
+		minVersion, ok := MinVersion[o.MinVersion]
+		if !ok {
+			return fmt.Errorf("invalid TLS minimum version: %s", o.MinVersion)
+		}
+		config.PreferServerCipherSuites = true
+		config.MinVersion = minVersion
+	}
+	if len(o.MaxVersion) > 0 {
+		maxVersion, ok := MinVersion[o.MaxVersion]
+		if !ok {
+			return fmt.Errorf("invalid TLS maximum version: %s", o.MaxVersion)
+		}
+		config.MaxVersion = maxVersion
+	}
+	if config.MinVersion > 0 && config.MaxVersion > 0 && config.MinVersion > config.MaxVersion {
+		return fmt.Errorf("TLS minimum version %s above the maximum version %s", o.MinVersion, o.MaxVersion)
+	}
+	if o.CipherSuites != nil {
+		config.CipherSuites = make([]uint16, 0)
+		for _, cipher := range o.CipherSuites {
+			cipherConst, ok := CipherSuites[cipher]
+			if !ok {
+				return fmt.Errorf("invalid CipherSuite: %s", cipher)
+			}
+			config.CipherSuites = append(config.CipherSuites, cipherConst)
+		}
+	}
+	if o.CurvePreferences != nil {
+		config.CurvePreferences = make([]tls.CurveID, 0)
+		for _, curve := range o.CurvePreferences {
+			curveID, ok := CurveIDs[curve]
+			if !ok {
+				return fmt.Errorf("invalid curve: %s", curve)
+			}
+			config.CurvePreferences = append(config.CurvePreferences, curveID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package tls
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsApply(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		options       Options
+		expected      *tls.Config
+		expectedError string
+	}{
+		{
+			desc:     "no options",
+			expected: &tls.Config{},
+		},
+		{
+			desc: "all options",
+			options: Options{
+				MinVersion:       "VersionTLS11",
+				MaxVersion:       "VersionTLS12",
+				CipherSuites:     []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+				CurvePreferences: []string{"X25519", "CurveP256"},
+			},
+			expected: &tls.Config{
+				PreferServerCipherSuites: true,
+				MinVersion:               tls.VersionTLS11,
+				MaxVersion:               tls.VersionTLS12,
+				CipherSuites:             []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+				CurvePreferences:         []tls.CurveID{tls.X25519, tls.CurveP256},
+			},
+		},
+		{
+			desc:          "unknown minimum version",
+			options:       Options{MinVersion: "VersionSSL30"},
+			expectedError: "invalid TLS minimum version: VersionSSL30",
+		},
+		{
+			desc:          "minimum version above the maximum version",
+			options:       Options{MinVersion: "VersionTLS12", MaxVersion: "VersionTLS10"},
+			expectedError: "TLS minimum version VersionTLS12 above the maximum version VersionTLS10",
+		},
+		{
+			desc:          "unknown cipher suite",
+			options:       Options{CipherSuites: []string{"TLS_FOO"}},
+			expectedError: "invalid CipherSuite: TLS_FOO",
+		},
+		{
+			desc:          "unknown curve",
+			options:       Options{CurvePreferences: []string{"CurveP224"}},
+			expectedError: "invalid curve: CurveP224",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := &tls.Config{}
+			err := test.options.Apply(config)
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, config)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	"golang.org/x/crypto/ocsp"
+)
+
+// RevocationChecker rejects the client certificates revoked by the CRLs of their CA, or by their OCSP responder,
+// failing the handshakes with a bad certificate alert.
+type RevocationChecker struct {
+	crlFiles     []string
+	ocsp         bool
+	ocspSoftFail bool
+	client       *http.Client
+	// crls holds the []*pkix.CertificateList read from the CRL files.
+	crls safe.Safe
+	lock sync.Mutex
+	// ocspResponses are the OCSP responses of the client certificates by fingerprint, cached until their next update.
+	ocspResponses map[string]*ocsp.Response
+}
+
+// NewRevocationChecker creates the checker of the client certificates verified against the client CA,
+// reading its CRL files.
+func NewRevocationChecker(clientCA ClientCA) (*RevocationChecker, error) {
+	c := &RevocationChecker{
+		crlFiles:      clientCA.CRLFiles,
+		ocsp:          clientCA.OCSP,
+		ocspSoftFail:  clientCA.OCSPSoftFail,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		ocspResponses: make(map[string]*ocsp.Response),
+	}
+	if err := c.ReloadCRLs(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CRLFiles returns the CRL files of the checker.
+func (c *RevocationChecker) CRLFiles() []string {
+	return c.crlFiles
+}
+
+// ReloadCRLs reads the CRL files, PEM or DER encoded, keeping the previous CRLs when one of them is invalid.
+func (c *RevocationChecker) ReloadCRLs() error {
+	var crls []*pkix.CertificateList
+	for _, crlFile := range c.crlFiles {
+		data, err := ioutil.ReadFile(crlFile)
+		if err != nil {
+			return err
+		}
+		crl, err := x509.ParseCRL(data)
+		if err != nil {
+			return fmt.Errorf("invalid CRL %s: %v", crlFile, err)
+		}
+		crls = append(crls, crl)
+	}
+	c.crls.Set(crls)
+	return nil
+}
+
+// Apply makes the config check the revocation of the client certificates.
+// The session tickets of the config are disabled, the certificates of the resumed sessions not being verified again.
+func (c *RevocationChecker) Apply(config *tls.Config) {
+	config.VerifyPeerCertificate = c.VerifyPeerCertificate
+	config.SessionTicketsDisabled = true
+}
+
+// VerifyPeerCertificate checks the certificates of the verified chains of the client,
+// the leaf and intermediate certificates against the CRLs of their issuer, and the leaf certificate against its OCSP responder.
+func (c *RevocationChecker) VerifyPeerCertificate(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	crls, _ := c.crls.Get().([]*pkix.CertificateList)
+	for _, chain := range verifiedChains {
+		for i := 0; i < len(chain)-1; i++ {
+			if revokedByCRL(crls, chain[i], chain[i+1]) {
+				return fmt.Errorf("client certificate %s revoked by the CRL of %s", chain[i].SerialNumber, chain[i+1].Subject.CommonName)
+			}
+		}
+		if c.ocsp && len(chain) > 1 {
+			if err := c.checkOCSP(chain[0], chain[1]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// revokedByCRL checks whether a CRL signed by the issuer of the certificate revokes it.
+func revokedByCRL(crls []*pkix.CertificateList, cert *x509.Certificate, issuer *x509.Certificate) bool {
+	for _, crl := range crls {
+		if issuer.CheckCRLSignature(crl) != nil {
+			continue
+		}
+		for _, revoked := range crl.TBSCertList.RevokedCertificates {
+			if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *RevocationChecker) checkOCSP(leaf *x509.Certificate, issuer *x509.Certificate) error {
+	if len(leaf.OCSPServer) == 0 {
+		return nil
+	}
+
+	response, err := c.ocspResponse(leaf, issuer)
+	if err != nil {
+		if c.ocspSoftFail {
+			log.Warnf("Unable to check the OCSP status of the client certificate %s, accepting it: %v", leaf.SerialNumber, err)
+			return nil
+		}
+		return fmt.Errorf("unable to check the OCSP status of the client certificate %s: %v", leaf.SerialNumber, err)
+	}
+
+	switch response.Status {
+	case ocsp.Revoked:
+		return fmt.Errorf("client certificate %s revoked by its OCSP responder", leaf.SerialNumber)
+	case ocsp.Unknown:
+		if !c.ocspSoftFail {
+			return fmt.Errorf("client certificate %s unknown to its OCSP responder", leaf.SerialNumber)
+		}
+	}
+	return nil
+}
+
+// ocspResponse returns the cached OCSP response of the certificate, or requests it when it is missing or expired.
+func (c *RevocationChecker) ocspResponse(leaf *x509.Certificate, issuer *x509.Certificate) (*ocsp.Response, error) {
+	key := fingerprint(leaf.Raw)
+	now := time.Now()
+
+	c.lock.Lock()
+	response, ok := c.ocspResponses[key]
+	c.lock.Unlock()
+	if ok && now.Before(response.NextUpdate) {
+		return response, nil
+	}
+
+	_, response, err := requestOCSPResponse(c.client, leaf, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	// the expired responses are dropped, the ones without next update being cached for the default refresh interval
+	for k, cached := range c.ocspResponses {
+		if !now.Before(cached.NextUpdate) {
+			delete(c.ocspResponses, k)
+		}
+	}
+	if response.NextUpdate.IsZero() {
+		response.NextUpdate = now.Add(defaultOCSPRefreshInterval)
+	}
+	c.ocspResponses[key] = response
+	return response, nil
+}
@@ -0,0 +1,219 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestRevocationCheckerCRL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crl")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t, "Tenant CA")
+	otherCA := newTestCA(t, "Other CA")
+	crlFile := filepath.Join(dir, "ca.crl")
+	otherCRLFile := filepath.Join(dir, "other.crl")
+	ca.writeCRL(t, crlFile, 3)
+	otherCA.writeCRL(t, otherCRLFile, 2)
+
+	checker, err := NewRevocationChecker(ClientCA{CRLFiles: []string{crlFile, otherCRLFile}})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		chains        [][]*x509.Certificate
+		expectedError string
+	}{
+		{
+			desc:   "certificate not revoked",
+			chains: [][]*x509.Certificate{{ca.issue(t, 2, "").Leaf, ca.cert}},
+		},
+		{
+			desc:          "certificate revoked",
+			chains:        [][]*x509.Certificate{{ca.issue(t, 3, "").Leaf, ca.cert}},
+			expectedError: "client certificate 3 revoked by the CRL of Tenant CA",
+		},
+		{
+			desc: "no certificate",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := checker.VerifyPeerCertificate(nil, test.chains)
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRevocationCheckerReloadCRLs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crl")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t, "Tenant CA")
+	cert := ca.issue(t, 2, "").Leaf
+	crlFile := filepath.Join(dir, "ca.crl")
+	ca.writeCRL(t, crlFile)
+
+	checker, err := NewRevocationChecker(ClientCA{CRLFiles: []string{crlFile}})
+	require.NoError(t, err)
+	assert.NoError(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{cert, ca.cert}}))
+
+	ca.writeCRL(t, crlFile, 2)
+	require.NoError(t, checker.ReloadCRLs())
+	assert.Error(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{cert, ca.cert}}))
+
+	require.NoError(t, ioutil.WriteFile(crlFile, []byte("not a CRL"), 0600))
+	assert.Error(t, checker.ReloadCRLs())
+	assert.Error(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{cert, ca.cert}}), "the previous CRLs are kept when invalid")
+}
+
+func TestRevocationCheckerResumedSession(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crl")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t, "Tenant CA")
+	crlFile := filepath.Join(dir, "ca.crl")
+	ca.writeCRL(t, crlFile)
+
+	checker, err := NewRevocationChecker(ClientCA{CRLFiles: []string{crlFile}})
+	require.NoError(t, err)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{*generateCertificate(t, &x509.Certificate{
+			SerialNumber: big.NewInt(10),
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}, ca)},
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	checker.Apply(server.TLS)
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ca.cert)
+	client := &http.Client{Transport: &http.Transport{
+		DisableKeepAlives: true,
+		TLSClientConfig: &tls.Config{
+			Certificates:       []tls.Certificate{*ca.issue(t, 2, "")},
+			RootCAs:            rootCAs,
+			ClientSessionCache: tls.NewLRUClientSessionCache(1),
+		},
+	}}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.False(t, resp.TLS.DidResume)
+
+	// the certificates of the resumed sessions wouldn't be checked
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.False(t, resp.TLS.DidResume, "session resumed")
+
+	ca.writeCRL(t, crlFile, 2)
+	require.NoError(t, checker.ReloadCRLs())
+
+	_, err = client.Get(server.URL)
+	require.Error(t, err, "revoked certificate accepted")
+	assert.Contains(t, err.Error(), "bad certificate")
+}
+
+func TestRevocationCheckerOCSP(t *testing.T) {
+	ca := newTestCA(t, "Tenant CA")
+	responder := ca.newOCSPResponder(t, map[int64]int{2: ocsp.Good, 3: ocsp.Revoked}, time.Hour)
+	defer responder.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	testCases := []struct {
+		desc          string
+		cert          *x509.Certificate
+		softFail      bool
+		expectedError string
+	}{
+		{
+			desc: "good certificate",
+			cert: ca.issue(t, 2, responder.URL).Leaf,
+		},
+		{
+			desc:          "revoked certificate",
+			cert:          ca.issue(t, 3, responder.URL).Leaf,
+			expectedError: "client certificate 3 revoked by its OCSP responder",
+		},
+		{
+			desc:          "revoked certificate, soft fail",
+			cert:          ca.issue(t, 3, responder.URL).Leaf,
+			softFail:      true,
+			expectedError: "client certificate 3 revoked by its OCSP responder",
+		},
+		{
+			desc:          "unknown certificate",
+			cert:          ca.issue(t, 4, responder.URL).Leaf,
+			expectedError: "client certificate 4 unknown to its OCSP responder",
+		},
+		{
+			desc:     "unknown certificate, soft fail",
+			cert:     ca.issue(t, 4, responder.URL).Leaf,
+			softFail: true,
+		},
+		{
+			desc:          "unreachable responder",
+			cert:          ca.issue(t, 5, unreachable.URL).Leaf,
+			expectedError: "unable to check the OCSP status of the client certificate 5",
+		},
+		{
+			desc:     "unreachable responder, soft fail",
+			cert:     ca.issue(t, 5, unreachable.URL).Leaf,
+			softFail: true,
+		},
+		{
+			desc: "certificate without OCSP responder",
+			cert: ca.issue(t, 6, "").Leaf,
+		},
+	}
+
+	// the subtests aren't parallel, to run while the responder is up
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			checker, err := NewRevocationChecker(ClientCA{OCSP: true, OCSPSoftFail: test.softFail})
+			require.NoError(t, err)
+
+			err = checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{test.cert, ca.cert}})
+			if len(test.expectedError) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package tls
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+)
+
+// StrictSNI wraps the GetCertificate callback of a TLS config, rejecting the handshakes without server name,
+// or whose server name matches neither a certificate returned by the callback nor a certificate of the config,
+// instead of serving the default certificate.
+func StrictSNI(config *tls.Config, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if len(clientHello.ServerName) == 0 {
+			return nil, errors.New("strict SNI enabled - no server name")
+		}
+		if getCertificate != nil {
+			if cert, err := getCertificate(clientHello); cert != nil || err != nil {
+				return cert, err
+			}
+		}
+		if cert := nameCertificate(config, clientHello.ServerName); cert != nil {
+			return cert, nil
+		}
+		return nil, fmt.Errorf("strict SNI enabled - no certificate for server name %q", clientHello.ServerName)
+	}
+}
@@ -0,0 +1,78 @@
+package tls
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictSNI(t *testing.T) {
+	defaultCert := &tls.Certificate{Certificate: [][]byte{[]byte("default")}}
+	staticCert := &tls.Certificate{Certificate: [][]byte{[]byte("static")}}
+	wildcardCert := &tls.Certificate{Certificate: [][]byte{[]byte("wildcard")}}
+	dynamicCert := &tls.Certificate{Certificate: [][]byte{[]byte("dynamic")}}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{*defaultCert, *staticCert, *wildcardCert},
+		NameToCertificate: map[string]*tls.Certificate{
+			"static.example.com": staticCert,
+			"*.example.org":      wildcardCert,
+		},
+	}
+	getCertificate := StrictSNI(config, func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if clientHello.ServerName == "dynamic.example.com" {
+			return dynamicCert, nil
+		}
+		return nil, nil
+	})
+
+	testCases := []struct {
+		desc          string
+		serverName    string
+		expected      *tls.Certificate
+		expectedError string
+	}{
+		{
+			desc:       "dynamic certificate",
+			serverName: "dynamic.example.com",
+			expected:   dynamicCert,
+		},
+		{
+			desc:       "static certificate",
+			serverName: "Static.example.com",
+			expected:   staticCert,
+		},
+		{
+			desc:       "wildcard certificate",
+			serverName: "www.example.org",
+			expected:   wildcardCert,
+		},
+		{
+			desc:          "unknown server name",
+			serverName:    "unknown.example.com",
+			expectedError: `strict SNI enabled - no certificate for server name "unknown.example.com"`,
+		},
+		{
+			desc:          "no server name",
+			expectedError: "strict SNI enabled - no server name",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cert, err := getCertificate(&tls.ClientHelloInfo{ServerName: test.serverName})
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+				assert.Nil(t, cert)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, cert)
+		})
+	}
+}
@@ -18,15 +18,46 @@ const (
 type ClientCA struct {
 	Files    []string
 	Optional bool
+	Headers  *ClientCertHeaders
+	// CRLFiles are the CRLs of the CAs, rejecting the client certificates they revoke.
+	CRLFiles []string
+	// OCSP rejects the client certificates reported as revoked by their OCSP responder,
+	// and the ones whose status can't be fetched unless OCSPSoftFail is set.
+	OCSP         bool
+	OCSPSoftFail bool
+}
+
+// ClientCertHeaders defines the request headers forwarding the details of the client certificate to the backends,
+// a detail with an empty header name being left out.
+type ClientCertHeaders struct {
+	PEM     string
+	Subject string
+	SANs    string
+	Serial  string
 }
 
 // TLS configures TLS for an entry point
 type TLS struct {
-	MinVersion    string `export:"true"`
-	CipherSuites  []string
-	Certificates  Certificates
-	ClientCAFiles []string // Deprecated
-	ClientCA      ClientCA
+	MinVersion       string `export:"true"`
+	MaxVersion       string `export:"true"`
+	CipherSuites     []string
+	CurvePreferences []string `export:"true"`
+	Certificates     Certificates
+	// DefaultCertificate is served to the handshakes without SNI server name, or whose server name matches no certificate.
+	DefaultCertificate *Certificate
+	ClientCAFiles      []string // Deprecated
+	ClientCA           ClientCA
+	SNIStrict          bool `export:"true"`
+}
+
+// Options returns the TLS versions, cipher suites and curves of the entry point.
+func (t *TLS) Options() *Options {
+	return &Options{
+		MinVersion:       t.MinVersion,
+		MaxVersion:       t.MaxVersion,
+		CipherSuites:     t.CipherSuites,
+		CurvePreferences: t.CurvePreferences,
+	}
 }
 
 // RootCAs hold the CA we want to have in root
@@ -1,12 +1,16 @@
 package types
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -17,16 +21,80 @@ import (
 	"github.com/containous/traefik/log"
 	traefikTls "github.com/containous/traefik/tls"
 	"github.com/ryanuber/go-glob"
+	"golang.org/x/net/proxy"
 )
 
 // Backend holds backend configuration.
 type Backend struct {
-	Servers        map[string]Server `json:"servers,omitempty"`
-	CircuitBreaker *CircuitBreaker   `json:"circuitBreaker,omitempty"`
-	LoadBalancer   *LoadBalancer     `json:"loadBalancer,omitempty"`
-	MaxConn        *MaxConn          `json:"maxConn,omitempty"`
-	HealthCheck    *HealthCheck      `json:"healthCheck,omitempty"`
-	Buffering      *Buffering        `json:"buffering,omitempty"`
+	Servers            map[string]Server   `json:"servers,omitempty"`
+	CircuitBreaker     *CircuitBreaker     `json:"circuitBreaker,omitempty"`
+	LoadBalancer       *LoadBalancer       `json:"loadBalancer,omitempty"`
+	MaxConn            *MaxConn            `json:"maxConn,omitempty"`
+	AdaptiveLimit      *AdaptiveLimit      `json:"adaptiveLimit,omitempty"`
+	HealthCheck        *HealthCheck        `json:"healthCheck,omitempty"`
+	Buffering          *Buffering          `json:"buffering,omitempty"`
+	ForwardingTimeouts *ForwardingTimeouts `json:"forwardingTimeouts,omitempty"`
+	Proxy              *OutboundProxy      `json:"proxy,omitempty"`
+	HostHeader         *HostHeader         `json:"hostHeader,omitempty"`
+	TLS                *BackendTLS         `json:"tls,omitempty"`
+	DialPolicy         string              `json:"dialPolicy,omitempty"`
+	// ProxyProtocol is the version (1 or 2) of the PROXY protocol header announcing the clients to the servers.
+	ProxyProtocol int `json:"proxyProtocol,omitempty"`
+}
+
+// Dial policies of the backend servers, used when their hostnames resolve to both IPv6 and IPv4 addresses.
+const (
+	DialPolicyPreferIPv6    = "preferIPv6"
+	DialPolicyPreferIPv4    = "preferIPv4"
+	DialPolicyHappyEyeballs = "happyEyeballs"
+)
+
+// Host header strategies
+const (
+	HostHeaderPass    = "pass"
+	HostHeaderBackend = "backend"
+	HostHeaderCustom  = "custom"
+)
+
+// HostHeader holds the strategy used to set the Host header of the forwarded requests:
+// pass the original one, use the host of the backend server, or use a custom value.
+type HostHeader struct {
+	Strategy string `json:"strategy,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
+
+// ClientCA requires the clients of a frontend to present a certificate signed by one of the CAs,
+// the certificate being optional when Optional is set. It replaces the client CA of the TLS entry points for the hosts of the frontend.
+type ClientCA struct {
+	Files    []traefikTls.FileOrContent `json:"files,omitempty"`
+	Optional bool                       `json:"optional,omitempty"`
+}
+
+// IPStrategy holds the strategy used to get the IP of the client checked by the whitelists:
+// the address of the connection by default, or the IP at the given depth of X-Forwarded-For,
+// or else the first IP of X-Forwarded-For, from the right, which is not excluded.
+type IPStrategy struct {
+	Depth       int      `json:"depth,omitempty"`
+	ExcludedIPs []string `json:"excludedIPs,omitempty"`
+}
+
+// TLS verification policies of the backend servers certificates
+const (
+	TLSVerificationVerify       = "verify"
+	TLSVerificationSkipHostname = "skipHostname"
+	TLSVerificationInsecure     = "insecure"
+)
+
+// BackendTLS holds the TLS configuration used to reach the HTTPS servers of a backend.
+// The servers of a service mesh may be identified by URIs, such as SPIFFE IDs, rather than by host names.
+// The client certificate managed in memory by a provider is referenced by its CertificateName in the tls.ClientCertificates.
+type BackendTLS struct {
+	ServerName      string                  `json:"serverName,omitempty"`
+	RootCAs         traefikTls.RootCAs      `json:"rootCAs,omitempty"`
+	Verification    string                  `json:"verification,omitempty"`
+	Certificate     *traefikTls.Certificate `json:"certificate,omitempty"`
+	CertificateName string                  `json:"certificateName,omitempty"`
+	PeerURIs        []string                `json:"peerURIs,omitempty"`
 }
 
 // MaxConn holds maximum connection configuration
@@ -35,6 +103,14 @@ type MaxConn struct {
 	ExtractorFunc string `json:"extractorFunc,omitempty"`
 }
 
+// AdaptiveLimit holds the bounds of the concurrency limit of a backend,
+// which is adjusted to the latency of its requests.
+type AdaptiveLimit struct {
+	InitialLimit int `json:"initialLimit,omitempty"`
+	MinLimit     int `json:"minLimit,omitempty"`
+	MaxLimit     int `json:"maxLimit,omitempty"`
+}
+
 // LoadBalancer holds load balancing configuration.
 type LoadBalancer struct {
 	Method     string      `json:"method,omitempty"`
@@ -43,8 +119,12 @@ type LoadBalancer struct {
 }
 
 // Stickiness holds sticky session configuration.
+// When Header or Query is set, the requests are sent to the server elected by hashing
+// the value of the request header or of the query parameter, instead of using a cookie.
 type Stickiness struct {
 	CookieName string `json:"cookieName,omitempty"`
+	Header     string `json:"header,omitempty"`
+	Query      string `json:"query,omitempty"`
 }
 
 // CircuitBreaker holds circuit breaker configuration.
@@ -63,15 +143,40 @@ type Buffering struct {
 
 // HealthCheck holds HealthCheck configuration
 type HealthCheck struct {
-	Path     string `json:"path,omitempty"`
-	Port     int    `json:"port,omitempty"`
-	Interval string `json:"interval,omitempty"`
+	Type     string            `json:"type,omitempty"`
+	Path     string            `json:"path,omitempty"`
+	Port     int               `json:"port,omitempty"`
+	Service  string            `json:"service,omitempty"`
+	Interval string            `json:"interval,omitempty"`
+	Timeout  string            `json:"timeout,omitempty"`
+	Hostname string            `json:"hostname,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	// ProxyProtocol is the version (1 or 2) of the PROXY protocol header sent by the probes.
+	ProxyProtocol int `json:"proxyProtocol,omitempty"`
+}
+
+const (
+	// HealthCheckTypeHTTP checks the servers with HTTP GET requests on the health check path (default).
+	HealthCheckTypeHTTP = "http"
+	// HealthCheckTypeGRPC checks the servers with the grpc.health.v1.Health/Check RPC for the health check service.
+	HealthCheckTypeGRPC = "grpc"
+	// HealthCheckTypeTCP only checks that a TCP connection (and a TLS handshake for the https servers) can be established.
+	HealthCheckTypeTCP = "tcp"
+)
+
+// ForwardingTimeouts holds timeout overrides for requests forwarded to the backend servers.
+// A zero value means that the timeout is inherited from the upper level (frontend, backend, global).
+type ForwardingTimeouts struct {
+	DialTimeout           flaeg.Duration `json:"dialTimeout,omitempty"`
+	ResponseHeaderTimeout flaeg.Duration `json:"responseHeaderTimeout,omitempty"`
+	IdleConnTimeout       flaeg.Duration `json:"idleConnTimeout,omitempty"`
 }
 
 // Server holds server configuration.
 type Server struct {
-	URL    string `json:"url,omitempty"`
-	Weight int    `json:"weight"`
+	URL    string            `json:"url,omitempty"`
+	Weight int               `json:"weight"`
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // Route holds route configuration.
@@ -173,10 +278,43 @@ type Frontend struct {
 	Priority             int                   `json:"priority"`
 	BasicAuth            []string              `json:"basicAuth"`
 	WhitelistSourceRange []string              `json:"whitelistSourceRange,omitempty"`
+	WhitelistIPStrategy  *IPStrategy           `json:"whitelistIPStrategy,omitempty"`
 	Headers              *Headers              `json:"headers,omitempty"`
 	Errors               map[string]*ErrorPage `json:"errors,omitempty"`
 	RateLimit            *RateLimit            `json:"ratelimit,omitempty"`
 	Redirect             *Redirect             `json:"redirect,omitempty"`
+	ForwardingTimeouts   *ForwardingTimeouts   `json:"forwardingTimeouts,omitempty"`
+	HostHeader           *HostHeader           `json:"hostHeader,omitempty"`
+	ServerSelector       string                `json:"serverSelector,omitempty"`
+	TLSPassthrough       bool                  `json:"tlsPassthrough,omitempty"`
+	Canary               *Canary               `json:"canary,omitempty"`
+	TrafficSplit         *TrafficSplit         `json:"trafficSplit,omitempty"`
+	Tenant               string                `json:"tenant,omitempty"`
+	PriorityClass        string                `json:"priorityClass,omitempty"`
+	ACMEResolver         string                `json:"acmeResolver,omitempty"`
+	ClientCA             *ClientCA             `json:"clientCA,omitempty"`
+	TLSOptions           string                `json:"tlsOptions,omitempty"`
+}
+
+// Canary configures the canary release of a frontend: the share of its traffic sent to the servers
+// of the canary backend increases step by step, as long as they stay under the error rate and latency thresholds.
+type Canary struct {
+	Backend      string  `json:"backend,omitempty"`
+	Steps        []int   `json:"steps,omitempty"`
+	StepInterval string  `json:"stepInterval,omitempty"`
+	MinRequests  int64   `json:"minRequests,omitempty"`
+	MaxErrorRate float64 `json:"maxErrorRate,omitempty"`
+	MaxLatency   string  `json:"maxLatency,omitempty"`
+	OnFailure    string  `json:"onFailure,omitempty"`
+}
+
+// TrafficSplit configures the share of the users of a frontend sent to the servers of an alternate backend,
+// the users being identified by a header or a cookie.
+type TrafficSplit struct {
+	Backend    string `json:"backend,omitempty"`
+	Percentage int    `json:"percentage,omitempty"`
+	Header     string `json:"header,omitempty"`
+	Cookie     string `json:"cookie,omitempty"`
 }
 
 // Redirect configures a redirection of an entry point to another, or to an URL
@@ -221,9 +359,10 @@ type Configurations map[string]*Configuration
 
 // Configuration of a provider.
 type Configuration struct {
-	Backends  map[string]*Backend         `json:"backends,omitempty"`
-	Frontends map[string]*Frontend        `json:"frontends,omitempty"`
-	TLS       []*traefikTls.Configuration `json:"tls,omitempty"`
+	Backends   map[string]*Backend            `json:"backends,omitempty"`
+	Frontends  map[string]*Frontend           `json:"frontends,omitempty"`
+	TLS        []*traefikTls.Configuration    `json:"tls,omitempty"`
+	TLSOptions map[string]*traefikTls.Options `json:"tlsOptions,omitempty"`
 }
 
 // ConfigMessage hold configuration information exchanged between parts of traefik.
@@ -357,6 +496,22 @@ type Cluster struct {
 	Store *Store `export:"true"`
 }
 
+// HeaderNormalization holds the normalization applied to the request headers before the rule matching.
+type HeaderNormalization struct {
+	MergeDuplicates  []string `export:"true"`
+	RejectDuplicates []string `export:"true"`
+	HopByHopHeaders  []string `export:"true"`
+	Canonicalize     bool     `export:"true"`
+}
+
+// Compression holds the filters of the responses compressed by an entry point.
+type Compression struct {
+	IncludedContentTypes []string `export:"true"`
+	ExcludedContentTypes []string `export:"true"`
+	ExcludedPaths        []string `export:"true"`
+	MinResponseBodyBytes int      `export:"true"`
+}
+
 // Auth holds authentication configuration (BASIC, DIGEST, users)
 type Auth struct {
 	Basic       *Basic   `export:"true"`
@@ -407,8 +562,17 @@ type Metrics struct {
 
 // Prometheus can contain specific configuration used by the Prometheus Metrics exporter
 type Prometheus struct {
-	Buckets    Buckets `description:"Buckets for latency metrics" export:"true"`
-	EntryPoint string  `description:"EntryPoint" export:"true"`
+	Buckets    Buckets         `description:"Buckets for latency metrics" export:"true"`
+	EntryPoint string          `description:"EntryPoint" export:"true"`
+	Push       *PrometheusPush `description:"Push the metrics periodically instead of only exposing them" export:"true"`
+}
+
+// PrometheusPush contains the endpoint and interval configuration to push the Prometheus metrics
+type PrometheusPush struct {
+	Protocol     string `description:"Push protocol: pushgateway | remotewrite" export:"true"`
+	Address      string `description:"Pushgateway or remote-write endpoint URL"`
+	Job          string `description:"Job label of the pushed metrics" export:"true"`
+	PushInterval string `description:"Prometheus push interval" export:"true"`
 }
 
 // Datadog contains address and metrics pushing interval configuration
@@ -467,10 +631,364 @@ type TraefikLog struct {
 	Format   string `json:"format,omitempty" description:"Traefik log format: json | common"`
 }
 
+// AuditLog holds the configuration settings for the audit logger.
+type AuditLog struct {
+	FilePath string `json:"file,omitempty" description:"Audit log file path" export:"true"`
+}
+
+// Notifications holds the configuration of the notifications of operational events.
+type Notifications struct {
+	Webhooks    Webhooks   `description:"Webhooks receiving the notifications: 'URL:https://hooks.example.com Secret:s3cr3t Events:acme.failure,backend.down'" export:"true"`
+	Slack       *Slack     `description:"Send the notifications to Slack" export:"true"`
+	PagerDuty   *PagerDuty `description:"Trigger PagerDuty alerts from the notifications" export:"true"`
+	DedupWindow string     `description:"Duration during which an event about the same issue is sent only once" export:"true"`
+}
+
+// Slack holds the configuration of the notifications sent to a Slack incoming webhook.
+type Slack struct {
+	WebhookURL string   `description:"Slack incoming webhook URL"`
+	Channel    string   `description:"Channel overriding the one of the incoming webhook" export:"true"`
+	Username   string   `description:"Username overriding the one of the incoming webhook" export:"true"`
+	Template   string   `description:"Go template of the messages" export:"true"`
+	Events     []string `description:"Events sent to Slack" export:"true"`
+}
+
+// PagerDuty holds the configuration of the alerts triggered through the PagerDuty Events API v2.
+type PagerDuty struct {
+	RoutingKey string   `description:"PagerDuty integration key"`
+	Template   string   `description:"Go template of the alert summaries" export:"true"`
+	Events     []string `description:"Events triggering alerts" export:"true"`
+}
+
+// CTMonitor holds the configuration of the monitoring of the certificates issued for the domains,
+// as logged in the Certificate Transparency logs.
+type CTMonitor struct {
+	Domains        []string `description:"Domains whose certificates are monitored, '%' being a wildcard (e.g. '%.example.com')" export:"true"`
+	AllowedIssuers []string `description:"Expected issuers of the certificates, matched against the issuer names" export:"true"`
+	Interval       string   `description:"Interval between the checks" export:"true"`
+	URL            string   `description:"URL of the crt.sh compatible search service" export:"true"`
+}
+
+// VaultPKI holds the configuration of the certificates issued by the PKI secrets engine of Vault.
+type VaultPKI struct {
+	Address     string         `description:"Address of the Vault server, e.g. 'https://vault.example.com:8200'" export:"true"`
+	Token       string         `description:"Vault token, read from the VAULT_TOKEN environment variable when empty"`
+	Mount       string         `description:"Mount path of the PKI secrets engine" export:"true"`
+	Role        string         `description:"PKI role issuing the certificates" export:"true"`
+	EntryPoint  string         `description:"Entrypoint serving the certificates" export:"true"`
+	Domains     []string       `description:"Domains whose certificates are issued on start" export:"true"`
+	OnHostRule  bool           `description:"Issue the certificates of the Host rules of the frontends of the entrypoint" export:"true"`
+	TTL         flaeg.Duration `description:"Requested lifetime of the certificates, the role one when zero" export:"true"`
+	RenewBefore flaeg.Duration `description:"Renew the certificates this duration before they expire, a third of their lifetime when zero" export:"true"`
+	Storage     string         `description:"File storing the issued certificates and their leases" export:"true"`
+}
+
+// OCSP holds the configuration of the OCSP stapling of the served certificates.
+type OCSP struct {
+	RefreshInterval flaeg.Duration `description:"Interval between the refreshes of the OCSP responses" export:"true"`
+}
+
+// SessionTickets holds the configuration of the rotation of the TLS session ticket keys.
+type SessionTickets struct {
+	RotationInterval flaeg.Duration `description:"Interval between the rotations of the session ticket keys" export:"true"`
+	EncryptionKey    string         `description:"Secret encrypting the session ticket keys stored in the KV store, required in cluster mode"`
+}
+
+// Webhook is an URL receiving the operational events as JSON payloads.
+// The payloads are signed with the secret when it is set, and only the listed events are sent when Events is not empty.
+type Webhook struct {
+	URL    string   `description:"Webhook URL"`
+	Secret string   `description:"Secret used to sign the payloads"`
+	Events []string `description:"Events sent to the webhook" export:"true"`
+}
+
+// Webhooks holds a Webhook parser
+type Webhooks []*Webhook
+
+//Set adds a webhook written as 'URL:... Secret:... Events:a,b' into the parser
+func (w *Webhooks) Set(str string) error {
+	webhook := &Webhook{}
+	for _, field := range strings.Fields(str) {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("bad Webhook format: %s", str)
+		}
+		switch strings.ToLower(kv[0]) {
+		case "url":
+			webhook.URL = kv[1]
+		case "secret":
+			webhook.Secret = kv[1]
+		case "events":
+			webhook.Events = strings.Split(kv[1], ",")
+		default:
+			return fmt.Errorf("unknown Webhook field %s: %s", kv[0], str)
+		}
+	}
+	if len(webhook.URL) == 0 {
+		return fmt.Errorf("missing Webhook URL: %s", str)
+	}
+	*w = append(*w, webhook)
+	return nil
+}
+
+//Get []*Webhook
+func (w *Webhooks) Get() interface{} { return []*Webhook(*w) }
+
+//String returns []*Webhook in string
+func (w *Webhooks) String() string { return fmt.Sprintf("%+v", *w) }
+
+//SetValue sets []*Webhook into the parser
+func (w *Webhooks) SetValue(val interface{}) {
+	*w = val.(Webhooks)
+}
+
+// Tenancy holds the quotas of the tenants, the frontends being tagged with their tenant.
+type Tenancy struct {
+	DefaultQuota *TenantQuota `description:"Quota of the tenants without a specific one" export:"true"`
+	Quotas       TenantQuotas `description:"Quotas of specific tenants: 'Tenant:team-a MaxFrontends:20 MaxRPS:100 MaxConnections:50'" export:"true"`
+}
+
+// TenantQuota limits the frontends of a tenant, and the requests they receive altogether.
+// A zero limit means unlimited.
+type TenantQuota struct {
+	Tenant         string `description:"Tenant of the quota" export:"true"`
+	MaxFrontends   int    `description:"Maximum number of frontends" export:"true"`
+	MaxRPS         int    `description:"Maximum number of requests per second" export:"true"`
+	MaxConnections int64  `description:"Maximum number of concurrent connections" export:"true"`
+}
+
+// TenantQuotas holds a TenantQuota parser
+type TenantQuotas []*TenantQuota
+
+//Set adds a quota written as 'Tenant:... MaxFrontends:... MaxRPS:... MaxConnections:...' into the parser
+func (q *TenantQuotas) Set(str string) error {
+	quota := &TenantQuota{}
+	for _, field := range strings.Fields(str) {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("bad TenantQuota format: %s", str)
+		}
+		var err error
+		switch strings.ToLower(kv[0]) {
+		case "tenant":
+			quota.Tenant = kv[1]
+		case "maxfrontends":
+			quota.MaxFrontends, err = strconv.Atoi(kv[1])
+		case "maxrps":
+			quota.MaxRPS, err = strconv.Atoi(kv[1])
+		case "maxconnections":
+			quota.MaxConnections, err = strconv.ParseInt(kv[1], 10, 64)
+		default:
+			return fmt.Errorf("unknown TenantQuota field %s: %s", kv[0], str)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid TenantQuota %s: %v", kv[0], err)
+		}
+	}
+	if len(quota.Tenant) == 0 {
+		return fmt.Errorf("missing TenantQuota tenant: %s", str)
+	}
+	*q = append(*q, quota)
+	return nil
+}
+
+//Get []*TenantQuota
+func (q *TenantQuotas) Get() interface{} { return []*TenantQuota(*q) }
+
+//String returns []*TenantQuota in string
+func (q *TenantQuotas) String() string { return fmt.Sprintf("%+v", *q) }
+
+//SetValue sets []*TenantQuota into the parser
+func (q *TenantQuotas) SetValue(val interface{}) {
+	*q = val.(TenantQuotas)
+}
+
+// Quota returns the quota of a tenant, which is the default one when it has no specific quota.
+func (t *Tenancy) Quota(tenant string) *TenantQuota {
+	if t == nil {
+		return nil
+	}
+	for _, quota := range t.Quotas {
+		if quota.Tenant == tenant {
+			return quota
+		}
+	}
+	return t.DefaultQuota
+}
+
+// Overload configures the protection against overload: beyond the maximum number of concurrent requests,
+// the requests are queued by priority, the lowest priority ones being shed first when the queue is full.
+type Overload struct {
+	MaxConcurrency int             `description:"Maximum number of requests handled concurrently" export:"true"`
+	MaxQueueSize   int             `description:"Maximum number of requests waiting to be handled" export:"true"`
+	QueueTimeout   flaeg.Duration  `description:"Maximum duration a request waits to be handled. If zero, it waits until its client gives up" export:"true"`
+	ClassHeader    string          `description:"Request header selecting the priority class of the request, overriding the one of its frontend" export:"true"`
+	DefaultClass   string          `description:"Priority class of the requests without one" export:"true"`
+	Classes        PriorityClasses `description:"Priority classes: 'Name:critical Priority:10'" export:"true"`
+}
+
+// PriorityClass is a class of requests, the requests of the classes with the highest priority being handled first.
+type PriorityClass struct {
+	Name     string `description:"Name of the class" export:"true"`
+	Priority int    `description:"Priority of the class" export:"true"`
+}
+
+// PriorityClasses holds a PriorityClass parser
+type PriorityClasses []PriorityClass
+
+//Set adds a class written as 'Name:... Priority:...' into the parser
+func (c *PriorityClasses) Set(str string) error {
+	class := PriorityClass{}
+	for _, field := range strings.Fields(str) {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("bad PriorityClass format: %s", str)
+		}
+		switch strings.ToLower(kv[0]) {
+		case "name":
+			class.Name = kv[1]
+		case "priority":
+			priority, err := strconv.Atoi(kv[1])
+			if err != nil {
+				return fmt.Errorf("invalid PriorityClass priority: %v", err)
+			}
+			class.Priority = priority
+		default:
+			return fmt.Errorf("unknown PriorityClass field %s: %s", kv[0], str)
+		}
+	}
+	if len(class.Name) == 0 {
+		return fmt.Errorf("missing PriorityClass name: %s", str)
+	}
+	*c = append(*c, class)
+	return nil
+}
+
+//Get []PriorityClass
+func (c *PriorityClasses) Get() interface{} { return []PriorityClass(*c) }
+
+//String returns []PriorityClass in string
+func (c *PriorityClasses) String() string { return fmt.Sprintf("%+v", *c) }
+
+//SetValue sets []PriorityClass into the parser
+func (c *PriorityClasses) SetValue(val interface{}) {
+	*c = val.(PriorityClasses)
+}
+
+// Policies applied to the frontends defined by several providers.
+const (
+	ConflictPolicyError     = "error"
+	ConflictPolicyFirstWins = "first-wins"
+	ConflictPolicyMerge     = "merge"
+)
+
+// ProvidersMerge configures how the frontends defined by several providers are resolved:
+// the definition of the provider with the highest priority wins, or the configuration is rejected,
+// or the servers and routes of the definitions are merged.
+// Without conflict policy, each provider wires its own frontends.
+type ProvidersMerge struct {
+	ConflictPolicy string             `description:"Policy applied to the frontends defined by several providers: error, first-wins or merge" export:"true"`
+	Priorities     ProviderPriorities `description:"Priorities of the providers, the highest first, the other providers having the priority 0: 'Provider:file Priority:10'" export:"true"`
+}
+
+// Priority returns the priority of a provider.
+func (m *ProvidersMerge) Priority(providerName string) int {
+	if m == nil {
+		return 0
+	}
+	for _, priority := range m.Priorities {
+		if priority.Provider == providerName {
+			return priority.Priority
+		}
+	}
+	return 0
+}
+
+// ProviderPriority is the priority of a provider, by the name of its configuration.
+type ProviderPriority struct {
+	Provider string `description:"Name of the provider" export:"true"`
+	Priority int    `description:"Priority of the provider" export:"true"`
+}
+
+// ProviderPriorities holds a ProviderPriority parser
+type ProviderPriorities []ProviderPriority
+
+//Set adds a priority written as 'Provider:... Priority:...' into the parser
+func (p *ProviderPriorities) Set(str string) error {
+	priority := ProviderPriority{}
+	for _, field := range strings.Fields(str) {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("bad ProviderPriority format: %s", str)
+		}
+		switch strings.ToLower(kv[0]) {
+		case "provider":
+			priority.Provider = kv[1]
+		case "priority":
+			value, err := strconv.Atoi(kv[1])
+			if err != nil {
+				return fmt.Errorf("invalid ProviderPriority priority: %v", err)
+			}
+			priority.Priority = value
+		default:
+			return fmt.Errorf("unknown ProviderPriority field %s: %s", kv[0], str)
+		}
+	}
+	if len(priority.Provider) == 0 {
+		return fmt.Errorf("missing ProviderPriority provider: %s", str)
+	}
+	*p = append(*p, priority)
+	return nil
+}
+
+//Get []ProviderPriority
+func (p *ProviderPriorities) Get() interface{} { return []ProviderPriority(*p) }
+
+//String returns []ProviderPriority in string
+func (p *ProviderPriorities) String() string { return fmt.Sprintf("%+v", *p) }
+
+//SetValue sets []ProviderPriority into the parser
+func (p *ProviderPriorities) SetValue(val interface{}) {
+	*p = val.(ProviderPriorities)
+}
+
+// ProviderConflict is a frontend defined by several providers.
+type ProviderConflict struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Providers defining the frontend, by decreasing priority.
+	Providers []string `json:"providers"`
+	Policy    string   `json:"policy"`
+}
+
+// Priority returns the priority of a class, unknown classes having the priority of the default class, or zero.
+func (o *Overload) Priority(class string) int {
+	if len(class) == 0 {
+		class = o.DefaultClass
+	}
+	for _, c := range o.Classes {
+		if c.Name == class {
+			return c.Priority
+		}
+	}
+	if class != o.DefaultClass {
+		return o.Priority(o.DefaultClass)
+	}
+	return 0
+}
+
 // AccessLog holds the configuration settings for the access logger (middlewares/accesslog).
 type AccessLog struct {
 	FilePath string `json:"file,omitempty" description:"Access log file path. Stdout is used when omitted or empty" export:"true"`
 	Format   string `json:"format,omitempty" description:"Access log format: json | common" export:"true"`
+	GeoIP    *GeoIP `json:"geoIP,omitempty" description:"Client location lookup" export:"true"`
+}
+
+// GeoIP holds the MaxMind databases used to look up the location of the clients.
+type GeoIP struct {
+	CityDatabase   string         `json:"cityDatabase,omitempty" description:"Path of the MaxMind City or Country database" export:"true"`
+	ASNDatabase    string         `json:"asnDatabase,omitempty" description:"Path of the MaxMind ASN database" export:"true"`
+	ReloadInterval flaeg.Duration `json:"reloadInterval,omitempty" description:"Interval at which the databases are reloaded when modified. If zero, they are never reloaded" export:"true"`
 }
 
 // ClientTLS holds TLS specific configurations as client
@@ -548,3 +1066,55 @@ func (clientTLS *ClientTLS) CreateTLSConfig() (*tls.Config, error) {
 	}
 	return TLSConfig, nil
 }
+
+// OutboundProxy holds the configuration of the proxy used to reach remote servers,
+// instead of the one defined by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+type OutboundProxy struct {
+	URL string `json:"url,omitempty" description:"Proxy URL: http://, https://, socks5:// or direct to bypass the proxy of the environment"`
+}
+
+// ConfigureTransport configures the transport to reach the remote servers through the proxy.
+func (p *OutboundProxy) ConfigureTransport(transport *http.Transport) error {
+	if p == nil || len(p.URL) == 0 {
+		return nil
+	}
+
+	if p.URL == "direct" {
+		transport.Proxy = nil
+		return nil
+	}
+
+	proxyURL, err := url.Parse(p.URL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL %q: %v", p.URL, err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https":
+		transport.Proxy = http.ProxyURL(proxyURL)
+	case "socks5":
+		forward := proxy.Dialer(&net.Dialer{})
+		if transport.DialContext != nil {
+			forward = contextDialer(transport.DialContext)
+		}
+		dialer, err := proxy.FromURL(proxyURL, forward)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL %q: %v", p.URL, err)
+		}
+		transport.Proxy = nil
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialer.Dial(network, addr)
+		}
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+
+	return nil
+}
+
+// contextDialer allows to use a DialContext function as a proxy.Dialer.
+type contextDialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
+func (d contextDialer) Dial(network, addr string) (net.Conn, error) {
+	return d(context.Background(), network, addr)
+}
@@ -0,0 +1,361 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
+)
+
+const (
+	defaultMount         = "pki"
+	defaultCheckInterval = time.Minute
+)
+
+// PKI issues the server certificates of the domains from the PKI secrets engine of Vault,
+// and renews them before they expire.
+// The issued certificates are stored along with their leases, to be served again on restart until they are renewed.
+type PKI struct {
+	address       string
+	token         string
+	mount         string
+	role          string
+	ttl           time.Duration
+	renewBefore   time.Duration
+	storage       string
+	checkInterval time.Duration
+	client        *http.Client
+	storageLock   sync.Mutex
+	lock          sync.RWMutex
+	// certificates are the issued certificates by domain.
+	certificates map[string]*Certificate
+	// pending are the domains whose certificate is being issued.
+	pending map[string]bool
+}
+
+// Certificate is a certificate issued by Vault, as stored.
+type Certificate struct {
+	Domain       string
+	Certificate  []byte
+	PrivateKey   []byte
+	SerialNumber string
+	LeaseID      string    `json:",omitempty"`
+	NotBefore    time.Time `json:"-"`
+	NotAfter     time.Time `json:"-"`
+	tlsCert      *tls.Certificate
+}
+
+// issueResponse is the response of the issue endpoint of the PKI secrets engine.
+type issueResponse struct {
+	LeaseID string `json:"lease_id"`
+	Data    struct {
+		Certificate  string   `json:"certificate"`
+		IssuingCA    string   `json:"issuing_ca"`
+		CAChain      []string `json:"ca_chain"`
+		PrivateKey   string   `json:"private_key"`
+		SerialNumber string   `json:"serial_number"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// NewPKI creates the PKI from its configuration, loading the certificates of its storage.
+// The token is read from the VAULT_TOKEN environment variable when it isn't configured.
+func NewPKI(config *types.VaultPKI) (*PKI, error) {
+	if len(config.Address) == 0 {
+		return nil, errors.New("missing Vault address")
+	}
+	if len(config.Role) == 0 {
+		return nil, errors.New("missing PKI role")
+	}
+
+	token := config.Token
+	if len(token) == 0 {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	mount := strings.Trim(config.Mount, "/")
+	if len(mount) == 0 {
+		mount = defaultMount
+	}
+
+	p := &PKI{
+		address:       strings.TrimRight(config.Address, "/"),
+		token:         token,
+		mount:         mount,
+		role:          config.Role,
+		ttl:           time.Duration(config.TTL),
+		renewBefore:   time.Duration(config.RenewBefore),
+		storage:       config.Storage,
+		checkInterval: defaultCheckInterval,
+		client:        &http.Client{Timeout: 30 * time.Second},
+		certificates:  make(map[string]*Certificate),
+		pending:       make(map[string]bool),
+	}
+	if err := p.load(); err != nil {
+		return nil, fmt.Errorf("unable to load the Vault PKI storage %s: %v", p.storage, err)
+	}
+	return p, nil
+}
+
+// GetCertificate wraps the GetCertificate callback of a TLS config, returning the certificate issued by Vault
+// for the server name, exactly or by wildcard, or else the certificate returned by the callback.
+func (p *PKI) GetCertificate(next func(*tls.ClientHelloInfo) (*tls.Certificate, error)) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if cert := p.certificate(clientHello.ServerName); cert != nil {
+			return cert, nil
+		}
+		if next != nil {
+			return next(clientHello)
+		}
+		return nil, nil
+	}
+}
+
+func (p *PKI) certificate(serverName string) *tls.Certificate {
+	if len(serverName) == 0 {
+		return nil
+	}
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	name := strings.TrimRight(types.CanonicalDomain(serverName), ".")
+	if cert, ok := p.certificates[name]; ok {
+		return cert.tlsCert
+	}
+	if labels := strings.Split(name, "."); len(labels) > 1 {
+		labels[0] = "*"
+		if cert, ok := p.certificates[strings.Join(labels, ".")]; ok {
+			return cert.tlsCert
+		}
+	}
+	return nil
+}
+
+// Certificates returns the certificates issued by Vault.
+func (p *PKI) Certificates() []*tls.Certificate {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	var certs []*tls.Certificate
+	for _, cert := range p.certificates {
+		certs = append(certs, cert.tlsCert)
+	}
+	return certs
+}
+
+// AddDomains issues in the background the certificates of the domains which have none yet.
+func (p *PKI) AddDomains(domains []string) {
+	for _, domain := range domains {
+		domain = types.CanonicalDomain(domain)
+		if len(domain) == 0 {
+			continue
+		}
+
+		p.lock.Lock()
+		_, issued := p.certificates[domain]
+		pending := p.pending[domain]
+		if !issued && !pending {
+			p.pending[domain] = true
+		}
+		p.lock.Unlock()
+		if issued || pending {
+			continue
+		}
+
+		d := domain
+		safe.Go(func() {
+			if err := p.issue(d); err != nil {
+				log.Errorf("Unable to issue the certificate of %s from Vault: %v", d, err)
+			}
+			p.lock.Lock()
+			delete(p.pending, d)
+			p.lock.Unlock()
+		})
+	}
+}
+
+// Run renews the certificates about to expire, every minute, until the context is done.
+func (p *PKI) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.renew()
+		}
+	}
+}
+
+// renew issues again the certificates reaching their renewal time, keeping the current ones on error.
+func (p *PKI) renew() {
+	for _, domain := range p.domainsToRenew(time.Now()) {
+		log.Infof("Renewing the certificate of %s from Vault", domain)
+		if err := p.issue(domain); err != nil {
+			log.Errorf("Unable to renew the certificate of %s from Vault: %v", domain, err)
+		}
+	}
+}
+
+// domainsToRenew returns the domains whose certificate expires within the renewal duration,
+// a third of the lifetime of the certificate when it isn't configured.
+func (p *PKI) domainsToRenew(now time.Time) []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	var domains []string
+	for domain, cert := range p.certificates {
+		renewBefore := p.renewBefore
+		if renewBefore <= 0 {
+			renewBefore = cert.NotAfter.Sub(cert.NotBefore) / 3
+		}
+		if !now.Before(cert.NotAfter.Add(-renewBefore)) {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// issue requests a certificate for the domain, and stores it.
+func (p *PKI) issue(domain string) error {
+	request := map[string]string{"common_name": domain}
+	if p.ttl > 0 {
+		request["ttl"] = p.ttl.String()
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/v1/%s/issue/%s", p.address, p.mount, p.role), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	issued := &issueResponse{}
+	if err := json.Unmarshal(data, issued); err != nil && resp.StatusCode == http.StatusOK {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.Join(issued.Errors, ", "))
+	}
+
+	chain := issued.Data.Certificate
+	if len(issued.Data.CAChain) > 0 {
+		chain += "\n" + strings.Join(issued.Data.CAChain, "\n")
+	} else if len(issued.Data.IssuingCA) > 0 {
+		chain += "\n" + issued.Data.IssuingCA
+	}
+	cert := &Certificate{
+		Domain:       domain,
+		Certificate:  []byte(chain),
+		PrivateKey:   []byte(issued.Data.PrivateKey),
+		SerialNumber: issued.Data.SerialNumber,
+		LeaseID:      issued.LeaseID,
+	}
+	if err := cert.parse(); err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	p.certificates[domain] = cert
+	p.lock.Unlock()
+	log.Infof("Issued the certificate of %s from Vault, serial %s, expiring at %s", domain, cert.SerialNumber, cert.NotAfter)
+
+	return p.save()
+}
+
+// parse loads the TLS certificate and the validity of the stored PEM certificate and key.
+func (c *Certificate) parse() error {
+	tlsCert, err := tls.X509KeyPair(c.Certificate, c.PrivateKey)
+	if err != nil {
+		return err
+	}
+	leaf, err := x509.ParseCertificate(tlsCert.Certificate[0])
+	if err != nil {
+		return err
+	}
+	tlsCert.Leaf = leaf
+
+	c.tlsCert = &tlsCert
+	c.NotBefore = leaf.NotBefore
+	c.NotAfter = leaf.NotAfter
+	return nil
+}
+
+// load reads the certificates of the storage, the storage being created by the first issued certificate.
+func (p *PKI) load() error {
+	if len(p.storage) == 0 {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(p.storage)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var certificates []*Certificate
+	if err := json.Unmarshal(data, &certificates); err != nil {
+		return err
+	}
+	for _, cert := range certificates {
+		if err := cert.parse(); err != nil {
+			log.Errorf("Unable to load the stored certificate of %s, it will be issued again: %v", cert.Domain, err)
+			continue
+		}
+		p.certificates[cert.Domain] = cert
+	}
+	log.Infof("Loaded %d certificates from the Vault PKI storage %s", len(p.certificates), p.storage)
+	return nil
+}
+
+func (p *PKI) save() error {
+	if len(p.storage) == 0 {
+		return nil
+	}
+	p.storageLock.Lock()
+	defer p.storageLock.Unlock()
+
+	p.lock.RLock()
+	var certificates []*Certificate
+	for _, cert := range p.certificates {
+		certificates = append(certificates, cert)
+	}
+	p.lock.RUnlock()
+
+	data, err := json.MarshalIndent(certificates, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p.storage, data, 0600)
+}
@@ -0,0 +1,215 @@
+package vault
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newVaultServer starts a PKI secrets engine issuing the certificates of the requested common names with a generated CA.
+func newVaultServer(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Internal CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	var serial int64 = 1
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Vault-Token") != token {
+			rw.WriteHeader(http.StatusForbidden)
+			rw.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		if req.URL.Path != "/v1/pki/issue/internal" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		request := map[string]string{}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&request))
+		ttl := time.Hour
+		if len(request["ttl"]) > 0 {
+			var err error
+			ttl, err = time.ParseDuration(request["ttl"])
+			require.NoError(t, err)
+		}
+
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		serial++
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: request["common_name"]},
+			DNSNames:     []string{request["common_name"]},
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(ttl),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+
+		response := issueResponse{LeaseID: "pki/issue/internal/" + request["common_name"]}
+		response.Data.Certificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+		response.Data.IssuingCA = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}))
+		response.Data.PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+		response.Data.SerialNumber = template.SerialNumber.String()
+		require.NoError(t, json.NewEncoder(rw).Encode(response))
+	}))
+}
+
+func TestPKIIssue(t *testing.T) {
+	server := newVaultServer(t, "s3cr3t")
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "vault")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config := &types.VaultPKI{
+		Address: server.URL,
+		Token:   "s3cr3t",
+		Role:    "internal",
+		TTL:     flaeg.Duration(2 * time.Hour),
+		Storage: filepath.Join(dir, "vault.json"),
+	}
+	pki, err := NewPKI(config)
+	require.NoError(t, err)
+
+	require.NoError(t, pki.issue("*.internal.example.com"))
+	require.NoError(t, pki.issue("api.example.com"))
+
+	getCertificate := pki.GetCertificate(func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return nil, nil
+	})
+	for serverName, expected := range map[string]string{
+		"api.example.com":          "api.example.com",
+		"API.example.com":          "api.example.com",
+		"web.internal.example.com": "*.internal.example.com",
+		"other.example.com":        "",
+	} {
+		cert, err := getCertificate(&tls.ClientHelloInfo{ServerName: serverName})
+		require.NoError(t, err)
+		if len(expected) == 0 {
+			assert.Nil(t, cert, serverName)
+			continue
+		}
+		require.NotNil(t, cert, serverName)
+		assert.Equal(t, expected, cert.Leaf.Subject.CommonName)
+		assert.Len(t, cert.Certificate, 2, "the issuing CA is served along with the certificate")
+		assert.InDelta(t, (2 * time.Hour).Seconds(), cert.Leaf.NotAfter.Sub(cert.Leaf.NotBefore).Seconds(), 5)
+	}
+
+	// the stored certificates are served again on restart
+	reloaded, err := NewPKI(config)
+	require.NoError(t, err)
+	require.Len(t, reloaded.certificates, 2)
+	assert.Equal(t, "pki/issue/internal/api.example.com", reloaded.certificates["api.example.com"].LeaseID)
+	assert.NotNil(t, reloaded.certificate("api.example.com"))
+}
+
+func TestPKIIssueError(t *testing.T) {
+	server := newVaultServer(t, "s3cr3t")
+	defer server.Close()
+
+	pki, err := NewPKI(&types.VaultPKI{Address: server.URL, Token: "wrong", Role: "internal"})
+	require.NoError(t, err)
+
+	assert.EqualError(t, pki.issue("api.example.com"), "unexpected status code 403: permission denied")
+	assert.Empty(t, pki.certificates)
+}
+
+func TestPKIDomainsToRenew(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		desc        string
+		renewBefore time.Duration
+		notBefore   time.Time
+		notAfter    time.Time
+		expected    bool
+	}{
+		{
+			desc:      "within the first two thirds of the lifetime",
+			notBefore: now.Add(-time.Hour),
+			notAfter:  now.Add(2 * time.Hour),
+		},
+		{
+			desc:      "within the last third of the lifetime",
+			notBefore: now.Add(-2 * time.Hour),
+			notAfter:  now.Add(time.Hour),
+			expected:  true,
+		},
+		{
+			desc:        "before the renewal duration",
+			renewBefore: 30 * time.Minute,
+			notBefore:   now.Add(-2 * time.Hour),
+			notAfter:    now.Add(time.Hour),
+		},
+		{
+			desc:        "within the renewal duration",
+			renewBefore: 30 * time.Minute,
+			notBefore:   now.Add(-2 * time.Hour),
+			notAfter:    now.Add(10 * time.Minute),
+			expected:    true,
+		},
+		{
+			desc:      "expired",
+			notBefore: now.Add(-2 * time.Hour),
+			notAfter:  now.Add(-time.Hour),
+			expected:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			pki := &PKI{
+				renewBefore: test.renewBefore,
+				certificates: map[string]*Certificate{
+					"api.example.com": {NotBefore: test.notBefore, NotAfter: test.notAfter},
+				},
+			}
+
+			domains := pki.domainsToRenew(now)
+			if test.expected {
+				assert.Equal(t, []string{"api.example.com"}, domains)
+			} else {
+				assert.Empty(t, domains)
+			}
+		})
+	}
+}
@@ -7,10 +7,15 @@ import (
 	"github.com/pkg/errors"
 )
 
+// UnixSocket is the address of the clients of the entry points listening on a Unix domain socket,
+// which are only in the white lists listing it.
+const UnixSocket = "unix"
+
 // IP allows to check that addresses are in a white list
 type IP struct {
 	whiteListsIPs []*net.IP
 	whiteListsNet []*net.IPNet
+	unixSocket    bool
 	insecure      bool
 }
 
@@ -24,6 +29,11 @@ func NewIP(whitelistStrings []string, insecure bool) (*IP, error) {
 
 	if !insecure {
 		for _, whitelistString := range whitelistStrings {
+			if whitelistString == UnixSocket {
+				ip.unixSocket = true
+				continue
+			}
+
 			ipAddr := net.ParseIP(whitelistString)
 			if ipAddr != nil {
 				ip.whiteListsIPs = append(ip.whiteListsIPs, &ipAddr)
@@ -46,6 +56,10 @@ func (ip *IP) Contains(addr string) (bool, net.IP, error) {
 		return true, nil, nil
 	}
 
+	if addr == UnixSocket {
+		return ip.unixSocket, nil, nil
+	}
+
 	ipAddr, err := ipFromRemoteAddr(addr)
 	if err != nil {
 		return false, nil, fmt.Errorf("unable to parse address: %s: %s", addr, err)
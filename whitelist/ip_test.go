@@ -297,6 +297,38 @@ func TestIsAllowed(t *testing.T) {
 	}
 }
 
+func TestUnixSocket(t *testing.T) {
+	cases := []struct {
+		desc             string
+		whitelistStrings []string
+		expected         bool
+	}{
+		{
+			desc:             "loopback",
+			whitelistStrings: []string{"127.0.0.1/8", "::1"},
+			expected:         false,
+		},
+		{
+			desc:             "unix socket",
+			whitelistStrings: []string{"1.2.3.4/24", UnixSocket},
+			expected:         true,
+		},
+	}
+
+	for _, test := range cases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			whiteLister, err := NewIP(test.whitelistStrings, false)
+			require.NoError(t, err)
+
+			allowed, _, err := whiteLister.Contains(UnixSocket)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, allowed)
+		})
+	}
+}
+
 func TestBrokenIPs(t *testing.T) {
 	brokenIPs := []string{
 		"foo",
@@ -0,0 +1,99 @@
+package whitelist
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/containous/traefik/types"
+	"github.com/pkg/errors"
+)
+
+const xForwardedFor = "X-Forwarded-For"
+
+// Strategy extracts the IP of the client checked by the whitelists from a request.
+type Strategy interface {
+	GetIP(req *http.Request) string
+}
+
+// NewStrategy returns the strategy configured by ipStrategy, the RemoteAddrStrategy when it is nil.
+func NewStrategy(ipStrategy *types.IPStrategy) (Strategy, error) {
+	if ipStrategy == nil {
+		return &RemoteAddrStrategy{}, nil
+	}
+
+	if ipStrategy.Depth < 0 {
+		return nil, errors.New("the depth of the IP strategy can't be negative")
+	}
+	if ipStrategy.Depth > 0 {
+		return &DepthStrategy{Depth: ipStrategy.Depth}, nil
+	}
+
+	if len(ipStrategy.ExcludedIPs) > 0 {
+		checker, err := NewIP(ipStrategy.ExcludedIPs, false)
+		if err != nil {
+			return nil, err
+		}
+		return &CheckerStrategy{Checker: checker}, nil
+	}
+
+	return &RemoteAddrStrategy{}, nil
+}
+
+// RemoteAddrStrategy returns the address of the connection of the request.
+type RemoteAddrStrategy struct{}
+
+// GetIP returns the IP of the connection of the request, or an empty string when its address can't be parsed.
+func (s *RemoteAddrStrategy) GetIP(req *http.Request) string {
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return ""
+	}
+	return ip
+}
+
+// DepthStrategy returns the IP at the given depth of X-Forwarded-For, counted from the right,
+// e.g. 1 for the client of a single load balancer appending the address of its own clients.
+type DepthStrategy struct {
+	Depth int
+}
+
+// GetIP returns the IP at the depth of X-Forwarded-For, or an empty string when there are not enough IPs.
+func (s *DepthStrategy) GetIP(req *http.Request) string {
+	xff := forwardedIPs(req)
+	if len(xff) < s.Depth {
+		return ""
+	}
+	return xff[len(xff)-s.Depth]
+}
+
+// CheckerStrategy returns the first IP of X-Forwarded-For, from the right, which is not excluded by the checker,
+// the excluded IPs being the ones of the proxies.
+type CheckerStrategy struct {
+	Checker *IP
+}
+
+// GetIP returns the first IP of X-Forwarded-For, from the right, which is not excluded,
+// or an empty string when all of them are excluded.
+func (s *CheckerStrategy) GetIP(req *http.Request) string {
+	xff := forwardedIPs(req)
+	for i := len(xff) - 1; i >= 0; i-- {
+		if excluded, _, err := s.Checker.Contains(xff[i]); err != nil || !excluded {
+			return xff[i]
+		}
+	}
+	return ""
+}
+
+// forwardedIPs returns the IPs of all the X-Forwarded-For headers of the request, from left to right.
+func forwardedIPs(req *http.Request) []string {
+	var ips []string
+	for _, value := range req.Header[xForwardedFor] {
+		for _, ip := range strings.Split(value, ",") {
+			if ip = strings.TrimSpace(ip); len(ip) > 0 {
+				ips = append(ips, ip)
+			}
+		}
+	}
+	return ips
+}
@@ -0,0 +1,108 @@
+package whitelist
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrategyGetIP(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		ipStrategy    *types.IPStrategy
+		remoteAddr    string
+		xForwardedFor []string
+		expected      string
+	}{
+		{
+			desc:          "remote address by default",
+			remoteAddr:    "10.0.0.1:40000",
+			xForwardedFor: []string{"1.2.3.4"},
+			expected:      "10.0.0.1",
+		},
+		{
+			desc:       "invalid remote address",
+			remoteAddr: "pipe",
+			expected:   "",
+		},
+		{
+			desc:          "depth of one",
+			ipStrategy:    &types.IPStrategy{Depth: 1},
+			remoteAddr:    "10.0.0.1:40000",
+			xForwardedFor: []string{"1.2.3.4, 5.6.7.8"},
+			expected:      "5.6.7.8",
+		},
+		{
+			desc:          "depth across several headers",
+			ipStrategy:    &types.IPStrategy{Depth: 3},
+			remoteAddr:    "10.0.0.1:40000",
+			xForwardedFor: []string{"1.2.3.4,5.6.7.8", "10.0.0.2"},
+			expected:      "1.2.3.4",
+		},
+		{
+			desc:          "depth greater than the forwarded IPs",
+			ipStrategy:    &types.IPStrategy{Depth: 3},
+			remoteAddr:    "10.0.0.1:40000",
+			xForwardedFor: []string{"1.2.3.4, 5.6.7.8"},
+			expected:      "",
+		},
+		{
+			desc:          "depth preferred to the excluded IPs",
+			ipStrategy:    &types.IPStrategy{Depth: 2, ExcludedIPs: []string{"1.2.3.4"}},
+			remoteAddr:    "10.0.0.1:40000",
+			xForwardedFor: []string{"1.2.3.4, 5.6.7.8"},
+			expected:      "1.2.3.4",
+		},
+		{
+			desc:          "excluded IPs",
+			ipStrategy:    &types.IPStrategy{ExcludedIPs: []string{"10.0.0.0/8", "5.6.7.8"}},
+			remoteAddr:    "10.0.0.1:40000",
+			xForwardedFor: []string{"1.2.3.4, 10.0.0.2, 5.6.7.8"},
+			expected:      "1.2.3.4",
+		},
+		{
+			desc:          "all IPs excluded",
+			ipStrategy:    &types.IPStrategy{ExcludedIPs: []string{"10.0.0.0/8"}},
+			remoteAddr:    "10.0.0.1:40000",
+			xForwardedFor: []string{"10.0.0.2"},
+			expected:      "",
+		},
+		{
+			desc:          "invalid IP not excluded",
+			ipStrategy:    &types.IPStrategy{ExcludedIPs: []string{"10.0.0.0/8"}},
+			remoteAddr:    "10.0.0.1:40000",
+			xForwardedFor: []string{"1.2.3.4, unknown"},
+			expected:      "unknown",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			strategy, err := NewStrategy(test.ipStrategy)
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost/", nil)
+			req.RemoteAddr = test.remoteAddr
+			for _, xff := range test.xForwardedFor {
+				req.Header.Add(xForwardedFor, xff)
+			}
+
+			assert.Equal(t, test.expected, strategy.GetIP(req))
+		})
+	}
+}
+
+func TestNewStrategyInvalid(t *testing.T) {
+	_, err := NewStrategy(&types.IPStrategy{Depth: -1})
+	assert.Error(t, err)
+
+	_, err = NewStrategy(&types.IPStrategy{ExcludedIPs: []string{"foo"}})
+	assert.Error(t, err)
+}